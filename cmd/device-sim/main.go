@@ -0,0 +1,435 @@
+// Command device-sim stands in for a room's controllers during development.
+// It plays one controller providing every device in devices.yaml: it
+// registers on start, publishes a heartbeat every interval with a full
+// registration every 12th beat, prints the commands it receives, answers
+// report_state, and publishes inputs typed on stdin. It reports itself online
+// on its status topic, with a last will that reports it offline.
+//
+// Usage:
+//
+//	device-sim [-devices devices.yaml] [-controller sim-001] [-heartbeat 5]
+//	           [-topic-prefix sentient] [-device-prefix devices]
+//
+// The broker is taken from MQTT_URL (default tcp://localhost:1883), with
+// credentials and TLS settings from MQTT_USERNAME, MQTT_PASSWORD and
+// MQTT_TLS_*. The prefixes must match the room's mqtt settings. Each line
+// on stdin is one of:
+//
+//	<device> field=value ...   publish an input; values are parsed per the
+//	                           device's payload types (true/false, numbers,
+//	                           anything else is a string)
+//	<device> {"json": ...}     publish a JSON payload as is
+//	state [<device>]           show simulated state
+//	list                       list devices and their signals
+//	quit                       exit
+//
+// A command whose payload is an object updates the simulated state of the
+// fields the device declares, as if the prop reported its new state.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/mqtt"
+)
+
+// simFirmware is reported as the simulated controller's firmware version.
+const simFirmware = "device-sim"
+
+// simulator holds the simulated devices' state.
+type simulator struct {
+	client       *mqtt.Client
+	topics       mqtt.Topics
+	controllerID string
+	heartbeatSec int
+	started      time.Time
+	devices      map[string]config.DeviceDefinition
+
+	mu    sync.Mutex
+	state map[string]map[string]interface{}
+}
+
+func main() {
+	fs := flag.NewFlagSet("device-sim", flag.ContinueOnError)
+	devicesPath := fs.String("devices", "devices.yaml", "devices.yaml to simulate")
+	controllerID := fs.String("controller", "sim-001", "controller id to register as")
+	heartbeat := fs.Int("heartbeat", 5, "seconds between heartbeats")
+	topicPrefix := fs.String("topic-prefix", mqtt.DefaultTopics().Prefix, "prefix of controller topics (room.yaml mqtt.topic_prefix)")
+	devicePrefix := fs.String("device-prefix", mqtt.DefaultTopics().DevicePrefix, "prefix of device topics (room.yaml mqtt.device_prefix)")
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+	if fs.NArg() != 0 || *heartbeat <= 0 || *controllerID == "" {
+		fmt.Fprintln(os.Stderr, "usage: device-sim [-devices devices.yaml] [-controller sim-001] [-heartbeat 5] [-topic-prefix sentient] [-device-prefix devices]")
+		os.Exit(2)
+	}
+
+	devCfg, err := config.LoadDevicesConfig(*devicesPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "device-sim: %s: %v\n", *devicesPath, err)
+		os.Exit(1)
+	}
+	if len(devCfg.Devices) == 0 {
+		fmt.Fprintf(os.Stderr, "device-sim: %s declares no devices\n", *devicesPath)
+		os.Exit(1)
+	}
+
+	client, err := mqtt.NewClient(*controllerID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "device-sim: %v\n", err)
+		os.Exit(1)
+	}
+
+	sim := &simulator{
+		client:       client,
+		topics:       mqtt.Topics{Prefix: *topicPrefix, DevicePrefix: *devicePrefix},
+		controllerID: *controllerID,
+		heartbeatSec: *heartbeat,
+		started:      time.Now(),
+		devices:      devCfg.Devices,
+		state:        make(map[string]map[string]interface{}),
+	}
+	for id, dev := range devCfg.Devices {
+		sim.state[id] = initialState(dev)
+	}
+
+	sim.client.SetWill(sim.statusTopic(), []byte(mqtt.StatusOffline))
+	if err := sim.client.Connect(); err != nil {
+		fmt.Fprintf(os.Stderr, "device-sim: cannot connect to %s: %v\n", mqtt.BrokerURL(), err)
+		os.Exit(1)
+	}
+	defer sim.client.Disconnect()
+
+	for _, id := range sim.deviceIDs() {
+		if err := sim.client.Subscribe(sim.commandTopic(id), sim.commandHandler(id)); err != nil {
+			fmt.Fprintf(os.Stderr, "device-sim: cannot subscribe for %s: %v\n", id, err)
+			os.Exit(1)
+		}
+	}
+	if err := sim.register(); err != nil {
+		fmt.Fprintf(os.Stderr, "device-sim: registration failed: %v\n", err)
+		os.Exit(1)
+	}
+	if err := sim.client.PublishRetained(sim.statusTopic(), []byte(mqtt.StatusOnline)); err != nil {
+		fmt.Fprintf(os.Stderr, "device-sim: status failed: %v\n", err)
+	}
+	fmt.Printf("registered %s with %d devices on %s; type \"list\" for devices, \"quit\" to exit\n",
+		sim.controllerID, len(sim.devices), mqtt.BrokerURL())
+
+	stop := make(chan struct{})
+	go sim.heartbeat(stop)
+	go func() {
+		sim.readInputs(os.Stdin)
+		close(stop)
+	}()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case <-sigCh:
+	case <-stop:
+	}
+	// A clean disconnect does not send the will
+	_ = sim.client.PublishRetained(sim.statusTopic(), []byte(mqtt.StatusOffline))
+}
+
+// deviceIDs returns the simulated devices in name order.
+func (s *simulator) deviceIDs() []string {
+	ids := make([]string, 0, len(s.devices))
+	for id := range s.devices {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (s *simulator) eventTopic(id string) string {
+	return s.topics.DeviceEvents(s.controllerID, id)
+}
+
+func (s *simulator) statusTopic() string {
+	return s.topics.Status(s.controllerID)
+}
+
+func (s *simulator) commandTopic(id string) string {
+	return s.topics.DeviceCommands(s.controllerID, id)
+}
+
+// register publishes the controller's registration.
+func (s *simulator) register() error {
+	reg := mqtt.RegistrationPayload{
+		Version: 1,
+		Controller: mqtt.ControllerInfo{
+			ID:           s.controllerID,
+			Type:         "simulator",
+			Firmware:     simFirmware,
+			UptimeMS:     time.Since(s.started).Milliseconds(),
+			HeartbeatSec: s.heartbeatSec,
+		},
+	}
+	for _, id := range s.deviceIDs() {
+		dev := s.devices[id]
+		reg.Devices = append(reg.Devices, mqtt.DeviceRegistration{
+			LogicalID:    id,
+			Type:         dev.Type,
+			Capabilities: dev.Capabilities,
+			Signals: mqtt.DeviceSignals{
+				Inputs:  dev.Signals.Inputs,
+				Outputs: dev.Signals.Outputs,
+			},
+			Topics: mqtt.DeviceTopics{
+				Publish:   s.eventTopic(id),
+				Subscribe: s.commandTopic(id),
+			},
+		})
+	}
+	b, err := json.Marshal(reg)
+	if err != nil {
+		return err
+	}
+	return s.client.Publish(s.topics.Registration(s.controllerID), b)
+}
+
+// registerEvery is how many heartbeats pass between full registrations.
+const registerEvery = 12
+
+// beat publishes a lightweight heartbeat.
+func (s *simulator) beat() error {
+	b, err := json.Marshal(mqtt.HeartbeatPayload{
+		UptimeMS:     time.Since(s.started).Milliseconds(),
+		HeartbeatSec: s.heartbeatSec,
+	})
+	if err != nil {
+		return err
+	}
+	return s.client.Publish(s.topics.Heartbeat(s.controllerID), b)
+}
+
+// heartbeat publishes heartbeats, with a full registration every
+// registerEvery beats, until stop is closed.
+func (s *simulator) heartbeat(stop <-chan struct{}) {
+	ticker := time.NewTicker(time.Duration(s.heartbeatSec) * time.Second)
+	defer ticker.Stop()
+	for beat := 1; ; beat++ {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			var err error
+			if beat%registerEvery == 0 {
+				err = s.register()
+			} else {
+				err = s.beat()
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "device-sim: heartbeat failed: %v\n", err)
+			}
+		}
+	}
+}
+
+// commandHandler prints a device's commands and answers report_state.
+func (s *simulator) commandHandler(id string) paho.MessageHandler {
+	return func(_ paho.Client, msg paho.Message) {
+		var cmd struct {
+			Signal  string      `json:"signal"`
+			Payload interface{} `json:"payload"`
+		}
+		if err := json.Unmarshal(msg.Payload(), &cmd); err != nil {
+			fmt.Printf("<- %s: invalid command %q\n", id, msg.Payload())
+			return
+		}
+		if cmd.Payload != nil {
+			p, _ := json.Marshal(cmd.Payload)
+			fmt.Printf("<- %s %s %s\n", id, cmd.Signal, p)
+		} else {
+			fmt.Printf("<- %s %s\n", id, cmd.Signal)
+		}
+
+		// Publishing waits for the broker, which must not happen on the
+		// client's message handler
+		if cmd.Signal == mqtt.ReportStateSignal {
+			go s.publishState(id)
+			return
+		}
+		if fields, ok := cmd.Payload.(map[string]interface{}); ok {
+			update := make(map[string]interface{})
+			for field, v := range fields {
+				if _, declared := s.devices[id].Payload[field]; declared {
+					update[field] = v
+				}
+			}
+			if len(update) > 0 {
+				go s.publish(id, update)
+			}
+		}
+	}
+}
+
+// publishState publishes a device's whole simulated state.
+func (s *simulator) publishState(id string) {
+	s.mu.Lock()
+	state := make(map[string]interface{}, len(s.state[id]))
+	for field, v := range s.state[id] {
+		state[field] = v
+	}
+	s.mu.Unlock()
+	s.publish(id, state)
+}
+
+// publish records payload fields in a device's state and publishes them as
+// an input.
+func (s *simulator) publish(id string, payload map[string]interface{}) {
+	s.mu.Lock()
+	for field, v := range payload {
+		s.state[id][field] = v
+	}
+	s.mu.Unlock()
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "device-sim: %s: %v\n", id, err)
+		return
+	}
+	if err := s.client.Publish(s.eventTopic(id), b); err != nil {
+		fmt.Fprintf(os.Stderr, "device-sim: publish for %s failed: %v\n", id, err)
+		return
+	}
+	fmt.Printf("-> %s %s\n", id, b)
+}
+
+// readInputs runs the interactive prompt until quit or end of input.
+func (s *simulator) readInputs(r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		name, rest, _ := strings.Cut(line, " ")
+		rest = strings.TrimSpace(rest)
+
+		switch name {
+		case "quit", "exit":
+			return
+		case "list":
+			for _, id := range s.deviceIDs() {
+				dev := s.devices[id]
+				fmt.Printf("  %-20s %-10s inputs=%s outputs=%s\n", id, dev.Type,
+					strings.Join(dev.Signals.Inputs, ","), strings.Join(dev.Signals.Outputs, ","))
+			}
+			continue
+		case "state":
+			ids := s.deviceIDs()
+			if rest != "" {
+				ids = []string{rest}
+			}
+			for _, id := range ids {
+				if _, ok := s.devices[id]; !ok {
+					fmt.Printf("unknown device %q\n", id)
+					continue
+				}
+				s.mu.Lock()
+				b, _ := json.Marshal(s.state[id])
+				s.mu.Unlock()
+				fmt.Printf("  %-20s %s\n", id, b)
+			}
+			continue
+		}
+
+		dev, ok := s.devices[name]
+		if !ok {
+			fmt.Printf("unknown device %q; type \"list\" for devices\n", name)
+			continue
+		}
+		payload, err := parseInput(dev, rest)
+		if err != nil {
+			fmt.Printf("%s: %v\n", name, err)
+			continue
+		}
+		s.publish(name, payload)
+	}
+}
+
+// parseInput reads a typed input: a JSON object or field=value pairs.
+func parseInput(dev config.DeviceDefinition, text string) (map[string]interface{}, error) {
+	if strings.HasPrefix(text, "{") {
+		var payload map[string]interface{}
+		if err := json.Unmarshal([]byte(text), &payload); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return payload, nil
+	}
+
+	pairs := strings.Fields(text)
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("expected field=value or a JSON object")
+	}
+	payload := make(map[string]interface{}, len(pairs))
+	for _, pair := range pairs {
+		field, raw, ok := strings.Cut(pair, "=")
+		if !ok || field == "" {
+			return nil, fmt.Errorf("expected field=value, got %q", pair)
+		}
+		v, err := parseValue(dev.Payload[field].Type, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field, err)
+		}
+		payload[field] = v
+	}
+	return payload, nil
+}
+
+// parseValue parses a typed value as the declared payload type, or guesses
+// the type of an undeclared field.
+func parseValue(typ, raw string) (interface{}, error) {
+	switch typ {
+	case config.PayloadBool:
+		return strconv.ParseBool(raw)
+	case config.PayloadInt:
+		return strconv.ParseInt(raw, 10, 64)
+	case config.PayloadNumber:
+		return strconv.ParseFloat(raw, 64)
+	case config.PayloadString:
+		return raw, nil
+	}
+	if b, err := strconv.ParseBool(raw); err == nil {
+		return b, nil
+	}
+	if f, err := strconv.ParseFloat(raw, 64); err == nil {
+		return f, nil
+	}
+	return raw, nil
+}
+
+// initialState is a device's state before any input: the zero value of each
+// declared payload field.
+func initialState(dev config.DeviceDefinition) map[string]interface{} {
+	state := make(map[string]interface{}, len(dev.Payload))
+	for field, f := range dev.Payload {
+		switch f.Type {
+		case config.PayloadBool:
+			state[field] = false
+		case config.PayloadString:
+			state[field] = ""
+		default:
+			state[field] = 0
+		}
+	}
+	return state
+}
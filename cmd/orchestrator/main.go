@@ -5,6 +5,8 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -13,14 +15,29 @@ import (
 	"github.com/AaronLay10/SentientEngine/internal/api"
 	"github.com/AaronLay10/SentientEngine/internal/config"
 	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/export"
 	"github.com/AaronLay10/SentientEngine/internal/mqtt"
 	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+	"github.com/AaronLay10/SentientEngine/internal/storage/bolt"
 	"github.com/AaronLay10/SentientEngine/internal/storage/postgres"
+	"github.com/AaronLay10/SentientEngine/internal/tts"
 	"github.com/AaronLay10/SentientEngine/internal/version"
 )
 
 const shutdownTimeout = 10 * time.Second
 
+// storeRetryInterval is how often storage that could not be opened at
+// startup is tried again.
+const storeRetryInterval = 10 * time.Second
+
+// After a restore, devices get reconcileSettle to re-register before they are
+// asked for their state, and reconcileTimeout to answer.
+const (
+	reconcileSettle  = 10 * time.Second
+	reconcileTimeout = 5 * time.Second
+)
+
 func emit(level, event, msg string, fields map[string]interface{}) {
 	b, err := events.Emit(level, event, msg, fields)
 	if err != nil {
@@ -45,6 +62,87 @@ func sceneGraphPath() string {
 	return "/config/graphs/scene-graph.v1.json"
 }
 
+// stagedSceneGraphPath returns the staged scene graph path from SENTIENT_STAGED_SCENE_GRAPH_PATH or default.
+func stagedSceneGraphPath() string {
+	if path := os.Getenv("SENTIENT_STAGED_SCENE_GRAPH_PATH"); path != "" {
+		return path
+	}
+	return "/config/graphs/scene-graph.staged.json"
+}
+
+// spillDir returns the directory events are spilled to while the store is
+// down, from SENTIENT_SPILL_DIR or default.
+func spillDir() string {
+	if dir := os.Getenv("SENTIENT_SPILL_DIR"); dir != "" {
+		return dir
+	}
+	return "/data/spill"
+}
+
+// storageBackend returns the storage backend from SENTIENT_STORAGE or default.
+func storageBackend() string {
+	if backend := os.Getenv("SENTIENT_STORAGE"); backend != "" {
+		return strings.ToLower(backend)
+	}
+	return "postgres"
+}
+
+// boltPath returns the embedded storage file from SENTIENT_BOLT_PATH or default.
+func boltPath() string {
+	if path := os.Getenv("SENTIENT_BOLT_PATH"); path != "" {
+		return path
+	}
+	return "/data/sentient.db"
+}
+
+// openStore opens the given storage backend for a room.
+func openStore(backend, roomID string) (storage.Store, error) {
+	switch backend {
+	case "postgres":
+		client, err := postgres.New(roomID)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	case "bolt":
+		store, err := bolt.Open(boltPath(), roomID)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	}
+	return nil, fmt.Errorf("unknown SENTIENT_STORAGE %q (want postgres or bolt)", backend)
+}
+
+// connectStore opens the storage backend every storeRetryInterval until it
+// answers. It returns nil if stop is closed first.
+func connectStore(backend, roomID string, stop <-chan struct{}) storage.Store {
+	ticker := time.NewTicker(storeRetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+		if store, err := openStore(backend, roomID); err == nil {
+			return store
+		}
+	}
+}
+
+// persistEvents persists events to store from now on.
+func persistEvents(store storage.Store) {
+	// While the store is down mid-session events spill to disk and are
+	// replayed on reconnect, so an outage alerts but keeps /ready up
+	events.SetSpillDir(spillDir())
+	events.SetStoreStateCallback(func(connected bool) {
+		api.SetPostgresState(connected, !connected)
+	})
+	events.SetStore(store)
+	api.SetPostgresState(true, false)
+}
+
 func main() {
 	cfgDir := configDir()
 
@@ -56,6 +154,27 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Memory caps, for rooms running on small single-board computers
+	events.SetBufferLimits(roomCfg.EventBufferSize(), roomCfg.EventBufferBytes())
+	events.SetSubscriberQueueSize(roomCfg.WSQueueSize())
+
+	// Route engine problems to syslog/journald for venue log aggregation
+	var syslogSink *events.SyslogSink
+	if roomCfg.Syslog.Enabled {
+		sc := roomCfg.Syslog
+		if sc.Tag == "" {
+			sc.Tag = "sentient-" + roomCfg.Room.ID
+		}
+		syslogSink, err = events.NewSyslogSink(sc.Level, sc.Network, sc.Address, sc.Facility, sc.Tag)
+		if err != nil {
+			emit("error", "system.error", "failed to configure syslog", map[string]interface{}{
+				"error": err.Error(),
+			})
+			os.Exit(1)
+		}
+		syslogSink.Start()
+	}
+
 	devCfg, err := config.LoadDevicesConfig(cfgDir + "/devices.yaml")
 	if err != nil {
 		emit("error", "system.error", "failed to load devices.yaml", map[string]interface{}{
@@ -64,45 +183,169 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Convert device config to specs for MQTT validation
-	deviceSpecs := make(map[string]mqtt.DeviceSpec)
-	for id, dev := range devCfg.Devices {
-		deviceSpecs[id] = mqtt.DeviceSpecFromConfig(dev.Type, dev.Required, dev.Capabilities)
+	maintCfg, err := config.LoadMaintenanceConfig(cfgDir + "/maintenance.yaml")
+	if err != nil {
+		emit("error", "system.error", "failed to load maintenance.yaml", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
+
+	bridgeCfg, err := config.LoadBridgesConfig(cfgDir + "/bridges.yaml")
+	if err == nil {
+		err = orchestrator.ValidateBridgeConditions(bridgeCfg)
+	}
+	if err != nil {
+		emit("error", "system.error", "failed to load bridges.yaml", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
 	}
 
+	announceCfg, err := config.LoadAnnouncementsConfig(cfgDir + "/announcements.yaml")
+	if err == nil {
+		err = orchestrator.ValidateAnnouncementConditions(announceCfg)
+	}
+	if err != nil {
+		emit("error", "system.error", "failed to load announcements.yaml", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
+
+	fedCfg, err := config.LoadFederationConfig(cfgDir + "/federation.yaml")
+	if err == nil {
+		err = orchestrator.ValidateFederationConditions(fedCfg)
+	}
+	if err != nil {
+		emit("error", "system.error", "failed to load federation.yaml", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
+
+	tagsCfg, err := config.LoadTagsConfig(cfgDir + "/tags.yaml")
+	var tags *mqtt.TagRegistry
+	if err == nil {
+		tags, err = mqtt.NewTagRegistry(tagsCfg.UIDField, tagsCfg.Readers, tagsCfg.Tags)
+	}
+	if err != nil {
+		emit("error", "system.error", "failed to load tags.yaml", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
+
+	// Daily opening and closing routines (ADR-027)
+	routineLoc, err := roomCfg.Location()
+	var routines []orchestrator.Routine
+	if err == nil {
+		routines, err = orchestrator.ParseRoutines(roomCfg.Routines, devCfg)
+	}
+	if err != nil {
+		emit("error", "system.error", "invalid routines in room.yaml", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
+
+	// Convert device config to specs for MQTT validation
+	deviceSpecs := orchestrator.DeviceSpecsFromConfig(devCfg)
+
 	// Load scene graph
 	sg, err := orchestrator.LoadSceneGraph(sceneGraphPath())
+	payloadTypes := orchestrator.PayloadTypesFromConfig(devCfg)
+	if err == nil {
+		err = orchestrator.ValidatePayloadTypes(sg, payloadTypes)
+	}
 	if err != nil {
 		emit("error", "system.error", "failed to load scene graph", map[string]interface{}{
 			"error": err.Error(),
 		})
 		os.Exit(1)
 	}
+	orchestrator.SetPayloadTypes(payloadTypes)
+
+	// Devices the graph names but devices.yaml does not declare would only
+	// fail mid-game; refuse to start with ops.strict_device_refs, else warn
+	unknownRefs := orchestrator.UnknownDeviceRefs(sg, orchestrator.DeclaredDevices(devCfg))
+	if len(unknownRefs) > 0 && roomCfg.Ops.StrictDeviceRefs {
+		emit("error", "system.error", "scene graph references unknown devices", map[string]interface{}{
+			"error": orchestrator.ValidateDeviceRefs(sg, orchestrator.DeclaredDevices(devCfg)).Error(),
+		})
+		os.Exit(1)
+	}
+	for _, ref := range unknownRefs {
+		events.Warn("unknown_device_ref", ref.Problem(), map[string]interface{}{
+			"logical_id": ref.LogicalID,
+			"where":      ref.Where,
+		})
+	}
 
-	// Initialize Postgres for event persistence (before runtime, for restore)
-	var pgConnected bool
-	var pgClient *postgres.Client
-	pgClient, err = postgres.New(roomCfg.Room.ID)
+	// Initialize storage for event persistence (before runtime, for restore).
+	// SENTIENT_STORAGE picks Postgres or an embedded bolt file; its health is
+	// reported as postgres in /ready and /metrics either way
+	backend := storageBackend()
+	if backend != "postgres" && backend != "bolt" {
+		emit("error", "system.error", "invalid storage backend", map[string]interface{}{
+			"error": fmt.Sprintf("unknown SENTIENT_STORAGE %q (want postgres or bolt)", backend),
+		})
+		os.Exit(1)
+	}
+	var storeConnected bool
+	var store storage.Store
+	store, err = openStore(backend, roomCfg.Room.ID)
 	if err != nil {
-		emit("error", "system.error", "postgres connection failed", map[string]interface{}{
+		emit("error", "system.error", backend+" connection failed", map[string]interface{}{
 			"error": err.Error(),
 		})
-		// Continue without postgres per requirement (mark as optional)
+		// Continue without storage per requirement (mark as optional); it is
+		// retried in the background below
 		api.SetPostgresState(false, true)
 	} else {
-		pgConnected = true
-		events.SetPostgresClient(pgClient)
-		api.SetPostgresState(true, false)
-		// Note: pgClient.Close() is called explicitly during graceful shutdown
+		storeConnected = true
+		persistEvents(store)
+		// Note: store.Close() is called explicitly during graceful shutdown
 	}
 
 	// Create runtime
 	rt := orchestrator.NewRuntime(sg)
+	rt.SetGameDuration(roomCfg.GameDuration(), roomCfg.MaxGameDuration())
+	rt.SetCascadeReset(roomCfg.Ops.CascadeReset)
+	rt.SetResetCooldown(roomCfg.ResetCooldown())
+	rt.SetStallTimeout(roomCfg.StallTimeout())
+	rt.SetPauseIgnoresInput(roomCfg.PauseIgnoresInput())
+	rt.SetVideoSync(roomCfg.VideoSyncInterval(), roomCfg.Video.Cameras)
+
+	// Run mode profile: show, rehearsal or maintenance
+	mode, err := orchestrator.ParseRunMode(roomCfg.RunMode())
+	if err != nil {
+		emit("error", "system.error", "invalid ops.mode in room.yaml", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
+	_ = rt.SetMode(mode, "room.yaml")
+
+	// Physical state of props is checked against a restored game
+	reconcile, err := orchestrator.ParseReconcileMode(roomCfg.ReconcileMode())
+	if err != nil {
+		emit("error", "system.error", "invalid ops.reconcile in room.yaml", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
 
-	// Restore state from Postgres if connected (active session only)
+	// Checkpoint snapshots are persisted alongside events
+	if storeConnected {
+		rt.SetCheckpointStore(store)
+	}
+
+	// Restore state from storage if connected (active session only)
 	// If no active session found, runtime stays idle until /game/start
-	if pgConnected {
-		state, count, err := orchestrator.RestoreFromEvents(pgClient, roomCfg.Room.ID, orchestrator.DefaultRestoreLimit)
+	restored := false
+	if storeConnected {
+		state, count, err := orchestrator.RestoreFromEvents(store, roomCfg.Room.ID, orchestrator.DefaultRestoreLimit)
 		if err != nil {
 			emit("error", "system.error", "failed to restore from events", map[string]interface{}{
 				"error": err.Error(),
@@ -111,14 +354,20 @@ func main() {
 			// Active session found - restore it (no new scene.started emitted)
 			if err := rt.ApplyRestoredState(state); err == nil {
 				orchestrator.EmitStartupRestore(count, roomCfg.Room.ID)
+				restored = true
 			}
 		}
 		// If state == nil, no active session - remain idle until /game/start
 	}
-	// If postgres not connected, remain idle until /game/start
+	// If storage not connected, remain idle until /game/start
 
 	// Register runtime with API for operator control
 	api.SetRuntimeController(rt)
+	api.SetConfirmDestructive(roomCfg.RequireConfirm())
+
+	// Expose the loaded graph and staged revision for /admin/graph/diff
+	api.SetSceneGraph(sg)
+	api.SetStagedGraphPath(stagedSceneGraphPath())
 
 	// Set room name for metrics and alerts
 	api.SetRoomName(roomCfg.Room.Name)
@@ -129,18 +378,72 @@ func main() {
 	// Start alert monitor (checks MQTT/Postgres state periodically)
 	api.StartAlertMonitor(10 * time.Second)
 
+	// Alert gamemasters when a team stops making progress or a prop loses power
+	api.WatchStalls()
+	api.WatchPower()
+
 	// Start MQTT controller registration monitor
 	monitor := mqtt.NewMonitor(deviceSpecs, 2.0) // 2x heartbeat tolerance
-	monitor.Start(5 * time.Second)               // Check health every 5s
+	if err := monitor.SetMinFirmware(roomCfg.MQTT.MinFirmware); err != nil {
+		emit("error", "system.error", "invalid mqtt settings in room.yaml", map[string]interface{}{
+			"error": err.Error(),
+		})
+		os.Exit(1)
+	}
+	monitor.Start(5 * time.Second) // Check health every 5s
+	monitor.DeviceRegistry().SetMaxDevices(roomCfg.MaxDevices())
+	api.SetDeviceRegistry(monitor.DeviceRegistry())
+
+	// Asset metadata is persisted alongside events
+	if storeConnected {
+		if err := monitor.DeviceRegistry().SetAssetStore(store); err != nil {
+			emit("error", "system.error", "failed to load device assets", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
+	api.SetDeviceInventory(monitor)
+	api.SetControllerInventory(monitor)
+
+	// Trigger tokens for external systems survive restarts
+	if storeConnected {
+		if err := api.SetTriggerStore(store); err != nil {
+			emit("error", "system.error", "failed to load trigger tokens", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
 
-	mqttClient := mqtt.NewClient(roomCfg.Room.ID + "-orchestrator")
+	// Operator console preferences survive restarts
+	if storeConnected {
+		if err := api.SetPrefStore(store); err != nil {
+			emit("error", "system.error", "failed to load operator preferences", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
+
+	mqttClient, err := mqtt.NewClient(roomCfg.Room.ID + "-orchestrator")
+	if err != nil {
+		emit("error", "system.error", "invalid mqtt broker settings", map[string]interface{}{
+			"broker": mqtt.BrokerURL(),
+			"error":  err.Error(),
+		})
+		os.Exit(1)
+	}
+	mqtt.SetTopics(mqtt.Topics{Prefix: roomCfg.MQTTTopicPrefix(), DevicePrefix: roomCfg.MQTTDevicePrefix()})
+	mqttClient.SetDelivery(mqtt.Delivery{
+		PublishQoS:     roomCfg.MQTTPublishQoS(),
+		SubscribeQoS:   roomCfg.MQTTSubscribeQoS(),
+		RetainCommands: roomCfg.MQTT.RetainCommands,
+	})
 
 	// Register callback to update API state on connection changes
 	mqttClient.SetConnectionCallback(func(connected bool) {
 		api.SetMQTTState(connected, false)
 	})
 
-	mqttConnected := mqttClient.StartWithRetry("sentient/registration/#", func(client paho.Client, msg paho.Message) {
+	mqttConnected := mqttClient.StartWithRetry(mqtt.RegistrationTopic(), func(client paho.Client, msg paho.Message) {
 		payload, err := mqtt.ParseRegistration(msg.Payload())
 		if err != nil {
 			events.Emit("error", "device.error", "invalid registration payload", map[string]interface{}{
@@ -161,19 +464,237 @@ func main() {
 		api.SetMQTTState(true, false)
 	}
 
+	// Lightweight heartbeats between full registrations
+	if mqttConnected {
+		if err := mqttClient.Subscribe(mqtt.HeartbeatTopic(), monitor.HeartbeatHandler()); err != nil {
+			emit("error", "system.error", "failed to subscribe to controller heartbeats", map[string]interface{}{
+				"topic": mqtt.HeartbeatTopic(),
+				"error": err.Error(),
+			})
+		}
+	}
+
+	// Last wills mark controllers offline without waiting for the heartbeat timeout
+	if mqttConnected {
+		if err := mqttClient.Subscribe(mqtt.StatusTopic(), monitor.StatusHandler()); err != nil {
+			emit("error", "system.error", "failed to subscribe to controller status", map[string]interface{}{
+				"topic": mqtt.StatusTopic(),
+				"error": err.Error(),
+			})
+		}
+	}
+
+	// Learn per-device baselines and warn on silent, chattering, or out-of-range devices
+	anomalies := mqtt.NewAnomalyDetector(rt.IsGameActive)
+	if storeConnected {
+		rows, err := store.QueryByNames(mqtt.AnomalyEventNames, mqtt.DefaultAnomalySeedLimit)
+		if err != nil {
+			emit("error", "system.error", "failed to seed device baselines", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else {
+			anomalies.SeedFromEvents(rows)
+		}
+	}
+	anomalies.Start()
+
+	// Controller debug output, kept apart from game events
+	controllerLogs := mqtt.NewControllerLogs(roomCfg.ControllerLogRate(), roomCfg.ControllerLogRetention())
+	if storeConnected {
+		controllerLogs.SetStore(store)
+	}
+	if mqttConnected {
+		if err := mqttClient.Subscribe(mqtt.ControllerLogTopic(), controllerLogs.MessageHandler()); err != nil {
+			emit("error", "system.error", "failed to subscribe to controller logs", map[string]interface{}{
+				"topic": mqtt.ControllerLogTopic(),
+				"error": err.Error(),
+			})
+		}
+	}
+	controllerLogs.Start()
+	api.SetControllerLogs(controllerLogs)
+
+	// Smart plug power telemetry; props outside maintenance that draw nothing are reported
+	power := mqtt.NewPowerMonitor(orchestrator.PowerSpecsFromConfig(devCfg), func() bool {
+		return !rt.InMaintenance()
+	})
+	if mqttConnected {
+		if err := power.Subscribe(mqttClient); err != nil {
+			emit("error", "system.error", "failed to subscribe to power telemetry", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+	}
+	power.Start()
+	monitor.SetPowerMonitor(power)
+	api.SetPowerMonitor(power)
+
+	// Scheduled session reports for room owners
+	var exporter *export.Exporter
+	exportCfg, err := export.LoadConfig(roomCfg.Ops.Timezone)
+	if err != nil {
+		emit("error", "system.error", "invalid session export config", map[string]interface{}{
+			"error": err.Error(),
+		})
+		exportCfg = nil
+	} else if exportCfg.Enabled() && storeConnected {
+		exporter = export.New(exportCfg, store, roomCfg.Room.ID)
+		exporter.Start()
+	}
+
+	// Storage that could not be opened at startup is attached once it
+	// answers. Events emitted until then are not stored, and the session is
+	// not restored from it: the room has been running without it.
+	// lateStoreMu guards store and exporter while this can happen
+	var lateStoreMu sync.Mutex
+	stopStoreRetry := make(chan struct{})
+	if !storeConnected {
+		go func() {
+			s := connectStore(backend, roomCfg.Room.ID, stopStoreRetry)
+			if s == nil {
+				return
+			}
+			lateStoreMu.Lock()
+			defer lateStoreMu.Unlock()
+			select {
+			case <-stopStoreRetry:
+				s.Close()
+				return
+			default:
+			}
+
+			store = s
+			persistEvents(s)
+			rt.SetCheckpointStore(s)
+			if err := monitor.DeviceRegistry().SetAssetStore(s); err != nil {
+				emit("error", "system.error", "failed to load device assets", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+			if err := api.SetTriggerStore(s); err != nil {
+				emit("error", "system.error", "failed to load trigger tokens", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+			if err := api.SetPrefStore(s); err != nil {
+				emit("error", "system.error", "failed to load operator preferences", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+			controllerLogs.SetStore(s)
+			if exportCfg != nil && exportCfg.Enabled() {
+				exporter = export.New(exportCfg, s, roomCfg.Room.ID)
+				exporter.Start()
+			}
+			log.Printf("Connected to %s storage", backend)
+		}()
+	}
+
+	// Set up action executor for device commands
+	actionExecutor := orchestrator.NewActionExecutor(mqttClient, monitor.DeviceRegistry(), devCfg)
+	rt.SetActionExecutor(actionExecutor)
+	api.SetMessageRelay(actionExecutor)
+
+	// devices.yaml can be reloaded without a restart (POST /admin/devices/reload or SIGHUP)
+	devicesReloader := orchestrator.NewDevicesReloader(cfgDir+"/devices.yaml", devCfg, sg, monitor, actionExecutor)
+	devicesReloader.SetStrictDeviceRefs(roomCfg.Ops.StrictDeviceRefs)
+	api.SetDevicesReloader(devicesReloader)
+
+	// Text-to-speech for tts.speak actions
+	var speaker *tts.Client
+	if roomCfg.TTS.URL != "" {
+		speaker, err = tts.New(roomCfg.TTS)
+		if err != nil {
+			emit("error", "system.error", "failed to configure tts", map[string]interface{}{
+				"error": err.Error(),
+			})
+			os.Exit(1)
+		}
+		speaker.Start()
+		actionExecutor.SetSpeaker(speaker)
+	}
+
+	// Event-to-MQTT bridge rules for simple reactive wiring
+	var bridge *orchestrator.Bridge
+	if len(bridgeCfg.Rules) > 0 {
+		bridge = orchestrator.NewBridge(bridgeCfg, mqttClient, rt.GatedExecutor(actionExecutor))
+		bridge.Start()
+	}
+
+	// Engine events republished for show-control systems
+	var republisher *orchestrator.Republisher
+	if bridgeCfg.Republish != nil {
+		republisher = orchestrator.NewRepublisher(bridgeCfg.Republish, mqtt.CurrentTopics().Events(), mqttClient)
+		republisher.Start()
+	}
+
+	// Timed and event-driven announcements on the room's audio/TTS channel
+	var announcer *orchestrator.Announcer
+	if len(announceCfg.Rules) > 0 {
+		channel := orchestrator.NewAnnouncementChannel(announceCfg.Channel, mqttClient, rt.GatedExecutor(actionExecutor))
+		announcer = orchestrator.NewAnnouncer(announceCfg, channel, rt)
+		announcer.Start()
+	}
+
+	// Cross-room event links with sibling rooms (ADR-012)
+	var federation *orchestrator.Federation
+	if fedCfg.Enabled() {
+		federation, err = orchestrator.NewFederation(roomCfg.Room.ID, fedCfg, rt)
+		if err != nil {
+			emit("error", "system.error", "failed to configure federation", map[string]interface{}{
+				"error": err.Error(),
+			})
+			os.Exit(1)
+		}
+		federation.Start()
+		api.SetFederationReceiver(federation)
+	}
+
+	// Synchronized head-to-head starts with linked rooms (ADR-013)
+	var competition *orchestrator.Competition
+	if federation != nil {
+		competition = orchestrator.NewCompetition(federation, rt)
+		federation.SetControlHandler(competition.HandleControl)
+		competition.Start()
+		api.SetCompetitionController(competition)
+	}
+
+	// Maintenance mode runs device test routines through the same executor
+	maintenance := orchestrator.NewMaintenance(rt, maintCfg, actionExecutor)
+	api.SetMaintenanceController(maintenance)
+	dailyRoutines := orchestrator.NewRoutines(routines, routineLoc, rt, actionExecutor, maintenance)
+	dailyRoutines.Start()
+
 	// Set up device input subscriber for event topic subscriptions
 	if mqttConnected {
 		deviceSubscriber := mqtt.NewDeviceSubscriber(mqttClient, monitor.DeviceRegistry())
-		// Route device.input events to puzzle runtime
+		// Name RFID/NFC tags in reader payloads
+		if tags.Len() > 0 {
+			deviceSubscriber.SetTagRegistry(tags)
+		}
+		// Latched payload fields are device state readable as state.<logical_id>.<field>
+		deviceSubscriber.SetLatchedFields(devCfg.LatchedFields())
+		devicesReloader.SetLatchedFieldSetter(deviceSubscriber)
+		orchestrator.SetDeviceStates(deviceSubscriber)
+		// Route device.input and device.state_changed events to puzzle runtime
 		deviceSubscriber.SetInputHandler(func(eventName string, fields map[string]interface{}) {
+			if eventName == "device.input" {
+				logicalID, _ := fields["logical_id"].(string)
+				anomalies.Observe(logicalID, fields["payload"], time.Now())
+				maintenance.HandleInput(fields)
+			}
 			rt.InjectEvent(eventName, fields)
 		})
 		monitor.SetSubscriber(deviceSubscriber)
-	}
 
-	// Set up action executor for device commands
-	actionExecutor := orchestrator.NewActionExecutor(mqttClient, monitor.DeviceRegistry(), devCfg)
-	rt.SetActionExecutor(actionExecutor)
+		// Ask props whether they are where the restored game left them
+		if restored && reconcile != orchestrator.ReconcileOff {
+			go func() {
+				time.Sleep(reconcileSettle)
+				rt.Reconcile(deviceSubscriber, reconcileTimeout, reconcile == orchestrator.ReconcileCorrect)
+			}()
+		}
+	}
 
 	hostname, _ := os.Hostname()
 	emit("info", "system.startup", "orchestrator starting", map[string]interface{}{
@@ -186,12 +707,32 @@ func main() {
 		"scenes":             len(sg.Scenes),
 		"ui_port":            roomCfg.UIPort(),
 		"mqtt_connected":     mqttConnected,
-		"postgres_connected": pgConnected,
+		"storage":            backend,
+		"postgres_connected": storeConnected,
 	})
 
 	// Mark orchestrator as ready for /ready endpoint
 	api.SetOrchestratorReady(true)
 
+	// SIGHUP reloads devices.yaml
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	go func() {
+		for range hupCh {
+			reload, err := devicesReloader.Reload()
+			if err != nil {
+				emit("error", "system.error", "failed to reload devices.yaml", map[string]interface{}{
+					"error": err.Error(),
+				})
+				continue
+			}
+			log.Printf("Reloaded devices.yaml: %d devices, added %v, removed %v", reload.Devices, reload.Added, reload.Removed)
+			for _, ref := range reload.UnknownDevices {
+				log.Printf("Reloaded devices.yaml: %s", ref.Problem())
+			}
+		}
+	}()
+
 	// Wait for shutdown signal
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
@@ -209,8 +750,38 @@ func main() {
 		"hostname": hostname,
 	})
 
+	// Stop waiting for storage that never answered
+	lateStoreMu.Lock()
+	close(stopStoreRetry)
+	lateStoreMu.Unlock()
+
 	// Stop monitor first (stops health checks)
 	monitor.Stop()
+	anomalies.Stop()
+	controllerLogs.Stop()
+	power.Stop()
+	dailyRoutines.Stop()
+	if exporter != nil {
+		exporter.Stop()
+	}
+	if bridge != nil {
+		bridge.Stop()
+	}
+	if republisher != nil {
+		republisher.Stop()
+	}
+	if announcer != nil {
+		announcer.Stop()
+	}
+	if speaker != nil {
+		speaker.Stop()
+	}
+	if competition != nil {
+		competition.Stop()
+	}
+	if federation != nil {
+		federation.Stop()
+	}
 
 	// Shutdown API server gracefully (closes WebSockets, waits for requests)
 	if err := api.Shutdown(apiServer, shutdownTimeout); err != nil {
@@ -222,9 +793,15 @@ func main() {
 		mqttClient.Disconnect()
 	}
 
-	// Close Postgres connection
-	if pgClient != nil {
-		pgClient.Close()
+	// Write the events still queued, then close the store
+	if store != nil {
+		events.CloseStore()
+		store.Close()
+	}
+
+	// Flush events still queued for syslog
+	if syslogSink != nil {
+		syslogSink.Stop()
 	}
 
 	log.Printf("Graceful shutdown complete")
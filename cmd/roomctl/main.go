@@ -0,0 +1,153 @@
+// Command roomctl provides offline tooling for room content authors.
+//
+// Usage:
+//
+//	roomctl diff [-json] <old-graph.json> <new-graph.json>
+//	roomctl test [-graph <graph.json>] [-json] <room-dir>...
+//
+// diff exits 0 when the graphs are identical, 1 when they differ, and 2 on error.
+//
+// test plays the room tests under <room-dir>/tests against the room's scene
+// graph (default graphs/scene-graph.v1.json) in memory. It exits 0 when every
+// test passes, 1 when any fails, and 2 on error.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: roomctl <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  diff [-json] <old-graph.json> <new-graph.json>   semantic diff between two scene graphs")
+	fmt.Fprintln(os.Stderr, "  test [-graph <graph.json>] [-json] <room-dir>...    run room tests against the scene graph")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "diff":
+		os.Exit(runDiff(os.Args[2:]))
+	case "test":
+		os.Exit(runTest(os.Args[2:]))
+	case "help", "-h", "--help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "roomctl: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+}
+
+func runDiff(args []string) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the diff as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fmt.Fprintln(os.Stderr, "usage: roomctl diff [-json] <old-graph.json> <new-graph.json>")
+		return 2
+	}
+
+	oldGraph, err := orchestrator.LoadSceneGraph(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "roomctl: %s: %v\n", fs.Arg(0), err)
+		return 2
+	}
+	newGraph, err := orchestrator.LoadSceneGraph(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "roomctl: %s: %v\n", fs.Arg(1), err)
+		return 2
+	}
+
+	diff := orchestrator.DiffSceneGraphs(oldGraph, newGraph)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(diff); err != nil {
+			fmt.Fprintf(os.Stderr, "roomctl: %v\n", err)
+			return 2
+		}
+	} else {
+		diff.WriteText(os.Stdout)
+	}
+
+	if diff.IsEmpty() {
+		return 0
+	}
+	return 1
+}
+
+func runTest(args []string) int {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	graphPath := fs.String("graph", "", "scene graph to test (default <room-dir>/"+orchestrator.DefaultRoomGraph+")")
+	asJSON := fs.Bool("json", false, "print the results as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 || (*graphPath != "" && fs.NArg() != 1) {
+		fmt.Fprintln(os.Stderr, "usage: roomctl test [-graph <graph.json>] [-json] <room-dir>...")
+		fmt.Fprintln(os.Stderr, "       (-graph takes a single room)")
+		return 2
+	}
+
+	var all []*orchestrator.RoomTestResult
+	for _, dir := range fs.Args() {
+		results, err := orchestrator.RunRoomTests(dir, *graphPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "roomctl: %v\n", err)
+			return 2
+		}
+		if len(results) == 0 {
+			fmt.Fprintf(os.Stderr, "roomctl: %s: no room tests (%s/*.yaml)\n", dir, filepath.Join(dir, config.RoomTestDir))
+		}
+		all = append(all, results...)
+	}
+
+	failed := 0
+	for _, res := range all {
+		if !res.Passed() {
+			failed++
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(all); err != nil {
+			fmt.Fprintf(os.Stderr, "roomctl: %v\n", err)
+			return 2
+		}
+	} else {
+		for _, res := range all {
+			if res.Passed() {
+				fmt.Printf("ok    %s\n", res.Path)
+				continue
+			}
+			fmt.Printf("FAIL  %s\n", res.Path)
+			for _, f := range res.Failures {
+				fmt.Printf("      %s\n", f)
+			}
+		}
+		fmt.Printf("%d passed, %d failed\n", len(all)-failed, failed)
+	}
+
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,202 @@
+// Command sentientctl checks room configuration offline, so graph authors
+// catch mistakes before deploying to a room.
+//
+// Usage:
+//
+//	sentientctl validate [-graph <graph.json>] <room-dir>
+//	sentientctl graph lint [-devices <devices.yaml>] <graph.json>
+//
+// validate loads room.yaml, devices.yaml and the scene graph (default
+// graphs/scene-graph.v1.json) of a room directory, checks each against its
+// schema and checks that every device the graph names is declared in
+// devices.yaml. graph lint checks one scene graph; given devices.yaml it also
+// checks the graph's device references and warns about declared devices the
+// graph never names.
+//
+// Both exit 0 when no problems are found, 1 when there are problems, and 2 on
+// usage errors.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+// defaultGraph is the scene graph of a room directory, as deployed.
+const defaultGraph = "graphs/scene-graph.v1.json"
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: sentientctl <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  validate [-graph <graph.json>] <room-dir>            check room.yaml, devices.yaml and the scene graph")
+	fmt.Fprintln(os.Stderr, "  graph lint [-devices <devices.yaml>] <graph.json>    check a scene graph")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "validate":
+		os.Exit(runValidate(os.Args[2:]))
+	case "graph":
+		if len(os.Args) < 3 || os.Args[2] != "lint" {
+			usage()
+			os.Exit(2)
+		}
+		os.Exit(runGraphLint(os.Args[3:]))
+	case "help", "-h", "--help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "sentientctl: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+}
+
+func runValidate(args []string) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	graphPath := fs.String("graph", "", "scene graph to check (default <room-dir>/"+defaultGraph+")")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: sentientctl validate [-graph <graph.json>] <room-dir>")
+		return 2
+	}
+	dir := fs.Arg(0)
+	if *graphPath == "" {
+		*graphPath = filepath.Join(dir, defaultGraph)
+	}
+
+	problems := 0
+	report := func(path string, err error) {
+		for _, e := range splitErrors(err) {
+			fmt.Printf("%s: %v\n", path, e)
+			problems++
+		}
+	}
+
+	roomPath := filepath.Join(dir, "room.yaml")
+	roomCfg, err := config.LoadRoomConfig(roomPath)
+	if err == nil {
+		err = validateRoom(roomCfg)
+	}
+	report(roomPath, err)
+
+	devicesPath := filepath.Join(dir, "devices.yaml")
+	devCfg, err := config.LoadDevicesConfig(devicesPath)
+	report(devicesPath, err)
+
+	if roomCfg != nil && devCfg != nil {
+		_, err := orchestrator.ParseRoutines(roomCfg.Routines, devCfg)
+		report(roomPath, err)
+	}
+
+	sg, err := orchestrator.LoadSceneGraph(*graphPath)
+	report(*graphPath, err)
+
+	if sg != nil && devCfg != nil {
+		report(*graphPath, orchestrator.ValidatePayloadTypes(sg, orchestrator.PayloadTypesFromConfig(devCfg)))
+		report(*graphPath, orchestrator.ValidateDeviceRefs(sg, orchestrator.DeclaredDevices(devCfg)))
+	}
+
+	if problems > 0 {
+		fmt.Printf("%d problem(s) found\n", problems)
+		return 1
+	}
+	fmt.Println("ok")
+	return 0
+}
+
+func runGraphLint(args []string) int {
+	fs := flag.NewFlagSet("graph lint", flag.ContinueOnError)
+	devicesPath := fs.String("devices", "", "devices.yaml to check device references against")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: sentientctl graph lint [-devices <devices.yaml>] <graph.json>")
+		return 2
+	}
+	path := fs.Arg(0)
+
+	var devCfg *config.DevicesConfig
+	if *devicesPath != "" {
+		var err error
+		if devCfg, err = config.LoadDevicesConfig(*devicesPath); err != nil {
+			fmt.Fprintf(os.Stderr, "sentientctl: %s: %v\n", *devicesPath, err)
+			return 2
+		}
+	}
+
+	sg, err := orchestrator.LoadSceneGraph(path)
+	errs := splitErrors(err)
+	if sg != nil {
+		aliases := events.Aliases()
+		for _, name := range sg.LegacyEvents() {
+			fmt.Printf("%s: warning: legacy event name %q is deprecated, use %q\n", path, name, aliases[name])
+		}
+	}
+	if sg != nil && devCfg != nil {
+		errs = append(errs, splitErrors(orchestrator.ValidatePayloadTypes(sg, orchestrator.PayloadTypesFromConfig(devCfg)))...)
+		errs = append(errs, splitErrors(orchestrator.ValidateDeviceRefs(sg, orchestrator.DeclaredDevices(devCfg)))...)
+		for _, id := range orchestrator.UnusedDevices(sg, orchestrator.DeclaredDevices(devCfg)) {
+			fmt.Printf("%s: warning: device %q is never named by the graph\n", path, id)
+		}
+	}
+
+	for _, e := range errs {
+		fmt.Printf("%s: %v\n", path, e)
+	}
+	if len(errs) > 0 {
+		fmt.Printf("%d problem(s) found\n", len(errs))
+		return 1
+	}
+	fmt.Println("ok")
+	return 0
+}
+
+// validateRoom checks the room.yaml settings the orchestrator parses at
+// startup.
+func validateRoom(cfg *config.RoomConfig) error {
+	var errs []error
+	if cfg.Room.ID == "" {
+		errs = append(errs, fmt.Errorf("room.id is required"))
+	}
+	if _, err := orchestrator.ParseRunMode(cfg.RunMode()); err != nil {
+		errs = append(errs, fmt.Errorf("ops.mode: %w", err))
+	}
+	if _, err := orchestrator.ParseReconcileMode(cfg.ReconcileMode()); err != nil {
+		errs = append(errs, fmt.Errorf("ops.reconcile: %w", err))
+	}
+	if _, err := cfg.Location(); err != nil {
+		errs = append(errs, fmt.Errorf("ops.timezone: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// splitErrors returns the errors joined in err, one per problem.
+func splitErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var out []error
+		for _, e := range joined.Unwrap() {
+			out = append(out, splitErrors(e)...)
+		}
+		return out
+	}
+	return []error{err}
+}
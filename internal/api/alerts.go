@@ -23,6 +23,8 @@ const (
 	AlertMQTTDisconnected    = "mqtt_disconnected"
 	AlertPostgresUnavailable = "postgres_unavailable"
 	AlertContainerRestart    = "container_restart"
+	AlertSessionStalled      = "session_stalled"
+	AlertDeviceUnpowered     = "device_unpowered"
 )
 
 // AlertPayload is the JSON structure sent to the webhook.
@@ -60,8 +62,25 @@ var (
 	lastKnownMQTTState      bool
 	lastKnownPostgresState  bool
 	alertMonitorInitialized bool
+
+	// Recent non-info alerts, kept for acknowledgement and shift handover
+	alertLog []*AlertRecord
 )
 
+// maxAlertLog bounds how many alerts are kept for acknowledgement.
+const maxAlertLog = 200
+
+// AlertRecord tracks a sent alert until an operator acknowledges it.
+type AlertRecord struct {
+	AlertID   string    `json:"alert_id"`
+	Event     string    `json:"event"`
+	Severity  string    `json:"severity"`
+	Message   string    `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Resolved  bool      `json:"resolved"`
+	Acked     bool      `json:"acked"`
+}
+
 // InitAlerts initializes the alert system from environment variables.
 func InitAlerts() {
 	alertMu.Lock()
@@ -111,8 +130,13 @@ func generateAlertID(roomName, event string) string {
 // Returns the generated alert_id for correlation with recovery alerts.
 func SendAlert(event, severity, message string, details map[string]interface{}) string {
 	alertMu.Lock()
+	defer alertMu.Unlock()
+	return sendAlertLocked(event, severity, message, details)
+}
+
+// sendAlertLocked sends an alert and records it. Caller must hold alertMu.
+func sendAlertLocked(event, severity, message string, details map[string]interface{}) string {
 	webhookURL := alertConfig.WebhookURL
-	alertMu.Unlock()
 
 	roomName := GetRoomName()
 	if roomName == "" {
@@ -120,6 +144,7 @@ func SendAlert(event, severity, message string, details map[string]interface{})
 	}
 
 	alertID := generateAlertID(roomName, event)
+	recordAlertLocked(alertID, event, severity, message, details)
 
 	if webhookURL == "" {
 		// No webhook configured, log instead
@@ -142,6 +167,60 @@ func SendAlert(event, severity, message string, details map[string]interface{})
 	return alertID
 }
 
+// recordAlertLocked adds a warning/critical alert to the log, or marks the
+// related alert resolved for a recovery. Caller must hold alertMu.
+func recordAlertLocked(alertID, event, severity, message string, details map[string]interface{}) {
+	if severity == SeverityInfo {
+		if related, ok := details["related_alert_id"].(string); ok {
+			for _, rec := range alertLog {
+				if rec.AlertID == related {
+					rec.Resolved = true
+				}
+			}
+		}
+		return
+	}
+
+	alertLog = append(alertLog, &AlertRecord{
+		AlertID:   alertID,
+		Event:     event,
+		Severity:  severity,
+		Message:   message,
+		Timestamp: time.Now().UTC(),
+	})
+	if len(alertLog) > maxAlertLog {
+		alertLog = alertLog[len(alertLog)-maxAlertLog:]
+	}
+}
+
+// UnackedAlerts returns alerts sent since the given time that no operator has acknowledged.
+func UnackedAlerts(since time.Time) []AlertRecord {
+	alertMu.Lock()
+	defer alertMu.Unlock()
+
+	result := []AlertRecord{}
+	for _, rec := range alertLog {
+		if !rec.Acked && !rec.Timestamp.Before(since) {
+			result = append(result, *rec)
+		}
+	}
+	return result
+}
+
+// AckAlert marks an alert acknowledged. Returns false if the alert is unknown.
+func AckAlert(alertID string) bool {
+	alertMu.Lock()
+	defer alertMu.Unlock()
+
+	for _, rec := range alertLog {
+		if rec.AlertID == alertID {
+			rec.Acked = true
+			return true
+		}
+	}
+	return false
+}
+
 // sendWebhook performs the actual HTTP POST (runs in goroutine).
 func sendWebhook(url string, payload AlertPayload) {
 	body, err := json.Marshal(payload)
@@ -186,7 +265,7 @@ func CheckAndAlertMQTT(connected bool) {
 			if mqttLastAlertID != "" {
 				details["related_alert_id"] = mqttLastAlertID
 			}
-			SendAlert(AlertMQTTDisconnected, SeverityInfo, "MQTT connection restored", details)
+			sendAlertLocked(AlertMQTTDisconnected, SeverityInfo, "MQTT connection restored", details)
 		}
 		mqttDisconnectedSince = time.Time{}
 		mqttAlertSent = false
@@ -207,7 +286,7 @@ func CheckAndAlertMQTT(connected bool) {
 		disconnectedDuration := now.Sub(mqttDisconnectedSince)
 		if disconnectedDuration >= alertConfig.MQTTDisconnectDelay {
 			mqttAlertSent = true
-			mqttLastAlertID = SendAlert(AlertMQTTDisconnected, SeverityWarning,
+			mqttLastAlertID = sendAlertLocked(AlertMQTTDisconnected, SeverityWarning,
 				"MQTT broker disconnected",
 				map[string]interface{}{
 					"disconnected_since":   mqttDisconnectedSince.UTC().Format(time.RFC3339),
@@ -239,7 +318,7 @@ func CheckAndAlertPostgres(connected bool) {
 			if postgresLastAlertID != "" {
 				details["related_alert_id"] = postgresLastAlertID
 			}
-			SendAlert(AlertPostgresUnavailable, SeverityInfo, "PostgreSQL connection restored", details)
+			sendAlertLocked(AlertPostgresUnavailable, SeverityInfo, "PostgreSQL connection restored", details)
 		}
 		postgresDisconnectedAt = time.Time{}
 		postgresAlertSent = false
@@ -260,7 +339,7 @@ func CheckAndAlertPostgres(connected bool) {
 		disconnectedDuration := now.Sub(postgresDisconnectedAt)
 		if disconnectedDuration >= alertConfig.PostgresDisconnectDelay {
 			postgresAlertSent = true
-			postgresLastAlertID = SendAlert(AlertPostgresUnavailable, SeverityCritical,
+			postgresLastAlertID = sendAlertLocked(AlertPostgresUnavailable, SeverityCritical,
 				"PostgreSQL unavailable",
 				map[string]interface{}{
 					"disconnected_since":   postgresDisconnectedAt.UTC().Format(time.RFC3339),
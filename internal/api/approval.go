@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ApprovalsResponse lists operator nodes waiting for approval.
+type ApprovalsResponse struct {
+	Pending []string `json:"pending"`
+}
+
+// operatorApproveHandler lists pending approvals (GET) or approves an operator node (POST).
+func operatorApproveHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	if runtimeController == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "runtime not available"})
+		return
+	}
+
+	if r.Method == http.MethodGet {
+		_ = json.NewEncoder(w).Encode(ApprovalsResponse{Pending: runtimeController.PendingApprovals()})
+		return
+	}
+
+	var req OperatorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "invalid JSON"})
+		return
+	}
+
+	if req.NodeID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "node_id required"})
+		return
+	}
+
+	if !runtimeController.HasNode(req.NodeID) {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "node not found"})
+		return
+	}
+
+	// The runtime emits operator.approved (registry-approved)
+	if err := runtimeController.ApproveNode(req.NodeID); err != nil {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(OperatorResponse{OK: true})
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+func TestOperatorApproveHandler(t *testing.T) {
+	rt := orchestrator.NewRuntime(&orchestrator.SceneGraph{
+		Version: 1,
+		Scenes: []orchestrator.Scene{{
+			ID:    "scene_fog",
+			Entry: "confirm_fog",
+			Nodes: []orchestrator.Node{
+				{ID: "confirm_fog", Type: "operator", Config: map[string]interface{}{}},
+				{ID: "done", Type: "terminal", Config: map[string]interface{}{}},
+			},
+			Edges: []orchestrator.Edge{{From: "confirm_fog", To: "done"}},
+		}},
+	})
+	SetRuntimeController(rt)
+	defer SetRuntimeController(nil)
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+
+	w := httptest.NewRecorder()
+	operatorApproveHandler(w, httptest.NewRequest("GET", "/operator/approve", nil))
+	var resp ApprovalsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Pending) != 1 || resp.Pending[0] != "confirm_fog" {
+		t.Errorf("expected confirm_fog pending, got %v", resp.Pending)
+	}
+
+	w = httptest.NewRecorder()
+	operatorApproveHandler(w, httptest.NewRequest("POST", "/operator/approve", strings.NewReader(`{"node_id": "missing"}`)))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown node, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	operatorApproveHandler(w, httptest.NewRequest("POST", "/operator/approve", strings.NewReader(`{"node_id": "confirm_fog"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if rt.GetNodeState("done") != orchestrator.NodeStateCompleted {
+		t.Errorf("expected flow to continue after approval, got %s", rt.GetNodeState("done"))
+	}
+
+	w = httptest.NewRecorder()
+	operatorApproveHandler(w, httptest.NewRequest("POST", "/operator/approve", strings.NewReader(`{"node_id": "confirm_fog"}`)))
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status 409 when not awaiting approval, got %d", w.Code)
+	}
+}
@@ -94,6 +94,15 @@ func authenticate(r *http.Request) Role {
 	return ""
 }
 
+// requestUser returns the authenticated username, or "default" when auth is
+// disabled and the request carries no credentials.
+func requestUser(r *http.Request) string {
+	if user, _, ok := r.BasicAuth(); ok && user != "" {
+		return user
+	}
+	return "default"
+}
+
 // secureCompare performs constant-time string comparison to prevent timing attacks.
 func secureCompare(a, b string) bool {
 	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
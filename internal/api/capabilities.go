@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/version"
+)
+
+// CapabilitiesResponse is returned by GET /capabilities. The operator UI
+// reads it on load and hides the controls of features this engine does not
+// offer, so a newer UI keeps working against older or minimal deployments.
+// Features an engine predates are missing and read as false.
+type CapabilitiesResponse struct {
+	Version  string       `json:"version"`
+	Features Capabilities `json:"features"`
+}
+
+// Capabilities are the optional features enabled in this deployment.
+type Capabilities struct {
+	Hints       bool `json:"hints"`       // the loaded graph has puzzle hints
+	TLS         bool `json:"tls"`         // HTTPS
+	Auth        bool `json:"auth"`        // operator and admin credentials required
+	MultiRoom   bool `json:"multi_room"`  // federation with linked rooms
+	Competition bool `json:"competition"` // synchronized head-to-head starts
+	History     bool `json:"history"`     // persisted events and past sessions (storage)
+	Maintenance bool `json:"maintenance"` // device test routines
+	Messages    bool `json:"messages"`    // operator messages to the room
+}
+
+// currentCapabilities reports the features enabled right now.
+func currentCapabilities() Capabilities {
+	graphState.mu.RLock()
+	active := graphState.active
+	graphState.mu.RUnlock()
+
+	return Capabilities{
+		Hints:       runtimeController != nil && active != nil && active.HasHints(),
+		TLS:         IsTLSEnabled(),
+		Auth:        IsAuthEnabled(),
+		MultiRoom:   federationReceiver != nil,
+		Competition: competitionController != nil,
+		History:     events.GetStore() != nil,
+		Maintenance: maintenanceController != nil,
+		Messages:    messageRelay != nil,
+	}
+}
+
+// capabilitiesHandler lists the optional features of this engine (GET
+// /capabilities). It is public so the UI can adapt, e.g. whether to ask for
+// credentials, before the operator signs in.
+func capabilitiesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(CapabilitiesResponse{
+		Version:  version.Version,
+		Features: currentCapabilities(),
+	})
+}
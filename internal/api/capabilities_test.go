@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+func TestCapabilitiesHandler(t *testing.T) {
+	get := func() CapabilitiesResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		capabilitiesHandler(w, httptest.NewRequest("GET", "/capabilities", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+		var resp CapabilitiesResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		return resp
+	}
+
+	SetRuntimeController(nil)
+	SetSceneGraph(nil)
+	if resp := get(); resp.Version == "" || resp.Features.Hints {
+		t.Errorf("expected no game features without a runtime, got %+v", resp)
+	}
+
+	sg := &orchestrator.SceneGraph{Version: 1, Scenes: []orchestrator.Scene{{
+		ID: "intro", Entry: "puzzle_scarab",
+		Nodes: []orchestrator.Node{{ID: "puzzle_scarab", Type: "puzzle", Config: map[string]interface{}{}}},
+	}}}
+	rt := orchestrator.NewRuntime(sg)
+	SetRuntimeController(rt)
+	SetSceneGraph(sg)
+	defer SetRuntimeController(nil)
+	defer SetSceneGraph(nil)
+
+	features := get().Features
+	if features.Hints || features.MultiRoom {
+		t.Errorf("expected no hints without puzzle hints, got %+v", features)
+	}
+
+	sg.Scenes[0].Nodes[0].Config["hints"] = []interface{}{
+		map[string]interface{}{"text": "Look under the altar", "device_id": "crypt_screen", "signal": "show"},
+	}
+	if !get().Features.Hints {
+		t.Error("expected hints once a puzzle has them")
+	}
+
+	w := httptest.NewRecorder()
+	capabilitiesHandler(w, httptest.NewRequest("POST", "/capabilities", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for POST, got %d", w.Code)
+	}
+}
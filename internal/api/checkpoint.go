@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// CheckpointResetRequest names the checkpoint to rewind to.
+// An empty name selects the most recently reached checkpoint.
+type CheckpointResetRequest struct {
+	Name string `json:"name"`
+}
+
+// operatorResetToCheckpointHandler restores a checkpoint snapshot.
+func operatorResetToCheckpointHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	var req CheckpointResetRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "invalid JSON"})
+			return
+		}
+	}
+
+	if runtimeController == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "runtime not available"})
+		return
+	}
+
+	if !runtimeController.IsGameActive() {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "no active session"})
+		return
+	}
+
+	if !requireConfirm(w, r, "reset_to_checkpoint:"+req.Name) {
+		return
+	}
+
+	// Emit operator.reset event (registry-approved)
+	events.Emit("info", "operator.reset", "", map[string]interface{}{
+		"checkpoint": req.Name,
+		"action":     "reset_to_checkpoint",
+	})
+
+	if err := runtimeController.ResetToCheckpoint(req.Name); err != nil {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(OperatorResponse{OK: true})
+}
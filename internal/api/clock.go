@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+// ClockAdjustRequest adds or subtracts minutes from the session countdown.
+type ClockAdjustRequest struct {
+	Minutes float64 `json:"minutes"`
+}
+
+// gameClockHandler returns the current session countdown.
+func gameClockHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	if runtimeController == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "runtime not available"})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(runtimeController.GameClock())
+}
+
+func operatorClockPauseHandler(w http.ResponseWriter, r *http.Request) {
+	operatorClockHandler(w, r, "operator.pause", func() error {
+		return runtimeController.PauseClock()
+	})
+}
+
+func operatorClockResumeHandler(w http.ResponseWriter, r *http.Request) {
+	operatorClockHandler(w, r, "operator.resume", func() error {
+		return runtimeController.ResumeClock()
+	})
+}
+
+// operatorClockHandler records the operator event and applies a clock change.
+func operatorClockHandler(w http.ResponseWriter, r *http.Request, event string, apply func() error) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	if runtimeController == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "runtime not available"})
+		return
+	}
+
+	if !runtimeController.IsGameActive() {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "no active session"})
+		return
+	}
+
+	// Emit operator event
+	events.Emit("info", event, "", map[string]interface{}{
+		"timer_id": orchestrator.GameClockID,
+	})
+
+	if err := apply(); err != nil {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(OperatorResponse{OK: true})
+}
+
+func operatorClockAdjustHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	var req ClockAdjustRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "invalid JSON"})
+		return
+	}
+
+	if req.Minutes == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "minutes required"})
+		return
+	}
+
+	if runtimeController == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "runtime not available"})
+		return
+	}
+
+	if !runtimeController.IsGameActive() {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "no active session"})
+		return
+	}
+
+	delta := time.Duration(req.Minutes * float64(time.Minute))
+	if err := runtimeController.AdjustClock(delta); err != nil {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(OperatorResponse{OK: true})
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+func TestGameClockHandlers(t *testing.T) {
+	sg, err := orchestrator.LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	rt := orchestrator.NewRuntime(sg)
+	SetRuntimeController(rt)
+	defer SetRuntimeController(nil)
+
+	// No active session
+	w := httptest.NewRecorder()
+	operatorClockPauseHandler(w, httptest.NewRequest("POST", "/operator/clock/pause", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 without active session, got %d", w.Code)
+	}
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+
+	w = httptest.NewRecorder()
+	operatorClockPauseHandler(w, httptest.NewRequest("POST", "/operator/clock/pause", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 on pause, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	operatorClockAdjustHandler(w, httptest.NewRequest("POST", "/operator/clock/adjust", strings.NewReader(`{"minutes": -5}`)))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 on adjust, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	operatorClockAdjustHandler(w, httptest.NewRequest("POST", "/operator/clock/adjust", strings.NewReader(`{}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 without minutes, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	gameClockHandler(w, httptest.NewRequest("GET", "/game/clock", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var clock orchestrator.ClockState
+	if err := json.NewDecoder(w.Body).Decode(&clock); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !clock.Paused {
+		t.Error("expected clock to be paused")
+	}
+	if clock.DurationMs != 55*60*1000 {
+		t.Errorf("expected 55 minute duration after adjust, got %dms", clock.DurationMs)
+	}
+
+	w = httptest.NewRecorder()
+	operatorClockResumeHandler(w, httptest.NewRequest("POST", "/operator/clock/resume", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 on resume, got %d", w.Code)
+	}
+	if rt.GameClock().Paused {
+		t.Error("expected clock to be running after resume")
+	}
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+// CompetitionController arms synchronized starts across rooms (implemented by *orchestrator.Competition).
+type CompetitionController interface {
+	Arm(rooms []string, sceneID string, lead time.Duration) (orchestrator.CompetitionInfo, error)
+	Cancel() error
+	Standings() orchestrator.Standings
+}
+
+var competitionController CompetitionController
+
+// SetCompetitionController enables the /competition endpoints.
+func SetCompetitionController(cc CompetitionController) {
+	competitionController = cc
+}
+
+// CompetitionArmRequest arms a competition with sibling rooms.
+type CompetitionArmRequest struct {
+	Rooms      []string `json:"rooms"`
+	SceneID    string   `json:"scene_id,omitempty"`
+	StartInSec int      `json:"start_in_sec,omitempty"` // default 10
+}
+
+// CompetitionArmResponse returns the armed competition.
+type CompetitionArmResponse struct {
+	OK          bool                          `json:"ok"`
+	Error       string                        `json:"error,omitempty"`
+	Competition *orchestrator.CompetitionInfo `json:"competition,omitempty"`
+}
+
+// requireCompetition writes 404 if competition mode is not configured.
+func requireCompetition(w http.ResponseWriter) bool {
+	if competitionController == nil {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "competition mode requires federation peers"})
+		return false
+	}
+	return true
+}
+
+// competitionArmHandler arms this room and the given rooms for a synchronized start.
+func competitionArmHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+	if !requireCompetition(w) {
+		return
+	}
+
+	var req CompetitionArmRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "invalid JSON"})
+		return
+	}
+	if req.StartInSec < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "start_in_sec must not be negative"})
+		return
+	}
+
+	info, err := competitionController.Arm(req.Rooms, req.SceneID, time.Duration(req.StartInSec)*time.Second)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, orchestrator.ErrCompetitionArmed) {
+			status = http.StatusConflict
+		}
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(CompetitionArmResponse{OK: false, Error: err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(CompetitionArmResponse{OK: true, Competition: &info})
+}
+
+// competitionCancelHandler disarms a competition that has not started.
+func competitionCancelHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+	if !requireCompetition(w) {
+		return
+	}
+
+	if err := competitionController.Cancel(); err != nil {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(OperatorResponse{OK: true})
+}
+
+// competitionStandingsHandler returns the current competition's ranked rooms.
+func competitionStandingsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+	if !requireCompetition(w) {
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(competitionController.Standings())
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ConfirmTokenTTL is how long a confirmation token stays valid.
+const ConfirmTokenTTL = 30 * time.Second
+
+// ConfirmHeader carries the confirmation token when a destructive request is repeated.
+const ConfirmHeader = "X-Confirm-Token"
+
+// ConfirmResponse is returned with 428 when a destructive action needs confirmation.
+type ConfirmResponse struct {
+	OK           bool      `json:"ok"`
+	Error        string    `json:"error"`
+	ConfirmToken string    `json:"confirm_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// pendingConfirm is an issued token, bound to the action it confirms.
+type pendingConfirm struct {
+	action  string
+	expires time.Time
+}
+
+var (
+	confirmMu      sync.Mutex
+	confirmEnabled bool
+	confirmTokens  = make(map[string]pendingConfirm)
+)
+
+// SetConfirmDestructive enables two-step confirmation of destructive actions during a session.
+func SetConfirmDestructive(enabled bool) {
+	confirmMu.Lock()
+	defer confirmMu.Unlock()
+	confirmEnabled = enabled
+	confirmTokens = make(map[string]pendingConfirm)
+}
+
+// requireConfirm reports whether a destructive request may proceed.
+// Without a valid token for the same action it answers 428 with a fresh token,
+// which the client echoes back in the X-Confirm-Token header. Tokens are single use.
+func requireConfirm(w http.ResponseWriter, r *http.Request, action string) bool {
+	if runtimeController == nil || !runtimeController.IsGameActive() {
+		return true
+	}
+
+	confirmMu.Lock()
+	defer confirmMu.Unlock()
+
+	if !confirmEnabled {
+		return true
+	}
+
+	now := time.Now()
+	for token, p := range confirmTokens {
+		if !now.Before(p.expires) {
+			delete(confirmTokens, token)
+		}
+	}
+
+	msg := "confirmation required"
+	if token := r.Header.Get(ConfirmHeader); token != "" {
+		p, ok := confirmTokens[token]
+		if ok && p.action == action {
+			delete(confirmTokens, token)
+			return true
+		}
+		msg = "invalid or expired confirmation token"
+	}
+
+	secret := make([]byte, 16)
+	if _, err := rand.Read(secret); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "failed to generate token"})
+		return false
+	}
+	token := hex.EncodeToString(secret)
+	expires := now.Add(ConfirmTokenTTL)
+	confirmTokens[token] = pendingConfirm{action: action, expires: expires}
+
+	w.WriteHeader(http.StatusPreconditionRequired)
+	_ = json.NewEncoder(w).Encode(ConfirmResponse{
+		OK:           false,
+		Error:        msg,
+		ConfirmToken: token,
+		ExpiresAt:    expires.UTC(),
+	})
+	return false
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+func TestConfirmDestructiveActions(t *testing.T) {
+	rt := orchestrator.NewRuntime(&orchestrator.SceneGraph{
+		Version: 1,
+		Scenes: []orchestrator.Scene{{
+			ID:    "scene_confirm",
+			Entry: "confirm",
+			Nodes: []orchestrator.Node{
+				{ID: "confirm", Type: "operator", Config: map[string]interface{}{}},
+				{ID: "done", Type: "terminal", Config: map[string]interface{}{}},
+			},
+			Edges: []orchestrator.Edge{{From: "confirm", To: "done"}},
+		}},
+	})
+	SetRuntimeController(rt)
+	defer SetRuntimeController(nil)
+	SetConfirmDestructive(true)
+	defer SetConfirmDestructive(false)
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+
+	// First request is refused with a token
+	w := httptest.NewRecorder()
+	gameStopHandler(w, httptest.NewRequest("POST", "/game/stop", nil))
+	if w.Code != http.StatusPreconditionRequired {
+		t.Fatalf("expected status 428, got %d", w.Code)
+	}
+	var resp ConfirmResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.ConfirmToken == "" {
+		t.Fatal("expected confirm_token")
+	}
+	if !rt.IsGameActive() {
+		t.Fatal("expected game to keep running without confirmation")
+	}
+
+	// A token is bound to the action it was issued for
+	req := httptest.NewRequest("POST", "/operator/reset-node", strings.NewReader(`{"node_id": "confirm"}`))
+	req.Header.Set(ConfirmHeader, resp.ConfirmToken)
+	w = httptest.NewRecorder()
+	operatorResetNodeHandler(w, req)
+	if w.Code != http.StatusPreconditionRequired {
+		t.Errorf("expected status 428 for token of another action, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("POST", "/game/stop", nil)
+	req.Header.Set(ConfirmHeader, resp.ConfirmToken)
+	w = httptest.NewRecorder()
+	gameStopHandler(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200 with token, got %d: %s", w.Code, w.Body.String())
+	}
+	if rt.IsGameActive() {
+		t.Error("expected game stopped after confirmation")
+	}
+
+	// Tokens are single use
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	req = httptest.NewRequest("POST", "/game/stop", nil)
+	req.Header.Set(ConfirmHeader, resp.ConfirmToken)
+	w = httptest.NewRecorder()
+	gameStopHandler(w, req)
+	if w.Code != http.StatusPreconditionRequired {
+		t.Errorf("expected status 428 for reused token, got %d", w.Code)
+	}
+}
+
+func TestConfirmRestart(t *testing.T) {
+	rt := orchestrator.NewRuntime(&orchestrator.SceneGraph{
+		Version: 1,
+		Scenes: []orchestrator.Scene{{
+			ID:    "scene_confirm",
+			Entry: "done",
+			Nodes: []orchestrator.Node{
+				{ID: "done", Type: "operator", Config: map[string]interface{}{}},
+			},
+		}},
+	})
+	SetRuntimeController(rt)
+	defer SetRuntimeController(nil)
+	SetConfirmDestructive(true)
+	defer SetConfirmDestructive(false)
+
+	// Starting a game with none running needs no confirmation
+	w := httptest.NewRecorder()
+	gameStartHandler(w, httptest.NewRequest("POST", "/game/start", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	defer rt.StopGame()
+	session := events.CurrentSession()
+
+	// Starting over ends the active session, so it does
+	w = httptest.NewRecorder()
+	gameStartHandler(w, httptest.NewRequest("POST", "/game/start", nil))
+	if w.Code != http.StatusPreconditionRequired {
+		t.Fatalf("expected status 428, got %d", w.Code)
+	}
+	var resp ConfirmResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if events.CurrentSession() != session {
+		t.Fatal("expected the session to keep running without confirmation")
+	}
+
+	req := httptest.NewRequest("POST", "/game/start", nil)
+	req.Header.Set(ConfirmHeader, resp.ConfirmToken)
+	w = httptest.NewRecorder()
+	gameStartHandler(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200 with token, got %d: %s", w.Code, w.Body.String())
+	}
+	if events.CurrentSession() == session {
+		t.Error("expected a new session after confirmation")
+	}
+}
+
+func TestConfirmDisabled(t *testing.T) {
+	rt := orchestrator.NewRuntime(&orchestrator.SceneGraph{
+		Version: 1,
+		Scenes: []orchestrator.Scene{{
+			ID:    "scene_confirm",
+			Entry: "done",
+			Nodes: []orchestrator.Node{
+				{ID: "done", Type: "operator", Config: map[string]interface{}{}},
+			},
+		}},
+	})
+	SetRuntimeController(rt)
+	defer SetRuntimeController(nil)
+	SetConfirmDestructive(false)
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	gameStopHandler(w, httptest.NewRequest("POST", "/game/stop", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 with confirmation disabled, got %d", w.Code)
+	}
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/AaronLay10/SentientEngine/internal/mqtt"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// ControllerInventory lists registered controllers.
+type ControllerInventory interface {
+	Controllers() []mqtt.ControllerStatus
+}
+
+var controllerInventory ControllerInventory
+
+// SetControllerInventory sets the source used by /controllers.
+func SetControllerInventory(inv ControllerInventory) {
+	controllerInventory = inv
+}
+
+// ControllerLogSource returns controllers' recent debug output.
+type ControllerLogSource interface {
+	Recent(controllerID string, limit int) ([]storage.ControllerLogRow, error)
+}
+
+var controllerLogs ControllerLogSource
+
+// SetControllerLogs sets the source used by /controllers/{id}/logs.
+func SetControllerLogs(src ControllerLogSource) {
+	controllerLogs = src
+}
+
+// ControllersResponse is returned by /controllers.
+type ControllersResponse struct {
+	Controllers []mqtt.ControllerStatus `json:"controllers"`
+}
+
+// ControllerLogsResponse is returned by /controllers/{id}/logs.
+type ControllerLogsResponse struct {
+	ControllerID string                     `json:"controller_id"`
+	Lines        []storage.ControllerLogRow `json:"lines"` // oldest first
+}
+
+// controllersHandler lists every controller that has registered with its
+// type, firmware, uptime, heartbeat interval and devices (GET /controllers).
+func controllersHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	if controllerInventory == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "controller inventory not available"})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(ControllersResponse{Controllers: controllerInventory.Controllers()})
+}
+
+// controllerHandler serves GET /controllers/{id}/logs: the controller's most
+// recent debug log lines (limit, default and max 200).
+func controllerHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	id, sub, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/controllers/"), "/")
+	if id == "" || sub != "logs" {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "not found"})
+		return
+	}
+
+	if controllerLogs == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "controller logs not available"})
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = n
+	}
+
+	lines, err := controllerLogs.Recent(id, limit)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if lines == nil {
+		lines = []storage.ControllerLogRow{}
+	}
+	_ = json.NewEncoder(w).Encode(ControllerLogsResponse{ControllerID: id, Lines: lines})
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/mqtt"
+)
+
+func TestControllersHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	controllersHandler(w, httptest.NewRequest("GET", "/controllers", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 without an inventory, got %d", w.Code)
+	}
+
+	m := mqtt.NewMonitor(map[string]mqtt.DeviceSpec{"crypt_door": {Type: "door"}}, 2.0)
+	m.HandleRegistration(&mqtt.RegistrationPayload{
+		Version:    1,
+		Controller: mqtt.ControllerInfo{ID: "ctrl-001", Type: "esp32", Firmware: "1.4.0", HeartbeatSec: 5},
+		Devices:    []mqtt.DeviceRegistration{{LogicalID: "crypt_door", Type: "door"}},
+	})
+	SetControllerInventory(m)
+	defer SetControllerInventory(nil)
+
+	w = httptest.NewRecorder()
+	controllersHandler(w, httptest.NewRequest("GET", "/controllers", nil))
+	var resp ControllersResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if w.Code != http.StatusOK || len(resp.Controllers) != 1 || resp.Controllers[0].Firmware != "1.4.0" ||
+		!resp.Controllers[0].Connected || resp.Controllers[0].Devices[0] != "crypt_door" {
+		t.Errorf("expected ctrl-001 listed, got %d %+v", w.Code, resp)
+	}
+}
+
+func TestControllerLogsHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	controllerHandler(w, httptest.NewRequest("GET", "/controllers/ctrl-001/logs", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 without a log source, got %d", w.Code)
+	}
+
+	logs := mqtt.NewControllerLogs(60, 0)
+	now := time.Now()
+	logs.Handle("devices/ctrl-001/logs", []byte("boot ok"), now)
+	logs.Handle("devices/ctrl-001/logs", []byte(`{"level": "error", "msg": "sensor timeout"}`), now)
+	SetControllerLogs(logs)
+	defer SetControllerLogs(nil)
+
+	w = httptest.NewRecorder()
+	controllerHandler(w, httptest.NewRequest("GET", "/controllers/ctrl-001/logs?limit=1", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp ControllerLogsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.ControllerID != "ctrl-001" || len(resp.Lines) != 1 || resp.Lines[0].Message != "sensor timeout" {
+		t.Errorf("expected the most recent line, got %+v", resp)
+	}
+
+	w = httptest.NewRecorder()
+	controllerHandler(w, httptest.NewRequest("GET", "/controllers/ctrl-009/logs", nil))
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil || resp.Lines == nil || len(resp.Lines) != 0 {
+		t.Errorf("expected no lines for a silent controller, got %+v (%v)", resp, err)
+	}
+
+	for path, want := range map[string]int{
+		"/controllers/ctrl-001":              http.StatusNotFound,
+		"/controllers/ctrl-001/state":        http.StatusNotFound,
+		"/controllers/ctrl-001/logs?limit=x": http.StatusBadRequest,
+	} {
+		w = httptest.NewRecorder()
+		controllerHandler(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", path, want, w.Code)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	controllerHandler(w, httptest.NewRequest("POST", "/controllers/ctrl-001/logs", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+}
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/AaronLay10/SentientEngine/internal/mqtt"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+// DeviceInventory exposes device health and asset metadata.
+type DeviceInventory interface {
+	Devices() []mqtt.DeviceStatus
+	DeviceSignals(logicalID string) (map[string]mqtt.SignalValue, bool)
+	UpdateAsset(logicalID string, asset mqtt.DeviceAsset) error
+}
+
+var deviceInventory DeviceInventory
+
+// SetDeviceInventory sets the source used by device endpoints.
+func SetDeviceInventory(inv DeviceInventory) {
+	deviceInventory = inv
+}
+
+// DevicesReloader re-reads devices.yaml at runtime and cross-checks the scene
+// graph against it.
+type DevicesReloader interface {
+	Reload() (*orchestrator.DevicesReload, error)
+	UnknownDeviceRefs() []orchestrator.DeviceRef
+}
+
+var devicesReloader DevicesReloader
+
+// SetDevicesReloader sets the reloader used by /admin/devices/reload, /ready
+// and /graph/validation.
+func SetDevicesReloader(dr DevicesReloader) {
+	devicesReloader = dr
+}
+
+// DevicesReloadResponse is returned by /admin/devices/reload.
+type DevicesReloadResponse struct {
+	OK     bool                        `json:"ok"`
+	Error  string                      `json:"error,omitempty"`
+	Reload *orchestrator.DevicesReload `json:"reload,omitempty"`
+}
+
+// DevicesResponse is returned by /devices.
+type DevicesResponse struct {
+	Devices []mqtt.DeviceStatus `json:"devices"`
+}
+
+// DeviceStateResponse is returned by /devices/{id}/state.
+type DeviceStateResponse struct {
+	LogicalID string                      `json:"logical_id"`
+	State     map[string]mqtt.SignalValue `json:"state"` // payload field -> last value
+}
+
+// AssetUpdateRequest edits a device's asset metadata.
+// Omitted fields are left unchanged; empty strings clear them.
+type AssetUpdateRequest struct {
+	LogicalID       string  `json:"logical_id"`
+	InstallDate     *string `json:"install_date"`
+	Firmware        *string `json:"firmware"`
+	LastMaintenance *string `json:"last_maintenance"`
+	Notes           *string `json:"notes"`
+}
+
+// devicesHandler lists devices with registration (controller, topics, signals),
+// health (connected, last_seen) and asset metadata.
+func devicesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	if deviceInventory == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "device inventory not available"})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(DevicesResponse{Devices: deviceInventory.Devices()})
+}
+
+// deviceStateHandler serves GET /devices/{id}/state: the last value the device
+// reported for each payload field and when. Conditions read the same values
+// as state.<id>.<field>.
+func deviceStateHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	id, sub, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/devices/"), "/")
+	if id == "" || sub != "state" {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "not found"})
+		return
+	}
+
+	if deviceInventory == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "device inventory not available"})
+		return
+	}
+
+	signals, known := deviceInventory.DeviceSignals(id)
+	if !known {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "device not found"})
+		return
+	}
+	if signals == nil {
+		signals = map[string]mqtt.SignalValue{}
+	}
+	_ = json.NewEncoder(w).Encode(DeviceStateResponse{LogicalID: id, State: signals})
+}
+
+// deviceAssetHandler updates asset metadata for one device.
+func deviceAssetHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	var req AssetUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "invalid JSON"})
+		return
+	}
+
+	if req.LogicalID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "logical_id required"})
+		return
+	}
+
+	if deviceInventory == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "device inventory not available"})
+		return
+	}
+
+	var current *mqtt.DeviceStatus
+	for _, dev := range deviceInventory.Devices() {
+		if dev.LogicalID == req.LogicalID {
+			dev := dev
+			current = &dev
+			break
+		}
+	}
+	if current == nil {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "device not found"})
+		return
+	}
+
+	var asset mqtt.DeviceAsset
+	if current.Asset != nil {
+		asset = *current.Asset
+	}
+	if req.InstallDate != nil {
+		asset.InstallDate = *req.InstallDate
+	}
+	if req.Firmware != nil {
+		asset.Firmware = *req.Firmware
+	}
+	if req.LastMaintenance != nil {
+		asset.LastMaintenance = *req.LastMaintenance
+	}
+	if req.Notes != nil {
+		asset.Notes = *req.Notes
+	}
+
+	if err := asset.Validate(); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: err.Error()})
+		return
+	}
+
+	if err := deviceInventory.UpdateAsset(req.LogicalID, asset); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(OperatorResponse{OK: true})
+}
+
+// devicesReloadHandler serves POST /admin/devices/reload: re-reads
+// devices.yaml and re-validates registered controllers against it. A file
+// that does not load or no longer fits the scene graph is rejected and
+// nothing changes.
+func devicesReloadHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(DevicesReloadResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	if devicesReloader == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(DevicesReloadResponse{OK: false, Error: "devices reload not available"})
+		return
+	}
+
+	reload, err := devicesReloader.Reload()
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_ = json.NewEncoder(w).Encode(DevicesReloadResponse{OK: false, Error: err.Error()})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(DevicesReloadResponse{OK: true, Reload: reload})
+}
@@ -0,0 +1,211 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/mqtt"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+func TestDeviceAssetHandlers(t *testing.T) {
+	m := mqtt.NewMonitor(map[string]mqtt.DeviceSpec{"crypt_door": {Type: "door"}}, 2.0)
+	SetDeviceInventory(m)
+	defer SetDeviceInventory(nil)
+
+	w := httptest.NewRecorder()
+	deviceAssetHandler(w, httptest.NewRequest("POST", "/admin/devices/asset",
+		strings.NewReader(`{"logical_id":"crypt_door","firmware":"1.4.2","notes":"spare in cabinet B"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// Partial update keeps other fields
+	w = httptest.NewRecorder()
+	deviceAssetHandler(w, httptest.NewRequest("POST", "/admin/devices/asset",
+		strings.NewReader(`{"logical_id":"crypt_door","last_maintenance":"2025-01-15"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	deviceAssetHandler(w, httptest.NewRequest("POST", "/admin/devices/asset",
+		strings.NewReader(`{"logical_id":"crypt_door","install_date":"yesterday"}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid date, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	deviceAssetHandler(w, httptest.NewRequest("POST", "/admin/devices/asset",
+		strings.NewReader(`{"logical_id":"unknown"}`)))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown device, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	devicesHandler(w, httptest.NewRequest("GET", "/devices", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var resp DevicesResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Devices) != 1 || resp.Devices[0].Asset == nil {
+		t.Fatalf("expected crypt_door with asset, got %+v", resp.Devices)
+	}
+	asset := resp.Devices[0].Asset
+	if asset.Firmware != "1.4.2" || asset.LastMaintenance != "2025-01-15" {
+		t.Errorf("expected merged asset, got %+v", asset)
+	}
+}
+
+func TestDeviceStateHandler(t *testing.T) {
+	m := mqtt.NewMonitor(map[string]mqtt.DeviceSpec{"crypt_door": {Type: "door"}}, 2.0)
+	SetDeviceInventory(m)
+	defer SetDeviceInventory(nil)
+
+	w := httptest.NewRecorder()
+	deviceStateHandler(w, httptest.NewRequest("GET", "/devices/crypt_door/state", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp DeviceStateResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.LogicalID != "crypt_door" || resp.State == nil || len(resp.State) != 0 {
+		t.Errorf("expected empty state before the device reports, got %+v", resp)
+	}
+
+	for path, want := range map[string]int{
+		"/devices/unknown/state":   http.StatusNotFound,
+		"/devices/crypt_door":      http.StatusNotFound,
+		"/devices/crypt_door/logs": http.StatusNotFound,
+	} {
+		w = httptest.NewRecorder()
+		deviceStateHandler(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", path, want, w.Code)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	deviceStateHandler(w, httptest.NewRequest("POST", "/devices/crypt_door/state", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+}
+
+// fakeReloader returns a fixed reload outcome.
+type fakeReloader struct {
+	reload  *orchestrator.DevicesReload
+	err     error
+	unknown []orchestrator.DeviceRef
+}
+
+func (f *fakeReloader) Reload() (*orchestrator.DevicesReload, error) {
+	return f.reload, f.err
+}
+
+func (f *fakeReloader) UnknownDeviceRefs() []orchestrator.DeviceRef {
+	return f.unknown
+}
+
+func TestDevicesReloadHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	devicesReloadHandler(w, httptest.NewRequest("POST", "/admin/devices/reload", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 without a reloader, got %d", w.Code)
+	}
+
+	reloader := &fakeReloader{reload: &orchestrator.DevicesReload{Devices: 3, Added: []string{"altar"}}}
+	SetDevicesReloader(reloader)
+	defer SetDevicesReloader(nil)
+
+	w = httptest.NewRecorder()
+	devicesReloadHandler(w, httptest.NewRequest("POST", "/admin/devices/reload", nil))
+	var resp DevicesReloadResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if w.Code != http.StatusOK || !resp.OK || resp.Reload == nil || resp.Reload.Added[0] != "altar" {
+		t.Errorf("expected the reload summary, got %d %+v", w.Code, resp)
+	}
+
+	reloader.err = errors.New("devices.yaml: bad")
+	w = httptest.NewRecorder()
+	devicesReloadHandler(w, httptest.NewRequest("POST", "/admin/devices/reload", nil))
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422 for a rejected file, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	devicesReloadHandler(w, httptest.NewRequest("GET", "/admin/devices/reload", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+}
+
+func TestDeviceHealthInReadyAndMetrics(t *testing.T) {
+	clearTLSEnvServer(t)
+	m := mqtt.NewMonitor(map[string]mqtt.DeviceSpec{
+		"crypt_door": {Type: "door", Required: true},
+		"altar":      {Type: "sensor", Required: true},
+		"fog":        {Type: "relay"},
+	}, 2.0)
+	m.HandleRegistration(&mqtt.RegistrationPayload{
+		Version:    1,
+		Controller: mqtt.ControllerInfo{ID: "ctrl-001", HeartbeatSec: 5},
+		Devices:    []mqtt.DeviceRegistration{{LogicalID: "crypt_door", Type: "door"}, {LogicalID: "altar", Type: "sensor"}},
+	})
+	SetDeviceInventory(m)
+	defer SetDeviceInventory(nil)
+
+	SetOrchestratorReady(true)
+	SetMQTTState(true, false)
+	SetPostgresState(true, false)
+	ready := func() ReadinessResponse {
+		t.Helper()
+		w := httptest.NewRecorder()
+		readyHandler(w, httptest.NewRequest("GET", "/ready", nil))
+		var resp ReadinessResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return resp
+	}
+	if check := ready().Checks["devices"]; check.Status != "ok" {
+		t.Errorf("expected devices ok with every required device connected, got %+v", check)
+	}
+
+	w := httptest.NewRecorder()
+	metricsHandler(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		`device="crypt_door",required="true"} 1`,
+		`device="fog",required="false"} 0`,
+		`sentient_device_last_seen_timestamp{`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected metrics to contain %s", want)
+		}
+	}
+	if strings.Contains(body, `device="fog"} `) {
+		t.Error("expected no last seen timestamp for the unregistered fog machine")
+	}
+
+	// The controller goes offline: reported, but the engine stays ready
+	m.HandleStatus("sentient/status/ctrl-001", []byte("offline"), time.Now())
+	resp := ready()
+	check := resp.Checks["devices"]
+	if !resp.Ready || check.Status != "warning" || len(check.Details) != 2 ||
+		!strings.HasPrefix(check.Details[0], "required device 'altar' disconnected (last seen ") {
+		t.Errorf("expected both required devices reported disconnected, got %+v", resp)
+	}
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+// FederationReceiver accepts events from sibling rooms (implemented by *orchestrator.Federation).
+type FederationReceiver interface {
+	Receive(token string, e orchestrator.FederatedEvent) error
+}
+
+var federationReceiver FederationReceiver
+
+// SetFederationReceiver enables POST /federation/events.
+func SetFederationReceiver(fr FederationReceiver) {
+	federationReceiver = fr
+}
+
+// federationEventsHandler accepts an event from a sibling room (ADR-012).
+// The shared federation token is the only credential.
+func federationEventsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	if federationReceiver == nil {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "federation not configured"})
+		return
+	}
+
+	var fe orchestrator.FederatedEvent
+	if err := json.NewDecoder(r.Body).Decode(&fe); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "invalid JSON"})
+		return
+	}
+
+	if err := federationReceiver.Receive(r.Header.Get(orchestrator.FederationTokenHeader), fe); err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, orchestrator.ErrFederationUnauthorized):
+			status = http.StatusUnauthorized
+		case errors.Is(err, orchestrator.ErrFederationLoop):
+			status = http.StatusConflict
+		}
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(OperatorResponse{OK: true})
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+func TestFederationEventsHandler(t *testing.T) {
+	events.Clear()
+	post := func(token, body string) int {
+		req := httptest.NewRequest("POST", "/federation/events", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set(orchestrator.FederationTokenHeader, token)
+		}
+		w := httptest.NewRecorder()
+		federationEventsHandler(w, req)
+		return w.Code
+	}
+	event := `{"origin": "generator_room", "seq": 7, "event": "puzzle.solved", "fields": {"node_id": "puzzle_generator"}}`
+
+	if code := post("s3cret", event); code != http.StatusNotFound {
+		t.Errorf("expected 404 without federation, got %d", code)
+	}
+
+	t.Setenv("TEST_FEDERATION_TOKEN", "s3cret")
+	f, err := orchestrator.NewFederation("elevator_room", &config.FederationConfig{
+		Version:  1,
+		TokenEnv: "TEST_FEDERATION_TOKEN",
+		Peers:    []config.FederationPeer{{Room: "generator_room", URL: "http://unused"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to create federation: %v", err)
+	}
+	SetFederationReceiver(f)
+	defer SetFederationReceiver(nil)
+
+	tests := []struct {
+		name  string
+		token string
+		body  string
+		want  int
+	}{
+		{"no token", "", event, http.StatusUnauthorized},
+		{"invalid JSON", "s3cret", "{", http.StatusBadRequest},
+		{"own event", "s3cret", `{"origin": "elevator_room", "event": "puzzle.solved"}`, http.StatusConflict},
+		{"accepted", "s3cret", event, http.StatusOK},
+	}
+	for _, tt := range tests {
+		if code := post(tt.token, tt.body); code != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, code)
+		}
+	}
+
+	snap := events.Snapshot()
+	if len(snap) != 1 || snap[0].Name != "room.federated" || snap[0].Fields["from_room"] != "generator_room" {
+		t.Errorf("expected one room.federated from generator_room, got %+v", snap)
+	}
+}
+
+func TestCompetitionHandlersRequireFederation(t *testing.T) {
+	SetCompetitionController(nil)
+	req := httptest.NewRequest("GET", "/competition/standings", nil)
+	w := httptest.NewRecorder()
+	competitionStandingsHandler(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 without competition mode, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/competition/arm", nil)
+	w = httptest.NewRecorder()
+	competitionArmHandler(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
@@ -0,0 +1,239 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+// graphState holds the scene graph currently loaded by the orchestrator
+// and the path of the staged (unpublished) revision.
+var graphState = struct {
+	mu         sync.RWMutex
+	active     *orchestrator.SceneGraph
+	stagedPath string
+}{}
+
+// SetSceneGraph sets the scene graph currently loaded by the orchestrator.
+func SetSceneGraph(sg *orchestrator.SceneGraph) {
+	graphState.mu.Lock()
+	defer graphState.mu.Unlock()
+	graphState.active = sg
+}
+
+// SetStagedGraphPath sets the path of the staged scene graph revision.
+func SetStagedGraphPath(path string) {
+	graphState.mu.Lock()
+	defer graphState.mu.Unlock()
+	graphState.stagedPath = path
+}
+
+// graphHandler returns the loaded scene graph, including operator notes.
+func graphHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	graphState.mu.RLock()
+	active := graphState.active
+	graphState.mu.RUnlock()
+
+	if active == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "scene graph not loaded"})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(active)
+}
+
+// graphAnalysisHandler returns the puzzle dependency structure of the loaded
+// graph: what each node waits on, the critical path and parallel branches.
+// Historical solve times, when Postgres has them, weight the critical path.
+func graphAnalysisHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	graphState.mu.RLock()
+	active := graphState.active
+	graphState.mu.RUnlock()
+
+	if active == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "scene graph not loaded"})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(orchestrator.AnalyzeSceneGraph(active, currentPaceBaseline()))
+}
+
+// GraphValidationResponse is returned by GET /graph/validation.
+type GraphValidationResponse struct {
+	Valid          bool                     `json:"valid"`
+	UnknownDevices []orchestrator.DeviceRef `json:"unknown_devices"`
+	Problems       []string                 `json:"problems"`
+}
+
+// graphValidationHandler cross-checks the loaded graph against devices.yaml
+// and lists every reference to a device it does not declare.
+func graphValidationHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	graphState.mu.RLock()
+	active := graphState.active
+	graphState.mu.RUnlock()
+
+	if active == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "scene graph not loaded"})
+		return
+	}
+	if devicesReloader == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "devices.yaml not loaded"})
+		return
+	}
+
+	resp := GraphValidationResponse{
+		UnknownDevices: devicesReloader.UnknownDeviceRefs(),
+		Problems:       []string{},
+	}
+	if resp.UnknownDevices == nil {
+		resp.UnknownDevices = []orchestrator.DeviceRef{}
+	}
+	for _, ref := range resp.UnknownDevices {
+		resp.Problems = append(resp.Problems, ref.Problem())
+	}
+	resp.Valid = len(resp.Problems) == 0
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// graphSimulateHandler estimates how long the loaded graph takes to play by
+// simulating games with puzzle solve times from expected_solve_sec
+// annotations or historical medians. Query: scene, runs, seed and target_min
+// (default: the configured game length).
+func graphSimulateHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	graphState.mu.RLock()
+	active := graphState.active
+	graphState.mu.RUnlock()
+
+	if active == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "scene graph not loaded"})
+		return
+	}
+
+	q := r.URL.Query()
+	opts := orchestrator.SimulationOptions{
+		SceneID: q.Get("scene"),
+		Target:  orchestrator.DefaultGameDuration,
+		History: currentDifficultyReport(),
+	}
+	if runtimeController != nil {
+		opts.Target = runtimeController.GameDuration()
+	}
+	for _, param := range []struct {
+		name string
+		set  func(n int64)
+	}{
+		{"runs", func(n int64) { opts.Runs = int(n) }},
+		{"seed", func(n int64) { opts.Seed = n }},
+		{"target_min", func(n int64) { opts.Target = time.Duration(n) * time.Minute }},
+	} {
+		raw := q.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid " + param.name + " parameter"})
+			return
+		}
+		param.set(n)
+	}
+
+	sim, err := orchestrator.SimulateDuration(active, opts)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, orchestrator.ErrNoSolveTimes) {
+			status = http.StatusUnprocessableEntity
+		}
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(sim)
+}
+
+// graphDiffHandler returns a semantic diff from the active graph to the staged graph.
+func graphDiffHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	graphState.mu.RLock()
+	active := graphState.active
+	stagedPath := graphState.stagedPath
+	graphState.mu.RUnlock()
+
+	if active == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "scene graph not loaded"})
+		return
+	}
+
+	if stagedPath == "" {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "no staged graph"})
+		return
+	}
+
+	staged, err := orchestrator.LoadSceneGraph(stagedPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "no staged graph"})
+			return
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(orchestrator.DiffSceneGraphs(active, staged))
+}
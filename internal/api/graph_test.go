@@ -0,0 +1,197 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+func TestGraphDiffHandler(t *testing.T) {
+	active, err := orchestrator.LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	SetSceneGraph(active)
+	defer SetSceneGraph(nil)
+
+	// No staged graph on disk yet
+	stagedPath := filepath.Join(t.TempDir(), "scene-graph.staged.json")
+	SetStagedGraphPath(stagedPath)
+	defer SetStagedGraphPath("")
+
+	w := httptest.NewRecorder()
+	graphDiffHandler(w, httptest.NewRequest("GET", "/admin/graph/diff", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 without staged graph, got %d", w.Code)
+	}
+
+	// Stage a copy with the entry node changed
+	staged, _ := orchestrator.LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	staged.Scenes[0].Entry = "scene_complete"
+	data, _ := json.Marshal(staged)
+	if err := os.WriteFile(stagedPath, data, 0600); err != nil {
+		t.Fatalf("failed to write staged graph: %v", err)
+	}
+
+	w = httptest.NewRecorder()
+	graphDiffHandler(w, httptest.NewRequest("GET", "/admin/graph/diff", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var diff orchestrator.GraphDiff
+	if err := json.NewDecoder(w.Body).Decode(&diff); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(diff.Scenes) != 1 || len(diff.Scenes[0].Fields) != 1 || diff.Scenes[0].Fields[0].Field != "entry" {
+		t.Errorf("expected single entry change, got %+v", diff.Scenes)
+	}
+}
+
+func TestGraphHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	graphHandler(w, httptest.NewRequest("GET", "/graph", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 without graph, got %d", w.Code)
+	}
+
+	sg, err := orchestrator.LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	SetSceneGraph(sg)
+	defer SetSceneGraph(nil)
+
+	w = httptest.NewRecorder()
+	graphHandler(w, httptest.NewRequest("GET", "/graph", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+
+	var got orchestrator.SceneGraph
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	var notes string
+	for _, node := range got.Scenes[0].Nodes {
+		if node.ID == "puzzle_scarab" {
+			notes = node.Notes
+		}
+	}
+	if notes == "" {
+		t.Error("expected runbook notes for puzzle_scarab")
+	}
+}
+
+func TestGraphAnalysisHandler(t *testing.T) {
+	sg, err := orchestrator.LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	SetSceneGraph(sg)
+	defer SetSceneGraph(nil)
+
+	w := httptest.NewRecorder()
+	graphAnalysisHandler(w, httptest.NewRequest("GET", "/graph/analysis", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var resp orchestrator.GraphAnalysis
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Scenes) != 1 || len(resp.Scenes[0].CriticalPath) == 0 {
+		t.Errorf("expected a critical path for scene_intro, got %+v", resp.Scenes)
+	}
+}
+
+func TestGraphSimulateHandler(t *testing.T) {
+	sg, err := orchestrator.LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	SetSceneGraph(sg)
+	defer SetSceneGraph(nil)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		graphSimulateHandler(w, httptest.NewRequest("GET", url, nil))
+		return w
+	}
+
+	if w := get("/graph/simulate"); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422 without solve times, got %d", w.Code)
+	}
+	if w := get("/graph/simulate?runs=abc"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for invalid runs, got %d", w.Code)
+	}
+
+	sg.Scenes[0].Nodes[1].Config["expected_solve_sec"] = 900.0
+	w := get("/graph/simulate?runs=200&seed=1&target_min=30")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var sim orchestrator.DurationSimulation
+	if err := json.NewDecoder(w.Body).Decode(&sim); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if sim.Runs != 200 || sim.TargetMs != 30*60*1000 || sim.OnTargetPct == nil || sim.MedianMs == 0 {
+		t.Errorf("unexpected simulation: %+v", sim)
+	}
+}
+
+func TestGraphValidationHandler(t *testing.T) {
+	clearTLSEnvServer(t)
+	active, err := orchestrator.LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	SetSceneGraph(active)
+	defer SetSceneGraph(nil)
+
+	get := func() (*httptest.ResponseRecorder, GraphValidationResponse) {
+		w := httptest.NewRecorder()
+		graphValidationHandler(w, httptest.NewRequest("GET", "/graph/validation", nil))
+		var resp GraphValidationResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w, resp
+	}
+
+	if w, _ := get(); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without devices.yaml, got %d", w.Code)
+	}
+
+	reloader := &fakeReloader{}
+	SetDevicesReloader(reloader)
+	defer SetDevicesReloader(nil)
+	if w, resp := get(); w.Code != http.StatusOK || !resp.Valid || len(resp.Problems) != 0 {
+		t.Errorf("expected a valid graph, got %d %+v", w.Code, resp)
+	}
+
+	reloader.unknown = []orchestrator.DeviceRef{{LogicalID: "foo", Where: "scene scene_intro node unlock params"}}
+	_, resp := get()
+	want := "graph references unknown device 'foo' (scene scene_intro node unlock params)"
+	if resp.Valid || len(resp.UnknownDevices) != 1 || len(resp.Problems) != 1 || resp.Problems[0] != want {
+		t.Errorf("expected the unknown device reported, got %+v", resp)
+	}
+
+	// /ready reports it without affecting readiness
+	SetOrchestratorReady(true)
+	SetMQTTState(true, false)
+	SetPostgresState(true, false)
+	w := httptest.NewRecorder()
+	readyHandler(w, httptest.NewRequest("GET", "/ready", nil))
+	var ready ReadinessResponse
+	if err := json.NewDecoder(w.Body).Decode(&ready); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	check := ready.Checks["device_refs"]
+	if !ready.Ready || check.Status != "warning" || len(check.Details) != 1 || check.Details[0] != want {
+		t.Errorf("expected a device_refs warning on a ready engine, got %+v", ready)
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+// Default and maximum handover window in hours.
+const (
+	defaultHandoverHours = 12
+	maxHandoverHours     = 168
+)
+
+// HandoverResponse is returned by /handover.
+type HandoverResponse struct {
+	*orchestrator.HandoverDigest
+	Hours         int           `json:"hours"`
+	UnackedAlerts []AlertRecord `json:"unacked_alerts"`
+}
+
+// AlertAckRequest acknowledges an alert.
+type AlertAckRequest struct {
+	AlertID string `json:"alert_id"`
+}
+
+// handoverHandler summarizes the last N hours for the next GM shift.
+func handoverHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	hours := defaultHandoverHours
+	if hoursStr := r.URL.Query().Get("hours"); hoursStr != "" {
+		h, err := strconv.Atoi(hoursStr)
+		if err != nil || h <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid hours parameter"})
+			return
+		}
+		hours = h
+	}
+	if hours > maxHandoverHours {
+		hours = maxHandoverHours
+	}
+
+	client := events.GetStore()
+	if client == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "postgres not available"})
+		return
+	}
+
+	rows, err := client.QueryByNames(orchestrator.HandoverEventNames, defaultReportEventLimit)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	until := time.Now()
+	since := until.Add(-time.Duration(hours) * time.Hour)
+
+	_ = json.NewEncoder(w).Encode(HandoverResponse{
+		HandoverDigest: orchestrator.BuildHandoverDigest(rows, since, until),
+		Hours:          hours,
+		UnackedAlerts:  UnackedAlerts(since),
+	})
+}
+
+// alertAckHandler marks an alert as acknowledged so it drops off the handover.
+func alertAckHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	var req AlertAckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "invalid JSON"})
+		return
+	}
+
+	if req.AlertID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "alert_id required"})
+		return
+	}
+
+	if !AckAlert(req.AlertID) {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "alert not found"})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(OperatorResponse{OK: true})
+}
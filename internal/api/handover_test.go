@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAlertAckAndRecovery(t *testing.T) {
+	alertMu.Lock()
+	savedLog, savedConfig := alertLog, *alertConfig
+	alertLog = nil
+	alertConfig.WebhookURL = ""
+	alertConfig.MQTTDisconnectDelay = 0
+	alertMonitorInitialized = true
+	lastKnownMQTTState = true
+	alertMu.Unlock()
+	defer func() {
+		alertMu.Lock()
+		alertLog, *alertConfig = savedLog, savedConfig
+		alertMu.Unlock()
+	}()
+
+	since := time.Now().Add(-time.Minute)
+
+	// Disconnect alerts are raised while the alert lock is held
+	CheckAndAlertMQTT(false)
+	CheckAndAlertMQTT(false)
+	alerts := UnackedAlerts(since)
+	if len(alerts) != 1 || alerts[0].Event != AlertMQTTDisconnected {
+		t.Fatalf("expected one mqtt alert, got %+v", alerts)
+	}
+
+	// Recovery resolves but does not acknowledge
+	CheckAndAlertMQTT(true)
+	alerts = UnackedAlerts(since)
+	if len(alerts) != 1 || !alerts[0].Resolved {
+		t.Fatalf("expected resolved but unacked alert, got %+v", alerts)
+	}
+
+	w := httptest.NewRecorder()
+	alertAckHandler(w, httptest.NewRequest("POST", "/operator/alerts/ack",
+		strings.NewReader(`{"alert_id":"`+alerts[0].AlertID+`"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if len(UnackedAlerts(since)) != 0 {
+		t.Error("expected no unacked alerts after ack")
+	}
+
+	w = httptest.NewRecorder()
+	alertAckHandler(w, httptest.NewRequest("POST", "/operator/alerts/ack", strings.NewReader(`{"alert_id":"missing"}`)))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown alert, got %d", w.Code)
+	}
+}
+
+func TestHandoverRequiresPostgres(t *testing.T) {
+	w := httptest.NewRecorder()
+	handoverHandler(w, httptest.NewRequest("GET", "/handover?hours=abc", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid hours, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handoverHandler(w, httptest.NewRequest("GET", "/handover", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 without postgres, got %d", w.Code)
+	}
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// OperatorHintRequest names the puzzle to give a hint for. Without an index
+// the puzzle's next hint is given.
+type OperatorHintRequest struct {
+	NodeID string `json:"node_id"`
+	Index  *int   `json:"index,omitempty"`
+}
+
+// HintResponse reports which hint was given.
+type HintResponse struct {
+	OK    bool   `json:"ok"`
+	Hint  int    `json:"hint"`
+	Error string `json:"error,omitempty"`
+}
+
+// operatorHintHandler sends a puzzle hint to the players (POST /operator/hint).
+// The runtime delivers it to the hint's device and emits puzzle.hint.
+func operatorHintHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(HintResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	var req OperatorHintRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(HintResponse{OK: false, Error: "invalid JSON"})
+		return
+	}
+
+	if req.NodeID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(HintResponse{OK: false, Error: "node_id required"})
+		return
+	}
+	index := -1
+	if req.Index != nil {
+		if *req.Index < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(HintResponse{OK: false, Error: "index must not be negative"})
+			return
+		}
+		index = *req.Index
+	}
+
+	if runtimeController == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(HintResponse{OK: false, Error: "runtime not available"})
+		return
+	}
+
+	if !runtimeController.IsGameActive() {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(HintResponse{OK: false, Error: "no active session"})
+		return
+	}
+
+	if !runtimeController.HasNode(req.NodeID) {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(HintResponse{OK: false, Error: "node not found"})
+		return
+	}
+
+	given, err := runtimeController.GiveHint(req.NodeID, index)
+	if err != nil {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(HintResponse{OK: false, Error: err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(HintResponse{OK: true, Hint: given})
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+func TestOperatorHintHandler(t *testing.T) {
+	rt := orchestrator.NewRuntime(&orchestrator.SceneGraph{
+		Version: 1,
+		Scenes: []orchestrator.Scene{{
+			ID:    "scene_hint",
+			Entry: "puzzle_door",
+			Nodes: []orchestrator.Node{
+				{ID: "puzzle_door", Type: "puzzle", Config: map[string]interface{}{
+					"subgraph": "door",
+					"hints": []interface{}{
+						map[string]interface{}{"text": "Try the handle", "device_id": "hint_screen", "signal": "show_hint"},
+					},
+				}},
+			},
+			Subgraphs: []orchestrator.Subgraph{{
+				ID:    "door",
+				Entry: "wait",
+				Nodes: []orchestrator.Node{
+					{ID: "wait", Type: "decision"},
+					{ID: "done", Type: "terminal"},
+				},
+				Edges: []orchestrator.Edge{{From: "wait", To: "done", Condition: "event == 'device.input'"}},
+			}},
+		}},
+	})
+	SetRuntimeController(rt)
+	defer SetRuntimeController(nil)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		operatorHintHandler(w, httptest.NewRequest("POST", "/operator/hint", strings.NewReader(body)))
+		return w
+	}
+
+	if w := post(`{"node_id":"puzzle_door"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 without active session, got %d", w.Code)
+	}
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+
+	if w := post(`{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 without node_id, got %d", w.Code)
+	}
+	if w := post(`{"node_id":"puzzle_door","index":-1}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for a negative index, got %d", w.Code)
+	}
+	if w := post(`{"node_id":"missing"}`); w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown node, got %d", w.Code)
+	}
+
+	w := post(`{"node_id":"puzzle_door"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp HintResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil || !resp.OK || resp.Hint != 0 {
+		t.Errorf("unexpected response: %+v (%v)", resp, err)
+	}
+
+	if w := post(`{"node_id":"puzzle_door"}`); w.Code != http.StatusConflict {
+		t.Errorf("expected status 409 once every hint was given, got %d", w.Code)
+	}
+}
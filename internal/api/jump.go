@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// OperatorJumpRequest names the node to jump to and, to leave the active
+// scene, the scene it belongs to. A scene without a node starts at its entry.
+type OperatorJumpRequest struct {
+	NodeID  string `json:"node_id,omitempty"`
+	SceneID string `json:"scene_id,omitempty"`
+}
+
+// operatorJumpHandler moves the game straight to a node, overriding the
+// unfinished nodes leading to it (POST /operator/jump).
+func operatorJumpHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	var req OperatorJumpRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "invalid JSON"})
+		return
+	}
+
+	if req.NodeID == "" && req.SceneID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "node_id or scene_id required"})
+		return
+	}
+
+	if runtimeController == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "runtime not available"})
+		return
+	}
+
+	if !runtimeController.IsGameActive() {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "no active session"})
+		return
+	}
+
+	if req.SceneID == "" && !runtimeController.HasNode(req.NodeID) {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "node not found"})
+		return
+	}
+
+	if !requireConfirm(w, r, "jump:"+req.SceneID+"/"+req.NodeID) {
+		return
+	}
+
+	fields := map[string]interface{}{"node_id": req.NodeID}
+	if req.SceneID != "" {
+		fields["scene_id"] = req.SceneID
+	}
+	events.Emit("info", "operator.jump", "", fields)
+
+	if err := runtimeController.JumpTo(req.SceneID, req.NodeID); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(OperatorResponse{OK: true})
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+func TestOperatorJumpHandler(t *testing.T) {
+	sg, err := orchestrator.LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	rt := orchestrator.NewRuntime(sg)
+	SetRuntimeController(rt)
+	defer SetRuntimeController(nil)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		operatorJumpHandler(w, httptest.NewRequest("POST", "/operator/jump", strings.NewReader(body)))
+		return w
+	}
+
+	if w := post(`{"node_id":"puzzle_scarab"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 without active session, got %d", w.Code)
+	}
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+
+	if w := post(`{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 without a target, got %d", w.Code)
+	}
+	if w := post(`{"node_id":"missing"}`); w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown node, got %d", w.Code)
+	}
+	if w := post(`{"scene_id":"scene_missing"}`); w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown scene, got %d", w.Code)
+	}
+
+	events.Clear()
+	if w := post(`{"node_id":"scene_complete"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var jumped bool
+	for _, e := range events.Snapshot() {
+		if e.Name == "operator.jump" && e.Fields["node_id"] == "scene_complete" {
+			jumped = true
+		}
+	}
+	if !jumped {
+		t.Error("expected operator.jump event")
+	}
+}
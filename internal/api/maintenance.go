@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+// MaintenanceController runs device test routines while the room is closed to games.
+type MaintenanceController interface {
+	Start() error
+	Stop() error
+	Active() bool
+	Report() *orchestrator.MaintenanceReport
+}
+
+var maintenanceController MaintenanceController
+
+// SetMaintenanceController sets the runner used by maintenance endpoints.
+func SetMaintenanceController(mc MaintenanceController) {
+	maintenanceController = mc
+}
+
+// MaintenanceStatusResponse is returned by /maintenance/report.
+type MaintenanceStatusResponse struct {
+	Active bool                            `json:"active"`
+	Report *orchestrator.MaintenanceReport `json:"report,omitempty"`
+}
+
+func maintenanceStartHandler(w http.ResponseWriter, r *http.Request) {
+	maintenanceActionHandler(w, r, func() error { return maintenanceController.Start() })
+}
+
+func maintenanceStopHandler(w http.ResponseWriter, r *http.Request) {
+	maintenanceActionHandler(w, r, func() error { return maintenanceController.Stop() })
+}
+
+// maintenanceActionHandler applies a maintenance mode transition.
+func maintenanceActionHandler(w http.ResponseWriter, r *http.Request, apply func() error) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	if maintenanceController == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "maintenance not available"})
+		return
+	}
+
+	if err := apply(); err != nil {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(GameResponse{OK: true})
+}
+
+// maintenanceReportHandler returns maintenance status and the latest report.
+func maintenanceReportHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	if maintenanceController == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "maintenance not available"})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(MaintenanceStatusResponse{
+		Active: maintenanceController.Active(),
+		Report: maintenanceController.Report(),
+	})
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// maxOperatorMessageLen caps free text sent to in-room displays.
+const maxOperatorMessageLen = 500
+
+// MessageRelay delivers operator free text to in-room displays.
+type MessageRelay interface {
+	SendDisplayMessage(text, deviceID string) error
+}
+
+var messageRelay MessageRelay
+
+// SetMessageRelay sets the relay used by /operator/message.
+func SetMessageRelay(mr MessageRelay) {
+	messageRelay = mr
+}
+
+// OperatorMessageRequest is a free-text message for players.
+// An empty DeviceID sends the message to every display.
+type OperatorMessageRequest struct {
+	Text     string `json:"text"`
+	DeviceID string `json:"device_id,omitempty"`
+}
+
+// operatorMessageHandler records an operator message and relays it to in-room displays.
+func operatorMessageHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	var req OperatorMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "invalid JSON"})
+		return
+	}
+
+	req.Text = strings.TrimSpace(req.Text)
+	if req.Text == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "text required"})
+		return
+	}
+	if len(req.Text) > maxOperatorMessageLen {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "text too long"})
+		return
+	}
+
+	if messageRelay == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "display relay not available"})
+		return
+	}
+
+	// Emit operator.message event (registry-approved)
+	fields := map[string]interface{}{"text": req.Text}
+	if req.DeviceID != "" {
+		fields["device_id"] = req.DeviceID
+	}
+	events.Emit("info", "operator.message", "", fields)
+
+	// Delivery failures are also reported as device.error
+	if err := messageRelay.SendDisplayMessage(req.Text, req.DeviceID); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(OperatorResponse{OK: true})
+}
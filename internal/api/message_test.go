@@ -0,0 +1,62 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// fakeRelay records relayed messages.
+type fakeRelay struct {
+	sent []string
+	err  error
+}
+
+func (f *fakeRelay) SendDisplayMessage(text, deviceID string) error {
+	f.sent = append(f.sent, deviceID+":"+text)
+	return f.err
+}
+
+func TestOperatorMessageHandler(t *testing.T) {
+	events.Clear()
+	relay := &fakeRelay{}
+	SetMessageRelay(relay)
+	defer SetMessageRelay(nil)
+
+	w := httptest.NewRecorder()
+	operatorMessageHandler(w, httptest.NewRequest("POST", "/operator/message", strings.NewReader(`{"text": "  "}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for empty text, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	body := `{"text": "Please step back from the altar", "device_id": "crypt_screen"}`
+	operatorMessageHandler(w, httptest.NewRequest("POST", "/operator/message", strings.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(relay.sent) != 1 || relay.sent[0] != "crypt_screen:Please step back from the altar" {
+		t.Errorf("expected message relayed to crypt_screen, got %v", relay.sent)
+	}
+
+	var recorded bool
+	for _, e := range events.Snapshot() {
+		if e.Name == "operator.message" && e.Fields["text"] == "Please step back from the altar" {
+			recorded = true
+		}
+	}
+	if !recorded {
+		t.Error("expected operator.message event")
+	}
+
+	relay.err = fmt.Errorf("MQTT client not connected")
+	w = httptest.NewRecorder()
+	operatorMessageHandler(w, httptest.NewRequest("POST", "/operator/message", strings.NewReader(`{"text": "Five minutes left"}`)))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500 on delivery failure, got %d", w.Code)
+	}
+}
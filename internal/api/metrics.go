@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"runtime"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/mqtt"
 	"github.com/AaronLay10/SentientEngine/internal/version"
 )
 
@@ -22,6 +25,8 @@ type MetricsState struct {
 	startTime                time.Time
 	roomName                 string
 	backupLastSuccessTimeSec int64 // Unix timestamp, -1 if unknown
+	deviceRegistry           *mqtt.DeviceRegistry
+	power                    *mqtt.PowerMonitor
 }
 
 // InitMetrics initializes the metrics system. Must be called at startup.
@@ -53,6 +58,20 @@ func SetBackupLastSuccess(ts time.Time) {
 	metricsState.backupLastSuccessTimeSec = ts.Unix()
 }
 
+// SetDeviceRegistry sets the registry whose size /metrics reports.
+func SetDeviceRegistry(reg *mqtt.DeviceRegistry) {
+	metricsState.mu.Lock()
+	defer metricsState.mu.Unlock()
+	metricsState.deviceRegistry = reg
+}
+
+// SetPowerMonitor sets the smart plug readings /metrics reports.
+func SetPowerMonitor(power *mqtt.PowerMonitor) {
+	metricsState.mu.Lock()
+	defer metricsState.mu.Unlock()
+	metricsState.power = power
+}
+
 // metricsHandler returns Prometheus-compatible metrics in text format.
 func metricsHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -65,6 +84,8 @@ func metricsHandler(w http.ResponseWriter, r *http.Request) {
 	startTime := metricsState.startTime
 	roomName := metricsState.roomName
 	backupLastSuccess := metricsState.backupLastSuccessTimeSec
+	registry := metricsState.deviceRegistry
+	power := metricsState.power
 	metricsState.mu.RUnlock()
 
 	uptime := time.Since(startTime).Seconds()
@@ -77,6 +98,20 @@ func metricsHandler(w http.ResponseWriter, r *http.Request) {
 	readiness.mu.RUnlock()
 
 	wsClients := events.SubscriberCount()
+	wsDropped := events.DroppedCount()
+	wsEvicted := events.EvictedCount()
+	wsQueued := events.QueuedCount()
+
+	pgQueued := events.PersistQueueLen()
+	pgSpilled := events.SpilledEvents()
+
+	bufferEvents, bufferBytes, bufferShed := events.BufferStats()
+	registeredDevices, registryShed := 0, uint64(0)
+	if registry != nil {
+		registeredDevices, registryShed = registry.Len(), registry.ShedCount()
+	}
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
 
 	// Determine room active (1 if orchestrator ready, 0 otherwise)
 	roomActive := 0
@@ -137,11 +172,87 @@ func metricsHandler(w http.ResponseWriter, r *http.Request) {
 	writeMetric("sentient_postgres_connected", "gauge",
 		"Whether PostgreSQL is connected (1) or not (0)", postgresConnectedVal, labels)
 
+	writeMetric("sentient_postgres_queued_events", "gauge",
+		"Number of events waiting to be written to PostgreSQL", pgQueued, labels)
+	writeMetric("sentient_postgres_spilled_events", "gauge",
+		"Number of events spilled to disk waiting to be replayed into PostgreSQL", pgSpilled, labels)
+
 	// WebSocket clients
 	writeMetric("sentient_ws_clients", "gauge",
 		"Number of active WebSocket client connections", wsClients, labels)
 
+	// WebSocket backpressure
+	writeMetric("sentient_ws_dropped_events_total", "counter",
+		"Total number of events dropped for WebSocket clients that could not keep up", wsDropped, labels)
+	writeMetric("sentient_ws_evictions_total", "counter",
+		"Total number of WebSocket clients disconnected for falling too far behind", wsEvicted, labels)
+
+	writeMetric("sentient_ws_queued_events", "gauge",
+		"Number of events waiting in WebSocket client queues", wsQueued, labels)
+
+	// Memory budget
+	writeMetric("sentient_event_buffer_events", "gauge",
+		"Number of events kept in the in-memory event buffer", bufferEvents, labels)
+	writeMetric("sentient_event_buffer_bytes", "gauge",
+		"Approximate memory held by the in-memory event buffer", bufferBytes, labels)
+	writeMetric("sentient_registered_devices", "gauge",
+		"Number of devices in the device registry", registeredDevices, labels)
+	writeMetric("sentient_heap_bytes", "gauge",
+		"Bytes of allocated heap objects", mem.HeapAlloc, labels)
+	fmt.Fprintf(w, "# HELP %s %s\n", "sentient_memory_shed_total", "Total number of items dropped to stay within a memory cap by component")
+	fmt.Fprintf(w, "# TYPE %s %s\n", "sentient_memory_shed_total", "counter")
+	fmt.Fprintf(w, "sentient_memory_shed_total{%s,component=\"event_buffer\"} %d\n", labels, bufferShed)
+	fmt.Fprintf(w, "sentient_memory_shed_total{%s,component=\"device_registry\"} %d\n", labels, registryShed)
+
 	// Backup last success timestamp
 	writeMetric("sentient_backup_last_success_timestamp", "gauge",
 		"Unix timestamp of last successful backup (-1 if unknown)", backupLastSuccess, labels)
+
+	// Smart plug readings, one series per device with power telemetry
+	if power != nil {
+		readings := power.Readings()
+		ids := make([]string, 0, len(readings))
+		for id := range readings {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		fmt.Fprintf(w, "# HELP %s %s\n", "sentient_device_power_watts", "Latest power draw reported for a device")
+		fmt.Fprintf(w, "# TYPE %s %s\n", "sentient_device_power_watts", "gauge")
+		for _, id := range ids {
+			fmt.Fprintf(w, "sentient_device_power_watts{%s,device=\"%s\"} %v\n", labels, id, readings[id].Watts)
+		}
+		fmt.Fprintf(w, "# HELP %s %s\n", "sentient_device_energy_wh_total", "Energy used by a device since startup in watt-hours")
+		fmt.Fprintf(w, "# TYPE %s %s\n", "sentient_device_energy_wh_total", "counter")
+		for _, id := range ids {
+			fmt.Fprintf(w, "sentient_device_energy_wh_total{%s,device=\"%s\"} %v\n", labels, id, readings[id].EnergyWh)
+		}
+	}
+
+	// Device health, one series per device in devices.yaml or registered
+	if deviceInventory != nil {
+		devices := deviceInventory.Devices()
+		fmt.Fprintf(w, "# HELP %s %s\n", "sentient_device_connected", "Whether a device's controller is connected (1) or not (0)")
+		fmt.Fprintf(w, "# TYPE %s %s\n", "sentient_device_connected", "gauge")
+		for _, dev := range devices {
+			connected := 0
+			if dev.Connected {
+				connected = 1
+			}
+			fmt.Fprintf(w, "sentient_device_connected{%s,device=\"%s\",required=\"%t\"} %d\n", labels, dev.LogicalID, dev.Required, connected)
+		}
+		fmt.Fprintf(w, "# HELP %s %s\n", "sentient_device_last_seen_timestamp", "Unix timestamp of the last heartbeat from a device's controller")
+		fmt.Fprintf(w, "# TYPE %s %s\n", "sentient_device_last_seen_timestamp", "gauge")
+		for _, dev := range devices {
+			if dev.LastSeen != nil {
+				fmt.Fprintf(w, "sentient_device_last_seen_timestamp{%s,device=\"%s\"} %d\n", labels, dev.LogicalID, dev.LastSeen.Unix())
+			}
+		}
+	}
+
+	// Warnings by type, one series per type seen
+	fmt.Fprintf(w, "# HELP %s %s\n", "sentient_warnings_total", "Total number of warnings since startup by type")
+	fmt.Fprintf(w, "# TYPE %s %s\n", "sentient_warnings_total", "counter")
+	for _, wc := range events.WarningSummary() {
+		fmt.Fprintf(w, "sentient_warnings_total{%s,type=\"%s\"} %d\n", labels, wc.Type, wc.Count)
+	}
 }
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+// ModeResponse is returned by GET /mode.
+type ModeResponse struct {
+	Mode    orchestrator.RunMode     `json:"mode"`
+	Profile orchestrator.ModeProfile `json:"profile"`
+}
+
+// ModeRequest switches the run mode (show, rehearsal or maintenance).
+type ModeRequest struct {
+	Mode string `json:"mode"`
+}
+
+// SyntheticInputRequest is a device input typed in by an operator.
+type SyntheticInputRequest struct {
+	LogicalID string      `json:"logical_id"`
+	Payload   interface{} `json:"payload"`
+}
+
+// modeHandler returns the run mode and what it allows (GET /mode).
+func modeHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	if runtimeController == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "runtime not available"})
+		return
+	}
+
+	mode := runtimeController.Mode()
+	_ = json.NewEncoder(w).Encode(ModeResponse{Mode: mode, Profile: mode.Profile()})
+}
+
+// setModeHandler switches the run mode between games (POST /admin/mode).
+// The runtime emits room.mode_changed.
+func setModeHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	var req ModeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "invalid JSON"})
+		return
+	}
+	mode, err := orchestrator.ParseRunMode(req.Mode)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: err.Error()})
+		return
+	}
+
+	if runtimeController == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "runtime not available"})
+		return
+	}
+
+	// Routines return to the previous mode themselves when stopped
+	if maintenanceController != nil && maintenanceController.Active() {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "maintenance routines running"})
+		return
+	}
+
+	if err := runtimeController.SetMode(mode, requestUser(r)); err != nil {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(GameResponse{OK: true})
+}
+
+// gameInputHandler injects a synthetic device input (POST /game/input), for
+// rehearsing without props. Only modes that allow synthetic input accept it.
+func gameInputHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	var req SyntheticInputRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "invalid JSON"})
+		return
+	}
+
+	if req.LogicalID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "logical_id required"})
+		return
+	}
+
+	if runtimeController == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "runtime not available"})
+		return
+	}
+
+	if mode := runtimeController.Mode(); !mode.Profile().SyntheticInput {
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "synthetic input not allowed in " + string(mode) + " mode"})
+		return
+	}
+
+	if !runtimeController.IsGameActive() {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "no active session"})
+		return
+	}
+
+	fields := map[string]interface{}{
+		"logical_id": req.LogicalID,
+		"payload":    req.Payload,
+		"synthetic":  true,
+	}
+
+	// Emit device.input event (registry-approved) and route it like controller input
+	events.Emit("info", "device.input", "", fields)
+	runtimeController.InjectEvent("device.input", fields)
+
+	_ = json.NewEncoder(w).Encode(GameResponse{OK: true})
+}
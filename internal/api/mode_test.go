@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+func TestModeHandlers(t *testing.T) {
+	sg, err := orchestrator.LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	rt := orchestrator.NewRuntime(sg)
+	SetRuntimeController(rt)
+	defer SetRuntimeController(nil)
+
+	setMode := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		setModeHandler(w, httptest.NewRequest("POST", "/admin/mode", strings.NewReader(body)))
+		return w
+	}
+	input := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		body := `{"logical_id":"scarab_sensor","payload":{"placed":true}}`
+		gameInputHandler(w, httptest.NewRequest("POST", "/game/input", strings.NewReader(body)))
+		return w
+	}
+
+	if w := setMode(`{"mode":"party"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for unknown mode, got %d", w.Code)
+	}
+	if w := input(); w.Code != http.StatusForbidden {
+		t.Errorf("expected status 403 for synthetic input in show mode, got %d", w.Code)
+	}
+
+	if w := setMode(`{"mode":"rehearsal"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	w := httptest.NewRecorder()
+	modeHandler(w, httptest.NewRequest("GET", "/mode", nil))
+	if !strings.Contains(w.Body.String(), `"mode":"rehearsal"`) || !strings.Contains(w.Body.String(), `"synthetic_input":true`) {
+		t.Errorf("unexpected mode response: %s", w.Body.String())
+	}
+
+	if w := input(); w.Code != http.StatusConflict {
+		t.Errorf("expected status 409 without active session, got %d", w.Code)
+	}
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+
+	events.Clear()
+	if w := input(); w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var synthetic bool
+	for _, e := range events.Snapshot() {
+		if e.Name == "device.input" && e.Fields["synthetic"] == true {
+			synthetic = true
+		}
+	}
+	if !synthetic {
+		t.Error("expected device.input marked synthetic")
+	}
+
+	if w := setMode(`{"mode":"show"}`); w.Code != http.StatusConflict {
+		t.Errorf("expected status 409 during a game, got %d", w.Code)
+	}
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+// maxNodeSummaryLen bounds the config summary shown per node.
+const maxNodeSummaryLen = 160
+
+// NodeInfo is one scene graph node with its live state.
+type NodeInfo struct {
+	SceneID    string                        `json:"scene_id"`
+	ID         string                        `json:"id"`
+	Type       string                        `json:"type"`
+	State      orchestrator.NodeState        `json:"state"`
+	Resolution orchestrator.PuzzleResolution `json:"resolution,omitempty"`
+	Summary    string                        `json:"summary,omitempty"`
+	HasNotes   bool                          `json:"has_notes,omitempty"`
+}
+
+// NodesResponse is returned by GET /nodes.
+type NodesResponse struct {
+	ActiveSceneID string     `json:"active_scene_id,omitempty"`
+	Nodes         []NodeInfo `json:"nodes"`
+}
+
+// nodesHandler lists scene graph nodes with their live state, so the operator
+// UI can offer per-node controls. ?scene= limits the list to one scene.
+// Nodes outside the active scene are reported idle.
+func nodesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	graphState.mu.RLock()
+	active := graphState.active
+	graphState.mu.RUnlock()
+
+	if active == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "scene graph not loaded"})
+		return
+	}
+
+	var snap orchestrator.RuntimeSnapshot
+	if runtimeController != nil {
+		snap = runtimeController.Snapshot()
+	}
+	states := make(map[string]orchestrator.NodeState, len(snap.Nodes))
+	for _, n := range snap.Nodes {
+		states[n.ID] = n.State
+	}
+	resolutions := make(map[string]orchestrator.PuzzleResolution, len(snap.Puzzles))
+	for _, p := range snap.Puzzles {
+		resolutions[p.NodeID] = p.Resolution
+	}
+
+	filter := r.URL.Query().Get("scene")
+	resp := NodesResponse{ActiveSceneID: snap.SceneID, Nodes: []NodeInfo{}}
+	found := filter == ""
+	for _, scene := range active.Scenes {
+		if filter != "" && scene.ID != filter {
+			continue
+		}
+		found = true
+		live := scene.ID == snap.SceneID
+		for _, node := range scene.Nodes {
+			info := NodeInfo{
+				SceneID:  scene.ID,
+				ID:       node.ID,
+				Type:     node.Type,
+				State:    orchestrator.NodeStateIdle,
+				Summary:  nodeSummary(node.Config),
+				HasNotes: node.Notes != "",
+			}
+			if live {
+				if state, ok := states[node.ID]; ok {
+					info.State = state
+				}
+				info.Resolution = resolutions[node.ID]
+			}
+			resp.Nodes = append(resp.Nodes, info)
+		}
+	}
+
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "scene not found"})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// nodeSummary renders a node's scalar config as sorted key=value pairs, one
+// level of nesting deep (e.g. "action=device.command, params.signal=on").
+func nodeSummary(config map[string]interface{}) string {
+	var parts []string
+	var add func(prefix string, m map[string]interface{}, depth int)
+	add = func(prefix string, m map[string]interface{}, depth int) {
+		for k, v := range m {
+			switch val := v.(type) {
+			case map[string]interface{}:
+				if depth == 0 {
+					add(prefix+k+".", val, depth+1)
+				}
+			case []interface{}:
+				items := make([]string, 0, len(val))
+				for _, item := range val {
+					switch item.(type) {
+					case map[string]interface{}, []interface{}:
+						continue
+					}
+					items = append(items, fmt.Sprint(item))
+				}
+				if len(items) == len(val) {
+					parts = append(parts, prefix+k+"=["+strings.Join(items, " ")+"]")
+				}
+			case nil:
+			default:
+				parts = append(parts, prefix+k+"="+fmt.Sprint(val))
+			}
+		}
+	}
+	add("", config, 0)
+	sort.Strings(parts)
+
+	summary := strings.Join(parts, ", ")
+	if len(summary) > maxNodeSummaryLen {
+		summary = summary[:maxNodeSummaryLen-3] + "..."
+	}
+	return summary
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+func TestNodesHandler(t *testing.T) {
+	sg, err := orchestrator.LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	SetSceneGraph(sg)
+	defer SetSceneGraph(nil)
+	rt := orchestrator.NewRuntime(sg)
+	SetRuntimeController(rt)
+	defer SetRuntimeController(nil)
+
+	get := func(url string) (int, NodesResponse) {
+		w := httptest.NewRecorder()
+		nodesHandler(w, httptest.NewRequest("GET", url, nil))
+		var resp NodesResponse
+		_ = json.NewDecoder(w.Body).Decode(&resp)
+		return w.Code, resp
+	}
+
+	// Without a game every node is idle
+	code, resp := get("/nodes")
+	if code != http.StatusOK || resp.ActiveSceneID != "" || len(resp.Nodes) == 0 {
+		t.Fatalf("unexpected idle response %d: %+v", code, resp)
+	}
+	for _, n := range resp.Nodes {
+		if n.State != orchestrator.NodeStateIdle {
+			t.Errorf("expected %s idle, got %s", n.ID, n.State)
+		}
+	}
+
+	if err := rt.StartGame("scene_intro"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+	if err := rt.OverrideNode("puzzle_scarab"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+
+	code, resp = get("/nodes?scene=scene_intro")
+	if code != http.StatusOK || resp.ActiveSceneID != "scene_intro" {
+		t.Fatalf("unexpected response %d: %+v", code, resp)
+	}
+	nodes := make(map[string]NodeInfo)
+	for _, n := range resp.Nodes {
+		nodes[n.ID] = n
+	}
+	scarab := nodes["puzzle_scarab"]
+	if scarab.State != orchestrator.NodeStateOverridden || scarab.Resolution != orchestrator.PuzzleOverridden || !scarab.HasNotes {
+		t.Errorf("unexpected scarab node: %+v", scarab)
+	}
+	if scarab.Summary != "required=true, subgraph=puzzle_scarab_v1" {
+		t.Errorf("unexpected summary: %q", scarab.Summary)
+	}
+	if nodes["puzzle_tiles"].State != orchestrator.NodeStateActive || nodes["puzzle_tiles"].Resolution != orchestrator.PuzzleUnresolved {
+		t.Errorf("unexpected tiles node: %+v", nodes["puzzle_tiles"])
+	}
+
+	if code, _ := get("/nodes?scene=missing"); code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown scene, got %d", code)
+	}
+}
@@ -0,0 +1,475 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+	"github.com/AaronLay10/SentientEngine/internal/version"
+)
+
+// apiAccess is who may call an operation.
+type apiAccess int
+
+const (
+	accessPublic apiAccess = iota
+	accessAnyRole
+	accessAdmin
+	accessTriggerToken    // the token in the path is the credential
+	accessFederationToken // X-Sentient-Federation-Token header
+)
+
+// apiParam is a query or path parameter.
+type apiParam struct {
+	Name        string
+	In          string // "query" or "path"
+	Type        string // "string", "integer", "boolean"
+	Description string
+}
+
+// apiOperation documents one method on one route for /openapi.json.
+// Request and Response are zero values of the JSON body types.
+type apiOperation struct {
+	Path        string
+	Method      string
+	Summary     string
+	Access      apiAccess
+	Params      []apiParam
+	Request     interface{}
+	Response    interface{}
+	ContentType string // 200 content type when not JSON
+	Confirm     bool   // destructive: may answer 428 until confirmed
+	WebSocket   bool
+}
+
+// apiOperations lists every operation served by NewServer.
+// Keep in sync with the routes registered there.
+var apiOperations = []apiOperation{
+	// Public
+	{Path: "/health", Method: "GET", Summary: "Liveness probe", Response: HealthResponse{}},
+	{Path: "/ready", Method: "GET", Summary: "Readiness probe with dependency checks", Response: ReadinessResponse{}},
+	{Path: "/metrics", Method: "GET", Summary: "Prometheus metrics", ContentType: "text/plain"},
+	{Path: "/openapi.json", Method: "GET", Summary: "This document", ContentType: "application/json"},
+	{Path: "/capabilities", Method: "GET", Summary: "Optional features enabled in this deployment", Response: CapabilitiesResponse{}},
+	{Path: "/events", Method: "GET", Summary: "Recent events from the in-memory buffer, oldest first", Params: []apiParam{
+		{Name: "after", In: "query", Type: "integer", Description: "Only events with a higher seq"},
+		{Name: "limit", In: "query", Type: "integer", Description: "Page size: the oldest events after seq, else the newest"},
+	}, Response: []events.Event{}},
+	{Path: "/events/db", Method: "GET", Summary: "Stored events, newest first; X-Next-Cursor holds the next page cursor", Params: []apiParam{
+		{Name: "limit", In: "query", Type: "integer", Description: "Page size, default 200, max 1000"},
+		{Name: "offset", In: "query", Type: "integer", Description: "Rows to skip"},
+		{Name: "cursor", In: "query", Type: "integer", Description: "X-Next-Cursor from the previous page"},
+		{Name: "event", In: "query", Type: "string", Description: "Event name prefix"},
+		{Name: "level", In: "query", Type: "string"},
+		{Name: "session_id", In: "query", Type: "string"},
+		{Name: "node_id", In: "query", Type: "string"},
+		{Name: "since", In: "query", Type: "string", Description: "RFC 3339, inclusive"},
+		{Name: "until", In: "query", Type: "string", Description: "RFC 3339, exclusive"},
+	}, Response: []storage.EventRow{}},
+	{Path: "/events/{seq}", Method: "GET", Summary: "One event by sequence number", Params: []apiParam{
+		{Name: "seq", In: "path", Type: "integer"},
+	}, Response: events.Event{}},
+	{Path: "/trigger/{token}", Method: "POST", Summary: "Inject the device input bound to a trigger token", Access: accessTriggerToken, Params: []apiParam{
+		{Name: "token", In: "path", Type: "string"},
+	}, Response: OperatorResponse{}},
+	{Path: "/federation/events", Method: "POST", Summary: "Receive an event from a linked room", Access: accessFederationToken,
+		Request: orchestrator.FederatedEvent{}, Response: OperatorResponse{}},
+	{Path: "/overlay", Method: "GET", Summary: "Spoiler-free stream overlay page", ContentType: "text/html"},
+	{Path: "/ws/overlay", Method: "GET", Summary: "Overlay frames every second", Response: OverlayFrame{}, WebSocket: true},
+
+	// Admin or operator
+	{Path: "/ui", Method: "GET", Summary: "Operator UI", Access: accessAnyRole, ContentType: "text/html"},
+	{Path: "/ws/events", Method: "GET", Summary: "Live event stream", Access: accessAnyRole, Params: []apiParam{
+		{Name: "events", In: "query", Type: "string", Description: "Comma-separated event names or globs, e.g. puzzle.*"},
+		{Name: "level", In: "query", Type: "string", Description: "Minimum level: info, warning or error"},
+		{Name: "sessions", In: "query", Type: "string", Description: "Recent events replayed on connect: current (default) or all sessions"},
+	}, Response: events.Event{}, WebSocket: true},
+	{Path: "/operator/override", Method: "POST", Summary: "Mark a puzzle solved", Access: accessAnyRole,
+		Request: OperatorRequest{}, Response: OperatorResponse{}},
+	{Path: "/operator/reset", Method: "POST", Summary: "Reset a puzzle", Access: accessAnyRole, Confirm: true,
+		Request: OperatorRequest{}, Response: OperatorResponse{}},
+	{Path: "/operator/reset-node", Method: "POST", Summary: "Rewind the scene to a node", Access: accessAnyRole, Confirm: true,
+		Request: OperatorRequest{}, Response: OperatorResponse{}},
+	{Path: "/operator/reset-to-checkpoint", Method: "POST", Summary: "Restore a named checkpoint", Access: accessAnyRole, Confirm: true,
+		Request: CheckpointResetRequest{}, Response: OperatorResponse{}},
+	{Path: "/operator/jump", Method: "POST", Summary: "Jump to a node, overriding the nodes before it", Access: accessAnyRole, Confirm: true,
+		Request: OperatorJumpRequest{}, Response: OperatorResponse{}},
+	{Path: "/operator/approve", Method: "GET", Summary: "Approval nodes waiting for the operator", Access: accessAnyRole, Response: ApprovalsResponse{}},
+	{Path: "/operator/approve", Method: "POST", Summary: "Approve a waiting approval node", Access: accessAnyRole,
+		Request: OperatorRequest{}, Response: OperatorResponse{}},
+	{Path: "/operator/hint", Method: "POST", Summary: "Give a puzzle hint", Access: accessAnyRole,
+		Request: OperatorHintRequest{}, Response: HintResponse{}},
+	{Path: "/operator/undo", Method: "POST", Summary: "Undo the last operator action", Access: accessAnyRole, Response: UndoResponse{}},
+	{Path: "/operator/message", Method: "POST", Summary: "Send a message to the players", Access: accessAnyRole,
+		Request: OperatorMessageRequest{}, Response: OperatorResponse{}},
+	{Path: "/operator/clock/pause", Method: "POST", Summary: "Pause the game clock", Access: accessAnyRole, Response: OperatorResponse{}},
+	{Path: "/operator/clock/resume", Method: "POST", Summary: "Resume the game clock", Access: accessAnyRole, Response: OperatorResponse{}},
+	{Path: "/operator/clock/adjust", Method: "POST", Summary: "Add or remove time", Access: accessAnyRole,
+		Request: ClockAdjustRequest{}, Response: OperatorResponse{}},
+	{Path: "/operator/alerts/ack", Method: "POST", Summary: "Acknowledge an alert", Access: accessAnyRole,
+		Request: AlertAckRequest{}, Response: OperatorResponse{}},
+	{Path: "/reports/difficulty", Method: "GET", Summary: "Puzzle difficulty from session history", Access: accessAnyRole, Params: []apiParam{
+		{Name: "limit", In: "query", Type: "integer", Description: "Events to analyze"},
+	}, Response: orchestrator.DifficultyReport{}},
+	{Path: "/sessions", Method: "GET", Summary: "Recorded game sessions, newest first", Access: accessAnyRole, Params: []apiParam{
+		{Name: "limit", In: "query", Type: "integer", Description: "Page size, default 50, max 1000"},
+		{Name: "offset", In: "query", Type: "integer", Description: "Rows to skip"},
+	}, Response: SessionsResponse{}},
+	{Path: "/sessions/{id}", Method: "GET", Summary: "One game session", Access: accessAnyRole, Params: []apiParam{
+		{Name: "id", In: "path", Type: "string"},
+	}, Response: storage.SessionRow{}},
+	{Path: "/sessions/{id}/events", Method: "GET", Summary: "Stored events of one session; takes the /events/db filters", Access: accessAnyRole, Params: []apiParam{
+		{Name: "id", In: "path", Type: "string"},
+		{Name: "limit", In: "query", Type: "integer", Description: "Page size, default 200, max 1000"},
+		{Name: "cursor", In: "query", Type: "integer", Description: "X-Next-Cursor from the previous page"},
+		{Name: "event", In: "query", Type: "string", Description: "Event name prefix"},
+	}, Response: []storage.EventRow{}},
+	{Path: "/sessions/{id}/summary", Method: "GET", Summary: "Post-game summary and score of one session", Access: accessAnyRole, Params: []apiParam{
+		{Name: "id", In: "path", Type: "string"},
+	}, Response: orchestrator.SessionReport{}},
+	{Path: "/state", Method: "GET", Summary: "Puzzle and node states", Access: accessAnyRole, Response: StateResponse{}},
+	{Path: "/graph", Method: "GET", Summary: "The active scene graph", Access: accessAnyRole, Response: orchestrator.SceneGraph{}},
+	{Path: "/graph/analysis", Method: "GET", Summary: "Puzzle dependencies, critical path and parallel branches", Access: accessAnyRole,
+		Response: orchestrator.GraphAnalysis{}},
+	{Path: "/graph/validation", Method: "GET", Summary: "Scene graph references to devices missing from devices.yaml", Access: accessAnyRole,
+		Response: GraphValidationResponse{}},
+	{Path: "/graph/simulate", Method: "GET", Summary: "Simulated game duration", Access: accessAnyRole, Params: []apiParam{
+		{Name: "scene", In: "query", Type: "string"},
+		{Name: "runs", In: "query", Type: "integer"},
+		{Name: "seed", In: "query", Type: "integer"},
+		{Name: "target_min", In: "query", Type: "integer", Description: "Target game length in minutes"},
+	}, Response: orchestrator.DurationSimulation{}},
+	{Path: "/nodes", Method: "GET", Summary: "Scene nodes with their state", Access: accessAnyRole, Params: []apiParam{
+		{Name: "scene", In: "query", Type: "string"},
+	}, Response: NodesResponse{}},
+	{Path: "/scenes", Method: "GET", Summary: "Scenes in the active graph", Access: accessAnyRole, Response: ScenesResponse{}},
+	{Path: "/game/clock", Method: "GET", Summary: "Game clock", Access: accessAnyRole, Response: orchestrator.ClockState{}},
+	{Path: "/game/state", Method: "GET", Summary: "Runtime snapshot", Access: accessAnyRole, Response: orchestrator.RuntimeSnapshot{}},
+	{Path: "/competition/standings", Method: "GET", Summary: "Competition standings", Access: accessAnyRole, Response: orchestrator.Standings{}},
+	{Path: "/mode", Method: "GET", Summary: "Run mode and what it allows", Access: accessAnyRole, Response: ModeResponse{}},
+	{Path: "/warnings", Method: "GET", Summary: "Warnings since startup by type", Access: accessAnyRole, Response: WarningsResponse{}},
+	{Path: "/maintenance/report", Method: "GET", Summary: "Maintenance mode status and device checks", Access: accessAnyRole,
+		Response: MaintenanceStatusResponse{}},
+	{Path: "/devices", Method: "GET", Summary: "Devices with health, topics and asset metadata", Access: accessAnyRole, Response: DevicesResponse{}},
+	{Path: "/devices/{id}/state", Method: "GET", Summary: "Last value the device reported for each payload field", Access: accessAnyRole, Params: []apiParam{
+		{Name: "id", In: "path", Type: "string"},
+	}, Response: DeviceStateResponse{}},
+	{Path: "/controllers", Method: "GET", Summary: "Registered controllers with firmware, uptime and devices", Access: accessAnyRole,
+		Response: ControllersResponse{}},
+	{Path: "/controllers/{id}/logs", Method: "GET", Summary: "Recent debug log lines a controller forwarded", Access: accessAnyRole, Params: []apiParam{
+		{Name: "id", In: "path", Type: "string"},
+		{Name: "limit", In: "query", Type: "integer", Description: "Lines, default and max 200"},
+	}, Response: ControllerLogsResponse{}},
+	{Path: "/handover", Method: "GET", Summary: "Shift handover digest", Access: accessAnyRole, Params: []apiParam{
+		{Name: "hours", In: "query", Type: "integer"},
+	}, Response: HandoverResponse{}},
+	{Path: "/prefs", Method: "GET", Summary: "Preferences of the calling operator", Access: accessAnyRole, Response: PrefsResponse{}},
+	{Path: "/prefs/{key}", Method: "GET", Summary: "One preference value (any JSON)", Access: accessAnyRole, Params: []apiParam{
+		{Name: "key", In: "path", Type: "string"},
+	}},
+	{Path: "/prefs/{key}", Method: "PUT", Summary: "Store a preference value (any JSON, up to 16 KiB)", Access: accessAnyRole, Params: []apiParam{
+		{Name: "key", In: "path", Type: "string"},
+	}, Response: OperatorResponse{}},
+	{Path: "/prefs/{key}", Method: "DELETE", Summary: "Remove a preference", Access: accessAnyRole, Params: []apiParam{
+		{Name: "key", In: "path", Type: "string"},
+	}, Response: OperatorResponse{}},
+
+	// Admin only
+	{Path: "/game/start", Method: "POST", Summary: "Start a game", Access: accessAdmin, Confirm: true, Request: GameStartRequest{}, Response: GameResponse{}},
+	{Path: "/game/stop", Method: "POST", Summary: "Stop the game", Access: accessAdmin, Confirm: true, Response: GameResponse{}},
+	{Path: "/game/pause", Method: "POST", Summary: "Pause the game", Access: accessAnyRole, Response: GameResponse{}},
+	{Path: "/game/resume", Method: "POST", Summary: "Resume a paused game", Access: accessAnyRole, Response: GameResponse{}},
+	{Path: "/game/input", Method: "POST", Summary: "Inject a synthetic device input (rehearsal mode)", Access: accessAnyRole,
+		Request: SyntheticInputRequest{}, Response: GameResponse{}},
+	{Path: "/game/scene", Method: "POST", Summary: "Move the game to another scene", Access: accessAnyRole, Confirm: true,
+		Request: GameSceneRequest{}, Response: GameResponse{}},
+	{Path: "/competition/arm", Method: "POST", Summary: "Arm a synchronized start with linked rooms", Access: accessAdmin,
+		Request: CompetitionArmRequest{}, Response: CompetitionArmResponse{}},
+	{Path: "/competition/cancel", Method: "POST", Summary: "Cancel an armed competition", Access: accessAdmin, Response: OperatorResponse{}},
+	{Path: "/admin/graph/diff", Method: "GET", Summary: "Diff from the active to the staged scene graph", Access: accessAdmin,
+		Response: orchestrator.GraphDiff{}},
+	{Path: "/admin/maintenance/start", Method: "POST", Summary: "Enter maintenance mode", Access: accessAdmin, Response: GameResponse{}},
+	{Path: "/admin/maintenance/stop", Method: "POST", Summary: "Leave maintenance mode", Access: accessAdmin, Response: GameResponse{}},
+	{Path: "/admin/mode", Method: "POST", Summary: "Switch the run mode between games", Access: accessAdmin,
+		Request: ModeRequest{}, Response: GameResponse{}},
+	{Path: "/admin/devices/asset", Method: "POST", Summary: "Update device asset metadata", Access: accessAdmin,
+		Request: AssetUpdateRequest{}, Response: OperatorResponse{}},
+	{Path: "/admin/devices/reload", Method: "POST", Summary: "Reload devices.yaml and re-validate registered controllers", Access: accessAdmin,
+		Response: DevicesReloadResponse{}},
+	{Path: "/admin/triggers", Method: "GET", Summary: "Active trigger tokens", Access: accessAdmin, Response: TriggersResponse{}},
+	{Path: "/admin/triggers", Method: "POST", Summary: "Create a trigger token (shown once)", Access: accessAdmin,
+		Request: TriggerCreateRequest{}, Response: TriggerCreateResponse{}},
+	{Path: "/admin/triggers", Method: "DELETE", Summary: "Revoke a trigger token", Access: accessAdmin, Params: []apiParam{
+		{Name: "id", In: "query", Type: "string"},
+	}, Response: OperatorResponse{}},
+}
+
+var (
+	openAPIOnce sync.Once
+	openAPIDoc  []byte
+)
+
+// openAPIHandler serves the OpenAPI 3 description of the API.
+func openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	openAPIOnce.Do(func() {
+		openAPIDoc, _ = json.Marshal(buildOpenAPI(apiOperations))
+	})
+	_, _ = w.Write(openAPIDoc)
+}
+
+// buildOpenAPI renders operations as an OpenAPI 3.0 document.
+func buildOpenAPI(ops []apiOperation) map[string]interface{} {
+	b := &schemaBuilder{schemas: make(map[string]interface{}), names: make(map[reflect.Type]string)}
+	errorRef := b.schema(reflect.TypeOf(OperatorResponse{}))
+	errorResponse := func(desc string) map[string]interface{} {
+		return map[string]interface{}{
+			"description": desc,
+			"content":     map[string]interface{}{"application/json": map[string]interface{}{"schema": errorRef}},
+		}
+	}
+
+	paths := make(map[string]map[string]interface{})
+	for _, op := range ops {
+		responses := map[string]interface{}{}
+		ok := map[string]interface{}{"description": "OK"}
+		switch {
+		case op.ContentType != "":
+			ok["content"] = map[string]interface{}{op.ContentType: map[string]interface{}{}}
+		case op.Response != nil:
+			ok["content"] = map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": b.schema(reflect.TypeOf(op.Response))},
+			}
+		}
+		if op.WebSocket {
+			ok["description"] = "Not returned; the connection upgrades"
+			responses["101"] = map[string]interface{}{
+				"description": "WebSocket upgrade; each text message is one JSON value",
+				"content":     ok["content"],
+			}
+			delete(ok, "content")
+		} else {
+			responses["200"] = ok
+		}
+		if op.Request != nil || len(op.Params) > 0 {
+			responses["400"] = errorResponse("Invalid request")
+		}
+		responses["405"] = errorResponse("Method not allowed")
+
+		operation := map[string]interface{}{
+			"summary":     op.Summary,
+			"operationId": operationID(op),
+			"responses":   responses,
+		}
+		if op.Request != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": b.schema(reflect.TypeOf(op.Request))},
+				},
+			}
+		}
+
+		var params []interface{}
+		for _, p := range op.Params {
+			param := map[string]interface{}{
+				"name":     p.Name,
+				"in":       p.In,
+				"required": p.In == "path",
+				"schema":   map[string]interface{}{"type": p.Type},
+			}
+			if p.Description != "" {
+				param["description"] = p.Description
+			}
+			params = append(params, param)
+		}
+		if op.Confirm {
+			params = append(params, map[string]interface{}{
+				"name":        ConfirmHeader,
+				"in":          "header",
+				"description": "Token from a previous 428 response, when destructive actions need confirmation",
+				"schema":      map[string]interface{}{"type": "string"},
+			})
+			responses["428"] = map[string]interface{}{
+				"description": "Confirmation required; repeat with the token",
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": b.schema(reflect.TypeOf(ConfirmResponse{}))},
+				},
+			}
+		}
+		if params != nil {
+			operation["parameters"] = params
+		}
+
+		switch op.Access {
+		case accessPublic, accessTriggerToken:
+			operation["security"] = []interface{}{}
+		case accessAnyRole:
+			operation["security"] = []interface{}{map[string]interface{}{"basicAuth": []string{}}}
+			operation["x-roles"] = []Role{RoleAdmin, RoleOperator}
+		case accessAdmin:
+			operation["security"] = []interface{}{map[string]interface{}{"basicAuth": []string{}}}
+			operation["x-roles"] = []Role{RoleAdmin}
+		case accessFederationToken:
+			operation["security"] = []interface{}{map[string]interface{}{"federationToken": []string{}}}
+		}
+		switch op.Access {
+		case accessAnyRole, accessAdmin:
+			responses["401"] = errorResponse("Missing or invalid credentials")
+			responses["403"] = errorResponse("Role not allowed")
+		case accessTriggerToken:
+			responses["404"] = errorResponse("Unknown, expired or used-up token")
+		case accessFederationToken:
+			responses["401"] = errorResponse("Invalid federation token or unknown peer")
+		}
+
+		if paths[op.Path] == nil {
+			paths[op.Path] = make(map[string]interface{})
+		}
+		paths[op.Path][strings.ToLower(op.Method)] = operation
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Sentient Engine API",
+			"version":     version.Version,
+			"description": "Room orchestrator API. Credentials are only enforced when SENTIENT_ADMIN_USER and SENTIENT_ADMIN_PASS are set.",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": b.schemas,
+			"securitySchemes": map[string]interface{}{
+				"basicAuth": map[string]interface{}{"type": "http", "scheme": "basic"},
+				"federationToken": map[string]interface{}{
+					"type": "apiKey",
+					"in":   "header",
+					"name": orchestrator.FederationTokenHeader,
+				},
+			},
+		},
+		"security": []interface{}{map[string]interface{}{"basicAuth": []string{}}},
+	}
+}
+
+// operationID derives a stable operation ID, e.g. "get_graph_analysis".
+func operationID(op apiOperation) string {
+	path := strings.NewReplacer("/", "_", "-", "_", "{", "", "}", "", ".", "_").Replace(strings.Trim(op.Path, "/"))
+	return strings.ToLower(op.Method) + "_" + path
+}
+
+// schemaBuilder derives JSON schemas from Go types, following encoding/json rules.
+// Named structs become components referenced by $ref.
+type schemaBuilder struct {
+	schemas map[string]interface{}
+	names   map[reflect.Type]string
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+func (b *schemaBuilder) schema(t reflect.Type) map[string]interface{} {
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case rawMessageType:
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return b.schema(t.Elem())
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Int64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		name, ok := b.names[t]
+		if !ok {
+			name = b.componentName(t)
+			b.names[t] = name
+			b.schemas[name] = nil // reserve before recursing, for self-referencing types
+			b.schemas[name] = b.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+	return map[string]interface{}{} // interface{}: any value
+}
+
+// componentName names a struct's component, qualifying it by package on a clash.
+func (b *schemaBuilder) componentName(t reflect.Type) string {
+	name := t.Name()
+	if _, taken := b.schemas[name]; !taken {
+		return name
+	}
+	pkg := t.PkgPath()
+	return pkg[strings.LastIndex(pkg, "/")+1:] + "." + name
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	props := make(map[string]interface{})
+	b.addFields(t, props)
+	return map[string]interface{}{"type": "object", "properties": props}
+}
+
+// addFields adds a struct's JSON fields to props, flattening embedded structs.
+func (b *schemaBuilder) addFields(t reflect.Type, props map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				b.addFields(ft, props)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = b.schema(f.Type)
+	}
+}
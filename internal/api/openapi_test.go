@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPIDocument(t *testing.T) {
+	w := httptest.NewRecorder()
+	openAPIHandler(w, httptest.NewRequest("GET", "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var doc struct {
+		OpenAPI    string                                       `json:"openapi"`
+		Paths      map[string]map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("failed to parse document: %v", err)
+	}
+	if doc.OpenAPI != "3.0.3" {
+		t.Errorf("expected openapi 3.0.3, got %q", doc.OpenAPI)
+	}
+
+	// Every $ref resolves
+	for _, ref := range regexp.MustCompile(`"\$ref":"#/components/schemas/([^"]+)"`).FindAllStringSubmatch(w.Body.String(), -1) {
+		if doc.Components.Schemas[ref[1]] == nil {
+			t.Errorf("unresolved schema reference %s", ref[1])
+		}
+	}
+
+	// Spot checks: auth, request and response types, confirmation
+	start := doc.Paths["/game/start"]["post"]
+	if start == nil || start["requestBody"] == nil {
+		t.Fatal("expected POST /game/start with a request body")
+	}
+	if roles, _ := start["x-roles"].([]interface{}); len(roles) != 1 || roles[0] != "admin" {
+		t.Errorf("expected /game/start to be admin only, got %v", start["x-roles"])
+	}
+	if sec, _ := doc.Paths["/health"]["get"]["security"].([]interface{}); sec == nil || len(sec) != 0 {
+		t.Errorf("expected /health to be public, got %v", doc.Paths["/health"]["get"]["security"])
+	}
+	if responses, _ := doc.Paths["/game/stop"]["post"]["responses"].(map[string]interface{}); responses["428"] == nil {
+		t.Error("expected /game/stop to document the 428 confirmation response")
+	}
+	props, _ := doc.Components.Schemas["CompetitionArmRequest"]["properties"].(map[string]interface{})
+	if props["rooms"] == nil || props["start_in_sec"] == nil {
+		t.Errorf("expected CompetitionArmRequest fields from json tags, got %v", props)
+	}
+	props, _ = doc.Components.Schemas["HandoverResponse"]["properties"].(map[string]interface{})
+	if props["hours"] == nil || len(props) < 3 {
+		t.Errorf("expected HandoverResponse to include embedded digest fields, got %v", props)
+	}
+}
+
+// TestOpenAPICoversRoutes keeps apiOperations in sync with NewServer.
+func TestOpenAPICoversRoutes(t *testing.T) {
+	src, err := os.ReadFile("server.go")
+	if err != nil {
+		t.Fatalf("failed to read server.go: %v", err)
+	}
+	documented := make(map[string]bool)
+	for _, op := range apiOperations {
+		documented[op.Path] = true
+	}
+
+	routes := regexp.MustCompile(`mux\.HandleFunc\("([^"]+)"`).FindAllStringSubmatch(string(src), -1)
+	if len(routes) == 0 {
+		t.Fatal("no routes found in server.go")
+	}
+	for _, route := range routes {
+		path := route[1]
+		if !strings.HasSuffix(path, "/") {
+			if !documented[path] {
+				t.Errorf("route %s is not in apiOperations", path)
+			}
+			continue
+		}
+		// Subtree routes are documented with a path parameter, e.g. /events/{seq}
+		found := false
+		for p := range documented {
+			if strings.HasPrefix(p, path+"{") {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("route %s has no parameterized path in apiOperations", path)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// overlayInterval is how often /ws/overlay pushes a frame.
+const overlayInterval = time.Second
+
+// OverlayFrame is the public, spoiler-free view of the current game for
+// stream overlays: no node, puzzle or device names.
+type OverlayFrame struct {
+	Room        string `json:"room,omitempty"`
+	Team        string `json:"team,omitempty"`
+	GameActive  bool   `json:"game_active"`
+	ProgressPct int    `json:"progress_pct"`
+	RemainingMs int64  `json:"remaining_ms"`
+	DurationMs  int64  `json:"duration_ms"`
+	Paused      bool   `json:"paused"`
+	Expired     bool   `json:"expired"`
+}
+
+// overlayState holds the team name shown on the overlay and closes overlay
+// connections on shutdown.
+var overlayState = struct {
+	mu    sync.Mutex
+	team  string
+	done  chan struct{}
+	close sync.Once
+}{done: make(chan struct{})}
+
+// setOverlayTeam sets the team name for the current session ("" to clear).
+func setOverlayTeam(team string) {
+	overlayState.mu.Lock()
+	defer overlayState.mu.Unlock()
+	overlayState.team = team
+}
+
+// closeOverlays disconnects all /ws/overlay clients.
+func closeOverlays() {
+	overlayState.close.Do(func() { close(overlayState.done) })
+}
+
+// currentOverlayFrame builds a frame from the live runtime state.
+func currentOverlayFrame() OverlayFrame {
+	overlayState.mu.Lock()
+	frame := OverlayFrame{Room: GetRoomName(), Team: overlayState.team}
+	overlayState.mu.Unlock()
+
+	if runtimeController == nil {
+		return frame
+	}
+	snap := runtimeController.Snapshot()
+	frame.GameActive = snap.GameActive
+	frame.ProgressPct = snap.ProgressPct()
+	frame.RemainingMs = snap.Clock.RemainingMs
+	frame.DurationMs = snap.Clock.DurationMs
+	frame.Paused = snap.Clock.Paused
+	frame.Expired = snap.Clock.Expired
+	if !snap.GameActive {
+		frame.Team = ""
+	}
+	return frame
+}
+
+// wsOverlayHandler streams overlay frames once per second. It is public so
+// OBS browser sources can use it without credentials.
+func wsOverlayHandler(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("ws overlay upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	// Reader goroutine - handles pongs and close messages
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+		conn.SetPongHandler(func(string) error {
+			conn.SetReadDeadline(time.Now().Add(pongWait))
+			return nil
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(overlayInterval)
+	defer ticker.Stop()
+	pings := time.NewTicker(pingPeriod)
+	defer pings.Stop()
+
+	if !writeOverlayFrame(conn) {
+		return
+	}
+	for {
+		select {
+		case <-done:
+			return
+		case <-overlayState.done:
+			return
+		case <-ticker.C:
+			if !writeOverlayFrame(conn) {
+				return
+			}
+		case <-pings.C:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeOverlayFrame sends the current frame. Returns false if the client is gone.
+func writeOverlayFrame(conn *websocket.Conn) bool {
+	data, err := json.Marshal(currentOverlayFrame())
+	if err != nil {
+		return true
+	}
+	conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return conn.WriteMessage(websocket.TextMessage, data) == nil
+}
+
+// overlayHandler serves a transparent page rendering /ws/overlay, sized for
+// an OBS browser source.
+func overlayHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(overlayHTML))
+}
+
+const overlayHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <title>Sentient Engine - Overlay</title>
+    <style>
+        body {
+            margin: 0;
+            background: transparent;
+            color: #fff;
+            font-family: sans-serif;
+            text-shadow: 0 1px 3px rgba(0, 0, 0, 0.8);
+        }
+        #overlay { padding: 16px; display: inline-block; }
+        #team { font-size: 28px; font-weight: bold; }
+        #room { font-size: 16px; opacity: 0.8; }
+        #clock { font-size: 48px; font-variant-numeric: tabular-nums; }
+        #clock.paused { opacity: 0.5; }
+        .bar { width: 320px; height: 10px; background: rgba(255, 255, 255, 0.25); border-radius: 5px; }
+        #progress { height: 100%; width: 0; background: #22c55e; border-radius: 5px; transition: width 0.5s; }
+    </style>
+</head>
+<body>
+    <div id="overlay">
+        <div id="team"></div>
+        <div id="room"></div>
+        <div id="clock">--:--</div>
+        <div class="bar"><div id="progress"></div></div>
+    </div>
+    <script>
+        function pad(n) { return (n < 10 ? '0' : '') + n; }
+
+        function render(f) {
+            document.getElementById('team').textContent = f.team || '';
+            document.getElementById('room').textContent = f.room || '';
+            const clock = document.getElementById('clock');
+            const secs = Math.ceil((f.remaining_ms || 0) / 1000);
+            clock.textContent = f.game_active ? pad(Math.floor(secs / 60)) + ':' + pad(secs % 60) : '--:--';
+            clock.className = f.paused ? 'paused' : '';
+            document.getElementById('progress').style.width = (f.progress_pct || 0) + '%';
+        }
+
+        function connect() {
+            const protocol = location.protocol === 'https:' ? 'wss:' : 'ws:';
+            const ws = new WebSocket(protocol + '//' + location.host + '/ws/overlay');
+            ws.onmessage = function(msg) {
+                try { render(JSON.parse(msg.data)); } catch (err) {}
+            };
+            ws.onclose = function() { setTimeout(connect, 3000); };
+        }
+        connect();
+    </script>
+</body>
+</html>`
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+	"github.com/gorilla/websocket"
+)
+
+func TestOverlayFeed(t *testing.T) {
+	clearTLSEnv(t)
+	sg, err := orchestrator.LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	rt := orchestrator.NewRuntime(sg)
+	SetRuntimeController(rt)
+	defer SetRuntimeController(nil)
+
+	w := httptest.NewRecorder()
+	gameStartHandler(w, httptest.NewRequest("POST", "/game/start", strings.NewReader(`{"team": "The Scarabs"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	defer func() {
+		rt.StopGame()
+		setOverlayTeam("")
+	}()
+	if err := rt.OverrideNode("puzzle_scarab"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(wsOverlayHandler))
+	defer server.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("failed to read frame: %v", err)
+	}
+	var frame OverlayFrame
+	if err := json.Unmarshal(data, &frame); err != nil {
+		t.Fatalf("failed to decode frame: %v", err)
+	}
+	if !frame.GameActive || frame.Team != "The Scarabs" || frame.ProgressPct != 50 || frame.RemainingMs <= 0 {
+		t.Errorf("unexpected frame: %+v", frame)
+	}
+	// Spoiler-free: no node or puzzle names
+	if strings.Contains(string(data), "puzzle") || strings.Contains(string(data), "scene") {
+		t.Errorf("overlay frame leaks graph details: %s", data)
+	}
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// gamePauseHandler freezes the running session (POST /game/pause).
+func gamePauseHandler(w http.ResponseWriter, r *http.Request) {
+	gamePauseResumeHandler(w, r, func() error {
+		return runtimeController.PauseGame()
+	})
+}
+
+// gameResumeHandler continues a paused session (POST /game/resume).
+func gameResumeHandler(w http.ResponseWriter, r *http.Request) {
+	gamePauseResumeHandler(w, r, func() error {
+		return runtimeController.ResumeGame()
+	})
+}
+
+// gamePauseResumeHandler applies a pause or resume. The runtime emits
+// operator.pause or operator.resume.
+func gamePauseResumeHandler(w http.ResponseWriter, r *http.Request, apply func() error) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	if runtimeController == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "runtime not available"})
+		return
+	}
+
+	if !runtimeController.IsGameActive() {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "no active session"})
+		return
+	}
+
+	if err := apply(); err != nil {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(GameResponse{OK: true})
+}
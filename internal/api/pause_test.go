@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+func TestGamePauseHandlers(t *testing.T) {
+	sg, err := orchestrator.LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	rt := orchestrator.NewRuntime(sg)
+	SetRuntimeController(rt)
+	defer SetRuntimeController(nil)
+
+	// No active session
+	w := httptest.NewRecorder()
+	gamePauseHandler(w, httptest.NewRequest("POST", "/game/pause", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 without active session, got %d", w.Code)
+	}
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+
+	w = httptest.NewRecorder()
+	gameResumeHandler(w, httptest.NewRequest("POST", "/game/resume", nil))
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status 409 resuming a running game, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	gamePauseHandler(w, httptest.NewRequest("POST", "/game/pause", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 on pause, got %d", w.Code)
+	}
+	if !rt.IsPaused() {
+		t.Error("expected game paused")
+	}
+
+	w = httptest.NewRecorder()
+	gamePauseHandler(w, httptest.NewRequest("GET", "/game/pause", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405 on GET, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	gameResumeHandler(w, httptest.NewRequest("POST", "/game/resume", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 on resume, got %d", w.Code)
+	}
+	if rt.IsPaused() {
+		t.Error("expected game running after resume")
+	}
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/mqtt"
+)
+
+// WatchPower sends a warning alert to the webhook whenever a prop that should
+// be powered draws nothing (device.error with anomaly no_power), since hidden
+// electronics that lose power otherwise fail silently. It returns a function
+// that stops watching.
+func WatchPower() func() {
+	return events.AddListener(func(e events.Event) {
+		if e.Name != "device.error" || e.Fields["anomaly"] != mqtt.AnomalyNoPower {
+			return
+		}
+		logicalID, _ := e.Fields["logical_id"].(string)
+		details := map[string]interface{}{
+			"logical_id": logicalID,
+			"watts":      e.Fields["watts"],
+			"min_watts":  e.Fields["min_watts"],
+		}
+		SendAlert(AlertDeviceUnpowered, SeverityWarning, fmt.Sprintf("Device %s is drawing no power", logicalID), details)
+	})
+}
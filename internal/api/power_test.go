@@ -0,0 +1,46 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/mqtt"
+)
+
+func TestWatchPower(t *testing.T) {
+	alertMu.Lock()
+	savedLog, savedConfig := alertLog, *alertConfig
+	alertLog = nil
+	alertConfig.WebhookURL = ""
+	alertMu.Unlock()
+	defer func() {
+		alertMu.Lock()
+		alertLog, *alertConfig = savedLog, savedConfig
+		alertMu.Unlock()
+	}()
+	events.Clear()
+	defer events.Clear()
+
+	stop := WatchPower()
+	since := time.Now().Add(-time.Minute)
+	events.Emit("warning", "device.error", "device anomaly: silent mid-game", map[string]interface{}{
+		"logical_id": "fog_machine",
+		"anomaly":    mqtt.AnomalySilent,
+	})
+	events.Emit("warning", "device.error", "device anomaly: drawing no power", map[string]interface{}{
+		"logical_id": "fog_machine",
+		"anomaly":    mqtt.AnomalyNoPower,
+		"watts":      0.0,
+		"min_watts":  5.0,
+	})
+	stop()
+
+	alerts := UnackedAlerts(since)
+	if len(alerts) != 1 || alerts[0].Event != AlertDeviceUnpowered || alerts[0].Severity != SeverityWarning {
+		t.Fatalf("expected one device_unpowered warning, got %+v", alerts)
+	}
+	if alerts[0].Message != "Device fog_machine is drawing no power" {
+		t.Errorf("unexpected message %q", alerts[0].Message)
+	}
+}
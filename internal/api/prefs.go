@@ -0,0 +1,186 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// Preference limits.
+const (
+	MaxPrefBytes    = 16 << 10 // per value
+	MaxPrefsPerUser = 64
+)
+
+// prefKeyPattern is the allowed shape of preference keys.
+var prefKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// PrefStore persists operator preferences (implemented by storage.Store).
+type PrefStore interface {
+	SavePref(username, key string, value []byte, ts time.Time) error
+	DeletePref(username, key string) error
+	LoadPrefs() (map[string]map[string][]byte, error)
+}
+
+// PrefsResponse is returned by GET /prefs. Preferences are small JSON values
+// the GM console keeps per user (filters, pinned devices, layout) so its view
+// survives a reload; the server does not interpret them.
+type PrefsResponse struct {
+	User  string                     `json:"user"`
+	Prefs map[string]json.RawMessage `json:"prefs"`
+}
+
+var (
+	prefsMu   sync.Mutex
+	prefs     = make(map[string]map[string]json.RawMessage) // username -> key -> value
+	prefStore PrefStore
+)
+
+// SetPrefStore sets where preferences are persisted and loads stored ones.
+// Preferences set before the store was attached (while it was unreachable)
+// take precedence over stored values and are saved to it.
+func SetPrefStore(store PrefStore) error {
+	stored, err := store.LoadPrefs()
+	if err != nil {
+		return err
+	}
+
+	prefsMu.Lock()
+	defer prefsMu.Unlock()
+
+	prefStore = store
+	for user, values := range prefs {
+		for key, value := range values {
+			savePrefLocked(user, key, value)
+		}
+	}
+	for user, values := range stored {
+		if prefs[user] == nil {
+			prefs[user] = make(map[string]json.RawMessage, len(values))
+		}
+		for key, value := range values {
+			if _, ok := prefs[user][key]; !ok {
+				prefs[user][key] = json.RawMessage(value)
+			}
+		}
+	}
+	return nil
+}
+
+// savePrefLocked persists one preference. Caller must hold prefsMu.
+func savePrefLocked(user, key string, value []byte) {
+	if prefStore == nil {
+		return
+	}
+	if err := prefStore.SavePref(user, key, value, time.Now()); err != nil {
+		events.Emit("error", "system.error", "failed to persist preference", map[string]interface{}{
+			"key":   key,
+			"error": err.Error(),
+		})
+	}
+}
+
+// prefsHandler returns every preference of the calling user (GET /prefs).
+func prefsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	user := requestUser(r)
+	resp := PrefsResponse{User: user, Prefs: make(map[string]json.RawMessage)}
+	prefsMu.Lock()
+	for key, value := range prefs[user] {
+		resp.Prefs[key] = value
+	}
+	prefsMu.Unlock()
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// prefHandler reads (GET), stores (PUT) or removes (DELETE) one preference of
+// the calling user at /prefs/{key}. A PUT body is any JSON value.
+func prefHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	key := strings.TrimPrefix(r.URL.Path, "/prefs/")
+	if !prefKeyPattern.MatchString(key) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "invalid key"})
+		return
+	}
+	user := requestUser(r)
+
+	switch r.Method {
+	case http.MethodGet:
+		prefsMu.Lock()
+		value, ok := prefs[user][key]
+		prefsMu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "preference not found"})
+			return
+		}
+		_, _ = w.Write(value)
+
+	case http.MethodPut:
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxPrefBytes))
+		if err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "preference too large"})
+			return
+		}
+		if !json.Valid(body) {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "invalid JSON"})
+			return
+		}
+
+		prefsMu.Lock()
+		defer prefsMu.Unlock()
+		values := prefs[user]
+		if _, exists := values[key]; !exists && len(values) >= MaxPrefsPerUser {
+			w.WriteHeader(http.StatusConflict)
+			_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "too many preferences"})
+			return
+		}
+		if values == nil {
+			values = make(map[string]json.RawMessage)
+			prefs[user] = values
+		}
+		values[key] = json.RawMessage(body)
+		savePrefLocked(user, key, body)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: true})
+
+	case http.MethodDelete:
+		prefsMu.Lock()
+		defer prefsMu.Unlock()
+		if _, ok := prefs[user][key]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "preference not found"})
+			return
+		}
+		delete(prefs[user], key)
+		if prefStore != nil {
+			if err := prefStore.DeletePref(user, key); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: err.Error()})
+				return
+			}
+		}
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: true})
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "method not allowed"})
+	}
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memPrefStore keeps preferences in memory.
+type memPrefStore struct {
+	rows map[string]map[string][]byte
+}
+
+func (s *memPrefStore) SavePref(username, key string, value []byte, ts time.Time) error {
+	if s.rows[username] == nil {
+		s.rows[username] = make(map[string][]byte)
+	}
+	s.rows[username][key] = value
+	return nil
+}
+
+func (s *memPrefStore) DeletePref(username, key string) error {
+	delete(s.rows[username], key)
+	return nil
+}
+
+func (s *memPrefStore) LoadPrefs() (map[string]map[string][]byte, error) {
+	return s.rows, nil
+}
+
+func resetPrefs() {
+	prefsMu.Lock()
+	prefs = make(map[string]map[string]json.RawMessage)
+	prefStore = nil
+	prefsMu.Unlock()
+}
+
+func TestPrefs(t *testing.T) {
+	store := &memPrefStore{rows: map[string]map[string][]byte{
+		"operator": {"pinned": []byte(`["crypt_door"]`)},
+	}}
+	if err := SetPrefStore(store); err != nil {
+		t.Fatalf("failed to set store: %v", err)
+	}
+	defer resetPrefs()
+
+	do := func(method, path, user, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.SetBasicAuth(user, "secret")
+		w := httptest.NewRecorder()
+		if path == "/prefs" {
+			prefsHandler(w, req)
+		} else {
+			prefHandler(w, req)
+		}
+		return w
+	}
+
+	if w := do("GET", "/prefs/pinned", "operator", ""); w.Code != http.StatusOK || w.Body.String() != `["crypt_door"]` {
+		t.Errorf("expected stored preference, got %d %s", w.Code, w.Body.String())
+	}
+
+	layout := `{"columns":["events","devices"],"filter":"puzzle.*"}`
+	if w := do("PUT", "/prefs/layout", "operator", layout); w.Code != http.StatusOK {
+		t.Fatalf("expected status 200 on put, got %d: %s", w.Code, w.Body.String())
+	}
+	if string(store.rows["operator"]["layout"]) != layout {
+		t.Errorf("expected layout persisted, got %s", store.rows["operator"]["layout"])
+	}
+
+	var resp PrefsResponse
+	w := do("GET", "/prefs", "operator", "")
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if resp.User != "operator" || len(resp.Prefs) != 2 {
+		t.Errorf("expected 2 preferences for operator, got %+v", resp)
+	}
+
+	// Preferences are per user
+	if w := do("GET", "/prefs/layout", "admin", ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected admin to have no layout, got %d", w.Code)
+	}
+
+	if w := do("PUT", "/prefs/layout", "operator", "{not json"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid JSON, got %d", w.Code)
+	}
+	if w := do("PUT", "/prefs/bad%20key", "operator", "1"); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for invalid key, got %d", w.Code)
+	}
+	if w := do("PUT", "/prefs/big", "operator", `"`+strings.Repeat("x", MaxPrefBytes)+`"`); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected status 413 for oversized value, got %d", w.Code)
+	}
+
+	if w := do("DELETE", "/prefs/layout", "operator", ""); w.Code != http.StatusOK {
+		t.Errorf("expected status 200 on delete, got %d", w.Code)
+	}
+	if _, ok := store.rows["operator"]["layout"]; ok {
+		t.Error("expected layout removed from the store")
+	}
+	if w := do("DELETE", "/prefs/layout", "operator", ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 deleting again, got %d", w.Code)
+	}
+}
+
+func TestPrefStoreAttachedLate(t *testing.T) {
+	defer resetPrefs()
+
+	// Set while the store was unreachable
+	req := httptest.NewRequest("PUT", "/prefs/layout", strings.NewReader(`"compact"`))
+	req.SetBasicAuth("operator", "secret")
+	prefHandler(httptest.NewRecorder(), req)
+
+	store := &memPrefStore{rows: map[string]map[string][]byte{
+		"operator": {"layout": []byte(`"wide"`), "pinned": []byte(`["crypt_door"]`)},
+	}}
+	if err := SetPrefStore(store); err != nil {
+		t.Fatalf("failed to set store: %v", err)
+	}
+
+	prefsMu.Lock()
+	layout, pinned := string(prefs["operator"]["layout"]), string(prefs["operator"]["pinned"])
+	prefsMu.Unlock()
+	if layout != `"compact"` || pinned != `["crypt_door"]` {
+		t.Errorf("expected the newer layout kept and stored prefs merged, got %s and %s", layout, pinned)
+	}
+	if string(store.rows["operator"]["layout"]) != `"compact"` {
+		t.Errorf("expected the newer layout saved to the store, got %s", store.rows["operator"]["layout"])
+	}
+}
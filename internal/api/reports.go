@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+// Default and maximum number of persisted events scanned for reports.
+const (
+	defaultReportEventLimit = 20000
+	maxReportEventLimit     = 100000
+)
+
+// difficultyReportHandler ranks puzzles by historical difficulty.
+func difficultyReportHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	client := events.GetStore()
+	if client == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "postgres not available"})
+		return
+	}
+
+	graphState.mu.RLock()
+	sg := graphState.active
+	graphState.mu.RUnlock()
+	if sg == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "scene graph not loaded"})
+		return
+	}
+
+	limit := defaultReportEventLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid limit parameter"})
+			return
+		}
+		limit = l
+	}
+	if limit > maxReportEventLimit {
+		limit = maxReportEventLimit
+	}
+
+	rows, err := client.QueryByNames(orchestrator.DifficultyEventNames, limit)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(orchestrator.BuildDifficultyReport(rows, sg))
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// GameSceneRequest moves the running game to another scene.
+type GameSceneRequest struct {
+	SceneID string `json:"scene_id"`
+}
+
+// gameSceneHandler moves the running game to another scene (POST /game/scene),
+// overriding the graph's scene transitions. The runtime emits scene.advanced.
+func gameSceneHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	var req GameSceneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "invalid JSON"})
+		return
+	}
+
+	if req.SceneID == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "scene_id required"})
+		return
+	}
+
+	if runtimeController == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "runtime not available"})
+		return
+	}
+
+	if !runtimeController.IsGameActive() {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: "no active session"})
+		return
+	}
+
+	if !requireConfirm(w, r, "advance_scene:"+req.SceneID) {
+		return
+	}
+
+	if err := runtimeController.AdvanceScene(req.SceneID); err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(GameResponse{OK: true})
+}
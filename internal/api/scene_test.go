@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+func TestGameSceneHandler(t *testing.T) {
+	sg, err := orchestrator.LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	sg.Scenes = append(sg.Scenes, orchestrator.Scene{
+		ID:    "scene_finale",
+		Entry: "the_end",
+		Nodes: []orchestrator.Node{{ID: "the_end", Type: "terminal"}},
+	})
+	rt := orchestrator.NewRuntime(sg)
+	SetRuntimeController(rt)
+	defer SetRuntimeController(nil)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		gameSceneHandler(w, httptest.NewRequest("POST", "/game/scene", strings.NewReader(body)))
+		return w
+	}
+
+	if w := post(`{"scene_id":"scene_finale"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 without active session, got %d", w.Code)
+	}
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+
+	if w := post(`{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 without scene_id, got %d", w.Code)
+	}
+	if w := post(`{"scene_id":"scene_missing"}`); w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown scene, got %d", w.Code)
+	}
+	if w := post(`{"scene_id":"scene_finale"}`); w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if rt.ActiveSceneID() != "scene_finale" {
+		t.Errorf("expected scene_finale, got %s", rt.ActiveSceneID())
+	}
+}
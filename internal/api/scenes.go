@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// SceneInfo summarizes one scene of the loaded scene graph.
+type SceneInfo struct {
+	ID        string `json:"id"`
+	Name      string `json:"name,omitempty"`
+	Entry     string `json:"entry"`
+	NodeCount int    `json:"node_count"`
+	Default   bool   `json:"default,omitempty"` // started when /game/start omits scene_id
+}
+
+// ScenesResponse is returned by GET /scenes.
+type ScenesResponse struct {
+	ActiveSceneID string      `json:"active_scene_id,omitempty"`
+	Scenes        []SceneInfo `json:"scenes"`
+}
+
+// scenesHandler lists the scenes of the loaded scene graph in graph order, so
+// the operator UI can offer them when starting a game.
+func scenesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	graphState.mu.RLock()
+	active := graphState.active
+	graphState.mu.RUnlock()
+
+	if active == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "scene graph not loaded"})
+		return
+	}
+
+	resp := ScenesResponse{Scenes: make([]SceneInfo, 0, len(active.Scenes))}
+	if runtimeController != nil {
+		resp.ActiveSceneID = runtimeController.ActiveSceneID()
+	}
+	for i, scene := range active.Scenes {
+		resp.Scenes = append(resp.Scenes, SceneInfo{
+			ID:        scene.ID,
+			Name:      scene.Name,
+			Entry:     scene.Entry,
+			NodeCount: len(scene.Nodes),
+			Default:   i == 0,
+		})
+	}
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+func TestScenesHandler(t *testing.T) {
+	SetSceneGraph(nil)
+	w := httptest.NewRecorder()
+	scenesHandler(w, httptest.NewRequest("GET", "/scenes", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without a graph, got %d", w.Code)
+	}
+
+	sg, err := orchestrator.LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	SetSceneGraph(sg)
+	defer SetSceneGraph(nil)
+
+	w = httptest.NewRecorder()
+	scenesHandler(w, httptest.NewRequest("GET", "/scenes", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var resp ScenesResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Scenes) != len(sg.Scenes) {
+		t.Fatalf("expected %d scenes, got %d", len(sg.Scenes), len(resp.Scenes))
+	}
+	first := resp.Scenes[0]
+	if first.ID != "scene_intro" || first.Entry != sg.Scenes[0].Entry || first.NodeCount != len(sg.Scenes[0].Nodes) || !first.Default {
+		t.Errorf("unexpected scene info: %+v", first)
+	}
+
+	w = httptest.NewRecorder()
+	scenesHandler(w, httptest.NewRequest("POST", "/scenes", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
@@ -6,12 +6,17 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/mqtt"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
 	"github.com/AaronLay10/SentientEngine/internal/version"
 )
 
@@ -56,12 +61,34 @@ type ReadinessResponse struct {
 	Version     string                    `json:"version"`
 	Checks      map[string]ReadinessCheck `json:"checks"`
 	NotReadyMsg string                    `json:"message,omitempty"`
+	Warnings    map[string]uint64         `json:"warnings,omitempty"` // warnings since startup by type; informational
 }
 
 // ReadinessCheck represents a single dependency check.
 type ReadinessCheck struct {
-	Status   string `json:"status"` // "ok", "not_ready", "unavailable"
-	Optional bool   `json:"optional,omitempty"`
+	Status   string   `json:"status"` // "ok", "not_ready", "unavailable", "warning"
+	Optional bool     `json:"optional,omitempty"`
+	Details  []string `json:"details,omitempty"`
+}
+
+// requiredDevicesCheck reports the required devices that are not connected.
+func requiredDevicesCheck(devices []mqtt.DeviceStatus) ReadinessCheck {
+	check := ReadinessCheck{Status: "ok"}
+	for _, dev := range devices {
+		switch {
+		case !dev.Required || dev.Connected:
+			continue
+		case !dev.Registered:
+			check.Details = append(check.Details, fmt.Sprintf("required device '%s' not registered", dev.LogicalID))
+		case dev.LastSeen != nil:
+			check.Details = append(check.Details, fmt.Sprintf("required device '%s' disconnected (last seen %s)",
+				dev.LogicalID, dev.LastSeen.UTC().Format(time.RFC3339)))
+		default:
+			check.Details = append(check.Details, fmt.Sprintf("required device '%s' disconnected", dev.LogicalID))
+		}
+		check.Status = "warning"
+	}
+	return check
 }
 
 // RuntimeController provides node validation, operator control, and game lifecycle.
@@ -69,10 +96,32 @@ type RuntimeController interface {
 	HasNode(nodeID string) bool
 	OverrideNode(nodeID string) error
 	ResetNode(nodeID string) error
+	ResetNodeCascade(nodeID string, cascade bool) error
 	ResetToNode(nodeID string) error
+	ResetToCheckpoint(name string) error
+	ApproveNode(nodeID string) error
+	PendingApprovals() []string
+	UndoLastAction() (orchestrator.OperatorAction, error)
+	InjectEvent(name string, fields map[string]interface{})
 	StartGame(sceneID string) error
 	StopGame() error
 	IsGameActive() bool
+	GameClock() orchestrator.ClockState
+	PauseClock() error
+	ResumeClock() error
+	AdjustClock(delta time.Duration) error
+	PauseGame() error
+	ResumeGame() error
+	AdvanceScene(sceneID string) error
+	JumpTo(sceneID, nodeID string) error
+	GiveHint(nodeID string, index int) (int, error)
+	Mode() orchestrator.RunMode
+	SetMode(mode orchestrator.RunMode, by string) error
+	ActiveSceneID() string
+	GameDuration() time.Duration
+	EstimatePace(baseline orchestrator.PaceBaseline) *orchestrator.PaceEstimate
+	Snapshot() orchestrator.RuntimeSnapshot
+	SessionReport(sessionID string) *orchestrator.SessionReport
 }
 
 var runtimeController RuntimeController
@@ -146,15 +195,33 @@ func readyHandler(w http.ResponseWriter, r *http.Request) {
 		notReadyReasons = append(notReadyReasons, "postgres not connected")
 	}
 
+	// Required devices (devices.yaml) that are not connected block game start
+	// but never readiness, so a prop going offline does not restart the engine
+	if deviceInventory != nil {
+		checks["devices"] = requiredDevicesCheck(deviceInventory.Devices())
+	}
+
+	// Scene graph devices missing from devices.yaml (never affects readiness;
+	// ops.strict_device_refs refuses to start instead)
+	if devicesReloader != nil {
+		check := ReadinessCheck{Status: "ok"}
+		for _, ref := range devicesReloader.UnknownDeviceRefs() {
+			check.Status = "warning"
+			check.Details = append(check.Details, ref.Problem())
+		}
+		checks["device_refs"] = check
+	}
+
 	// Overall readiness: orchestrator must be ready, plus any non-optional dependencies
 	isReady := orchestratorReady &&
 		(mqttConnected || mqttOptional) &&
 		(postgresConnected || postgresOptional)
 
 	resp := ReadinessResponse{
-		Ready:   isReady,
-		Version: version.Version,
-		Checks:  checks,
+		Ready:    isReady,
+		Version:  version.Version,
+		Checks:   checks,
+		Warnings: warningCounts(),
 	}
 
 	if !isReady && len(notReadyReasons) > 0 {
@@ -173,50 +240,179 @@ func readyHandler(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
+// eventsHandler serves GET /events: the buffered events, oldest first.
+// ?after=<seq> returns only newer events and ?limit=<n> caps the page (the
+// oldest n after seq, else the newest n), so clients can poll or page through
+// a large buffer without copying all of it.
 func eventsHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(events.Snapshot())
+
+	q := r.URL.Query()
+	limit := 0
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = n
+	}
+
+	if v := q.Get("after"); v != "" {
+		seq, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "after must be an event seq"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(events.After(seq, limit))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(events.RecentEvents(limit))
+}
+
+// eventBySeqHandler serves GET /events/{seq}, a stable link to one event.
+// Recent events come from the in-memory buffer, older ones from Postgres.
+func eventBySeqHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	seq, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, "/events/"), 10, 64)
+	if err != nil || seq == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid event seq"})
+		return
+	}
+
+	if e, ok := events.Lookup(seq); ok {
+		_ = json.NewEncoder(w).Encode(e)
+		return
+	}
+
+	if client := events.GetStore(); client != nil {
+		row, err := client.EventBySeq(seq)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		if row != nil {
+			e := events.Event{
+				Seq:       uint64(row.Seq),
+				Timestamp: row.Timestamp.UTC().Format(time.RFC3339Nano),
+				Level:     row.Level,
+				Name:      row.Event,
+				Fields:    row.Fields,
+			}
+			if row.Message != nil {
+				e.Message = *row.Message
+			}
+			_ = json.NewEncoder(w).Encode(e)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": "event not found"})
 }
 
 const maxEventsDBLimit = 1000
 
+// eventsDBHandler returns stored events, newest first. Query parameters:
+//   - limit (default 200, max 1000), offset
+//   - cursor: event_id of the last row of the previous page
+//   - event: event name prefix, level, session_id, node_id
+//   - since, until: RFC 3339 timestamps
+//
+// When a page is full, X-Next-Cursor holds the cursor for the next one.
 func eventsDBHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	client := events.GetPostgresClient()
+	client := events.GetStore()
 	if client == nil {
 		w.WriteHeader(http.StatusServiceUnavailable)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": "postgres not available"})
 		return
 	}
 
-	limit := 200
-	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		l, err := strconv.Atoi(limitStr)
-		if err != nil || l <= 0 {
-			w.WriteHeader(http.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid limit parameter"})
-			return
-		}
-		limit = l
-	}
-	// Clamp to max
-	if limit > maxEventsDBLimit {
-		limit = maxEventsDBLimit
+	filter, err := parseEventFilter(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
 	}
 
-	rows, err := client.Query(limit)
+	rows, err := client.Query(filter)
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 		return
 	}
-
+	if len(rows) == filter.Limit {
+		w.Header().Set("X-Next-Cursor", strconv.FormatInt(rows[len(rows)-1].EventID, 10))
+	}
 	_ = json.NewEncoder(w).Encode(rows)
 }
 
+// parseEventFilter reads /events/db query parameters.
+func parseEventFilter(q url.Values) (storage.EventFilter, error) {
+	filter := storage.EventFilter{
+		Limit:       200,
+		EventPrefix: q.Get("event"),
+		Level:       q.Get("level"),
+		SessionID:   q.Get("session_id"),
+		NodeID:      q.Get("node_id"),
+	}
+
+	if limitStr := q.Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 {
+			return filter, fmt.Errorf("invalid limit parameter")
+		}
+		filter.Limit = l
+	}
+	// Clamp to max
+	if filter.Limit > maxEventsDBLimit {
+		filter.Limit = maxEventsDBLimit
+	}
+	if offsetStr := q.Get("offset"); offsetStr != "" {
+		o, err := strconv.Atoi(offsetStr)
+		if err != nil || o < 0 {
+			return filter, fmt.Errorf("invalid offset parameter")
+		}
+		filter.Offset = o
+	}
+	if cursorStr := q.Get("cursor"); cursorStr != "" {
+		c, err := strconv.ParseInt(cursorStr, 10, 64)
+		if err != nil || c <= 0 {
+			return filter, fmt.Errorf("invalid cursor parameter")
+		}
+		filter.Before = c
+	}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"since", &filter.Since}, {"until", &filter.Until}} {
+		if v := q.Get(p.name); v != "" {
+			ts, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s parameter: expected RFC 3339 timestamp", p.name)
+			}
+			*p.dst = ts
+		}
+	}
+	return filter, nil
+}
+
 type OperatorRequest struct {
-	NodeID string `json:"node_id"`
+	NodeID  string `json:"node_id"`
+	Cascade *bool  `json:"cascade,omitempty"` // reset only; overrides the room default
 }
 
 type OperatorResponse struct {
@@ -295,13 +491,25 @@ func operatorResetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !requireConfirm(w, r, "reset:"+req.NodeID) {
+		return
+	}
+
 	// Emit operator event
-	events.Emit("info", "operator.reset", "", map[string]interface{}{
-		"node_id": req.NodeID,
-	})
+	fields := map[string]interface{}{"node_id": req.NodeID}
+	if req.Cascade != nil {
+		fields["cascade"] = *req.Cascade
+	}
+	events.Emit("info", "operator.reset", "", fields)
 
 	// Apply reset to runtime
-	if err := runtimeController.ResetNode(req.NodeID); err != nil {
+	var err error
+	if req.Cascade != nil {
+		err = runtimeController.ResetNodeCascade(req.NodeID, *req.Cascade)
+	} else {
+		err = runtimeController.ResetNode(req.NodeID)
+	}
+	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: err.Error()})
 		return
@@ -350,6 +558,10 @@ func operatorResetNodeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !requireConfirm(w, r, "reset_to_node:"+req.NodeID) {
+		return
+	}
+
 	// Emit operator.reset event (registry-approved)
 	events.Emit("info", "operator.reset", "", map[string]interface{}{
 		"node_id": req.NodeID,
@@ -368,6 +580,7 @@ func operatorResetNodeHandler(w http.ResponseWriter, r *http.Request) {
 
 type GameStartRequest struct {
 	SceneID string `json:"scene_id"`
+	Team    string `json:"team,omitempty"` // shown on the stream overlay
 }
 
 type GameResponse struct {
@@ -390,6 +603,12 @@ func gameStartHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Starting over ends an active session, so it needs the same confirmation
+	// as stopping it
+	if !requireConfirm(w, r, "start") {
+		return
+	}
+
 	var req GameStartRequest
 	// Allow empty body (optional scene_id)
 	_ = json.NewDecoder(r.Body).Decode(&req)
@@ -399,6 +618,7 @@ func gameStartHandler(w http.ResponseWriter, r *http.Request) {
 		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: err.Error()})
 		return
 	}
+	setOverlayTeam(req.Team)
 
 	_ = json.NewEncoder(w).Encode(GameResponse{OK: true})
 }
@@ -418,11 +638,16 @@ func gameStopHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !requireConfirm(w, r, "stop") {
+		return
+	}
+
 	if err := runtimeController.StopGame(); err != nil {
 		w.WriteHeader(http.StatusBadRequest)
 		_ = json.NewEncoder(w).Encode(GameResponse{OK: false, Error: err.Error()})
 		return
 	}
+	setOverlayTeam("")
 
 	_ = json.NewEncoder(w).Encode(GameResponse{OK: true})
 }
@@ -442,19 +667,72 @@ func NewServer(port int) *http.Server {
 	mux.HandleFunc("/health", healthHandler)
 	mux.HandleFunc("/ready", readyHandler)
 	mux.HandleFunc("/metrics", metricsHandler)
+	mux.HandleFunc("/openapi.json", openAPIHandler)
+	mux.HandleFunc("/capabilities", capabilitiesHandler) // read by the UI before sign-in
 	mux.HandleFunc("/events", eventsHandler)
 	mux.HandleFunc("/events/db", eventsDBHandler)
+	mux.HandleFunc("/events/", eventBySeqHandler)
+	mux.HandleFunc("/trigger/", triggerHandler)                   // token is the credential
+	mux.HandleFunc("/federation/events", federationEventsHandler) // federation token is the credential
+	mux.HandleFunc("/overlay", overlayHandler)                    // spoiler-free stream overlay
+	mux.HandleFunc("/ws/overlay", wsOverlayHandler)
 
 	// Protected endpoints (admin OR operator)
 	mux.HandleFunc("/operator/override", RequireAnyRole(operatorOverrideHandler))
 	mux.HandleFunc("/operator/reset", RequireAnyRole(operatorResetHandler))
 	mux.HandleFunc("/operator/reset-node", RequireAnyRole(operatorResetNodeHandler))
+	mux.HandleFunc("/operator/reset-to-checkpoint", RequireAnyRole(operatorResetToCheckpointHandler))
+	mux.HandleFunc("/operator/jump", RequireAnyRole(operatorJumpHandler))
+	mux.HandleFunc("/operator/approve", RequireAnyRole(operatorApproveHandler))
+	mux.HandleFunc("/operator/hint", RequireAnyRole(operatorHintHandler))
+	mux.HandleFunc("/operator/undo", RequireAnyRole(operatorUndoHandler))
+	mux.HandleFunc("/operator/message", RequireAnyRole(operatorMessageHandler))
 	mux.HandleFunc("/ws/events", RequireAnyRole(wsEventsHandler))
 	mux.HandleFunc("/ui", RequireAnyRole(uiHandler))
+	mux.HandleFunc("/reports/difficulty", RequireAnyRole(difficultyReportHandler))
+	mux.HandleFunc("/sessions", RequireAnyRole(sessionsHandler))
+	mux.HandleFunc("/sessions/", RequireAnyRole(sessionHandler))
+	mux.HandleFunc("/state", RequireAnyRole(stateHandler))
+	mux.HandleFunc("/graph", RequireAnyRole(graphHandler))
+	mux.HandleFunc("/graph/analysis", RequireAnyRole(graphAnalysisHandler))
+	mux.HandleFunc("/graph/validation", RequireAnyRole(graphValidationHandler))
+	mux.HandleFunc("/graph/simulate", RequireAnyRole(graphSimulateHandler))
+	mux.HandleFunc("/nodes", RequireAnyRole(nodesHandler))
+	mux.HandleFunc("/scenes", RequireAnyRole(scenesHandler))
+	mux.HandleFunc("/game/clock", RequireAnyRole(gameClockHandler))
+	mux.HandleFunc("/game/state", RequireAnyRole(gameStateHandler))
+	mux.HandleFunc("/game/pause", RequireAnyRole(gamePauseHandler))
+	mux.HandleFunc("/game/resume", RequireAnyRole(gameResumeHandler))
+	mux.HandleFunc("/game/scene", RequireAnyRole(gameSceneHandler))
+	mux.HandleFunc("/game/input", RequireAnyRole(gameInputHandler))
+	mux.HandleFunc("/mode", RequireAnyRole(modeHandler))
+	mux.HandleFunc("/warnings", RequireAnyRole(warningsHandler))
+	mux.HandleFunc("/competition/standings", RequireAnyRole(competitionStandingsHandler))
+	mux.HandleFunc("/operator/clock/pause", RequireAnyRole(operatorClockPauseHandler))
+	mux.HandleFunc("/operator/clock/resume", RequireAnyRole(operatorClockResumeHandler))
+	mux.HandleFunc("/operator/clock/adjust", RequireAnyRole(operatorClockAdjustHandler))
+	mux.HandleFunc("/maintenance/report", RequireAnyRole(maintenanceReportHandler))
+	mux.HandleFunc("/devices", RequireAnyRole(devicesHandler))
+	mux.HandleFunc("/devices/", RequireAnyRole(deviceStateHandler))
+	mux.HandleFunc("/controllers", RequireAnyRole(controllersHandler))
+	mux.HandleFunc("/controllers/", RequireAnyRole(controllerHandler))
+	mux.HandleFunc("/handover", RequireAnyRole(handoverHandler))
+	mux.HandleFunc("/operator/alerts/ack", RequireAnyRole(alertAckHandler))
+	mux.HandleFunc("/prefs", RequireAnyRole(prefsHandler))
+	mux.HandleFunc("/prefs/", RequireAnyRole(prefHandler))
 
 	// Admin-only endpoints
 	mux.HandleFunc("/game/start", RequireAdmin(gameStartHandler))
 	mux.HandleFunc("/game/stop", RequireAdmin(gameStopHandler))
+	mux.HandleFunc("/competition/arm", RequireAdmin(competitionArmHandler))
+	mux.HandleFunc("/competition/cancel", RequireAdmin(competitionCancelHandler))
+	mux.HandleFunc("/admin/graph/diff", RequireAdmin(graphDiffHandler))
+	mux.HandleFunc("/admin/maintenance/start", RequireAdmin(maintenanceStartHandler))
+	mux.HandleFunc("/admin/maintenance/stop", RequireAdmin(maintenanceStopHandler))
+	mux.HandleFunc("/admin/mode", RequireAdmin(setModeHandler))
+	mux.HandleFunc("/admin/devices/asset", RequireAdmin(deviceAssetHandler))
+	mux.HandleFunc("/admin/devices/reload", RequireAdmin(devicesReloadHandler))
+	mux.HandleFunc("/admin/triggers", RequireAdmin(adminTriggersHandler))
 
 	return &http.Server{
 		Addr:    fmt.Sprintf(":%d", port),
@@ -545,6 +823,7 @@ func Start(port int) {
 func Shutdown(srv *http.Server, timeout time.Duration) error {
 	// Close all WebSocket connections first
 	events.CloseAllSubscribers()
+	closeOverlays()
 
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
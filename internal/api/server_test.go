@@ -4,7 +4,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
 )
 
 // clearTLSEnvServer prevents TLS initialization from trying to load nonexistent certs.
@@ -297,3 +303,125 @@ func TestSetReadinessState(t *testing.T) {
 	}
 	readiness.mu.RUnlock()
 }
+
+func TestEventBySeqEndpoint(t *testing.T) {
+	events.Clear()
+	events.Emit("info", "node.started", "", map[string]interface{}{"node_id": "first"})
+	events.Emit("info", "node.started", "", map[string]interface{}{"node_id": "second"})
+
+	snap := events.Snapshot()
+	if len(snap) != 2 || snap[0].Seq == 0 || snap[1].Seq != snap[0].Seq+1 {
+		t.Fatalf("expected consecutive seqs, got %+v", snap)
+	}
+	seq := strconv.FormatUint(snap[0].Seq, 10)
+
+	w := httptest.NewRecorder()
+	eventBySeqHandler(w, httptest.NewRequest("GET", "/events/"+seq, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var e events.Event
+	if err := json.Unmarshal(w.Body.Bytes(), &e); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if e.Seq != snap[0].Seq || e.Fields["node_id"] != "first" {
+		t.Errorf("expected event %s for node first, got %+v", seq, e)
+	}
+
+	tests := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{"GET", "/events/999999999", http.StatusNotFound},
+		{"GET", "/events/abc", http.StatusBadRequest},
+		{"GET", "/events/0", http.StatusBadRequest},
+		{"POST", "/events/" + seq, http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		eventBySeqHandler(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.want {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.path, tt.want, w.Code)
+		}
+	}
+}
+
+func TestEventsEndpoint_Paging(t *testing.T) {
+	events.Clear()
+	for i := 0; i < 5; i++ {
+		events.Emit("info", "node.started", "", map[string]interface{}{"i": i})
+	}
+	snap := events.Snapshot()
+	first := snap[0].Seq
+
+	get := func(path string) []events.Event {
+		t.Helper()
+		w := httptest.NewRecorder()
+		eventsHandler(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", path, w.Code, w.Body.String())
+		}
+		var out []events.Event
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+			t.Fatalf("%s: failed to parse response: %v", path, err)
+		}
+		return out
+	}
+
+	if all := get("/events"); len(all) != 5 {
+		t.Errorf("expected all 5 events, got %d", len(all))
+	}
+	if newest := get("/events?limit=2"); len(newest) != 2 || newest[1].Seq != snap[4].Seq {
+		t.Errorf("expected the newest 2 events, got %+v", newest)
+	}
+	page := get("/events?after=" + strconv.FormatUint(first, 10) + "&limit=2")
+	if len(page) != 2 || page[0].Seq != first+1 || page[1].Seq != first+2 {
+		t.Errorf("expected the 2 events after %d, got %+v", first, page)
+	}
+	if rest := get("/events?after=" + strconv.FormatUint(snap[4].Seq, 10)); len(rest) != 0 {
+		t.Errorf("expected nothing after the newest event, got %+v", rest)
+	}
+
+	for _, path := range []string{"/events?limit=0", "/events?after=x"} {
+		w := httptest.NewRecorder()
+		eventsHandler(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", path, w.Code)
+		}
+	}
+}
+
+func TestParseEventFilter(t *testing.T) {
+	q, _ := url.ParseQuery("event=puzzle.&level=warning&session_id=s1&node_id=puzzle_scarab" +
+		"&since=2026-01-02T15:00:00Z&until=2026-01-02T16:00:00Z&limit=5000&offset=20&cursor=42")
+	filter, err := parseEventFilter(q)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := storage.EventFilter{
+		Limit:       maxEventsDBLimit,
+		Offset:      20,
+		Before:      42,
+		EventPrefix: "puzzle.",
+		Level:       "warning",
+		Since:       time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC),
+		Until:       time.Date(2026, 1, 2, 16, 0, 0, 0, time.UTC),
+		SessionID:   "s1",
+		NodeID:      "puzzle_scarab",
+	}
+	if filter != want {
+		t.Errorf("expected %+v, got %+v", want, filter)
+	}
+
+	if filter, err := parseEventFilter(url.Values{}); err != nil || filter.Limit != 200 {
+		t.Errorf("expected default limit 200, got %+v (%v)", filter, err)
+	}
+
+	for _, bad := range []string{"limit=0", "offset=-1", "cursor=abc", "since=yesterday", "until=2026-01-02"} {
+		q, _ := url.ParseQuery(bad)
+		if _, err := parseEventFilter(q); err == nil {
+			t.Errorf("%s: expected error", bad)
+		}
+	}
+}
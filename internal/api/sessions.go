@@ -0,0 +1,182 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// Default and maximum page sizes for GET /sessions.
+const (
+	defaultSessionsLimit = 50
+	maxSessionsLimit     = 1000
+)
+
+// SessionsResponse is returned by GET /sessions.
+type SessionsResponse struct {
+	CurrentSessionID string               `json:"current_session_id,omitempty"`
+	Sessions         []storage.SessionRow `json:"sessions"`
+}
+
+// sessionsHandler lists recorded game sessions, newest first.
+func sessionsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	client := events.GetStore()
+	if client == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "postgres not available"})
+		return
+	}
+
+	q := r.URL.Query()
+	limit, offset := defaultSessionsLimit, 0
+	if limitStr := q.Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid limit parameter"})
+			return
+		}
+		limit = min(l, maxSessionsLimit)
+	}
+	if offsetStr := q.Get("offset"); offsetStr != "" {
+		o, err := strconv.Atoi(offsetStr)
+		if err != nil || o < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid offset parameter"})
+			return
+		}
+		offset = o
+	}
+
+	sessions, err := client.Sessions(limit, offset)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if sessions == nil {
+		sessions = []storage.SessionRow{}
+	}
+	_ = json.NewEncoder(w).Encode(SessionsResponse{
+		CurrentSessionID: events.CurrentSession(),
+		Sessions:         sessions,
+	})
+}
+
+// sessionHandler serves GET /sessions/{id}, GET /sessions/{id}/events and
+// GET /sessions/{id}/summary. The events endpoint takes the /events/db filters
+// and pages the same way. The summary of the running or last session comes
+// from the runtime; older sessions are rebuilt from their stored events.
+func sessionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	id, sub, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/sessions/"), "/")
+	if id == "" || (sub != "" && sub != "events" && sub != "summary") {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "not found"})
+		return
+	}
+
+	if sub == "summary" && runtimeController != nil {
+		if report := runtimeController.SessionReport(id); report != nil {
+			_ = json.NewEncoder(w).Encode(report)
+			return
+		}
+	}
+
+	client := events.GetStore()
+	if client == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "postgres not available"})
+		return
+	}
+
+	if sub == "" {
+		session, err := client.Session(id)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		if session == nil {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "session not found"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(session)
+		return
+	}
+
+	if sub == "summary" {
+		report, err := storedSessionReport(client, id)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+		if report == nil {
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "session not found"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(report)
+		return
+	}
+
+	filter, err := parseEventFilter(r.URL.Query())
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	filter.SessionID = id
+
+	rows, err := client.Query(filter)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	if len(rows) == filter.Limit {
+		w.Header().Set("X-Next-Cursor", strconv.FormatInt(rows[len(rows)-1].EventID, 10))
+	}
+	_ = json.NewEncoder(w).Encode(rows)
+}
+
+// storedSessionReport rebuilds a session's summary from every stored event of
+// the session, or returns nil if none were stored.
+func storedSessionReport(client storage.Store, id string) (*orchestrator.SessionReport, error) {
+	filter := storage.EventFilter{SessionID: id, Limit: maxSessionsLimit}
+	var rows []storage.EventRow
+	for {
+		page, err := client.Query(filter)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, page...)
+		if len(page) < filter.Limit {
+			break
+		}
+		filter.Before = page[len(page)-1].EventID
+	}
+	return orchestrator.BuildSessionReport(id, rows), nil
+}
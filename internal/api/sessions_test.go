@@ -0,0 +1,47 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+func TestSessionSummaryHandler(t *testing.T) {
+	sg, err := orchestrator.LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	rt := orchestrator.NewRuntime(sg)
+	SetRuntimeController(rt)
+	defer SetRuntimeController(nil)
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+	sessionID := events.CurrentSession()
+
+	w := httptest.NewRecorder()
+	sessionHandler(w, httptest.NewRequest("GET", "/sessions/"+sessionID+"/summary", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var report orchestrator.SessionReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if report.SessionID != sessionID || report.SceneID == "" || report.EndedAt != nil {
+		t.Errorf("unexpected summary: %+v", report)
+	}
+
+	// Older sessions need Postgres
+	w = httptest.NewRecorder()
+	sessionHandler(w, httptest.NewRequest("GET", "/sessions/unknown/summary", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 without postgres, got %d", w.Code)
+	}
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// WatchStalls sends a warning alert to the webhook whenever the runtime
+// reports a stalled game (scene.stalled), so gamemasters notice stuck teams.
+// It returns a function that stops watching.
+func WatchStalls() func() {
+	return events.AddListener(func(e events.Event) {
+		if e.Name != "scene.stalled" {
+			return
+		}
+		sceneID, _ := e.Fields["scene_id"].(string)
+		details := map[string]interface{}{
+			"scene_id": sceneID,
+			"idle_ms":  e.Fields["idle_ms"],
+			"puzzles":  e.Fields["puzzles"],
+		}
+		if e.SessionID != "" {
+			details["session_id"] = e.SessionID
+		}
+		msg := fmt.Sprintf("No puzzle progress in scene %s", sceneID)
+		if idle, ok := e.Fields["idle_ms"].(int64); ok {
+			msg = fmt.Sprintf("No puzzle progress in scene %s for %d minutes", sceneID, idle/60000)
+		}
+		SendAlert(AlertSessionStalled, SeverityWarning, msg, details)
+	})
+}
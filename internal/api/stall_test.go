@@ -0,0 +1,41 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+func TestWatchStalls(t *testing.T) {
+	alertMu.Lock()
+	savedLog, savedConfig := alertLog, *alertConfig
+	alertLog = nil
+	alertConfig.WebhookURL = ""
+	alertMu.Unlock()
+	defer func() {
+		alertMu.Lock()
+		alertLog, *alertConfig = savedLog, savedConfig
+		alertMu.Unlock()
+	}()
+	events.Clear()
+	defer events.Clear()
+
+	stop := WatchStalls()
+	since := time.Now().Add(-time.Minute)
+	events.Emit("info", "scene.stalled", "", map[string]interface{}{
+		"scene_id": "scene_intro",
+		"idle_ms":  int64(10 * time.Minute / time.Millisecond),
+		"puzzles":  []string{"puzzle_scarab"},
+	})
+	stop()
+	events.Emit("info", "scene.stalled", "", map[string]interface{}{"scene_id": "scene_intro"})
+
+	alerts := UnackedAlerts(since)
+	if len(alerts) != 1 || alerts[0].Event != AlertSessionStalled || alerts[0].Severity != SeverityWarning {
+		t.Fatalf("expected one session_stalled warning, got %+v", alerts)
+	}
+	if alerts[0].Message != "No puzzle progress in scene scene_intro for 10 minutes" {
+		t.Errorf("unexpected message %q", alerts[0].Message)
+	}
+}
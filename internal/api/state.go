@@ -0,0 +1,129 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+// paceBaselineTTL bounds how often historical solve times are re-read from Postgres.
+const paceBaselineTTL = 5 * time.Minute
+
+// paceCache holds the historical difficulty report and the baseline derived
+// from it for live pace estimates and duration simulations.
+var paceCache = struct {
+	mu       sync.Mutex
+	report   *orchestrator.DifficultyReport
+	baseline orchestrator.PaceBaseline
+	builtAt  time.Time
+}{}
+
+// StateResponse is returned by the /state endpoint.
+type StateResponse struct {
+	GameActive bool                       `json:"game_active"`
+	SceneID    string                     `json:"scene_id,omitempty"`
+	Clock      orchestrator.ClockState    `json:"clock"`
+	Pace       *orchestrator.PaceEstimate `json:"pace,omitempty"`
+}
+
+// currentPaceBaseline returns cached historical medians, rebuilding them when stale.
+// Returns an empty baseline if Postgres or the scene graph is unavailable.
+func currentPaceBaseline() orchestrator.PaceBaseline {
+	paceCache.mu.Lock()
+	defer paceCache.mu.Unlock()
+
+	refreshPaceCache()
+	if paceCache.baseline == nil {
+		return orchestrator.PaceBaseline{}
+	}
+	return paceCache.baseline
+}
+
+// currentDifficultyReport returns the cached historical difficulty report, or
+// nil if Postgres or the scene graph is unavailable.
+func currentDifficultyReport() *orchestrator.DifficultyReport {
+	paceCache.mu.Lock()
+	defer paceCache.mu.Unlock()
+
+	refreshPaceCache()
+	return paceCache.report
+}
+
+// refreshPaceCache rebuilds the report and baseline when stale, keeping the
+// previous ones if Postgres fails. Caller must hold paceCache.mu.
+func refreshPaceCache() {
+	if paceCache.report != nil && time.Since(paceCache.builtAt) < paceBaselineTTL {
+		return
+	}
+
+	client := events.GetStore()
+	graphState.mu.RLock()
+	sg := graphState.active
+	graphState.mu.RUnlock()
+	if client == nil || sg == nil {
+		return
+	}
+
+	rows, err := client.QueryByNames(orchestrator.DifficultyEventNames, defaultReportEventLimit)
+	if err != nil {
+		log.Printf("[state] failed to load pace baseline: %v", err)
+		return
+	}
+
+	paceCache.report = orchestrator.BuildDifficultyReport(rows, sg)
+	paceCache.baseline = orchestrator.NewPaceBaseline(paceCache.report)
+	paceCache.builtAt = time.Now()
+}
+
+// stateHandler returns live session state with a finish-time estimate.
+func stateHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	if runtimeController == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "runtime not available"})
+		return
+	}
+
+	resp := StateResponse{
+		GameActive: runtimeController.IsGameActive(),
+		SceneID:    runtimeController.ActiveSceneID(),
+		Clock:      runtimeController.GameClock(),
+	}
+	if resp.GameActive {
+		resp.Pace = runtimeController.EstimatePace(currentPaceBaseline())
+	}
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// gameStateHandler returns the full runtime snapshot: active scene, node states,
+// puzzle resolutions, pending timers and session metadata.
+func gameStateHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	if runtimeController == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "runtime not available"})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(runtimeController.Snapshot())
+}
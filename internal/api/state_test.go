@@ -0,0 +1,114 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+func TestStateHandler(t *testing.T) {
+	sg, err := orchestrator.LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	rt := orchestrator.NewRuntime(sg)
+	SetRuntimeController(rt)
+	defer SetRuntimeController(nil)
+
+	w := httptest.NewRecorder()
+	stateHandler(w, httptest.NewRequest("GET", "/state", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var resp StateResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.GameActive || resp.Pace != nil {
+		t.Errorf("expected idle state without pace, got %+v", resp)
+	}
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+
+	w = httptest.NewRecorder()
+	stateHandler(w, httptest.NewRequest("GET", "/state", nil))
+	resp = StateResponse{}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !resp.GameActive || resp.SceneID != "scene_intro" {
+		t.Errorf("expected active scene_intro, got %+v", resp)
+	}
+	// No Postgres history in tests, so the light is unknown
+	if resp.Pace == nil || resp.Pace.Light != orchestrator.PaceUnknown || resp.Pace.PuzzlesRemaining != 2 {
+		t.Errorf("unexpected pace estimate: %+v", resp.Pace)
+	}
+}
+
+func TestGameStateHandler(t *testing.T) {
+	sg, err := orchestrator.LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	rt := orchestrator.NewRuntime(sg)
+	SetRuntimeController(rt)
+	defer SetRuntimeController(nil)
+
+	w := httptest.NewRecorder()
+	gameStateHandler(w, httptest.NewRequest("POST", "/game/state", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+	if err := rt.OverrideNode("puzzle_scarab"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+
+	w = httptest.NewRecorder()
+	gameStateHandler(w, httptest.NewRequest("GET", "/game/state", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	var snap orchestrator.RuntimeSnapshot
+	if err := json.NewDecoder(w.Body).Decode(&snap); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !snap.GameActive || snap.SceneID != "scene_intro" || snap.SceneStartedAt == nil || !snap.Clock.Running {
+		t.Errorf("expected running scene_intro, got %+v", snap)
+	}
+
+	states := make(map[string]orchestrator.NodeState)
+	for _, n := range snap.Nodes {
+		states[n.ID] = n.State
+	}
+	if states["start_parallel"] != orchestrator.NodeStateActive || states["puzzle_scarab"] != orchestrator.NodeStateOverridden ||
+		states["scene_complete"] != orchestrator.NodeStateIdle {
+		t.Errorf("unexpected node states: %v", states)
+	}
+
+	resolutions := make(map[string]orchestrator.PuzzleResolution)
+	for _, p := range snap.Puzzles {
+		resolutions[p.NodeID] = p.Resolution
+	}
+	if resolutions["puzzle_scarab"] != orchestrator.PuzzleOverridden || resolutions["puzzle_tiles"] != orchestrator.PuzzleUnresolved {
+		t.Errorf("unexpected puzzle resolutions: %v", resolutions)
+	}
+
+	timers := make(map[string]bool)
+	for _, tm := range snap.Timers {
+		timers[tm.Kind+":"+tm.ID] = tm.RemainingMs > 0
+	}
+	if !timers["clock:"+orchestrator.GameClockID] || !timers["loop:loop_ambience"] {
+		t.Errorf("expected the countdown and ambience loop to be pending, got %+v", snap.Timers)
+	}
+}
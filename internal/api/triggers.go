@@ -0,0 +1,272 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// DefaultTriggerTTL is how long a trigger token stays valid when no TTL is given.
+const DefaultTriggerTTL = 24 * time.Hour
+
+// TriggerStore persists trigger tokens (implemented by storage.Store).
+type TriggerStore interface {
+	SaveTriggerToken(tokenHash string, trigger []byte, ts time.Time) error
+	DeleteTriggerToken(tokenHash string) error
+	LoadTriggerTokens() (map[string][]byte, error)
+}
+
+// TriggerToken lets an external system inject one device input without operator credentials.
+// The secret itself is only returned on creation; tokens are stored by hash.
+type TriggerToken struct {
+	ID        string      `json:"id"`
+	Name      string      `json:"name"`
+	LogicalID string      `json:"logical_id"`
+	Payload   interface{} `json:"payload,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	ExpiresAt time.Time   `json:"expires_at"`
+	MaxUses   int         `json:"max_uses,omitempty"`
+	Uses      int         `json:"uses"`
+}
+
+// usable reports whether the token may still be called at now.
+func (t *TriggerToken) usable(now time.Time) bool {
+	if !now.Before(t.ExpiresAt) {
+		return false
+	}
+	return t.MaxUses == 0 || t.Uses < t.MaxUses
+}
+
+// TriggerCreateRequest defines a new trigger token.
+type TriggerCreateRequest struct {
+	Name       string      `json:"name"`
+	LogicalID  string      `json:"logical_id"`
+	Payload    interface{} `json:"payload,omitempty"`
+	TTLMinutes int         `json:"ttl_minutes,omitempty"`
+	MaxUses    int         `json:"max_uses,omitempty"`
+}
+
+// TriggerCreateResponse returns the secret token once.
+type TriggerCreateResponse struct {
+	Token   string        `json:"token"`
+	Trigger *TriggerToken `json:"trigger"`
+}
+
+// TriggersResponse is returned by GET /admin/triggers.
+type TriggersResponse struct {
+	Triggers []TriggerToken `json:"triggers"`
+}
+
+var (
+	triggerMu    sync.Mutex
+	triggers     = make(map[string]*TriggerToken) // keyed by token hash
+	triggerStore TriggerStore
+)
+
+// SetTriggerStore sets where trigger tokens are persisted and loads stored tokens.
+// Tokens created before the store was attached (while it was unreachable)
+// are saved to it.
+func SetTriggerStore(store TriggerStore) error {
+	stored, err := store.LoadTriggerTokens()
+	if err != nil {
+		return err
+	}
+
+	triggerMu.Lock()
+	defer triggerMu.Unlock()
+
+	triggerStore = store
+	for hash, t := range triggers {
+		saveTriggerLocked(hash, t)
+	}
+	for hash, data := range stored {
+		if _, ok := triggers[hash]; ok {
+			continue
+		}
+		var t TriggerToken
+		if err := json.Unmarshal(data, &t); err != nil {
+			continue
+		}
+		triggers[hash] = &t
+	}
+	return nil
+}
+
+// hashTriggerToken returns the storage key for a secret token.
+func hashTriggerToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// saveTriggerLocked persists a token. Caller must hold triggerMu.
+func saveTriggerLocked(hash string, t *TriggerToken) {
+	if triggerStore == nil {
+		return
+	}
+	data, err := json.Marshal(t)
+	if err == nil {
+		err = triggerStore.SaveTriggerToken(hash, data, time.Now())
+	}
+	if err != nil {
+		events.Emit("error", "system.error", "failed to persist trigger token", map[string]interface{}{
+			"trigger": t.Name,
+			"error":   err.Error(),
+		})
+	}
+}
+
+// adminTriggersHandler lists (GET), creates (POST) or revokes (DELETE ?id=) trigger tokens.
+func adminTriggersHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.Method {
+	case http.MethodGet:
+		now := time.Now()
+		triggerMu.Lock()
+		list := []TriggerToken{}
+		for _, t := range triggers {
+			if t.usable(now) {
+				list = append(list, *t)
+			}
+		}
+		triggerMu.Unlock()
+		sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+		_ = json.NewEncoder(w).Encode(TriggersResponse{Triggers: list})
+
+	case http.MethodPost:
+		var req TriggerCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "invalid JSON"})
+			return
+		}
+		if req.Name == "" || req.LogicalID == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "name and logical_id required"})
+			return
+		}
+		if req.TTLMinutes < 0 || req.MaxUses < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "ttl_minutes and max_uses must not be negative"})
+			return
+		}
+
+		secret := make([]byte, 24)
+		if _, err := rand.Read(secret); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "failed to generate token"})
+			return
+		}
+		token := hex.EncodeToString(secret)
+		hash := hashTriggerToken(token)
+
+		ttl := DefaultTriggerTTL
+		if req.TTLMinutes > 0 {
+			ttl = time.Duration(req.TTLMinutes) * time.Minute
+		}
+		now := time.Now().UTC()
+		t := &TriggerToken{
+			ID:        hash[:12],
+			Name:      req.Name,
+			LogicalID: req.LogicalID,
+			Payload:   req.Payload,
+			CreatedAt: now,
+			ExpiresAt: now.Add(ttl),
+			MaxUses:   req.MaxUses,
+		}
+
+		triggerMu.Lock()
+		triggers[hash] = t
+		saveTriggerLocked(hash, t)
+		triggerMu.Unlock()
+
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(TriggerCreateResponse{Token: token, Trigger: t})
+
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "id required"})
+			return
+		}
+
+		triggerMu.Lock()
+		defer triggerMu.Unlock()
+		for hash, t := range triggers {
+			if t.ID != id {
+				continue
+			}
+			delete(triggers, hash)
+			if triggerStore != nil {
+				if err := triggerStore.DeleteTriggerToken(hash); err != nil {
+					w.WriteHeader(http.StatusInternalServerError)
+					_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: err.Error()})
+					return
+				}
+			}
+			_ = json.NewEncoder(w).Encode(OperatorResponse{OK: true})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "trigger not found"})
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "method not allowed"})
+	}
+}
+
+// triggerHandler injects the device input bound to POST /trigger/{token}.
+// The token is the only credential; unknown, expired and used-up tokens all return 404.
+func triggerHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	token := strings.TrimPrefix(r.URL.Path, "/trigger/")
+	hash := hashTriggerToken(token)
+
+	triggerMu.Lock()
+	t, ok := triggers[hash]
+	if !ok || token == "" || !t.usable(time.Now()) {
+		triggerMu.Unlock()
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "trigger not found"})
+		return
+	}
+
+	if runtimeController == nil || !runtimeController.IsGameActive() {
+		triggerMu.Unlock()
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(OperatorResponse{OK: false, Error: "no active session"})
+		return
+	}
+
+	t.Uses++
+	saveTriggerLocked(hash, t)
+	fields := map[string]interface{}{
+		"logical_id": t.LogicalID,
+		"payload":    t.Payload,
+		"trigger":    t.Name,
+	}
+	triggerMu.Unlock()
+
+	// Emit device.input event (registry-approved) and route it like controller input
+	events.Emit("info", "device.input", "", fields)
+	runtimeController.InjectEvent("device.input", fields)
+
+	_ = json.NewEncoder(w).Encode(OperatorResponse{OK: true})
+}
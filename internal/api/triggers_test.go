@@ -0,0 +1,144 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+// memTriggerStore keeps trigger tokens in memory.
+type memTriggerStore struct {
+	rows map[string][]byte
+}
+
+func (s *memTriggerStore) SaveTriggerToken(tokenHash string, trigger []byte, ts time.Time) error {
+	s.rows[tokenHash] = trigger
+	return nil
+}
+
+func (s *memTriggerStore) DeleteTriggerToken(tokenHash string) error {
+	delete(s.rows, tokenHash)
+	return nil
+}
+
+func (s *memTriggerStore) LoadTriggerTokens() (map[string][]byte, error) {
+	return s.rows, nil
+}
+
+func TestTriggerTokens(t *testing.T) {
+	events.Clear()
+	store := &memTriggerStore{rows: make(map[string][]byte)}
+	if err := SetTriggerStore(store); err != nil {
+		t.Fatalf("failed to set store: %v", err)
+	}
+	defer func() {
+		triggerMu.Lock()
+		triggers = make(map[string]*TriggerToken)
+		triggerStore = nil
+		triggerMu.Unlock()
+	}()
+
+	sg, err := orchestrator.LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	rt := orchestrator.NewRuntime(sg)
+	SetRuntimeController(rt)
+	defer SetRuntimeController(nil)
+
+	body := `{"name": "lobby_intercom", "logical_id": "intercom_button", "payload": "pressed", "max_uses": 1}`
+	w := httptest.NewRecorder()
+	adminTriggersHandler(w, httptest.NewRequest("POST", "/admin/triggers", strings.NewReader(body)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created TriggerCreateResponse
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if created.Token == "" || len(store.rows) != 1 {
+		t.Fatalf("expected token persisted by hash, got %+v", store.rows)
+	}
+	if _, ok := store.rows[created.Token]; ok {
+		t.Error("expected secret not to be stored in plain text")
+	}
+
+	call := func(token string) int {
+		w := httptest.NewRecorder()
+		triggerHandler(w, httptest.NewRequest("POST", "/trigger/"+token, nil))
+		return w.Code
+	}
+
+	if code := call("bogus"); code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown token, got %d", code)
+	}
+	if code := call(created.Token); code != http.StatusConflict {
+		t.Errorf("expected 409 without active session, got %d", code)
+	}
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+
+	if code := call(created.Token); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	var injected bool
+	for _, e := range events.Snapshot() {
+		if e.Name == "device.input" && e.Fields["trigger"] == "lobby_intercom" && e.Fields["logical_id"] == "intercom_button" {
+			injected = true
+		}
+	}
+	if !injected {
+		t.Error("expected device.input from trigger")
+	}
+
+	// max_uses exhausted
+	if code := call(created.Token); code != http.StatusNotFound {
+		t.Errorf("expected 404 once uses are exhausted, got %d", code)
+	}
+
+	w = httptest.NewRecorder()
+	adminTriggersHandler(w, httptest.NewRequest("DELETE", "/admin/triggers?id="+created.Trigger.ID, nil))
+	if w.Code != http.StatusOK || len(store.rows) != 0 {
+		t.Errorf("expected revoke to delete stored token, got %d %+v", w.Code, store.rows)
+	}
+}
+
+func TestTriggerStoreAttachedLate(t *testing.T) {
+	defer func() {
+		triggerMu.Lock()
+		triggers = make(map[string]*TriggerToken)
+		triggerStore = nil
+		triggerMu.Unlock()
+	}()
+
+	// Created while the store was unreachable
+	expires := time.Now().Add(time.Hour)
+	triggerMu.Lock()
+	triggers[hashTriggerToken("new")] = &TriggerToken{Name: "lobby_intercom", LogicalID: "intercom_button", ExpiresAt: expires}
+	triggerMu.Unlock()
+
+	old, _ := json.Marshal(TriggerToken{Name: "webhook", LogicalID: "crypt_door", ExpiresAt: expires})
+	store := &memTriggerStore{rows: map[string][]byte{hashTriggerToken("old"): old}}
+	if err := SetTriggerStore(store); err != nil {
+		t.Fatalf("failed to set store: %v", err)
+	}
+
+	triggerMu.Lock()
+	n := len(triggers)
+	triggerMu.Unlock()
+	if n != 2 {
+		t.Errorf("expected stored and new tokens merged, got %d", n)
+	}
+	if _, ok := store.rows[hashTriggerToken("new")]; !ok {
+		t.Error("expected the new token saved to the store")
+	}
+}
@@ -50,6 +50,7 @@ const operatorUIHTML = `<!DOCTYPE html>
         }
         .health-dot.ok { background: #22c55e; }
         .health-dot.err { background: #ef4444; }
+        .health-dot.warn { background: #f59e0b; }
         .health-label {
             font-size: 11px;
             color: #9ca3af;
@@ -76,6 +77,7 @@ const operatorUIHTML = `<!DOCTYPE html>
             border-left: 3px solid #0f3460;
             font-size: 13px;
             display: flex;
+            flex-wrap: wrap;
             gap: 12px;
             align-items: baseline;
         }
@@ -90,6 +92,25 @@ const operatorUIHTML = `<!DOCTYPE html>
         .name { color: #60a5fa; font-weight: bold; min-width: 140px; }
         .id { color: #a78bfa; }
         .msg { color: #9ca3af; }
+        .seq { color: #4b5563; font-size: 11px; text-decoration: none; }
+        .seq:hover { color: #60a5fa; }
+        .event.bookmarked { outline: 1px solid #fcd34d; }
+        .runbook {
+            color: #fcd34d;
+            cursor: pointer;
+            font-size: 11px;
+        }
+        .notes {
+            flex-basis: 100%;
+            background: #1a1a2e;
+            border-left: 2px solid #fcd34d;
+            padding: 6px 10px;
+            color: #d1d5db;
+            white-space: normal;
+        }
+        .notes h4 { color: #fcd34d; margin: 4px 0; font-size: 12px; }
+        .notes ul { margin-left: 18px; }
+        .notes code { background: #0f3460; padding: 0 3px; border-radius: 2px; }
         footer {
             background: #16213e;
             padding: 8px 20px;
@@ -115,7 +136,7 @@ const operatorUIHTML = `<!DOCTYPE html>
             font-size: 12px;
             color: #9ca3af;
         }
-        .control-group input {
+        .control-group input, .control-group select {
             background: #1a1a2e;
             border: 1px solid #0f3460;
             border-radius: 4px;
@@ -125,7 +146,7 @@ const operatorUIHTML = `<!DOCTYPE html>
             font-size: 12px;
             width: 160px;
         }
-        .control-group input:focus {
+        .control-group input:focus, .control-group select:focus {
             outline: none;
             border-color: #2563eb;
         }
@@ -161,6 +182,10 @@ const operatorUIHTML = `<!DOCTYPE html>
         .control-group input.small {
             width: 100px;
         }
+        .control-group select.scenes {
+            width: auto;
+            max-width: 220px;
+        }
         .control-group input.filter {
             width: 180px;
         }
@@ -169,6 +194,43 @@ const operatorUIHTML = `<!DOCTYPE html>
             color: #6b7280;
         }
         .event.filtered { display: none; }
+        .nodes {
+            background: #16213e;
+            padding: 6px 20px;
+            border-bottom: 1px solid #0f3460;
+            display: flex;
+            gap: 6px;
+            flex-wrap: wrap;
+        }
+        .nodes:empty { display: none; }
+        .controller-logs {
+            background: #0b1020;
+            border-bottom: 1px solid #0f3460;
+            padding: 6px 20px;
+            max-height: 160px;
+            overflow-y: auto;
+            font-family: monospace;
+            font-size: 11px;
+            color: #9ca3af;
+            white-space: pre-wrap;
+        }
+        .controller-logs:empty { display: none; }
+        .controller-logs .log-error { color: #fca5a5; }
+        .controller-logs .log-warn { color: #fcd34d; }
+        .node-btn {
+            background: #1a1a2e;
+            border: 1px solid #0f3460;
+            border-radius: 4px;
+            padding: 3px 8px;
+            color: #9ca3af;
+            font-family: monospace;
+            font-size: 11px;
+            cursor: pointer;
+        }
+        .node-btn.state-active { border-color: #0891b2; color: #eee; }
+        .node-btn.state-completed, .node-btn.state-overridden { color: #6b7280; }
+        .node-btn.state-failed { border-color: #dc2626; color: #fca5a5; }
+        .node-btn .override { color: #fcd34d; margin-left: 6px; }
         .divider {
             width: 1px;
             height: 24px;
@@ -200,6 +262,8 @@ const operatorUIHTML = `<!DOCTYPE html>
             <div class="health-indicators">
                 <span class="health-label"><span id="mqttDot" class="health-dot"></span>MQTT</span>
                 <span class="health-label"><span id="pgDot" class="health-dot"></span>PG</span>
+                <span class="health-label" id="devLabel"><span id="devDot" class="health-dot"></span>Devices <span id="devCount"></span></span>
+                <span class="health-label" id="warnLabel"><span id="warnDot" class="health-dot"></span>Warnings <span id="warnCount"></span></span>
             </div>
             <span id="status" class="disconnected">Disconnected</span>
         </div>
@@ -207,7 +271,7 @@ const operatorUIHTML = `<!DOCTYPE html>
     <div class="controls">
         <div class="control-group">
             <label>Game:</label>
-            <input type="text" id="sceneId" class="small" placeholder="scene_id">
+            <select id="sceneId" class="scenes"><option value="">(default scene)</option></select>
             <button id="startBtn" class="start" onclick="startGame()">Start</button>
             <button id="stopBtn" class="stop" onclick="stopGame()">Stop</button>
         </div>
@@ -223,8 +287,15 @@ const operatorUIHTML = `<!DOCTYPE html>
             <input type="text" id="filterInput" class="filter" placeholder="event name, scope, or level">
             <span id="filterInfo" class="filter-info"></span>
         </div>
+        <div class="divider"></div>
+        <div class="control-group">
+            <label>Controller logs:</label>
+            <select id="logController" class="scenes"><option value="">(hidden)</option></select>
+        </div>
         <span id="result"></span>
     </div>
+    <div id="nodes" class="nodes"></div>
+    <div id="controllerLogs" class="controller-logs"></div>
     <main>
         <div id="events"></div>
     </main>
@@ -240,6 +311,47 @@ const operatorUIHTML = `<!DOCTYPE html>
         let ws = null;
         let reconnectTimer = null;
         let currentFilter = '';
+        let runbook = {};
+
+        // Load operator notes (markdown) for scenes and nodes from the graph
+        fetch('/graph')
+            .then(function(res) { return res.json(); })
+            .then(function(graph) {
+                (graph.scenes || []).forEach(function(scene) {
+                    if (scene.notes) runbook[scene.id] = scene.notes;
+                    (scene.nodes || []).forEach(function(node) {
+                        if (node.notes) runbook[node.id] = node.notes;
+                    });
+                });
+            })
+            .catch(function() {});
+
+        function escapeHTML(text) {
+            return text.replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;');
+        }
+
+        // Minimal markdown: headings, bullet lists, bold and inline code
+        function renderMarkdown(md) {
+            let html = '';
+            let inList = false;
+            md.split('\n').forEach(function(line) {
+                let text = escapeHTML(line.trim())
+                    .replace(/\*\*([^*]+)\*\*/g, '<strong>$1</strong>')
+                    .replace(/\x60([^\x60]+)\x60/g, '<code>$1</code>');
+                const item = /^[-*] /.test(text);
+                if (item && !inList) { html += '<ul>'; inList = true; }
+                if (!item && inList) { html += '</ul>'; inList = false; }
+                if (item) {
+                    html += '<li>' + text.slice(2) + '</li>';
+                } else if (/^#+ /.test(text)) {
+                    html += '<h4>' + text.replace(/^#+ /, '') + '</h4>';
+                } else if (text) {
+                    html += '<div>' + text + '</div>';
+                }
+            });
+            if (inList) html += '</ul>';
+            return html;
+        }
 
         function formatTime(ts) {
             try {
@@ -267,12 +379,35 @@ const operatorUIHTML = `<!DOCTYPE html>
                 else if (e.fields.puzzle_id) idText = e.fields.puzzle_id;
             }
 
+            // Deep link to the event (/ui#event-<seq>, backed by /events/<seq>)
+            if (e.seq) div.id = 'event-' + e.seq;
+
             div.innerHTML =
+                (e.seq ? '<a class="seq" href="#event-' + e.seq + '" title="Link to this event">#' + e.seq + '</a>' : '') +
                 '<span class="ts">' + formatTime(e.ts) + '</span>' +
                 '<span class="name">' + e.event + '</span>' +
                 (idText ? '<span class="id">' + idText + '</span>' : '') +
                 (e.msg ? '<span class="msg">' + e.msg + '</span>' : '');
 
+            // Runbook notes for the node or scene, shown on click
+            if (idText && runbook[idText]) {
+                const toggle = document.createElement('span');
+                toggle.className = 'runbook';
+                toggle.textContent = '[runbook]';
+                toggle.onclick = function() {
+                    const open = div.querySelector('.notes');
+                    if (open) {
+                        div.removeChild(open);
+                        return;
+                    }
+                    const notes = document.createElement('div');
+                    notes.className = 'notes';
+                    notes.innerHTML = renderMarkdown(runbook[idText]);
+                    div.appendChild(notes);
+                };
+                div.appendChild(toggle);
+            }
+
             // Apply current filter to new event
             if (currentFilter) {
                 const text = div.textContent.toLowerCase();
@@ -282,6 +417,10 @@ const operatorUIHTML = `<!DOCTYPE html>
                 }
             }
 
+            if (e.seq && location.hash === '#event-' + e.seq) {
+                div.classList.add('bookmarked');
+            }
+
             eventsDiv.appendChild(div);
             eventCount++;
             countEl.textContent = eventCount;
@@ -321,7 +460,12 @@ const operatorUIHTML = `<!DOCTYPE html>
             ws.onmessage = function(msg) {
                 try {
                     const e = JSON.parse(msg.data);
+                    if (e.event === 'device.log') {
+                        appendControllerLog(e.fields || {});
+                        return;
+                    }
                     renderEvent(e);
+                    if (/^(node|puzzle|scene)\./.test(e.event)) refreshNodesSoon();
                 } catch (err) {
                     console.error('Failed to parse event:', err);
                 }
@@ -346,6 +490,34 @@ const operatorUIHTML = `<!DOCTYPE html>
             }, 3000);
         }
 
+        // Show the event a deep link points at, fetching it if it is not on screen
+        function showBookmark() {
+            const m = /^#event-(\d+)$/.exec(location.hash);
+            if (!m) return;
+            document.querySelectorAll('.event.bookmarked').forEach(function(el) {
+                el.classList.remove('bookmarked');
+            });
+            const shown = document.getElementById('event-' + m[1]);
+            if (shown) {
+                shown.classList.add('bookmarked');
+                shown.scrollIntoView({ block: 'center' });
+                return;
+            }
+            fetch('/events/' + m[1])
+                .then(function(res) { return res.ok ? res.json() : null; })
+                .then(function(e) {
+                    if (!e) {
+                        showResult(false, 'Event #' + m[1] + ' not found');
+                        return;
+                    }
+                    renderEvent(e);
+                    const el = document.getElementById('event-' + m[1]);
+                    if (el) el.scrollIntoView({ block: 'center' });
+                })
+                .catch(function() {});
+        }
+        window.addEventListener('hashchange', showBookmark);
+
         // Initial connection
         connect();
 
@@ -363,6 +535,26 @@ const operatorUIHTML = `<!DOCTYPE html>
             }, 5000);
         }
 
+        // Destructive actions may answer 428 with a confirm_token during a session;
+        // ask the operator and repeat the request with the token.
+        function postConfirmed(url, body, prompt) {
+            const opts = {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: body ? JSON.stringify(body) : undefined
+            };
+            return fetch(url, opts).then(function(res) {
+                if (res.status !== 428) return res.json();
+                return res.json().then(function(data) {
+                    if (!window.confirm(prompt)) {
+                        return { ok: false, error: 'Cancelled' };
+                    }
+                    opts.headers['X-Confirm-Token'] = data.confirm_token;
+                    return fetch(url, opts).then(function(res) { return res.json(); });
+                });
+            });
+        }
+
         function resetToNode() {
             const nodeId = nodeIdInput.value.trim();
             if (!nodeId) {
@@ -374,12 +566,7 @@ const operatorUIHTML = `<!DOCTYPE html>
             resultEl.className = '';
             resultEl.textContent = '';
 
-            fetch('/operator/reset-node', {
-                method: 'POST',
-                headers: { 'Content-Type': 'application/json' },
-                body: JSON.stringify({ node_id: nodeId })
-            })
-            .then(function(res) { return res.json(); })
+            postConfirmed('/operator/reset-node', { node_id: nodeId }, 'Reset the running game to ' + nodeId + '?')
             .then(function(data) {
                 resetBtn.disabled = false;
                 if (data.ok) {
@@ -400,23 +587,105 @@ const operatorUIHTML = `<!DOCTYPE html>
             if (e.key === 'Enter') resetToNode();
         });
 
+        // Node buttons for the active scene (from /nodes): click picks the node
+        // for Reset; active nodes can be overridden directly
+        const nodesDiv = document.getElementById('nodes');
+        let nodesTimer = null;
+
+        function loadNodes() {
+            fetch('/nodes')
+                .then(function(res) { return res.json(); })
+                .then(function(data) {
+                    nodesDiv.innerHTML = '';
+                    (data.nodes || []).forEach(function(node) {
+                        if (node.scene_id !== data.active_scene_id) return;
+                        const btn = document.createElement('button');
+                        btn.className = 'node-btn state-' + node.state;
+                        btn.title = node.type + ' - ' + node.state +
+                            (node.resolution ? ' (' + node.resolution + ')' : '') +
+                            (node.summary ? '\n' + node.summary : '');
+                        btn.textContent = node.id;
+                        btn.onclick = function() {
+                            nodeIdInput.value = node.id;
+                            nodeIdInput.focus();
+                        };
+                        if (node.state === 'active' && node.type !== 'parallel') {
+                            const override = document.createElement('span');
+                            override.className = 'override';
+                            override.textContent = '[override]';
+                            override.onclick = function(ev) {
+                                ev.stopPropagation();
+                                overrideNode(node.id);
+                            };
+                            btn.appendChild(override);
+                        }
+                        nodesDiv.appendChild(btn);
+                    });
+                })
+                .catch(function() {});
+        }
+
+        // Reload node states shortly after node, puzzle or scene events
+        function refreshNodesSoon() {
+            if (nodesTimer) return;
+            nodesTimer = setTimeout(function() {
+                nodesTimer = null;
+                loadNodes();
+            }, 250);
+        }
+
+        function overrideNode(nodeId) {
+            if (!window.confirm('Override ' + nodeId + '?')) return;
+            fetch('/operator/override', {
+                method: 'POST',
+                headers: { 'Content-Type': 'application/json' },
+                body: JSON.stringify({ node_id: nodeId })
+            })
+            .then(function(res) { return res.json(); })
+            .then(function(data) {
+                if (data.ok) {
+                    showResult(true, 'Overrode ' + nodeId);
+                } else {
+                    showResult(false, data.error || 'Override failed');
+                }
+                loadNodes();
+            })
+            .catch(function() {
+                showResult(false, 'Network error');
+            });
+        }
+
+        loadNodes();
+
         // Game controls
         const sceneIdInput = document.getElementById('sceneId');
         const startBtn = document.getElementById('startBtn');
         const stopBtn = document.getElementById('stopBtn');
 
+        // Offer the graph's scenes for Start; the first one is the default
+        function loadScenes() {
+            fetch('/scenes')
+                .then(function(res) { return res.json(); })
+                .then(function(data) {
+                    (data.scenes || []).forEach(function(scene) {
+                        const opt = document.createElement('option');
+                        opt.value = scene.id;
+                        opt.textContent = (scene.name || scene.id) + (scene.default ? ' (default)' : '') +
+                            ' - ' + scene.node_count + ' nodes';
+                        sceneIdInput.appendChild(opt);
+                    });
+                })
+                .catch(function() {});
+        }
+        loadScenes();
+
         function startGame() {
             const sceneId = sceneIdInput.value.trim();
             startBtn.disabled = true;
 
             const body = sceneId ? { scene_id: sceneId } : {};
 
-            fetch('/game/start', {
-                method: 'POST',
-                headers: { 'Content-Type': 'application/json' },
-                body: JSON.stringify(body)
-            })
-            .then(function(res) { return res.json(); })
+            postConfirmed('/game/start', body, 'End the running game and start a new one?')
             .then(function(data) {
                 startBtn.disabled = false;
                 if (data.ok) {
@@ -435,11 +704,7 @@ const operatorUIHTML = `<!DOCTYPE html>
         function stopGame() {
             stopBtn.disabled = true;
 
-            fetch('/game/stop', {
-                method: 'POST',
-                headers: { 'Content-Type': 'application/json' }
-            })
-            .then(function(res) { return res.json(); })
+            postConfirmed('/game/stop', null, 'Stop the running game?')
             .then(function(data) {
                 stopBtn.disabled = false;
                 if (data.ok) {
@@ -454,11 +719,6 @@ const operatorUIHTML = `<!DOCTYPE html>
             });
         }
 
-        // Allow Enter key to trigger start
-        sceneIdInput.addEventListener('keypress', function(e) {
-            if (e.key === 'Enter') startGame();
-        });
-
         // Event filter functionality
         const filterInput = document.getElementById('filterInput');
         const filterInfo = document.getElementById('filterInfo');
@@ -499,6 +759,7 @@ const operatorUIHTML = `<!DOCTYPE html>
                 .then(function(data) {
                     mqttDot.className = 'health-dot ' + (data.mqtt && data.mqtt.status === 'ok' ? 'ok' : 'err');
                     pgDot.className = 'health-dot ' + (data.postgres && data.postgres.status === 'ok' ? 'ok' : 'err');
+                    updateWarnings(data);
                 })
                 .catch(function() {
                     mqttDot.className = 'health-dot err';
@@ -506,9 +767,108 @@ const operatorUIHTML = `<!DOCTYPE html>
                 });
         }
 
+        const warnDot = document.getElementById('warnDot');
+        const warnLabel = document.getElementById('warnLabel');
+        const warnCount = document.getElementById('warnCount');
+
+        // Warnings badge: total since startup, amber if any; the tooltip lists them by type
+        function updateWarnings(data) {
+            const counts = data.warnings || {};
+            const types = Object.keys(counts).sort();
+            const total = types.reduce(function(sum, t) { return sum + counts[t]; }, 0);
+            warnCount.textContent = total;
+            warnDot.className = 'health-dot ' + (total > 0 ? 'warn' : 'ok');
+            warnLabel.title = types.map(function(t) { return t + ': ' + counts[t]; }).join('\n');
+        }
+
+        // Controller debug output (device.log) is shown per controller, not in
+        // the event stream: picking a controller loads its recent lines from
+        // /controllers/{id}/logs and live lines are appended
+        const logController = document.getElementById('logController');
+        const controllerLogsDiv = document.getElementById('controllerLogs');
+
+        function appendControllerLog(line) {
+            if (!logController.value || line.controller_id !== logController.value) return;
+            if (!controllerLogsDiv.children.length) controllerLogsDiv.textContent = '';
+            const div = document.createElement('div');
+            div.className = 'log-' + line.level;
+            div.textContent = (line.ts ? formatTime(line.ts) : formatTime(new Date().toISOString())) +
+                ' [' + line.level + '] ' + line.msg +
+                (line.dropped ? ' (' + line.dropped + ' lines dropped)' : '');
+            controllerLogsDiv.appendChild(div);
+            while (controllerLogsDiv.children.length > 200) {
+                controllerLogsDiv.removeChild(controllerLogsDiv.firstChild);
+            }
+            controllerLogsDiv.scrollTop = controllerLogsDiv.scrollHeight;
+        }
+
+        function loadControllerLogs() {
+            controllerLogsDiv.innerHTML = '';
+            const id = logController.value;
+            if (!id) return;
+            fetch('/controllers/' + encodeURIComponent(id) + '/logs')
+                .then(function(res) { return res.json(); })
+                .then(function(data) {
+                    if (logController.value !== id) return;
+                    controllerLogsDiv.innerHTML = '';
+                    (data.lines || []).forEach(appendControllerLog);
+                    if (!controllerLogsDiv.children.length) {
+                        controllerLogsDiv.textContent = 'No log lines from ' + id;
+                    }
+                })
+                .catch(function() {});
+        }
+        logController.addEventListener('change', loadControllerLogs);
+
+        // Offer every controller a device is registered on
+        function updateLogControllers(devices) {
+            devices.forEach(function(d) {
+                if (!d.controller_id) return;
+                const exists = Array.prototype.some.call(logController.options, function(o) {
+                    return o.value === d.controller_id;
+                });
+                if (exists) return;
+                const opt = document.createElement('option');
+                opt.value = opt.textContent = d.controller_id;
+                logController.appendChild(opt);
+            });
+        }
+
+        const devDot = document.getElementById('devDot');
+        const devLabel = document.getElementById('devLabel');
+        const devCount = document.getElementById('devCount');
+
+        // Device health: connected/total, red if a required device is offline;
+        // the tooltip lists offline devices with their controller and last_seen
+        function updateDevices() {
+            fetch('/devices')
+                .then(function(res) { return res.json(); })
+                .then(function(data) {
+                    const devices = data.devices || [];
+                    updateLogControllers(devices);
+                    const offline = devices.filter(function(d) { return !d.connected; });
+                    devCount.textContent = (devices.length - offline.length) + '/' + devices.length;
+                    const requiredDown = offline.some(function(d) { return d.required; });
+                    devDot.className = 'health-dot ' + (requiredDown ? 'err' : 'ok');
+                    devLabel.title = offline.map(function(d) {
+                        return d.logical_id + (d.controller_id ? ' @ ' + d.controller_id : '') +
+                            (d.last_seen ? ' (last seen ' + formatTime(d.last_seen) + ')' : ' (never seen)');
+                    }).join('\n');
+                })
+                .catch(function() {
+                    devDot.className = 'health-dot';
+                    devCount.textContent = '';
+                    devLabel.title = '';
+                });
+        }
+
         // Poll health every 10 seconds
         updateHealth();
-        setInterval(updateHealth, 10000);
+        updateDevices();
+        setInterval(function() { updateHealth(); updateDevices(); }, 10000);
+
+        // Open a deep-linked event (/ui#event-<seq>)
+        showBookmark();
     </script>
 </body>
 </html>`
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+// UndoResponse reports which operator action was undone.
+type UndoResponse struct {
+	OK     bool                         `json:"ok"`
+	Undone *orchestrator.OperatorAction `json:"undone,omitempty"`
+	Error  string                       `json:"error,omitempty"`
+}
+
+// operatorUndoHandler reverts the most recent override/reset in the session.
+func operatorUndoHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(UndoResponse{OK: false, Error: "method not allowed"})
+		return
+	}
+
+	if runtimeController == nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(UndoResponse{OK: false, Error: "runtime not available"})
+		return
+	}
+
+	// The runtime emits operator.undo (registry-approved)
+	action, err := runtimeController.UndoLastAction()
+	if err != nil {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(UndoResponse{OK: false, Error: err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(UndoResponse{OK: true, Undone: &action})
+}
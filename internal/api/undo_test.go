@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+func TestOperatorUndoHandler(t *testing.T) {
+	rt := orchestrator.NewRuntime(&orchestrator.SceneGraph{
+		Version: 1,
+		Scenes: []orchestrator.Scene{{
+			ID:    "scene_undo",
+			Entry: "confirm",
+			Nodes: []orchestrator.Node{
+				{ID: "confirm", Type: "operator", Config: map[string]interface{}{}},
+				{ID: "done", Type: "terminal", Config: map[string]interface{}{}},
+			},
+			Edges: []orchestrator.Edge{{From: "confirm", To: "done"}},
+		}},
+	})
+	SetRuntimeController(rt)
+	defer SetRuntimeController(nil)
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+
+	w := httptest.NewRecorder()
+	operatorUndoHandler(w, httptest.NewRequest("POST", "/operator/undo", nil))
+	if w.Code != http.StatusConflict {
+		t.Errorf("expected status 409 with nothing to undo, got %d", w.Code)
+	}
+
+	if err := rt.OverrideNode("confirm"); err != nil {
+		t.Fatalf("override failed: %v", err)
+	}
+
+	w = httptest.NewRecorder()
+	operatorUndoHandler(w, httptest.NewRequest("POST", "/operator/undo", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp UndoResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Undone == nil || resp.Undone.Action != "override" || resp.Undone.Target != "confirm" {
+		t.Errorf("unexpected undone action: %+v", resp.Undone)
+	}
+	if rt.GetNodeState("confirm") != orchestrator.NodeStateActive {
+		t.Errorf("expected confirm active after undo, got %s", rt.GetNodeState("confirm"))
+	}
+
+	w = httptest.NewRecorder()
+	operatorUndoHandler(w, httptest.NewRequest("GET", "/operator/undo", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status 405, got %d", w.Code)
+	}
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// WarningsResponse is returned by GET /warnings.
+type WarningsResponse struct {
+	Total    uint64                `json:"total"`
+	Warnings []events.WarningCount `json:"warnings"`
+}
+
+// warningsHandler summarizes the warnings counted since startup by type.
+func warningsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	resp := WarningsResponse{Warnings: events.WarningSummary()}
+	for _, wc := range resp.Warnings {
+		resp.Total += wc.Count
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// warningCounts returns the warning counts by type, or nil if there were none.
+func warningCounts() map[string]uint64 {
+	summary := events.WarningSummary()
+	if len(summary) == 0 {
+		return nil
+	}
+	counts := make(map[string]uint64, len(summary))
+	for _, wc := range summary {
+		counts[wc.Type] = wc.Count
+	}
+	return counts
+}
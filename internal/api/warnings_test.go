@@ -0,0 +1,35 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+func TestWarningsHandler(t *testing.T) {
+	events.ClearWarnings()
+	defer events.ClearWarnings()
+	events.Warn("unrecognized_device", "device not in devices.yaml", map[string]interface{}{"logical_id": "fog"})
+
+	w := httptest.NewRecorder()
+	warningsHandler(w, httptest.NewRequest("GET", "/warnings", nil))
+	var resp WarningsResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Total != 1 || len(resp.Warnings) != 1 || resp.Warnings[0].Type != "unrecognized_device" {
+		t.Errorf("unexpected warnings: %+v", resp)
+	}
+
+	w = httptest.NewRecorder()
+	readyHandler(w, httptest.NewRequest("GET", "/ready", nil))
+	var ready ReadinessResponse
+	if err := json.NewDecoder(w.Body).Decode(&ready); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if ready.Warnings["unrecognized_device"] != 1 {
+		t.Errorf("expected warning counts in /ready, got %v", ready.Warnings)
+	}
+}
@@ -2,8 +2,12 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"net/url"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/AaronLay10/SentientEngine/internal/events"
@@ -22,6 +26,9 @@ const (
 
 	// Send pings to peer with this period (must be less than pongWait)
 	pingPeriod = 54 * time.Second
+
+	// Close reason sent to clients evicted for not keeping up with the event stream
+	slowClientCloseReason = "too slow: events dropped"
 )
 
 var upgrader = websocket.Upgrader{
@@ -33,8 +40,83 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
+// levelRank orders event levels for the level filter.
+var levelRank = map[string]int{"info": 0, "warning": 1, "error": 2}
+
+// streamFilter selects the events a /ws/events client receives.
+type streamFilter struct {
+	patterns    []string // event name globs, e.g. "puzzle.*"; empty matches all
+	minLevel    int
+	allSessions bool // replay buffered events of every session, not just the current one
+}
+
+// parseStreamFilter reads ?events=puzzle.*,scene.started&level=warning&sessions=all.
+// level is the minimum level delivered. sessions=all replays the buffered
+// events of earlier sessions too (default current).
+func parseStreamFilter(q url.Values) (*streamFilter, error) {
+	f := &streamFilter{}
+	for _, p := range strings.Split(q.Get("events"), ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid events pattern: %s", p)
+		}
+		f.patterns = append(f.patterns, p)
+	}
+	if level := q.Get("level"); level != "" {
+		rank, ok := levelRank[level]
+		if !ok {
+			return nil, fmt.Errorf("invalid level: %s (expected info, warning or error)", level)
+		}
+		f.minLevel = rank
+	}
+	switch q.Get("sessions") {
+	case "", "current":
+	case "all":
+		f.allSessions = true
+	default:
+		return nil, fmt.Errorf("invalid sessions: %s (expected current or all)", q.Get("sessions"))
+	}
+	return f, nil
+}
+
+// replays reports whether a buffered event is sent to a client as it
+// connects: it must match and, unless all sessions were asked for, belong to
+// session, the current one ("" between games). Events left over from an
+// earlier game would otherwise show up as if they were part of this one.
+func (f *streamFilter) replays(e events.Event, session string) bool {
+	return (f.allSessions || e.SessionID == session) && f.match(e)
+}
+
+func (f *streamFilter) match(e events.Event) bool {
+	if levelRank[e.Level] < f.minLevel {
+		return false
+	}
+	if len(f.patterns) == 0 {
+		return true
+	}
+	for _, p := range f.patterns {
+		if ok, _ := path.Match(p, e.Name); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // wsEventsHandler handles WebSocket connections for live event streaming.
+// Query parameters events and level narrow the stream and sessions widens the
+// replay of recent events (see parseStreamFilter).
 func wsEventsHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseStreamFilter(r.URL.Query())
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("ws upgrade failed: %v", err)
@@ -44,9 +126,12 @@ func wsEventsHandler(w http.ResponseWriter, r *http.Request) {
 	// Subscribe to events
 	sub := events.Subscribe()
 
-	// Send recent events immediately
-	recent := events.RecentEvents(recentEventsCount)
-	for _, e := range recent {
+	// Send recent matching events of the current session immediately
+	session := events.CurrentSession()
+	replay := events.RecentMatching(recentEventsCount, func(e events.Event) bool {
+		return filter.replays(e, session)
+	})
+	for _, e := range replay {
 		data, err := json.Marshal(e)
 		if err != nil {
 			continue
@@ -93,10 +178,18 @@ func wsEventsHandler(w http.ResponseWriter, r *http.Request) {
 
 		case e, ok := <-sub:
 			if !ok {
-				// Subscriber channel closed
+				// Subscriber channel closed: evicted for falling behind, or shutdown
+				if events.Evicted(sub) {
+					log.Printf("ws client %s evicted: too slow", r.RemoteAddr)
+					msg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, slowClientCloseReason)
+					_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(writeWait))
+				}
 				conn.Close()
 				return
 			}
+			if !filter.match(e) {
+				continue
+			}
 			data, err := json.Marshal(e)
 			if err != nil {
 				continue
@@ -227,3 +227,119 @@ func TestWebSocketMultipleClients(t *testing.T) {
 		t.Errorf("client2: expected 'scene.completed', got '%s'", e2.Name)
 	}
 }
+
+func TestWebSocketEventFilter(t *testing.T) {
+	clearTLSEnv(t)
+	events.Clear()
+
+	events.Emit("info", "puzzle.solved", "", map[string]interface{}{"puzzle_id": "early"})
+	events.Emit("warning", "puzzle.failed", "", map[string]interface{}{"puzzle_id": "early"})
+	events.Emit("warning", "device.error", "", nil)
+
+	server := httptest.NewServer(http.HandlerFunc(wsEventsHandler))
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL+"?events=puzzle.*,scene.completed&level=warning", nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		events.Emit("info", "puzzle.solved", "", map[string]interface{}{"puzzle_id": "late"})
+		events.Emit("error", "scene.completed", "", nil)
+		events.Emit("error", "device.error", "", nil)
+		events.Emit("error", "puzzle.failed", "", map[string]interface{}{"puzzle_id": "late"})
+	}()
+
+	var names []string
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for len(names) < 3 {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("failed to read event: %v", err)
+		}
+		var e events.Event
+		if err := json.Unmarshal(msg, &e); err != nil {
+			t.Fatalf("failed to unmarshal event: %v", err)
+		}
+		names = append(names, e.Name+"/"+e.Level)
+	}
+	want := []string{"puzzle.failed/warning", "scene.completed/error", "puzzle.failed/error"}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, names)
+			break
+		}
+	}
+
+	for _, bad := range []string{"?level=debug", "?events=puzzle.[", "?sessions=last"} {
+		resp, err := http.Get(server.URL + bad)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", bad, resp.StatusCode)
+		}
+	}
+}
+
+func TestWebSocketReplaysCurrentSession(t *testing.T) {
+	clearTLSEnv(t)
+	events.Clear()
+
+	events.StartSession("intro")
+	events.Emit("info", "puzzle.solved", "", map[string]interface{}{"puzzle_id": "earlier_game"})
+	events.EndSession(events.SessionCompleted)
+	events.StartSession("intro")
+	defer events.EndSession(events.SessionStopped)
+	events.Emit("info", "puzzle.solved", "", map[string]interface{}{"puzzle_id": "this_game"})
+
+	server := httptest.NewServer(http.HandlerFunc(wsEventsHandler))
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "?events=puzzle.*"
+
+	replayed := func(query string) []string {
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL+query, nil)
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		defer conn.Close()
+
+		// A live event marks the end of the replay
+		waitFor(t, 2*time.Second, func() bool { return events.SubscriberCount() == 1 }, "client subscribed")
+		marker := "live" + query
+		events.Emit("info", "puzzle.activated", "", map[string]interface{}{"puzzle_id": marker})
+
+		var ids []string
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				t.Fatalf("failed to read event: %v", err)
+			}
+			var e events.Event
+			if err := json.Unmarshal(msg, &e); err != nil {
+				t.Fatalf("failed to unmarshal event: %v", err)
+			}
+			if e.Name == "puzzle.activated" {
+				if e.Fields["puzzle_id"] == marker {
+					return ids
+				}
+				continue
+			}
+			ids = append(ids, e.Fields["puzzle_id"].(string))
+		}
+	}
+
+	if got := strings.Join(replayed(""), ","); got != "this_game" {
+		t.Errorf("expected only the current session replayed, got %s", got)
+	}
+	waitFor(t, 2*time.Second, func() bool { return events.SubscriberCount() == 0 }, "client unsubscribed")
+	if got := strings.Join(replayed("&sessions=all"), ","); got != "earlier_game,this_game" {
+		t.Errorf("expected every session replayed, got %s", got)
+	}
+}
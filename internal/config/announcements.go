@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AnnouncementChannel is where announcements are sent.
+// type mqtt sends a logical device command (device_id + signal) or a raw topic;
+// type http POSTs the announcement as JSON to url.
+type AnnouncementChannel struct {
+	Type      string `yaml:"type"`
+	DeviceID  string `yaml:"device_id"`
+	Signal    string `yaml:"signal"`
+	Topic     string `yaml:"topic"`
+	URL       string `yaml:"url"`
+	TimeoutMs int    `yaml:"timeout_ms"`
+}
+
+// VoiceLine is a room-specific recording or TTS text.
+type VoiceLine struct {
+	Text  string `yaml:"text"`
+	File  string `yaml:"file"`
+	Voice string `yaml:"voice"`
+}
+
+// AnnouncementRule plays a voice line when the game clock reaches a mark
+// (remaining_sec) or when a matching event is emitted (when).
+type AnnouncementRule struct {
+	Name         string       `yaml:"name"`
+	RemainingSec int          `yaml:"remaining_sec"`
+	When         *BridgeMatch `yaml:"when"`
+	Line         string       `yaml:"line"`
+}
+
+// AnnouncementsConfig defines in-game announcements in announcements.yaml.
+type AnnouncementsConfig struct {
+	Version int                  `yaml:"version"`
+	Channel AnnouncementChannel  `yaml:"channel"`
+	Lines   map[string]VoiceLine `yaml:"lines"`
+	Rules   []AnnouncementRule   `yaml:"rules"`
+}
+
+// LoadAnnouncementsConfig loads announcements.yaml. A missing file yields an empty config.
+func LoadAnnouncementsConfig(path string) (*AnnouncementsConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &AnnouncementsConfig{Version: 1}, nil
+		}
+		return nil, err
+	}
+
+	var cfg AnnouncementsConfig
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.Version != 1 {
+		return nil, fmt.Errorf("unsupported announcements.yaml version: %d", cfg.Version)
+	}
+
+	if len(cfg.Rules) == 0 {
+		return &cfg, nil
+	}
+
+	ch := cfg.Channel
+	switch ch.Type {
+	case "mqtt":
+		hasDevice := ch.DeviceID != "" || ch.Signal != ""
+		if hasDevice == (ch.Topic != "") {
+			return nil, fmt.Errorf("announcement channel: mqtt needs either device_id and signal, or topic")
+		}
+		if hasDevice && (ch.DeviceID == "" || ch.Signal == "") {
+			return nil, fmt.Errorf("announcement channel: mqtt needs both device_id and signal")
+		}
+	case "http":
+		if ch.URL == "" {
+			return nil, fmt.Errorf("announcement channel: http needs url")
+		}
+	default:
+		return nil, fmt.Errorf("announcement channel: unknown type %q (want mqtt or http)", ch.Type)
+	}
+
+	for i, rule := range cfg.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("announcement rule %d: name required", i)
+		}
+		hasEvent := rule.When != nil && rule.When.Event != ""
+		if hasEvent == (rule.RemainingSec > 0) {
+			return nil, fmt.Errorf("announcement rule %s: needs either remaining_sec or when.event", rule.Name)
+		}
+		if _, ok := cfg.Lines[rule.Line]; !ok {
+			return nil, fmt.Errorf("announcement rule %s: unknown line %q", rule.Name, rule.Line)
+		}
+	}
+
+	return &cfg, nil
+}
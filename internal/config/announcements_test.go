@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadAnnouncementsConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "announcements.yaml")
+
+	cfg, err := LoadAnnouncementsConfig(path)
+	if err != nil || len(cfg.Rules) != 0 {
+		t.Fatalf("expected empty config for missing file, got %+v, %v", cfg, err)
+	}
+
+	valid := `version: 1
+channel:
+  type: http
+  url: http://tts.local/speak
+lines:
+  thirty:
+    text: Thirty minutes remaining.
+rules:
+  - name: thirty_minutes
+    remaining_sec: 1800
+    line: thirty
+  - name: scarab
+    when:
+      event: puzzle.solved
+    line: thirty
+`
+	if err := os.WriteFile(path, []byte(valid), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadAnnouncementsConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Rules) != 2 || cfg.Rules[0].RemainingSec != 1800 || cfg.Rules[1].When.Event != "puzzle.solved" {
+		t.Errorf("unexpected rules: %+v", cfg.Rules)
+	}
+
+	invalid := map[string]string{
+		"unknown line": `version: 1
+channel: {type: http, url: http://tts.local}
+rules:
+  - {name: a, remaining_sec: 60, line: missing}
+`,
+		"mark and event": `version: 1
+channel: {type: http, url: http://tts.local}
+lines: {l: {text: hi}}
+rules:
+  - {name: a, remaining_sec: 60, when: {event: puzzle.solved}, line: l}
+`,
+		"no channel": `version: 1
+lines: {l: {text: hi}}
+rules:
+  - {name: a, remaining_sec: 60, line: l}
+`,
+		"mqtt without signal": `version: 1
+channel: {type: mqtt, device_id: speaker}
+lines: {l: {text: hi}}
+rules:
+  - {name: a, remaining_sec: 60, line: l}
+`,
+	}
+	for name, doc := range invalid {
+		if err := os.WriteFile(path, []byte(doc), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadAnnouncementsConfig(path); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
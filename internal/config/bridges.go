@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BridgeMatch selects the events a bridge rule reacts to.
+type BridgeMatch struct {
+	Event     string                 `yaml:"event"`
+	Fields    map[string]interface{} `yaml:"fields"`
+	Condition string                 `yaml:"condition"`
+}
+
+// BridgePublish is the MQTT message a bridge rule sends.
+// Either a logical device command (device_id + signal) or a raw topic.
+type BridgePublish struct {
+	DeviceID string      `yaml:"device_id"`
+	Signal   string      `yaml:"signal"`
+	Topic    string      `yaml:"topic"`
+	Payload  interface{} `yaml:"payload"`
+}
+
+// BridgeRule publishes an MQTT message whenever a matching event is emitted.
+type BridgeRule struct {
+	Name    string        `yaml:"name"`
+	When    BridgeMatch   `yaml:"when"`
+	Publish BridgePublish `yaml:"publish"`
+}
+
+// RepublishConfig republishes engine events onto an MQTT topic tree, so
+// show-control systems (lighting desks, audio servers) can react to them
+// without polling the HTTP API. An event is published as JSON on
+// <topic>/<event name with dots as levels>, e.g. sentient/events/puzzle/solved.
+type RepublishConfig struct {
+	Topic  string   `yaml:"topic"`  // default <mqtt.topic_prefix>/events
+	Events []string `yaml:"events"` // event names or globs; default scene.*, puzzle.*, timer.*
+	Retain bool     `yaml:"retain"` // keep the last event of each name for late subscribers
+}
+
+// DefaultRepublishEvents are the events republished when none are listed.
+var DefaultRepublishEvents = []string{"scene.*", "puzzle.*", "timer.*"}
+
+// EventPatterns returns the events to republish.
+func (c *RepublishConfig) EventPatterns() []string {
+	if len(c.Events) == 0 {
+		return DefaultRepublishEvents
+	}
+	return c.Events
+}
+
+// BridgesConfig defines event-to-MQTT bridge rules in bridges.yaml.
+type BridgesConfig struct {
+	Version   int              `yaml:"version"`
+	Rules     []BridgeRule     `yaml:"rules"`
+	Republish *RepublishConfig `yaml:"republish"` // nil when events are not republished
+}
+
+// LoadBridgesConfig loads bridges.yaml. A missing file yields an empty config.
+func LoadBridgesConfig(path string) (*BridgesConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &BridgesConfig{Version: 1}, nil
+		}
+		return nil, err
+	}
+
+	var cfg BridgesConfig
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.Version != 1 {
+		return nil, fmt.Errorf("unsupported bridges.yaml version: %d", cfg.Version)
+	}
+
+	for i, rule := range cfg.Rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("bridge rule %d: name required", i)
+		}
+		if rule.When.Event == "" {
+			return nil, fmt.Errorf("bridge rule %s: when.event required", rule.Name)
+		}
+		hasDevice := rule.Publish.DeviceID != "" || rule.Publish.Signal != ""
+		if hasDevice == (rule.Publish.Topic != "") {
+			return nil, fmt.Errorf("bridge rule %s: publish needs either device_id and signal, or topic", rule.Name)
+		}
+		if hasDevice && (rule.Publish.DeviceID == "" || rule.Publish.Signal == "") {
+			return nil, fmt.Errorf("bridge rule %s: publish needs both device_id and signal", rule.Name)
+		}
+	}
+
+	if cfg.Republish != nil {
+		if err := cfg.Republish.validate(); err != nil {
+			return nil, fmt.Errorf("republish: %w", err)
+		}
+	}
+
+	return &cfg, nil
+}
+
+func (c *RepublishConfig) validate() error {
+	if err := validateTopicPrefix("topic", c.Topic); err != nil {
+		return err
+	}
+	for _, pattern := range c.Events {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return fmt.Errorf("invalid events pattern %q", pattern)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadBridgesConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, err := LoadBridgesConfig(filepath.Join(dir, "bridges.yaml"))
+	if err != nil || len(cfg.Rules) != 0 {
+		t.Fatalf("expected empty config for missing file, got %+v, %v", cfg, err)
+	}
+
+	path := filepath.Join(dir, "bridges.yaml")
+	valid := `version: 1
+rules:
+  - name: scarab_glow
+    when:
+      event: puzzle.solved
+      fields:
+        node_id: puzzle_scarab
+    publish:
+      device_id: scarab_light
+      signal: "on"
+`
+	if err := os.WriteFile(path, []byte(valid), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadBridgesConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Rules) != 1 || cfg.Rules[0].When.Fields["node_id"] != "puzzle_scarab" {
+		t.Errorf("unexpected rules: %+v", cfg.Rules)
+	}
+
+	both := `version: 1
+rules:
+  - name: ambiguous
+    when:
+      event: puzzle.solved
+    publish:
+      device_id: scarab_light
+      signal: "on"
+      topic: lights/scarab
+`
+	if err := os.WriteFile(path, []byte(both), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBridgesConfig(path); err == nil {
+		t.Error("expected error when both device and topic are set")
+	}
+}
+
+func TestLoadBridgesConfigRepublish(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bridges.yaml")
+	write := func(body string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte("version: 1\n"+body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("rules: []\n")
+	cfg, err := LoadBridgesConfig(path)
+	if err != nil || cfg.Republish != nil {
+		t.Fatalf("expected events not republished by default, got %+v, %v", cfg.Republish, err)
+	}
+
+	write("republish: {}\n")
+	cfg, err = LoadBridgesConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.Republish.EventPatterns(); len(got) != 3 || got[0] != "scene.*" {
+		t.Errorf("expected the default events, got %v", got)
+	}
+
+	write("republish:\n  topic: show/crypt\n  events: [puzzle.solved, timer.*]\n  retain: true\n")
+	cfg, err = LoadBridgesConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r := cfg.Republish; r.Topic != "show/crypt" || len(r.EventPatterns()) != 2 || !r.Retain {
+		t.Errorf("unexpected republish: %+v", r)
+	}
+
+	for _, bad := range []string{
+		"republish:\n  topic: show/#\n",
+		"republish:\n  topic: show/\n",
+		"republish:\n  events: [\"puzzle.[\"]\n",
+	} {
+		write(bad)
+		if _, err := LoadBridgesConfig(path); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
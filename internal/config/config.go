@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -15,11 +17,110 @@ type RoomConfig struct {
 		Name        string `yaml:"name"`
 		Description string `yaml:"description"`
 	} `yaml:"room"`
+	Ops struct {
+		Timezone           string `yaml:"timezone"`
+		DefaultGameMinutes int    `yaml:"default_game_minutes"`
+		MaxGameMinutes     int    `yaml:"max_game_minutes"`
+		CascadeReset       bool   `yaml:"cascade_reset"`
+		ConfirmDestructive *bool  `yaml:"confirm_destructive"`
+		ResetCooldownMs    int    `yaml:"reset_cooldown_ms"`
+		PauseIgnoresInput  *bool  `yaml:"pause_ignores_input"`
+		StallMinutes       int    `yaml:"stall_minutes"`
+		Reconcile          string `yaml:"reconcile"`
+		Mode               string `yaml:"mode"`
+		ControllerLogRate  int    `yaml:"controller_log_rate"`
+		ControllerLogDays  int    `yaml:"controller_log_retention_days"`
+		StrictDeviceRefs   bool   `yaml:"strict_device_refs"`
+	} `yaml:"ops"`
 	Network struct {
 		UIPort   int `yaml:"ui_port"`
 		MQTTPort int `yaml:"mqtt_port"`
 		DBPort   int `yaml:"db_port"`
 	} `yaml:"network"`
+	MQTT  MQTTConfig `yaml:"mqtt"`
+	Video struct {
+		SyncIntervalSec int           `yaml:"sync_interval_sec"`
+		Cameras         []VideoCamera `yaml:"cameras"`
+	} `yaml:"video"`
+	TTS    TTSConfig    `yaml:"tts"`
+	Syslog SyslogConfig `yaml:"syslog"`
+	Limits struct {
+		EventBufferSize int `yaml:"event_buffer_size"`
+		EventBufferMB   int `yaml:"event_buffer_mb"`
+		MaxDevices      int `yaml:"max_devices"`
+		WSQueueSize     int `yaml:"ws_queue_size"`
+	} `yaml:"limits"`
+	Routines []RoutineConfig `yaml:"routines"`
+}
+
+// RoutineConfig is a daily routine run at a time of day in ops.timezone, such
+// as warming up props at opening or powering them down at closing. Its
+// commands are sent in order, then the maintenance.yaml device tests run if
+// precheck is set, then the room switches to mode if one is given.
+type RoutineConfig struct {
+	Name     string           `yaml:"name"`
+	At       string           `yaml:"at"`   // HH:MM
+	Days     []string         `yaml:"days"` // mon-sun; default every day
+	Commands []RoutineCommand `yaml:"commands"`
+	Precheck bool             `yaml:"precheck"`
+	Mode     string           `yaml:"mode"` // show, rehearsal or maintenance
+}
+
+// RoutineCommand is a device command sent by a routine, optionally followed
+// by a pause.
+type RoutineCommand struct {
+	Device  string      `yaml:"device"`
+	Signal  string      `yaml:"signal"`
+	Payload interface{} `yaml:"payload"`
+	WaitMs  int         `yaml:"wait_ms"`
+}
+
+// SyslogConfig routes events at or above a level to syslog, for venues that
+// collect logs with standard Linux tooling. Disabled unless enabled is set.
+type SyslogConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Level    string `yaml:"level"`    // minimum event level; default warning
+	Network  string `yaml:"network"`  // udp or tcp for a remote server; empty for the local socket (journald)
+	Address  string `yaml:"address"`  // host:port of a remote server
+	Facility string `yaml:"facility"` // daemon (default), user or local0-local7
+	Tag      string `yaml:"tag"`      // default sentient-<room id>
+}
+
+// MQTTConfig sets the room's MQTT topic namespace and delivery. Rooms sharing
+// a broker each use their own prefixes.
+type MQTTConfig struct {
+	TopicPrefix    string `yaml:"topic_prefix"`  // controller topics; default "sentient"
+	DevicePrefix   string `yaml:"device_prefix"` // device topics; default "devices"
+	PublishQoS     *int   `yaml:"publish_qos"`   // default 1
+	SubscribeQoS   *int   `yaml:"subscribe_qos"` // default 1
+	RetainCommands bool   `yaml:"retain_commands"`
+	MinFirmware    string `yaml:"min_firmware"` // controllers below it are flagged; default none
+}
+
+// TTSConfig configures the text-to-speech service used by the tts.speak action.
+// engine "piper" POSTs plain text to a local Piper HTTP server; engine "http"
+// POSTs JSON (text, voice, format) to a cloud or custom API. The returned audio
+// is POSTed to a speaker endpoint.
+type TTSConfig struct {
+	Engine         string            `yaml:"engine"`
+	URL            string            `yaml:"url"`
+	APIKeyEnv      string            `yaml:"api_key_env"` // env var holding a bearer token (*_FILE supported)
+	Voice          string            `yaml:"voice"`
+	Format         string            `yaml:"format"`
+	TimeoutMs      int               `yaml:"timeout_ms"`
+	CacheDir       string            `yaml:"cache_dir"`
+	Speakers       map[string]string `yaml:"speakers"` // speaker name -> URL accepting audio POSTs
+	DefaultSpeaker string            `yaml:"default_speaker"`
+}
+
+// VideoCamera describes a CCTV camera recorded by the room's NVR.
+type VideoCamera struct {
+	ID        string `yaml:"id" json:"id"`
+	Name      string `yaml:"name" json:"name,omitempty"`
+	NVR       string `yaml:"nvr" json:"nvr,omitempty"`
+	Channel   int    `yaml:"channel" json:"channel,omitempty"`
+	StreamURL string `yaml:"stream_url" json:"stream_url,omitempty"`
+	OffsetMs  int64  `yaml:"offset_ms" json:"offset_ms,omitempty"` // NVR clock minus engine clock
 }
 
 // UIPort returns the configured UI port, defaulting to 8080 if not set.
@@ -30,6 +131,187 @@ func (c *RoomConfig) UIPort() int {
 	return c.Network.UIPort
 }
 
+// GameDuration returns the default session countdown, defaulting to 60 minutes if not set.
+func (c *RoomConfig) GameDuration() time.Duration {
+	if c.Ops.DefaultGameMinutes <= 0 {
+		return 60 * time.Minute
+	}
+	return time.Duration(c.Ops.DefaultGameMinutes) * time.Minute
+}
+
+// MaxGameDuration returns the hard cap on session length, or 0 if uncapped.
+func (c *RoomConfig) MaxGameDuration() time.Duration {
+	if c.Ops.MaxGameMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(c.Ops.MaxGameMinutes) * time.Minute
+}
+
+// RequireConfirm reports whether destructive operator actions need a confirmation
+// token during a session, defaulting to true if not set.
+func (c *RoomConfig) RequireConfirm() bool {
+	if c.Ops.ConfirmDestructive == nil {
+		return true
+	}
+	return *c.Ops.ConfirmDestructive
+}
+
+// ResetCooldown returns how long a reset puzzle ignores matching input, or 0
+// if there is no cooldown.
+func (c *RoomConfig) ResetCooldown() time.Duration {
+	if c.Ops.ResetCooldownMs <= 0 {
+		return 0
+	}
+	return time.Duration(c.Ops.ResetCooldownMs) * time.Millisecond
+}
+
+// StallTimeout returns how long a game may go without puzzle progress before
+// it is reported as stalled, or 0 if stall detection is off.
+func (c *RoomConfig) StallTimeout() time.Duration {
+	if c.Ops.StallMinutes <= 0 {
+		return 0
+	}
+	return time.Duration(c.Ops.StallMinutes) * time.Minute
+}
+
+// PauseIgnoresInput reports whether device.input is ignored while a game is
+// paused, defaulting to true if not set.
+func (c *RoomConfig) PauseIgnoresInput() bool {
+	if c.Ops.PauseIgnoresInput == nil {
+		return true
+	}
+	return *c.Ops.PauseIgnoresInput
+}
+
+// RunMode returns the engine mode at startup, defaulting to "show" if not set.
+func (c *RoomConfig) RunMode() string {
+	if c.Ops.Mode == "" {
+		return "show"
+	}
+	return c.Ops.Mode
+}
+
+// ReconcileMode returns what startup reconciliation does after a restore,
+// defaulting to "report" if not set.
+func (c *RoomConfig) ReconcileMode() string {
+	if c.Ops.Reconcile == "" {
+		return "report"
+	}
+	return c.Ops.Reconcile
+}
+
+// EventBufferSize returns how many events are kept in memory, defaulting to
+// 256 if not set.
+func (c *RoomConfig) EventBufferSize() int {
+	if c.Limits.EventBufferSize <= 0 {
+		return 256
+	}
+	return c.Limits.EventBufferSize
+}
+
+// EventBufferBytes returns the approximate memory cap of the event buffer,
+// defaulting to 16 MiB if not set.
+func (c *RoomConfig) EventBufferBytes() int64 {
+	if c.Limits.EventBufferMB <= 0 {
+		return 16 << 20
+	}
+	return int64(c.Limits.EventBufferMB) << 20
+}
+
+// MaxDevices returns how many devices the registry keeps, defaulting to 1024
+// if not set.
+func (c *RoomConfig) MaxDevices() int {
+	if c.Limits.MaxDevices <= 0 {
+		return 1024
+	}
+	return c.Limits.MaxDevices
+}
+
+// WSQueueSize returns how many events are queued per WebSocket client,
+// defaulting to 64 if not set.
+func (c *RoomConfig) WSQueueSize() int {
+	if c.Limits.WSQueueSize <= 0 {
+		return 64
+	}
+	return c.Limits.WSQueueSize
+}
+
+// MQTTTopicPrefix returns the prefix of controller topics, defaulting to
+// "sentient" if not set.
+func (c *RoomConfig) MQTTTopicPrefix() string {
+	if c.MQTT.TopicPrefix == "" {
+		return "sentient"
+	}
+	return c.MQTT.TopicPrefix
+}
+
+// MQTTDevicePrefix returns the prefix of device topics, defaulting to
+// "devices" if not set.
+func (c *RoomConfig) MQTTDevicePrefix() string {
+	if c.MQTT.DevicePrefix == "" {
+		return "devices"
+	}
+	return c.MQTT.DevicePrefix
+}
+
+// MQTTPublishQoS returns the QoS of messages the engine publishes,
+// defaulting to 1 if not set.
+func (c *RoomConfig) MQTTPublishQoS() byte {
+	if c.MQTT.PublishQoS == nil {
+		return 1
+	}
+	return byte(*c.MQTT.PublishQoS)
+}
+
+// MQTTSubscribeQoS returns the QoS of the engine's subscriptions, defaulting
+// to 1 if not set.
+func (c *RoomConfig) MQTTSubscribeQoS() byte {
+	if c.MQTT.SubscribeQoS == nil {
+		return 1
+	}
+	return byte(*c.MQTT.SubscribeQoS)
+}
+
+// ControllerLogRate returns how many log lines per minute each controller may
+// forward, defaulting to 60 if not set.
+func (c *RoomConfig) ControllerLogRate() int {
+	if c.Ops.ControllerLogRate <= 0 {
+		return 60
+	}
+	return c.Ops.ControllerLogRate
+}
+
+// ControllerLogRetention returns how long controller log lines are kept,
+// defaulting to 7 days if not set.
+func (c *RoomConfig) ControllerLogRetention() time.Duration {
+	if c.Ops.ControllerLogDays <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return time.Duration(c.Ops.ControllerLogDays) * 24 * time.Hour
+}
+
+// Location returns the room's time zone (ops.timezone), defaulting to the
+// host's local time if not set.
+func (c *RoomConfig) Location() (*time.Location, error) {
+	if c.Ops.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(c.Ops.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", c.Ops.Timezone, err)
+	}
+	return loc, nil
+}
+
+// VideoSyncInterval returns how often timer.sync is emitted during a session,
+// defaulting to 10 seconds if not set.
+func (c *RoomConfig) VideoSyncInterval() time.Duration {
+	if c.Video.SyncIntervalSec <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(c.Video.SyncIntervalSec) * time.Second
+}
+
 // DeviceDefinition defines a device in devices.yaml.
 type DeviceDefinition struct {
 	Type         string   `yaml:"type"`
@@ -40,6 +322,15 @@ type DeviceDefinition struct {
 		Inputs  []string `yaml:"inputs"`
 		Outputs []string `yaml:"outputs"`
 	} `yaml:"signals"`
+	Payload map[string]PayloadField `yaml:"payload"` // device.input payload field -> type
+	Power   *PowerConfig            `yaml:"power"`
+}
+
+// PowerConfig maps power telemetry from a smart plug to the device it feeds.
+type PowerConfig struct {
+	Topic    string  `yaml:"topic"`     // MQTT topic the plug publishes readings on
+	Field    string  `yaml:"field"`     // JSON field holding watts; empty for a bare number
+	MinWatts float64 `yaml:"min_watts"` // a powered prop draws at least this; 0 disables the check
 }
 
 type DevicesConfig struct {
@@ -61,10 +352,38 @@ func LoadRoomConfig(path string) (*RoomConfig, error) {
 	if cfg.Version != 1 {
 		return nil, fmt.Errorf("unsupported room.yaml version: %d", cfg.Version)
 	}
+	if err := cfg.MQTT.validate(); err != nil {
+		return nil, fmt.Errorf("mqtt: %w", err)
+	}
 
 	return &cfg, nil
 }
 
+func (m MQTTConfig) validate() error {
+	if err := validateTopicPrefix("topic_prefix", m.TopicPrefix); err != nil {
+		return err
+	}
+	if err := validateTopicPrefix("device_prefix", m.DevicePrefix); err != nil {
+		return err
+	}
+	if m.PublishQoS != nil && (*m.PublishQoS < 0 || *m.PublishQoS > 2) {
+		return fmt.Errorf("publish_qos must be 0, 1 or 2")
+	}
+	if m.SubscribeQoS != nil && (*m.SubscribeQoS < 0 || *m.SubscribeQoS > 2) {
+		return fmt.Errorf("subscribe_qos must be 0, 1 or 2")
+	}
+	return nil
+}
+
+// validateTopicPrefix rejects prefixes that are not plain topic levels.
+func validateTopicPrefix(name, prefix string) error {
+	if strings.ContainsAny(prefix, "+#") || strings.HasPrefix(prefix, "/") ||
+		strings.HasSuffix(prefix, "/") || strings.Contains(prefix, "//") {
+		return fmt.Errorf("%s %q must be topic levels without wildcards or empty levels", name, prefix)
+	}
+	return nil
+}
+
 func LoadDevicesConfig(path string) (*DevicesConfig, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
@@ -80,5 +399,14 @@ func LoadDevicesConfig(path string) (*DevicesConfig, error) {
 		return nil, fmt.Errorf("unsupported devices.yaml version: %d", cfg.Version)
 	}
 
+	for id, dev := range cfg.Devices {
+		if err := validatePayloadFields(dev.Payload); err != nil {
+			return nil, fmt.Errorf("device %s: %w", id, err)
+		}
+		if dev.Power != nil && (dev.Power.Topic == "" || dev.Power.MinWatts < 0) {
+			return nil, fmt.Errorf("device %s: power needs a topic and a min_watts of 0 or more", id)
+		}
+	}
+
 	return &cfg, nil
 }
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeRoomConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "room.yaml")
+	if err := os.WriteFile(path, []byte("version: 1\nroom:\n  id: crypt\n"+body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadRoomConfigMQTT(t *testing.T) {
+	cfg, err := LoadRoomConfig(writeRoomConfig(t, ""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.MQTTTopicPrefix() != "sentient" || cfg.MQTTDevicePrefix() != "devices" ||
+		cfg.MQTTPublishQoS() != 1 || cfg.MQTTSubscribeQoS() != 1 || cfg.MQTT.RetainCommands {
+		t.Errorf("unexpected defaults: %+v", cfg.MQTT)
+	}
+
+	cfg, err = LoadRoomConfig(writeRoomConfig(t, `mqtt:
+  topic_prefix: venue/crypt
+  device_prefix: venue/crypt/devices
+  publish_qos: 2
+  subscribe_qos: 0
+  retain_commands: true
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.MQTTTopicPrefix() != "venue/crypt" || cfg.MQTTDevicePrefix() != "venue/crypt/devices" ||
+		cfg.MQTTPublishQoS() != 2 || cfg.MQTTSubscribeQoS() != 0 || !cfg.MQTT.RetainCommands {
+		t.Errorf("unexpected settings: %+v", cfg.MQTT)
+	}
+
+	for body, want := range map[string]string{
+		"mqtt:\n  topic_prefix: venue/#\n":   "topic_prefix",
+		"mqtt:\n  device_prefix: /devices\n": "device_prefix",
+		"mqtt:\n  topic_prefix: a//b\n":      "topic_prefix",
+		"mqtt:\n  publish_qos: 3\n":          "publish_qos",
+		"mqtt:\n  subscribe_qos: -1\n":       "subscribe_qos",
+	} {
+		_, err := LoadRoomConfig(writeRoomConfig(t, body))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected error about %s, got %v", body, want, err)
+		}
+	}
+}
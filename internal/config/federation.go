@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FederationPeer is a sibling room's API that receives this room's events.
+type FederationPeer struct {
+	Room string `yaml:"room"`
+	URL  string `yaml:"url"`
+}
+
+// FederationConfig defines cross-room event linking in federation.yaml.
+// Events matching a publish rule are POSTed to every peer; events from peers
+// arrive as room.federated.
+type FederationConfig struct {
+	Version   int              `yaml:"version"`
+	TokenEnv  string           `yaml:"token_env"` // env var holding the shared token (*_FILE supported)
+	TimeoutMs int              `yaml:"timeout_ms"`
+	Peers     []FederationPeer `yaml:"peers"`
+	Publish   []BridgeMatch    `yaml:"publish"`
+}
+
+// Enabled reports whether any peers are configured.
+func (c *FederationConfig) Enabled() bool {
+	return len(c.Peers) > 0
+}
+
+// LoadFederationConfig loads federation.yaml. A missing file yields an empty config.
+func LoadFederationConfig(path string) (*FederationConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &FederationConfig{Version: 1}, nil
+		}
+		return nil, err
+	}
+
+	var cfg FederationConfig
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.Version != 1 {
+		return nil, fmt.Errorf("unsupported federation.yaml version: %d", cfg.Version)
+	}
+
+	if !cfg.Enabled() {
+		return &cfg, nil
+	}
+	if cfg.TokenEnv == "" {
+		return nil, fmt.Errorf("federation: token_env required")
+	}
+
+	seen := make(map[string]bool, len(cfg.Peers))
+	for i, peer := range cfg.Peers {
+		if peer.Room == "" || peer.URL == "" {
+			return nil, fmt.Errorf("federation peer %d: room and url required", i)
+		}
+		if seen[peer.Room] {
+			return nil, fmt.Errorf("federation peer %s: duplicate room", peer.Room)
+		}
+		seen[peer.Room] = true
+	}
+
+	for i, match := range cfg.Publish {
+		if match.Event == "" {
+			return nil, fmt.Errorf("federation publish %d: event required", i)
+		}
+		if match.Event == "room.federated" {
+			// Received events are never forwarded, so links cannot loop
+			return nil, fmt.Errorf("federation publish %d: room.federated cannot be published", i)
+		}
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFederationConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "federation.yaml")
+
+	cfg, err := LoadFederationConfig(path)
+	if err != nil || cfg.Enabled() {
+		t.Fatalf("expected disabled config for missing file, got %+v, %v", cfg, err)
+	}
+
+	valid := `version: 1
+token_env: SENTIENT_FEDERATION_TOKEN
+peers:
+  - room: generator_room
+    url: http://10.0.0.12:8080
+publish:
+  - event: puzzle.solved
+    fields:
+      node_id: puzzle_scarab
+`
+	if err := os.WriteFile(path, []byte(valid), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadFederationConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.Enabled() || cfg.Peers[0].Room != "generator_room" || cfg.Publish[0].Event != "puzzle.solved" {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	invalid := map[string]string{
+		"no token": `version: 1
+peers: [{room: a, url: http://a}]
+`,
+		"peer without url": `version: 1
+token_env: T
+peers: [{room: a}]
+`,
+		"duplicate peer": `version: 1
+token_env: T
+peers: [{room: a, url: http://a}, {room: a, url: http://b}]
+`,
+		"publish without event": `version: 1
+token_env: T
+peers: [{room: a, url: http://a}]
+publish: [{fields: {node_id: x}}]
+`,
+		"republish": `version: 1
+token_env: T
+peers: [{room: a, url: http://a}]
+publish: [{event: room.federated}]
+`,
+	}
+	for name, doc := range invalid {
+		if err := os.WriteFile(path, []byte(doc), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFederationConfig(path); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
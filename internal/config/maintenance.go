@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MaintenanceStep is one action in a device test routine.
+// A step may send a command, wait for an expected input, pause, or any combination.
+type MaintenanceStep struct {
+	Signal    string      `yaml:"signal"`
+	Payload   interface{} `yaml:"payload"`
+	Expect    string      `yaml:"expect"`
+	TimeoutMs int         `yaml:"timeout_ms"`
+	WaitMs    int         `yaml:"wait_ms"`
+}
+
+// MaintenanceRoutine exercises a single device.
+type MaintenanceRoutine struct {
+	Description string            `yaml:"description"`
+	Steps       []MaintenanceStep `yaml:"steps"`
+}
+
+// MaintenanceConfig defines device test routines in maintenance.yaml.
+type MaintenanceConfig struct {
+	Version  int                           `yaml:"version"`
+	Routines map[string]MaintenanceRoutine `yaml:"routines"`
+}
+
+// LoadMaintenanceConfig loads maintenance.yaml. A missing file yields an empty config.
+func LoadMaintenanceConfig(path string) (*MaintenanceConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &MaintenanceConfig{Version: 1, Routines: map[string]MaintenanceRoutine{}}, nil
+		}
+		return nil, err
+	}
+
+	var cfg MaintenanceConfig
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.Version != 1 {
+		return nil, fmt.Errorf("unsupported maintenance.yaml version: %d", cfg.Version)
+	}
+
+	return &cfg, nil
+}
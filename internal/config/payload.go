@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Payload field types declared in devices.yaml.
+const (
+	PayloadBool   = "bool"
+	PayloadInt    = "int"
+	PayloadNumber = "number"
+	PayloadString = "string"
+)
+
+// Input semantics of a payload field.
+const (
+	InputMomentary = "momentary" // each message is an event (a button press)
+	InputLatched   = "latched"   // the field is state that holds until it changes (a door sensor)
+)
+
+// shorthandUnits are the units a payload field may be written as on their
+// own. Other units need the mapping form, so a misspelt type name is not
+// taken for a unit.
+var shorthandUnits = map[string]bool{
+	"celsius": true, "fahrenheit": true, "kelvin": true,
+	"percent": true, "lux": true, "ppm": true, "db": true,
+	"degrees": true, "rpm": true, "hz": true, "bpm": true,
+	"ms": true, "seconds": true,
+	"mm": true, "cm": true, "meters": true,
+	"volts": true, "amps": true, "watts": true,
+	"grams": true, "kg": true,
+}
+
+// PayloadField declares the type and unit of one field of a device's
+// device.input payload. A field may be written as {type, unit, input} or as a
+// single word: a type name, or a common unit (e.g. celsius, see
+// shorthandUnits), which implies a number.
+type PayloadField struct {
+	Type  string `yaml:"type"`  // bool, int, number or string; default number when a unit is given
+	Unit  string `yaml:"unit"`  // informational, e.g. celsius, percent; numeric fields only
+	Input string `yaml:"input"` // momentary (default) or latched
+}
+
+// UnmarshalYAML accepts the single-word form as well as a mapping.
+func (f *PayloadField) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		switch value.Value {
+		case PayloadBool, PayloadInt, PayloadNumber, PayloadString:
+			f.Type = value.Value
+		default:
+			if !shorthandUnits[value.Value] {
+				return fmt.Errorf("line %d: unknown payload type or unit %q (write other units as {type: number, unit: %s})", value.Line, value.Value, value.Value)
+			}
+			f.Unit = value.Value
+		}
+		return nil
+	}
+	type plain PayloadField
+	return value.Decode((*plain)(f))
+}
+
+// validatePayloadFields checks and normalizes a device's payload declarations.
+func validatePayloadFields(fields map[string]PayloadField) error {
+	for name, f := range fields {
+		if f.Type == "" && f.Unit != "" {
+			f.Type = PayloadNumber
+		}
+		switch f.Type {
+		case PayloadInt, PayloadNumber:
+		case PayloadBool, PayloadString:
+			if f.Unit != "" {
+				return fmt.Errorf("payload field %s: unit %s needs a numeric type, got %s", name, f.Unit, f.Type)
+			}
+		case "":
+			return fmt.Errorf("payload field %s: type or unit required", name)
+		default:
+			return fmt.Errorf("payload field %s: unknown type %s", name, f.Type)
+		}
+		switch f.Input {
+		case "", InputMomentary, InputLatched:
+		default:
+			return fmt.Errorf("payload field %s: input must be momentary or latched, got %s", name, f.Input)
+		}
+		fields[name] = f
+	}
+	return nil
+}
+
+// LatchedFields returns the latched payload fields of each device, sorted.
+func (c *DevicesConfig) LatchedFields() map[string][]string {
+	latched := make(map[string][]string)
+	for id, dev := range c.Devices {
+		for name, f := range dev.Payload {
+			if f.Input == InputLatched {
+				latched[id] = append(latched[id], name)
+			}
+		}
+		sort.Strings(latched[id])
+	}
+	return latched
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadDevicesConfigPayload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "devices.yaml")
+
+	if _, err := LoadDevicesConfig("../../rooms/_template/devices.yaml"); err != nil {
+		t.Fatalf("expected template to load, got %v", err)
+	}
+
+	valid := `version: 1
+devices:
+  brazier:
+    type: sensor
+    payload:
+      lit: bool
+      flame_level: int
+      temperature: celsius
+      humidity: {type: number, unit: percent}
+      color: string
+      door_closed: {type: bool, input: latched}
+`
+	if err := os.WriteFile(path, []byte(valid), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadDevicesConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	payload := cfg.Devices["brazier"].Payload
+	want := map[string]PayloadField{
+		"lit":         {Type: PayloadBool},
+		"flame_level": {Type: PayloadInt},
+		"temperature": {Type: PayloadNumber, Unit: "celsius"},
+		"humidity":    {Type: PayloadNumber, Unit: "percent"},
+		"color":       {Type: PayloadString},
+		"door_closed": {Type: PayloadBool, Input: InputLatched},
+	}
+	for name, f := range want {
+		if payload[name] != f {
+			t.Errorf("%s: got %+v, want %+v", name, payload[name], f)
+		}
+	}
+	if latched := cfg.LatchedFields()["brazier"]; len(latched) != 1 || latched[0] != "door_closed" {
+		t.Errorf("expected door_closed latched, got %v", latched)
+	}
+
+	for name, field := range map[string]string{
+		"unknown type":   "{type: float}",
+		"unit on bool":   "{type: bool, unit: celsius}",
+		"missing type":   "{}",
+		"unit on string": "{type: string, unit: lux}",
+		"unknown input":  "{type: bool, input: toggle}",
+		"misspelt type":  "bol",
+		"unknown unit":   "furlongs",
+	} {
+		bad := "version: 1\ndevices:\n  brazier:\n    payload:\n      lit: " + field + "\n"
+		if err := os.WriteFile(path, []byte(bad), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadDevicesConfig(path); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RoomTestDir is where a room keeps its room tests, relative to the room directory.
+const RoomTestDir = "tests"
+
+// RoomTest is a scripted game played against the room's scene graph, in
+// rooms/<room-id>/tests/*.yaml.
+type RoomTest struct {
+	Version int            `yaml:"version"`
+	Name    string         `yaml:"name"`
+	Scene   string         `yaml:"scene"` // scene to start; default the first
+	Seed    int64          `yaml:"seed"`  // seeds random choices; default 1
+	Steps   []RoomTestStep `yaml:"steps"`
+	Expect  *RoomTestCheck `yaml:"expect"` // checked after the last step
+}
+
+// RoomTestStep is one step of a room test. Exactly one field is set.
+type RoomTestStep struct {
+	Input    *RoomTestInput `yaml:"input"`    // a device input
+	Event    *RoomTestEvent `yaml:"event"`    // any other event
+	WaitSec  float64        `yaml:"wait_sec"` // let game time pass
+	Override string         `yaml:"override"` // operator override of a node
+	Reset    string         `yaml:"reset"`    // operator reset of a node
+	Expect   *RoomTestCheck `yaml:"expect"`   // check the game so far
+}
+
+// RoomTestInput is a device input sent by a prop.
+type RoomTestInput struct {
+	Device  string                 `yaml:"device"` // logical ID
+	Payload map[string]interface{} `yaml:"payload"`
+}
+
+// RoomTestEvent is an event with fields. In expectations, fields lists only
+// the fields that must match; nested objects match the same way.
+type RoomTestEvent struct {
+	Name   string                 `yaml:"name"`
+	Fields map[string]interface{} `yaml:"fields"`
+}
+
+// RoomTestCommand is a device command the game must send. Payload is
+// matched like event fields when given.
+type RoomTestCommand struct {
+	Device  string      `yaml:"device"`
+	Signal  string      `yaml:"signal"`
+	Payload interface{} `yaml:"payload"`
+}
+
+// RoomTestCheck lists what must hold at a point of the test. Events and
+// commands must appear in the given order among those since the previous
+// check, others may come between them.
+type RoomTestCheck struct {
+	Events    []RoomTestEvent        `yaml:"events"`
+	NoEvents  []RoomTestEvent        `yaml:"no_events"` // must not appear since the previous check
+	Commands  []RoomTestCommand      `yaml:"commands"`
+	Nodes     map[string]string      `yaml:"nodes"`   // node ID -> state
+	Puzzles   map[string]string      `yaml:"puzzles"` // puzzle node ID -> resolution
+	Variables map[string]interface{} `yaml:"variables"`
+}
+
+// LoadRoomTest loads a room test file.
+func LoadRoomTest(path string) (*RoomTest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var test RoomTest
+	if err := yaml.Unmarshal(b, &test); err != nil {
+		return nil, err
+	}
+
+	if test.Version != 1 {
+		return nil, fmt.Errorf("unsupported room test version: %d", test.Version)
+	}
+	if test.Name == "" {
+		test.Name = filepath.Base(path)
+	}
+
+	for i, step := range test.Steps {
+		if err := step.validate(); err != nil {
+			return nil, fmt.Errorf("steps[%d]: %w", i, err)
+		}
+	}
+
+	return &test, nil
+}
+
+func (s RoomTestStep) validate() error {
+	set := 0
+	if s.Input != nil {
+		set++
+		if s.Input.Device == "" {
+			return fmt.Errorf("input needs a device")
+		}
+	}
+	if s.Event != nil {
+		set++
+		if s.Event.Name == "" {
+			return fmt.Errorf("event needs a name")
+		}
+	}
+	if s.WaitSec < 0 {
+		return fmt.Errorf("wait_sec must not be negative")
+	}
+	if s.WaitSec > 0 {
+		set++
+	}
+	if s.Override != "" {
+		set++
+	}
+	if s.Reset != "" {
+		set++
+	}
+	if s.Expect != nil {
+		set++
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of input, event, wait_sec, override, reset or expect is required")
+	}
+	return nil
+}
+
+// FindRoomTests returns the room test files of a room directory in name
+// order. A room without a tests directory has none.
+func FindRoomTests(roomDir string) ([]string, error) {
+	var paths []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(roomDir, RoomTestDir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
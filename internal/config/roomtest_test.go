@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadRoomTest(t *testing.T) {
+	paths, err := FindRoomTests("../../rooms/_template")
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("expected template room tests, got %v, %v", paths, err)
+	}
+	for _, path := range paths {
+		if _, err := LoadRoomTest(path); err != nil {
+			t.Errorf("%s: %v", path, err)
+		}
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "door.yaml")
+	valid := `version: 1
+steps:
+  - input: { device: crypt_door, payload: { door_closed: true } }
+  - wait_sec: 1.5
+  - expect:
+      puzzles: { puzzle_scarab: solved }
+`
+	if err := os.WriteFile(path, []byte(valid), 0644); err != nil {
+		t.Fatal(err)
+	}
+	test, err := LoadRoomTest(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if test.Name != "door.yaml" || len(test.Steps) != 3 || test.Steps[1].WaitSec != 1.5 ||
+		test.Steps[0].Input.Payload["door_closed"] != true {
+		t.Errorf("unexpected test: %+v", test)
+	}
+
+	twoActions := `version: 1
+steps:
+  - override: puzzle_scarab
+    reset: puzzle_scarab
+`
+	if err := os.WriteFile(path, []byte(twoActions), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRoomTest(path); err == nil {
+		t.Error("expected error for a step with two actions")
+	}
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TagsConfig maps RFID/NFC tag UIDs to the props they are attached to, in tags.yaml.
+type TagsConfig struct {
+	Version  int               `yaml:"version"`
+	Readers  []string          `yaml:"readers"`   // logical IDs of tag readers; empty for every device
+	UIDField string            `yaml:"uid_field"` // payload field holding the UID; default "uid"
+	Tags     map[string]string `yaml:"tags"`      // UID -> item name
+}
+
+// LoadTagsConfig loads tags.yaml. A missing file yields an empty config.
+func LoadTagsConfig(path string) (*TagsConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &TagsConfig{Version: 1}, nil
+		}
+		return nil, err
+	}
+
+	var cfg TagsConfig
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.Version != 1 {
+		return nil, fmt.Errorf("unsupported tags.yaml version: %d", cfg.Version)
+	}
+
+	for uid, item := range cfg.Tags {
+		if item == "" {
+			return nil, fmt.Errorf("tag %s: item name required", uid)
+		}
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadTagsConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tags.yaml")
+
+	cfg, err := LoadTagsConfig(path)
+	if err != nil || len(cfg.Tags) != 0 {
+		t.Fatalf("expected empty config for missing file, got %+v, %v", cfg, err)
+	}
+
+	cfg, err = LoadTagsConfig("../../rooms/_template/tags.yaml")
+	if err != nil || len(cfg.Tags) != 0 {
+		t.Fatalf("expected template to load with no tags, got %+v, %v", cfg, err)
+	}
+
+	valid := `version: 1
+readers: [altar_reader]
+tags:
+  "04:A2:3B:1C": ankh_idol
+  04A23B1D: ankh_idol
+`
+	if err := os.WriteFile(path, []byte(valid), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadTagsConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.Tags) != 2 || cfg.Tags["04A23B1D"] != "ankh_idol" || cfg.Readers[0] != "altar_reader" {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	noItem := `version: 1
+tags:
+  "04:A2:3B:1C": ""
+`
+	if err := os.WriteFile(path, []byte(noItem), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTagsConfig(path); err == nil {
+		t.Error("expected error for tag without item")
+	}
+}
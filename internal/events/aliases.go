@@ -0,0 +1,72 @@
+package events
+
+import (
+	"sort"
+	"sync"
+)
+
+// WarningDeprecatedEvent is the warning type reported when a legacy event name is used.
+const WarningDeprecatedEvent = "deprecated_event"
+
+var deprecations = struct {
+	mu     sync.Mutex
+	warned map[string]bool // source + alias already reported
+}{warned: make(map[string]bool)}
+
+// Canonical returns the current name of an event, translating a legacy
+// alias. It reports whether name was an alias.
+func Canonical(name string) (string, bool) {
+	if current, ok := aliases[name]; ok {
+		return current, true
+	}
+	return name, false
+}
+
+// Translate returns the current name of an event used by source (a file,
+// a peer room or "emit"). It is applied wherever names enter the engine:
+// emitted events, scene graphs, bridge, announcement and federation rules,
+// sibling rooms and events read back from the store. The first use of each
+// alias from a source emits a deprecated_event warning, so authors know what
+// to update before the alias is removed.
+func Translate(name, source string) string {
+	current, ok := Canonical(name)
+	if !ok {
+		return name
+	}
+
+	key := source + "\x00" + name
+	deprecations.mu.Lock()
+	warned := deprecations.warned[key]
+	deprecations.warned[key] = true
+	deprecations.mu.Unlock()
+
+	if !warned {
+		Warn(WarningDeprecatedEvent, "legacy event name used", map[string]interface{}{
+			"alias":  name,
+			"event":  current,
+			"source": source,
+		})
+	}
+	return current
+}
+
+// Aliases returns the legacy event names and the names that replaced them.
+func Aliases() map[string]string {
+	out := make(map[string]string, len(aliases))
+	for legacy, current := range aliases {
+		out[legacy] = current
+	}
+	return out
+}
+
+// LegacyNames returns the legacy names of an event, sorted.
+func LegacyNames(name string) []string {
+	var names []string
+	for legacy, current := range aliases {
+		if current == name {
+			names = append(names, legacy)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,47 @@
+package events
+
+import "testing"
+
+func TestAliases(t *testing.T) {
+	aliases["puzzle.completed"] = "puzzle.solved"
+	defer delete(aliases, "puzzle.completed")
+	Clear()
+	ClearWarnings()
+	defer ClearWarnings()
+
+	if name, ok := Canonical("puzzle.completed"); !ok || name != "puzzle.solved" {
+		t.Errorf("expected puzzle.completed translated, got %s, %v", name, ok)
+	}
+	if name, ok := Canonical("puzzle.solved"); ok || name != "puzzle.solved" {
+		t.Errorf("expected current names unchanged, got %s, %v", name, ok)
+	}
+	if got := LegacyNames("puzzle.solved"); len(got) != 1 || got[0] != "puzzle.completed" {
+		t.Errorf("unexpected legacy names: %v", got)
+	}
+
+	// Emitted under its current name, with one deprecation warning per source
+	for i := 0; i < 2; i++ {
+		if _, err := Emit("info", "puzzle.completed", "", map[string]interface{}{"puzzle_id": "scarab"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	Translate("puzzle.completed", "graphs/scene-graph.v1.json")
+
+	var solved, deprecated int
+	for _, e := range Snapshot() {
+		switch {
+		case e.Name == "puzzle.solved":
+			solved++
+		case e.Name == "system.warning" && e.Fields["type"] == WarningDeprecatedEvent:
+			deprecated++
+			if e.Fields["alias"] != "puzzle.completed" || e.Fields["event"] != "puzzle.solved" {
+				t.Errorf("unexpected warning fields: %v", e.Fields)
+			}
+		case e.Name == "puzzle.completed":
+			t.Error("expected no event under the legacy name")
+		}
+	}
+	if solved != 2 || deprecated != 2 {
+		t.Errorf("expected 2 puzzle.solved and a warning per source, got %d and %d", solved, deprecated)
+	}
+}
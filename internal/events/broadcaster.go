@@ -1,62 +1,132 @@
 package events
 
 import (
+	"log"
 	"sync"
+	"sync/atomic"
 )
 
+// MaxConsecutiveDrops is how many events in a row a subscriber may miss
+// because its buffer is full before it is evicted.
+const MaxConsecutiveDrops = 128
+
 // Subscriber represents a channel that receives events.
 type Subscriber chan Event
 
 // Broadcaster manages WebSocket event subscribers.
 type Broadcaster struct {
-	mu          sync.RWMutex
-	subscribers map[Subscriber]struct{}
+	mu          sync.Mutex
+	subscribers map[Subscriber]int      // subscriber -> consecutive drops
+	evicted     map[Subscriber]struct{} // closed for being too slow, until the owner checks Evicted
 }
 
 var broadcaster = &Broadcaster{
-	subscribers: make(map[Subscriber]struct{}),
+	subscribers: make(map[Subscriber]int),
+	evicted:     make(map[Subscriber]struct{}),
+}
+
+var (
+	droppedTotal uint64
+	evictedTotal uint64
+)
+
+// subscriberQueueSize is the number of events buffered per subscriber.
+var subscriberQueueSize int64 = 64
+
+// SetSubscriberQueueSize sets the buffer of subscribers created afterwards.
+func SetSubscriberQueueSize(n int) {
+	atomic.StoreInt64(&subscriberQueueSize, int64(n))
 }
 
 // Subscribe adds a new subscriber and returns its channel.
 // The channel has a buffer to prevent blocking on slow clients.
 func Subscribe() Subscriber {
-	ch := make(Subscriber, 64) // Buffer to avoid blocking Emit
+	ch := make(Subscriber, atomic.LoadInt64(&subscriberQueueSize)) // Buffer to avoid blocking Emit
 	broadcaster.mu.Lock()
-	broadcaster.subscribers[ch] = struct{}{}
+	broadcaster.subscribers[ch] = 0
 	broadcaster.mu.Unlock()
 	return ch
 }
 
 // Unsubscribe removes a subscriber and closes its channel.
+// Safe to call after the subscriber was evicted or closed at shutdown.
 func Unsubscribe(sub Subscriber) {
 	broadcaster.mu.Lock()
-	delete(broadcaster.subscribers, sub)
-	broadcaster.mu.Unlock()
-	close(sub)
+	defer broadcaster.mu.Unlock()
+	delete(broadcaster.evicted, sub)
+	if _, ok := broadcaster.subscribers[sub]; ok {
+		delete(broadcaster.subscribers, sub)
+		close(sub)
+	}
+}
+
+// Evicted reports whether sub's channel was closed because it fell
+// MaxConsecutiveDrops events behind, rather than at shutdown.
+func Evicted(sub Subscriber) bool {
+	broadcaster.mu.Lock()
+	defer broadcaster.mu.Unlock()
+	_, ok := broadcaster.evicted[sub]
+	delete(broadcaster.evicted, sub)
+	return ok
 }
 
 // broadcast sends an event to all subscribers.
-// Non-blocking: if a subscriber's buffer is full, the event is dropped for that subscriber.
+// Non-blocking: if a subscriber's buffer is full, the event is dropped for that subscriber,
+// and a subscriber that keeps missing events is evicted.
 func broadcast(e Event) {
-	broadcaster.mu.RLock()
-	defer broadcaster.mu.RUnlock()
+	broadcaster.mu.Lock()
+	defer broadcaster.mu.Unlock()
 
-	for sub := range broadcaster.subscribers {
+	for sub, drops := range broadcaster.subscribers {
 		select {
 		case sub <- e:
+			broadcaster.subscribers[sub] = 0
 		default:
 			// Buffer full, drop event for this slow subscriber
+			atomic.AddUint64(&droppedTotal, 1)
+			noteWarning(WarningEventsDropped, "event dropped for a slow subscriber")
+			drops++
+			if drops < MaxConsecutiveDrops {
+				broadcaster.subscribers[sub] = drops
+				continue
+			}
+			log.Printf("[events] evicting subscriber after %d consecutive dropped events", drops)
+			atomic.AddUint64(&evictedTotal, 1)
+			delete(broadcaster.subscribers, sub)
+			broadcaster.evicted[sub] = struct{}{}
+			close(sub)
 		}
 	}
 }
 
 // SubscriberCount returns the current number of subscribers.
 func SubscriberCount() int {
-	broadcaster.mu.RLock()
-	defer broadcaster.mu.RUnlock()
+	broadcaster.mu.Lock()
+	defer broadcaster.mu.Unlock()
 	return len(broadcaster.subscribers)
 }
 
+// QueuedCount returns the number of events waiting in subscriber buffers.
+func QueuedCount() int {
+	broadcaster.mu.Lock()
+	defer broadcaster.mu.Unlock()
+	queued := 0
+	for sub := range broadcaster.subscribers {
+		queued += len(sub)
+	}
+	return queued
+}
+
+// DroppedCount returns the total number of events dropped for slow subscribers since startup.
+func DroppedCount() uint64 {
+	return atomic.LoadUint64(&droppedTotal)
+}
+
+// EvictedCount returns the total number of subscribers evicted for being too slow since startup.
+func EvictedCount() uint64 {
+	return atomic.LoadUint64(&evictedTotal)
+}
+
 // CloseAllSubscribers closes all subscriber channels for graceful shutdown.
 // This should be called before shutting down the HTTP server.
 func CloseAllSubscribers() {
@@ -66,15 +136,18 @@ func CloseAllSubscribers() {
 	for sub := range broadcaster.subscribers {
 		close(sub)
 	}
-	broadcaster.subscribers = make(map[Subscriber]struct{})
+	broadcaster.subscribers = make(map[Subscriber]int)
+	broadcaster.evicted = make(map[Subscriber]struct{})
 }
 
 // RecentEvents returns the last n events from the ring buffer.
 // If n is greater than available events, returns all available.
 func RecentEvents(n int) []Event {
-	all := buffer.Snapshot()
-	if n <= 0 || n >= len(all) {
-		return all
-	}
-	return all[len(all)-n:]
+	return buffer.Tail(n, nil)
+}
+
+// RecentMatching returns the last n buffered events accepted by match, oldest
+// first. Only the returned events are copied.
+func RecentMatching(n int, match func(Event) bool) []Event {
+	return buffer.Tail(n, match)
 }
@@ -152,3 +152,57 @@ func TestCloseAllSubscribers(t *testing.T) {
 		t.Errorf("expected 0 subscribers after CloseAllSubscribers, got %d", SubscriberCount())
 	}
 }
+
+func TestSlowSubscriberEvicted(t *testing.T) {
+	CloseAllSubscribers()
+	evictedBefore := EvictedCount()
+
+	slow := Subscribe()
+	fast := Subscribe()
+	defer Unsubscribe(fast)
+
+	// Fill the slow subscriber's buffer, then drop one short of eviction
+	for i := 0; i < cap(slow)+MaxConsecutiveDrops-1; i++ {
+		broadcast(Event{Name: "node.started"})
+		<-fast
+	}
+	if SubscriberCount() != 2 {
+		t.Fatalf("expected slow subscriber kept before %d drops, got %d subscribers", MaxConsecutiveDrops, SubscriberCount())
+	}
+
+	// Reading one event resets the count
+	<-slow
+	broadcast(Event{Name: "node.started"})
+	<-fast
+	for i := 0; i < MaxConsecutiveDrops-1; i++ {
+		broadcast(Event{Name: "node.started"})
+		<-fast
+	}
+	if SubscriberCount() != 2 {
+		t.Fatal("expected drop count to reset after a delivered event")
+	}
+
+	broadcast(Event{Name: "node.started"})
+	<-fast
+	if SubscriberCount() != 1 {
+		t.Fatalf("expected slow subscriber evicted, got %d subscribers", SubscriberCount())
+	}
+	for range slow {
+		// Drain buffered events until the channel is closed
+	}
+	if !Evicted(slow) {
+		t.Error("expected Evicted to report the slow subscriber")
+	}
+	if Evicted(slow) {
+		t.Error("expected Evicted to report only once")
+	}
+	if Evicted(fast) {
+		t.Error("expected fast subscriber not evicted")
+	}
+	if got := EvictedCount() - evictedBefore; got != 1 {
+		t.Errorf("expected eviction counted once, got %d", got)
+	}
+
+	// Unsubscribing an evicted subscriber must not close its channel twice
+	Unsubscribe(slow)
+}
@@ -1,52 +1,243 @@
 package events
 
-import "sync"
+import (
+	"sync"
+	"sync/atomic"
+)
 
+// RingBuffer keeps the most recent events in memory, up to a number of events
+// and, optionally, an approximate number of bytes. Events over the byte cap
+// are shed oldest first and counted as a memory_shed warning.
 type RingBuffer struct {
-	mu     sync.RWMutex
-	size   int
-	events []Event
-	index  int
-	full   bool
+	mu       sync.RWMutex
+	size     int
+	events   []Event
+	sizes    []int64 // approximate bytes of each slot
+	start    int     // slot of the oldest event
+	n        int     // number of buffered events
+	bytes    int64
+	maxBytes int64 // 0 for no byte cap
+	shed     uint64
 }
 
 func NewRingBuffer(size int) *RingBuffer {
 	return &RingBuffer{
 		size:   size,
 		events: make([]Event, size),
+		sizes:  make([]int64, size),
 	}
 }
 
 func (rb *RingBuffer) Add(e Event) {
 	rb.mu.Lock()
 	defer rb.mu.Unlock()
+	rb.add(e)
+}
+
+// AddNext gives e the next sequence number from seq and adds it, returning
+// the numbered event. Numbering under the buffer lock keeps the buffer in
+// seq order when events are emitted from several goroutines, which After
+// relies on.
+func (rb *RingBuffer) AddNext(e Event, seq *uint64) Event {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+	e.Seq = atomic.AddUint64(seq, 1)
+	rb.add(e)
+	return e
+}
+
+// add appends e, shedding old events as needed.
+// Caller must hold rb.mu.
+func (rb *RingBuffer) add(e Event) {
+	if rb.n == rb.size {
+		rb.dropOldest()
+	}
+	slot := (rb.start + rb.n) % rb.size
+	rb.events[slot] = e
+	rb.sizes[slot] = approxEventSize(e)
+	rb.bytes += rb.sizes[slot]
+	rb.n++
 
-	rb.events[rb.index] = e
-	rb.index = (rb.index + 1) % rb.size
-	if rb.index == 0 {
-		rb.full = true
+	// The newest event is always kept, even if it alone is over the cap
+	shed := 0
+	for rb.maxBytes > 0 && rb.bytes > rb.maxBytes && rb.n > 1 {
+		rb.dropOldest()
+		shed++
 	}
+	if shed > 0 {
+		rb.shed += uint64(shed)
+		noteWarning(WarningMemoryShed, "event buffer over its memory cap")
+	}
+}
+
+// dropOldest removes the oldest event.
+// Caller must hold rb.mu.
+func (rb *RingBuffer) dropOldest() {
+	rb.bytes -= rb.sizes[rb.start]
+	rb.events[rb.start] = Event{}
+	rb.sizes[rb.start] = 0
+	rb.start = (rb.start + 1) % rb.size
+	rb.n--
 }
 
+// Resize changes the number of events kept and the approximate byte cap
+// (0 for none), keeping the most recent events that fit.
+func (rb *RingBuffer) Resize(size int, maxBytes int64) {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	keep := rb.n
+	if keep > size {
+		keep = size
+	}
+	events := make([]Event, size)
+	sizes := make([]int64, size)
+	var bytes int64
+	for i := 0; i < keep; i++ {
+		slot := (rb.start + rb.n - keep + i) % rb.size
+		events[i] = rb.events[slot]
+		sizes[i] = rb.sizes[slot]
+		bytes += sizes[i]
+	}
+	rb.size, rb.events, rb.sizes = size, events, sizes
+	rb.start, rb.n, rb.bytes = 0, keep, bytes
+	rb.maxBytes = maxBytes
+
+	for rb.maxBytes > 0 && rb.bytes > rb.maxBytes && rb.n > 1 {
+		rb.dropOldest()
+		rb.shed++
+	}
+}
+
+// Stats returns the number of buffered events, their approximate size in
+// bytes and how many events have been shed for the byte cap.
+func (rb *RingBuffer) Stats() (events int, bytes int64, shed uint64) {
+	rb.mu.RLock()
+	defer rb.mu.RUnlock()
+	return rb.n, rb.bytes, rb.shed
+}
+
+// Snapshot returns a copy of every buffered event, oldest first.
 func (rb *RingBuffer) Snapshot() []Event {
 	rb.mu.RLock()
 	defer rb.mu.RUnlock()
 
-	if !rb.full {
-		return append([]Event{}, rb.events[:rb.index]...)
+	out := make([]Event, rb.n)
+	for i := range out {
+		out[i] = rb.at(i)
 	}
+	return out
+}
+
+// at returns the i-th buffered event, oldest first.
+// Caller must hold rb.mu.
+func (rb *RingBuffer) at(i int) Event {
+	return rb.events[(rb.start+i)%rb.size]
+}
 
-	out := make([]Event, 0, rb.size)
-	out = append(out, rb.events[rb.index:]...)
-	out = append(out, rb.events[:rb.index]...)
+// Tail returns up to n of the most recent events accepted by match (all
+// events when match is nil), oldest first. n <= 0 means no limit. Only the
+// returned events are copied.
+func (rb *RingBuffer) Tail(n int, match func(Event) bool) []Event {
+	rb.mu.RLock()
+	defer rb.mu.RUnlock()
+
+	total := rb.n
+	if n <= 0 || n > total {
+		n = total
+	}
+	out := make([]Event, 0, n)
+	for i := total - 1; i >= 0 && len(out) < n; i-- {
+		if e := rb.at(i); match == nil || match(e) {
+			out = append(out, e)
+		}
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return out
+}
+
+// After returns up to limit buffered events with a sequence number above
+// seq, oldest first, so a client can page through the buffer or poll for
+// what it has not seen. limit <= 0 means no limit. The buffer is scanned from
+// the newest event back, so the cost follows the number of newer events, not
+// the buffer size; it relies on events being added in seq order (AddNext).
+func (rb *RingBuffer) After(seq uint64, limit int) []Event {
+	rb.mu.RLock()
+	defer rb.mu.RUnlock()
+
+	total := rb.n
+	first := total
+	for first > 0 && rb.at(first-1).Seq > seq {
+		first--
+	}
+	end := total
+	if limit > 0 && first+limit < end {
+		end = first + limit
+	}
+	out := make([]Event, 0, end-first)
+	for i := first; i < end; i++ {
+		out = append(out, rb.at(i))
+	}
 	return out
 }
 
+// Lookup returns the buffered event with the given sequence number.
+func (rb *RingBuffer) Lookup(seq uint64) (Event, bool) {
+	rb.mu.RLock()
+	defer rb.mu.RUnlock()
+
+	for _, e := range rb.events {
+		if e.Seq == seq && e.Seq != 0 {
+			return e, true
+		}
+	}
+	return Event{}, false
+}
+
 // Clear resets the buffer to empty state.
 func (rb *RingBuffer) Clear() {
 	rb.mu.Lock()
 	defer rb.mu.Unlock()
 	rb.events = make([]Event, rb.size)
-	rb.index = 0
-	rb.full = false
+	rb.sizes = make([]int64, rb.size)
+	rb.start, rb.n, rb.bytes = 0, 0, 0
+}
+
+// approxEventSize estimates the memory an event holds: its strings and
+// fields plus a fixed overhead. It is cheap rather than exact.
+func approxEventSize(e Event) int64 {
+	const eventOverhead = 160
+	return eventOverhead + int64(len(e.Timestamp)+len(e.Level)+len(e.Name)+len(e.Message)+len(e.SessionID)) +
+		approxValueSize(e.Fields)
+}
+
+// approxValueSize estimates the memory of a decoded JSON-like value.
+func approxValueSize(v interface{}) int64 {
+	const valueOverhead = 16
+	switch v := v.(type) {
+	case string:
+		return valueOverhead + int64(len(v))
+	case map[string]interface{}:
+		size := int64(48)
+		for k, x := range v {
+			size += valueOverhead + int64(len(k)) + approxValueSize(x)
+		}
+		return size
+	case []interface{}:
+		size := int64(24)
+		for _, x := range v {
+			size += approxValueSize(x)
+		}
+		return size
+	case []string:
+		size := int64(24)
+		for _, s := range v {
+			size += valueOverhead + int64(len(s))
+		}
+		return size
+	default:
+		return valueOverhead
+	}
 }
@@ -0,0 +1,177 @@
+package events
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+// filledBuffer returns a buffer of the given size holding n events with seq 1..n.
+func filledBuffer(size, n int) *RingBuffer {
+	rb := NewRingBuffer(size)
+	for i := 1; i <= n; i++ {
+		rb.Add(Event{Seq: uint64(i), Level: "info", Name: "node.started"})
+	}
+	return rb
+}
+
+func seqs(evts []Event) []uint64 {
+	out := make([]uint64, len(evts))
+	for i, e := range evts {
+		out[i] = e.Seq
+	}
+	return out
+}
+
+func equalSeqs(a, b []uint64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRingBuffer_Wraparound(t *testing.T) {
+	rb := filledBuffer(4, 6)
+
+	if got := seqs(rb.Snapshot()); !equalSeqs(got, []uint64{3, 4, 5, 6}) {
+		t.Errorf("Snapshot: got %v", got)
+	}
+	if got := seqs(rb.Tail(2, nil)); !equalSeqs(got, []uint64{5, 6}) {
+		t.Errorf("Tail(2): got %v", got)
+	}
+	odd := func(e Event) bool { return e.Seq%2 == 1 }
+	if got := seqs(rb.Tail(0, odd)); !equalSeqs(got, []uint64{3, 5}) {
+		t.Errorf("Tail matching: got %v", got)
+	}
+	if got := seqs(rb.After(4, 0)); !equalSeqs(got, []uint64{5, 6}) {
+		t.Errorf("After(4): got %v", got)
+	}
+	if got := seqs(rb.After(0, 3)); !equalSeqs(got, []uint64{3, 4, 5}) {
+		t.Errorf("After(0, 3): got %v", got)
+	}
+	if got := rb.After(6, 0); len(got) != 0 {
+		t.Errorf("After(6): expected nothing newer, got %v", seqs(got))
+	}
+}
+
+func TestRingBuffer_PartlyFilled(t *testing.T) {
+	rb := filledBuffer(8, 3)
+
+	if got := seqs(rb.Snapshot()); !equalSeqs(got, []uint64{1, 2, 3}) {
+		t.Errorf("Snapshot: got %v", got)
+	}
+	if got := seqs(rb.Tail(10, nil)); !equalSeqs(got, []uint64{1, 2, 3}) {
+		t.Errorf("Tail(10): got %v", got)
+	}
+	if got := seqs(rb.After(1, 1)); !equalSeqs(got, []uint64{2}) {
+		t.Errorf("After(1, 1): got %v", got)
+	}
+}
+
+func TestRingBuffer_AddNextConcurrent(t *testing.T) {
+	const writers, perWriter = 8, 200
+	rb := NewRingBuffer(writers * perWriter)
+	var seq uint64
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				rb.AddNext(Event{Name: "node.started"}, &seq)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Paging with After must visit every event exactly once, in order
+	var after uint64
+	for page := 0; ; page++ {
+		evts := rb.After(after, 50)
+		if len(evts) == 0 {
+			break
+		}
+		for _, e := range evts {
+			if e.Seq != after+1 {
+				t.Fatalf("page %d: expected seq %d, got %d", page, after+1, e.Seq)
+			}
+			after = e.Seq
+		}
+	}
+	if after != writers*perWriter {
+		t.Errorf("expected to page through %d events, stopped at %d", writers*perWriter, after)
+	}
+}
+
+const benchBufferSize = 10000
+
+func BenchmarkRingBuffer_Snapshot(b *testing.B) {
+	rb := filledBuffer(benchBufferSize, 2*benchBufferSize)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = rb.Snapshot()
+	}
+}
+
+func BenchmarkRingBuffer_Tail(b *testing.B) {
+	rb := filledBuffer(benchBufferSize, 2*benchBufferSize)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = rb.Tail(50, nil)
+	}
+}
+
+func BenchmarkRingBuffer_TailFiltered(b *testing.B) {
+	rb := filledBuffer(benchBufferSize, 2*benchBufferSize)
+	match := func(e Event) bool { return e.Seq%100 == 0 }
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = rb.Tail(50, match)
+	}
+}
+
+func BenchmarkRingBuffer_After(b *testing.B) {
+	rb := filledBuffer(benchBufferSize, 2*benchBufferSize)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = rb.After(2*benchBufferSize-20, 0)
+	}
+}
+
+func TestRingBuffer_ByteCap(t *testing.T) {
+	rb := NewRingBuffer(10)
+	big := Event{Name: "node.started", Fields: map[string]interface{}{"blob": strings.Repeat("x", 1000)}}
+	size := approxEventSize(big)
+	rb.Resize(10, 3*size)
+
+	for i := 1; i <= 5; i++ {
+		big.Seq = uint64(i)
+		rb.Add(big)
+	}
+	n, bytes, shed := rb.Stats()
+	if n != 3 || bytes != 3*size || shed != 2 {
+		t.Errorf("expected 3 events kept and 2 shed, got %d events, %d bytes, %d shed", n, bytes, shed)
+	}
+	if got := seqs(rb.Snapshot()); !equalSeqs(got, []uint64{3, 4, 5}) {
+		t.Errorf("expected the oldest events shed, got %v", got)
+	}
+	if _, ok := rb.Lookup(1); ok {
+		t.Error("expected a shed event no longer found")
+	}
+
+	// Shrinking keeps the most recent events
+	rb.Resize(2, 0)
+	if got := seqs(rb.Snapshot()); !equalSeqs(got, []uint64{4, 5}) {
+		t.Errorf("expected the newest 2 events kept, got %v", got)
+	}
+	rb.Add(Event{Seq: 6})
+	if got := seqs(rb.Snapshot()); !equalSeqs(got, []uint64{5, 6}) {
+		t.Errorf("expected the buffer to wrap at its new size, got %v", got)
+	}
+}
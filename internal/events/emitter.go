@@ -7,7 +7,7 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/AaronLay10/SentientEngine/internal/storage/postgres"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
 )
 
 var buffer = NewRingBuffer(256)
@@ -15,86 +15,112 @@ var buffer = NewRingBuffer(256)
 // eventsTotal tracks the total number of events emitted since startup.
 var eventsTotal uint64
 
+// lastSeq is the sequence number of the most recently emitted event.
+// It resumes from the highest stored seq when a store is attached, so
+// /events/{seq} links stay valid across restarts.
+var lastSeq uint64
+
 var (
-	pgClient      *postgres.Client
-	pgMu          sync.RWMutex
-	pgErrorLogged bool
+	activeStore storage.Store
+	storeMu     sync.RWMutex
 )
 
-// SetPostgresClient sets the Postgres client for event persistence.
-func SetPostgresClient(client *postgres.Client) {
-	pgMu.Lock()
-	pgClient = client
-	pgMu.Unlock()
+// SetStore sets the store events are persisted to (nil for none). Events
+// queued for a previous store are written to it first. Events spilled by a
+// previous run are replayed before SetStore returns, so the store holds them
+// when the session is restored from it.
+func SetStore(store storage.Store) {
+	var w *eventWriter
+	if store != nil {
+		storeMu.RLock()
+		dir := spillDir
+		storeMu.RUnlock()
+		spill := openSpill(dir)
+
+		if stored, err := store.MaxSeq(); err == nil {
+			advanceSeq(stored)
+		}
+		// Events spilled by a previous run are not stored yet
+		advanceSeq(spill.maxSeq)
+		// Stored events read back under their current names
+		store.SetEventAliases(Aliases())
+		w = newEventWriter(store, spill)
+		w.replaySpill()
+		w.start()
+	}
+
+	storeMu.Lock()
+	old := writer
+	activeStore = store
+	writer = w
+	storeMu.Unlock()
+	if old != nil {
+		old.stop()
+	}
+}
+
+// advanceSeq moves lastSeq forward to at least seq.
+func advanceSeq(seq uint64) {
+	for {
+		cur := atomic.LoadUint64(&lastSeq)
+		if cur >= seq || atomic.CompareAndSwapUint64(&lastSeq, cur, seq) {
+			return
+		}
+	}
 }
 
-// GetPostgresClient returns the current Postgres client (for API queries).
-func GetPostgresClient() *postgres.Client {
-	pgMu.RLock()
-	defer pgMu.RUnlock()
-	return pgClient
+// GetStore returns the store events are persisted to, or nil (for API
+// queries).
+func GetStore() storage.Store {
+	storeMu.RLock()
+	defer storeMu.RUnlock()
+	return activeStore
 }
 
 type Event struct {
+	Seq       uint64                 `json:"seq,omitempty"`
 	Timestamp string                 `json:"ts"`
 	Level     string                 `json:"level"`
 	Name      string                 `json:"event"`
 	Message   string                 `json:"msg,omitempty"`
 	Fields    map[string]interface{} `json:"fields,omitempty"`
+	SessionID string                 `json:"session_id,omitempty"`
 }
 
 func Emit(level, name, msg string, fields map[string]interface{}) ([]byte, error) {
+	name = Translate(name, "emit")
 	if err := Validate(name); err != nil {
 		return nil, err
 	}
 
 	ts := time.Now().UTC()
-	e := Event{
+	e := buffer.AddNext(Event{
 		Timestamp: ts.Format(time.RFC3339Nano),
 		Level:     level,
 		Name:      name,
 		Message:   msg,
 		Fields:    fields,
-	}
-
-	buffer.Add(e)
+		SessionID: CurrentSession(),
+	}, &lastSeq)
 	atomic.AddUint64(&eventsTotal, 1)
+	if level == "warning" {
+		countWarning(e)
+	}
 
 	// Broadcast to WebSocket subscribers
 	broadcast(e)
 
-	// Persist to Postgres (non-blocking, error-resistant)
-	pgMu.RLock()
-	client := pgClient
-	errorLogged := pgErrorLogged
-	pgMu.RUnlock()
-
-	if client != nil {
-		if err := client.Append(ts, level, name, msg, fields, ""); err != nil {
-			// Log error once to avoid spam.
-			// IMPORTANT: We add directly to buffer.Add() here, NOT Emit(),
-			// to avoid infinite recursion if Postgres keeps failing.
-			if !errorLogged {
-				pgMu.Lock()
-				if !pgErrorLogged {
-					pgErrorLogged = true
-					pgMu.Unlock()
-					// Add system.error directly to ring buffer (bypasses DB append)
-					errEvent := Event{
-						Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
-						Level:     "error",
-						Name:      "system.error",
-						Message:   "postgres append failed",
-						Fields: map[string]interface{}{
-							"error": err.Error(),
-						},
-					}
-					buffer.Add(errEvent) // Direct add, no recursion
-				} else {
-					pgMu.Unlock()
-				}
-			}
-		}
+	// Notify in-process listeners (e.g. bridge rules)
+	notifyListeners(e)
+
+	// Persist in the background (see persist.go)
+	storeMu.RLock()
+	w := writer
+	storeMu.RUnlock()
+	if w != nil {
+		w.enqueue(storage.EventInsert{
+			Seq: e.Seq, Timestamp: ts, Level: level, Event: name, Message: msg, Fields: fields, SessionID: e.SessionID,
+		})
 	}
 
 	b, err := json.Marshal(e)
@@ -105,10 +131,86 @@ func Emit(level, name, msg string, fields map[string]interface{}) ([]byte, error
 	return b, nil
 }
 
+// EmitLive streams a high-volume, low-priority event (controller logs) to
+// WebSocket subscribers only. The event gets no seq and is neither buffered,
+// passed to listeners nor persisted; its producer keeps its own history.
+func EmitLive(level, name, msg string, fields map[string]interface{}) error {
+	name = Translate(name, "emit")
+	if err := Validate(name); err != nil {
+		return err
+	}
+	broadcast(Event{
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		Level:     level,
+		Name:      name,
+		Message:   msg,
+		Fields:    fields,
+		SessionID: CurrentSession(),
+	})
+	return nil
+}
+
+// Listener is called synchronously for every emitted event.
+// Listeners must not block and must not call Emit directly.
+type Listener func(Event)
+
+var (
+	listenersMu sync.RWMutex
+	listeners   = make(map[int]Listener)
+	nextID      int
+)
+
+// AddListener registers a listener and returns a function that removes it.
+func AddListener(l Listener) func() {
+	listenersMu.Lock()
+	id := nextID
+	nextID++
+	listeners[id] = l
+	listenersMu.Unlock()
+
+	return func() {
+		listenersMu.Lock()
+		delete(listeners, id)
+		listenersMu.Unlock()
+	}
+}
+
+// notifyListeners passes an event to every registered listener.
+func notifyListeners(e Event) {
+	listenersMu.RLock()
+	defer listenersMu.RUnlock()
+	for _, l := range listeners {
+		l(e)
+	}
+}
+
 func Snapshot() []Event {
 	return buffer.Snapshot()
 }
 
+// After returns up to limit buffered events with a sequence number above seq,
+// oldest first (limit <= 0 for all).
+func After(seq uint64, limit int) []Event {
+	return buffer.After(seq, limit)
+}
+
+// SetBufferLimits sets how many events are kept in memory and their
+// approximate byte cap (0 for none), keeping the most recent events.
+func SetBufferLimits(size int, maxBytes int64) {
+	buffer.Resize(size, maxBytes)
+}
+
+// BufferStats returns the number of buffered events, their approximate size
+// in bytes and how many have been shed for the byte cap since startup.
+func BufferStats() (events int, bytes int64, shed uint64) {
+	return buffer.Stats()
+}
+
+// Lookup returns a recent event by sequence number, if it is still buffered.
+func Lookup(seq uint64) (Event, bool) {
+	return buffer.Lookup(seq)
+}
+
 // Clear resets the event buffer. Used for testing.
 func Clear() {
 	buffer.Clear()
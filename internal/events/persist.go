@@ -0,0 +1,343 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+const (
+	persistQueueSize     = 4096
+	persistBatchSize     = 100
+	persistFlushInterval = 50 * time.Millisecond
+	persistProbeInterval = 5 * time.Second
+)
+
+// eventStore stores batches of events (implemented by storage.Store).
+type eventStore interface {
+	AppendBatch(batch []storage.EventInsert) error
+	Ping() error
+}
+
+// eventWriter writes queued events to a store in batches, off the emit path,
+// so a burst of events costs one write per batch and Emit never waits on the
+// store. A batch is written in seq order once it holds persistBatchSize
+// events or persistFlushInterval after its first event. While the store is
+// down batches are spilled, and it is probed every persistProbeInterval.
+type eventWriter struct {
+	store   eventStore
+	queue   chan storage.EventInsert
+	flushCh chan chan struct{}
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+	pending atomic.Int64 // queued or being written
+	spilled atomic.Int64 // in the spill file
+
+	// used only by the worker
+	spill         *spillFile
+	down          bool
+	probeInterval time.Duration
+}
+
+var (
+	writer   *eventWriter // guarded by storeMu
+	spillDir string       // guarded by storeMu
+
+	// storeStateCallback is told when persistence loses or regains the
+	// store (guarded by storeMu).
+	storeStateCallback func(connected bool)
+)
+
+// SetSpillDir sets the directory events are spilled to while the store is
+// down (empty disables spilling). Set it before SetStore.
+func SetSpillDir(dir string) {
+	storeMu.Lock()
+	spillDir = dir
+	storeMu.Unlock()
+}
+
+// SetStoreStateCallback sets a function called when event persistence
+// loses the store and when it reconnects. It is called from the writer's
+// goroutine and must not block.
+func SetStoreStateCallback(fn func(connected bool)) {
+	storeMu.Lock()
+	storeStateCallback = fn
+	storeMu.Unlock()
+}
+
+func newEventWriter(store eventStore, spill *spillFile) *eventWriter {
+	w := &eventWriter{
+		store:         store,
+		queue:         make(chan storage.EventInsert, persistQueueSize),
+		flushCh:       make(chan chan struct{}),
+		stopCh:        make(chan struct{}),
+		spill:         spill,
+		probeInterval: persistProbeInterval,
+	}
+	w.spilled.Store(int64(spill.count))
+	return w
+}
+
+// start starts the worker.
+func (w *eventWriter) start() *eventWriter {
+	w.wg.Add(1)
+	go w.run()
+	return w
+}
+
+// enqueue queues an event without blocking, counting it as skipped if the
+// queue is full.
+func (w *eventWriter) enqueue(e storage.EventInsert) {
+	w.pending.Add(1)
+	select {
+	case w.queue <- e:
+	default:
+		w.pending.Add(-1)
+		noteWarning(WarningPersistenceSkipped, "event store write queue full")
+	}
+}
+
+// flush waits until the events queued so far are written.
+func (w *eventWriter) flush() {
+	ack := make(chan struct{})
+	select {
+	case w.flushCh <- ack:
+		<-ack
+	case <-w.stopCh:
+	}
+}
+
+// stop writes the queued events and stops the worker.
+func (w *eventWriter) stop() {
+	close(w.stopCh)
+	w.wg.Wait()
+}
+
+func (w *eventWriter) run() {
+	defer w.wg.Done()
+
+	batch := make([]storage.EventInsert, 0, persistBatchSize)
+	timer := time.NewTimer(persistFlushInterval)
+	timer.Stop()
+	probe := time.NewTicker(w.probeInterval)
+	defer probe.Stop()
+
+	write := func() {
+		timer.Stop()
+		if len(batch) == 0 {
+			return
+		}
+		w.write(batch)
+		w.pending.Add(-int64(len(batch)))
+		batch = batch[:0]
+	}
+	// drain writes everything queued right now
+	drain := func() {
+		for {
+			select {
+			case e := <-w.queue:
+				batch = append(batch, e)
+				if len(batch) >= persistBatchSize {
+					write()
+				}
+			default:
+				write()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case e := <-w.queue:
+			batch = append(batch, e)
+			if len(batch) == 1 {
+				timer.Reset(persistFlushInterval)
+			}
+			if len(batch) >= persistBatchSize {
+				write()
+			}
+		case <-timer.C:
+			write()
+		case <-probe.C:
+			if w.down {
+				w.recover()
+			}
+		case ack := <-w.flushCh:
+			drain()
+			close(ack)
+		case <-w.stopCh:
+			drain()
+			return
+		}
+	}
+}
+
+// write stores one batch, or spills it while the store is down.
+func (w *eventWriter) write(batch []storage.EventInsert) {
+	batch = encodable(batch)
+	if len(batch) == 0 {
+		return
+	}
+	if !w.down {
+		err := w.store.AppendBatch(batch)
+		if err == nil {
+			return
+		}
+		if w.store.Ping() == nil {
+			// The store is up, so it rejected the batch itself
+			w.writeEach(batch, err)
+			return
+		}
+		w.setDown(err, batch[len(batch)-1].SessionID)
+	}
+	if err := w.spill.append(batch); err != nil {
+		for range batch {
+			noteWarning(WarningPersistenceSkipped, err.Error())
+		}
+		return
+	}
+	w.spilled.Add(int64(len(batch)))
+}
+
+// writeEach stores the events of a batch the store rejected one at a time,
+// so only those it rejects again are lost (counted as persistence_skipped).
+func (w *eventWriter) writeEach(batch []storage.EventInsert, batchErr error) {
+	log.Printf("[events] store rejected a batch of %d events, writing them one at a time: %v", len(batch), batchErr)
+	for i := range batch {
+		if err := w.store.AppendBatch(batch[i : i+1]); err != nil {
+			noteWarning(WarningPersistenceSkipped, fmt.Sprintf("event %d: %v", batch[i].Seq, err))
+		}
+	}
+}
+
+// encodable drops, counting each as persistence_skipped, the events of batch
+// whose fields cannot be encoded as JSON (such as a NaN), so one bad event
+// does not fail the batch it is in. It filters batch in place.
+func encodable(batch []storage.EventInsert) []storage.EventInsert {
+	out := batch[:0]
+	for _, e := range batch {
+		if _, err := json.Marshal(e.Fields); err != nil {
+			log.Printf("[events] not persisting event %d (%s): %v", e.Seq, e.Event, err)
+			noteWarning(WarningPersistenceSkipped, fmt.Sprintf("event %d: %v", e.Seq, err))
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// replaySpill replays events spilled by a previous run before any new ones
+// are written. If the store does not take them, it is treated as down.
+// Call before start.
+func (w *eventWriter) replaySpill() {
+	if w.spill.count > 0 {
+		w.down = true
+		w.recover()
+	}
+}
+
+// recover replays the spill file once the store answers again and resumes
+// writing directly.
+func (w *eventWriter) recover() {
+	if err := w.store.Ping(); err != nil {
+		return
+	}
+	n, err := w.spill.replay(w.store)
+	w.spilled.Store(int64(w.spill.count))
+	if err != nil {
+		log.Printf("[events] replay of spilled events failed after %d: %v", n, err)
+		return
+	}
+	if n > 0 {
+		log.Printf("[events] replayed %d spilled events into the store", n)
+	}
+	w.down = false
+	notifyStoreState(true)
+}
+
+// setDown marks the store as down. The outage is reported as system.error,
+// added straight to the buffer rather than emitted so it is not persisted
+// (and cannot fail again) itself.
+func (w *eventWriter) setDown(err error, sessionID string) {
+	w.down = true
+	notifyStoreState(false)
+
+	fields := map[string]interface{}{
+		"error": err.Error(),
+	}
+	if w.spill.path != "" {
+		fields["spill_file"] = w.spill.path
+	}
+	buffer.AddNext(Event{
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		Level:     "error",
+		Name:      "system.error",
+		Message:   "event store append failed",
+		Fields:    fields,
+		SessionID: sessionID,
+	}, &lastSeq)
+}
+
+func notifyStoreState(connected bool) {
+	storeMu.RLock()
+	fn := storeStateCallback
+	storeMu.RUnlock()
+	if fn != nil {
+		fn(connected)
+	}
+}
+
+// PersistQueueLen returns the number of events waiting to be written to the
+// store.
+func PersistQueueLen() int {
+	storeMu.RLock()
+	w := writer
+	storeMu.RUnlock()
+	if w == nil {
+		return 0
+	}
+	return int(w.pending.Load())
+}
+
+// SpilledEvents returns the number of events in the spill file waiting to be
+// replayed into the store.
+func SpilledEvents() int {
+	storeMu.RLock()
+	w := writer
+	storeMu.RUnlock()
+	if w == nil {
+		return 0
+	}
+	return int(w.spilled.Load())
+}
+
+// FlushStore waits until the events emitted so far are written to the
+// store (or spilled, or have failed to be).
+func FlushStore() {
+	storeMu.RLock()
+	w := writer
+	storeMu.RUnlock()
+	if w != nil {
+		w.flush()
+	}
+}
+
+// CloseStore writes the events still queued, spilling them if the store is
+// down, and stops persisting. Call it before closing the store; events
+// emitted afterwards are only buffered in memory.
+func CloseStore() {
+	storeMu.Lock()
+	w := writer
+	writer = nil
+	activeStore = nil
+	storeMu.Unlock()
+	if w != nil {
+		w.stop()
+	}
+}
@@ -0,0 +1,320 @@
+package events
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// fakeStore records the batches written to it; while err is set it fails.
+type fakeStore struct {
+	mu      sync.Mutex
+	batches [][]storage.EventInsert
+	err     error
+}
+
+func (f *fakeStore) AppendBatch(batch []storage.EventInsert) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.batches = append(f.batches, append([]storage.EventInsert(nil), batch...))
+	return nil
+}
+
+func (f *fakeStore) Ping() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.err
+}
+
+func (f *fakeStore) setErr(err error) {
+	f.mu.Lock()
+	f.err = err
+	f.mu.Unlock()
+}
+
+// seqs returns the seq of every event stored, in order.
+func (f *fakeStore) seqs() []uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var seqs []uint64
+	for _, batch := range f.batches {
+		for _, e := range batch {
+			seqs = append(seqs, e.Seq)
+		}
+	}
+	return seqs
+}
+
+// useStore persists emitted events to store until the test ends, spilling
+// them to dir (if set) while it fails.
+func useStore(t *testing.T, store eventStore, dir string) {
+	t.Helper()
+	w := newEventWriter(store, openSpill(dir))
+	w.probeInterval = 10 * time.Millisecond
+	w.replaySpill()
+	storeMu.Lock()
+	writer = w.start()
+	storeMu.Unlock()
+	t.Cleanup(CloseStore)
+}
+
+// waitFor polls cond for up to two seconds.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPersistBatchesInOrder(t *testing.T) {
+	store := &fakeStore{}
+	useStore(t, store, "")
+
+	for i := 0; i < 250; i++ {
+		Emit("info", "puzzle.activated", "", map[string]interface{}{"node_id": "puzzle_scarab"})
+	}
+	FlushStore()
+	if n := PersistQueueLen(); n != 0 {
+		t.Errorf("expected nothing queued after a flush, got %d", n)
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	var total int
+	var last uint64
+	for _, batch := range store.batches {
+		if len(batch) > persistBatchSize {
+			t.Errorf("expected batches of at most %d, got %d", persistBatchSize, len(batch))
+		}
+		for _, e := range batch {
+			if e.Seq <= last || e.Event != "puzzle.activated" || e.Fields["node_id"] != "puzzle_scarab" {
+				t.Fatalf("unexpected event after seq %d: %+v", last, e)
+			}
+			last = e.Seq
+			total++
+		}
+	}
+	if total != 250 || len(store.batches) >= 250 {
+		t.Errorf("expected 250 events in a few batches, got %d in %d", total, len(store.batches))
+	}
+}
+
+func TestPersistFailure(t *testing.T) {
+	ClearWarnings()
+	defer ClearWarnings()
+	Clear()
+
+	store := &fakeStore{err: errors.New("connection refused")}
+	useStore(t, store, "")
+
+	Emit("info", "puzzle.activated", "", nil)
+	FlushStore()
+	Emit("info", "puzzle.activated", "", nil)
+	FlushStore()
+
+	var skipped uint64
+	for _, w := range WarningSummary() {
+		if w.Type == WarningPersistenceSkipped {
+			skipped = w.Count
+		}
+	}
+	if skipped != 2 {
+		t.Errorf("expected 2 events counted as skipped, got %d", skipped)
+	}
+	var errs int
+	for _, e := range Snapshot() {
+		if e.Name == "system.error" && e.Message == "event store append failed" {
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Errorf("expected the failure reported once, got %d", errs)
+	}
+
+	// Closing writes nothing more and stops persisting
+	CloseStore()
+	Emit("info", "puzzle.activated", "", nil)
+	if PersistQueueLen() != 0 {
+		t.Error("expected nothing queued once closed")
+	}
+}
+
+func TestPersistSkipsUnencodableEvent(t *testing.T) {
+	ClearWarnings()
+	defer ClearWarnings()
+	Clear()
+
+	store := &fakeStore{}
+	useStore(t, store, "")
+
+	Emit("info", "device.input", "", map[string]interface{}{"value": 1.0})
+	Emit("info", "device.input", "", map[string]interface{}{"value": math.NaN()})
+	Emit("info", "device.input", "", map[string]interface{}{"value": 2.0})
+	FlushStore()
+
+	got := store.seqs()
+	if len(got) != 2 {
+		t.Fatalf("expected the two valid events stored, got seqs %v", got)
+	}
+	var skipped uint64
+	for _, w := range WarningSummary() {
+		if w.Type == WarningPersistenceSkipped {
+			skipped = w.Count
+		}
+	}
+	if skipped != 1 {
+		t.Errorf("expected only the NaN event skipped, got %d", skipped)
+	}
+}
+
+// rejectingStore answers pings but rejects any batch holding an event named
+// reject.
+type rejectingStore struct {
+	fakeStore
+	reject string
+}
+
+func (r *rejectingStore) AppendBatch(batch []storage.EventInsert) error {
+	for _, e := range batch {
+		if e.Event == r.reject {
+			return errors.New("invalid input syntax")
+		}
+	}
+	return r.fakeStore.AppendBatch(batch)
+}
+
+func TestPersistRejectedBatchIsNotAnOutage(t *testing.T) {
+	ClearWarnings()
+	defer ClearWarnings()
+	Clear()
+
+	var downs int
+	SetStoreStateCallback(func(connected bool) {
+		if !connected {
+			downs++
+		}
+	})
+	defer SetStoreStateCallback(nil)
+	store := &rejectingStore{reject: "puzzle.progress"}
+	useStore(t, store, t.TempDir())
+
+	Emit("info", "puzzle.activated", "", nil)
+	Emit("info", "puzzle.progress", "", nil)
+	Emit("info", "puzzle.solved", "", nil)
+	FlushStore()
+
+	if got := store.seqs(); len(got) != 2 {
+		t.Errorf("expected the two accepted events stored, got seqs %v", got)
+	}
+	if downs != 0 || SpilledEvents() != 0 {
+		t.Errorf("expected no outage, got %d down notifications and %d spilled", downs, SpilledEvents())
+	}
+	for _, e := range Snapshot() {
+		if e.Name == "system.error" {
+			t.Errorf("expected no system.error, got %+v", e)
+		}
+	}
+	for _, w := range WarningSummary() {
+		if w.Type == WarningPersistenceSkipped && w.Count != 1 {
+			t.Errorf("expected only the rejected event skipped, got %d", w.Count)
+		}
+	}
+}
+
+func TestPersistSpillAndReplay(t *testing.T) {
+	Clear()
+	dir := t.TempDir()
+	store := &fakeStore{err: errors.New("connection refused")}
+
+	var mu sync.Mutex
+	var states []bool
+	SetStoreStateCallback(func(connected bool) {
+		mu.Lock()
+		states = append(states, connected)
+		mu.Unlock()
+	})
+	defer SetStoreStateCallback(nil)
+	useStore(t, store, dir)
+
+	var want []uint64
+	for i := 0; i < 3; i++ {
+		Emit("info", "puzzle.activated", "", map[string]interface{}{"node_id": "puzzle_scarab"})
+		want = append(want, atomic.LoadUint64(&lastSeq))
+		FlushStore()
+	}
+	if n := SpilledEvents(); n != 3 {
+		t.Fatalf("expected 3 events spilled while down, got %d", n)
+	}
+	if _, err := os.Stat(filepath.Join(dir, spillFileName)); err != nil {
+		t.Fatalf("expected a spill file: %v", err)
+	}
+
+	// The store comes back: the spill file is replayed before new events
+	store.setErr(nil)
+	waitFor(t, "replay", func() bool { return SpilledEvents() == 0 })
+	Emit("info", "puzzle.solved", "", nil)
+	want = append(want, atomic.LoadUint64(&lastSeq))
+	FlushStore()
+
+	if got := store.seqs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected seqs %v stored in order, got %v", want, got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, spillFileName)); !os.IsNotExist(err) {
+		t.Errorf("expected the spill file removed after replay, got %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(states, []bool{false, true}) {
+		t.Errorf("expected down then up, got %v", states)
+	}
+}
+
+func TestPersistReplaysLeftoverSpill(t *testing.T) {
+	dir := t.TempDir()
+	left := openSpill(dir)
+	if err := left.append([]storage.EventInsert{
+		{Seq: 7, Timestamp: time.Now(), Level: "info", Event: "puzzle.solved", Fields: map[string]interface{}{"node_id": "puzzle_scarab"}},
+		{Seq: 8, Timestamp: time.Now(), Level: "info", Event: "scene.completed"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// A record cut short by a crash is skipped
+	f, err := os.OpenFile(left.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"seq":9,"ts":`)
+	f.Close()
+
+	spill := openSpill(dir)
+	if spill.count != 2 || spill.maxSeq != 8 {
+		t.Fatalf("expected 2 events up to seq 8, got %d up to %d", spill.count, spill.maxSeq)
+	}
+
+	store := &fakeStore{}
+	// They are stored before the writer starts, ahead of any restore
+	useStore(t, store, dir)
+	if got := store.seqs(); !reflect.DeepEqual(got, []uint64{7, 8}) {
+		t.Errorf("expected the leftover events replayed, got %v", got)
+	}
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.batches[0][0].Fields["node_id"] != "puzzle_scarab" {
+		t.Errorf("expected fields to survive the spill file, got %v", store.batches[0][0].Fields)
+	}
+}
@@ -11,46 +11,85 @@ var allowedEvents = map[string]struct{}{
 	"node.overridden": {},
 
 	// puzzle
-	"puzzle.activated": {},
-	"puzzle.solved":    {},
-	"puzzle.failed":    {},
-	"puzzle.reset":     {},
-	"puzzle.overridden": {},
+	"puzzle.activated":        {},
+	"puzzle.solved":           {},
+	"puzzle.failed":           {},
+	"puzzle.reset":            {},
+	"puzzle.overridden":       {},
+	"puzzle.progress":         {},
+	"puzzle.step":             {},
+	"puzzle.input_suppressed": {},
+	"puzzle.hint":             {},
 
 	// scene
 	"scene.started":   {},
 	"scene.completed": {},
+	"scene.advanced":  {},
 	"scene.failed":    {},
 	"scene.reset":     {},
+	"scene.stalled":   {},
 
 	// loop
 	"loop.started": {},
 	"loop.tick":    {},
 	"loop.stopped": {},
 
+	// room
+	"room.maintenance_started":   {},
+	"room.maintenance_ended":     {},
+	"room.federated":             {},
+	"room.competition_armed":     {},
+	"room.competition_cancelled": {},
+	"room.mode_changed":          {},
+	"room.routine_started":       {},
+	"room.routine_finished":      {},
+
 	// timer
 	"timer.started":   {},
 	"timer.expired":  {},
 	"timer.cancelled": {},
+	"timer.paused":    {},
+	"timer.resumed":   {},
+	"timer.adjusted":  {},
+	"timer.sync":      {},
 
 	// operator
-	"operator.override": {},
-	"operator.reset":    {},
-	"operator.jump":     {},
-	"operator.pause":    {},
-	"operator.resume":   {},
+	"operator.override":           {},
+	"operator.reset":              {},
+	"operator.jump":               {},
+	"operator.pause":              {},
+	"operator.resume":             {},
+	"operator.approval_requested": {},
+	"operator.approved":           {},
+	"operator.message":            {},
+	"operator.undo":               {},
+
+	// variable
+	"variable.set": {},
 
 	// device
-	"device.connected":    {},
-	"device.disconnected": {},
-	"device.input":        {},
-	"device.error":        {},
+	"device.connected":      {},
+	"device.disconnected":   {},
+	"device.input":          {},
+	"device.error":          {},
+	"device.state_changed":  {},
+	"device.state_mismatch": {},
+	"device.log":            {},
 
 	// system
 	"system.startup":         {},
 	"system.shutdown":        {},
 	"system.error":           {},
 	"system.startup_restore": {},
+	"system.warning":         {},
+	"system.reconciled":      {},
+}
+
+// aliases maps legacy event names to the names that replaced them. A renamed
+// event keeps its old name here so existing scene graphs, room config and
+// stored events still work; using an alias is reported as deprecated.
+var aliases = map[string]string{
+	// "<legacy name>": "<current name>",
 }
 
 func Validate(event string) error {
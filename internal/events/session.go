@@ -0,0 +1,87 @@
+package events
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Session results recorded when a game ends.
+const (
+	SessionCompleted = "completed" // the scene reached its terminal
+	SessionExpired   = "expired"   // the game clock ran out first
+	SessionStopped   = "stopped"   // the operator stopped the game
+)
+
+var (
+	sessionMu sync.RWMutex
+	sessionID string
+)
+
+// NewSessionID returns a random (version 4) UUID.
+func NewSessionID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand does not fail on supported platforms
+		panic(fmt.Sprintf("session id: %v", err))
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// StartSession begins a game session: every event emitted until EndSession
+// carries the returned session ID, and the session is recorded in the store.
+func StartSession(sceneID string) string {
+	id := NewSessionID()
+	sessionMu.Lock()
+	sessionID = id
+	sessionMu.Unlock()
+
+	if client := GetStore(); client != nil {
+		if err := client.StartSession(id, sceneID, time.Now().UTC()); err != nil {
+			Emit("error", "system.error", "failed to record session start", map[string]interface{}{
+				"session_id": id,
+				"error":      err.Error(),
+			})
+		}
+	}
+	return id
+}
+
+// ResumeSession makes id the current session again after a restart,
+// without recording a new session.
+func ResumeSession(id string) {
+	sessionMu.Lock()
+	sessionID = id
+	sessionMu.Unlock()
+}
+
+// EndSession records the current session's result and stops stamping
+// events with its ID. Does nothing outside a session.
+func EndSession(result string) {
+	sessionMu.Lock()
+	id := sessionID
+	sessionID = ""
+	sessionMu.Unlock()
+
+	if id == "" {
+		return
+	}
+	if client := GetStore(); client != nil {
+		if err := client.EndSession(id, time.Now().UTC(), result); err != nil {
+			Emit("error", "system.error", "failed to record session end", map[string]interface{}{
+				"session_id": id,
+				"error":      err.Error(),
+			})
+		}
+	}
+}
+
+// CurrentSession returns the running session's ID, or "" between games.
+func CurrentSession() string {
+	sessionMu.RLock()
+	defer sessionMu.RUnlock()
+	return sessionID
+}
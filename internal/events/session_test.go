@@ -0,0 +1,40 @@
+package events
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestNewSessionID(t *testing.T) {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id := NewSessionID()
+		if !uuid.MatchString(id) {
+			t.Fatalf("not a version 4 UUID: %s", id)
+		}
+		if seen[id] {
+			t.Fatalf("duplicate session ID %s", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestEventsCarrySession(t *testing.T) {
+	Clear()
+	id := StartSession("scene_intro")
+	Emit("info", "scene.started", "", nil)
+	EndSession(SessionStopped)
+	Emit("info", "system.startup", "", nil)
+
+	snapshot := Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(snapshot))
+	}
+	if snapshot[0].SessionID != id {
+		t.Errorf("expected session %s on the first event, got %q", id, snapshot[0].SessionID)
+	}
+	if snapshot[1].SessionID != "" {
+		t.Errorf("expected no session after EndSession, got %q", snapshot[1].SessionID)
+	}
+}
@@ -0,0 +1,164 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// spillFileName is the name of the spill file in the spill directory.
+const spillFileName = "events.spill.jsonl"
+
+// spillFile is the append-only file events are spilled to while the store is
+// down, one JSON event per line and fsynced per batch. Once the store answers
+// it is replayed in seq order and removed; a file left by a previous run is
+// replayed at startup. It is used only by the writer's worker.
+type spillFile struct {
+	path   string // empty when spilling is disabled
+	count  int    // events waiting to be replayed
+	maxSeq uint64 // highest seq in the file
+}
+
+// openSpill opens the spill file in dir (empty disables spilling), counting
+// the events left in it by a previous run.
+func openSpill(dir string) *spillFile {
+	s := &spillFile{}
+	if dir == "" {
+		return s
+	}
+	s.path = filepath.Join(dir, spillFileName)
+	records, err := s.read()
+	if err != nil && !os.IsNotExist(err) {
+		noteWarning(WarningPersistenceSkipped, fmt.Sprintf("read spill file: %v", err))
+	}
+	s.count = len(records)
+	for _, e := range records {
+		if e.Seq > s.maxSeq {
+			s.maxSeq = e.Seq
+		}
+	}
+	return s
+}
+
+// append adds a batch to the end of the file.
+func (s *spillFile) append(batch []storage.EventInsert) error {
+	if s.path == "" {
+		return fmt.Errorf("no spill directory")
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := writeRecords(f, batch); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	s.count += len(batch)
+	for _, e := range batch {
+		if e.Seq > s.maxSeq {
+			s.maxSeq = e.Seq
+		}
+	}
+	return nil
+}
+
+// read returns the events in the file, skipping lines that do not decode
+// (such as one cut short by a crash).
+func (s *spillFile) read() ([]storage.EventInsert, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []storage.EventInsert
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var e storage.EventInsert
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			noteWarning(WarningPersistenceSkipped, fmt.Sprintf("unreadable spill record: %v", err))
+			continue
+		}
+		records = append(records, e)
+	}
+	return records, scanner.Err()
+}
+
+// replay writes the spilled events to store in batches and removes the file.
+// If a batch fails, the events not yet stored are kept for the next attempt.
+// It returns the number of events stored.
+func (s *spillFile) replay(store eventStore) (int, error) {
+	if s.path == "" || s.count == 0 {
+		return 0, nil
+	}
+	records, err := s.read()
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+	for i := 0; i < len(records); i += persistBatchSize {
+		end := min(i+persistBatchSize, len(records))
+		if err := store.AppendBatch(records[i:end]); err != nil {
+			if i > 0 {
+				if rerr := s.rewrite(records[i:]); rerr != nil {
+					return i, rerr
+				}
+			}
+			return i, err
+		}
+	}
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return len(records), err
+	}
+	s.count = 0
+	s.maxSeq = 0
+	return len(records), nil
+}
+
+// rewrite replaces the file with the given events.
+func (s *spillFile) rewrite(records []storage.EventInsert) error {
+	tmp := s.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := writeRecords(f, records); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+	s.count = len(records)
+	return nil
+}
+
+// writeRecords writes events to f one per line and syncs it.
+func writeRecords(f *os.File, records []storage.EventInsert) error {
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, e := range records {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return f.Sync()
+}
@@ -0,0 +1,161 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/syslog"
+	"strings"
+	"sync"
+)
+
+// WarningSyslogDropped counts events not written to syslog because its queue was full.
+const WarningSyslogDropped = "syslog_dropped"
+
+// syslogQueueSize is the number of events waiting to be written.
+const syslogQueueSize = 256
+
+// levelRank orders event levels by severity.
+var levelRank = map[string]int{
+	"debug":    0,
+	"info":     1,
+	"warning":  2,
+	"error":    3,
+	"critical": 4,
+}
+
+var syslogFacilities = map[string]syslog.Priority{
+	"daemon": syslog.LOG_DAEMON,
+	"user":   syslog.LOG_USER,
+	"local0": syslog.LOG_LOCAL0,
+	"local1": syslog.LOG_LOCAL1,
+	"local2": syslog.LOG_LOCAL2,
+	"local3": syslog.LOG_LOCAL3,
+	"local4": syslog.LOG_LOCAL4,
+	"local5": syslog.LOG_LOCAL5,
+	"local6": syslog.LOG_LOCAL6,
+	"local7": syslog.LOG_LOCAL7,
+}
+
+// SyslogSink forwards every event at or above a minimum level to syslog with
+// the matching severity (the local socket is also read by journald). Writes
+// happen on a worker so a slow or remote syslog never blocks Emit; events that
+// do not fit the queue are dropped and counted as syslog_dropped.
+type SyslogSink struct {
+	minRank int
+	w       *syslog.Writer
+	queue   chan Event
+	remove  func()
+	wg      sync.WaitGroup
+}
+
+// NewSyslogSink connects to syslog. An empty network writes to the local
+// syslog socket; otherwise network ("udp" or "tcp") and address name a remote
+// server. minLevel defaults to warning and facility to daemon.
+func NewSyslogSink(minLevel, network, address, facility, tag string) (*SyslogSink, error) {
+	if minLevel == "" {
+		minLevel = "warning"
+	}
+	rank, ok := levelRank[minLevel]
+	if !ok {
+		return nil, fmt.Errorf("syslog: unknown level %q", minLevel)
+	}
+	if facility == "" {
+		facility = "daemon"
+	}
+	prio, ok := syslogFacilities[facility]
+	if !ok {
+		return nil, fmt.Errorf("syslog: unknown facility %q", facility)
+	}
+	if network != "" && network != "udp" && network != "tcp" {
+		return nil, fmt.Errorf("syslog: unknown network %q (want udp or tcp)", network)
+	}
+	if (network == "") != (address == "") {
+		return nil, fmt.Errorf("syslog: network and address must be set together")
+	}
+
+	w, err := syslog.Dial(network, address, prio|syslog.LOG_WARNING, tag)
+	if err != nil {
+		return nil, fmt.Errorf("syslog: %w", err)
+	}
+	return &SyslogSink{
+		minRank: rank,
+		w:       w,
+		queue:   make(chan Event, syslogQueueSize),
+	}, nil
+}
+
+// Start begins forwarding emitted events.
+func (s *SyslogSink) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for e := range s.queue {
+			s.write(e)
+		}
+	}()
+
+	s.remove = AddListener(func(e Event) {
+		if rank, ok := levelRank[e.Level]; !ok || rank < s.minRank {
+			return
+		}
+		select {
+		case s.queue <- e:
+		default:
+			noteWarning(WarningSyslogDropped, e.Name)
+		}
+	})
+}
+
+// Stop stops forwarding, writes the queued events and closes the connection.
+func (s *SyslogSink) Stop() {
+	if s.remove != nil {
+		s.remove()
+	}
+	close(s.queue)
+	s.wg.Wait()
+	_ = s.w.Close()
+}
+
+// write sends one event with the severity of its level.
+func (s *SyslogSink) write(e Event) {
+	line := formatSyslogLine(e)
+	var err error
+	switch e.Level {
+	case "critical":
+		err = s.w.Crit(line)
+	case "error":
+		err = s.w.Err(line)
+	case "warning":
+		err = s.w.Warning(line)
+	case "info":
+		err = s.w.Info(line)
+	default:
+		err = s.w.Debug(line)
+	}
+	if err != nil {
+		noteWarning(WarningSyslogDropped, err.Error())
+	}
+}
+
+// formatSyslogLine renders an event as one line:
+// "<event> seq=<n> session=<id> <msg> <fields JSON>".
+func formatSyslogLine(e Event) string {
+	var b strings.Builder
+	b.WriteString(e.Name)
+	if e.Seq != 0 {
+		fmt.Fprintf(&b, " seq=%d", e.Seq)
+	}
+	if e.SessionID != "" {
+		b.WriteString(" session=" + e.SessionID)
+	}
+	if e.Message != "" {
+		b.WriteString(" " + e.Message)
+	}
+	if len(e.Fields) > 0 {
+		if f, err := json.Marshal(e.Fields); err == nil {
+			b.WriteString(" ")
+			b.Write(f)
+		}
+	}
+	return b.String()
+}
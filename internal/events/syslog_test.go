@@ -0,0 +1,60 @@
+package events
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen for syslog: %v", err)
+	}
+	defer conn.Close()
+
+	sink, err := NewSyslogSink("", "udp", conn.LocalAddr().String(), "local3", "sentient-test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sink.Start()
+
+	Emit("info", "system.startup", "not routed", nil)
+	Emit("error", "device.error", "sensor timeout", map[string]interface{}{"logical_id": "altar"})
+	sink.Stop()
+
+	buf := make([]byte, 2048)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("expected a syslog message: %v", err)
+	}
+	msg := string(buf[:n])
+	// local3 (19) * 8 + err (3)
+	if !strings.HasPrefix(msg, "<155>") {
+		t.Errorf("expected facility local3 and severity err, got %q", msg)
+	}
+	if !strings.Contains(msg, "sentient-test") || !strings.Contains(msg, `device.error seq=`) ||
+		!strings.Contains(msg, `sensor timeout {"logical_id":"altar"}`) {
+		t.Errorf("unexpected message: %q", msg)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if n, _, err := conn.ReadFrom(buf); err == nil {
+		t.Errorf("expected events below warning skipped, got %q", buf[:n])
+	}
+}
+
+func TestNewSyslogSink_InvalidConfig(t *testing.T) {
+	for name, args := range map[string][5]string{
+		"level":    {"loud", "udp", "127.0.0.1:514", "", ""},
+		"facility": {"", "udp", "127.0.0.1:514", "kern", ""},
+		"network":  {"", "unix", "/dev/log", "", ""},
+		"address":  {"", "udp", "", "", ""},
+	} {
+		if _, err := NewSyslogSink(args[0], args[1], args[2], args[3], args[4]); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Warning types counted by this package.
+const (
+	WarningEventsDropped      = "events_dropped"      // an event was dropped for a slow subscriber
+	WarningPersistenceSkipped = "persistence_skipped" // an event was not written to the store
+	WarningMemoryShed         = "memory_shed"         // old data was dropped to stay within a memory cap
+)
+
+// WarningCount aggregates one type of warning.
+type WarningCount struct {
+	Type        string    `json:"type"`
+	Count       uint64    `json:"count"`
+	LastAt      time.Time `json:"last_at"`
+	LastMessage string    `json:"last_message,omitempty"`
+}
+
+var warnings = struct {
+	mu     sync.Mutex
+	counts map[string]*WarningCount
+}{counts: make(map[string]*WarningCount)}
+
+// Warn emits system.warning with the given type. fields may be nil.
+func Warn(warningType, msg string, fields map[string]interface{}) {
+	f := map[string]interface{}{"type": warningType}
+	for k, v := range fields {
+		f[k] = v
+	}
+	Emit("warning", "system.warning", msg, f)
+}
+
+// countWarning counts a warning-level event by its type: the "type" field of
+// system.warning, otherwise the event name. The counts back /warnings, /ready
+// and /metrics; conditions that would flood the log or recurse into Emit are
+// counted through noteWarning instead.
+func countWarning(e Event) {
+	warningType := e.Name
+	if t, ok := e.Fields["type"].(string); ok && e.Name == "system.warning" {
+		warningType = t
+	}
+	noteWarning(warningType, e.Message)
+}
+
+// noteWarning counts a warning without emitting an event.
+func noteWarning(warningType, msg string) {
+	warnings.mu.Lock()
+	defer warnings.mu.Unlock()
+
+	w, ok := warnings.counts[warningType]
+	if !ok {
+		w = &WarningCount{Type: warningType}
+		warnings.counts[warningType] = w
+	}
+	w.Count++
+	w.LastAt = time.Now().UTC()
+	if msg != "" {
+		w.LastMessage = msg
+	}
+}
+
+// WarningSummary returns the warnings counted since startup, by type.
+func WarningSummary() []WarningCount {
+	warnings.mu.Lock()
+	defer warnings.mu.Unlock()
+
+	summary := make([]WarningCount, 0, len(warnings.counts))
+	for _, w := range warnings.counts {
+		summary = append(summary, *w)
+	}
+	sort.Slice(summary, func(i, j int) bool { return summary[i].Type < summary[j].Type })
+	return summary
+}
+
+// ClearWarnings resets the warning counts. Used for testing.
+func ClearWarnings() {
+	warnings.mu.Lock()
+	defer warnings.mu.Unlock()
+	warnings.counts = make(map[string]*WarningCount)
+}
@@ -0,0 +1,29 @@
+package events
+
+import "testing"
+
+func TestWarningSummary(t *testing.T) {
+	ClearWarnings()
+	defer ClearWarnings()
+
+	Warn("unrecognized_device", "device not in devices.yaml", map[string]interface{}{"logical_id": "fog"})
+	Warn("unrecognized_device", "device not in devices.yaml", map[string]interface{}{"logical_id": "strobe"})
+	Emit("warning", "node.failed", "", map[string]interface{}{"node_id": "vault"})
+	Emit("error", "system.error", "boom", nil)
+	noteWarning(WarningEventsDropped, "")
+
+	summary := WarningSummary()
+	if len(summary) != 3 {
+		t.Fatalf("expected 3 warning types, got %+v", summary)
+	}
+	counts := make(map[string]uint64)
+	for _, w := range summary {
+		counts[w.Type] = w.Count
+	}
+	if counts["unrecognized_device"] != 2 || counts["node.failed"] != 1 || counts[WarningEventsDropped] != 1 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+	if summary[2].Type != "unrecognized_device" || summary[2].LastMessage != "device not in devices.yaml" {
+		t.Errorf("unexpected summary entry: %+v", summary[2])
+	}
+}
@@ -0,0 +1,324 @@
+// Package export writes periodic session summary reports for room owners.
+package export
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// Report schedules.
+const (
+	ScheduleDaily  = "daily"
+	ScheduleWeekly = "weekly"
+)
+
+// DefaultQueryLimit is the number of persisted events scanned per report.
+const DefaultQueryLimit = 100000
+
+// EventSource reads persisted events; implemented by storage.Store.
+type EventSource interface {
+	QueryByNames(names []string, limit int) ([]storage.EventRow, error)
+}
+
+// SMTPConfig holds outgoing mail settings.
+type SMTPConfig struct {
+	Host string
+	Port int
+	User string
+	Pass string
+	From string
+	To   []string
+}
+
+// Config controls when and where reports are delivered.
+type Config struct {
+	Schedule string
+	Hour     int
+	Dir      string
+	Location *time.Location
+	SMTP     SMTPConfig
+}
+
+// Enabled reports whether a schedule and at least one destination are configured.
+func (c *Config) Enabled() bool {
+	if c.Schedule == "" {
+		return false
+	}
+	return c.Dir != "" || c.emailEnabled()
+}
+
+func (c *Config) emailEnabled() bool {
+	return c.SMTP.Host != "" && c.SMTP.From != "" && len(c.SMTP.To) > 0
+}
+
+// LoadConfig reads export settings from the environment.
+// SMTP credentials support the *_FILE convention.
+//
+//	SENTIENT_EXPORT_SCHEDULE     daily | weekly (unset disables exports)
+//	SENTIENT_EXPORT_HOUR         local hour reports run at (default 6)
+//	SENTIENT_EXPORT_DIR          directory CSV files are written to
+//	SENTIENT_SMTP_HOST           mail server host
+//	SENTIENT_SMTP_PORT           mail server port (default 587)
+//	SENTIENT_SMTP_USER[_FILE]    mail server username
+//	SENTIENT_SMTP_PASS[_FILE]    mail server password
+//	SENTIENT_EXPORT_EMAIL_FROM   sender address
+//	SENTIENT_EXPORT_EMAIL_TO     comma-separated recipient addresses
+func LoadConfig(timezone string) (*Config, error) {
+	cfg := &Config{
+		Schedule: strings.ToLower(strings.TrimSpace(os.Getenv("SENTIENT_EXPORT_SCHEDULE"))),
+		Hour:     6,
+		Dir:      os.Getenv("SENTIENT_EXPORT_DIR"),
+		Location: time.Local,
+		SMTP: SMTPConfig{
+			Host: os.Getenv("SENTIENT_SMTP_HOST"),
+			Port: 587,
+			From: os.Getenv("SENTIENT_EXPORT_EMAIL_FROM"),
+		},
+	}
+
+	switch cfg.Schedule {
+	case "", ScheduleDaily, ScheduleWeekly:
+	default:
+		return nil, fmt.Errorf("invalid SENTIENT_EXPORT_SCHEDULE %q (want daily or weekly)", cfg.Schedule)
+	}
+
+	if v := os.Getenv("SENTIENT_EXPORT_HOUR"); v != "" {
+		hour, err := strconv.Atoi(v)
+		if err != nil || hour < 0 || hour > 23 {
+			return nil, fmt.Errorf("invalid SENTIENT_EXPORT_HOUR %q (want 0-23)", v)
+		}
+		cfg.Hour = hour
+	}
+
+	if v := os.Getenv("SENTIENT_SMTP_PORT"); v != "" {
+		port, err := strconv.Atoi(v)
+		if err != nil || port <= 0 {
+			return nil, fmt.Errorf("invalid SENTIENT_SMTP_PORT %q", v)
+		}
+		cfg.SMTP.Port = port
+	}
+
+	for _, addr := range strings.Split(os.Getenv("SENTIENT_EXPORT_EMAIL_TO"), ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			cfg.SMTP.To = append(cfg.SMTP.To, addr)
+		}
+	}
+
+	var err error
+	if cfg.SMTP.User, err = config.ResolveSecret("SENTIENT_SMTP_USER"); err != nil {
+		return nil, err
+	}
+	if cfg.SMTP.Pass, err = config.ResolveSecret("SENTIENT_SMTP_PASS"); err != nil {
+		return nil, err
+	}
+
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", timezone, err)
+		}
+		cfg.Location = loc
+	}
+
+	return cfg, nil
+}
+
+// Exporter delivers session summaries on a daily or weekly schedule.
+type Exporter struct {
+	cfg    *Config
+	source EventSource
+	roomID string
+	send   func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+	now    func() time.Time
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// New creates an exporter reading events from source.
+func New(cfg *Config, source EventSource, roomID string) *Exporter {
+	return &Exporter{
+		cfg:    cfg,
+		source: source,
+		roomID: roomID,
+		send:   smtp.SendMail,
+		now:    time.Now,
+		stopCh: make(chan struct{}),
+	}
+}
+
+// Start runs the schedule loop in the background.
+func (e *Exporter) Start() {
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		for {
+			now := e.now()
+			at := nextRun(now, e.cfg.Schedule, e.cfg.Hour, e.cfg.Location)
+			timer := time.NewTimer(at.Sub(now))
+			select {
+			case <-e.stopCh:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			start, end := reportPeriod(at, e.cfg.Schedule)
+			if err := e.Run(start, end); err != nil {
+				events.Emit("error", "system.error", "session export failed", map[string]interface{}{
+					"error": err.Error(),
+				})
+			}
+		}
+	}()
+}
+
+// Stop stops the schedule loop.
+func (e *Exporter) Stop() {
+	close(e.stopCh)
+	e.wg.Wait()
+}
+
+// Run exports sessions that started in [start, end) to the configured destinations.
+func (e *Exporter) Run(start, end time.Time) error {
+	rows, err := e.source.QueryByNames(orchestrator.SessionEventNames, DefaultQueryLimit)
+	if err != nil {
+		return fmt.Errorf("failed to query events: %w", err)
+	}
+
+	var sessions []orchestrator.SessionSummary
+	for _, s := range orchestrator.BuildSessionSummaries(rows) {
+		if s.StartedAt.Before(start) || !s.StartedAt.Before(end) {
+			continue
+		}
+		sessions = append(sessions, s)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, sessions); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("sessions-%s-%s.csv", e.roomID, start.In(e.cfg.Location).Format("2006-01-02"))
+
+	if e.cfg.Dir != "" {
+		if err := os.MkdirAll(e.cfg.Dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create export dir: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(e.cfg.Dir, name), buf.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+	}
+
+	if e.cfg.emailEnabled() {
+		subject := fmt.Sprintf("%s session report %s (%d sessions)",
+			e.roomID, start.In(e.cfg.Location).Format("2006-01-02"), len(sessions))
+		msg := buildMessage(e.cfg.SMTP.From, e.cfg.SMTP.To, subject, name, buf.Bytes())
+
+		var auth smtp.Auth
+		if e.cfg.SMTP.User != "" {
+			auth = smtp.PlainAuth("", e.cfg.SMTP.User, e.cfg.SMTP.Pass, e.cfg.SMTP.Host)
+		}
+		addr := fmt.Sprintf("%s:%d", e.cfg.SMTP.Host, e.cfg.SMTP.Port)
+		if err := e.send(addr, auth, e.cfg.SMTP.From, e.cfg.SMTP.To, msg); err != nil {
+			return fmt.Errorf("failed to send export email: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// WriteCSV writes one row per session with a header row.
+func WriteCSV(w io.Writer, sessions []orchestrator.SessionSummary) error {
+	cw := csv.NewWriter(w)
+	header := []string{
+		"scene_id", "started_at", "ended_at", "duration_sec", "completed",
+		"puzzles_solved", "puzzles_overridden", "overrides", "hints",
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, s := range sessions {
+		ended := ""
+		if s.EndedAt != nil {
+			ended = s.EndedAt.UTC().Format(time.RFC3339)
+		}
+		record := []string{
+			s.SceneID,
+			s.StartedAt.UTC().Format(time.RFC3339),
+			ended,
+			strconv.FormatFloat(s.DurationSec, 'f', 0, 64),
+			strconv.FormatBool(s.Completed),
+			strconv.Itoa(s.PuzzlesSolved),
+			strconv.Itoa(s.PuzzlesOverridden),
+			strconv.Itoa(s.Overrides),
+			strconv.Itoa(s.Hints),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// nextRun returns the first scheduled run strictly after now.
+// Daily reports run at hour each day; weekly reports run at hour on Mondays.
+func nextRun(now time.Time, schedule string, hour int, loc *time.Location) time.Time {
+	local := now.In(loc)
+	at := time.Date(local.Year(), local.Month(), local.Day(), hour, 0, 0, 0, loc)
+	for !at.After(now) || (schedule == ScheduleWeekly && at.Weekday() != time.Monday) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at
+}
+
+// reportPeriod returns the local day or week that ended at the start of runAt's day.
+func reportPeriod(runAt time.Time, schedule string) (time.Time, time.Time) {
+	end := time.Date(runAt.Year(), runAt.Month(), runAt.Day(), 0, 0, 0, 0, runAt.Location())
+	if schedule == ScheduleWeekly {
+		return end.AddDate(0, 0, -7), end
+	}
+	return end.AddDate(0, 0, -1), end
+}
+
+// buildMessage builds a MIME email with the CSV report attached.
+func buildMessage(from string, to []string, subject, filename string, body []byte) []byte {
+	const boundary = "sentient-session-report"
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", boundary)
+
+	fmt.Fprintf(&b, "--%s\r\n", boundary)
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&b, "Session summaries attached (%s).\r\n\r\n", filename)
+
+	fmt.Fprintf(&b, "--%s\r\n", boundary)
+	b.WriteString("Content-Type: text/csv; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: base64\r\n")
+	fmt.Fprintf(&b, "Content-Disposition: attachment; filename=%q\r\n\r\n", filename)
+	encoded := base64.StdEncoding.EncodeToString(body)
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded + "\r\n")
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return b.Bytes()
+}
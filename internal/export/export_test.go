@@ -0,0 +1,155 @@
+package export
+
+import (
+	"bytes"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// fakeSource returns fixed rows.
+type fakeSource struct {
+	rows []storage.EventRow
+}
+
+func (f *fakeSource) QueryByNames(names []string, limit int) ([]storage.EventRow, error) {
+	return f.rows, nil
+}
+
+func sessionRows(day time.Time) []storage.EventRow {
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+	return []storage.EventRow{
+		// Previous day, excluded from the report
+		{EventID: 1, Timestamp: at(-5, 0), Event: "scene.started", Fields: map[string]interface{}{"scene_id": "scene_intro"}},
+		{EventID: 2, Timestamp: at(-4, 0), Event: "scene.reset"},
+		// Completed session with a hint and an override
+		{EventID: 3, Timestamp: at(10, 0), Event: "scene.started", Fields: map[string]interface{}{"scene_id": "scene_intro"}},
+		{EventID: 4, Timestamp: at(10, 10), Event: "puzzle.hint"},
+		{EventID: 5, Timestamp: at(10, 20), Event: "puzzle.solved"},
+		{EventID: 6, Timestamp: at(10, 30), Event: "operator.override"},
+		{EventID: 7, Timestamp: at(10, 30), Event: "puzzle.overridden"},
+		{EventID: 8, Timestamp: at(10, 55), Event: "scene.completed"},
+		{EventID: 9, Timestamp: at(11, 0), Event: "scene.reset"},
+		// Abandoned session, closed by the next start
+		{EventID: 10, Timestamp: at(14, 0), Event: "scene.started", Fields: map[string]interface{}{"scene_id": "scene_intro"}},
+		{EventID: 11, Timestamp: at(15, 0), Event: "scene.started", Fields: map[string]interface{}{"scene_id": "scene_intro"}},
+	}
+}
+
+func TestRunWritesCSV(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	dir := t.TempDir()
+	cfg := &Config{Schedule: ScheduleDaily, Dir: dir, Location: time.UTC}
+	exp := New(cfg, &fakeSource{rows: sessionRows(day)}, "pharaohs")
+
+	if err := exp.Run(day, day.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "sessions-pharaohs-2024-03-04.csv"))
+	if err != nil {
+		t.Fatalf("expected export file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected header and 3 sessions, got %d lines:\n%s", len(lines), data)
+	}
+	want := "scene_intro,2024-03-04T10:00:00Z,2024-03-04T11:00:00Z,3600,true,1,1,1,1"
+	if lines[1] != want {
+		t.Errorf("unexpected first row:\n got %s\nwant %s", lines[1], want)
+	}
+	if !strings.HasSuffix(lines[3], ",,0,false,0,0,0,0") {
+		t.Errorf("expected running session without end, got %s", lines[3])
+	}
+}
+
+func TestRunSendsEmail(t *testing.T) {
+	day := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	cfg := &Config{
+		Schedule: ScheduleDaily,
+		Location: time.UTC,
+		SMTP: SMTPConfig{
+			Host: "mail.example.com", Port: 587, User: "reports", Pass: "secret",
+			From: "room@example.com", To: []string{"owner@example.com"},
+		},
+	}
+	exp := New(cfg, &fakeSource{rows: sessionRows(day)}, "pharaohs")
+
+	var gotAddr string
+	var gotMsg []byte
+	exp.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotMsg = addr, msg
+		return nil
+	}
+
+	if err := exp.Run(day, day.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if gotAddr != "mail.example.com:587" {
+		t.Errorf("expected mail sent to configured server, got %q", gotAddr)
+	}
+	if !bytes.Contains(gotMsg, []byte("Subject: pharaohs session report 2024-03-04 (3 sessions)")) {
+		t.Errorf("unexpected message:\n%s", gotMsg)
+	}
+	if !bytes.Contains(gotMsg, []byte(`filename="sessions-pharaohs-2024-03-04.csv"`)) {
+		t.Errorf("expected CSV attachment, got:\n%s", gotMsg)
+	}
+}
+
+func TestNextRun(t *testing.T) {
+	loc := time.UTC
+	// Wednesday 2024-03-06 08:00
+	now := time.Date(2024, 3, 6, 8, 0, 0, 0, loc)
+
+	if got := nextRun(now, ScheduleDaily, 6, loc); !got.Equal(time.Date(2024, 3, 7, 6, 0, 0, 0, loc)) {
+		t.Errorf("daily after hour: got %v", got)
+	}
+	if got := nextRun(now, ScheduleDaily, 9, loc); !got.Equal(time.Date(2024, 3, 6, 9, 0, 0, 0, loc)) {
+		t.Errorf("daily before hour: got %v", got)
+	}
+	if got := nextRun(now, ScheduleWeekly, 6, loc); !got.Equal(time.Date(2024, 3, 11, 6, 0, 0, 0, loc)) {
+		t.Errorf("weekly: got %v", got)
+	}
+
+	start, end := reportPeriod(time.Date(2024, 3, 11, 6, 0, 0, 0, loc), ScheduleWeekly)
+	if !start.Equal(time.Date(2024, 3, 4, 0, 0, 0, 0, loc)) || !end.Equal(time.Date(2024, 3, 11, 0, 0, 0, 0, loc)) {
+		t.Errorf("weekly period: got %v - %v", start, end)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	passFile := filepath.Join(t.TempDir(), "smtp_pass")
+	if err := os.WriteFile(passFile, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SENTIENT_EXPORT_SCHEDULE", "Weekly")
+	t.Setenv("SENTIENT_EXPORT_HOUR", "7")
+	t.Setenv("SENTIENT_SMTP_HOST", "mail.example.com")
+	t.Setenv("SENTIENT_SMTP_PASS_FILE", passFile)
+	t.Setenv("SENTIENT_EXPORT_EMAIL_FROM", "room@example.com")
+	t.Setenv("SENTIENT_EXPORT_EMAIL_TO", "owner@example.com, manager@example.com")
+
+	cfg, err := LoadConfig("America/Denver")
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if cfg.Schedule != ScheduleWeekly || cfg.Hour != 7 || cfg.SMTP.Port != 587 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if cfg.SMTP.Pass != "from-file" {
+		t.Errorf("expected password from file, got %q", cfg.SMTP.Pass)
+	}
+	if len(cfg.SMTP.To) != 2 || !cfg.Enabled() {
+		t.Errorf("expected two recipients and export enabled, got %+v", cfg.SMTP.To)
+	}
+
+	t.Setenv("SENTIENT_EXPORT_SCHEDULE", "hourly")
+	if _, err := LoadConfig(""); err == nil {
+		t.Error("expected error for invalid schedule")
+	}
+}
@@ -0,0 +1,374 @@
+package mqtt
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// Anomaly kinds reported in device.error warning events.
+const (
+	AnomalySilent     = "silent"
+	AnomalyChatter    = "chatter"
+	AnomalyOutOfRange = "out_of_range"
+)
+
+// AnomalyEventNames lists the persisted events used to seed device baselines.
+var AnomalyEventNames = []string{"scene.started", "scene.advanced", "scene.reset", "scene.completed", "device.input"}
+
+// DefaultAnomalySeedLimit is the number of persisted events scanned when seeding baselines.
+const DefaultAnomalySeedLimit = 50000
+
+// Anomaly detection thresholds.
+const (
+	// Length of the rate measurement window.
+	anomalyWindow = time.Minute
+	// Weight of the newest window in the message rate baseline.
+	rateSmoothing = 0.1
+	// Windows of history required before rate anomalies are reported.
+	minRateWindows = 5
+	// A device is silent after this many expected message intervals without traffic.
+	silenceFactor = 5.0
+	// Never report silence sooner than this.
+	minSilence = 30 * time.Second
+	// A device chatters when a window exceeds this multiple of its baseline rate.
+	chatterFactor = 5.0
+	// Never report chatter below this many messages per window.
+	minChatter = 20
+	// Values this many standard deviations from the mean are out of range.
+	outOfRangeSigma = 4.0
+	// Samples required per field before values are range checked.
+	minValueSamples = 30
+)
+
+// runningStats accumulates mean and variance using Welford's algorithm.
+type runningStats struct {
+	N    int     `json:"n"`
+	Mean float64 `json:"mean"`
+	M2   float64 `json:"m2"`
+}
+
+func (s *runningStats) add(x float64) {
+	s.N++
+	delta := x - s.Mean
+	s.Mean += delta / float64(s.N)
+	s.M2 += delta * (x - s.Mean)
+}
+
+func (s *runningStats) stddev() float64 {
+	if s.N < 2 {
+		return 0
+	}
+	return math.Sqrt(s.M2 / float64(s.N-1))
+}
+
+// DeviceBaseline is the learned normal behavior of a device across sessions.
+type DeviceBaseline struct {
+	RatePerMin  float64                  `json:"rate_per_min"`
+	RateWindows int                      `json:"rate_windows"`
+	Values      map[string]*runningStats `json:"values"`
+}
+
+// deviceActivity tracks a device within the current session.
+type deviceActivity struct {
+	lastSeen    time.Time
+	windowCount int
+	silent      bool
+}
+
+// AnomalyDetector learns per-device message rates and value distributions
+// during games and emits warnings when a device deviates from its baseline.
+type AnomalyDetector struct {
+	mu           sync.Mutex
+	baselines    map[string]*DeviceBaseline
+	activity     map[string]*deviceActivity
+	gameActive   func() bool
+	sessionStart time.Time
+	wasActive    bool
+	stopCh       chan struct{}
+	wg           sync.WaitGroup
+}
+
+// NewAnomalyDetector creates a detector. gameActive reports whether a game is
+// running; baselines are only learned and checked mid-game.
+func NewAnomalyDetector(gameActive func() bool) *AnomalyDetector {
+	return &AnomalyDetector{
+		baselines:  make(map[string]*DeviceBaseline),
+		activity:   make(map[string]*deviceActivity),
+		gameActive: gameActive,
+		stopCh:     make(chan struct{}),
+	}
+}
+
+// Observe records a device message and checks its values against the baseline.
+func (d *AnomalyDetector) Observe(logicalID string, payload interface{}, at time.Time) {
+	if logicalID == "" {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	act := d.activityFor(logicalID)
+	act.lastSeen = at
+	act.windowCount++
+	act.silent = false
+
+	if !d.gameActive() {
+		return
+	}
+
+	base := d.baselineFor(logicalID)
+	for field, value := range numericFields(payload) {
+		stats, ok := base.Values[field]
+		if !ok {
+			stats = &runningStats{}
+			base.Values[field] = stats
+		}
+		if stats.N >= minValueSamples {
+			if sd := stats.stddev(); sd > 0 && math.Abs(value-stats.Mean)/sd > outOfRangeSigma {
+				events.Emit("warning", "device.error", "device anomaly: value out of range", map[string]interface{}{
+					"logical_id": logicalID,
+					"anomaly":    AnomalyOutOfRange,
+					"field":      field,
+					"value":      value,
+					"mean":       stats.Mean,
+					"stddev":     sd,
+				})
+				// Outliers do not shift the baseline
+				continue
+			}
+		}
+		stats.add(value)
+	}
+}
+
+// Check closes the current rate window, emitting silence and chatter warnings
+// and folding the window into each device's rate baseline.
+func (d *AnomalyDetector) Check(now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	active := d.gameActive()
+	if active && !d.wasActive {
+		d.sessionStart = now
+	}
+	d.wasActive = active
+
+	if !active {
+		for _, act := range d.activity {
+			act.windowCount = 0
+		}
+		return
+	}
+
+	for logicalID, base := range d.baselines {
+		act := d.activityFor(logicalID)
+		count := act.windowCount
+		act.windowCount = 0
+
+		chatter := false
+		if base.RateWindows >= minRateWindows {
+			threshold := math.Max(float64(minChatter), chatterFactor*base.RatePerMin)
+			if float64(count) > threshold {
+				chatter = true
+				events.Emit("warning", "device.error", "device anomaly: abnormal message rate", map[string]interface{}{
+					"logical_id":    logicalID,
+					"anomaly":       AnomalyChatter,
+					"count":         count,
+					"window_sec":    anomalyWindow.Seconds(),
+					"baseline_rate": base.RatePerMin,
+				})
+			}
+
+			if base.RatePerMin >= 1 && !act.silent {
+				expected := time.Duration(float64(time.Minute) / base.RatePerMin * silenceFactor)
+				if expected < minSilence {
+					expected = minSilence
+				}
+				since := act.lastSeen
+				if since.Before(d.sessionStart) {
+					since = d.sessionStart
+				}
+				if now.Sub(since) > expected {
+					act.silent = true
+					events.Emit("warning", "device.error", "device anomaly: silent mid-game", map[string]interface{}{
+						"logical_id":    logicalID,
+						"anomaly":       AnomalySilent,
+						"silent_sec":    now.Sub(since).Seconds(),
+						"baseline_rate": base.RatePerMin,
+					})
+				}
+			}
+		}
+
+		// Chattering windows do not shift the baseline
+		if !chatter {
+			d.foldRate(base, float64(count))
+		}
+	}
+
+	// Devices seen for the first time this window start a baseline
+	for logicalID, act := range d.activity {
+		if _, ok := d.baselines[logicalID]; !ok && act.windowCount > 0 {
+			d.foldRate(d.baselineFor(logicalID), float64(act.windowCount))
+			act.windowCount = 0
+		}
+	}
+}
+
+// SeedFromEvents builds baselines from persisted device.input history.
+// Only messages inside sessions (scene.started or scene.advanced until
+// scene.reset/scene.completed) count.
+func (d *AnomalyDetector) SeedFromEvents(rows []storage.EventRow) {
+	sorted := append([]storage.EventRow{}, rows...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].Timestamp.Equal(sorted[j].Timestamp) {
+			return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+		}
+		return sorted[i].EventID < sorted[j].EventID
+	})
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var sessionStart time.Time
+	var sessionTime time.Duration
+	counts := make(map[string]int)
+	for _, row := range sorted {
+		switch row.Event {
+		case "scene.started", "scene.advanced":
+			if sessionStart.IsZero() {
+				sessionStart = row.Timestamp
+			}
+		case "scene.reset", "scene.completed":
+			if !sessionStart.IsZero() {
+				sessionTime += row.Timestamp.Sub(sessionStart)
+				sessionStart = time.Time{}
+			}
+		case "device.input":
+			if sessionStart.IsZero() {
+				continue
+			}
+			logicalID, _ := row.Fields["logical_id"].(string)
+			if logicalID == "" {
+				continue
+			}
+			counts[logicalID]++
+			base := d.baselineFor(logicalID)
+			for field, value := range numericFields(row.Fields["payload"]) {
+				stats, ok := base.Values[field]
+				if !ok {
+					stats = &runningStats{}
+					base.Values[field] = stats
+				}
+				stats.add(value)
+			}
+		}
+	}
+
+	windows := int(sessionTime / anomalyWindow)
+	if windows == 0 {
+		return
+	}
+	for logicalID, count := range counts {
+		base := d.baselineFor(logicalID)
+		base.RatePerMin = float64(count) / sessionTime.Minutes()
+		base.RateWindows = windows
+	}
+}
+
+// Baselines returns a copy of the learned baselines.
+func (d *AnomalyDetector) Baselines() map[string]DeviceBaseline {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	out := make(map[string]DeviceBaseline, len(d.baselines))
+	for id, base := range d.baselines {
+		cpy := DeviceBaseline{
+			RatePerMin:  base.RatePerMin,
+			RateWindows: base.RateWindows,
+			Values:      make(map[string]*runningStats, len(base.Values)),
+		}
+		for field, stats := range base.Values {
+			s := *stats
+			cpy.Values[field] = &s
+		}
+		out[id] = cpy
+	}
+	return out
+}
+
+// Start begins closing rate windows in the background.
+func (d *AnomalyDetector) Start() {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(anomalyWindow)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.stopCh:
+				return
+			case now := <-ticker.C:
+				d.Check(now)
+			}
+		}
+	}()
+}
+
+// Stop stops the background window loop.
+func (d *AnomalyDetector) Stop() {
+	close(d.stopCh)
+	d.wg.Wait()
+}
+
+// foldRate blends a window's message count into the rate baseline.
+func (d *AnomalyDetector) foldRate(base *DeviceBaseline, count float64) {
+	perMin := count / anomalyWindow.Minutes()
+	if base.RateWindows == 0 {
+		base.RatePerMin = perMin
+	} else {
+		base.RatePerMin += rateSmoothing * (perMin - base.RatePerMin)
+	}
+	base.RateWindows++
+}
+
+func (d *AnomalyDetector) baselineFor(logicalID string) *DeviceBaseline {
+	base, ok := d.baselines[logicalID]
+	if !ok {
+		base = &DeviceBaseline{Values: make(map[string]*runningStats)}
+		d.baselines[logicalID] = base
+	}
+	return base
+}
+
+func (d *AnomalyDetector) activityFor(logicalID string) *deviceActivity {
+	act, ok := d.activity[logicalID]
+	if !ok {
+		act = &deviceActivity{}
+		d.activity[logicalID] = act
+	}
+	return act
+}
+
+// numericFields extracts numeric values from a device payload.
+// A bare number is reported under "value"; objects contribute their top-level numeric fields.
+func numericFields(payload interface{}) map[string]float64 {
+	out := make(map[string]float64)
+	switch p := payload.(type) {
+	case float64:
+		out["value"] = p
+	case map[string]interface{}:
+		for k, v := range p {
+			if f, ok := v.(float64); ok {
+				out[k] = f
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,140 @@
+package mqtt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// anomalyWarnings returns device.error events reporting the given anomaly for a device.
+func anomalyWarnings(logicalID, anomaly string) int {
+	n := 0
+	for _, e := range events.Snapshot() {
+		if e.Name == "device.error" && e.Fields["logical_id"] == logicalID && e.Fields["anomaly"] == anomaly {
+			n++
+		}
+	}
+	return n
+}
+
+func TestAnomalyOutOfRange(t *testing.T) {
+	events.Clear()
+	d := NewAnomalyDetector(func() bool { return true })
+	now := time.Now()
+
+	for i := 0; i < 40; i++ {
+		temp := 19.0
+		if i%2 == 0 {
+			temp = 21.0
+		}
+		d.Observe("thermo", map[string]interface{}{"temp": temp}, now)
+	}
+	if anomalyWarnings("thermo", AnomalyOutOfRange) != 0 {
+		t.Fatal("expected no warnings for normal values")
+	}
+
+	d.Observe("thermo", map[string]interface{}{"temp": 100.0}, now)
+	if anomalyWarnings("thermo", AnomalyOutOfRange) != 1 {
+		t.Error("expected out_of_range warning for extreme value")
+	}
+
+	// Outlier should not have shifted the baseline
+	if mean := d.Baselines()["thermo"].Values["temp"].Mean; mean != 20.0 {
+		t.Errorf("expected baseline mean 20, got %v", mean)
+	}
+}
+
+func TestAnomalyChatterAndSilence(t *testing.T) {
+	events.Clear()
+	d := NewAnomalyDetector(func() bool { return true })
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	d.Check(now) // session starts
+
+	// Learn a baseline of 6 messages per minute
+	for w := 0; w < 6; w++ {
+		for i := 0; i < 6; i++ {
+			d.Observe("door", 1.0, now.Add(time.Duration(i)*10*time.Second))
+		}
+		now = now.Add(time.Minute)
+		d.Check(now)
+	}
+	if anomalyWarnings("door", AnomalyChatter) != 0 || anomalyWarnings("door", AnomalySilent) != 0 {
+		t.Fatal("expected no warnings while learning baseline")
+	}
+
+	// A burst far above baseline
+	for i := 0; i < 60; i++ {
+		d.Observe("door", 1.0, now)
+	}
+	now = now.Add(time.Minute)
+	d.Check(now)
+	if anomalyWarnings("door", AnomalyChatter) != 1 {
+		t.Error("expected chatter warning for burst")
+	}
+
+	// Then nothing for two minutes
+	now = now.Add(2 * time.Minute)
+	d.Check(now)
+	d.Check(now.Add(time.Minute))
+	if anomalyWarnings("door", AnomalySilent) != 1 {
+		t.Errorf("expected exactly one silent warning, got %d", anomalyWarnings("door", AnomalySilent))
+	}
+
+	// Traffic clears the silent flag
+	d.Observe("door", 1.0, now.Add(time.Minute))
+	d.mu.Lock()
+	silent := d.activity["door"].silent
+	d.mu.Unlock()
+	if silent {
+		t.Error("expected silent flag to clear on new traffic")
+	}
+}
+
+func TestAnomalyIdleNotLearned(t *testing.T) {
+	d := NewAnomalyDetector(func() bool { return false })
+	d.Observe("door", 1.0, time.Now())
+	d.Check(time.Now())
+
+	if len(d.Baselines()) != 0 {
+		t.Error("expected no baselines learned outside a game")
+	}
+}
+
+func TestAnomalySeedFromEvents(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	rows := []storage.EventRow{
+		{EventID: 1, Timestamp: start, Event: "scene.started"},
+		{EventID: 100, Timestamp: start.Add(10 * time.Minute), Event: "scene.reset"},
+		// Outside any session: ignored
+		{EventID: 101, Timestamp: start.Add(11 * time.Minute), Event: "device.input", Fields: map[string]interface{}{
+			"logical_id": "door", "payload": 999.0,
+		}},
+	}
+	for i := 0; i < 20; i++ {
+		rows = append(rows, storage.EventRow{
+			EventID:   int64(2 + i),
+			Timestamp: start.Add(time.Duration(i) * 30 * time.Second),
+			Event:     "device.input",
+			Fields:    map[string]interface{}{"logical_id": "door", "payload": 1.0},
+		})
+	}
+
+	d := NewAnomalyDetector(func() bool { return true })
+	d.SeedFromEvents(rows)
+
+	base, ok := d.Baselines()["door"]
+	if !ok {
+		t.Fatal("expected baseline for door")
+	}
+	if base.RatePerMin != 2.0 {
+		t.Errorf("expected 2 messages/min, got %v", base.RatePerMin)
+	}
+	if base.RateWindows != 10 {
+		t.Errorf("expected 10 windows, got %d", base.RateWindows)
+	}
+	if stats := base.Values["value"]; stats == nil || stats.N != 20 || stats.Mean != 1.0 {
+		t.Errorf("unexpected value stats: %+v", stats)
+	}
+}
@@ -0,0 +1,95 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AssetDateLayout is the expected format for asset dates.
+const AssetDateLayout = "2006-01-02"
+
+// DeviceAsset holds inventory metadata for a logical device.
+// Assets survive re-registration and are kept for devices that are offline.
+type DeviceAsset struct {
+	InstallDate     string    `json:"install_date,omitempty"`
+	Firmware        string    `json:"firmware,omitempty"`
+	LastMaintenance string    `json:"last_maintenance,omitempty"`
+	Notes           string    `json:"notes,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Validate checks that asset dates use AssetDateLayout.
+func (a DeviceAsset) Validate() error {
+	if a.InstallDate != "" {
+		if _, err := time.Parse(AssetDateLayout, a.InstallDate); err != nil {
+			return fmt.Errorf("install_date must be YYYY-MM-DD")
+		}
+	}
+	if a.LastMaintenance != "" {
+		if _, err := time.Parse(AssetDateLayout, a.LastMaintenance); err != nil {
+			return fmt.Errorf("last_maintenance must be YYYY-MM-DD")
+		}
+	}
+	return nil
+}
+
+// AssetStore persists device asset metadata (implemented by storage.Store).
+type AssetStore interface {
+	SaveDeviceAsset(logicalID string, asset []byte, ts time.Time) error
+	LoadDeviceAssets() (map[string][]byte, error)
+}
+
+// SetAssetStore sets where asset metadata is persisted and loads stored assets.
+func (r *DeviceRegistry) SetAssetStore(store AssetStore) error {
+	stored, err := store.LoadDeviceAssets()
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.store = store
+	for logicalID, data := range stored {
+		var asset DeviceAsset
+		if err := json.Unmarshal(data, &asset); err != nil {
+			return fmt.Errorf("invalid asset for %s: %w", logicalID, err)
+		}
+		r.assets[logicalID] = asset
+	}
+	return nil
+}
+
+// Asset returns asset metadata for a device, or nil if none is recorded.
+func (r *DeviceRegistry) Asset(logicalID string) *DeviceAsset {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if asset, ok := r.assets[logicalID]; ok {
+		return &asset
+	}
+	return nil
+}
+
+// UpdateAsset validates, persists and records asset metadata for a device.
+func (r *DeviceRegistry) UpdateAsset(logicalID string, asset DeviceAsset) error {
+	if err := asset.Validate(); err != nil {
+		return err
+	}
+	asset.UpdatedAt = time.Now().UTC()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.store != nil {
+		data, err := json.Marshal(asset)
+		if err != nil {
+			return fmt.Errorf("failed to marshal asset: %w", err)
+		}
+		if err := r.store.SaveDeviceAsset(logicalID, data, asset.UpdatedAt); err != nil {
+			return fmt.Errorf("failed to persist asset: %w", err)
+		}
+	}
+	r.assets[logicalID] = asset
+	return nil
+}
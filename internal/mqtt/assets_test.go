@@ -0,0 +1,106 @@
+package mqtt
+
+import (
+	"testing"
+	"time"
+)
+
+// memAssetStore keeps asset records in memory.
+type memAssetStore struct {
+	assets map[string][]byte
+}
+
+func (s *memAssetStore) SaveDeviceAsset(logicalID string, asset []byte, ts time.Time) error {
+	s.assets[logicalID] = asset
+	return nil
+}
+
+func (s *memAssetStore) LoadDeviceAssets() (map[string][]byte, error) {
+	return s.assets, nil
+}
+
+func TestDeviceRegistry_AssetPersistence(t *testing.T) {
+	store := &memAssetStore{assets: make(map[string][]byte)}
+	registry := NewDeviceRegistry()
+	if err := registry.SetAssetStore(store); err != nil {
+		t.Fatalf("failed to set asset store: %v", err)
+	}
+
+	err := registry.UpdateAsset("crypt_door", DeviceAsset{
+		InstallDate: "2024-03-01",
+		Firmware:    "1.4.2",
+		Notes:       "spare maglock in cabinet B",
+	})
+	if err != nil {
+		t.Fatalf("failed to update asset: %v", err)
+	}
+	if err := registry.UpdateAsset("crypt_door", DeviceAsset{InstallDate: "March 1st"}); err == nil {
+		t.Error("expected invalid date to be rejected")
+	}
+
+	// A fresh registry loads the stored asset
+	reloaded := NewDeviceRegistry()
+	if err := reloaded.SetAssetStore(store); err != nil {
+		t.Fatalf("failed to reload assets: %v", err)
+	}
+	asset := reloaded.Asset("crypt_door")
+	if asset == nil {
+		t.Fatal("expected asset after reload")
+	}
+	if asset.Firmware != "1.4.2" || asset.Notes != "spare maglock in cabinet B" {
+		t.Errorf("unexpected asset: %+v", asset)
+	}
+	if asset.UpdatedAt.IsZero() {
+		t.Error("expected updated_at to be set")
+	}
+}
+
+func TestMonitor_Devices(t *testing.T) {
+	specs := map[string]DeviceSpec{
+		"crypt_door":   {Type: "door", Required: true},
+		"scarab_panel": {Type: "panel"},
+	}
+	m := NewMonitor(specs, 2.0)
+
+	m.HandleRegistration(&RegistrationPayload{
+		Controller: ControllerInfo{ID: "ctrl-001", HeartbeatSec: 5},
+		Version:    1,
+		Devices: []DeviceRegistration{{
+			LogicalID: "crypt_door",
+			Type:      "door",
+			Topics:    DeviceTopics{Publish: "devices/ctrl-001/crypt_door/events", Subscribe: "devices/ctrl-001/crypt_door/commands"},
+			Signals:   DeviceSignals{Inputs: []string{"closed"}, Outputs: []string{"lock", "unlock"}},
+		}},
+	})
+
+	if err := m.UpdateAsset("scarab_panel", DeviceAsset{LastMaintenance: "2025-01-15"}); err != nil {
+		t.Fatalf("expected asset update for configured device, got %v", err)
+	}
+	if err := m.UpdateAsset("unknown", DeviceAsset{}); err == nil {
+		t.Error("expected error for unknown device")
+	}
+
+	devices := m.Devices()
+	if len(devices) != 2 {
+		t.Fatalf("expected 2 devices, got %d", len(devices))
+	}
+	door, panel := devices[0], devices[1]
+	if door.LogicalID != "crypt_door" || !door.Registered || !door.Connected || !door.Required {
+		t.Errorf("unexpected crypt_door status: %+v", door)
+	}
+	if door.ControllerID != "ctrl-001" || door.LastSeen == nil {
+		t.Errorf("expected controller and last_seen for crypt_door, got %+v", door)
+	}
+	if door.Topics == nil || door.Topics.Subscribe != "devices/ctrl-001/crypt_door/commands" {
+		t.Errorf("expected crypt_door topics, got %+v", door.Topics)
+	}
+	if door.Signals == nil || len(door.Signals.Outputs) != 2 || door.Signals.Inputs[0] != "closed" {
+		t.Errorf("expected crypt_door signals, got %+v", door.Signals)
+	}
+	if panel.Registered || panel.Connected || panel.Topics != nil {
+		t.Errorf("expected scarab_panel to be unregistered, got %+v", panel)
+	}
+	if panel.Asset == nil || panel.Asset.LastMaintenance != "2025-01-15" {
+		t.Errorf("expected scarab_panel asset, got %+v", panel.Asset)
+	}
+}
@@ -0,0 +1,108 @@
+package mqtt
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"os"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+)
+
+// tlsSchemes are the broker URL schemes Paho connects to over TLS.
+var tlsSchemes = map[string]bool{
+	"mqtts":    true,
+	"ssl":      true,
+	"tls":      true,
+	"tcps":     true,
+	"mqtt+ssl": true,
+}
+
+// applyBrokerAuth sets the credentials and TLS configuration for a broker
+// from the environment: MQTT_USERNAME, MQTT_PASSWORD and the MQTT_TLS_CA,
+// MQTT_TLS_CERT and MQTT_TLS_KEY file paths, each also read from a *_FILE
+// variable (see ops/docker/README.md). TLS settings require a TLS broker URL.
+func applyBrokerAuth(opts *paho.ClientOptions, brokerURL string) error {
+	username, err := config.ResolveSecret("MQTT_USERNAME")
+	if err != nil {
+		return err
+	}
+	password, err := config.ResolveSecret("MQTT_PASSWORD")
+	if err != nil {
+		return err
+	}
+	if password != "" && username == "" {
+		return fmt.Errorf("MQTT_PASSWORD is set without MQTT_USERNAME")
+	}
+	if username != "" {
+		opts.SetUsername(username)
+		opts.SetPassword(password)
+	}
+
+	u, err := url.Parse(brokerURL)
+	if err != nil {
+		return fmt.Errorf("invalid MQTT_URL %q: %w", brokerURL, err)
+	}
+	tlsCfg, err := brokerTLSConfig()
+	if err != nil {
+		return err
+	}
+	if !tlsSchemes[u.Scheme] {
+		if tlsCfg != nil {
+			return fmt.Errorf("MQTT_TLS_* is set but MQTT_URL %q is not a TLS URL (mqtts://)", brokerURL)
+		}
+		return nil
+	}
+	if tlsCfg == nil {
+		tlsCfg = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	opts.SetTLSConfig(tlsCfg)
+	return nil
+}
+
+// brokerTLSConfig builds the TLS configuration from MQTT_TLS_*. It returns
+// nil if none is set.
+func brokerTLSConfig() (*tls.Config, error) {
+	caFile, err := config.ResolveSecret("MQTT_TLS_CA")
+	if err != nil {
+		return nil, err
+	}
+	certFile, err := config.ResolveSecret("MQTT_TLS_CERT")
+	if err != nil {
+		return nil, err
+	}
+	keyFile, err := config.ResolveSecret("MQTT_TLS_KEY")
+	if err != nil {
+		return nil, err
+	}
+	if caFile == "" && certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read MQTT_TLS_CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("MQTT_TLS_CA %s holds no PEM certificates", caFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("MQTT_TLS_CERT and MQTT_TLS_KEY must be set together")
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load MQTT client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
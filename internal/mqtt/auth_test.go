@@ -0,0 +1,131 @@
+package mqtt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeTestCert writes a self-signed certificate and its key to dir.
+func writeTestCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "sentient-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile = filepath.Join(dir, "client.crt")
+	keyFile = filepath.Join(dir, "client.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestNewClientBrokerAuth(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCert(t, dir)
+	passFile := filepath.Join(dir, "mqtt_pass")
+	if err := os.WriteFile(passFile, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("MQTT_URL", "mqtts://broker.example:8883")
+	t.Setenv("MQTT_USERNAME", "room-crypt")
+	t.Setenv("MQTT_PASSWORD_FILE", passFile)
+	t.Setenv("MQTT_TLS_CA", certFile)
+	t.Setenv("MQTT_TLS_CERT", certFile)
+	t.Setenv("MQTT_TLS_KEY", keyFile)
+
+	c, err := NewClient("test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.opts.Username != "room-crypt" || c.opts.Password != "s3cret" {
+		t.Errorf("expected credentials from env, got %q/%q", c.opts.Username, c.opts.Password)
+	}
+	tlsCfg := c.opts.TLSConfig
+	if tlsCfg == nil || tlsCfg.RootCAs == nil || len(tlsCfg.Certificates) != 1 {
+		t.Errorf("expected CA and client certificate in TLS config, got %+v", tlsCfg)
+	}
+}
+
+func TestNewClientBrokerAuthDefaults(t *testing.T) {
+	t.Setenv("MQTT_URL", "tcp://localhost:1883")
+	c, err := NewClient("test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.opts.Username != "" || c.opts.TLSConfig != nil {
+		t.Errorf("expected an anonymous plain connection, got user %q, TLS %v", c.opts.Username, c.opts.TLSConfig)
+	}
+
+	// A TLS broker without MQTT_TLS_* is checked against the system roots
+	t.Setenv("MQTT_URL", "mqtts://broker.example:8883")
+	c, err = NewClient("test")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.opts.TLSConfig == nil || c.opts.TLSConfig.RootCAs != nil {
+		t.Errorf("expected a default TLS config, got %+v", c.opts.TLSConfig)
+	}
+}
+
+func TestNewClientBrokerAuthInvalid(t *testing.T) {
+	dir := t.TempDir()
+	certFile, _ := writeTestCert(t, dir)
+	notPEM := filepath.Join(dir, "ca.txt")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"password without username", map[string]string{"MQTT_URL": "tcp://b:1883", "MQTT_PASSWORD": "x"}, "without MQTT_USERNAME"},
+		{"tls on a plain url", map[string]string{"MQTT_URL": "tcp://b:1883", "MQTT_TLS_CA": certFile}, "not a TLS URL"},
+		{"cert without key", map[string]string{"MQTT_URL": "mqtts://b:8883", "MQTT_TLS_CERT": certFile}, "set together"},
+		{"ca not pem", map[string]string{"MQTT_URL": "mqtts://b:8883", "MQTT_TLS_CA": notPEM}, "no PEM certificates"},
+		{"missing secret file", map[string]string{"MQTT_URL": "tcp://b:1883", "MQTT_USERNAME_FILE": filepath.Join(dir, "missing")}, "MQTT_USERNAME_FILE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := NewClient("test")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
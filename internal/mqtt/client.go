@@ -13,10 +13,36 @@ import (
 type ConnectionCallback func(connected bool)
 
 // Client wraps the Paho MQTT client for Sentient Engine.
+//
+// The broker forgets a client's subscriptions when its connection drops (the
+// client connects with a clean session), and Paho does not restore them when
+// it reconnects. The client therefore remembers every topic it subscribed to
+// and subscribes to them again after each reconnect, before running the
+// hooks registered with OnReconnect.
 type Client struct {
 	client             paho.Client
+	opts               *paho.ClientOptions
 	mu                 sync.Mutex
 	connectionCallback ConnectionCallback
+	subscriptions      map[string]paho.MessageHandler // topic -> handler, restored on reconnect
+	reconnectHooks     []func()
+	connectedBefore    bool
+	delivery           Delivery
+}
+
+// Delivery sets the QoS of a client's publishes and subscriptions, and
+// whether the device commands it publishes are retained by the broker, so a
+// prop that restarts receives its last command when it resubscribes.
+type Delivery struct {
+	PublishQoS     byte
+	SubscribeQoS   byte
+	RetainCommands bool
+}
+
+// DefaultDelivery returns the delivery used when room.yaml sets none: QoS 1
+// both ways and commands not retained.
+func DefaultDelivery() Delivery {
+	return Delivery{PublishQoS: 1, SubscribeQoS: 1}
 }
 
 // BrokerURL returns the MQTT broker URL from env or default.
@@ -27,12 +53,15 @@ func BrokerURL() string {
 	return "tcp://localhost:1883"
 }
 
-// NewClient creates a new MQTT client but does not connect.
-func NewClient(clientID string) *Client {
-	c := &Client{}
+// NewClient creates a new MQTT client but does not connect. Broker
+// credentials and TLS settings are read from the environment (see auth.go);
+// an error is returned if they are invalid.
+func NewClient(clientID string) (*Client, error) {
+	c := &Client{subscriptions: make(map[string]paho.MessageHandler), delivery: DefaultDelivery()}
 
+	brokerURL := BrokerURL()
 	opts := paho.NewClientOptions().
-		AddBroker(BrokerURL()).
+		AddBroker(brokerURL).
 		SetClientID(clientID).
 		SetAutoReconnect(true).
 		SetConnectRetry(true).
@@ -45,14 +74,34 @@ func NewClient(clientID string) *Client {
 			}
 		}).
 		SetOnConnectHandler(func(_ paho.Client) {
-			log.Printf("mqtt: connected to %s", BrokerURL())
-			if c.connectionCallback != nil {
-				c.connectionCallback(true)
-			}
+			log.Printf("mqtt: connected to %s", brokerURL)
+			c.handleConnect()
 		})
+	if err := applyBrokerAuth(opts, brokerURL); err != nil {
+		return nil, err
+	}
 
+	c.opts = opts
 	c.client = paho.NewClient(opts)
-	return c
+	return c, nil
+}
+
+// SetWill sets the retained message the broker publishes on topic if the
+// client disconnects without saying goodbye. Call it before Connect.
+func (c *Client) SetWill(topic string, payload []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.opts.SetBinaryWill(topic, payload, c.delivery.PublishQoS, true)
+	c.client = paho.NewClient(c.opts)
+}
+
+// SetDelivery sets the client's QoS and command retention. Call it before
+// Connect.
+func (c *Client) SetDelivery(d Delivery) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.delivery = d
 }
 
 // SetConnectionCallback sets a callback to be notified of connection state changes.
@@ -62,6 +111,52 @@ func (c *Client) SetConnectionCallback(cb ConnectionCallback) {
 	c.connectionCallback = cb
 }
 
+// OnReconnect registers fn to run each time the client reconnects to the
+// broker, after its subscriptions have been restored. fn runs on Paho's
+// connect goroutine and may subscribe or publish.
+func (c *Client) OnReconnect(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reconnectHooks = append(c.reconnectHooks, fn)
+}
+
+// handleConnect restores subscriptions and runs the reconnect hooks when a
+// connection is not the first, then reports the connection.
+func (c *Client) handleConnect() {
+	c.mu.Lock()
+	reconnect := c.connectedBefore
+	c.connectedBefore = true
+	subscriptions := make(map[string]paho.MessageHandler, len(c.subscriptions))
+	for topic, handler := range c.subscriptions {
+		subscriptions[topic] = handler
+	}
+	hooks := append([]func(){}, c.reconnectHooks...)
+	callback := c.connectionCallback
+	c.mu.Unlock()
+
+	if reconnect {
+		restored := 0
+		for topic, handler := range subscriptions {
+			if err := c.Subscribe(topic, handler); err != nil {
+				log.Printf("mqtt: failed to restore subscription to %s: %v", topic, err)
+				c.mu.Lock()
+				delete(c.subscriptions, topic)
+				c.mu.Unlock()
+				continue
+			}
+			restored++
+		}
+		log.Printf("mqtt: reconnected, restored %d of %d subscriptions", restored, len(subscriptions))
+		for _, fn := range hooks {
+			fn()
+		}
+	}
+
+	if callback != nil {
+		callback(true)
+	}
+}
+
 // Connect attempts to connect to the broker.
 // Returns an error if connection fails, but does not block indefinitely.
 func (c *Client) Connect() error {
@@ -78,30 +173,82 @@ func (c *Client) Connect() error {
 	return nil
 }
 
-// Subscribe subscribes to a topic with the given handler.
+// Subscribe subscribes to a topic with the given handler. Once accepted, the
+// subscription is restored whenever the client reconnects.
 func (c *Client) Subscribe(topic string, handler paho.MessageHandler) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	token := c.client.Subscribe(topic, 1, handler)
+	token := c.client.Subscribe(topic, c.delivery.SubscribeQoS, handler)
 	if !token.WaitTimeout(10 * time.Second) {
 		return &SubscribeTimeoutError{Topic: topic}
 	}
-	return token.Error()
+	if err := token.Error(); err != nil {
+		return err
+	}
+	c.subscriptions[topic] = handler
+	return nil
+}
+
+// HasSubscription reports whether the client holds a subscription to topic.
+func (c *Client) HasSubscription(topic string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.subscriptions[topic]
+	return ok
 }
 
 // Publish publishes a message to the specified topic.
 func (c *Client) Publish(topic string, payload []byte) error {
+	return c.PublishTimeout(topic, payload, 10*time.Second)
+}
+
+// PublishTimeout publishes a message, waiting at most timeout for the broker to acknowledge it.
+func (c *Client) PublishTimeout(topic string, payload []byte, timeout time.Duration) error {
+	return c.publish(topic, payload, false, timeout)
+}
+
+// PublishRetained publishes a message the broker keeps for later subscribers.
+func (c *Client) PublishRetained(topic string, payload []byte) error {
+	return c.publish(topic, payload, true, 10*time.Second)
+}
+
+func (c *Client) publish(topic string, payload []byte, retain bool, timeout time.Duration) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	token := c.client.Publish(topic, 1, false, payload)
-	if !token.WaitTimeout(10 * time.Second) {
+	token := c.client.Publish(topic, c.delivery.PublishQoS, retain, payload)
+	if !token.WaitTimeout(timeout) {
 		return &PublishTimeoutError{Topic: topic}
 	}
 	return token.Error()
 }
 
+// Commands returns a publisher for device commands, which are retained when
+// the client's delivery says so.
+func (c *Client) Commands() *CommandPublisher {
+	return &CommandPublisher{c}
+}
+
+// CommandPublisher publishes device commands through a client.
+type CommandPublisher struct {
+	c *Client
+}
+
+// IsConnected returns true if the client is connected.
+func (p *CommandPublisher) IsConnected() bool {
+	return p.c.IsConnected()
+}
+
+// PublishTimeout publishes a command, waiting at most timeout for the broker
+// to acknowledge it.
+func (p *CommandPublisher) PublishTimeout(topic string, payload []byte, timeout time.Duration) error {
+	p.c.mu.Lock()
+	retain := p.c.delivery.RetainCommands
+	p.c.mu.Unlock()
+	return p.c.publish(topic, payload, retain, timeout)
+}
+
 // Disconnect cleanly disconnects from the broker.
 func (c *Client) Disconnect() {
 	c.mu.Lock()
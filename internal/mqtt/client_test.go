@@ -0,0 +1,120 @@
+package mqtt
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+// fakePaho records subscriptions; the embedded interface panics on anything else.
+type fakePaho struct {
+	paho.Client
+	mu         sync.Mutex
+	subscribed []string
+	failOnce   map[string]bool // topics whose next subscribe is rejected
+	qos        []byte          // QoS of each subscribe and publish
+	retained   []bool          // retain flag of each publish
+}
+
+func (f *fakePaho) Subscribe(topic string, qos byte, _ paho.MessageHandler) paho.Token {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.subscribed = append(f.subscribed, topic)
+	f.qos = append(f.qos, qos)
+	if f.failOnce[topic] {
+		delete(f.failOnce, topic)
+		return &errToken{err: errors.New("not authorized")}
+	}
+	return &mockToken{}
+}
+
+func (f *fakePaho) Publish(_ string, qos byte, retained bool, _ interface{}) paho.Token {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.qos = append(f.qos, qos)
+	f.retained = append(f.retained, retained)
+	return &mockToken{}
+}
+
+func (f *fakePaho) IsConnected() bool { return true }
+
+func (f *fakePaho) take() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	topics := f.subscribed
+	f.subscribed = nil
+	sort.Strings(topics)
+	return strings.Join(topics, ",")
+}
+
+type errToken struct {
+	mockToken
+	err error
+}
+
+func (t *errToken) Error() error { return t.err }
+
+func TestClient_RestoresSubscriptionsOnReconnect(t *testing.T) {
+	fake := &fakePaho{failOnce: make(map[string]bool)}
+	c := &Client{client: fake, subscriptions: make(map[string]paho.MessageHandler)}
+	var connected []bool
+	c.SetConnectionCallback(func(up bool) { connected = append(connected, up) })
+
+	registry := NewDeviceRegistry()
+	registry.RegisterFromPayload(&RegistrationPayload{
+		Version:    1,
+		Controller: ControllerInfo{ID: "ctrl-001"},
+		Devices: []DeviceRegistration{
+			{LogicalID: "crypt_door", Topics: DeviceTopics{Publish: "devices/ctrl-001/crypt_door/events"}},
+			{LogicalID: "altar", Topics: DeviceTopics{Publish: "devices/ctrl-001/altar/events"}},
+		},
+	})
+	subscriber := NewDeviceSubscriber(c, registry)
+
+	// First connection: nothing to restore
+	c.handleConnect()
+	if err := c.Subscribe("sentient/registration/#", nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := subscriber.SubscribeAll(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fake.take()
+
+	// The broker rejects the altar topic once while restoring
+	fake.failOnce["devices/ctrl-001/altar/events"] = true
+	c.handleConnect()
+	got := fake.take()
+	want := "devices/ctrl-001/altar/events,devices/ctrl-001/altar/events,devices/ctrl-001/crypt_door/events,sentient/registration/#"
+	if got != want {
+		t.Errorf("expected every subscription restored and the rejected one retried by the subscriber\ngot  %s\nwant %s", got, want)
+	}
+	if !c.HasSubscription("devices/ctrl-001/altar/events") || !subscriber.IsSubscribed("devices/ctrl-001/altar/events") {
+		t.Error("expected the altar subscription held after the retry")
+	}
+	if len(connected) != 2 || !connected[1] {
+		t.Errorf("expected the connection reported each time, got %v", connected)
+	}
+}
+
+func TestClient_Delivery(t *testing.T) {
+	fake := &fakePaho{}
+	c := &Client{client: fake, subscriptions: make(map[string]paho.MessageHandler)}
+	c.SetDelivery(Delivery{PublishQoS: 2, SubscribeQoS: 0, RetainCommands: true})
+
+	_ = c.Subscribe("sentient/registration/#", nil)
+	_ = c.Publish("sentient/announce", []byte("x"))
+	_ = c.Commands().PublishTimeout("devices/ctrl-001/crypt_door/commands", []byte("{}"), time.Second)
+
+	if len(fake.qos) != 3 || fake.qos[0] != 0 || fake.qos[1] != 2 || fake.qos[2] != 2 {
+		t.Errorf("expected subscribe QoS 0 and publish QoS 2, got %v", fake.qos)
+	}
+	if len(fake.retained) != 2 || fake.retained[0] || !fake.retained[1] {
+		t.Errorf("expected only the command retained, got %v", fake.retained)
+	}
+}
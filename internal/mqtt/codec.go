@@ -0,0 +1,395 @@
+package mqtt
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Payload encodings a device may declare in its registration.
+const (
+	EncodingJSON    = "json" // default
+	EncodingCBOR    = "cbor"
+	EncodingMsgPack = "msgpack"
+)
+
+// maxDecodeDepth bounds nesting in binary payloads.
+const maxDecodeDepth = 32
+
+var errTruncated = errors.New("truncated payload")
+
+// ValidEncoding returns true if the encoding is supported ("" means JSON).
+func ValidEncoding(encoding string) bool {
+	switch encoding {
+	case "", EncodingJSON, EncodingCBOR, EncodingMsgPack:
+		return true
+	}
+	return false
+}
+
+// DecodePayload decodes a device message into the values encoding/json
+// produces, so conditions see the same payload whatever the wire format:
+// numbers become float64, maps map[string]interface{} (non-string keys are
+// formatted as strings) and byte strings lowercase hex.
+func DecodePayload(encoding string, data []byte) (interface{}, error) {
+	switch encoding {
+	case "", EncodingJSON:
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	case EncodingCBOR:
+		d := &decoder{data: data}
+		v, err := d.cbor(0)
+		if err == nil && d.pos != len(d.data) {
+			err = fmt.Errorf("%d trailing bytes", len(d.data)-d.pos)
+		}
+		return v, err
+	case EncodingMsgPack:
+		d := &decoder{data: data}
+		v, err := d.msgpack(0)
+		if err == nil && d.pos != len(d.data) {
+			err = fmt.Errorf("%d trailing bytes", len(d.data)-d.pos)
+		}
+		return v, err
+	}
+	return nil, fmt.Errorf("unsupported encoding: %s", encoding)
+}
+
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *decoder) next(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errTruncated
+	}
+	b := d.data[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return b, nil
+}
+
+func (d *decoder) byte() (byte, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+// uint reads a big-endian unsigned integer of size bytes.
+func (d *decoder) uint(size int) (uint64, error) {
+	b, err := d.next(uint64(size))
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// cborArg reads the argument of an initial byte: a length, count or value.
+// indefinite is returned for additional info 31.
+func (d *decoder) cborArg(info byte) (arg uint64, indefinite bool, err error) {
+	switch {
+	case info < 24:
+		return uint64(info), false, nil
+	case info <= 27:
+		arg, err = d.uint(1 << (info - 24))
+		return arg, false, err
+	case info == 31:
+		return 0, true, nil
+	}
+	return 0, false, fmt.Errorf("invalid cbor additional info %d", info)
+}
+
+// cbor decodes one CBOR (RFC 8949) data item.
+func (d *decoder) cbor(depth int) (interface{}, error) {
+	if depth > maxDecodeDepth {
+		return nil, errors.New("payload nested too deeply")
+	}
+	ib, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+	major, info := ib>>5, ib&0x1f
+
+	if major == 7 {
+		return d.cborSimple(info)
+	}
+	arg, indefinite, err := d.cborArg(info)
+	if err != nil {
+		return nil, err
+	}
+	if indefinite && (major < 2 || major == 6) {
+		return nil, fmt.Errorf("invalid indefinite length for cbor major type %d", major)
+	}
+
+	switch major {
+	case 0:
+		return float64(arg), nil
+	case 1:
+		return -1 - float64(arg), nil
+	case 2, 3:
+		b, err := d.cborBytes(major, arg, indefinite)
+		if err != nil {
+			return nil, err
+		}
+		if major == 2 {
+			return hex.EncodeToString(b), nil
+		}
+		return string(b), nil
+	case 4:
+		arr := []interface{}{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && d.cborBreak() {
+				break
+			}
+			v, err := d.cbor(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		return arr, nil
+	case 5:
+		m := map[string]interface{}{}
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && d.cborBreak() {
+				break
+			}
+			k, err := d.cbor(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			v, err := d.cbor(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			m[mapKey(k)] = v
+		}
+		return m, nil
+	}
+	// Tag: the tagged value stands for itself
+	return d.cbor(depth + 1)
+}
+
+// cborBytes reads a byte or text string, joining indefinite-length chunks.
+func (d *decoder) cborBytes(major byte, n uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		return d.next(n)
+	}
+	var out []byte
+	for !d.cborBreak() {
+		ib, err := d.byte()
+		if err != nil {
+			return nil, err
+		}
+		if ib>>5 != major {
+			return nil, errors.New("invalid cbor string chunk")
+		}
+		n, indef, err := d.cborArg(ib & 0x1f)
+		if err != nil {
+			return nil, err
+		}
+		if indef {
+			return nil, errors.New("nested indefinite cbor string")
+		}
+		chunk, err := d.next(n)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, chunk...)
+	}
+	return out, nil
+}
+
+// cborBreak consumes the break code ending an indefinite-length item.
+func (d *decoder) cborBreak() bool {
+	if d.pos < len(d.data) && d.data[d.pos] == 0xff {
+		d.pos++
+		return true
+	}
+	return false
+}
+
+func (d *decoder) cborSimple(info byte) (interface{}, error) {
+	switch info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22, 23: // null, undefined
+		return nil, nil
+	case 25:
+		bits, err := d.uint(2)
+		return finite(halfToFloat(uint16(bits)), err)
+	case 26:
+		bits, err := d.uint(4)
+		return finite(float64(math.Float32frombits(uint32(bits))), err)
+	case 27:
+		bits, err := d.uint(8)
+		return finite(math.Float64frombits(bits), err)
+	}
+	return nil, fmt.Errorf("unsupported cbor simple value %d", info)
+}
+
+// finite rejects NaN and ±Inf, which JSON (and so events) cannot carry.
+func finite(v float64, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil, fmt.Errorf("non-finite float %v", v)
+	}
+	return v, nil
+}
+
+// halfToFloat converts an IEEE 754 half-precision float.
+func halfToFloat(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var v float64
+	switch exp {
+	case 0:
+		v = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			v = math.Inf(1)
+		} else {
+			v = math.NaN()
+		}
+	default:
+		v = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -v
+	}
+	return v
+}
+
+// msgpack decodes one MessagePack value.
+func (d *decoder) msgpack(depth int) (interface{}, error) {
+	if depth > maxDecodeDepth {
+		return nil, errors.New("payload nested too deeply")
+	}
+	b, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case b <= 0x7f:
+		return float64(b), nil
+	case b >= 0xe0:
+		return float64(int8(b)), nil
+	case b&0xf0 == 0x80:
+		return d.msgpackMap(uint64(b&0x0f), depth)
+	case b&0xf0 == 0x90:
+		return d.msgpackArray(uint64(b&0x0f), depth)
+	case b&0xe0 == 0xa0:
+		s, err := d.next(uint64(b & 0x1f))
+		return string(s), err
+	}
+
+	switch b {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xc5, 0xc6: // bin 8/16/32
+		n, err := d.uint(1 << (b - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		raw, err := d.next(n)
+		return hex.EncodeToString(raw), err
+	case 0xca:
+		bits, err := d.uint(4)
+		return finite(float64(math.Float32frombits(uint32(bits))), err)
+	case 0xcb:
+		bits, err := d.uint(8)
+		return finite(math.Float64frombits(bits), err)
+	case 0xcc, 0xcd, 0xce, 0xcf: // uint 8/16/32/64
+		v, err := d.uint(1 << (b - 0xcc))
+		return float64(v), err
+	case 0xd0, 0xd1, 0xd2, 0xd3: // int 8/16/32/64
+		size := 1 << (b - 0xd0)
+		v, err := d.uint(size)
+		shift := 64 - 8*size
+		return float64(int64(v<<shift) >> shift), err
+	case 0xd9, 0xda, 0xdb: // str 8/16/32
+		n, err := d.uint(1 << (b - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		s, err := d.next(n)
+		return string(s), err
+	case 0xdc, 0xdd: // array 16/32
+		n, err := d.uint(2 << (b - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.msgpackArray(n, depth)
+	case 0xde, 0xdf: // map 16/32
+		n, err := d.uint(2 << (b - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.msgpackMap(n, depth)
+	}
+	return nil, fmt.Errorf("unsupported msgpack type 0x%02x", b)
+}
+
+func (d *decoder) msgpackArray(n uint64, depth int) (interface{}, error) {
+	if n > uint64(len(d.data)-d.pos) {
+		return nil, errTruncated // every element takes at least one byte
+	}
+	arr := make([]interface{}, 0, n)
+	for i := uint64(0); i < n; i++ {
+		v, err := d.msgpack(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, v)
+	}
+	return arr, nil
+}
+
+func (d *decoder) msgpackMap(n uint64, depth int) (interface{}, error) {
+	m := map[string]interface{}{}
+	for i := uint64(0); i < n; i++ {
+		k, err := d.msgpack(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.msgpack(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		m[mapKey(k)] = v
+	}
+	return m, nil
+}
+
+// mapKey formats a decoded map key as a JSON object key.
+func mapKey(k interface{}) string {
+	if s, ok := k.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(k)
+	return string(b)
+}
@@ -0,0 +1,124 @@
+package mqtt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeCBOR(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want interface{}
+	}{
+		{"uint", []byte{0x18, 0x64}, 100.0},
+		{"negative", []byte{0x38, 0x63}, -100.0},
+		{"uint64", []byte{0x1b, 0, 0, 0, 0xe8, 0xd4, 0xa5, 0x10, 0x00}, 1e12},
+		{"half", []byte{0xf9, 0x3e, 0x00}, 1.5},
+		{"half subnormal", []byte{0xf9, 0x00, 0x01}, 5.960464477539063e-08},
+		{"float32", []byte{0xfa, 0x47, 0xc3, 0x50, 0x00}, 100000.0},
+		{"float64", []byte{0xfb, 0x3f, 0xf1, 0x99, 0x99, 0x99, 0x99, 0x99, 0x9a}, 1.1},
+		{"true", []byte{0xf5}, true},
+		{"null", []byte{0xf6}, nil},
+		{"text", []byte{0x64, 'I', 'E', 'T', 'F'}, "IETF"},
+		{"bytes as hex", []byte{0x44, 0x04, 0xa2, 0x3b, 0xff}, "04a23bff"},
+		{"indefinite text", []byte{0x7f, 0x62, 's', 't', 0x63, 'r', 'e', 'a', 0xff}, "strea"},
+		{"tagged", []byte{0xc1, 0x1a, 0x51, 0x4b, 0x67, 0xb0}, 1363896240.0},
+		{"array", []byte{0x83, 0x01, 0x82, 0x02, 0x03, 0x9f, 0x04, 0xff}, []interface{}{1.0, []interface{}{2.0, 3.0}, []interface{}{4.0}}},
+		{
+			"map",
+			// {"uid": h'04A23B', "rssi": -42, 1: true}
+			[]byte{0xa3, 0x63, 'u', 'i', 'd', 0x43, 0x04, 0xa2, 0x3b, 0x64, 'r', 's', 's', 'i', 0x38, 0x29, 0x01, 0xf5},
+			map[string]interface{}{"uid": "04a23b", "rssi": -42.0, "1": true},
+		},
+		{
+			"indefinite map",
+			[]byte{0xbf, 0x61, 'a', 0xf4, 0xff},
+			map[string]interface{}{"a": false},
+		},
+	}
+	for _, tt := range tests {
+		got, err := DecodePayload(EncodingCBOR, tt.data)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %#v, got %#v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestDecodeMsgPack(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want interface{}
+	}{
+		{"fixint", []byte{0x2a}, 42.0},
+		{"negative fixint", []byte{0xff}, -1.0},
+		{"uint16", []byte{0xcd, 0x01, 0x00}, 256.0},
+		{"int8", []byte{0xd0, 0x80}, -128.0},
+		{"int32", []byte{0xd2, 0xff, 0xff, 0xff, 0xd6}, -42.0},
+		{"float32", []byte{0xca, 0x3f, 0xc0, 0x00, 0x00}, 1.5},
+		{"float64", []byte{0xcb, 0x3f, 0xf1, 0x99, 0x99, 0x99, 0x99, 0x99, 0x9a}, 1.1},
+		{"nil", []byte{0xc0}, nil},
+		{"false", []byte{0xc2}, false},
+		{"fixstr", []byte{0xa3, 'a', 'b', 'c'}, "abc"},
+		{"str8", []byte{0xd9, 0x02, 'h', 'i'}, "hi"},
+		{"bin as hex", []byte{0xc4, 0x03, 0x04, 0xa2, 0x3b}, "04a23b"},
+		{"array16", []byte{0xdc, 0x00, 0x02, 0x01, 0xc3}, []interface{}{1.0, true}},
+		{
+			"map",
+			// {"angle": 270, "dir": "cw", 7: nil}
+			[]byte{0x83, 0xa5, 'a', 'n', 'g', 'l', 'e', 0xcd, 0x01, 0x0e, 0xa3, 'd', 'i', 'r', 0xa2, 'c', 'w', 0x07, 0xc0},
+			map[string]interface{}{"angle": 270.0, "dir": "cw", "7": nil},
+		},
+	}
+	for _, tt := range tests {
+		got, err := DecodePayload(EncodingMsgPack, tt.data)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %#v, got %#v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestDecodePayloadErrors(t *testing.T) {
+	deep := make([]byte, maxDecodeDepth+2)
+	for i := range deep {
+		deep[i] = 0x81 // one-element array, repeated
+	}
+
+	tests := []struct {
+		name     string
+		encoding string
+		data     []byte
+	}{
+		{"cbor empty", EncodingCBOR, nil},
+		{"cbor truncated text", EncodingCBOR, []byte{0x64, 'I', 'E'}},
+		{"cbor huge length", EncodingCBOR, []byte{0x5b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{"cbor trailing", EncodingCBOR, []byte{0x01, 0x02}},
+		{"cbor unterminated", EncodingCBOR, []byte{0x9f, 0x01}},
+		{"cbor too deep", EncodingCBOR, deep},
+		{"cbor half +Inf", EncodingCBOR, []byte{0xf9, 0x7c, 0x00}},
+		{"cbor half NaN", EncodingCBOR, []byte{0xf9, 0x7e, 0x00}},
+		{"cbor float32 -Inf", EncodingCBOR, []byte{0xfa, 0xff, 0x80, 0x00, 0x00}},
+		{"cbor float64 NaN in map", EncodingCBOR, []byte{0xa1, 0x61, 'v', 0xfb, 0x7f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{"msgpack truncated", EncodingMsgPack, []byte{0xcd, 0x01}},
+		{"msgpack huge array", EncodingMsgPack, []byte{0xdd, 0xff, 0xff, 0xff, 0xff}},
+		{"msgpack float32 NaN", EncodingMsgPack, []byte{0xca, 0x7f, 0xc0, 0x00, 0x00}},
+		{"msgpack float64 +Inf", EncodingMsgPack, []byte{0xcb, 0x7f, 0xf0, 0, 0, 0, 0, 0, 0}},
+		{"msgpack ext", EncodingMsgPack, []byte{0xd4, 0x01, 0x00}},
+		{"json invalid", EncodingJSON, []byte("{")},
+		{"unknown encoding", "protobuf", []byte{0x01}},
+	}
+	for _, tt := range tests {
+		if v, err := DecodePayload(tt.encoding, tt.data); err == nil {
+			t.Errorf("%s: expected error, got %#v", tt.name, v)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package mqtt
+
+import (
+	"sort"
+	"time"
+)
+
+// ControllerStatus describes one controller from its last registration and
+// heartbeats.
+type ControllerStatus struct {
+	ControllerID     string     `json:"controller_id"`
+	Type             string     `json:"type,omitempty"`
+	Firmware         string     `json:"firmware,omitempty"`
+	FirmwareOutdated bool       `json:"firmware_outdated"` // below mqtt.min_firmware
+	UptimeMS         int64      `json:"uptime_ms"`         // reported at registration, advanced while connected
+	HeartbeatSec     int        `json:"heartbeat_sec"`
+	Valid            bool       `json:"valid"` // the registration validated against devices.yaml
+	Connected        bool       `json:"connected"`
+	RegisteredAt     time.Time  `json:"registered_at"`
+	LastSeen         *time.Time `json:"last_seen,omitempty"`
+	Devices          []string   `json:"devices"` // logical IDs
+}
+
+// Controllers returns every controller that has registered, sorted by ID.
+func (m *Monitor) Controllers() []ControllerStatus {
+	return m.controllersAt(time.Now())
+}
+
+func (m *Monitor) controllersAt(now time.Time) []ControllerStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	result := make([]ControllerStatus, 0, len(m.registrations))
+	for id, reg := range m.registrations {
+		info := reg.payload.Controller
+		status := ControllerStatus{
+			ControllerID:     id,
+			Type:             info.Type,
+			Firmware:         info.Firmware,
+			FirmwareOutdated: m.firmwareOutdated(info.Firmware),
+			UptimeMS:         info.UptimeMS,
+			HeartbeatSec:     info.HeartbeatSec,
+			Valid:            ValidateRegistration(reg.payload, m.specs).Valid,
+			RegisteredAt:     reg.at,
+			Devices:          make([]string, 0, len(reg.payload.Devices)),
+		}
+		for _, dev := range reg.payload.Devices {
+			status.Devices = append(status.Devices, dev.LogicalID)
+		}
+		if state, ok := m.controllers[id]; ok {
+			status.Connected = state.Connected
+			lastSeen := state.LastSeen
+			status.LastSeen = &lastSeen
+			if state.Connected {
+				status.UptimeMS += now.Sub(reg.at).Milliseconds()
+			}
+		}
+		result = append(result, status)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ControllerID < result[j].ControllerID
+	})
+	return result
+}
@@ -0,0 +1,102 @@
+package mqtt
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DeviceStatus combines configuration, registration, health and asset data for one device.
+type DeviceStatus struct {
+	LogicalID    string                 `json:"logical_id"`
+	ControllerID string                 `json:"controller_id,omitempty"`
+	Type         string                 `json:"type,omitempty"`
+	Required     bool                   `json:"required"`
+	Registered   bool                   `json:"registered"`
+	Connected    bool                   `json:"connected"`
+	LastSeen     *time.Time             `json:"last_seen,omitempty"`
+	Capabilities []string               `json:"capabilities,omitempty"`
+	Topics       *DeviceTopics          `json:"topics,omitempty"`  // from registration
+	Signals      *DeviceSignals         `json:"signals,omitempty"` // from registration
+	Asset        *DeviceAsset           `json:"asset,omitempty"`
+	State        map[string]interface{} `json:"state,omitempty"` // last reported payload fields
+	Power        *DevicePower           `json:"power,omitempty"` // latest smart plug reading
+}
+
+// Devices returns every device from devices.yaml or registration, sorted by logical ID.
+func (m *Monitor) Devices() []DeviceStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	byID := make(map[string]*DeviceStatus)
+	for id, spec := range m.specs {
+		byID[id] = &DeviceStatus{LogicalID: id, Type: spec.Type, Required: spec.Required}
+	}
+
+	for _, dev := range m.registry.All() {
+		status, ok := byID[dev.LogicalID]
+		if !ok {
+			status = &DeviceStatus{LogicalID: dev.LogicalID}
+			byID[dev.LogicalID] = status
+		}
+		status.Registered = true
+		status.ControllerID = dev.ControllerID
+		status.Capabilities = dev.Capabilities
+		status.Topics = &DeviceTopics{Publish: dev.EventTopic, Subscribe: dev.CommandTopic}
+		status.Signals = &DeviceSignals{Inputs: dev.InputSignals, Outputs: dev.OutputSignals}
+		if status.Type == "" {
+			status.Type = dev.Type
+		}
+		if ctrl, ok := m.controllers[dev.ControllerID]; ok {
+			status.Connected = ctrl.Connected
+			lastSeen := ctrl.LastSeen
+			status.LastSeen = &lastSeen
+		}
+	}
+
+	result := make([]DeviceStatus, 0, len(byID))
+	for id, status := range byID {
+		status.Asset = m.registry.Asset(id)
+		if m.subscriber != nil {
+			status.State = m.subscriber.DeviceState(id)
+		}
+		if m.power != nil {
+			status.Power = m.power.Power(id)
+		}
+		result = append(result, *status)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LogicalID < result[j].LogicalID
+	})
+	return result
+}
+
+// DeviceSignals returns the last value a device reported for each payload
+// field, with when it was reported. known is false for a device that is
+// neither in devices.yaml nor registered.
+func (m *Monitor) DeviceSignals(logicalID string) (signals map[string]SignalValue, known bool) {
+	m.mu.RLock()
+	_, known = m.specs[logicalID]
+	subscriber := m.subscriber
+	m.mu.RUnlock()
+
+	if !known && !m.registry.Exists(logicalID) {
+		return nil, false
+	}
+	if subscriber != nil {
+		signals = subscriber.DeviceSignals(logicalID)
+	}
+	return signals, true
+}
+
+// UpdateAsset records asset metadata for a device known from devices.yaml or registration.
+func (m *Monitor) UpdateAsset(logicalID string, asset DeviceAsset) error {
+	m.mu.RLock()
+	_, known := m.specs[logicalID]
+	m.mu.RUnlock()
+
+	if !known && !m.registry.Exists(logicalID) {
+		return fmt.Errorf("device not found: %s", logicalID)
+	}
+	return m.registry.UpdateAsset(logicalID, asset)
+}
@@ -0,0 +1,97 @@
+package mqtt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// WarningOutdatedFirmware is the warning type of controllers below the
+// minimum firmware version.
+const WarningOutdatedFirmware = "outdated_firmware"
+
+// firmwareVersion is a dotted numeric version such as 1.4.2. A leading "v"
+// and any pre-release or build suffix ("-beta", "+g1a2b") are ignored.
+type firmwareVersion []int
+
+// parseFirmware parses a firmware version string.
+func parseFirmware(s string) (firmwareVersion, error) {
+	core := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(core, "-+ "); i >= 0 {
+		core = core[:i]
+	}
+	if core == "" {
+		return nil, fmt.Errorf("firmware version %q is not a dotted number", s)
+	}
+	parts := strings.Split(core, ".")
+	v := make(firmwareVersion, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("firmware version %q is not a dotted number", s)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+// less reports whether v is older than other. Missing components count as
+// zero, so 1.4 equals 1.4.0.
+func (v firmwareVersion) less(other firmwareVersion) bool {
+	for i := 0; i < len(v) || i < len(other); i++ {
+		var a, b int
+		if i < len(v) {
+			a = v[i]
+		}
+		if i < len(other) {
+			b = other[i]
+		}
+		if a != b {
+			return a < b
+		}
+	}
+	return false
+}
+
+// SetMinFirmware sets the firmware version controllers should run at least;
+// empty disables the check.
+func (m *Monitor) SetMinFirmware(min string) error {
+	var v firmwareVersion
+	if min != "" {
+		var err error
+		if v, err = parseFirmware(min); err != nil {
+			return fmt.Errorf("min_firmware: %w", err)
+		}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.minFirmware = min
+	m.minFirmwareVersion = v
+	return nil
+}
+
+// firmwareOutdated reports whether firmware is below the minimum, counting a
+// version that does not parse as outdated. Caller must hold m.mu.
+func (m *Monitor) firmwareOutdated(firmware string) bool {
+	if m.minFirmwareVersion == nil {
+		return false
+	}
+	v, err := parseFirmware(firmware)
+	return err != nil || v.less(m.minFirmwareVersion)
+}
+
+// checkFirmware flags a registering controller whose firmware is below the
+// minimum (room.yaml mqtt.min_firmware). The controller is otherwise accepted
+// as usual. Caller must hold m.mu.
+func (m *Monitor) checkFirmware(info ControllerInfo) {
+	if !m.firmwareOutdated(info.Firmware) {
+		return
+	}
+	events.Warn(WarningOutdatedFirmware, "controller firmware below minimum", map[string]interface{}{
+		"controller_id": info.ID,
+		"firmware":      info.Firmware,
+		"min_firmware":  m.minFirmware,
+	})
+}
@@ -0,0 +1,83 @@
+package mqtt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+func TestFirmwareVersionOrder(t *testing.T) {
+	for _, c := range []struct {
+		a, b string
+		less bool
+	}{
+		{"1.3.9", "1.4.0", true},
+		{"1.4", "1.4.0", false},
+		{"v1.10.0", "1.9.2", false},
+		{"1.4.0-beta", "1.4.1", true},
+		{"2.0.0+g1a2b", "2.0.0", false},
+	} {
+		a, err := parseFirmware(c.a)
+		if err != nil {
+			t.Fatalf("%s: %v", c.a, err)
+		}
+		b, _ := parseFirmware(c.b)
+		if a.less(b) != c.less {
+			t.Errorf("%s < %s: expected %v", c.a, c.b, c.less)
+		}
+	}
+	for _, bad := range []string{"", "latest", "1..2", "1.x"} {
+		if _, err := parseFirmware(bad); err == nil {
+			t.Errorf("expected %q rejected", bad)
+		}
+	}
+}
+
+func TestMonitorControllers(t *testing.T) {
+	m := NewMonitor(map[string]DeviceSpec{"crypt_door": {Type: "door"}}, 2.0)
+	if err := m.SetMinFirmware("latest"); err == nil {
+		t.Error("expected an unparsable minimum rejected")
+	}
+	if err := m.SetMinFirmware("1.4.0"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events.Clear()
+	m.HandleRegistration(&RegistrationPayload{
+		Version:    1,
+		Controller: ControllerInfo{ID: "ctrl-002", Type: "esp32", Firmware: "1.3.2", UptimeMS: 5000, HeartbeatSec: 5},
+		Devices:    []DeviceRegistration{{LogicalID: "crypt_door", Type: "door"}},
+	})
+	m.HandleRegistration(&RegistrationPayload{
+		Version:    1,
+		Controller: ControllerInfo{ID: "ctrl-001", Type: "teensy", Firmware: "v1.4.1", HeartbeatSec: 10},
+		Devices:    []DeviceRegistration{{LogicalID: "crypt_door", Type: "relay"}},
+	})
+
+	var outdated []string
+	for _, e := range events.Snapshot() {
+		if e.Name == "system.warning" && e.Fields["type"] == WarningOutdatedFirmware {
+			outdated = append(outdated, e.Fields["controller_id"].(string))
+			if e.Fields["min_firmware"] != "1.4.0" || e.Fields["firmware"] != "1.3.2" {
+				t.Errorf("unexpected warning fields: %v", e.Fields)
+			}
+		}
+	}
+	if len(outdated) != 1 || outdated[0] != "ctrl-002" {
+		t.Errorf("expected only ctrl-002 flagged, got %v", outdated)
+	}
+
+	controllers := m.controllersAt(time.Now().Add(2 * time.Second))
+	if len(controllers) != 2 || controllers[0].ControllerID != "ctrl-001" {
+		t.Fatalf("expected both controllers by ID, got %+v", controllers)
+	}
+	first, second := controllers[0], controllers[1]
+	if first.Valid || first.Connected || first.FirmwareOutdated || first.HeartbeatSec != 10 || first.LastSeen != nil {
+		t.Errorf("expected ctrl-001 listed but rejected for its door type, got %+v", first)
+	}
+	if !second.Valid || !second.Connected || !second.FirmwareOutdated || second.Type != "esp32" ||
+		second.UptimeMS < 7000 || len(second.Devices) != 1 || second.Devices[0] != "crypt_door" {
+		t.Errorf("unexpected ctrl-002: %+v", second)
+	}
+}
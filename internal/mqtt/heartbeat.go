@@ -0,0 +1,71 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// HeartbeatPayload is the optional body of a heartbeat message.
+type HeartbeatPayload struct {
+	UptimeMS     int64 `json:"uptime_ms,omitempty"`
+	HeartbeatSec int   `json:"heartbeat_sec,omitempty"`
+}
+
+// HeartbeatHandler returns the MQTT handler for HeartbeatTopic.
+func (m *Monitor) HeartbeatHandler() paho.MessageHandler {
+	return func(_ paho.Client, msg paho.Message) {
+		m.HandleHeartbeat(msg.Topic(), msg.Payload(), time.Now())
+	}
+}
+
+// HandleHeartbeat records a heartbeat published on a controller's heartbeat
+// topic at the given time. It reports whether the controller was known.
+// A heartbeat refreshes LastSeen and reconnects a controller that had timed
+// out; controllers without an accepted registration must register first.
+func (m *Monitor) HandleHeartbeat(topic string, payload []byte, at time.Time) bool {
+	ctrlID, ok := controllerID(topic, CurrentTopics().Heartbeat)
+	if !ok {
+		return false
+	}
+	var hb HeartbeatPayload
+	_ = json.Unmarshal(payload, &hb)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.controllers[ctrlID]
+	if !ok {
+		return false
+	}
+	state.LastSeen = at
+	if hb.HeartbeatSec > 0 {
+		state.HeartbeatSec = hb.HeartbeatSec
+	}
+	m.reconnect(ctrlID, state)
+	return true
+}
+
+// reconnect marks a disconnected controller connected again, emitting
+// device.connected with reconnect for each of its devices.
+// Caller must hold m.mu.
+func (m *Monitor) reconnect(ctrlID string, state *ControllerState) {
+	if state.Connected {
+		return
+	}
+	state.Connected = true
+	for _, logicalID := range state.Devices {
+		fields := map[string]interface{}{
+			"controller_id": ctrlID,
+			"logical_id":    logicalID,
+			"reconnect":     true,
+		}
+		if dev := m.registry.Get(logicalID); dev != nil {
+			fields["type"] = dev.Type
+		}
+		events.Emit("info", "device.connected", "", fields)
+	}
+}
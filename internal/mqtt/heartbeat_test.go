@@ -0,0 +1,52 @@
+package mqtt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+func TestMonitor_HandleHeartbeat(t *testing.T) {
+	m := NewMonitor(map[string]DeviceSpec{"crypt_door": {Type: "door"}}, 2.0)
+	if m.HandleHeartbeat("sentient/heartbeat/ctrl-001", nil, time.Now()) {
+		t.Error("expected a heartbeat before registration ignored")
+	}
+
+	m.HandleRegistration(&RegistrationPayload{
+		Version:    1,
+		Controller: ControllerInfo{ID: "ctrl-001", HeartbeatSec: 5},
+		Devices:    []DeviceRegistration{{LogicalID: "crypt_door", Type: "door"}},
+	})
+
+	at := time.Now().Add(3 * time.Second)
+	if !m.HandleHeartbeat("sentient/heartbeat/ctrl-001", []byte(`{"uptime_ms": 9000, "heartbeat_sec": 10}`), at) {
+		t.Fatal("expected the heartbeat accepted")
+	}
+	state := m.GetControllerState("ctrl-001")
+	if !state.LastSeen.Equal(at) || state.HeartbeatSec != 10 {
+		t.Errorf("expected LastSeen and interval refreshed, got %+v", state)
+	}
+
+	// A timed-out controller reconnects on its next heartbeat
+	m.mu.Lock()
+	m.controllers["ctrl-001"].Connected = false
+	m.mu.Unlock()
+	events.Clear()
+	m.HandleHeartbeat("sentient/heartbeat/ctrl-001", []byte("ping"), at.Add(time.Second))
+
+	if !m.GetControllerState("ctrl-001").Connected {
+		t.Error("expected the controller connected again")
+	}
+	snap := events.Snapshot()
+	if len(snap) != 1 || snap[0].Name != "device.connected" || snap[0].Fields["reconnect"] != true ||
+		snap[0].Fields["type"] != "door" {
+		t.Errorf("expected device.connected with reconnect, got %+v", snap)
+	}
+
+	for _, topic := range []string{"sentient/heartbeat/", "sentient/heartbeat/ctrl-001/extra", "sentient/registration/ctrl-001"} {
+		if m.HandleHeartbeat(topic, nil, at) {
+			t.Errorf("%s: expected the topic ignored", topic)
+		}
+	}
+}
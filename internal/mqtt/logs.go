@@ -0,0 +1,229 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// Controller log limits.
+const (
+	// Lines kept in memory per controller.
+	controllerLogHistory = 200
+	// Longer lines are truncated.
+	maxLogLineLen = 1024
+	// How often expired lines are pruned from the store.
+	logPruneInterval = time.Hour
+)
+
+// ControllerLogStore persists controller log lines (implemented by storage.Store).
+type ControllerLogStore interface {
+	SaveControllerLog(row storage.ControllerLogRow) error
+	ControllerLogs(controllerID string, limit int) ([]storage.ControllerLogRow, error)
+	PruneControllerLogs(before time.Time) (int64, error)
+}
+
+// logBucket rate limits one controller's lines.
+type logBucket struct {
+	tokens  float64
+	updated time.Time
+	dropped int
+}
+
+// ControllerLogs collects the debug output controllers publish on
+// <device_prefix>/<controller_id>/logs, one plain text or {"level", "msg"}
+// line per message. Accepted lines are streamed as device.log, kept in memory
+// per controller and written to the store, pruned after the retention period
+// (ADR-026). Lines over the rate limit are dropped and counted on the next one.
+type ControllerLogs struct {
+	mu        sync.Mutex
+	perMinute int
+	retention time.Duration
+	buckets   map[string]*logBucket
+	recent    map[string][]storage.ControllerLogRow
+	store     ControllerLogStore
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewControllerLogs creates a collector accepting perMinute lines per
+// controller (also the burst size) and keeping stored lines for retention.
+func NewControllerLogs(perMinute int, retention time.Duration) *ControllerLogs {
+	return &ControllerLogs{
+		perMinute: perMinute,
+		retention: retention,
+		buckets:   make(map[string]*logBucket),
+		recent:    make(map[string][]storage.ControllerLogRow),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// SetStore sets where log lines are persisted.
+func (l *ControllerLogs) SetStore(store ControllerLogStore) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.store = store
+}
+
+// MessageHandler returns the MQTT handler for ControllerLogTopic.
+func (l *ControllerLogs) MessageHandler() paho.MessageHandler {
+	return func(_ paho.Client, msg paho.Message) {
+		l.Handle(msg.Topic(), msg.Payload(), time.Now())
+	}
+}
+
+// Handle records a line published on a controller's log topic at the given
+// time. It reports whether the line was accepted.
+func (l *ControllerLogs) Handle(topic string, payload []byte, at time.Time) bool {
+	rest, ok := strings.CutPrefix(topic, CurrentTopics().DevicePrefix+"/")
+	if !ok {
+		return false
+	}
+	ctrlID, ok := strings.CutSuffix(rest, "/logs")
+	if !ok || ctrlID == "" || strings.Contains(ctrlID, "/") {
+		return false
+	}
+	row := parseLogLine(ctrlID, payload, at)
+
+	l.mu.Lock()
+	if !l.allow(row.ControllerID, at) {
+		l.mu.Unlock()
+		return false
+	}
+	bucket := l.buckets[row.ControllerID]
+	row.Dropped, bucket.dropped = bucket.dropped, 0
+
+	lines := append(l.recent[row.ControllerID], row)
+	if len(lines) > controllerLogHistory {
+		lines = lines[len(lines)-controllerLogHistory:]
+	}
+	l.recent[row.ControllerID] = lines
+	store := l.store
+	l.mu.Unlock()
+
+	if store != nil {
+		if err := store.SaveControllerLog(row); err != nil {
+			log.Printf("[mqtt] failed to store log line of %s: %v", row.ControllerID, err)
+		}
+	}
+
+	fields := map[string]interface{}{
+		"controller_id": row.ControllerID,
+		"level":         row.Level,
+		"msg":           row.Message,
+	}
+	if row.Dropped > 0 {
+		fields["dropped"] = row.Dropped
+	}
+	_ = events.EmitLive("debug", "device.log", "", fields)
+	return true
+}
+
+// allow takes a token from a controller's bucket, counting the line as
+// dropped if there is none.
+// Caller must hold l.mu.
+func (l *ControllerLogs) allow(controllerID string, at time.Time) bool {
+	b, ok := l.buckets[controllerID]
+	if !ok {
+		b = &logBucket{tokens: float64(l.perMinute), updated: at}
+		l.buckets[controllerID] = b
+	}
+	if elapsed := at.Sub(b.updated); elapsed > 0 {
+		b.tokens = min(float64(l.perMinute), b.tokens+elapsed.Minutes()*float64(l.perMinute))
+		b.updated = at
+	}
+	if b.tokens < 1 {
+		b.dropped++
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Recent returns up to limit of a controller's most recent lines, oldest
+// first: from the store if one is set, else from memory.
+func (l *ControllerLogs) Recent(controllerID string, limit int) ([]storage.ControllerLogRow, error) {
+	if limit <= 0 || limit > controllerLogHistory {
+		limit = controllerLogHistory
+	}
+
+	l.mu.Lock()
+	store := l.store
+	lines := l.recent[controllerID]
+	if len(lines) > limit {
+		lines = lines[len(lines)-limit:]
+	}
+	lines = append([]storage.ControllerLogRow{}, lines...)
+	l.mu.Unlock()
+
+	if store != nil {
+		return store.ControllerLogs(controllerID, limit)
+	}
+	return lines, nil
+}
+
+// Start begins pruning expired lines from the store.
+func (l *ControllerLogs) Start() {
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		ticker := time.NewTicker(logPruneInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-l.stopCh:
+				return
+			case now := <-ticker.C:
+				l.Prune(now)
+			}
+		}
+	}()
+}
+
+// Stop stops pruning.
+func (l *ControllerLogs) Stop() {
+	close(l.stopCh)
+	l.wg.Wait()
+}
+
+// Prune deletes stored lines older than the retention period.
+func (l *ControllerLogs) Prune(now time.Time) {
+	l.mu.Lock()
+	store := l.store
+	l.mu.Unlock()
+	if store == nil || l.retention <= 0 {
+		return
+	}
+	if _, err := store.PruneControllerLogs(now.Add(-l.retention)); err != nil {
+		log.Printf("[mqtt] failed to prune controller logs: %v", err)
+	}
+}
+
+// parseLogLine reads a log message: {"level", "msg"} JSON or plain text.
+func parseLogLine(controllerID string, payload []byte, at time.Time) storage.ControllerLogRow {
+	row := storage.ControllerLogRow{ControllerID: controllerID, Timestamp: at.UTC(), Level: "info"}
+
+	var line struct {
+		Level string `json:"level"`
+		Msg   string `json:"msg"`
+	}
+	if err := json.Unmarshal(payload, &line); err == nil && line.Msg != "" {
+		row.Message = line.Msg
+		if line.Level != "" {
+			row.Level = strings.ToLower(line.Level)
+		}
+	} else {
+		row.Message = strings.TrimRight(string(payload), "\r\n")
+	}
+	if len(row.Message) > maxLogLineLen {
+		row.Message = row.Message[:maxLogLineLen]
+	}
+	return row
+}
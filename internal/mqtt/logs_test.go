@@ -0,0 +1,125 @@
+package mqtt
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// memLogStore is an in-memory ControllerLogStore.
+type memLogStore struct {
+	rows   []storage.ControllerLogRow
+	pruned time.Time
+}
+
+func (s *memLogStore) SaveControllerLog(row storage.ControllerLogRow) error {
+	s.rows = append(s.rows, row)
+	return nil
+}
+
+func (s *memLogStore) ControllerLogs(controllerID string, limit int) ([]storage.ControllerLogRow, error) {
+	var out []storage.ControllerLogRow
+	for _, row := range s.rows {
+		if row.ControllerID == controllerID {
+			out = append(out, row)
+		}
+	}
+	if len(out) > limit {
+		out = out[len(out)-limit:]
+	}
+	return out, nil
+}
+
+func (s *memLogStore) PruneControllerLogs(before time.Time) (int64, error) {
+	s.pruned = before
+	return 0, nil
+}
+
+func TestControllerLogs_Handle(t *testing.T) {
+	events.Clear()
+	sub := events.Subscribe()
+	defer events.Unsubscribe(sub)
+
+	logs := NewControllerLogs(60, 0)
+	now := time.Now()
+	if !logs.Handle("devices/ctrl-001/logs", []byte(`{"level": "WARN", "msg": "maglock current high"}`), now) {
+		t.Fatal("expected the JSON line accepted")
+	}
+	if !logs.Handle("devices/ctrl-001/logs", []byte("boot ok\r\n"), now) {
+		t.Fatal("expected the text line accepted")
+	}
+	if logs.Handle("devices/ctrl-001/events", []byte("not a log"), now) {
+		t.Error("expected other topics ignored")
+	}
+	logs.Handle("devices/ctrl-002/logs", []byte(strings.Repeat("x", 2000)), now)
+
+	lines, err := logs.Recent("ctrl-001", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %+v", lines)
+	}
+	if lines[0].Level != "warn" || lines[0].Message != "maglock current high" {
+		t.Errorf("unexpected JSON line: %+v", lines[0])
+	}
+	if lines[1].Level != "info" || lines[1].Message != "boot ok" {
+		t.Errorf("unexpected text line: %+v", lines[1])
+	}
+	if long, _ := logs.Recent("ctrl-002", 0); len(long) != 1 || len(long[0].Message) != maxLogLineLen {
+		t.Errorf("expected the long line truncated, got %d lines", len(long))
+	}
+
+	e := <-sub
+	if e.Name != "device.log" || e.Level != "debug" || e.Seq != 0 || e.Fields["controller_id"] != "ctrl-001" {
+		t.Errorf("unexpected streamed event: %+v", e)
+	}
+	if len(events.Snapshot()) != 0 {
+		t.Error("expected log lines kept out of the event buffer")
+	}
+}
+
+func TestControllerLogs_RateLimit(t *testing.T) {
+	events.Clear()
+	logs := NewControllerLogs(2, 0)
+	store := &memLogStore{}
+	logs.SetStore(store)
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		logs.Handle("devices/ctrl-001/logs", []byte("spam"), now)
+	}
+	if len(store.rows) != 2 {
+		t.Fatalf("expected the burst limited to 2 lines, got %d", len(store.rows))
+	}
+	if !logs.Handle("devices/ctrl-002/logs", []byte("other"), now) {
+		t.Error("expected each controller limited separately")
+	}
+
+	// One line per 30s refills
+	if !logs.Handle("devices/ctrl-001/logs", []byte("later"), now.Add(30*time.Second)) {
+		t.Fatal("expected a line accepted once the bucket refills")
+	}
+	last := store.rows[len(store.rows)-1]
+	if last.Message != "later" || last.Dropped != 3 {
+		t.Errorf("expected the dropped lines counted on the next line, got %+v", last)
+	}
+
+	lines, _ := logs.Recent("ctrl-001", 2)
+	if len(lines) != 2 || lines[1].Message != "later" {
+		t.Errorf("expected recent lines from the store, got %+v", lines)
+	}
+
+	logs.Prune(now)
+	if !store.pruned.IsZero() {
+		t.Error("expected no pruning without a retention period")
+	}
+	logs.retention = 24 * time.Hour
+	logs.Prune(now)
+	if !store.pruned.Equal(now.Add(-24 * time.Hour)) {
+		t.Errorf("expected lines before %v pruned, got %v", now.Add(-24*time.Hour), store.pruned)
+	}
+}
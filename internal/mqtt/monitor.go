@@ -1,6 +1,7 @@
 package mqtt
 
 import (
+	"sort"
 	"sync"
 	"time"
 
@@ -16,16 +17,36 @@ type ControllerState struct {
 	Connected    bool
 }
 
+// lastRegistration is the most recent registration a controller published.
+type lastRegistration struct {
+	payload *RegistrationPayload
+	at      time.Time
+}
+
+// ControllerRevalidation is the outcome of checking a controller's last
+// registration against reloaded devices.yaml specs.
+type ControllerRevalidation struct {
+	ControllerID string   `json:"controller_id"`
+	WasValid     bool     `json:"was_valid"`
+	Valid        bool     `json:"valid"`
+	Errors       []string `json:"errors,omitempty"`
+}
+
 // Monitor tracks controller registration and health.
 type Monitor struct {
-	mu          sync.RWMutex
-	controllers map[string]*ControllerState
-	specs       map[string]DeviceSpec
-	tolerance   float64 // multiplier for heartbeat interval (e.g., 2.0 = 2x heartbeat)
-	stopCh      chan struct{}
-	wg          sync.WaitGroup
-	registry    *DeviceRegistry
-	subscriber  *DeviceSubscriber
+	mu            sync.RWMutex
+	controllers   map[string]*ControllerState
+	registrations map[string]lastRegistration
+	specs         map[string]DeviceSpec
+	tolerance     float64 // multiplier for heartbeat interval (e.g., 2.0 = 2x heartbeat)
+	stopCh        chan struct{}
+	wg            sync.WaitGroup
+	registry      *DeviceRegistry
+	subscriber    *DeviceSubscriber
+	power         *PowerMonitor
+
+	minFirmware        string          // as configured
+	minFirmwareVersion firmwareVersion // nil: no minimum
 }
 
 // NewMonitor creates a new controller monitor.
@@ -35,24 +56,44 @@ func NewMonitor(specs map[string]DeviceSpec, tolerance float64) *Monitor {
 		tolerance = 2.0 // default: miss 1 heartbeat
 	}
 	return &Monitor{
-		controllers: make(map[string]*ControllerState),
-		specs:       specs,
-		tolerance:   tolerance,
-		stopCh:      make(chan struct{}),
-		registry:    NewDeviceRegistry(),
+		controllers:   make(map[string]*ControllerState),
+		registrations: make(map[string]lastRegistration),
+		specs:         specs,
+		tolerance:     tolerance,
+		stopCh:        make(chan struct{}),
+		registry:      NewDeviceRegistry(),
 	}
 }
 
 // HandleRegistration processes a registration payload.
 // Returns validation result and emits appropriate events.
 func (m *Monitor) HandleRegistration(payload *RegistrationPayload) *ValidationResult {
-	result := ValidateRegistration(payload, m.specs)
-
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	ctrlID := payload.Controller.ID
 	now := time.Now()
+	m.registrations[payload.Controller.ID] = lastRegistration{payload: payload, at: now}
+	m.checkFirmware(payload.Controller)
+
+	result := ValidateRegistration(payload, m.specs)
+	if result.Valid {
+		m.accept(payload, now)
+	} else {
+		// Emit device.error for validation failure
+		events.Emit("error", "device.error", "registration validation failed", map[string]interface{}{
+			"controller_id": payload.Controller.ID,
+			"errors":        result.Errors,
+		})
+	}
+
+	return result
+}
+
+// accept records a valid registration seen at the given time: it registers
+// and subscribes the controller's devices and emits device.connected for each.
+// Caller must hold m.mu.
+func (m *Monitor) accept(payload *RegistrationPayload, seen time.Time) {
+	ctrlID := payload.Controller.ID
 
 	// Collect device logical IDs
 	var deviceIDs []string
@@ -63,53 +104,124 @@ func (m *Monitor) HandleRegistration(payload *RegistrationPayload) *ValidationRe
 	existing, wasConnected := m.controllers[ctrlID]
 	isReconnect := wasConnected && existing != nil && !existing.Connected
 
-	if result.Valid {
-		m.controllers[ctrlID] = &ControllerState{
-			ControllerID: ctrlID,
-			LastSeen:     now,
-			HeartbeatSec: payload.Controller.HeartbeatSec,
-			Devices:      deviceIDs,
-			Connected:    true,
-		}
+	m.controllers[ctrlID] = &ControllerState{
+		ControllerID: ctrlID,
+		LastSeen:     seen,
+		HeartbeatSec: payload.Controller.HeartbeatSec,
+		Devices:      deviceIDs,
+		Connected:    true,
+	}
+
+	// Update device registry with command topics
+	m.registry.RegisterFromPayload(payload)
 
-		// Update device registry with command topics
-		m.registry.RegisterFromPayload(payload)
-
-		// Subscribe to device event topics if subscriber is set
-		if m.subscriber != nil {
-			for _, dev := range payload.Devices {
-				regDev := m.registry.Get(dev.LogicalID)
-				if regDev != nil {
-					if err := m.subscriber.SubscribeDevice(regDev); err != nil {
-						events.Emit("error", "device.error", "failed to subscribe to device events", map[string]interface{}{
-							"controller_id": ctrlID,
-							"logical_id":    dev.LogicalID,
-							"topic":         regDev.EventTopic,
-							"error":         err.Error(),
-						})
-					}
+	// Subscribe to device event topics if subscriber is set
+	if m.subscriber != nil {
+		for _, dev := range payload.Devices {
+			regDev := m.registry.Get(dev.LogicalID)
+			if regDev != nil {
+				if err := m.subscriber.SubscribeDevice(regDev); err != nil {
+					events.Emit("error", "device.error", "failed to subscribe to device events", map[string]interface{}{
+						"controller_id": ctrlID,
+						"logical_id":    dev.LogicalID,
+						"topic":         regDev.EventTopic,
+						"error":         err.Error(),
+					})
 				}
 			}
 		}
+	}
 
-		// Emit device.connected for each device
-		for _, dev := range payload.Devices {
-			events.Emit("info", "device.connected", "", map[string]interface{}{
-				"controller_id": ctrlID,
-				"logical_id":    dev.LogicalID,
-				"type":          dev.Type,
-				"reconnect":     isReconnect,
-			})
-		}
-	} else {
-		// Emit device.error for validation failure
-		events.Emit("error", "device.error", "registration validation failed", map[string]interface{}{
+	// Emit device.connected for each device
+	for _, dev := range payload.Devices {
+		events.Emit("info", "device.connected", "", map[string]interface{}{
 			"controller_id": ctrlID,
-			"errors":        result.Errors,
+			"logical_id":    dev.LogicalID,
+			"type":          dev.Type,
+			"reconnect":     isReconnect,
 		})
 	}
 
-	return result
+	// Devices missing from devices.yaml are accepted but flagged
+	for _, dev := range payload.Devices {
+		if _, known := m.specs[dev.LogicalID]; !known {
+			m.warnUnrecognized(ctrlID, dev.LogicalID)
+		}
+	}
+}
+
+// warnUnrecognized flags a registered device missing from devices.yaml.
+func (m *Monitor) warnUnrecognized(controllerID, logicalID string) {
+	events.Warn("unrecognized_device", "device not in devices.yaml", map[string]interface{}{
+		"controller_id": controllerID,
+		"logical_id":    logicalID,
+	})
+}
+
+// ReloadSpecs replaces the devices.yaml specs and re-validates the last
+// registration of every controller against them, emitting what changed:
+// device.error for a controller no longer valid, device.connected for the
+// devices of a controller that now validates and for devices newly declared
+// on a valid controller, and unrecognized_device warnings for devices no
+// longer declared. Controllers are listed in ID order.
+func (m *Monitor) ReloadSpecs(specs map[string]DeviceSpec) []ControllerRevalidation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	old := m.specs
+	m.specs = specs
+
+	ids := make([]string, 0, len(m.registrations))
+	for id := range m.registrations {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	results := make([]ControllerRevalidation, 0, len(ids))
+	for _, ctrlID := range ids {
+		reg := m.registrations[ctrlID]
+		before := ValidateRegistration(reg.payload, old)
+		after := ValidateRegistration(reg.payload, specs)
+		results = append(results, ControllerRevalidation{
+			ControllerID: ctrlID,
+			WasValid:     before.Valid,
+			Valid:        after.Valid,
+			Errors:       after.Errors,
+		})
+
+		switch {
+		case !after.Valid:
+			if before.Valid {
+				events.Emit("error", "device.error", "registration invalid after devices.yaml reload", map[string]interface{}{
+					"controller_id": ctrlID,
+					"errors":        after.Errors,
+				})
+			}
+		case !before.Valid:
+			// A controller gone quiet is accepted when it next registers
+			if time.Since(reg.at) <= m.timeout(reg.payload.Controller.HeartbeatSec) {
+				m.accept(reg.payload, reg.at)
+			}
+		default:
+			state := m.controllers[ctrlID]
+			for _, dev := range reg.payload.Devices {
+				_, wasKnown := old[dev.LogicalID]
+				_, known := specs[dev.LogicalID]
+				switch {
+				case known && !wasKnown && state != nil && state.Connected:
+					events.Emit("info", "device.connected", "", map[string]interface{}{
+						"controller_id": ctrlID,
+						"logical_id":    dev.LogicalID,
+						"type":          dev.Type,
+						"reconnect":     false,
+					})
+				case wasKnown && !known:
+					m.warnUnrecognized(ctrlID, dev.LogicalID)
+				}
+			}
+		}
+	}
+	return results
 }
 
 // Start begins the background health check loop.
@@ -151,24 +263,29 @@ func (m *Monitor) checkHealth() {
 			continue
 		}
 
-		// Calculate timeout: heartbeat * tolerance
-		timeout := time.Duration(float64(state.HeartbeatSec)*m.tolerance) * time.Second
+		timeout := m.timeout(state.HeartbeatSec)
 		if now.Sub(state.LastSeen) > timeout {
 			state.Connected = false
 
 			// Emit device.disconnected for each device
 			for _, logicalID := range state.Devices {
 				events.Emit("warning", "device.disconnected", "heartbeat timeout", map[string]interface{}{
-					"controller_id":  ctrlID,
-					"logical_id":     logicalID,
-					"last_seen":      state.LastSeen.Format(time.RFC3339),
-					"timeout_sec":    timeout.Seconds(),
+					"controller_id": ctrlID,
+					"logical_id":    logicalID,
+					"last_seen":     state.LastSeen.Format(time.RFC3339),
+					"timeout_sec":   timeout.Seconds(),
+					"reason":        "heartbeat_timeout",
 				})
 			}
 		}
 	}
 }
 
+// timeout is how long a controller may stay silent: heartbeat * tolerance.
+func (m *Monitor) timeout(heartbeatSec int) time.Duration {
+	return time.Duration(float64(heartbeatSec)*m.tolerance) * time.Second
+}
+
 // GetControllerState returns the state of a controller (for testing/inspection).
 func (m *Monitor) GetControllerState(controllerID string) *ControllerState {
 	m.mu.RLock()
@@ -208,3 +325,10 @@ func (m *Monitor) SetSubscriber(subscriber *DeviceSubscriber) {
 	defer m.mu.Unlock()
 	m.subscriber = subscriber
 }
+
+// SetPowerMonitor sets the source of power readings shown with devices.
+func (m *Monitor) SetPowerMonitor(power *PowerMonitor) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.power = power
+}
@@ -0,0 +1,101 @@
+package mqtt
+
+import (
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+func TestMonitor_ReloadSpecs(t *testing.T) {
+	m := NewMonitor(map[string]DeviceSpec{
+		"crypt_door": {Type: "door"},
+		"lights":     {Type: "dimmer"},
+	}, 2.0)
+
+	m.HandleRegistration(&RegistrationPayload{
+		Version:    1,
+		Controller: ControllerInfo{ID: "ctrl-001", HeartbeatSec: 5},
+		Devices: []DeviceRegistration{
+			{LogicalID: "crypt_door", Type: "door"},
+			{LogicalID: "altar", Type: "sensor"},
+		},
+	})
+	if res := m.HandleRegistration(&RegistrationPayload{
+		Version:    1,
+		Controller: ControllerInfo{ID: "ctrl-002", HeartbeatSec: 5},
+		Devices:    []DeviceRegistration{{LogicalID: "lights", Type: "relay"}},
+	}); res.Valid {
+		t.Fatal("expected ctrl-002 rejected for its lights type")
+	}
+
+	// altar is declared, lights fixed to relay, crypt_door now needs a capability
+	events.Clear()
+	results := m.ReloadSpecs(map[string]DeviceSpec{
+		"crypt_door": {Type: "door", Capabilities: []string{"locked_state"}},
+		"lights":     {Type: "relay"},
+		"altar":      {Type: "sensor"},
+	})
+
+	if len(results) != 2 || results[0].ControllerID != "ctrl-001" || results[1].ControllerID != "ctrl-002" {
+		t.Fatalf("expected both controllers re-validated in order, got %+v", results)
+	}
+	if !results[0].WasValid || results[0].Valid || len(results[0].Errors) != 1 {
+		t.Errorf("expected ctrl-001 to become invalid, got %+v", results[0])
+	}
+	if results[1].WasValid || !results[1].Valid {
+		t.Errorf("expected ctrl-002 to become valid, got %+v", results[1])
+	}
+
+	var errored, connected []string
+	for _, e := range events.Snapshot() {
+		switch e.Name {
+		case "device.error":
+			errored = append(errored, e.Fields["controller_id"].(string))
+		case "device.connected":
+			connected = append(connected, e.Fields["logical_id"].(string))
+		}
+	}
+	if len(errored) != 1 || errored[0] != "ctrl-001" {
+		t.Errorf("expected device.error for ctrl-001, got %v", errored)
+	}
+	if len(connected) != 1 || connected[0] != "lights" {
+		t.Errorf("expected device.connected for the accepted lights, got %v", connected)
+	}
+	if state := m.GetControllerState("ctrl-002"); state == nil || !state.Connected {
+		t.Errorf("expected ctrl-002 connected after the reload, got %+v", state)
+	}
+	if !m.DeviceRegistry().Exists("lights") {
+		t.Error("expected lights registered after the reload")
+	}
+}
+
+func TestMonitor_ReloadSpecsDeclaresDevice(t *testing.T) {
+	m := NewMonitor(map[string]DeviceSpec{"crypt_door": {Type: "door"}}, 2.0)
+	m.HandleRegistration(&RegistrationPayload{
+		Version:    1,
+		Controller: ControllerInfo{ID: "ctrl-001", HeartbeatSec: 5},
+		Devices: []DeviceRegistration{
+			{LogicalID: "crypt_door", Type: "door"},
+			{LogicalID: "altar", Type: "sensor"},
+		},
+	})
+
+	events.Clear()
+	m.ReloadSpecs(map[string]DeviceSpec{"altar": {Type: "sensor"}})
+
+	var connected, unrecognized []string
+	for _, e := range events.Snapshot() {
+		switch {
+		case e.Name == "device.connected":
+			connected = append(connected, e.Fields["logical_id"].(string))
+		case e.Name == "system.warning" && e.Fields["type"] == "unrecognized_device":
+			unrecognized = append(unrecognized, e.Fields["logical_id"].(string))
+		}
+	}
+	if len(connected) != 1 || connected[0] != "altar" {
+		t.Errorf("expected device.connected for the newly declared altar, got %v", connected)
+	}
+	if len(unrecognized) != 1 || unrecognized[0] != "crypt_door" {
+		t.Errorf("expected crypt_door flagged once undeclared, got %v", unrecognized)
+	}
+}
@@ -0,0 +1,217 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// AnomalyNoPower is the anomaly kind reported for a prop drawing no power.
+const AnomalyNoPower = "no_power"
+
+// Power monitoring timings.
+const (
+	// A prop must draw too little for this long before it is reported.
+	powerGrace = 30 * time.Second
+	// How often draws are checked against their minimum.
+	powerCheckInterval = 10 * time.Second
+	// Gaps between readings longer than this are not counted as energy used.
+	maxEnergyGap = 5 * time.Minute
+)
+
+// PowerSpec maps a smart plug's readings to a device.
+type PowerSpec struct {
+	Topic    string
+	Field    string  // JSON field holding watts; empty for a bare number
+	MinWatts float64 // 0 disables the no-power check
+}
+
+// DevicePower is a device's latest power reading.
+type DevicePower struct {
+	Watts     float64   `json:"watts"`
+	EnergyWh  float64   `json:"energy_wh"` // used since startup
+	UpdatedAt time.Time `json:"updated_at"`
+	MinWatts  float64   `json:"min_watts,omitempty"`
+	Unpowered bool      `json:"unpowered,omitempty"` // reported as drawing no power
+}
+
+// devicePower tracks one device's readings.
+type devicePower struct {
+	DevicePower
+	lowSince time.Time // when the draw fell below MinWatts; zero while above
+}
+
+// PowerMonitor collects smart plug readings, mapped to devices by
+// devices.yaml (power.topic, power.field), and reports props that should be
+// powered but draw nothing. The latest reading and the energy used since
+// startup are shown in /devices and /metrics.
+type PowerMonitor struct {
+	mu            sync.Mutex
+	specs         map[string]PowerSpec // logical ID -> spec
+	readings      map[string]*devicePower
+	expectPowered func() bool
+	stopCh        chan struct{}
+	wg            sync.WaitGroup
+}
+
+// NewPowerMonitor creates a monitor for the given devices. expectPowered
+// reports whether props should be powered now (for example, outside
+// maintenance); draws are only checked while it returns true.
+func NewPowerMonitor(specs map[string]PowerSpec, expectPowered func() bool) *PowerMonitor {
+	return &PowerMonitor{
+		specs:         specs,
+		readings:      make(map[string]*devicePower),
+		expectPowered: expectPowered,
+		stopCh:        make(chan struct{}),
+	}
+}
+
+// Subscribe subscribes to every mapped plug topic.
+func (p *PowerMonitor) Subscribe(client *Client) error {
+	for logicalID, spec := range p.specs {
+		if err := client.Subscribe(spec.Topic, p.MessageHandler(logicalID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MessageHandler returns the MQTT handler for a device's plug topic.
+func (p *PowerMonitor) MessageHandler(logicalID string) paho.MessageHandler {
+	return func(_ paho.Client, msg paho.Message) {
+		p.HandleReading(logicalID, msg.Payload(), time.Now())
+	}
+}
+
+// HandleReading records a reading for a device. It reports whether the
+// payload held a reading.
+func (p *PowerMonitor) HandleReading(logicalID string, payload []byte, at time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	spec, ok := p.specs[logicalID]
+	if !ok {
+		return false
+	}
+	watts, ok := parseWatts(payload, spec.Field)
+	if !ok {
+		return false
+	}
+
+	r, ok := p.readings[logicalID]
+	if !ok {
+		r = &devicePower{DevicePower: DevicePower{MinWatts: spec.MinWatts}}
+		p.readings[logicalID] = r
+	} else if gap := at.Sub(r.UpdatedAt); gap > 0 && gap <= maxEnergyGap {
+		r.EnergyWh += r.Watts * gap.Hours()
+	}
+	r.Watts = watts
+	r.UpdatedAt = at
+
+	if watts >= spec.MinWatts {
+		r.lowSince = time.Time{}
+		r.Unpowered = false
+	} else if r.lowSince.IsZero() {
+		r.lowSince = at
+	}
+	return true
+}
+
+// parseWatts reads watts from a bare number or a JSON object field.
+func parseWatts(payload []byte, field string) (float64, bool) {
+	if field == "" {
+		w, err := strconv.ParseFloat(strings.TrimSpace(string(payload)), 64)
+		return w, err == nil
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(payload, &obj); err != nil {
+		return 0, false
+	}
+	w, ok := obj[field].(float64)
+	return w, ok
+}
+
+// Check reports props that have drawn less than their minimum for powerGrace
+// while they should be powered, once per outage (anomaly no_power) until the
+// draw recovers.
+func (p *PowerMonitor) Check(now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	expected := p.expectPowered == nil || p.expectPowered()
+	for logicalID, r := range p.readings {
+		if r.MinWatts <= 0 || r.lowSince.IsZero() {
+			continue
+		}
+		if !expected {
+			// Props powered down on purpose are judged afresh afterwards
+			r.lowSince = now
+			continue
+		}
+		if r.Unpowered || now.Sub(r.lowSince) < powerGrace {
+			continue
+		}
+		r.Unpowered = true
+		events.Emit("warning", "device.error", "device anomaly: drawing no power", map[string]interface{}{
+			"logical_id": logicalID,
+			"anomaly":    AnomalyNoPower,
+			"watts":      r.Watts,
+			"min_watts":  r.MinWatts,
+		})
+	}
+}
+
+// Power returns a device's latest reading, or nil if it has none.
+func (p *PowerMonitor) Power(logicalID string) *DevicePower {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	r, ok := p.readings[logicalID]
+	if !ok {
+		return nil
+	}
+	cpy := r.DevicePower
+	return &cpy
+}
+
+// Readings returns the latest reading of every device that has one.
+func (p *PowerMonitor) Readings() map[string]DevicePower {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := make(map[string]DevicePower, len(p.readings))
+	for id, r := range p.readings {
+		out[id] = r.DevicePower
+	}
+	return out
+}
+
+// Start begins checking draws in the background.
+func (p *PowerMonitor) Start() {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(powerCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stopCh:
+				return
+			case now := <-ticker.C:
+				p.Check(now)
+			}
+		}
+	}()
+}
+
+// Stop stops the background checks.
+func (p *PowerMonitor) Stop() {
+	close(p.stopCh)
+	p.wg.Wait()
+}
@@ -0,0 +1,70 @@
+package mqtt
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+func TestPowerMonitor(t *testing.T) {
+	powered := true
+	p := NewPowerMonitor(map[string]PowerSpec{
+		"fog_machine": {Topic: "plugs/fog/power", MinWatts: 5},
+		"projector":   {Topic: "plugs/projector/status", Field: "apower"},
+	}, func() bool { return powered })
+
+	start := time.Now()
+	if !p.HandleReading("fog_machine", []byte("120"), start) ||
+		!p.HandleReading("projector", []byte(`{"apower": 80.5, "voltage": 230}`), start) {
+		t.Fatal("expected both readings accepted")
+	}
+	for name, payload := range map[string]string{"not a number": "on", "missing field": `{"voltage": 230}`} {
+		if p.HandleReading("projector", []byte(payload), start) {
+			t.Errorf("%s: expected the reading rejected", name)
+		}
+	}
+	if p.HandleReading("lights", []byte("10"), start) {
+		t.Error("expected a reading for an unmapped device rejected")
+	}
+
+	// 120 W for 3 minutes is 6 Wh
+	low := start.Add(3 * time.Minute)
+	p.HandleReading("fog_machine", []byte("0"), low)
+	if got := p.Power("fog_machine"); got == nil || got.Watts != 0 || math.Abs(got.EnergyWh-6) > 0.01 {
+		t.Errorf("expected 6 Wh used, got %+v", got)
+	}
+
+	events.Clear()
+	p.Check(low.Add(powerGrace / 2))
+	if len(events.Snapshot()) != 0 {
+		t.Fatal("expected no report within the grace period")
+	}
+	p.Check(low.Add(powerGrace))
+	p.Check(low.Add(2 * powerGrace))
+	snap := events.Snapshot()
+	if len(snap) != 1 || snap[0].Name != "device.error" || snap[0].Fields["anomaly"] != AnomalyNoPower ||
+		snap[0].Fields["logical_id"] != "fog_machine" {
+		t.Fatalf("expected one no_power report, got %+v", snap)
+	}
+	if !p.Power("fog_machine").Unpowered {
+		t.Error("expected the fog machine marked unpowered")
+	}
+
+	// Drawing power again clears it; powered down on purpose is not reported
+	p.HandleReading("fog_machine", []byte("100"), low.Add(3*powerGrace))
+	if p.Power("fog_machine").Unpowered {
+		t.Error("expected the fog machine powered again")
+	}
+	powered = false
+	events.Clear()
+	p.HandleReading("fog_machine", []byte("0"), low.Add(4*powerGrace))
+	p.Check(low.Add(10 * powerGrace))
+	if len(events.Snapshot()) != 0 {
+		t.Error("expected no report while props should be off")
+	}
+	if readings := p.Readings(); len(readings) != 2 || readings["projector"].Watts != 80.5 {
+		t.Errorf("unexpected readings: %+v", readings)
+	}
+}
@@ -0,0 +1,93 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// ReportStateSignal is the command signal asking a device to publish its
+// current state on its event topic, as an ordinary input message. Devices
+// accept it whether or not devices.yaml lists it as an output.
+const ReportStateSignal = "report_state"
+
+// QueryState asks each device to report its current state and waits up to
+// timeout for the answers. It returns the payload fields each device reported
+// after the request; devices that did not answer in time are missing.
+func (s *DeviceSubscriber) QueryState(logicalIDs []string, timeout time.Duration) map[string]map[string]interface{} {
+	requested := time.Now()
+	pending := make(map[string]chan struct{}, len(logicalIDs))
+	s.mu.Lock()
+	for _, id := range logicalIDs {
+		if _, ok := pending[id]; ok {
+			continue
+		}
+		ch := make(chan struct{})
+		pending[id] = ch
+		s.waiters[id] = append(s.waiters[id], ch)
+	}
+	s.mu.Unlock()
+
+	payload, _ := json.Marshal(map[string]interface{}{"signal": ReportStateSignal})
+	for id := range pending {
+		topic := s.registry.GetCommandTopic(id)
+		if topic == "" || s.client == nil {
+			continue
+		}
+		if err := s.client.PublishTimeout(topic, payload, timeout); err != nil {
+			log.Printf("[mqtt] state query for %s failed: %v", id, err)
+		}
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+wait:
+	for _, ch := range pending {
+		select {
+		case <-ch:
+		case <-deadline.C:
+			break wait
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	reported := make(map[string]map[string]interface{})
+	for id, ch := range pending {
+		s.dropWaiter(id, ch)
+		for field, v := range s.state[id] {
+			if v.UpdatedAt.Before(requested) {
+				continue
+			}
+			if reported[id] == nil {
+				reported[id] = make(map[string]interface{})
+			}
+			reported[id][field] = v.Value
+		}
+	}
+	return reported
+}
+
+// notifyReported wakes the state queries waiting on a device.
+// Caller must hold s.mu.
+func (s *DeviceSubscriber) notifyReported(logicalID string) {
+	for _, ch := range s.waiters[logicalID] {
+		close(ch)
+	}
+	delete(s.waiters, logicalID)
+}
+
+// dropWaiter removes a query that stopped waiting on a device.
+// Caller must hold s.mu.
+func (s *DeviceSubscriber) dropWaiter(logicalID string, ch chan struct{}) {
+	waiters := s.waiters[logicalID]
+	for i, w := range waiters {
+		if w == ch {
+			s.waiters[logicalID] = append(waiters[:i], waiters[i+1:]...)
+			break
+		}
+	}
+	if len(s.waiters[logicalID]) == 0 {
+		delete(s.waiters, logicalID)
+	}
+}
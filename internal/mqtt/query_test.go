@@ -0,0 +1,38 @@
+package mqtt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+func TestDeviceSubscriber_QueryState(t *testing.T) {
+	events.Clear()
+	defer events.Clear()
+	subscriber := NewDeviceSubscriber(nil, NewDeviceRegistry())
+	door := subscriber.createHandler("ctrl-012", "crypt_door", "devices/ctrl-012/crypt_door/events")
+	lights := subscriber.createHandler("ctrl-012", "lights", "devices/ctrl-012/lights/events")
+
+	// Values reported before the query do not count as answers
+	lights(nil, &mockMessage{payload: []byte(`{"level": 80}`)})
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		door(nil, &mockMessage{payload: []byte(`{"open": false}`)})
+	}()
+	reported := subscriber.QueryState([]string{"crypt_door", "lights"}, 200*time.Millisecond)
+
+	if reported["crypt_door"]["open"] != false {
+		t.Errorf("expected the door's answer, got %v", reported)
+	}
+	if _, ok := reported["lights"]; ok {
+		t.Errorf("expected no answer from lights, got %v", reported["lights"])
+	}
+
+	subscriber.mu.Lock()
+	defer subscriber.mu.Unlock()
+	if len(subscriber.waiters) != 0 {
+		t.Errorf("expected waiters cleaned up, got %v", subscriber.waiters)
+	}
+}
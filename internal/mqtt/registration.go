@@ -23,11 +23,12 @@ type ControllerInfo struct {
 
 // DeviceRegistration describes a single device provided by the controller.
 type DeviceRegistration struct {
-	LogicalID    string           `json:"logical_id"`
-	Type         string           `json:"type"`
-	Capabilities []string         `json:"capabilities"`
-	Signals      DeviceSignals    `json:"signals"`
-	Topics       DeviceTopics     `json:"topics"`
+	LogicalID    string        `json:"logical_id"`
+	Type         string        `json:"type"`
+	Capabilities []string      `json:"capabilities"`
+	Signals      DeviceSignals `json:"signals"`
+	Topics       DeviceTopics  `json:"topics"`
+	Encoding     string        `json:"encoding,omitempty"` // event payload encoding; default json
 }
 
 // DeviceSignals defines input/output signals for a device.
@@ -87,6 +88,10 @@ func ValidateRegistration(payload *RegistrationPayload, specs map[string]DeviceS
 			result.Valid = false
 			continue
 		}
+		if !ValidEncoding(dev.Encoding) {
+			result.Errors = append(result.Errors, fmt.Sprintf("device %s: unsupported encoding %s", dev.LogicalID, dev.Encoding))
+			result.Valid = false
+		}
 		registered[dev.LogicalID] = dev
 	}
 
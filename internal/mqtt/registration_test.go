@@ -185,6 +185,52 @@ func TestValidateRegistration(t *testing.T) {
 			wantValid: false,
 			wantErrs:  1,
 		},
+		{
+			name: "binary encodings",
+			payload: &RegistrationPayload{
+				Version: 1,
+				Controller: ControllerInfo{
+					ID:           "ctrl-005",
+					HeartbeatSec: 5,
+				},
+				Devices: []DeviceRegistration{
+					{
+						LogicalID:    "required_device",
+						Type:         "actuator",
+						Capabilities: []string{"on", "off"},
+						Encoding:     EncodingCBOR,
+					},
+					{
+						LogicalID:    "example_device",
+						Type:         "sensor",
+						Capabilities: []string{"boolean"},
+						Encoding:     EncodingMsgPack,
+					},
+				},
+			},
+			wantValid: true,
+			wantErrs:  0,
+		},
+		{
+			name: "unsupported encoding",
+			payload: &RegistrationPayload{
+				Version: 1,
+				Controller: ControllerInfo{
+					ID:           "ctrl-006",
+					HeartbeatSec: 5,
+				},
+				Devices: []DeviceRegistration{
+					{
+						LogicalID:    "required_device",
+						Type:         "actuator",
+						Capabilities: []string{"on", "off"},
+						Encoding:     "protobuf",
+					},
+				},
+			},
+			wantValid: false,
+			wantErrs:  1,
+		},
 	}
 
 	for _, tt := range tests {
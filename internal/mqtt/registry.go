@@ -3,38 +3,77 @@ package mqtt
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
 )
 
 // RegisteredDevice holds runtime information about a registered device.
 type RegisteredDevice struct {
-	LogicalID    string
-	ControllerID string
-	Type         string
-	CommandTopic string   // topics.subscribe from registration
-	EventTopic   string   // topics.publish from registration
-	Capabilities []string
-	InputSignals []string
+	LogicalID     string
+	ControllerID  string
+	Type          string
+	CommandTopic  string // topics.subscribe from registration
+	EventTopic    string // topics.publish from registration
+	Capabilities  []string
+	InputSignals  []string
 	OutputSignals []string
+	Encoding      string // event payload encoding from registration
 }
 
 // DeviceRegistry maintains a mapping of logical device IDs to their MQTT topics and metadata.
+// With a cap set, registering a device beyond it sheds the device registered
+// longest ago (its controller has stopped re-registering) with a memory_shed
+// warning.
 type DeviceRegistry struct {
-	mu      sync.RWMutex
-	devices map[string]*RegisteredDevice
+	mu         sync.RWMutex
+	devices    map[string]*RegisteredDevice
+	registered map[string]time.Time // last registration of each device
+	maxDevices int                  // 0 for no cap
+	shed       uint64
+	assets     map[string]DeviceAsset
+	store      AssetStore
 }
 
 // NewDeviceRegistry creates a new empty device registry.
 func NewDeviceRegistry() *DeviceRegistry {
 	return &DeviceRegistry{
-		devices: make(map[string]*RegisteredDevice),
+		devices:    make(map[string]*RegisteredDevice),
+		registered: make(map[string]time.Time),
+		assets:     make(map[string]DeviceAsset),
 	}
 }
 
+// SetMaxDevices caps the number of registered devices (0 for no cap).
+func (r *DeviceRegistry) SetMaxDevices(n int) {
+	r.mu.Lock()
+	r.maxDevices = n
+	shed := r.shedOverCap(nil)
+	r.mu.Unlock()
+	warnShedDevices(shed)
+}
+
+// Len returns the number of registered devices.
+func (r *DeviceRegistry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.devices)
+}
+
+// ShedCount returns how many devices have been shed for the cap since startup.
+func (r *DeviceRegistry) ShedCount() uint64 {
+	return atomic.LoadUint64(&r.shed)
+}
+
 // Register adds or updates a device in the registry.
 func (r *DeviceRegistry) Register(dev *RegisteredDevice) {
 	r.mu.Lock()
-	defer r.mu.Unlock()
 	r.devices[dev.LogicalID] = dev
+	r.registered[dev.LogicalID] = time.Now()
+	shed := r.shedOverCap(map[string]bool{dev.LogicalID: true})
+	r.mu.Unlock()
+	warnShedDevices(shed)
 }
 
 // Unregister removes a device from the registry.
@@ -42,6 +81,44 @@ func (r *DeviceRegistry) Unregister(logicalID string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	delete(r.devices, logicalID)
+	delete(r.registered, logicalID)
+}
+
+// shedOverCap removes the devices registered longest ago, other than keep,
+// until the registry is within its cap, and returns them.
+// Caller must hold r.mu.
+func (r *DeviceRegistry) shedOverCap(keep map[string]bool) []*RegisteredDevice {
+	var shed []*RegisteredDevice
+	for r.maxDevices > 0 && len(r.devices) > r.maxDevices {
+		oldest := ""
+		for id, at := range r.registered {
+			if keep[id] {
+				continue
+			}
+			if oldest == "" || at.Before(r.registered[oldest]) {
+				oldest = id
+			}
+		}
+		if oldest == "" {
+			break
+		}
+		shed = append(shed, r.devices[oldest])
+		delete(r.devices, oldest)
+		delete(r.registered, oldest)
+		atomic.AddUint64(&r.shed, 1)
+	}
+	return shed
+}
+
+// warnShedDevices reports devices shed from the registry.
+func warnShedDevices(shed []*RegisteredDevice) {
+	for _, dev := range shed {
+		events.Warn(events.WarningMemoryShed, "device registry over its cap; dropped the device registered longest ago", map[string]interface{}{
+			"component":     "device_registry",
+			"controller_id": dev.ControllerID,
+			"logical_id":    dev.LogicalID,
+		})
+	}
 }
 
 // Get returns a device by logical ID, or nil if not found.
@@ -77,6 +154,16 @@ func (r *DeviceRegistry) GetCommandTopic(logicalID string) string {
 	return ""
 }
 
+// GetEncoding returns the event payload encoding for a device, or empty string (JSON) if not found.
+func (r *DeviceRegistry) GetEncoding(logicalID string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if dev, ok := r.devices[logicalID]; ok {
+		return dev.Encoding
+	}
+	return ""
+}
+
 // HasOutputSignal returns true if the device supports the given output signal.
 func (r *DeviceRegistry) HasOutputSignal(logicalID, signal string) bool {
 	r.mu.RLock()
@@ -134,9 +221,11 @@ func (r *DeviceRegistry) All() []*RegisteredDevice {
 // RegisterFromPayload registers all devices from a registration payload.
 func (r *DeviceRegistry) RegisterFromPayload(payload *RegistrationPayload) {
 	r.mu.Lock()
-	defer r.mu.Unlock()
-
+	now := time.Now()
+	keep := make(map[string]bool, len(payload.Devices))
 	for _, dev := range payload.Devices {
+		keep[dev.LogicalID] = true
+		r.registered[dev.LogicalID] = now
 		r.devices[dev.LogicalID] = &RegisteredDevice{
 			LogicalID:     dev.LogicalID,
 			ControllerID:  payload.Controller.ID,
@@ -146,8 +235,12 @@ func (r *DeviceRegistry) RegisterFromPayload(payload *RegistrationPayload) {
 			Capabilities:  append([]string{}, dev.Capabilities...),
 			InputSignals:  append([]string{}, dev.Signals.Inputs...),
 			OutputSignals: append([]string{}, dev.Signals.Outputs...),
+			Encoding:      dev.Encoding,
 		}
 	}
+	shed := r.shedOverCap(keep)
+	r.mu.Unlock()
+	warnShedDevices(shed)
 }
 
 // Clear removes all devices from the registry.
@@ -155,4 +248,5 @@ func (r *DeviceRegistry) Clear() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.devices = make(map[string]*RegisteredDevice)
+	r.registered = make(map[string]time.Time)
 }
@@ -2,6 +2,9 @@ package mqtt
 
 import (
 	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
 )
 
 func TestDeviceRegistry_RegisterAndGet(t *testing.T) {
@@ -238,3 +241,33 @@ func TestDeviceRegistry_ValidateCommand_NoCommandTopic(t *testing.T) {
 		t.Error("expected error for device with no command topic")
 	}
 }
+
+func TestDeviceRegistry_MaxDevices(t *testing.T) {
+	events.Clear()
+	registry := NewDeviceRegistry()
+	registry.SetMaxDevices(2)
+
+	registry.Register(&RegisteredDevice{LogicalID: "altar", ControllerID: "ctrl-001"})
+	time.Sleep(time.Millisecond)
+	registry.RegisterFromPayload(&RegistrationPayload{
+		Controller: ControllerInfo{ID: "ctrl-002"},
+		Devices:    []DeviceRegistration{{LogicalID: "lights"}, {LogicalID: "fog"}},
+	})
+
+	if registry.Len() != 2 || registry.Exists("altar") || !registry.Exists("lights") || !registry.Exists("fog") {
+		t.Errorf("expected the device registered longest ago shed, got %d devices", registry.Len())
+	}
+	if registry.ShedCount() != 1 {
+		t.Errorf("expected 1 device shed, got %d", registry.ShedCount())
+	}
+
+	found := false
+	for _, e := range events.Snapshot() {
+		if e.Name == "system.warning" && e.Fields["type"] == events.WarningMemoryShed && e.Fields["logical_id"] == "altar" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected a memory_shed warning for altar")
+	}
+}
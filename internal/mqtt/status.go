@@ -0,0 +1,76 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// Controller status payloads.
+const (
+	StatusOnline  = "online"
+	StatusOffline = "offline"
+)
+
+// StatusHandler returns the MQTT handler for StatusTopic.
+func (m *Monitor) StatusHandler() paho.MessageHandler {
+	return func(_ paho.Client, msg paho.Message) {
+		m.HandleStatus(msg.Topic(), msg.Payload(), time.Now())
+	}
+}
+
+// HandleStatus records a status published on a controller's status topic at
+// the given time. It reports whether the controller was known and the status
+// recognized. "offline", usually the controller's Last Will, disconnects it
+// at once rather than after the heartbeat timeout; "online" counts as a
+// heartbeat.
+func (m *Monitor) HandleStatus(topic string, payload []byte, at time.Time) bool {
+	ctrlID, ok := controllerID(topic, CurrentTopics().Status)
+	if !ok {
+		return false
+	}
+	status := parseStatus(payload)
+	if status != StatusOnline && status != StatusOffline {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.controllers[ctrlID]
+	if !ok {
+		return false
+	}
+	if status == StatusOnline {
+		state.LastSeen = at
+		m.reconnect(ctrlID, state)
+		return true
+	}
+	if state.Connected {
+		state.Connected = false
+		for _, logicalID := range state.Devices {
+			events.Emit("warning", "device.disconnected", "controller offline", map[string]interface{}{
+				"controller_id": ctrlID,
+				"logical_id":    logicalID,
+				"last_seen":     state.LastSeen.Format(time.RFC3339),
+				"reason":        "will",
+			})
+		}
+	}
+	return true
+}
+
+// parseStatus reads a bare status word or a {"status": "..."} object.
+func parseStatus(payload []byte) string {
+	var obj struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(payload, &obj); err == nil && obj.Status != "" {
+		return strings.ToLower(obj.Status)
+	}
+	return strings.ToLower(strings.TrimSpace(string(payload)))
+}
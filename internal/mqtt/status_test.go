@@ -0,0 +1,58 @@
+package mqtt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+func TestMonitor_HandleStatus(t *testing.T) {
+	m := NewMonitor(map[string]DeviceSpec{"crypt_door": {Type: "door"}}, 2.0)
+	if m.HandleStatus("sentient/status/ctrl-001", []byte("offline"), time.Now()) {
+		t.Error("expected a will before registration ignored")
+	}
+
+	m.HandleRegistration(&RegistrationPayload{
+		Version:    1,
+		Controller: ControllerInfo{ID: "ctrl-001", HeartbeatSec: 5},
+		Devices:    []DeviceRegistration{{LogicalID: "crypt_door", Type: "door"}},
+	})
+
+	events.Clear()
+	if !m.HandleStatus("sentient/status/ctrl-001", []byte("Offline"), time.Now()) {
+		t.Fatal("expected the will accepted")
+	}
+	if m.GetControllerState("ctrl-001").Connected {
+		t.Error("expected the controller disconnected at once")
+	}
+	snap := events.Snapshot()
+	if len(snap) != 1 || snap[0].Name != "device.disconnected" || snap[0].Fields["reason"] != "will" {
+		t.Fatalf("expected device.disconnected for the will, got %+v", snap)
+	}
+
+	// A repeated will does not report again; online reconnects
+	m.HandleStatus("sentient/status/ctrl-001", []byte(`{"status": "offline"}`), time.Now())
+	at := time.Now().Add(time.Second)
+	if !m.HandleStatus("sentient/status/ctrl-001", []byte(`{"status": "online"}`), at) {
+		t.Fatal("expected online accepted")
+	}
+	state := m.GetControllerState("ctrl-001")
+	if !state.Connected || !state.LastSeen.Equal(at) {
+		t.Errorf("expected the controller connected and seen, got %+v", state)
+	}
+	snap = events.Snapshot()
+	if len(snap) != 2 || snap[1].Name != "device.connected" || snap[1].Fields["reconnect"] != true {
+		t.Errorf("expected one device.connected after the will, got %+v", snap)
+	}
+
+	for topic, payload := range map[string]string{
+		"sentient/status/ctrl-001":       "rebooting",
+		"sentient/status/ctrl-001/extra": "offline",
+		"sentient/heartbeat/ctrl-001":    "offline",
+	} {
+		if m.HandleStatus(topic, []byte(payload), at) {
+			t.Errorf("%s %q: expected the status ignored", topic, payload)
+		}
+	}
+}
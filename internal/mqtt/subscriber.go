@@ -2,7 +2,9 @@ package mqtt
 
 import (
 	"encoding/json"
+	"reflect"
 	"sync"
+	"time"
 
 	paho "github.com/eclipse/paho.mqtt.golang"
 
@@ -21,15 +23,49 @@ type DeviceSubscriber struct {
 	registry     *DeviceRegistry
 	subscribed   map[string]bool // topic -> subscribed
 	inputHandler DeviceInputHandler
+	tags         *TagRegistry
+	latched      map[string][]string               // logical_id -> latched payload fields
+	state        map[string]map[string]SignalValue // logical_id -> payload field -> last value
+	waiters      map[string][]chan struct{}        // logical_id -> pending state queries (see query.go)
 }
 
-// NewDeviceSubscriber creates a new device subscriber.
+// SignalValue is the last value a device reported for one payload field.
+type SignalValue struct {
+	Value     interface{} `json:"value"`
+	UpdatedAt time.Time   `json:"updated_at"`
+	Latched   bool        `json:"latched,omitempty"` // declared input: latched in devices.yaml
+}
+
+// NewDeviceSubscriber creates a new device subscriber. Device subscriptions
+// are checked again each time the client reconnects to the broker.
 func NewDeviceSubscriber(client *Client, registry *DeviceRegistry) *DeviceSubscriber {
-	return &DeviceSubscriber{
+	s := &DeviceSubscriber{
 		client:     client,
 		registry:   registry,
 		subscribed: make(map[string]bool),
+		state:      make(map[string]map[string]SignalValue),
+		waiters:    make(map[string][]chan struct{}),
+	}
+	if client != nil {
+		client.OnReconnect(s.resubscribe)
+	}
+	return s
+}
+
+// resubscribe runs after the client reconnects and restores its own
+// subscriptions. Device topics it could not restore are forgotten, then every
+// registered device without a subscription is subscribed, so inputs are not
+// lost after a broker restart.
+func (s *DeviceSubscriber) resubscribe() {
+	s.mu.Lock()
+	for topic := range s.subscribed {
+		if !s.client.HasSubscription(topic) {
+			delete(s.subscribed, topic)
+		}
 	}
+	s.mu.Unlock()
+
+	_ = s.SubscribeAll()
 }
 
 // SetInputHandler sets the callback for device.input events.
@@ -40,6 +76,115 @@ func (s *DeviceSubscriber) SetInputHandler(handler DeviceInputHandler) {
 	s.inputHandler = handler
 }
 
+// SetTagRegistry sets the RFID/NFC tag registry used to name tags in reader payloads.
+func (s *DeviceSubscriber) SetTagRegistry(tags *TagRegistry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tags = tags
+}
+
+// SetLatchedFields sets the payload fields, per logical ID, that are latched
+// state rather than momentary events; only they emit device.state_changed.
+func (s *DeviceSubscriber) SetLatchedFields(latched map[string][]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latched = latched
+}
+
+// LastValue returns the last value a device reported for a payload field.
+// A payload that is not a JSON object is kept as the field "value".
+func (s *DeviceSubscriber) LastValue(logicalID, field string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.state[logicalID][field]
+	return v.Value, ok
+}
+
+// DeviceState returns a copy of a device's last reported payload fields, or
+// nil if it has reported none.
+func (s *DeviceSubscriber) DeviceState(logicalID string) map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.state[logicalID]) == 0 {
+		return nil
+	}
+	state := make(map[string]interface{}, len(s.state[logicalID]))
+	for field, v := range s.state[logicalID] {
+		state[field] = v.Value
+	}
+	return state
+}
+
+// DeviceSignals returns a copy of a device's last reported payload fields with
+// when each was reported, or nil if it has reported none.
+func (s *DeviceSubscriber) DeviceSignals(logicalID string) map[string]SignalValue {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.state[logicalID]) == 0 {
+		return nil
+	}
+	signals := make(map[string]SignalValue, len(s.state[logicalID]))
+	for field, v := range s.state[logicalID] {
+		signals[field] = v
+	}
+	return signals
+}
+
+// updateState records every field of a payload as the device's last known
+// value and returns a device.state_changed field set for each latched field
+// whose value changed. The first report of a latched field is a change
+// without a previous value.
+func (s *DeviceSubscriber) updateState(controllerID, logicalID string, payload interface{}) []map[string]interface{} {
+	p, ok := payload.(map[string]interface{})
+	if !ok {
+		if payload == nil {
+			return nil
+		}
+		p = map[string]interface{}{"value": payload}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.state[logicalID]
+	if state == nil {
+		state = make(map[string]SignalValue)
+		s.state[logicalID] = state
+	}
+
+	var changes []map[string]interface{}
+	latched := make(map[string]bool, len(s.latched[logicalID]))
+	for _, field := range s.latched[logicalID] {
+		latched[field] = true
+		v, present := p[field]
+		if !present {
+			continue
+		}
+		previous, known := state[field]
+		if known && reflect.DeepEqual(previous.Value, v) {
+			continue
+		}
+
+		change := map[string]interface{}{
+			"controller_id": controllerID,
+			"logical_id":    logicalID,
+			"field":         field,
+			"value":         v,
+		}
+		if known {
+			change["previous"] = previous.Value
+		}
+		changes = append(changes, change)
+	}
+
+	now := time.Now()
+	for field, v := range p {
+		state[field] = SignalValue{Value: v, UpdatedAt: now, Latched: latched[field]}
+	}
+	s.notifyReported(logicalID)
+	return changes
+}
+
 // SubscribeDevice subscribes to a device's event topic if not already subscribed.
 // This is idempotent - calling multiple times for the same device is safe.
 func (s *DeviceSubscriber) SubscribeDevice(dev *RegisteredDevice) error {
@@ -87,11 +232,34 @@ func (s *DeviceSubscriber) SubscribeAll() error {
 // createHandler creates a message handler that emits device.input events.
 func (s *DeviceSubscriber) createHandler(controllerID, logicalID, topic string) paho.MessageHandler {
 	return func(client paho.Client, msg paho.Message) {
-		// Parse the payload as JSON if possible
 		var payload interface{}
-		if err := json.Unmarshal(msg.Payload(), &payload); err != nil {
-			// If not valid JSON, use raw string
-			payload = string(msg.Payload())
+		switch encoding := s.registry.GetEncoding(logicalID); encoding {
+		case EncodingCBOR, EncodingMsgPack:
+			decoded, err := DecodePayload(encoding, msg.Payload())
+			if err != nil {
+				events.Emit("error", "device.error", "failed to decode device payload", map[string]interface{}{
+					"controller_id": controllerID,
+					"logical_id":    logicalID,
+					"topic":         topic,
+					"encoding":      encoding,
+					"error":         err.Error(),
+				})
+				return
+			}
+			payload = decoded
+		default:
+			// Parse the payload as JSON if possible
+			if err := json.Unmarshal(msg.Payload(), &payload); err != nil {
+				// If not valid JSON, use raw string
+				payload = string(msg.Payload())
+			}
+		}
+
+		s.mu.RLock()
+		tags := s.tags
+		s.mu.RUnlock()
+		if tags != nil {
+			payload = tags.Translate(logicalID, payload)
 		}
 
 		fields := map[string]interface{}{
@@ -101,6 +269,9 @@ func (s *DeviceSubscriber) createHandler(controllerID, logicalID, topic string)
 			"payload":       payload,
 		}
 
+		// Last known values are updated first so conditions on this input see them
+		changes := s.updateState(controllerID, logicalID, payload)
+
 		// Emit device.input event for logging/persistence
 		events.Emit("info", "device.input", "", fields)
 
@@ -111,6 +282,14 @@ func (s *DeviceSubscriber) createHandler(controllerID, logicalID, topic string)
 		if handler != nil {
 			handler("device.input", fields)
 		}
+
+		// Repeated reports of a latched value are not state changes
+		for _, change := range changes {
+			events.Emit("info", "device.state_changed", "", change)
+			if handler != nil {
+				handler("device.state_changed", change)
+			}
+		}
 	}
 }
 
@@ -133,8 +312,8 @@ func (s *DeviceSubscriber) SubscribedTopics() []string {
 	return topics
 }
 
-// ClearSubscriptions clears the subscription tracking.
-// Call this on disconnect to allow re-subscription on reconnect.
+// ClearSubscriptions clears the subscription tracking, so the next
+// SubscribeAll subscribes every device again.
 func (s *DeviceSubscriber) ClearSubscriptions() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
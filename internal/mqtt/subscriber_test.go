@@ -7,6 +7,8 @@ import (
 	"time"
 
 	paho "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
 )
 
 // MockMQTTClient is a mock MQTT client for testing subscriptions.
@@ -350,3 +352,152 @@ func TestDeviceInput_PayloadParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestDeviceSubscriber_BinaryPayloads(t *testing.T) {
+	events.Clear()
+	registry := NewDeviceRegistry()
+	registry.RegisterFromPayload(&RegistrationPayload{
+		Version:    1,
+		Controller: ControllerInfo{ID: "ctrl-009"},
+		Devices: []DeviceRegistration{
+			{LogicalID: "rfid_reader", Topics: DeviceTopics{Publish: "devices/ctrl-009/rfid_reader/events"}, Encoding: EncodingCBOR},
+			{LogicalID: "dial", Topics: DeviceTopics{Publish: "devices/ctrl-009/dial/events"}, Encoding: EncodingMsgPack},
+		},
+	})
+
+	subscriber := NewDeviceSubscriber(nil, registry)
+	var received []map[string]interface{}
+	subscriber.SetInputHandler(func(eventName string, fields map[string]interface{}) {
+		received = append(received, fields)
+	})
+	rfid := subscriber.createHandler("ctrl-009", "rfid_reader", "devices/ctrl-009/rfid_reader/events")
+	dial := subscriber.createHandler("ctrl-009", "dial", "devices/ctrl-009/dial/events")
+
+	// {"uid": h'04A23B'}
+	rfid(nil, &mockMessage{payload: []byte{0xa1, 0x63, 'u', 'i', 'd', 0x43, 0x04, 0xa2, 0x3b}})
+	// {"angle": 270}
+	dial(nil, &mockMessage{payload: []byte{0x81, 0xa5, 'a', 'n', 'g', 'l', 'e', 0xcd, 0x01, 0x0e}})
+	// Truncated CBOR is reported, not routed
+	rfid(nil, &mockMessage{payload: []byte{0xa1, 0x63, 'u'}})
+
+	if len(received) != 2 {
+		t.Fatalf("expected 2 routed inputs, got %d", len(received))
+	}
+	if p, _ := received[0]["payload"].(map[string]interface{}); p["uid"] != "04a23b" {
+		t.Errorf("expected decoded CBOR uid, got %v", received[0]["payload"])
+	}
+	if p, _ := received[1]["payload"].(map[string]interface{}); p["angle"] != 270.0 {
+		t.Errorf("expected decoded MessagePack angle, got %v", received[1]["payload"])
+	}
+
+	var decodeErrors int
+	for _, e := range events.Snapshot() {
+		if e.Name == "device.error" && e.Fields["logical_id"] == "rfid_reader" && e.Fields["encoding"] == EncodingCBOR {
+			decodeErrors++
+		}
+	}
+	if decodeErrors != 1 {
+		t.Errorf("expected 1 decode device.error, got %d", decodeErrors)
+	}
+}
+
+func TestDeviceSubscriber_TagTranslation(t *testing.T) {
+	events.Clear()
+	tags, err := NewTagRegistry("", nil, map[string]string{"04:A2:3B:1C": "ankh_idol"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	subscriber := NewDeviceSubscriber(nil, NewDeviceRegistry())
+	subscriber.SetTagRegistry(tags)
+	var routed interface{}
+	subscriber.SetInputHandler(func(eventName string, fields map[string]interface{}) {
+		routed = fields["payload"]
+	})
+
+	handler := subscriber.createHandler("ctrl-010", "altar_reader", "devices/ctrl-010/altar_reader/events")
+	handler(nil, &mockMessage{payload: []byte(`{"uid": "04A23B1C"}`)})
+
+	if p, _ := routed.(map[string]interface{}); p["item"] != "ankh_idol" {
+		t.Errorf("expected routed payload to name the item, got %v", routed)
+	}
+	snap := events.Snapshot()
+	if len(snap) != 1 || snap[0].Fields["payload"].(map[string]interface{})["item"] != "ankh_idol" {
+		t.Errorf("expected device.input to record the item, got %+v", snap)
+	}
+}
+
+func TestDeviceSubscriber_LatchedState(t *testing.T) {
+	events.Clear()
+	subscriber := NewDeviceSubscriber(nil, NewDeviceRegistry())
+	subscriber.SetLatchedFields(map[string][]string{"crypt_door": {"door_closed"}})
+	var routed []string
+	subscriber.SetInputHandler(func(eventName string, fields map[string]interface{}) {
+		routed = append(routed, eventName)
+	})
+
+	handler := subscriber.createHandler("ctrl-011", "crypt_door", "devices/ctrl-011/crypt_door/events")
+	handler(nil, &mockMessage{payload: []byte(`{"door_closed": true}`)})
+	handler(nil, &mockMessage{payload: []byte(`{"door_closed": true}`)})
+	handler(nil, &mockMessage{payload: []byte(`{"door_closed": false, "knock": 1}`)})
+
+	want := []string{"device.input", "device.state_changed", "device.input", "device.input", "device.state_changed"}
+	if len(routed) != len(want) {
+		t.Fatalf("expected %v routed, got %v", want, routed)
+	}
+	for i := range want {
+		if routed[i] != want[i] {
+			t.Fatalf("expected %v routed, got %v", want, routed)
+		}
+	}
+
+	var changes []events.Event
+	for _, e := range events.Snapshot() {
+		if e.Name == "device.state_changed" {
+			changes = append(changes, e)
+		}
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 device.state_changed events, got %d", len(changes))
+	}
+	if _, ok := changes[0].Fields["previous"]; ok {
+		t.Errorf("expected no previous value on the first report, got %v", changes[0].Fields)
+	}
+	if changes[1].Fields["value"] != false || changes[1].Fields["previous"] != true {
+		t.Errorf("unexpected change: %v", changes[1].Fields)
+	}
+
+	if v, ok := subscriber.LastValue("crypt_door", "door_closed"); !ok || v != false {
+		t.Errorf("expected door_closed false, got %v (%v)", v, ok)
+	}
+	signals := subscriber.DeviceSignals("crypt_door")
+	if !signals["door_closed"].Latched || signals["knock"].Latched || signals["knock"].Value != float64(1) {
+		t.Errorf("expected both fields kept, only door_closed latched, got %+v", signals)
+	}
+}
+
+func TestDeviceSubscriber_LastValues(t *testing.T) {
+	events.Clear()
+	subscriber := NewDeviceSubscriber(nil, NewDeviceRegistry())
+
+	handler := subscriber.createHandler("ctrl-004", "lever", "devices/ctrl-004/lever/events")
+	handler(nil, &mockMessage{payload: []byte(`{"pulled": true, "angle": 40}`)})
+	handler(nil, &mockMessage{payload: []byte(`{"angle": 85}`)})
+	speaker := subscriber.createHandler("ctrl-004", "speaker", "devices/ctrl-004/speaker/events")
+	speaker(nil, &mockMessage{payload: []byte(`"playing"`)})
+
+	state := subscriber.DeviceState("lever")
+	if state["pulled"] != true || state["angle"] != float64(85) {
+		t.Errorf("expected the last value of each field, got %v", state)
+	}
+	if v, ok := subscriber.LastValue("speaker", "value"); !ok || v != "playing" {
+		t.Errorf("expected a plain payload kept as value, got %v (%v)", v, ok)
+	}
+	if subscriber.DeviceSignals("altar") != nil {
+		t.Error("expected no state for a device that never reported")
+	}
+	for _, e := range events.Snapshot() {
+		if e.Name == "device.state_changed" {
+			t.Errorf("expected no device.state_changed without latched fields, got %v", e.Fields)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package mqtt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultTagUIDField is the payload field holding a tag UID.
+const DefaultTagUIDField = "uid"
+
+// TagRegistry translates raw RFID/NFC reader payloads into the props the
+// tags belong to, so scene graphs match on item names instead of UIDs.
+type TagRegistry struct {
+	uidField string
+	readers  map[string]bool // empty: every device
+	items    map[string]string
+}
+
+// NewTagRegistry creates a registry from UID -> item mappings.
+// UIDs are compared after NormalizeTagUID.
+func NewTagRegistry(uidField string, readers []string, tags map[string]string) (*TagRegistry, error) {
+	if uidField == "" {
+		uidField = DefaultTagUIDField
+	}
+	t := &TagRegistry{
+		uidField: uidField,
+		readers:  make(map[string]bool),
+		items:    make(map[string]string),
+	}
+	for _, id := range readers {
+		t.readers[id] = true
+	}
+	for uid, item := range tags {
+		norm := NormalizeTagUID(uid)
+		if norm == "" {
+			return nil, fmt.Errorf("tag %q: empty UID", uid)
+		}
+		if prev, ok := t.items[norm]; ok && prev != item {
+			return nil, fmt.Errorf("tag %s: mapped to both %s and %s", uid, prev, item)
+		}
+		t.items[norm] = item
+	}
+	return t, nil
+}
+
+// NormalizeTagUID lowercases a UID and strips separators, so "04:A2:3B:1C",
+// "04-a2-3b-1c" and "04A23B1C" match.
+func NormalizeTagUID(uid string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(uid) {
+		switch r {
+		case ':', '-', ' ', '.':
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Item returns the item a tag belongs to, or "" if the tag is unknown.
+func (t *TagRegistry) Item(uid string) string {
+	return t.items[NormalizeTagUID(uid)]
+}
+
+// Len returns the number of known tags.
+func (t *TagRegistry) Len() int {
+	return len(t.items)
+}
+
+// Translate adds "item" to a reader payload carrying a known tag UID.
+// The payload is either the UID itself or an object with the UID field;
+// a bare UID becomes an object. Other payloads are returned unchanged.
+func (t *TagRegistry) Translate(logicalID string, payload interface{}) interface{} {
+	if len(t.readers) > 0 && !t.readers[logicalID] {
+		return payload
+	}
+
+	switch p := payload.(type) {
+	case string:
+		if item := t.Item(p); item != "" {
+			return map[string]interface{}{t.uidField: p, "item": item}
+		}
+	case map[string]interface{}:
+		uid, _ := p[t.uidField].(string)
+		if item := t.Item(uid); item != "" {
+			out := make(map[string]interface{}, len(p)+1)
+			for k, v := range p {
+				out[k] = v
+			}
+			out["item"] = item
+			return out
+		}
+	}
+	return payload
+}
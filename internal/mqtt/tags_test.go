@@ -0,0 +1,91 @@
+package mqtt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTagRegistryTranslate(t *testing.T) {
+	tags, err := NewTagRegistry("", []string{"altar_reader"}, map[string]string{
+		"04:A2:3B:1C": "ankh_idol",
+		"04a23b1d":    "ankh_idol",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		logicalID string
+		payload   interface{}
+		want      interface{}
+	}{
+		{
+			"object payload",
+			"altar_reader",
+			map[string]interface{}{"uid": "04-A2-3B-1C", "rssi": -40.0},
+			map[string]interface{}{"uid": "04-A2-3B-1C", "rssi": -40.0, "item": "ankh_idol"},
+		},
+		{
+			"bare UID",
+			"altar_reader",
+			"04A23B1D",
+			map[string]interface{}{"uid": "04A23B1D", "item": "ankh_idol"},
+		},
+		{
+			"hex from binary payload",
+			"altar_reader",
+			map[string]interface{}{"uid": "04a23b1c"},
+			map[string]interface{}{"uid": "04a23b1c", "item": "ankh_idol"},
+		},
+		{
+			"unknown tag",
+			"altar_reader",
+			map[string]interface{}{"uid": "deadbeef"},
+			map[string]interface{}{"uid": "deadbeef"},
+		},
+		{
+			"tag removed",
+			"altar_reader",
+			map[string]interface{}{"uid": nil},
+			map[string]interface{}{"uid": nil},
+		},
+		{
+			"not a reader",
+			"crypt_door",
+			map[string]interface{}{"uid": "04:A2:3B:1C"},
+			map[string]interface{}{"uid": "04:A2:3B:1C"},
+		},
+	}
+	for _, tt := range tests {
+		if got := tags.Translate(tt.logicalID, tt.payload); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	// The input payload is not modified
+	in := map[string]interface{}{"uid": "04A23B1C"}
+	tags.Translate("altar_reader", in)
+	if _, ok := in["item"]; ok {
+		t.Error("expected Translate to copy the payload")
+	}
+}
+
+func TestNewTagRegistryErrors(t *testing.T) {
+	if _, err := NewTagRegistry("", nil, map[string]string{"04:A2": "ankh_idol", "04a2": "scarab"}); err == nil {
+		t.Error("expected error when one UID maps to two items")
+	}
+	if _, err := NewTagRegistry("", nil, map[string]string{"::": "ankh_idol"}); err == nil {
+		t.Error("expected error for empty UID")
+	}
+
+	// Custom UID field, every device a reader
+	tags, err := NewTagRegistry("tag", nil, map[string]string{"0411": "key"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := tags.Translate("any_device", map[string]interface{}{"tag": "04:11"})
+	if p, _ := got.(map[string]interface{}); p["item"] != "key" {
+		t.Errorf("expected item from custom uid field, got %v", got)
+	}
+}
@@ -0,0 +1,106 @@
+package mqtt
+
+import (
+	"strings"
+	"sync"
+)
+
+// Topics is an MQTT topic namespace. Rooms sharing a broker give each room
+// its own prefixes in room.yaml (mqtt.topic_prefix, mqtt.device_prefix) so
+// they never see each other's controllers.
+type Topics struct {
+	Prefix       string // controller topics: <prefix>/registration/<controller_id>, ...
+	DevicePrefix string // device topics: <device_prefix>/<controller_id>/...
+}
+
+// DefaultTopics returns the namespace used when room.yaml sets none.
+func DefaultTopics() Topics {
+	return Topics{Prefix: "sentient", DevicePrefix: "devices"}
+}
+
+// Registration returns a controller's registration topic. With controllerID
+// "#" it is the filter for every controller's.
+func (t Topics) Registration(controllerID string) string {
+	return t.Prefix + "/registration/" + controllerID
+}
+
+// Heartbeat returns a controller's heartbeat topic ("#" for every controller's).
+func (t Topics) Heartbeat(controllerID string) string {
+	return t.Prefix + "/heartbeat/" + controllerID
+}
+
+// Status returns a controller's status and will topic ("#" for every controller's).
+func (t Topics) Status(controllerID string) string {
+	return t.Prefix + "/status/" + controllerID
+}
+
+// Events returns the default root of the topic tree engine events are
+// republished on (bridges.yaml republish).
+func (t Topics) Events() string {
+	return t.Prefix + "/events"
+}
+
+// ControllerLog returns a controller's log topic ("+" for every controller's).
+func (t Topics) ControllerLog(controllerID string) string {
+	return t.DevicePrefix + "/" + controllerID + "/logs"
+}
+
+// DeviceEvents returns the conventional topic a device publishes its events on.
+func (t Topics) DeviceEvents(controllerID, logicalID string) string {
+	return t.DevicePrefix + "/" + controllerID + "/" + logicalID + "/events"
+}
+
+// DeviceCommands returns the conventional topic a device receives its commands on.
+func (t Topics) DeviceCommands(controllerID, logicalID string) string {
+	return t.DevicePrefix + "/" + controllerID + "/" + logicalID + "/commands"
+}
+
+// controllerID returns the controller ID of a topic made by topic (such as
+// t.Status) for a single controller.
+func controllerID(topic string, topicFor func(string) string) (string, bool) {
+	ctrlID, ok := strings.CutPrefix(topic, topicFor(""))
+	if !ok || ctrlID == "" || strings.Contains(ctrlID, "/") {
+		return "", false
+	}
+	return ctrlID, true
+}
+
+var (
+	topicsMu sync.RWMutex
+	topics   = DefaultTopics()
+)
+
+// SetTopics sets the namespace the engine subscribes to and parses topics
+// in. Call it before subscribing.
+func SetTopics(t Topics) {
+	topicsMu.Lock()
+	defer topicsMu.Unlock()
+	topics = t
+}
+
+// CurrentTopics returns the namespace set by SetTopics.
+func CurrentTopics() Topics {
+	topicsMu.RLock()
+	defer topicsMu.RUnlock()
+	return topics
+}
+
+// RegistrationTopic returns the topic filter for controller registrations.
+func RegistrationTopic() string {
+	return CurrentTopics().Registration("#")
+}
+
+// HeartbeatTopic returns the topic filter for controller heartbeats.
+func HeartbeatTopic() string {
+	return CurrentTopics().Heartbeat("#")
+}
+
+// StatusTopic returns the topic filter for controller status and wills.
+func StatusTopic() string {
+	return CurrentTopics().Status("#")
+}
+
+// ControllerLogTopic returns the topic filter for controller log lines.
+func ControllerLogTopic() string {
+	return CurrentTopics().ControllerLog("+")
+}
@@ -0,0 +1,49 @@
+package mqtt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTopics(t *testing.T) {
+	if RegistrationTopic() != "sentient/registration/#" || HeartbeatTopic() != "sentient/heartbeat/#" ||
+		StatusTopic() != "sentient/status/#" || ControllerLogTopic() != "devices/+/logs" {
+		t.Errorf("unexpected default topics: %s %s %s %s", RegistrationTopic(), HeartbeatTopic(), StatusTopic(), ControllerLogTopic())
+	}
+
+	SetTopics(Topics{Prefix: "venue/crypt", DevicePrefix: "venue/crypt/devices"})
+	t.Cleanup(func() { SetTopics(DefaultTopics()) })
+
+	if RegistrationTopic() != "venue/crypt/registration/#" || ControllerLogTopic() != "venue/crypt/devices/+/logs" {
+		t.Errorf("unexpected prefixed topics: %s %s", RegistrationTopic(), ControllerLogTopic())
+	}
+
+	m := NewMonitor(map[string]DeviceSpec{"crypt_door": {Type: "door"}}, 2.0)
+	m.HandleRegistration(&RegistrationPayload{
+		Version:    1,
+		Controller: ControllerInfo{ID: "ctrl-001", HeartbeatSec: 5},
+		Devices:    []DeviceRegistration{{LogicalID: "crypt_door", Type: "door"}},
+	})
+	now := time.Now()
+	if m.HandleHeartbeat("sentient/heartbeat/ctrl-001", nil, now) {
+		t.Error("expected a heartbeat outside the namespace ignored")
+	}
+	if !m.HandleHeartbeat("venue/crypt/heartbeat/ctrl-001", nil, now) {
+		t.Error("expected a heartbeat in the namespace accepted")
+	}
+	if m.HandleStatus("sentient/status/ctrl-001", []byte("online"), now) ||
+		!m.HandleStatus("venue/crypt/status/ctrl-001", []byte("online"), now) {
+		t.Error("expected only status in the namespace accepted")
+	}
+
+	logs := NewControllerLogs(60, 0)
+	if logs.Handle("devices/ctrl-001/logs", []byte("boot"), now) {
+		t.Error("expected a log line outside the namespace ignored")
+	}
+	if !logs.Handle("venue/crypt/devices/ctrl-001/logs", []byte("boot"), now) {
+		t.Error("expected a log line in the namespace accepted")
+	}
+	if rows, _ := logs.Recent("ctrl-001", 0); len(rows) != 1 {
+		t.Errorf("expected the line kept for ctrl-001, got %+v", rows)
+	}
+}
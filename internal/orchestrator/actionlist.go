@@ -0,0 +1,73 @@
+package orchestrator
+
+import "fmt"
+
+// actionsKey is the config key holding an action list.
+const actionsKey = "actions"
+
+// actionList returns a node's action list, if it has one.
+func actionList(config map[string]interface{}) ([]interface{}, bool) {
+	list, ok := config[actionsKey].([]interface{})
+	return list, ok
+}
+
+// hasAction reports whether a node config runs an action or an action list.
+func hasAction(config map[string]interface{}) bool {
+	if _, ok := config["action"].(string); ok {
+		return true
+	}
+	_, ok := actionList(config)
+	return ok
+}
+
+// runActionList executes every entry of an action list in order and returns
+// the first error, if any. The list runs within a single runtime step, so no
+// events are handled and no edges followed between entries, and a failing
+// entry does not stop the rest.
+func (r *Runtime) runActionList(nodeID string, list []interface{}) error {
+	var first error
+	for _, item := range list {
+		entry, _ := item.(map[string]interface{})
+		if err := r.runAction(nodeID, entry); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// validateActionList checks the shape of a node's action list.
+func validateActionList(node Node) error {
+	raw, present := node.Config[actionsKey]
+	if !present {
+		return nil
+	}
+	list, ok := raw.([]interface{})
+	if !ok || len(list) == 0 {
+		return fmt.Errorf("node %s: actions must be a non-empty list", node.ID)
+	}
+	if _, ok := node.Config["action"]; ok {
+		return fmt.Errorf("node %s: action and actions are mutually exclusive", node.ID)
+	}
+	for i, item := range list {
+		entry, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("node %s actions[%d]: must be an object", node.ID, i)
+		}
+		action, _ := entry["action"].(string)
+		switch {
+		case action == "":
+			return fmt.Errorf("node %s actions[%d]: missing action", node.ID, i)
+		case action == WaitAction:
+			return fmt.Errorf("node %s actions[%d]: wait is not allowed in an action list", node.ID, i)
+		}
+		for _, key := range []string{actionsKey, "delay_ms"} {
+			if _, ok := entry[key]; ok {
+				return fmt.Errorf("node %s actions[%d]: %s is not allowed in an action list", node.ID, i, key)
+			}
+		}
+		if err := validateRetry(entry); err != nil {
+			return fmt.Errorf("node %s actions[%d]: %w", node.ID, i, err)
+		}
+	}
+	return nil
+}
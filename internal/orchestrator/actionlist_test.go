@@ -0,0 +1,90 @@
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+func actionListSceneGraph(actions []interface{}) *SceneGraph {
+	return sceneGraph(Scene{
+		ID:    "scene_blackout",
+		Entry: "blackout",
+		Nodes: []Node{
+			{ID: "blackout", Type: "action", Config: map[string]interface{}{"actions": actions}},
+			{ID: "after", Type: "operator", Config: map[string]interface{}{}},
+		},
+		Edges: []Edge{{From: "blackout", To: "after"}},
+	})
+}
+
+func commandConfig(deviceID, signal string) map[string]interface{} {
+	return map[string]interface{}{
+		"action": "device.command",
+		"params": map[string]interface{}{"device_id": deviceID, "signal": signal},
+	}
+}
+
+func TestActionList(t *testing.T) {
+	events.Clear()
+	exec := &replyingExecutor{fail: map[string]bool{"speaker": true}}
+	rt := NewRuntime(actionListSceneGraph([]interface{}{
+		commandConfig("lights", "off"),
+		commandConfig("speaker", "play"),
+		map[string]interface{}{
+			"action": SetVariableAction,
+			"params": map[string]interface{}{"name": "blackout", "value": true},
+		},
+		commandConfig("door", "lock"),
+	}))
+	rt.SetActionExecutor(exec)
+
+	if err := rt.StartScene("scene_blackout"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+
+	// Every entry runs in order, even after one fails
+	exec.mu.Lock()
+	got := strings.Join(exec.commands, ",")
+	exec.mu.Unlock()
+	if got != "lights:off,speaker:play,door:lock" {
+		t.Errorf("unexpected commands: %s", got)
+	}
+	if v := rt.Variables()["blackout"]; v != true {
+		t.Errorf("expected blackout variable set, got %v", v)
+	}
+	if rt.GetNodeState("blackout") != NodeStateCompleted || rt.GetNodeState("after") != NodeStateActive {
+		t.Errorf("expected blackout completed and after active, got %s/%s",
+			rt.GetNodeState("blackout"), rt.GetNodeState("after"))
+	}
+}
+
+func TestActionListValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		want   string
+	}{
+		{"empty", map[string]interface{}{"actions": []interface{}{}}, "non-empty list"},
+		{"not a list", map[string]interface{}{"actions": "lights"}, "non-empty list"},
+		{"with action", map[string]interface{}{"action": "device.command", "actions": []interface{}{commandConfig("a", "on")}}, "mutually exclusive"},
+		{"missing action", map[string]interface{}{"actions": []interface{}{map[string]interface{}{}}}, "missing action"},
+		{"wait", map[string]interface{}{"actions": []interface{}{map[string]interface{}{"action": "wait"}}}, "wait is not allowed"},
+		{"delay", map[string]interface{}{"actions": []interface{}{map[string]interface{}{"action": "device.command", "delay_ms": float64(5)}}}, "delay_ms is not allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sg := actionListSceneGraph(nil)
+			sg.Scenes[0].Nodes[0].Config = tt.config
+			err := ValidateConditions(sg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if err := ValidateConditions(actionListSceneGraph([]interface{}{commandConfig("a", "on")})); err != nil {
+		t.Errorf("expected valid action list, got %v", err)
+	}
+}
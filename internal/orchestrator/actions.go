@@ -3,10 +3,15 @@ package orchestrator
 import (
 	"encoding/json"
 	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/AaronLay10/SentientEngine/internal/config"
 	"github.com/AaronLay10/SentientEngine/internal/events"
 	"github.com/AaronLay10/SentientEngine/internal/mqtt"
+	"github.com/AaronLay10/SentientEngine/internal/tts"
 )
 
 // ActionExecutorInterface defines the interface for action execution.
@@ -15,20 +20,160 @@ type ActionExecutorInterface interface {
 	ExecuteAction(nodeID string, config map[string]interface{}) error
 }
 
+// CommandPublisher publishes device commands (implemented by *mqtt.CommandPublisher).
+type CommandPublisher interface {
+	IsConnected() bool
+	PublishTimeout(topic string, payload []byte, timeout time.Duration) error
+}
+
+// Speaker queues text for speech on a room speaker (implemented by *tts.Client).
+type Speaker interface {
+	Say(req tts.Request) error
+}
+
 // ActionExecutor handles execution of action nodes.
 type ActionExecutor struct {
-	mqttClient     *mqtt.Client
+	publisher      CommandPublisher
 	deviceRegistry *mqtt.DeviceRegistry
+	configMu       sync.RWMutex
 	devicesConfig  *config.DevicesConfig
+	speaker        Speaker
+	sleep          func(time.Duration) // waits between retries
+	retries        sync.WaitGroup      // commands being retried
+	commandsMu     sync.Mutex
+	commands       map[string]*deviceCommands // by device ID
+}
+
+// deviceCommands orders the commands published to one device.
+type deviceCommands struct {
+	mu     sync.Mutex // held while publishing
+	latest uint64     // number of the newest command
+}
+
+// deviceCommands returns the command order of a device.
+func (e *ActionExecutor) deviceCommands(deviceID string) *deviceCommands {
+	e.commandsMu.Lock()
+	defer e.commandsMu.Unlock()
+	if e.commands == nil {
+		e.commands = make(map[string]*deviceCommands)
+	}
+	dc, ok := e.commands[deviceID]
+	if !ok {
+		dc = &deviceCommands{}
+		e.commands[deviceID] = dc
+	}
+	return dc
 }
 
 // NewActionExecutor creates a new action executor.
 func NewActionExecutor(mqttClient *mqtt.Client, deviceRegistry *mqtt.DeviceRegistry, devicesConfig *config.DevicesConfig) *ActionExecutor {
-	return &ActionExecutor{
-		mqttClient:     mqttClient,
+	e := &ActionExecutor{
 		deviceRegistry: deviceRegistry,
 		devicesConfig:  devicesConfig,
+		sleep:          time.Sleep,
+	}
+	if mqttClient != nil {
+		e.publisher = mqttClient.Commands()
+	}
+	return e
+}
+
+// SetDevicesConfig replaces the devices.yaml used to check command signals,
+// e.g. after devices.yaml is reloaded.
+func (e *ActionExecutor) SetDevicesConfig(cfg *config.DevicesConfig) {
+	e.configMu.Lock()
+	defer e.configMu.Unlock()
+	e.devicesConfig = cfg
+}
+
+// devices returns the current devices.yaml.
+func (e *ActionExecutor) devices() *config.DevicesConfig {
+	e.configMu.RLock()
+	defer e.configMu.RUnlock()
+	return e.devicesConfig
+}
+
+const (
+	defaultPublishTimeout = 10 * time.Second
+	maxRetryBackoff       = 5 * time.Second
+	maxRetryAttempts      = 5
+	maxRetryDuration      = 30 * time.Second
+)
+
+// RetryPolicy controls how a device command publish is retried on transient
+// MQTT failures (not connected, publish failed or timed out). It is read from
+// an optional "retry" object in the action config:
+//
+//	"retry": { "attempts": 3, "backoff_ms": 200, "timeout_ms": 2000 }
+//
+// attempts is the total number of tries (default 1, at most maxRetryAttempts),
+// backoff_ms the wait before the second try, doubling after each failure up to
+// maxRetryBackoff, and timeout_ms how long each publish waits for the broker.
+// All attempts and waits together must fit in maxRetryDuration; scene load
+// checks this. Validation errors are not retried. The first attempt runs
+// inline; later ones run in the background so the runtime is not held while
+// waiting. A newer command to the same device cancels a pending retry, so
+// commands never reach a device out of order. device.error is emitted once,
+// after the last attempt.
+type RetryPolicy struct {
+	Attempts int
+	Backoff  time.Duration
+	Timeout  time.Duration
+}
+
+// retryPolicy reads an action's retry config.
+func retryPolicy(config map[string]interface{}) RetryPolicy {
+	policy := RetryPolicy{Attempts: 1, Timeout: defaultPublishTimeout}
+	retry, ok := config["retry"].(map[string]interface{})
+	if !ok {
+		return policy
 	}
+	if n, ok := configInt(retry, "attempts"); ok && n > 1 {
+		policy.Attempts = min(n, maxRetryAttempts)
+	}
+	if ms, ok := configInt(retry, "backoff_ms"); ok && ms > 0 {
+		policy.Backoff = time.Duration(ms) * time.Millisecond
+	}
+	if ms, ok := configInt(retry, "timeout_ms"); ok && ms > 0 {
+		policy.Timeout = time.Duration(ms) * time.Millisecond
+	}
+	return policy
+}
+
+// Duration returns the longest time all attempts and the waits between
+// them can take.
+func (p RetryPolicy) Duration() time.Duration {
+	total := time.Duration(p.Attempts) * p.Timeout
+	backoff := p.Backoff
+	for attempt := 2; attempt <= p.Attempts; attempt++ {
+		total += backoff
+		backoff = min(backoff*2, maxRetryBackoff)
+	}
+	return total
+}
+
+// validateRetry checks an action's retry config.
+func validateRetry(config map[string]interface{}) error {
+	raw, present := config["retry"]
+	if !present {
+		return nil
+	}
+	retry, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("retry must be an object")
+	}
+	if n, ok := configInt(retry, "attempts"); ok && (n < 1 || n > maxRetryAttempts) {
+		return fmt.Errorf("retry attempts must be between 1 and %d", maxRetryAttempts)
+	}
+	for _, key := range []string{"backoff_ms", "timeout_ms"} {
+		if ms, ok := configInt(retry, key); ok && ms < 0 {
+			return fmt.Errorf("retry %s must not be negative", key)
+		}
+	}
+	if d := retryPolicy(config).Duration(); d > maxRetryDuration {
+		return fmt.Errorf("retry may take %v, more than %v; lower attempts or timeout_ms", d, maxRetryDuration)
+	}
+	return nil
 }
 
 // ExecuteAction executes an action node and returns an error if the action fails.
@@ -42,6 +187,10 @@ func (e *ActionExecutor) ExecuteAction(nodeID string, config map[string]interfac
 	switch actionName {
 	case "device.command":
 		return e.executeDeviceCommand(nodeID, config)
+	case "display.message":
+		return e.executeDisplayMessage(nodeID, config)
+	case TTSAction:
+		return e.executeTTS(nodeID, config)
 	default:
 		// Unknown action types complete without doing anything (MVP behavior)
 		return nil
@@ -77,8 +226,8 @@ func (e *ActionExecutor) executeDeviceCommand(nodeID string, config map[string]i
 	}
 
 	// Validate signal is allowed by devices.yaml outputs
-	if e.devicesConfig != nil {
-		if devDef, ok := e.devicesConfig.Devices[deviceID]; ok {
+	if devicesConfig := e.devices(); devicesConfig != nil {
+		if devDef, ok := devicesConfig.Devices[deviceID]; ok {
 			found := false
 			for _, output := range devDef.Signals.Outputs {
 				if output == signal {
@@ -109,18 +258,192 @@ func (e *ActionExecutor) executeDeviceCommand(nodeID string, config map[string]i
 		return e.emitDeviceError(nodeID, deviceID, signal, commandTopic, fmt.Sprintf("failed to marshal payload: %v", err))
 	}
 
-	// Publish to MQTT
-	if e.mqttClient == nil || !e.mqttClient.IsConnected() {
-		return e.emitDeviceError(nodeID, deviceID, signal, commandTopic, "MQTT client not connected")
+	// Publish to MQTT, retrying transient failures in the background. This
+	// command supersedes any retry still pending for the device.
+	policy := retryPolicy(config)
+	dc := e.deviceCommands(deviceID)
+	dc.mu.Lock()
+	dc.latest++
+	n := dc.latest
+	err = e.publish(commandTopic, payloadBytes, policy.Timeout)
+	dc.mu.Unlock()
+	if err == nil {
+		return nil
+	}
+	if policy.Attempts == 1 {
+		return e.emitDeviceError(nodeID, deviceID, signal, commandTopic, err.Error())
+	}
+	e.retries.Add(1)
+	go func() {
+		defer e.retries.Done()
+		e.retryDeviceCommand(nodeID, deviceID, signal, commandTopic, payloadBytes, policy, dc, n)
+	}()
+	return nil
+}
+
+// publish sends a command payload to the broker once.
+func (e *ActionExecutor) publish(topic string, payload []byte, timeout time.Duration) error {
+	if e.publisher == nil || !e.publisher.IsConnected() {
+		return fmt.Errorf("MQTT client not connected")
+	}
+	if err := e.publisher.PublishTimeout(topic, payload, timeout); err != nil {
+		return fmt.Errorf("MQTT publish failed: %v", err)
+	}
+	return nil
+}
+
+// retryDeviceCommand makes the attempts after the first failed one of
+// command n to a device, emitting device.error if none succeeds. It gives up
+// once a newer command has been sent to the device.
+func (e *ActionExecutor) retryDeviceCommand(nodeID, deviceID, signal, topic string, payload []byte, policy RetryPolicy, dc *deviceCommands, n uint64) {
+	backoff := policy.Backoff
+	var err error
+	for attempt := 2; attempt <= policy.Attempts; attempt++ {
+		e.sleep(backoff)
+		backoff = min(backoff*2, maxRetryBackoff)
+
+		dc.mu.Lock()
+		if dc.latest != n {
+			dc.mu.Unlock()
+			log.Printf("[actions] %s: %s.%s not retried, superseded by a newer command", nodeID, deviceID, signal)
+			return
+		}
+		err = e.publish(topic, payload, policy.Timeout)
+		dc.mu.Unlock()
+		if err == nil {
+			log.Printf("[actions] %s: %s.%s published on attempt %d", nodeID, deviceID, signal, attempt)
+			return
+		}
+	}
+	_ = e.emitDeviceError(nodeID, deviceID, signal, topic, fmt.Sprintf("%s (after %d attempts)", err, policy.Attempts))
+}
+
+// DisplaySignal is the output signal in-room displays accept for text messages.
+const DisplaySignal = "message"
+
+// executeDisplayMessage handles the display.message action type.
+// Params: text (required), device_id (optional; defaults to every display in
+// devices.yaml that accepts the message signal), duration_ms (optional).
+func (e *ActionExecutor) executeDisplayMessage(nodeID string, config map[string]interface{}) error {
+	params, ok := config["params"].(map[string]interface{})
+	if !ok {
+		return e.emitDeviceError(nodeID, "", "", "", "missing 'params' field")
+	}
+
+	text, ok := params["text"].(string)
+	if !ok || text == "" {
+		return e.emitDeviceError(nodeID, "", "", "", "missing 'text' in params")
+	}
+
+	payload := map[string]interface{}{"text": text}
+	if d, ok := params["duration_ms"]; ok {
+		payload["duration_ms"] = d
+	}
+
+	targets := []string{}
+	if deviceID, ok := params["device_id"].(string); ok && deviceID != "" {
+		targets = append(targets, deviceID)
+	} else {
+		targets = e.displayDevices()
+	}
+	if len(targets) == 0 {
+		return e.emitDeviceError(nodeID, "", DisplaySignal, "", "no display devices configured")
+	}
+
+	// Deliver to every display; report the first failure
+	var firstErr error
+	for _, deviceID := range targets {
+		err := e.executeDeviceCommand(nodeID, map[string]interface{}{
+			"params": map[string]interface{}{
+				"device_id": deviceID,
+				"signal":    DisplaySignal,
+				"payload":   payload,
+			},
+			"retry": config["retry"],
+		})
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// displayDevices returns the devices.yaml devices of type display that accept messages, sorted.
+func (e *ActionExecutor) displayDevices() []string {
+	var ids []string
+	devicesConfig := e.devices()
+	if devicesConfig == nil {
+		return ids
+	}
+	for id, def := range devicesConfig.Devices {
+		if def.Type != "display" {
+			continue
+		}
+		for _, output := range def.Signals.Outputs {
+			if output == DisplaySignal {
+				ids = append(ids, id)
+				break
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// TTSAction speaks params.text on a room speaker through the configured TTS service.
+const TTSAction = "tts.speak"
+
+// SetSpeaker sets the TTS client used by tts.speak.
+func (e *ActionExecutor) SetSpeaker(s Speaker) {
+	e.speaker = s
+}
+
+// executeTTS queues a tts.speak action. Synthesis and playback run in the
+// background so the runtime is not blocked; failures are reported as device.error.
+func (e *ActionExecutor) executeTTS(nodeID string, config map[string]interface{}) error {
+	params, ok := config["params"].(map[string]interface{})
+	if !ok {
+		return e.emitDeviceError(nodeID, "", "", "", "missing 'params' field")
+	}
+
+	text, ok := params["text"].(string)
+	if !ok || text == "" {
+		return e.emitDeviceError(nodeID, "", "", "", "missing 'text' in params")
 	}
+	voice, _ := params["voice"].(string)
+	speaker, _ := params["speaker"].(string)
 
-	if err := e.mqttClient.Publish(commandTopic, payloadBytes); err != nil {
-		return e.emitDeviceError(nodeID, deviceID, signal, commandTopic, fmt.Sprintf("MQTT publish failed: %v", err))
+	if e.speaker == nil {
+		return e.emitDeviceError(nodeID, speaker, "", "", "tts not configured")
 	}
 
+	err := e.speaker.Say(tts.Request{
+		Text:    text,
+		Voice:   voice,
+		Speaker: speaker,
+		OnError: func(err error) {
+			_ = e.emitDeviceError(nodeID, speaker, "", "", err.Error())
+		},
+	})
+	if err != nil {
+		return e.emitDeviceError(nodeID, speaker, "", "", err.Error())
+	}
 	return nil
 }
 
+// SendDisplayMessage relays operator free text to in-room displays.
+// An empty deviceID targets every display.
+func (e *ActionExecutor) SendDisplayMessage(text, deviceID string) error {
+	params := map[string]interface{}{"text": text}
+	if deviceID != "" {
+		params["device_id"] = deviceID
+	}
+	return e.ExecuteAction("operator_message", map[string]interface{}{
+		"action": "display.message",
+		"params": params,
+	})
+}
+
 // emitDeviceError emits a device.error event with full context and returns an error.
 func (e *ActionExecutor) emitDeviceError(nodeID, deviceID, signal, topic, msg string) error {
 	fields := map[string]interface{}{
@@ -2,10 +2,14 @@ package orchestrator
 
 import (
 	"encoding/json"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/events"
 	"github.com/AaronLay10/SentientEngine/internal/mqtt"
 )
 
@@ -393,3 +397,247 @@ type testError struct {
 func (e *testError) Error() string {
 	return e.msg
 }
+
+func TestActionExecutor_DisplayMessageTargets(t *testing.T) {
+	display := func(outputs ...string) config.DeviceDefinition {
+		def := config.DeviceDefinition{Type: "display"}
+		def.Signals.Outputs = outputs
+		return def
+	}
+	devCfg := &config.DevicesConfig{
+		Version: 1,
+		Devices: map[string]config.DeviceDefinition{
+			"lobby_screen": display("message"),
+			"crypt_screen": display("message", "clear"),
+			"video_wall":   display("play"),
+			"crypt_door":   {Type: "door"},
+		},
+	}
+	registry := mqtt.NewDeviceRegistry()
+	registry.Register(&mqtt.RegisteredDevice{
+		LogicalID:     "crypt_screen",
+		CommandTopic:  "devices/ctrl-002/crypt_screen/commands",
+		OutputSignals: []string{"message", "clear"},
+	})
+	executor := NewActionExecutor(nil, registry, devCfg)
+
+	targets := executor.displayDevices()
+	if len(targets) != 2 || targets[0] != "crypt_screen" || targets[1] != "lobby_screen" {
+		t.Errorf("expected crypt_screen and lobby_screen, got %v", targets)
+	}
+
+	if err := executor.SendDisplayMessage("", ""); err == nil {
+		t.Error("expected error for empty text")
+	}
+
+	// Validation passes for the registered display; delivery fails without MQTT
+	err := executor.SendDisplayMessage("Look under the altar", "crypt_screen")
+	if err == nil || err.Error() != "MQTT client not connected" {
+		t.Errorf("expected MQTT error after validation, got %v", err)
+	}
+}
+
+// flakyPublisher fails the first failures publishes.
+type flakyPublisher struct {
+	failures int
+	calls    int
+	timeouts []time.Duration
+}
+
+func (p *flakyPublisher) IsConnected() bool { return true }
+
+func (p *flakyPublisher) PublishTimeout(topic string, payload []byte, timeout time.Duration) error {
+	p.calls++
+	p.timeouts = append(p.timeouts, timeout)
+	if p.calls <= p.failures {
+		return &mqtt.PublishTimeoutError{Topic: topic}
+	}
+	return nil
+}
+
+func TestActionExecutor_DeviceCommand_Retry(t *testing.T) {
+	registry := mqtt.NewDeviceRegistry()
+	registry.Register(&mqtt.RegisteredDevice{
+		LogicalID:     "crypt_door",
+		CommandTopic:  "devices/ctrl-001/crypt_door/commands",
+		OutputSignals: []string{"unlock"},
+	})
+	command := func(retry map[string]interface{}) map[string]interface{} {
+		cfg := map[string]interface{}{
+			"action": "device.command",
+			"params": map[string]interface{}{"device_id": "crypt_door", "signal": "unlock"},
+		}
+		if retry != nil {
+			cfg["retry"] = retry
+		}
+		return cfg
+	}
+	retry := map[string]interface{}{"attempts": float64(3), "backoff_ms": float64(100), "timeout_ms": float64(500)}
+
+	// Transient failures are retried with doubling backoff
+	pub := &flakyPublisher{failures: 2}
+	var waits []time.Duration
+	executor := &ActionExecutor{
+		publisher:      pub,
+		deviceRegistry: registry,
+		sleep:          func(d time.Duration) { waits = append(waits, d) },
+	}
+	events.Clear()
+	if err := executor.ExecuteAction("unlock_door", command(retry)); err != nil {
+		t.Fatalf("expected the command to be retried, got %v", err)
+	}
+	executor.retries.Wait()
+	if pub.calls != 3 {
+		t.Errorf("expected 3 publish attempts, got %d", pub.calls)
+	}
+	if len(waits) != 2 || waits[0] != 100*time.Millisecond || waits[1] != 200*time.Millisecond {
+		t.Errorf("expected backoff 100ms then 200ms, got %v", waits)
+	}
+	if pub.timeouts[0] != 500*time.Millisecond {
+		t.Errorf("expected 500ms publish timeout, got %v", pub.timeouts[0])
+	}
+	if countEvents("device.error", "unlock_door") != 0 {
+		t.Error("expected no device.error when a retry succeeds")
+	}
+
+	// Retries run in the background; device.error is emitted once, after
+	// the last attempt
+	pub = &flakyPublisher{failures: 5}
+	executor.publisher = pub
+	events.Clear()
+	if err := executor.ExecuteAction("unlock_door", command(retry)); err != nil {
+		t.Errorf("expected no error while retrying, got %v", err)
+	}
+	executor.retries.Wait()
+	if countEvents("device.error", "unlock_door") != 1 {
+		t.Fatalf("expected one device.error, got %d", countEvents("device.error", "unlock_door"))
+	}
+	for _, e := range events.Snapshot() {
+		if e.Name == "device.error" && !strings.Contains(e.Message, "after 3 attempts") {
+			t.Errorf("expected failure after 3 attempts, got %q", e.Message)
+		}
+	}
+
+	// Without retry config a command is tried once
+	pub = &flakyPublisher{failures: 1}
+	executor.publisher = pub
+	if err := executor.ExecuteAction("unlock_door", command(nil)); err == nil {
+		t.Error("expected failure without retry")
+	}
+	if pub.calls != 1 {
+		t.Errorf("expected a single attempt, got %d", pub.calls)
+	}
+
+	// Validation errors are not retried
+	pub = &flakyPublisher{}
+	executor.publisher = pub
+	bad := command(retry)
+	bad["params"].(map[string]interface{})["signal"] = "explode"
+	if err := executor.ExecuteAction("unlock_door", bad); err == nil {
+		t.Error("expected validation error")
+	}
+	if pub.calls != 0 {
+		t.Errorf("expected no publish for invalid command, got %d", pub.calls)
+	}
+}
+
+// orderPublisher fails the first failures publishes and records the signal
+// of every one that succeeds.
+type orderPublisher struct {
+	mu        sync.Mutex
+	failures  int
+	delivered []string
+}
+
+func (p *orderPublisher) IsConnected() bool { return true }
+
+func (p *orderPublisher) PublishTimeout(topic string, payload []byte, timeout time.Duration) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failures > 0 {
+		p.failures--
+		return &mqtt.PublishTimeoutError{Topic: topic}
+	}
+	var cmd struct{ Signal string }
+	_ = json.Unmarshal(payload, &cmd)
+	p.delivered = append(p.delivered, cmd.Signal)
+	return nil
+}
+
+func TestActionExecutor_DeviceCommand_RetrySuperseded(t *testing.T) {
+	registry := mqtt.NewDeviceRegistry()
+	registry.Register(&mqtt.RegisteredDevice{
+		LogicalID:     "crypt_door",
+		CommandTopic:  "devices/ctrl-001/crypt_door/commands",
+		OutputSignals: []string{"lock", "unlock"},
+	})
+	command := func(signal string) map[string]interface{} {
+		return map[string]interface{}{
+			"action": "device.command",
+			"params": map[string]interface{}{"device_id": "crypt_door", "signal": signal},
+			"retry":  map[string]interface{}{"attempts": float64(3), "backoff_ms": float64(100)},
+		}
+	}
+
+	pub := &orderPublisher{failures: 1}
+	waiting := make(chan struct{})
+	release := make(chan struct{})
+	executor := &ActionExecutor{
+		publisher:      pub,
+		deviceRegistry: registry,
+		sleep: func(time.Duration) {
+			close(waiting)
+			<-release
+		},
+	}
+	events.Clear()
+
+	// unlock fails and waits to be retried; lock is sent meanwhile
+	if err := executor.ExecuteAction("unlock_door", command("unlock")); err != nil {
+		t.Fatalf("expected the command to be retried, got %v", err)
+	}
+	<-waiting
+	if err := executor.ExecuteAction("lock_door", command("lock")); err != nil {
+		t.Fatalf("expected lock to be sent, got %v", err)
+	}
+	close(release)
+	executor.retries.Wait()
+
+	// The stale unlock must not land after the lock
+	if !reflect.DeepEqual(pub.delivered, []string{"lock"}) {
+		t.Errorf("expected only lock delivered, got %v", pub.delivered)
+	}
+	if countEvents("device.error", "unlock_door") != 0 {
+		t.Error("expected no device.error for a superseded command")
+	}
+}
+
+func TestRetryValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		retry interface{}
+		want  string
+	}{
+		{"not an object", "always", "must be an object"},
+		{"too many attempts", map[string]interface{}{"attempts": float64(50), "timeout_ms": float64(100)}, "attempts must be between"},
+		{"negative backoff", map[string]interface{}{"attempts": float64(2), "backoff_ms": float64(-1)}, "backoff_ms must not be negative"},
+		{"too long", map[string]interface{}{"attempts": float64(4)}, "more than 30s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := commandConfig("crypt_door", "unlock")
+			cfg["retry"] = tt.retry
+			sg := actionListSceneGraph([]interface{}{cfg})
+			err := ValidateConditions(sg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	cfg := commandConfig("crypt_door", "unlock")
+	cfg["retry"] = map[string]interface{}{"attempts": float64(5), "backoff_ms": float64(200), "timeout_ms": float64(2000)}
+	if err := ValidateConditions(actionListSceneGraph([]interface{}{cfg})); err != nil {
+		t.Errorf("expected valid retry, got %v", err)
+	}
+}
@@ -0,0 +1,470 @@
+package orchestrator
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GraphAnalysis is the puzzle dependency structure of every scene in a graph.
+type GraphAnalysis struct {
+	Scenes []SceneAnalysis `json:"scenes"`
+}
+
+// SceneAnalysis describes how a scene's nodes wait on each other.
+//
+// The critical path is the longest chain of waits from the entry node to the
+// end of the scene. With historical solve times each puzzle costs its median
+// (puzzles without history cost the average median) and timers their
+// duration, so CriticalPathMs estimates the shortest possible game. Without
+// history every puzzle costs one step. Edges that close a cycle are ignored.
+type SceneAnalysis struct {
+	SceneID             string           `json:"scene_id"`
+	Nodes               []NodeDependency `json:"nodes"`
+	CriticalPath        []string         `json:"critical_path"`
+	CriticalPathPuzzles int              `json:"critical_path_puzzles"`
+	CriticalPathMs      int64            `json:"critical_path_ms,omitempty"` // only with historical solve times
+	Parallel            []ParallelGroup  `json:"parallel,omitempty"`
+	Cyclic              []string         `json:"cyclic,omitempty"` // nodes reached again through a cycle
+}
+
+// NodeDependency lists what one node waits on and, for puzzles, what waits on it.
+type NodeDependency struct {
+	ID        string   `json:"id"`
+	Type      string   `json:"type"`
+	DependsOn []string `json:"depends_on,omitempty"` // nodes that must end before this node can start or finish
+	Gates     []string `json:"gates,omitempty"`      // puzzles: nodes waiting on this puzzle, directly or indirectly
+	Critical  bool     `json:"critical,omitempty"`
+}
+
+// ParallelGroup lists the branches of a parallel node that can progress at
+// the same time. Each branch is a child and the nodes only it leads to.
+type ParallelGroup struct {
+	NodeID      string     `json:"node_id"`
+	MinComplete int        `json:"min_complete,omitempty"`
+	Branches    [][]string `json:"branches"`
+}
+
+// AnalyzeSceneGraph computes the dependency structure of every scene.
+// baseline may be empty.
+func AnalyzeSceneGraph(sg *SceneGraph, baseline PaceBaseline) *GraphAnalysis {
+	analysis := &GraphAnalysis{Scenes: make([]SceneAnalysis, 0, len(sg.Scenes))}
+	for i := range sg.Scenes {
+		analysis.Scenes = append(analysis.Scenes, analyzeScene(&sg.Scenes[i], baseline))
+	}
+	return analysis
+}
+
+// sceneDeps holds the wait relations of one scene.
+type sceneDeps struct {
+	scene    *Scene
+	nodes    map[string]*Node
+	order    map[string]int      // position in scene order
+	start    map[string][]string // nodes that must end before the node starts
+	finish   map[string][]string // nodes that must end before the node finishes (all of)
+	anyOf    map[string][]string // gates: one of these must end before the node finishes
+	parent   map[string]string   // parallel child -> parallel node
+	children map[string][]string // parallel node -> children
+}
+
+// nodeTiming is the earliest start and finish of a node, with the node that
+// determined each so the critical path can be walked back.
+type nodeTiming struct {
+	start, finish  int64
+	startVia       string
+	startViaParent bool   // startVia is a parallel parent that started at the same time
+	finishVia      string // node whose end determined the finish, if not the node itself
+}
+
+func analyzeScene(scene *Scene, baseline PaceBaseline) SceneAnalysis {
+	deps := buildSceneDeps(scene)
+
+	// Cost of each node: historical medians when known, else one step per puzzle
+	var fallback time.Duration
+	if len(baseline) > 0 {
+		var total time.Duration
+		for _, d := range baseline {
+			total += d
+		}
+		fallback = total / time.Duration(len(baseline))
+	}
+	cost := func(node *Node) int64 {
+		switch node.Type {
+		case "puzzle":
+			if len(baseline) == 0 {
+				return 1
+			}
+			if d, ok := baseline[node.ID]; ok {
+				return d.Milliseconds()
+			}
+			return fallback.Milliseconds()
+		case "timer":
+			if len(baseline) > 0 {
+				if ms, ok := configInt(node.Config, "duration_ms"); ok && ms > 0 {
+					return int64(ms)
+				}
+			}
+		}
+		return 0
+	}
+
+	plan := deps.schedule(cost)
+
+	result := SceneAnalysis{
+		SceneID:      scene.ID,
+		Nodes:        make([]NodeDependency, 0, len(scene.Nodes)),
+		CriticalPath: plan.criticalPath(),
+	}
+	for _, id := range result.CriticalPath {
+		if deps.nodes[id].Type == "puzzle" {
+			result.CriticalPathPuzzles++
+		}
+	}
+	if len(baseline) > 0 && plan.end != "" {
+		result.CriticalPathMs = plan.endFinish
+	}
+	critical := make(map[string]bool, len(result.CriticalPath))
+	for _, id := range result.CriticalPath {
+		critical[id] = true
+	}
+
+	// waiters[x] lists the nodes that wait on x to end
+	waiters := make(map[string][]string)
+	for _, node := range scene.Nodes {
+		for _, dep := range deps.dependsOn(node.ID) {
+			waiters[dep] = append(waiters[dep], node.ID)
+		}
+	}
+	for _, node := range scene.Nodes {
+		nd := NodeDependency{
+			ID:        node.ID,
+			Type:      node.Type,
+			DependsOn: deps.dependsOn(node.ID),
+			Critical:  critical[node.ID],
+		}
+		if node.Type == "puzzle" {
+			nd.Gates = deps.downstream(node.ID, waiters)
+		}
+		result.Nodes = append(result.Nodes, nd)
+	}
+
+	for _, node := range scene.Nodes {
+		if children := deps.children[node.ID]; len(children) > 0 {
+			group := ParallelGroup{NodeID: node.ID, Branches: deps.branches(children)}
+			group.MinComplete, _ = configInt(node.Config, "min_complete")
+			result.Parallel = append(result.Parallel, group)
+		}
+	}
+
+	for id := range plan.cyclic {
+		result.Cyclic = append(result.Cyclic, id)
+	}
+	deps.sortByOrder(result.Cyclic)
+	return result
+}
+
+// scenePlan is the earliest start and finish of every node for one set of
+// node costs, ending at the scene's latest terminal.
+type scenePlan struct {
+	timings   map[string]*nodeTiming
+	cyclic    map[string]bool
+	end       string
+	endFinish int64
+	size      int
+}
+
+// schedule times every node given the cost of each node. Dependencies reached
+// again through a cycle are ignored.
+func (d *sceneDeps) schedule(cost func(node *Node) int64) *scenePlan {
+	plan := &scenePlan{
+		timings: make(map[string]*nodeTiming),
+		cyclic:  make(map[string]bool),
+		size:    len(d.scene.Nodes),
+	}
+	timings := plan.timings
+	visiting := make(map[string]bool)
+
+	// finishOf returns false for a node reached again through a cycle
+	var finishOf func(id string) (int64, bool)
+	var timingOf func(id string) *nodeTiming
+	finishOf = func(id string) (int64, bool) {
+		if visiting[id] {
+			plan.cyclic[id] = true
+			return 0, false
+		}
+		return timingOf(id).finish, true
+	}
+	timingOf = func(id string) *nodeTiming {
+		if t, ok := timings[id]; ok {
+			return t
+		}
+		visiting[id] = true
+		t := &nodeTiming{}
+
+		// Parallel children start with their parent, which is registered
+		// below before its children are timed
+		if parent, ok := d.parent[id]; ok {
+			t.start = timingOf(parent).start
+			t.startVia = parent
+			t.startViaParent = true
+		}
+		for _, dep := range d.start[id] {
+			if f, ok := finishOf(dep); ok && (f > t.start || (f == t.start && t.startVia == "")) {
+				t.start, t.startVia, t.startViaParent = f, dep, false
+			}
+		}
+		timings[id] = t
+
+		t.finish = t.start + cost(d.nodes[id])
+		if children := d.children[id]; len(children) > 0 {
+			ends := make([]int64, 0, len(children))
+			byEnd := make(map[int64]string)
+			for _, child := range children {
+				f, ok := finishOf(child)
+				if !ok {
+					continue
+				}
+				ends = append(ends, f)
+				if _, ok := byEnd[f]; !ok {
+					byEnd[f] = child
+				}
+			}
+			if len(ends) > 0 {
+				sort.Slice(ends, func(i, j int) bool { return ends[i] < ends[j] })
+				// A quorum join finishes with its min_complete-th child
+				end := ends[len(ends)-1]
+				if k, ok := configInt(d.nodes[id].Config, "min_complete"); ok && k > 0 && k < len(ends) {
+					end = ends[k-1]
+				}
+				if end > t.finish {
+					t.finish, t.finishVia = end, byEnd[end]
+				}
+			}
+		}
+		for _, dep := range d.finish[id] {
+			if f, ok := finishOf(dep); ok && f > t.finish {
+				t.finish, t.finishVia = f, dep
+			}
+		}
+		if anyOf := d.anyOf[id]; len(anyOf) > 0 {
+			first, via := int64(-1), ""
+			for _, dep := range anyOf {
+				if f, ok := finishOf(dep); ok && (first < 0 || f < first) {
+					first, via = f, dep
+				}
+			}
+			if first > t.finish {
+				t.finish, t.finishVia = first, via
+			}
+		}
+
+		visiting[id] = false
+		return t
+	}
+
+	// The scene ends with its latest terminal, or its latest node if it has none
+	plan.endFinish = -1
+	for _, terminalsOnly := range []bool{true, false} {
+		for _, node := range d.scene.Nodes {
+			if terminalsOnly && node.Type != "terminal" {
+				continue
+			}
+			if f := timingOf(node.ID).finish; f > plan.endFinish {
+				plan.end, plan.endFinish = node.ID, f
+			}
+		}
+		if plan.end != "" {
+			break
+		}
+	}
+	return plan
+}
+
+// criticalPath walks back from the end through the nodes that determined
+// each start and finish, returning them in order.
+func (p *scenePlan) criticalPath() []string {
+	path := []string{}
+	if p.end == "" {
+		return path
+	}
+
+	var walk []string
+	cur, finishing := p.end, true
+	for steps := 0; steps <= 3*p.size; steps++ {
+		walk = append(walk, cur)
+		t := p.timings[cur]
+		if t == nil {
+			break
+		}
+		if finishing && t.finishVia != "" {
+			cur = t.finishVia
+			continue
+		}
+		if t.startVia == "" {
+			break
+		}
+		cur, finishing = t.startVia, !t.startViaParent
+	}
+
+	onPath := make(map[string]bool)
+	for i := len(walk) - 1; i >= 0; i-- {
+		if !onPath[walk[i]] {
+			onPath[walk[i]] = true
+			path = append(path, walk[i])
+		}
+	}
+	return path
+}
+
+// buildSceneDeps collects the wait relations from edges, edge conditions,
+// parallel children and gate entries.
+func buildSceneDeps(scene *Scene) *sceneDeps {
+	deps := &sceneDeps{
+		scene:    scene,
+		nodes:    make(map[string]*Node),
+		order:    make(map[string]int),
+		start:    make(map[string][]string),
+		finish:   make(map[string][]string),
+		anyOf:    make(map[string][]string),
+		parent:   make(map[string]string),
+		children: make(map[string][]string),
+	}
+	for i := range scene.Nodes {
+		deps.nodes[scene.Nodes[i].ID] = &scene.Nodes[i]
+		deps.order[scene.Nodes[i].ID] = i
+	}
+
+	add := func(m map[string][]string, id string, refs ...string) {
+		for _, ref := range refs {
+			if deps.nodes[ref] == nil || ref == id || slices.Contains(m[id], ref) {
+				continue
+			}
+			m[id] = append(m[id], ref)
+		}
+	}
+
+	for _, edge := range scene.Edges {
+		if deps.nodes[edge.To] == nil {
+			continue
+		}
+		add(deps.start, edge.To, edge.From)
+		add(deps.start, edge.To, conditionRefs(edge.Condition)...)
+	}
+
+	for _, node := range scene.Nodes {
+		switch node.Type {
+		case "parallel":
+			for _, child := range configStrings(node.Config, "children") {
+				if deps.nodes[child] == nil {
+					continue
+				}
+				deps.children[node.ID] = append(deps.children[node.ID], child)
+				deps.parent[child] = node.ID
+			}
+		case "gate":
+			gateRefs := func(entries []string) []string {
+				var refs []string
+				for _, entry := range entries {
+					if deps.nodes[entry] != nil {
+						refs = append(refs, entry)
+					} else {
+						refs = append(refs, conditionRefs(entry)...)
+					}
+				}
+				return refs
+			}
+			add(deps.finish, node.ID, gateRefs(configStrings(node.Config, "all_of"))...)
+			add(deps.anyOf, node.ID, gateRefs(configStrings(node.Config, "any_of"))...)
+		}
+	}
+	return deps
+}
+
+// conditionRefs returns the nodes a condition checks, ignoring syntax errors
+// (ValidateConditions reports those at load time).
+func conditionRefs(expr string) []string {
+	cond, err := CompileCondition(strings.TrimSpace(expr))
+	if err != nil {
+		return nil
+	}
+	return cond.NodeRefs()
+}
+
+// dependsOn returns every node that id waits on, in scene order. A parallel
+// node waits on its children.
+func (d *sceneDeps) dependsOn(id string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, list := range [][]string{d.start[id], d.finish[id], d.anyOf[id], d.children[id]} {
+		for _, dep := range list {
+			if !seen[dep] {
+				seen[dep] = true
+				out = append(out, dep)
+			}
+		}
+	}
+	d.sortByOrder(out)
+	return out
+}
+
+// downstream returns the nodes that transitively wait on id, in scene order.
+func (d *sceneDeps) downstream(id string, waiters map[string][]string) []string {
+	seen := map[string]bool{id: true}
+	queue := []string{id}
+	var out []string
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, w := range waiters[cur] {
+			if !seen[w] {
+				seen[w] = true
+				out = append(out, w)
+				queue = append(queue, w)
+			}
+		}
+	}
+	d.sortByOrder(out)
+	return out
+}
+
+// branches returns each child with the nodes reachable from it over edges
+// that no sibling also reaches.
+func (d *sceneDeps) branches(children []string) [][]string {
+	reach := make([]map[string]bool, len(children))
+	count := make(map[string]int)
+	for i, child := range children {
+		reach[i] = map[string]bool{child: true}
+		queue := []string{child}
+		for len(queue) > 0 {
+			cur := queue[0]
+			queue = queue[1:]
+			for _, edge := range d.scene.Edges {
+				if edge.From == cur && d.nodes[edge.To] != nil && !reach[i][edge.To] {
+					reach[i][edge.To] = true
+					queue = append(queue, edge.To)
+				}
+			}
+		}
+		for id := range reach[i] {
+			count[id]++
+		}
+	}
+
+	branches := make([][]string, 0, len(children))
+	for i, child := range children {
+		branch := []string{child}
+		for id := range reach[i] {
+			if id != child && count[id] == 1 {
+				branch = append(branch, id)
+			}
+		}
+		d.sortByOrder(branch[1:])
+		branches = append(branches, branch)
+	}
+	return branches
+}
+
+func (d *sceneDeps) sortByOrder(ids []string) {
+	sort.SliceStable(ids, func(i, j int) bool { return d.order[ids[i]] < d.order[ids[j]] })
+}
@@ -0,0 +1,119 @@
+package orchestrator
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeSceneGraph_MVP(t *testing.T) {
+	sg, err := LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+
+	scene := AnalyzeSceneGraph(sg, nil).Scenes[0]
+	if want := []string{"start_parallel", "puzzle_scarab", "scene_complete"}; !reflect.DeepEqual(scene.CriticalPath, want) {
+		t.Errorf("expected critical path %v, got %v", want, scene.CriticalPath)
+	}
+	if scene.CriticalPathPuzzles != 1 || scene.CriticalPathMs != 0 {
+		t.Errorf("expected 1 puzzle and no duration, got %d / %d", scene.CriticalPathPuzzles, scene.CriticalPathMs)
+	}
+
+	byID := make(map[string]NodeDependency)
+	for _, n := range scene.Nodes {
+		byID[n.ID] = n
+	}
+	if want := []string{"start_parallel", "puzzle_scarab", "puzzle_tiles"}; !reflect.DeepEqual(byID["scene_complete"].DependsOn, want) {
+		t.Errorf("expected scene_complete to depend on %v, got %v", want, byID["scene_complete"].DependsOn)
+	}
+	if want := []string{"start_parallel", "scene_complete"}; !reflect.DeepEqual(byID["puzzle_tiles"].Gates, want) {
+		t.Errorf("expected puzzle_tiles to gate %v, got %v", want, byID["puzzle_tiles"].Gates)
+	}
+	if len(scene.Parallel) != 1 || len(scene.Parallel[0].Branches) != 3 {
+		t.Errorf("expected 3 parallel branches, got %+v", scene.Parallel)
+	}
+
+	// Historical solve times move the critical path to the slower puzzle
+	scene = AnalyzeSceneGraph(sg, PaceBaseline{
+		"puzzle_scarab": 5 * time.Minute,
+		"puzzle_tiles":  10 * time.Minute,
+	}).Scenes[0]
+	if want := []string{"start_parallel", "puzzle_tiles", "scene_complete"}; !reflect.DeepEqual(scene.CriticalPath, want) {
+		t.Errorf("expected critical path %v, got %v", want, scene.CriticalPath)
+	}
+	if scene.CriticalPathMs != (10 * time.Minute).Milliseconds() {
+		t.Errorf("expected 10 minutes, got %dms", scene.CriticalPathMs)
+	}
+}
+
+func TestAnalyzeSceneGraph_ChainAndGate(t *testing.T) {
+	sg := &SceneGraph{Version: 1, Scenes: []Scene{{
+		ID:    "scene_vault",
+		Entry: "start",
+		Nodes: []Node{
+			{ID: "start", Type: "parallel", Config: map[string]interface{}{"children": []interface{}{"puzzle_a", "puzzle_c"}}},
+			{ID: "puzzle_a", Type: "puzzle", Config: map[string]interface{}{}},
+			{ID: "puzzle_b", Type: "puzzle", Config: map[string]interface{}{}},
+			{ID: "puzzle_c", Type: "puzzle", Config: map[string]interface{}{}},
+			{ID: "door", Type: "gate", Config: map[string]interface{}{"all_of": []interface{}{"puzzle_b", "puzzle_c.resolved"}}},
+			{ID: "end", Type: "terminal", Config: map[string]interface{}{}},
+		},
+		Edges: []Edge{
+			{From: "puzzle_a", To: "puzzle_b"},
+			{From: "start", To: "door"},
+			{From: "door", To: "end"},
+		},
+	}}}
+
+	scene := AnalyzeSceneGraph(sg, nil).Scenes[0]
+	if want := []string{"start", "puzzle_a", "puzzle_b", "door", "end"}; !reflect.DeepEqual(scene.CriticalPath, want) {
+		t.Errorf("expected critical path %v, got %v", want, scene.CriticalPath)
+	}
+	if scene.CriticalPathPuzzles != 2 {
+		t.Errorf("expected 2 puzzles on the critical path, got %d", scene.CriticalPathPuzzles)
+	}
+	if want := [][]string{{"puzzle_a", "puzzle_b"}, {"puzzle_c"}}; !reflect.DeepEqual(scene.Parallel[0].Branches, want) {
+		t.Errorf("expected branches %v, got %v", want, scene.Parallel[0].Branches)
+	}
+	for _, n := range scene.Nodes {
+		switch n.ID {
+		case "door":
+			if want := []string{"start", "puzzle_b", "puzzle_c"}; !reflect.DeepEqual(n.DependsOn, want) {
+				t.Errorf("expected door to depend on %v, got %v", want, n.DependsOn)
+			}
+		case "puzzle_c":
+			if n.Critical {
+				t.Error("expected puzzle_c off the critical path")
+			}
+			if want := []string{"start", "door", "end"}; !reflect.DeepEqual(n.Gates, want) {
+				t.Errorf("expected puzzle_c to gate %v, got %v", want, n.Gates)
+			}
+		}
+	}
+}
+
+func TestAnalyzeSceneGraph_Cycle(t *testing.T) {
+	sg := &SceneGraph{Version: 1, Scenes: []Scene{{
+		ID:    "scene_loop",
+		Entry: "puzzle_a",
+		Nodes: []Node{
+			{ID: "puzzle_a", Type: "puzzle", Config: map[string]interface{}{}},
+			{ID: "puzzle_b", Type: "puzzle", Config: map[string]interface{}{}},
+			{ID: "end", Type: "terminal", Config: map[string]interface{}{}},
+		},
+		Edges: []Edge{
+			{From: "puzzle_a", To: "puzzle_b"},
+			{From: "puzzle_b", To: "puzzle_a", Condition: "puzzle_b.failed"},
+			{From: "puzzle_b", To: "end", Condition: "puzzle_b.resolved"},
+		},
+	}}}
+
+	scene := AnalyzeSceneGraph(sg, nil).Scenes[0]
+	if len(scene.Cyclic) == 0 {
+		t.Error("expected the retry edge to be reported as a cycle")
+	}
+	if want := []string{"puzzle_a", "puzzle_b", "end"}; !reflect.DeepEqual(scene.CriticalPath, want) {
+		t.Errorf("expected critical path %v, got %v", want, scene.CriticalPath)
+	}
+}
@@ -0,0 +1,291 @@
+package orchestrator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// announcePollInterval is how often the game clock is checked for announcement marks.
+const announcePollInterval = time.Second
+
+// announceNodePrefix marks device commands issued for announcements.
+const announceNodePrefix = "announce:"
+
+// defaultAnnounceTimeout bounds HTTP announcement requests.
+const defaultAnnounceTimeout = 5 * time.Second
+
+// Announcement is a voice line sent to the announcement channel.
+type Announcement struct {
+	Rule  string `json:"rule"`
+	Line  string `json:"line"`
+	Text  string `json:"text,omitempty"`
+	File  string `json:"file,omitempty"`
+	Voice string `json:"voice,omitempty"`
+}
+
+// AnnouncementChannel delivers announcements to an audio or TTS device.
+type AnnouncementChannel interface {
+	Announce(a Announcement) error
+}
+
+// GameClockSource reports the session countdown (implemented by *Runtime).
+type GameClockSource interface {
+	GameClock() ClockState
+}
+
+// NewAnnouncementChannel builds the channel configured in announcements.yaml.
+// MQTT device commands go through executor; raw topics and HTTP are sent directly.
+func NewAnnouncementChannel(cfg config.AnnouncementChannel, publisher BridgePublisher, executor ActionExecutorInterface) AnnouncementChannel {
+	switch {
+	case cfg.Type == "http":
+		timeout := defaultAnnounceTimeout
+		if cfg.TimeoutMs > 0 {
+			timeout = time.Duration(cfg.TimeoutMs) * time.Millisecond
+		}
+		return &httpAnnouncementChannel{url: cfg.URL, client: &http.Client{Timeout: timeout}}
+	case cfg.Topic != "":
+		return &topicAnnouncementChannel{topic: cfg.Topic, publisher: publisher}
+	default:
+		return &deviceAnnouncementChannel{deviceID: cfg.DeviceID, signal: cfg.Signal, executor: executor}
+	}
+}
+
+// deviceAnnouncementChannel sends announcements as logical device commands.
+type deviceAnnouncementChannel struct {
+	deviceID string
+	signal   string
+	executor ActionExecutorInterface
+}
+
+func (c *deviceAnnouncementChannel) Announce(a Announcement) error {
+	if c.executor == nil {
+		return fmt.Errorf("action executor not available")
+	}
+	// The executor validates the command and reports its own failures
+	_ = c.executor.ExecuteAction(announceNodePrefix+a.Rule, map[string]interface{}{
+		"action": "device.command",
+		"params": map[string]interface{}{
+			"device_id": c.deviceID,
+			"signal":    c.signal,
+			"payload":   a,
+		},
+	})
+	return nil
+}
+
+// topicAnnouncementChannel publishes announcements as JSON to a raw MQTT topic.
+type topicAnnouncementChannel struct {
+	topic     string
+	publisher BridgePublisher
+}
+
+func (c *topicAnnouncementChannel) Announce(a Announcement) error {
+	payload, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("failed to marshal announcement: %w", err)
+	}
+	if c.publisher == nil || !c.publisher.IsConnected() {
+		return fmt.Errorf("MQTT client not connected")
+	}
+	if err := c.publisher.Publish(c.topic, payload); err != nil {
+		return fmt.Errorf("MQTT publish failed: %w", err)
+	}
+	return nil
+}
+
+// httpAnnouncementChannel POSTs announcements as JSON to a TTS or audio service.
+type httpAnnouncementChannel struct {
+	url    string
+	client *http.Client
+}
+
+func (c *httpAnnouncementChannel) Announce(a Announcement) error {
+	payload, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("failed to marshal announcement: %w", err)
+	}
+	resp, err := c.client.Post(c.url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("HTTP announce failed: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP announce failed: %s", resp.Status)
+	}
+	return nil
+}
+
+// Announcer plays voice lines from announcements.yaml when the game clock
+// reaches a mark ("30 minutes remaining") or a matching event is emitted, so
+// routine announcements do not need edges in the scene graph.
+// Events and clock marks are handled by one worker, off the emit path.
+type Announcer struct {
+	rules   []config.AnnouncementRule
+	lines   map[string]config.VoiceLine
+	channel AnnouncementChannel
+	clock   GameClockSource
+
+	// armed holds clock marks the countdown is above, so each mark fires once
+	// when crossed and again only if time is added back. Worker goroutine only.
+	armed map[string]bool
+
+	queue   chan events.Event
+	remove  func()
+	dropped atomic.Bool
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewAnnouncer creates an announcer for the given rules, channel and game clock.
+func NewAnnouncer(cfg *config.AnnouncementsConfig, channel AnnouncementChannel, clock GameClockSource) *Announcer {
+	rules := make([]config.AnnouncementRule, len(cfg.Rules))
+	for i, rule := range cfg.Rules {
+		if rule.When != nil {
+			when := currentMatch(*rule.When, "announcements.yaml")
+			rule.When = &when
+		}
+		rules[i] = rule
+	}
+	return &Announcer{
+		rules:   rules,
+		lines:   cfg.Lines,
+		channel: channel,
+		clock:   clock,
+		armed:   make(map[string]bool),
+		queue:   make(chan events.Event, bridgeQueueSize),
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// Start subscribes to emitted events and polls the game clock in the background.
+func (a *Announcer) Start() {
+	a.remove = events.AddListener(func(e events.Event) {
+		select {
+		case a.queue <- e:
+		default:
+			// Never block the emitter; note the first overflow only
+			if a.dropped.CompareAndSwap(false, true) {
+				log.Printf("[announce] queue full, dropping events")
+			}
+		}
+	})
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(announcePollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-a.stopCh:
+				return
+			case e := <-a.queue:
+				a.Handle(e)
+			case <-ticker.C:
+				if a.clock != nil {
+					a.CheckClock(a.clock.GameClock())
+				}
+			}
+		}
+	}()
+}
+
+// Stop unsubscribes and waits for the worker to exit.
+func (a *Announcer) Stop() {
+	if a.remove != nil {
+		a.remove()
+	}
+	close(a.stopCh)
+	a.wg.Wait()
+}
+
+// Handle fires every event rule matching the event.
+func (a *Announcer) Handle(e events.Event) {
+	// Ignore errors caused by announcements so a failing channel cannot feed itself
+	if _, ok := e.Fields["announcement"]; ok {
+		return
+	}
+	if nodeID, _ := e.Fields["node_id"].(string); strings.HasPrefix(nodeID, announceNodePrefix) {
+		return
+	}
+
+	for _, rule := range a.rules {
+		if rule.When != nil && bridgeMatches(*rule.When, e) {
+			a.fire(rule)
+		}
+	}
+}
+
+// CheckClock fires the clock mark the countdown has just crossed. If several
+// marks are crossed at once (time removed), only the lowest is announced.
+func (a *Announcer) CheckClock(state ClockState) {
+	if state == (ClockState{}) {
+		// No session: marks re-arm when the next countdown starts above them
+		a.armed = make(map[string]bool)
+		return
+	}
+
+	var crossed *config.AnnouncementRule
+	for i := range a.rules {
+		rule := &a.rules[i]
+		if rule.RemainingSec <= 0 {
+			continue
+		}
+		mark := int64(rule.RemainingSec) * 1000
+		if state.RemainingMs > mark {
+			a.armed[rule.Name] = true
+			continue
+		}
+		if !a.armed[rule.Name] {
+			continue
+		}
+		delete(a.armed, rule.Name)
+		if crossed == nil || rule.RemainingSec < crossed.RemainingSec {
+			crossed = rule
+		}
+	}
+	if crossed != nil {
+		a.fire(*crossed)
+	}
+}
+
+// fire sends a rule's voice line. Failures are reported as device.error.
+func (a *Announcer) fire(rule config.AnnouncementRule) {
+	line := a.lines[rule.Line]
+	err := a.channel.Announce(Announcement{
+		Rule:  rule.Name,
+		Line:  rule.Line,
+		Text:  line.Text,
+		File:  line.File,
+		Voice: line.Voice,
+	})
+	if err != nil {
+		events.Emit("error", "device.error", err.Error(), map[string]interface{}{
+			"announcement": rule.Name,
+			"error":        err.Error(),
+		})
+	}
+}
+
+// ValidateAnnouncementConditions compiles each event rule's condition so syntax errors fail at startup.
+func ValidateAnnouncementConditions(cfg *config.AnnouncementsConfig) error {
+	for _, rule := range cfg.Rules {
+		if rule.When == nil {
+			continue
+		}
+		if _, err := CompileCondition(rule.When.Condition); err != nil {
+			return fmt.Errorf("announcement rule %s: %w", rule.Name, err)
+		}
+	}
+	return nil
+}
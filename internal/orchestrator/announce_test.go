@@ -0,0 +1,131 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// recordingChannel collects announcements.
+type recordingChannel struct {
+	got []Announcement
+}
+
+func (c *recordingChannel) Announce(a Announcement) error {
+	c.got = append(c.got, a)
+	return nil
+}
+
+func announceConfig() *config.AnnouncementsConfig {
+	return &config.AnnouncementsConfig{
+		Version: 1,
+		Lines: map[string]config.VoiceLine{
+			"thirty": {Text: "Thirty minutes remaining."},
+			"ten":    {Text: "Ten minutes remaining."},
+			"scarab": {File: "scarab.wav"},
+		},
+		Rules: []config.AnnouncementRule{
+			{Name: "thirty_minutes", RemainingSec: 1800, Line: "thirty"},
+			{Name: "ten_minutes", RemainingSec: 600, Line: "ten"},
+			{Name: "scarab", When: &config.BridgeMatch{Event: "puzzle.solved", Fields: map[string]interface{}{"node_id": "puzzle_scarab"}}, Line: "scarab"},
+		},
+	}
+}
+
+func remaining(min int64) ClockState {
+	return ClockState{Running: true, DurationMs: 3600000, RemainingMs: min * 60000}
+}
+
+func TestAnnouncerClockMarks(t *testing.T) {
+	ch := &recordingChannel{}
+	a := NewAnnouncer(announceConfig(), ch, nil)
+
+	a.CheckClock(remaining(60))
+	a.CheckClock(remaining(31))
+	if len(ch.got) != 0 {
+		t.Fatalf("expected no announcements yet, got %+v", ch.got)
+	}
+
+	a.CheckClock(remaining(30))
+	a.CheckClock(remaining(29))
+	if len(ch.got) != 1 || ch.got[0].Rule != "thirty_minutes" || ch.got[0].Text != "Thirty minutes remaining." {
+		t.Fatalf("expected thirty_minutes once, got %+v", ch.got)
+	}
+
+	// Time added back above a mark re-arms it
+	a.CheckClock(remaining(32))
+	a.CheckClock(remaining(28))
+	if len(ch.got) != 2 {
+		t.Fatalf("expected thirty_minutes again after re-arm, got %+v", ch.got)
+	}
+
+	// Crossing several marks at once only announces the lowest
+	a.CheckClock(remaining(35))
+	a.CheckClock(remaining(5))
+	if len(ch.got) != 3 || ch.got[2].Rule != "ten_minutes" {
+		t.Fatalf("expected only ten_minutes, got %+v", ch.got)
+	}
+
+	// A countdown first seen below a mark (e.g. after restart) does not announce it
+	a.CheckClock(ClockState{})
+	a.CheckClock(remaining(20))
+	a.CheckClock(remaining(9))
+	if len(ch.got) != 4 || ch.got[3].Rule != "ten_minutes" {
+		t.Errorf("expected only ten_minutes after restart, got %+v", ch.got)
+	}
+}
+
+func TestAnnouncerEventRules(t *testing.T) {
+	ch := &recordingChannel{}
+	a := NewAnnouncer(announceConfig(), ch, nil)
+
+	a.Handle(events.Event{Name: "puzzle.solved", Fields: map[string]interface{}{"node_id": "puzzle_tiles"}})
+	a.Handle(events.Event{Name: "puzzle.solved", Fields: map[string]interface{}{"node_id": "puzzle_scarab"}})
+	if len(ch.got) != 1 || ch.got[0].File != "scarab.wav" {
+		t.Errorf("expected scarab announcement, got %+v", ch.got)
+	}
+}
+
+func TestAnnouncementChannels(t *testing.T) {
+	executor := &replyingExecutor{}
+	device := NewAnnouncementChannel(config.AnnouncementChannel{Type: "mqtt", DeviceID: "speaker", Signal: "speak"}, nil, executor)
+	if err := device.Announce(Announcement{Rule: "r", Line: "l", Text: "hi"}); err != nil {
+		t.Fatalf("device announce failed: %v", err)
+	}
+	if len(executor.commands) != 1 || executor.commands[0] != "speaker:speak" {
+		t.Errorf("expected speaker command, got %v", executor.commands)
+	}
+
+	publisher := NewMockMQTTClient()
+	topic := NewAnnouncementChannel(config.AnnouncementChannel{Type: "mqtt", Topic: "room/tts"}, publisher, nil)
+	if err := topic.Announce(Announcement{Rule: "r", Line: "l", Text: "hi"}); err != nil {
+		t.Fatalf("topic announce failed: %v", err)
+	}
+	if published := publisher.GetPublished(); len(published) != 1 || published[0].Topic != "room/tts" {
+		t.Errorf("expected publish to room/tts, got %+v", published)
+	}
+
+	var received Announcement
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		if received.Line == "broken" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	web := NewAnnouncementChannel(config.AnnouncementChannel{Type: "http", URL: srv.URL}, nil, nil)
+	if err := web.Announce(Announcement{Rule: "r", Line: "l", Text: "hi"}); err != nil {
+		t.Fatalf("http announce failed: %v", err)
+	}
+	if received.Text != "hi" {
+		t.Errorf("expected posted announcement, got %+v", received)
+	}
+	if err := web.Announce(Announcement{Rule: "r", Line: "broken"}); err == nil {
+		t.Error("expected error for non-2xx response")
+	}
+}
@@ -0,0 +1,58 @@
+package orchestrator
+
+import "fmt"
+
+// requestApproval announces that an operator node is waiting. Operator nodes
+// pause flow until a gamemaster approves them (see design/scene-graph/schema.md).
+func (r *Runtime) requestApproval(node *Node) {
+	fields := map[string]interface{}{"node_id": node.ID}
+	if prompt, ok := node.Config["prompt"].(string); ok && prompt != "" {
+		fields["prompt"] = prompt
+	}
+	r.emitEvent("operator.approval_requested", fields)
+}
+
+// ApproveNode releases an operator node that is waiting for approval.
+func (r *Runtime) ApproveNode(nodeID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.activeScene == nil {
+		return fmt.Errorf("no active scene")
+	}
+
+	node := r.findNode(nodeID)
+	if node == nil {
+		return fmt.Errorf("node not found: %s", nodeID)
+	}
+	if node.Type != "operator" {
+		return fmt.Errorf("node %s is not an operator node", nodeID)
+	}
+	if status := r.nodeStates[nodeID]; status.State != NodeStateActive {
+		return fmt.Errorf("node %s is not awaiting approval", nodeID)
+	}
+
+	r.emitEvent("operator.approved", map[string]interface{}{"node_id": nodeID})
+	r.completeNode(nodeID)
+	return nil
+}
+
+// PendingApprovals returns the IDs of operator nodes awaiting approval.
+func (r *Runtime) PendingApprovals() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending := []string{}
+	if r.activeScene == nil {
+		return pending
+	}
+	for _, node := range r.activeScene.Nodes {
+		if node.Type != "operator" {
+			continue
+		}
+		if status, ok := r.nodeStates[node.ID]; ok && status.State == NodeStateActive {
+			pending = append(pending, node.ID)
+		}
+	}
+	return pending
+}
@@ -0,0 +1,80 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// approvalSceneGraph builds a scene where fog waits for operator approval.
+func approvalSceneGraph() *SceneGraph {
+	return sceneGraph(Scene{
+		ID:    "scene_fog",
+		Entry: "confirm_fog",
+		Nodes: []Node{
+			{ID: "confirm_fog", Type: "operator", Config: map[string]interface{}{"prompt": "Players clear of the fog machine?"}},
+			{ID: "fog_on", Type: "action", Config: map[string]interface{}{"action": "fog.on"}},
+			{ID: "done", Type: "terminal", Config: map[string]interface{}{}},
+		},
+		Edges: []Edge{
+			{From: "confirm_fog", To: "fog_on"},
+			{From: "fog_on", To: "done"},
+		},
+	})
+}
+
+func TestOperatorNodeBlocksUntilApproved(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(approvalSceneGraph())
+	if err := rt.StartScene("scene_fog"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+
+	if rt.GetNodeState("confirm_fog") != NodeStateActive {
+		t.Fatalf("expected operator node to wait, got %s", rt.GetNodeState("confirm_fog"))
+	}
+	if rt.GetNodeState("fog_on") != NodeStateIdle {
+		t.Fatalf("expected downstream action to wait, got %s", rt.GetNodeState("fog_on"))
+	}
+	var prompted bool
+	for _, e := range events.Snapshot() {
+		if e.Name == "operator.approval_requested" && e.Fields["prompt"] == "Players clear of the fog machine?" {
+			prompted = true
+		}
+	}
+	if !prompted {
+		t.Error("expected operator.approval_requested with prompt")
+	}
+	if pending := rt.PendingApprovals(); len(pending) != 1 || pending[0] != "confirm_fog" {
+		t.Errorf("expected confirm_fog pending, got %v", pending)
+	}
+
+	if err := rt.ApproveNode("fog_on"); err == nil {
+		t.Error("expected error approving a non-operator node")
+	}
+
+	if err := rt.ApproveNode("confirm_fog"); err != nil {
+		t.Fatalf("failed to approve: %v", err)
+	}
+	if countEvents("operator.approved", "confirm_fog") != 1 {
+		t.Errorf("expected one operator.approved event, got %d", countEvents("operator.approved", "confirm_fog"))
+	}
+	if rt.GetNodeState("done") != NodeStateCompleted {
+		t.Errorf("expected flow to reach terminal, got %s", rt.GetNodeState("done"))
+	}
+	if len(rt.PendingApprovals()) != 0 {
+		t.Errorf("expected no pending approvals, got %v", rt.PendingApprovals())
+	}
+
+	if err := rt.ApproveNode("confirm_fog"); err == nil {
+		t.Error("expected error approving twice")
+	}
+
+	// Reset asks for approval again
+	if err := rt.ResetNode("confirm_fog"); err != nil {
+		t.Fatalf("failed to reset: %v", err)
+	}
+	if countEvents("operator.approval_requested", "confirm_fog") != 2 {
+		t.Errorf("expected approval to be requested again, got %d", countEvents("operator.approval_requested", "confirm_fog"))
+	}
+}
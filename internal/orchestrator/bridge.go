@@ -0,0 +1,199 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// bridgeQueueSize bounds events waiting for bridge rule evaluation.
+const bridgeQueueSize = 256
+
+// bridgeNodePrefix marks device commands issued by bridge rules.
+const bridgeNodePrefix = "bridge:"
+
+// BridgePublisher publishes raw MQTT messages (implemented by *mqtt.Client).
+type BridgePublisher interface {
+	IsConnected() bool
+	Publish(topic string, payload []byte) error
+}
+
+// Bridge publishes MQTT messages for emitted events that match bridges.yaml rules,
+// so simple reactive wiring does not need action nodes in the graph.
+// Rules are evaluated off the emit path, in emission order.
+type Bridge struct {
+	rules     []config.BridgeRule
+	publisher BridgePublisher
+	executor  ActionExecutorInterface
+
+	queue   chan events.Event
+	remove  func()
+	dropped atomic.Bool
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewBridge creates a bridge. Device commands go through executor; raw topics through publisher.
+func NewBridge(cfg *config.BridgesConfig, publisher BridgePublisher, executor ActionExecutorInterface) *Bridge {
+	rules := make([]config.BridgeRule, len(cfg.Rules))
+	for i, rule := range cfg.Rules {
+		rule.When = currentMatch(rule.When, "bridges.yaml")
+		rules[i] = rule
+	}
+	return &Bridge{
+		rules:     rules,
+		publisher: publisher,
+		executor:  executor,
+		queue:     make(chan events.Event, bridgeQueueSize),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start subscribes to emitted events and evaluates rules in the background.
+func (b *Bridge) Start() {
+	b.remove = events.AddListener(func(e events.Event) {
+		select {
+		case b.queue <- e:
+		default:
+			// Never block the emitter; note the first overflow only
+			if b.dropped.CompareAndSwap(false, true) {
+				log.Printf("[bridge] queue full, dropping events")
+			}
+		}
+	})
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for {
+			select {
+			case <-b.stopCh:
+				return
+			case e := <-b.queue:
+				b.Handle(e)
+			}
+		}
+	}()
+}
+
+// Stop unsubscribes and waits for the worker to exit.
+func (b *Bridge) Stop() {
+	if b.remove != nil {
+		b.remove()
+	}
+	close(b.stopCh)
+	b.wg.Wait()
+}
+
+// Handle fires every rule matching the event.
+func (b *Bridge) Handle(e events.Event) {
+	// Ignore errors caused by bridge rules so a failing rule cannot feed itself
+	if _, ok := e.Fields["bridge"]; ok {
+		return
+	}
+	if nodeID, _ := e.Fields["node_id"].(string); strings.HasPrefix(nodeID, bridgeNodePrefix) {
+		return
+	}
+
+	for _, rule := range b.rules {
+		if bridgeMatches(rule.When, e) {
+			b.fire(rule)
+		}
+	}
+}
+
+// ValidateBridgeConditions compiles each rule's condition so syntax errors fail at startup.
+func ValidateBridgeConditions(cfg *config.BridgesConfig) error {
+	for _, rule := range cfg.Rules {
+		if _, err := CompileCondition(rule.When.Condition); err != nil {
+			return fmt.Errorf("bridge rule %s: %w", rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// currentMatch returns when with a legacy event name replaced by its
+// current name, reporting the alias as used by source.
+func currentMatch(when config.BridgeMatch, source string) config.BridgeMatch {
+	when.Event = events.Translate(when.Event, source)
+	return when
+}
+
+// bridgeMatches reports whether an event satisfies a rule's event name, fields and condition.
+func bridgeMatches(when config.BridgeMatch, e events.Event) bool {
+	if e.Name != when.Event {
+		return false
+	}
+	for field, want := range when.Fields {
+		if !matchValue(getNestedField(e.Fields, field), fmt.Sprint(want)) {
+			return false
+		}
+	}
+	if when.Condition != "" {
+		ctx := &EvalContext{Event: &Event{Name: e.Name, Fields: e.Fields}}
+		if !EvalCondition(when.Condition, ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+// fire publishes a rule's message. Failures are reported as device.error.
+func (b *Bridge) fire(rule config.BridgeRule) {
+	pub := rule.Publish
+
+	if pub.DeviceID != "" {
+		if b.executor == nil {
+			b.emitError(rule, "action executor not available")
+			return
+		}
+		// The executor validates the command and reports its own failures
+		_ = b.executor.ExecuteAction(bridgeNodePrefix+rule.Name, map[string]interface{}{
+			"action": "device.command",
+			"params": map[string]interface{}{
+				"device_id": pub.DeviceID,
+				"signal":    pub.Signal,
+				"payload":   pub.Payload,
+			},
+		})
+		return
+	}
+
+	var payload []byte
+	if s, ok := pub.Payload.(string); ok {
+		payload = []byte(s)
+	} else {
+		data, err := json.Marshal(pub.Payload)
+		if err != nil {
+			b.emitError(rule, fmt.Sprintf("failed to marshal payload: %v", err))
+			return
+		}
+		payload = data
+	}
+
+	if b.publisher == nil || !b.publisher.IsConnected() {
+		b.emitError(rule, "MQTT client not connected")
+		return
+	}
+	if err := b.publisher.Publish(pub.Topic, payload); err != nil {
+		b.emitError(rule, fmt.Sprintf("MQTT publish failed: %v", err))
+	}
+}
+
+// emitError emits a device.error event for a failed bridge rule.
+func (b *Bridge) emitError(rule config.BridgeRule, msg string) {
+	fields := map[string]interface{}{
+		"bridge": rule.Name,
+		"error":  msg,
+	}
+	if rule.Publish.Topic != "" {
+		fields["topic"] = rule.Publish.Topic
+	}
+	events.Emit("error", "device.error", msg, fields)
+}
@@ -0,0 +1,85 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+func bridgeConfig() *config.BridgesConfig {
+	return &config.BridgesConfig{
+		Version: 1,
+		Rules: []config.BridgeRule{
+			{
+				Name: "scarab_glow",
+				When: config.BridgeMatch{Event: "puzzle.solved", Fields: map[string]interface{}{"node_id": "puzzle_scarab"}},
+				Publish: config.BridgePublish{
+					DeviceID: "scarab_light", Signal: "flash",
+				},
+			},
+			{
+				Name: "lobby_sign",
+				When: config.BridgeMatch{Event: "scene.completed", Condition: "scene_id == 'scene_a' || scene_id == 'scene_b'"},
+				Publish: config.BridgePublish{
+					Topic: "lobby/sign", Payload: map[string]interface{}{"text": "Escaped!"},
+				},
+			},
+		},
+	}
+}
+
+func TestBridgeRules(t *testing.T) {
+	executor := &replyingExecutor{}
+	publisher := NewMockMQTTClient()
+	bridge := NewBridge(bridgeConfig(), publisher, executor)
+
+	bridge.Handle(events.Event{Name: "puzzle.solved", Fields: map[string]interface{}{"node_id": "puzzle_tiles"}})
+	bridge.Handle(events.Event{Name: "puzzle.solved", Fields: map[string]interface{}{"node_id": "puzzle_scarab"}})
+	if len(executor.commands) != 1 || executor.commands[0] != "scarab_light:flash" {
+		t.Errorf("expected one scarab_light command, got %v", executor.commands)
+	}
+
+	bridge.Handle(events.Event{Name: "scene.completed", Fields: map[string]interface{}{"scene_id": "scene_b"}})
+	published := publisher.GetPublished()
+	if len(published) != 1 || published[0].Topic != "lobby/sign" || string(published[0].Payload) != `{"text":"Escaped!"}` {
+		t.Errorf("expected lobby sign message, got %+v", published)
+	}
+
+	// Bridge failures never re-trigger rules
+	publisher.SetConnected(false)
+	events.Clear()
+	bridge.Handle(events.Event{Name: "scene.completed", Fields: map[string]interface{}{"scene_id": "scene_a"}})
+	snapshot := events.Snapshot()
+	if len(snapshot) != 1 || snapshot[0].Name != "device.error" || snapshot[0].Fields["bridge"] != "lobby_sign" {
+		t.Fatalf("expected one device.error for the bridge, got %+v", snapshot)
+	}
+	bridge.Handle(snapshot[0])
+	if len(events.Snapshot()) != 1 {
+		t.Error("expected bridge errors to be ignored by the bridge")
+	}
+}
+
+func TestBridgeListensToEmitter(t *testing.T) {
+	executor := &replyingExecutor{}
+	bridge := NewBridge(bridgeConfig(), NewMockMQTTClient(), executor)
+	bridge.Start()
+	defer bridge.Stop()
+
+	events.Emit("info", "puzzle.solved", "", map[string]interface{}{"node_id": "puzzle_scarab"})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		executor.mu.Lock()
+		n := len(executor.commands)
+		executor.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for bridge command")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,262 @@
+package orchestrator
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/mqtt"
+)
+
+// Chaos mode plays a scripted session against the template room while
+// injecting faults at random, then asserts the session still completed
+// correctly. Faults:
+//   - MQTT disconnects: device messages are lost and command publishes fail
+//     until the broker comes back
+//   - delayed persistence: a slow event sink stalls the emit path the way a
+//     slow Postgres append does
+//   - duplicate device messages
+//   - controller restarts: the controller registers again mid-session
+//
+// Each seed is a different, reproducible run. To soak:
+//
+//	go test ./internal/orchestrator -run Chaos -chaos.seed=1 -chaos.runs=500
+var (
+	chaosSeed = flag.Int64("chaos.seed", 1, "first chaos seed")
+	chaosRuns = flag.Int("chaos.runs", 20, "number of chaos seeds to run")
+)
+
+// Fault probabilities per scripted step.
+const (
+	chaosDisconnectRate = 0.3
+	chaosDelayRate      = 0.3
+	chaosDuplicateRate  = 0.4
+	chaosRestartRate    = 0.2
+	chaosMaxDelay       = 2 * time.Millisecond
+)
+
+// chaosNet is the broker as seen by the room: it can be down for a number of
+// ticks, where each publish attempt or retry backoff is one tick.
+type chaosNet struct {
+	mu        sync.Mutex
+	downTicks int
+	published []PublishedMessage
+	dropped   int
+}
+
+func (n *chaosNet) disconnect(ticks int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.downTicks = ticks
+}
+
+// tick advances time by one step, reconnecting when the outage ends.
+func (n *chaosNet) tick() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.downTicks > 0 {
+		n.downTicks--
+	}
+}
+
+func (n *chaosNet) IsConnected() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.downTicks == 0
+}
+
+func (n *chaosNet) PublishTimeout(topic string, payload []byte, timeout time.Duration) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.downTicks > 0 {
+		n.downTicks--
+		return &mqtt.PublishTimeoutError{Topic: topic}
+	}
+	n.published = append(n.published, PublishedMessage{Topic: topic, Payload: payload})
+	return nil
+}
+
+// deliver sends a device message through the broker, as the device
+// subscriber does. Returns false if the message was lost to an outage.
+func (n *chaosNet) deliver(rt *Runtime, fields map[string]interface{}) bool {
+	if !n.IsConnected() {
+		n.mu.Lock()
+		n.dropped++
+		n.mu.Unlock()
+		n.tick()
+		return false
+	}
+	events.Emit("info", "device.input", "", fields)
+	rt.InjectEvent("device.input", fields)
+	return true
+}
+
+// chaosSink stalls every emitted event while a delay fault is active and
+// records sequence numbers to check ordering.
+type chaosSink struct {
+	mu      sync.Mutex
+	rng     *rand.Rand
+	delayed int // events left to delay
+	seqs    []uint64
+}
+
+func (s *chaosSink) listen(e events.Event) {
+	s.mu.Lock()
+	s.seqs = append(s.seqs, e.Seq)
+	var d time.Duration
+	if s.delayed > 0 {
+		s.delayed--
+		d = time.Duration(s.rng.Int63n(int64(chaosMaxDelay)))
+	}
+	s.mu.Unlock()
+	time.Sleep(d)
+}
+
+func chaosRegistration(uptimeMs int64) *mqtt.RegistrationPayload {
+	return &mqtt.RegistrationPayload{
+		Version:    1,
+		Controller: mqtt.ControllerInfo{ID: "ctrl-001", Type: "teensy", UptimeMS: uptimeMs, HeartbeatSec: 5},
+		Devices: []mqtt.DeviceRegistration{{
+			LogicalID:    "crypt_door",
+			Type:         "door",
+			Capabilities: []string{"open", "close"},
+			Signals:      mqtt.DeviceSignals{Inputs: []string{"door_closed"}, Outputs: []string{"unlock"}},
+			Topics: mqtt.DeviceTopics{
+				Publish:   "devices/ctrl-001/crypt_door/events",
+				Subscribe: "devices/ctrl-001/crypt_door/commands",
+			},
+		}},
+	}
+}
+
+func TestChaosSessionCompletes(t *testing.T) {
+	for seed := *chaosSeed; seed < *chaosSeed+int64(*chaosRuns); seed++ {
+		t.Run(fmt.Sprintf("seed=%d", seed), func(t *testing.T) {
+			runChaosSession(t, seed)
+		})
+	}
+}
+
+func runChaosSession(t *testing.T, seed int64) {
+	events.Clear()
+	rng := rand.New(rand.NewSource(seed))
+
+	sg, err := LoadSceneGraph("../../rooms/_template/graphs/scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load template scene graph: %v", err)
+	}
+	// Retry the unlock across short outages, as a production graph would
+	for i := range sg.Scenes[0].Subgraphs[0].Nodes {
+		node := &sg.Scenes[0].Subgraphs[0].Nodes[i]
+		if node.ID == "scarab_unlock" {
+			node.Config["retry"] = map[string]interface{}{"attempts": float64(4), "backoff_ms": float64(10)}
+		}
+	}
+
+	net := &chaosNet{}
+	sink := &chaosSink{rng: rand.New(rand.NewSource(seed))}
+	remove := events.AddListener(sink.listen)
+	defer remove()
+
+	monitor := mqtt.NewMonitor(map[string]mqtt.DeviceSpec{"crypt_door": {Type: "door", Required: true}}, 2.0)
+	rt := NewRuntime(sg)
+	rt.SetActionExecutor(&ActionExecutor{
+		publisher:      net,
+		deviceRegistry: monitor.DeviceRegistry(),
+		sleep:          func(time.Duration) { net.tick() },
+	})
+	defer rt.StopGame()
+
+	var faults []string
+	injectFaults := func(step string) (duplicate bool) {
+		if rng.Float64() < chaosDisconnectRate {
+			ticks := 1 + rng.Intn(3)
+			net.disconnect(ticks)
+			faults = append(faults, fmt.Sprintf("%s: mqtt down %d ticks", step, ticks))
+		}
+		if rng.Float64() < chaosDelayRate {
+			sink.mu.Lock()
+			sink.delayed = 5 + rng.Intn(20)
+			sink.mu.Unlock()
+			faults = append(faults, step+": slow persistence")
+		}
+		if rng.Float64() < chaosRestartRate {
+			monitor.HandleRegistration(chaosRegistration(0))
+			faults = append(faults, step+": controller restart")
+		}
+		duplicate = rng.Float64() < chaosDuplicateRate
+		if duplicate {
+			faults = append(faults, step+": duplicate message")
+		}
+		return duplicate
+	}
+	// sendUntilDelivered is the player (or controller) repeating an input
+	// until the room receives it
+	sendUntilDelivered := func(step string, fields map[string]interface{}) {
+		duplicate := injectFaults(step)
+		for attempt := 0; !net.deliver(rt, fields); attempt++ {
+			if attempt > 10 {
+				t.Fatalf("%s: message never delivered (faults: %v)", step, faults)
+			}
+		}
+		if duplicate {
+			net.deliver(rt, fields)
+		}
+	}
+
+	// Scripted session
+	injectFaults("register")
+	if result := monitor.HandleRegistration(chaosRegistration(120000)); !result.Valid {
+		t.Fatalf("registration rejected: %v", result.Errors)
+	}
+	if err := rt.StartGame("scene_intro"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	sendUntilDelivered("door", map[string]interface{}{
+		"controller_id": "ctrl-001",
+		"logical_id":    "crypt_door",
+		"topic":         "devices/ctrl-001/crypt_door/events",
+		"payload":       map[string]interface{}{"door_closed": true},
+	})
+	if injectFaults("tiles") {
+		rt.InjectEvent("puzzle.solved", map[string]interface{}{"puzzle_id": "tiles"})
+	}
+	rt.InjectEvent("puzzle.solved", map[string]interface{}{"puzzle_id": "tiles"})
+
+	// The session completed exactly once, each puzzle solved once
+	if n := countNamed("scene.completed"); n != 1 {
+		t.Errorf("expected one scene.completed, got %d (faults: %v)", n, faults)
+	}
+	for _, puzzle := range []string{"puzzle_scarab", "puzzle_tiles"} {
+		if rt.GetPuzzleResolution(puzzle) != PuzzleSolved {
+			t.Errorf("expected %s solved, got %v (faults: %v)", puzzle, rt.GetPuzzleResolution(puzzle), faults)
+		}
+	}
+	if n := countEvents("node.completed", "scarab_unlock"); n != 1 {
+		t.Errorf("expected scarab_unlock to run once, got %d (faults: %v)", n, faults)
+	}
+
+	// The unlock was either delivered once or reported, never silently lost
+	published := len(net.published)
+	reported := countEvents("device.error", "scarab_unlock")
+	if published+reported != 1 {
+		t.Errorf("expected the unlock published or reported once, got %d published / %d errors (faults: %v)",
+			published, reported, faults)
+	}
+	if published == 1 && net.published[0].Topic != "devices/ctrl-001/crypt_door/commands" {
+		t.Errorf("unlock sent to %s after controller restarts", net.published[0].Topic)
+	}
+
+	// Slow persistence must not reorder events
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	for i := 1; i < len(sink.seqs); i++ {
+		if sink.seqs[i] <= sink.seqs[i-1] {
+			t.Fatalf("events out of order: seq %d after %d (faults: %v)", sink.seqs[i], sink.seqs[i-1], faults)
+		}
+	}
+}
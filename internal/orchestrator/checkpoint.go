@@ -0,0 +1,198 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// CheckpointStore persists checkpoint snapshots (implemented by storage.Store).
+type CheckpointStore interface {
+	SaveCheckpoint(ts time.Time, name, sceneID string, snapshot []byte) error
+	LatestCheckpoint(name string) (*storage.CheckpointRow, error)
+}
+
+// Checkpoint is a named snapshot of runtime state.
+type Checkpoint struct {
+	Name          string                      `json:"name"`
+	NodeID        string                      `json:"node_id"`
+	SceneID       string                      `json:"scene_id"`
+	CreatedAt     time.Time                   `json:"created_at"`
+	Nodes         map[string]NodeState        `json:"nodes"`
+	Puzzles       map[string]PuzzleResolution `json:"puzzles"`
+	RandomChoices map[string]string           `json:"random_choices,omitempty"`
+	Variables     map[string]interface{}      `json:"variables,omitempty"`
+}
+
+// SetCheckpointStore sets where checkpoint snapshots are persisted.
+func (r *Runtime) SetCheckpointStore(store CheckpointStore) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checkpointStore = store
+}
+
+// checkpointName returns the configured checkpoint name, defaulting to the node id.
+func checkpointName(node *Node) string {
+	if name, ok := node.Config["name"].(string); ok && name != "" {
+		return name
+	}
+	return node.ID
+}
+
+// snapshot captures the active scene's node states, puzzle resolutions,
+// random choices and variables.
+func (r *Runtime) snapshot(name, nodeID string) *Checkpoint {
+	cp := &Checkpoint{
+		Name:          name,
+		NodeID:        nodeID,
+		SceneID:       r.activeScene.ID,
+		CreatedAt:     r.now(),
+		Nodes:         make(map[string]NodeState),
+		Puzzles:       make(map[string]PuzzleResolution),
+		RandomChoices: make(map[string]string),
+	}
+	for id, status := range r.nodeStates {
+		cp.Nodes[id] = status.State
+	}
+	for id, ps := range r.puzzleStates {
+		cp.Puzzles[id] = ps.Resolution
+	}
+	for id, choice := range r.randomChoices {
+		cp.RandomChoices[id] = choice
+	}
+	cp.Variables = copyVars(r.vars)
+	return cp
+}
+
+// activateCheckpoint snapshots state and completes the node. The snapshot is
+// kept in memory and persisted to the checkpoint store so operators can rewind
+// to it with ResetToCheckpoint.
+func (r *Runtime) activateCheckpoint(node *Node) {
+	name := checkpointName(node)
+
+	cp := r.snapshot(name, node.ID)
+	// The snapshot resumes after the checkpoint itself
+	cp.Nodes[node.ID] = NodeStateCompleted
+
+	r.checkpoints[name] = cp
+	r.lastCheckpoint = name
+
+	if r.checkpointStore != nil {
+		data, err := json.Marshal(cp)
+		if err == nil {
+			err = r.checkpointStore.SaveCheckpoint(cp.CreatedAt, name, cp.SceneID, data)
+		}
+		if err != nil {
+			log.Printf("[checkpoint] failed to persist %s: %v", name, err)
+		}
+	}
+
+	r.completeNodeWith(node.ID, map[string]interface{}{"checkpoint": name})
+}
+
+// ResetToCheckpoint restores the named checkpoint (or the most recent one if
+// name is empty) and resumes execution downstream of it.
+func (r *Runtime) ResetToCheckpoint(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.activeScene == nil {
+		return fmt.Errorf("no active session")
+	}
+
+	cp, err := r.findCheckpoint(name)
+	if err != nil {
+		return err
+	}
+	if cp.SceneID != r.activeScene.ID {
+		return fmt.Errorf("checkpoint %s belongs to scene %s", cp.Name, cp.SceneID)
+	}
+
+	r.pushUndo("reset_to_checkpoint", cp.Name)
+	r.restoreSnapshot(cp, true)
+	r.checkpoints[cp.Name] = cp
+
+	return nil
+}
+
+// restoreSnapshot returns the active scene to a snapshot. Nodes that changed
+// since are reset; with restartActive, nodes active in the snapshot also restart
+// from scratch even if they are still active.
+func (r *Runtime) restoreSnapshot(cp *Checkpoint, restartActive bool) {
+	var reactivate []string
+	for _, node := range r.activeScene.Nodes {
+		status := r.nodeStates[node.ID]
+		if status == nil {
+			continue
+		}
+		target, ok := cp.Nodes[node.ID]
+		if !ok {
+			target = NodeStateIdle
+		}
+
+		// Anything that changed is reset; active nodes optionally restart from scratch
+		if status.State != target || (restartActive && target == NodeStateActive) {
+			r.resetNodeState(node.ID, "")
+		}
+
+		switch target {
+		case NodeStateActive:
+			reactivate = append(reactivate, node.ID)
+		case NodeStateCompleted, NodeStateOverridden, NodeStateFailed:
+			status.State = target
+			if ps, ok := r.puzzleStates[node.ID]; ok {
+				if res, ok := cp.Puzzles[node.ID]; ok {
+					ps.Resolution = res
+				}
+			}
+		}
+	}
+
+	r.randomChoices = make(map[string]string)
+	for id, choice := range cp.RandomChoices {
+		r.randomChoices[id] = choice
+	}
+	r.replaceVars(cp.Variables)
+
+	for _, nodeID := range reactivate {
+		r.activateNode(nodeID)
+	}
+	r.evaluateAllConditions()
+}
+
+// findCheckpoint looks up a checkpoint in memory, falling back to the store.
+func (r *Runtime) findCheckpoint(name string) (*Checkpoint, error) {
+	lookup := name
+	if lookup == "" {
+		lookup = r.lastCheckpoint
+	}
+	if cp, ok := r.checkpoints[lookup]; ok && lookup != "" {
+		return cp, nil
+	}
+
+	if r.checkpointStore != nil {
+		row, err := r.checkpointStore.LatestCheckpoint(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+		}
+		if row != nil {
+			var cp Checkpoint
+			if err := json.Unmarshal(row.Snapshot, &cp); err != nil {
+				return nil, fmt.Errorf("invalid checkpoint %s: %w", row.Name, err)
+			}
+			// Ignore snapshots left over from earlier sessions
+			if !cp.CreatedAt.Before(r.sceneStartedAt) {
+				return &cp, nil
+			}
+		}
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("no checkpoint reached")
+	}
+	return nil, fmt.Errorf("checkpoint not found: %s", name)
+}
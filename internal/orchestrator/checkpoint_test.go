@@ -0,0 +1,180 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// memCheckpointStore keeps checkpoint rows in memory.
+type memCheckpointStore struct {
+	rows []storage.CheckpointRow
+}
+
+func (s *memCheckpointStore) SaveCheckpoint(ts time.Time, name, sceneID string, snapshot []byte) error {
+	s.rows = append(s.rows, storage.CheckpointRow{
+		ID: int64(len(s.rows) + 1), Timestamp: ts, Name: name, SceneID: sceneID, Snapshot: snapshot,
+	})
+	return nil
+}
+
+func (s *memCheckpointStore) LatestCheckpoint(name string) (*storage.CheckpointRow, error) {
+	for i := len(s.rows) - 1; i >= 0; i-- {
+		if name == "" || s.rows[i].Name == name {
+			row := s.rows[i]
+			return &row, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkpointSceneGraph builds a scene with a checkpoint between two puzzles.
+func checkpointSceneGraph() *SceneGraph {
+	return sceneGraph(Scene{
+		ID:    "scene_cp",
+		Entry: "puzzle_one",
+		Nodes: []Node{
+			{ID: "puzzle_one", Type: "puzzle", Config: map[string]interface{}{"subgraph": "sub_one"}},
+			{ID: "cp_tomb", Type: "checkpoint", Config: map[string]interface{}{"name": "tomb_open"}},
+			{ID: "puzzle_two", Type: "puzzle", Config: map[string]interface{}{"subgraph": "sub_two"}},
+			{ID: "done", Type: "terminal", Config: map[string]interface{}{}},
+		},
+		Edges: []Edge{
+			{From: "puzzle_one", To: "cp_tomb"},
+			{From: "cp_tomb", To: "puzzle_two"},
+			{From: "puzzle_two", To: "done"},
+		},
+		Subgraphs: []Subgraph{
+			{ID: "sub_one", Entry: "one_wait", Nodes: []Node{{ID: "one_wait", Type: "decision"}}},
+			{ID: "sub_two", Entry: "two_wait", Nodes: []Node{{ID: "two_wait", Type: "decision"}}},
+		},
+	})
+}
+
+func TestCheckpointSnapshotAndReset(t *testing.T) {
+	events.Clear()
+	store := &memCheckpointStore{}
+	rt := NewRuntime(checkpointSceneGraph())
+	rt.SetCheckpointStore(store)
+
+	if err := rt.StartScene("scene_cp"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+	if err := rt.ResetToCheckpoint(""); err == nil {
+		t.Error("expected error before any checkpoint is reached")
+	}
+
+	if err := rt.OverrideNode("puzzle_one"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+	if rt.GetNodeState("cp_tomb") != NodeStateCompleted {
+		t.Fatalf("expected checkpoint to complete, got %s", rt.GetNodeState("cp_tomb"))
+	}
+	if len(store.rows) != 1 || store.rows[0].Name != "tomb_open" {
+		t.Fatalf("expected one persisted checkpoint, got %+v", store.rows)
+	}
+	var tagged bool
+	for _, e := range events.Snapshot() {
+		if e.Name == "node.completed" && e.Fields["checkpoint"] == "tomb_open" {
+			tagged = true
+		}
+	}
+	if !tagged {
+		t.Error("expected node.completed with checkpoint name")
+	}
+
+	// Progress past the checkpoint, then rewind
+	if err := rt.OverrideNode("puzzle_two"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+	if rt.GetNodeState("done") != NodeStateCompleted {
+		t.Fatalf("expected terminal to complete, got %s", rt.GetNodeState("done"))
+	}
+
+	if err := rt.ResetToCheckpoint("tomb_open"); err != nil {
+		t.Fatalf("failed to reset to checkpoint: %v", err)
+	}
+
+	if rt.GetNodeState("puzzle_one") != NodeStateOverridden {
+		t.Errorf("expected puzzle_one to stay overridden, got %s", rt.GetNodeState("puzzle_one"))
+	}
+	if rt.GetNodeState("puzzle_two") != NodeStateActive {
+		t.Errorf("expected puzzle_two to be active again, got %s", rt.GetNodeState("puzzle_two"))
+	}
+	if rt.puzzleStates["puzzle_two"].Resolution != PuzzleUnresolved {
+		t.Errorf("expected puzzle_two unresolved, got %s", rt.puzzleStates["puzzle_two"].Resolution)
+	}
+	if rt.GetNodeState("done") != NodeStateIdle {
+		t.Errorf("expected terminal to be idle, got %s", rt.GetNodeState("done"))
+	}
+
+	if err := rt.ResetToCheckpoint("unknown"); err == nil {
+		t.Error("expected error for unknown checkpoint")
+	}
+}
+
+func TestCheckpointResetAfterRestart(t *testing.T) {
+	store := &memCheckpointStore{}
+	rt := NewRuntime(checkpointSceneGraph())
+	rt.SetCheckpointStore(store)
+	if err := rt.StartScene("scene_cp"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+	if err := rt.OverrideNode("puzzle_one"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+	if err := rt.OverrideNode("puzzle_two"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+
+	// A restarted runtime only has the persisted snapshot
+	restarted := NewRuntime(checkpointSceneGraph())
+	restarted.SetCheckpointStore(store)
+	err := restarted.ApplyRestoredState(&RestoredState{
+		SessionActive: true,
+		SceneID:       "scene_cp",
+		StartedAt:     time.Now().Add(-time.Minute),
+		PuzzleStates: map[string]PuzzleResolution{
+			"puzzle_one": PuzzleOverridden,
+			"puzzle_two": PuzzleOverridden,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to apply restored state: %v", err)
+	}
+
+	if err := restarted.ResetToCheckpoint(""); err != nil {
+		t.Fatalf("failed to reset to persisted checkpoint: %v", err)
+	}
+	if restarted.GetNodeState("cp_tomb") != NodeStateCompleted {
+		t.Errorf("expected checkpoint completed, got %s", restarted.GetNodeState("cp_tomb"))
+	}
+	if restarted.GetNodeState("puzzle_two") != NodeStateActive {
+		t.Errorf("expected puzzle_two active, got %s", restarted.GetNodeState("puzzle_two"))
+	}
+}
+
+func TestCheckpointIgnoresEarlierSession(t *testing.T) {
+	store := &memCheckpointStore{}
+	rt := NewRuntime(checkpointSceneGraph())
+	rt.SetCheckpointStore(store)
+	if err := rt.StartScene("scene_cp"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+	if err := rt.OverrideNode("puzzle_one"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+
+	// A new session started after the snapshot must not rewind into it
+	later := NewRuntime(checkpointSceneGraph())
+	later.SetCheckpointStore(store)
+	later.now = func() time.Time { return time.Now().Add(time.Hour) }
+	if err := later.StartScene("scene_cp"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+	if err := later.ResetToCheckpoint("tomb_open"); err == nil {
+		t.Error("expected checkpoint from an earlier session to be ignored")
+	}
+}
@@ -0,0 +1,281 @@
+package orchestrator
+
+import (
+	"fmt"
+	"time"
+)
+
+// GameClockID identifies the session countdown in timer.* events.
+const GameClockID = "game_clock"
+
+// DefaultGameDuration is the session countdown length when none is configured.
+const DefaultGameDuration = 60 * time.Minute
+
+// gameClockKey is the scheduler key for the session countdown expiry.
+const gameClockKey = "clock:" + GameClockID
+
+// gameClock tracks the master countdown for a game session.
+// Elapsed time accumulates across run segments so pauses do not consume time.
+type gameClock struct {
+	duration  time.Duration
+	elapsed   time.Duration
+	resumedAt time.Time
+	running   bool
+	paused    bool
+	expired   bool
+}
+
+// ClockState is a point-in-time view of the session countdown.
+type ClockState struct {
+	Running     bool  `json:"running"`
+	Paused      bool  `json:"paused"`
+	Expired     bool  `json:"expired"`
+	DurationMs  int64 `json:"duration_ms"`
+	ElapsedMs   int64 `json:"elapsed_ms"`
+	RemainingMs int64 `json:"remaining_ms"`
+}
+
+// SetGameDuration sets the countdown length used by subsequent StartGame calls
+// and the cap that adjustments may not exceed (0 for no cap).
+func (r *Runtime) SetGameDuration(d, max time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gameDuration = d
+	r.maxDuration = max
+}
+
+// GameDuration returns the countdown length used for new games.
+func (r *Runtime) GameDuration() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.gameDuration <= 0 {
+		return DefaultGameDuration
+	}
+	return r.gameDuration
+}
+
+// GameClock returns the current state of the session countdown.
+func (r *Runtime) GameClock() ClockState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.clockState()
+}
+
+// PauseClock stops the session countdown without losing remaining time.
+func (r *Runtime) PauseClock() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := r.clock
+	if c == nil {
+		return fmt.Errorf("no active game")
+	}
+	if c.expired {
+		return fmt.Errorf("clock expired")
+	}
+	r.pauseClock()
+	return nil
+}
+
+// pauseClock stops a running countdown.
+// Caller must hold r.mu.
+func (r *Runtime) pauseClock() {
+	c := r.clock
+	if c == nil || c.expired || c.paused {
+		return
+	}
+
+	c.elapsed += r.now().Sub(c.resumedAt)
+	c.resumedAt = time.Time{}
+	c.paused = true
+	r.cancelScheduled(gameClockKey)
+
+	r.emitEvent("timer.paused", map[string]interface{}{
+		"timer_id":     GameClockID,
+		"remaining_ms": r.clockRemaining().Milliseconds(),
+	})
+}
+
+// ResumeClock restarts a paused session countdown.
+func (r *Runtime) ResumeClock() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := r.clock
+	if c == nil {
+		return fmt.Errorf("no active game")
+	}
+	if c.expired {
+		return fmt.Errorf("clock expired")
+	}
+	if r.paused {
+		return fmt.Errorf("game paused")
+	}
+	r.resumeClock()
+	return nil
+}
+
+// resumeClock restarts a paused countdown.
+// Caller must hold r.mu.
+func (r *Runtime) resumeClock() {
+	c := r.clock
+	if c == nil || c.expired || !c.paused {
+		return
+	}
+
+	c.paused = false
+	c.resumedAt = r.now()
+	r.scheduleClockExpiry()
+
+	r.emitEvent("timer.resumed", map[string]interface{}{
+		"timer_id":     GameClockID,
+		"remaining_ms": r.clockRemaining().Milliseconds(),
+	})
+}
+
+// AdjustClock adds (positive) or removes (negative) time from the session countdown.
+// Removing more time than remains expires the clock immediately.
+func (r *Runtime) AdjustClock(delta time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := r.clock
+	if c == nil {
+		return fmt.Errorf("no active game")
+	}
+	if c.expired {
+		return fmt.Errorf("clock expired")
+	}
+
+	duration := c.duration + delta
+	if r.maxDuration > 0 && duration > r.maxDuration {
+		return fmt.Errorf("adjustment exceeds max game duration of %s", r.maxDuration)
+	}
+	if duration < 0 {
+		duration = 0
+	}
+	c.duration = duration
+
+	r.emitEvent("timer.adjusted", map[string]interface{}{
+		"timer_id":     GameClockID,
+		"delta_ms":     delta.Milliseconds(),
+		"remaining_ms": r.clockRemaining().Milliseconds(),
+	})
+
+	if r.clockRemaining() == 0 {
+		r.cancelScheduled(gameClockKey)
+		r.expireClock()
+		return nil
+	}
+	if !c.paused {
+		r.scheduleClockExpiry()
+	}
+	return nil
+}
+
+// startClock begins a fresh countdown for a new game session.
+// Caller must hold r.mu.
+func (r *Runtime) startClock() {
+	duration := r.gameDuration
+	if duration <= 0 {
+		duration = DefaultGameDuration
+	}
+	r.clock = &gameClock{
+		duration:  duration,
+		resumedAt: r.now(),
+		running:   true,
+	}
+	r.scheduleClockExpiry()
+
+	r.emitEvent("timer.started", map[string]interface{}{
+		"timer_id":    GameClockID,
+		"duration_ms": duration.Milliseconds(),
+	})
+}
+
+// stopClock cancels the countdown when the session ends.
+// Caller must hold r.mu.
+func (r *Runtime) stopClock(reason string) {
+	c := r.clock
+	if c == nil {
+		return
+	}
+	r.cancelScheduled(gameClockKey)
+	if !c.expired {
+		r.emitEvent("timer.cancelled", map[string]interface{}{
+			"timer_id":     GameClockID,
+			"reason":       reason,
+			"remaining_ms": r.clockRemaining().Milliseconds(),
+		})
+	}
+	r.clock = nil
+}
+
+// scheduleClockExpiry arms the scheduler for the remaining countdown.
+// Caller must hold r.mu.
+func (r *Runtime) scheduleClockExpiry() {
+	r.schedule(gameClockKey, r.clockRemaining(), r.expireClock)
+}
+
+// expireClock marks the countdown as expired. The game keeps running so the
+// operator decides how to end it.
+// Caller must hold r.mu.
+func (r *Runtime) expireClock() {
+	c := r.clock
+	if c == nil || c.expired {
+		return
+	}
+	if !c.paused {
+		c.elapsed += r.now().Sub(c.resumedAt)
+	}
+	c.resumedAt = time.Time{}
+	c.running = false
+	c.paused = false
+	c.expired = true
+	r.emitEvent("timer.expired", map[string]interface{}{"timer_id": GameClockID})
+}
+
+// clockElapsed returns the run time consumed by the countdown.
+// Caller must hold r.mu.
+func (r *Runtime) clockElapsed() time.Duration {
+	c := r.clock
+	if c == nil {
+		return 0
+	}
+	elapsed := c.elapsed
+	if c.running && !c.paused {
+		elapsed += r.now().Sub(c.resumedAt)
+	}
+	return elapsed
+}
+
+// clockRemaining returns the countdown time left, never negative.
+// Caller must hold r.mu.
+func (r *Runtime) clockRemaining() time.Duration {
+	c := r.clock
+	if c == nil {
+		return 0
+	}
+	remaining := c.duration - r.clockElapsed()
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining
+}
+
+// clockState snapshots the countdown.
+// Caller must hold r.mu.
+func (r *Runtime) clockState() ClockState {
+	c := r.clock
+	if c == nil {
+		return ClockState{}
+	}
+	return ClockState{
+		Running:     c.running,
+		Paused:      c.paused,
+		Expired:     c.expired,
+		DurationMs:  c.duration.Milliseconds(),
+		ElapsedMs:   r.clockElapsed().Milliseconds(),
+		RemainingMs: r.clockRemaining().Milliseconds(),
+	}
+}
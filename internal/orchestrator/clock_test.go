@@ -0,0 +1,171 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// fakeClock is a manually advanced time source for the runtime.
+type fakeClock struct {
+	t time.Time
+}
+
+func (f *fakeClock) now() time.Time { return f.t }
+
+func (f *fakeClock) advance(d time.Duration) { f.t = f.t.Add(d) }
+
+func newClockRuntime(t *testing.T) (*Runtime, *fakeClock) {
+	t.Helper()
+	sg, err := LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	rt := NewRuntime(sg)
+	fc := &fakeClock{t: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	rt.now = fc.now
+	rt.SetGameDuration(60*time.Minute, 90*time.Minute)
+	return rt, fc
+}
+
+func TestGameClockPauseResume(t *testing.T) {
+	events.Clear()
+	rt, fc := newClockRuntime(t)
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+
+	clock := rt.GameClock()
+	if !clock.Running || clock.RemainingMs != (60*time.Minute).Milliseconds() {
+		t.Fatalf("expected running clock with 60m remaining, got %+v", clock)
+	}
+
+	fc.advance(10 * time.Minute)
+	if err := rt.PauseClock(); err != nil {
+		t.Fatalf("failed to pause: %v", err)
+	}
+
+	// Time spent paused does not count
+	fc.advance(5 * time.Minute)
+	clock = rt.GameClock()
+	if !clock.Paused || clock.RemainingMs != (50*time.Minute).Milliseconds() {
+		t.Errorf("expected paused clock with 50m remaining, got %+v", clock)
+	}
+
+	if err := rt.ResumeClock(); err != nil {
+		t.Fatalf("failed to resume: %v", err)
+	}
+	fc.advance(20 * time.Minute)
+	clock = rt.GameClock()
+	if clock.Paused || clock.RemainingMs != (30*time.Minute).Milliseconds() {
+		t.Errorf("expected running clock with 30m remaining, got %+v", clock)
+	}
+
+	for _, name := range []string{"timer.started", "timer.paused", "timer.resumed"} {
+		found := false
+		for _, e := range events.Snapshot() {
+			if e.Name == name && e.Fields["timer_id"] == GameClockID {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected %s for game clock", name)
+		}
+	}
+}
+
+func TestGameClockAdjust(t *testing.T) {
+	events.Clear()
+	rt, _ := newClockRuntime(t)
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+
+	if err := rt.AdjustClock(5 * time.Minute); err != nil {
+		t.Fatalf("failed to add time: %v", err)
+	}
+	if got := rt.GameClock().RemainingMs; got != (65 * time.Minute).Milliseconds() {
+		t.Errorf("expected 65m remaining, got %dms", got)
+	}
+
+	// Cannot exceed max_game_minutes
+	if err := rt.AdjustClock(30 * time.Minute); err == nil {
+		t.Error("expected error when exceeding max game duration")
+	}
+
+	// Removing more than remains expires the clock
+	if err := rt.AdjustClock(-120 * time.Minute); err != nil {
+		t.Fatalf("failed to remove time: %v", err)
+	}
+	clock := rt.GameClock()
+	if !clock.Expired || clock.RemainingMs != 0 {
+		t.Errorf("expected expired clock, got %+v", clock)
+	}
+
+	// Game keeps running after expiry
+	if !rt.IsGameActive() {
+		t.Error("expected game to remain active after clock expiry")
+	}
+}
+
+func TestGameClockExpiry(t *testing.T) {
+	events.Clear()
+	rt, fc := newClockRuntime(t)
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+
+	fc.advance(60 * time.Minute)
+	rt.mu.Lock()
+	fired := rt.fireScheduled(gameClockKey)
+	rt.mu.Unlock()
+	if !fired {
+		t.Fatal("expected game clock expiry to be scheduled")
+	}
+
+	if !rt.GameClock().Expired {
+		t.Error("expected clock to be expired")
+	}
+	found := false
+	for _, e := range events.Snapshot() {
+		if e.Name == "timer.expired" && e.Fields["timer_id"] == GameClockID {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected timer.expired for game clock")
+	}
+}
+
+func TestGameClockStop(t *testing.T) {
+	events.Clear()
+	rt, _ := newClockRuntime(t)
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	if err := rt.StopGame(); err != nil {
+		t.Fatalf("failed to stop game: %v", err)
+	}
+
+	if rt.GameClock().Running {
+		t.Error("expected clock to stop with the game")
+	}
+	if err := rt.PauseClock(); err == nil {
+		t.Error("expected error pausing clock without an active game")
+	}
+
+	found := false
+	for _, e := range events.Snapshot() {
+		if e.Name == "timer.cancelled" && e.Fields["timer_id"] == GameClockID {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected timer.cancelled for game clock on stop")
+	}
+}
@@ -0,0 +1,452 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// DefaultCompetitionLead is how far ahead the start is scheduled when arming.
+const DefaultCompetitionLead = 10 * time.Second
+
+// competitionStatusInterval is how often running rooms share their progress.
+const competitionStatusInterval = 2 * time.Second
+
+// Control messages exchanged between competing rooms.
+const (
+	competitionArm    = "competition.arm"
+	competitionCancel = "competition.cancel"
+	competitionStatus = "competition.status"
+)
+
+// ErrCompetitionArmed is returned when arming while another competition is armed.
+var ErrCompetitionArmed = errors.New("competition: already armed")
+
+// CompetitionLink sends control messages to sibling rooms (implemented by *Federation).
+type CompetitionLink interface {
+	RoomID() string
+	IsPeer(room string) bool
+	SendControl(room, name string, fields map[string]interface{}) error
+}
+
+// CompetitionRoom is the local game a competition starts (implemented by *Runtime).
+type CompetitionRoom interface {
+	StartGame(sceneID string) error
+	Snapshot() RuntimeSnapshot
+}
+
+// CompetitionInfo describes an armed or running competition.
+type CompetitionInfo struct {
+	ID          string    `json:"id"`
+	Coordinator string    `json:"coordinator"`
+	Rooms       []string  `json:"rooms"`
+	SceneID     string    `json:"scene_id,omitempty"`
+	StartAt     time.Time `json:"start_at"`
+	Started     bool      `json:"started"`
+}
+
+// RoomStanding is one room's live result in a competition.
+type RoomStanding struct {
+	Room        string    `json:"room"`
+	Rank        int       `json:"rank"`
+	GameActive  bool      `json:"game_active"`
+	ProgressPct int       `json:"progress_pct"`
+	ElapsedMs   int64     `json:"elapsed_ms"`
+	RemainingMs int64     `json:"remaining_ms"`
+	Finished    bool      `json:"finished"`
+	FinishMs    int64     `json:"finish_ms,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitempty"`
+}
+
+// Standings ranks the rooms of the current competition: finished rooms by
+// finish time, then the rest by progress and elapsed time.
+type Standings struct {
+	Competition *CompetitionInfo `json:"competition,omitempty"`
+	Rooms       []RoomStanding   `json:"rooms"`
+}
+
+// Competition arms and starts synchronized games across rooms and collects
+// their standings (ADR-013). Every room starts when its own clock reaches the
+// shared start time, so rooms start together regardless of message latency
+// as long as their hosts are NTP-synced.
+type Competition struct {
+	link CompetitionLink
+	room CompetitionRoom
+
+	mu         sync.Mutex
+	current    *CompetitionInfo
+	startTimer *time.Timer
+	standings  map[string]RoomStanding
+	finalSent  bool // the final status after the local game ended was shared
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewCompetition creates a competition coordinator for the local room.
+func NewCompetition(link CompetitionLink, room CompetitionRoom) *Competition {
+	return &Competition{
+		link:      link,
+		room:      room,
+		standings: make(map[string]RoomStanding),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start shares this room's progress with the other rooms while a competition runs.
+func (c *Competition) Start() {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(competitionStatusInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.stopCh:
+				return
+			case <-ticker.C:
+				c.ShareStatus()
+			}
+		}
+	}()
+}
+
+// Stop cancels a pending start and waits for the worker to exit.
+func (c *Competition) Stop() {
+	c.mu.Lock()
+	if c.startTimer != nil {
+		c.startTimer.Stop()
+	}
+	c.mu.Unlock()
+	close(c.stopCh)
+	c.wg.Wait()
+}
+
+// Arm schedules a synchronized start of sceneID in this room and the given
+// sibling rooms, lead from now. If any room cannot be armed, the rooms already
+// armed are cancelled and an error is returned.
+func (c *Competition) Arm(rooms []string, sceneID string, lead time.Duration) (CompetitionInfo, error) {
+	self := c.link.RoomID()
+	if lead <= 0 {
+		lead = DefaultCompetitionLead
+	}
+
+	all := []string{self}
+	seen := map[string]bool{self: true}
+	for _, room := range rooms {
+		if seen[room] {
+			continue
+		}
+		if !c.link.IsPeer(room) {
+			return CompetitionInfo{}, fmt.Errorf("competition: %s is not a federation peer", room)
+		}
+		seen[room] = true
+		all = append(all, room)
+	}
+	if len(all) < 2 {
+		return CompetitionInfo{}, fmt.Errorf("competition: at least one other room required")
+	}
+
+	c.mu.Lock()
+	if c.current != nil && !c.current.Started {
+		c.mu.Unlock()
+		return CompetitionInfo{}, ErrCompetitionArmed
+	}
+	c.mu.Unlock()
+
+	startAt := time.Now().Add(lead).UTC()
+	info := CompetitionInfo{
+		ID:          fmt.Sprintf("%s-%d", self, startAt.UnixMilli()),
+		Coordinator: self,
+		Rooms:       all,
+		SceneID:     sceneID,
+		StartAt:     startAt,
+	}
+
+	fields := map[string]interface{}{
+		"competition_id": info.ID,
+		"rooms":          info.Rooms,
+		"scene_id":       info.SceneID,
+		"start_at":       info.StartAt.Format(time.RFC3339Nano),
+	}
+	for i, room := range all[1:] {
+		if err := c.link.SendControl(room, competitionArm, fields); err != nil {
+			for _, armed := range all[1 : i+1] {
+				_ = c.link.SendControl(armed, competitionCancel, map[string]interface{}{"competition_id": info.ID})
+			}
+			return CompetitionInfo{}, fmt.Errorf("competition: arm %s: %w", room, err)
+		}
+	}
+
+	if err := c.arm(info); err != nil {
+		return CompetitionInfo{}, err
+	}
+	return info, nil
+}
+
+// Cancel disarms a competition that has not started, here and in the other rooms.
+func (c *Competition) Cancel() error {
+	c.mu.Lock()
+	info := c.current
+	if info == nil || info.Started {
+		c.mu.Unlock()
+		return fmt.Errorf("competition: nothing armed")
+	}
+	c.disarm(info.ID)
+	c.mu.Unlock()
+
+	self := c.link.RoomID()
+	for _, room := range info.Rooms {
+		if room == self || !c.link.IsPeer(room) {
+			continue
+		}
+		if err := c.link.SendControl(room, competitionCancel, map[string]interface{}{"competition_id": info.ID}); err != nil {
+			log.Printf("[competition] failed to cancel %s: %v", room, err)
+		}
+	}
+	return nil
+}
+
+// HandleControl handles competition messages from sibling rooms.
+func (c *Competition) HandleControl(fe FederatedEvent) error {
+	id, _ := fe.Fields["competition_id"].(string)
+	if id == "" {
+		return fmt.Errorf("competition: competition_id required")
+	}
+
+	switch fe.Event {
+	case competitionArm:
+		info := CompetitionInfo{ID: id, Coordinator: fe.Origin}
+		info.SceneID, _ = fe.Fields["scene_id"].(string)
+		rooms, _ := fe.Fields["rooms"].([]interface{})
+		for _, room := range rooms {
+			if s, ok := room.(string); ok {
+				info.Rooms = append(info.Rooms, s)
+			}
+		}
+		startAt, _ := fe.Fields["start_at"].(string)
+		ts, err := time.Parse(time.RFC3339Nano, startAt)
+		if err != nil {
+			return fmt.Errorf("competition: invalid start_at")
+		}
+		info.StartAt = ts
+		if !hasRoom(info.Rooms, c.link.RoomID()) {
+			return fmt.Errorf("competition: this room is not in the competition")
+		}
+		if !time.Now().Before(info.StartAt) {
+			return fmt.Errorf("competition: start time has passed")
+		}
+		return c.arm(info)
+
+	case competitionCancel:
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.current != nil && c.current.ID == id && !c.current.Started {
+			c.disarm(id)
+		}
+		return nil
+
+	case competitionStatus:
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.current == nil || c.current.ID != id || !hasRoom(c.current.Rooms, fe.Origin) {
+			return nil
+		}
+		s := RoomStanding{Room: fe.Origin, UpdatedAt: time.Now().UTC()}
+		s.GameActive, _ = fe.Fields["game_active"].(bool)
+		s.Finished, _ = fe.Fields["finished"].(bool)
+		if v, ok := toFloat(fe.Fields["progress_pct"]); ok {
+			s.ProgressPct = int(v)
+		}
+		if v, ok := toFloat(fe.Fields["elapsed_ms"]); ok {
+			s.ElapsedMs = int64(v)
+		}
+		if v, ok := toFloat(fe.Fields["remaining_ms"]); ok {
+			s.RemainingMs = int64(v)
+		}
+		if v, ok := toFloat(fe.Fields["finish_ms"]); ok {
+			s.FinishMs = int64(v)
+		}
+		c.standings[fe.Origin] = s
+		return nil
+	}
+	return fmt.Errorf("competition: unknown message %q", fe.Event)
+}
+
+// ShareStatus sends this room's standing to the other rooms while its game runs,
+// and once more after it ends.
+func (c *Competition) ShareStatus() {
+	c.mu.Lock()
+	if c.current == nil || !c.current.Started || c.finalSent {
+		c.mu.Unlock()
+		return
+	}
+	own := c.ownStanding()
+	if !own.GameActive {
+		c.finalSent = true
+	}
+	info := *c.current
+	c.mu.Unlock()
+
+	fields := map[string]interface{}{
+		"competition_id": info.ID,
+		"game_active":    own.GameActive,
+		"progress_pct":   own.ProgressPct,
+		"elapsed_ms":     own.ElapsedMs,
+		"remaining_ms":   own.RemainingMs,
+		"finished":       own.Finished,
+		"finish_ms":      own.FinishMs,
+	}
+	// Only linked rooms get status; the coordinator is linked with every room,
+	// so its standings are always complete
+	self := c.link.RoomID()
+	for _, room := range info.Rooms {
+		if room == self || !c.link.IsPeer(room) {
+			continue
+		}
+		if err := c.link.SendControl(room, competitionStatus, fields); err != nil {
+			log.Printf("[competition] failed to send status to %s: %v", room, err)
+		}
+	}
+}
+
+// Standings returns the current competition and its ranked rooms.
+func (c *Competition) Standings() Standings {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := Standings{Rooms: []RoomStanding{}}
+	if c.current == nil {
+		return result
+	}
+	info := *c.current
+	result.Competition = &info
+
+	if info.Started {
+		c.ownStanding()
+	}
+	for _, room := range info.Rooms {
+		s, ok := c.standings[room]
+		if !ok {
+			s = RoomStanding{Room: room}
+		}
+		result.Rooms = append(result.Rooms, s)
+	}
+
+	sort.SliceStable(result.Rooms, func(i, j int) bool {
+		a, b := result.Rooms[i], result.Rooms[j]
+		if a.Finished != b.Finished {
+			return a.Finished
+		}
+		if a.Finished {
+			return a.FinishMs < b.FinishMs
+		}
+		if a.ProgressPct != b.ProgressPct {
+			return a.ProgressPct > b.ProgressPct
+		}
+		return a.ElapsedMs < b.ElapsedMs
+	})
+	for i := range result.Rooms {
+		result.Rooms[i].Rank = i + 1
+	}
+	return result
+}
+
+// arm records a competition and schedules the local start.
+func (c *Competition) arm(info CompetitionInfo) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.current != nil && !c.current.Started {
+		return ErrCompetitionArmed
+	}
+	c.current = &info
+	c.standings = make(map[string]RoomStanding)
+	c.finalSent = false
+	c.startTimer = time.AfterFunc(time.Until(info.StartAt), func() { c.start(info.ID) })
+
+	events.Emit("info", "room.competition_armed", "", map[string]interface{}{
+		"competition_id": info.ID,
+		"coordinator":    info.Coordinator,
+		"rooms":          info.Rooms,
+		"scene_id":       info.SceneID,
+		"start_at":       info.StartAt.Format(time.RFC3339Nano),
+	})
+	return nil
+}
+
+// disarm drops an armed competition. Caller must hold c.mu.
+func (c *Competition) disarm(id string) {
+	if c.startTimer != nil {
+		c.startTimer.Stop()
+		c.startTimer = nil
+	}
+	c.current = nil
+	c.standings = make(map[string]RoomStanding)
+	events.Emit("info", "room.competition_cancelled", "", map[string]interface{}{
+		"competition_id": id,
+	})
+}
+
+// start begins the local game at the shared start time.
+func (c *Competition) start(id string) {
+	c.mu.Lock()
+	if c.current == nil || c.current.ID != id || c.current.Started {
+		c.mu.Unlock()
+		return
+	}
+	c.current.Started = true
+	c.startTimer = nil
+	sceneID := c.current.SceneID
+	c.mu.Unlock()
+
+	if err := c.room.StartGame(sceneID); err != nil {
+		events.Emit("error", "system.error", "competition start failed", map[string]interface{}{
+			"competition_id": id,
+			"error":          err.Error(),
+		})
+	}
+}
+
+// ownStanding refreshes this room's standing from the runtime. After the game
+// ends the last standing is kept. Caller must hold c.mu.
+func (c *Competition) ownStanding() RoomStanding {
+	self := c.link.RoomID()
+	snap := c.room.Snapshot()
+	if !snap.GameActive {
+		if s, ok := c.standings[self]; ok {
+			s.GameActive = false
+			c.standings[self] = s
+			return s
+		}
+	}
+
+	s := RoomStanding{
+		Room:        self,
+		GameActive:  snap.GameActive,
+		ProgressPct: snap.ProgressPct(),
+		ElapsedMs:   snap.Clock.ElapsedMs,
+		RemainingMs: snap.Clock.RemainingMs,
+		UpdatedAt:   time.Now().UTC(),
+	}
+	if snap.SceneCompletedAt != nil && snap.SceneStartedAt != nil {
+		s.Finished = true
+		s.FinishMs = snap.SceneCompletedAt.Sub(*snap.SceneStartedAt).Milliseconds()
+	}
+	c.standings[self] = s
+	return s
+}
+
+func hasRoom(rooms []string, room string) bool {
+	for _, r := range rooms {
+		if r == room {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// memoryLink delivers control messages directly to other rooms' competitions,
+// through JSON like the federation link.
+type memoryLink struct {
+	self  string
+	rooms map[string]*Competition
+	down  map[string]bool
+}
+
+func (l *memoryLink) RoomID() string { return l.self }
+
+func (l *memoryLink) IsPeer(room string) bool {
+	_, ok := l.rooms[room]
+	return ok || l.down[room]
+}
+
+func (l *memoryLink) SendControl(room, name string, fields map[string]interface{}) error {
+	if l.down[room] {
+		return fmt.Errorf("peer unreachable")
+	}
+	data, _ := json.Marshal(FederatedEvent{Type: FederationControl, Origin: l.self, Event: name, Fields: fields})
+	var fe FederatedEvent
+	_ = json.Unmarshal(data, &fe)
+	return l.rooms[room].HandleControl(fe)
+}
+
+// competingRooms links two rooms running the MVP scene graph.
+func competingRooms(t *testing.T) (*Competition, *Runtime, *Competition, *Runtime, *memoryLink) {
+	t.Helper()
+	var runtimes []*Runtime
+	for i := 0; i < 2; i++ {
+		sg, err := LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+		if err != nil {
+			t.Fatalf("failed to load scene graph: %v", err)
+		}
+		runtimes = append(runtimes, NewRuntime(sg))
+	}
+	linkA := &memoryLink{self: "room_a", rooms: map[string]*Competition{}, down: map[string]bool{}}
+	linkB := &memoryLink{self: "room_b", rooms: map[string]*Competition{}, down: map[string]bool{}}
+	a := NewCompetition(linkA, runtimes[0])
+	b := NewCompetition(linkB, runtimes[1])
+	linkA.rooms["room_b"] = b
+	linkB.rooms["room_a"] = a
+	return a, runtimes[0], b, runtimes[1], linkA
+}
+
+func TestCompetitionSynchronizedStart(t *testing.T) {
+	events.Clear()
+	a, rtA, b, rtB, _ := competingRooms(t)
+	defer rtA.StopGame()
+	defer rtB.StopGame()
+
+	info, err := a.Arm([]string{"room_b"}, "scene_intro", 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("failed to arm: %v", err)
+	}
+	if len(info.Rooms) != 2 || info.Coordinator != "room_a" {
+		t.Errorf("unexpected competition: %+v", info)
+	}
+	if got := b.Standings().Competition; got == nil || got.ID != info.ID || got.Coordinator != "room_a" {
+		t.Errorf("expected room_b armed by room_a, got %+v", got)
+	}
+	if _, err := a.Arm([]string{"room_b"}, "", time.Second); !errors.Is(err, ErrCompetitionArmed) {
+		t.Errorf("expected ErrCompetitionArmed, got %v", err)
+	}
+	if n := countNamed("room.competition_armed"); n != 2 {
+		t.Errorf("expected 2 room.competition_armed, got %d", n)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !(rtA.IsGameActive() && rtB.IsGameActive()) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	snapA, snapB := rtA.Snapshot(), rtB.Snapshot()
+	if !snapA.GameActive || !snapB.GameActive {
+		t.Fatalf("expected both rooms started")
+	}
+	if d := snapA.SceneStartedAt.Sub(*snapB.SceneStartedAt); d > 50*time.Millisecond || d < -50*time.Millisecond {
+		t.Errorf("expected rooms to start together, %v apart", d)
+	}
+	if err := a.Cancel(); err == nil {
+		t.Errorf("expected cancel to fail after the start")
+	}
+
+	// room_b pulls ahead and shares its progress with room_a
+	if err := rtB.OverrideNode("puzzle_scarab"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+	b.ShareStatus()
+	a.ShareStatus()
+
+	for name, c := range map[string]*Competition{"room_a": a, "room_b": b} {
+		standings := c.Standings()
+		if len(standings.Rooms) != 2 || standings.Rooms[0].Room != "room_b" || standings.Rooms[0].Rank != 1 ||
+			standings.Rooms[0].ProgressPct != 50 || standings.Rooms[1].ProgressPct != 0 {
+			t.Errorf("%s: unexpected standings: %+v", name, standings.Rooms)
+		}
+	}
+}
+
+func TestCompetitionCancelAndRollback(t *testing.T) {
+	events.Clear()
+	a, rtA, b, rtB, linkA := competingRooms(t)
+
+	if _, err := a.Arm([]string{"room_x"}, "", time.Hour); err == nil {
+		t.Errorf("expected error for a room that is not a peer")
+	}
+
+	// A room that cannot be reached cancels the rooms already armed
+	linkA.down["room_c"] = true
+	if _, err := a.Arm([]string{"room_b", "room_c"}, "", time.Hour); err == nil {
+		t.Fatalf("expected arm to fail")
+	}
+	if a.Standings().Competition != nil || b.Standings().Competition != nil {
+		t.Errorf("expected no competition armed after rollback")
+	}
+
+	if _, err := a.Arm([]string{"room_b"}, "", time.Hour); err != nil {
+		t.Fatalf("failed to arm: %v", err)
+	}
+	if err := a.Cancel(); err != nil {
+		t.Fatalf("failed to cancel: %v", err)
+	}
+	if a.Standings().Competition != nil || b.Standings().Competition != nil {
+		t.Errorf("expected both rooms disarmed")
+	}
+	if n := countNamed("room.competition_cancelled"); n != 3 {
+		t.Errorf("expected 3 room.competition_cancelled (rollback + cancel), got %d", n)
+	}
+	if rtA.IsGameActive() || rtB.IsGameActive() {
+		t.Errorf("expected no games started")
+	}
+}
@@ -0,0 +1,89 @@
+package orchestrator
+
+import "time"
+
+// SetResetCooldown sets the default re-solve cooldown after a puzzle reset
+// (ops.reset_cooldown_ms). While it runs the puzzle ignores matching input, so
+// a retained MQTT message or a prop still in the solved position cannot
+// re-solve it the moment it resets.
+func (r *Runtime) SetResetCooldown(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.resetCooldown = d
+}
+
+// cooldownDuration returns the re-solve cooldown for the active scene.
+func (r *Runtime) cooldownDuration() time.Duration {
+	if r.activeScene != nil && r.activeScene.ResetCooldownMs != nil {
+		return time.Duration(*r.activeScene.ResetCooldownMs) * time.Millisecond
+	}
+	return r.resetCooldown
+}
+
+// startCooldown starts a reset puzzle's cooldown.
+func (r *Runtime) startCooldown(nodeID string) {
+	d := r.cooldownDuration()
+	if d <= 0 {
+		delete(r.cooldowns, nodeID)
+		return
+	}
+	r.cooldowns[nodeID] = r.now().Add(d)
+}
+
+// coolingDown reports whether a puzzle ignores evt because it is in its
+// cooldown, emitting puzzle.input_suppressed if evt would have matched.
+func (r *Runtime) coolingDown(nodeID string, pr *PuzzleRuntime, evt Event) bool {
+	until, ok := r.cooldowns[nodeID]
+	if !ok {
+		return false
+	}
+	remaining := until.Sub(r.now())
+	if remaining <= 0 {
+		delete(r.cooldowns, nodeID)
+		return false
+	}
+	if pr.Matches(evt) {
+		fields := map[string]interface{}{
+			"node_id":      nodeID,
+			"event":        evt.Name,
+			"remaining_ms": remaining.Milliseconds(),
+		}
+		if logicalID, ok := evt.Fields["logical_id"].(string); ok {
+			fields["logical_id"] = logicalID
+		}
+		r.emitEvent("puzzle.input_suppressed", fields)
+	}
+	return true
+}
+
+// Matches reports whether evt matches a condition of a waiting subgraph node,
+// without changing any state.
+func (pr *PuzzleRuntime) Matches(evt Event) bool {
+	if pr.Done() {
+		return false
+	}
+	ctx := &EvalContext{
+		Event: &evt,
+		Vars:  pr.vars,
+	}
+	for _, node := range pr.subgraph.Nodes {
+		if pr.nodeStates[node.ID].State != NodeStateActive {
+			continue
+		}
+		if node.Type == "decision" {
+			for _, edge := range pr.subgraph.Edges {
+				if edge.From == node.ID && EvalCondition(edge.Condition, ctx) {
+					return true
+				}
+			}
+			continue
+		}
+		for _, key := range []string{"match", "reset_on"} {
+			if expr, ok := node.Config[key].(string); ok && expr != "" && EvalCondition(expr, ctx) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+func TestResetCooldownSuppressesResolve(t *testing.T) {
+	fc := &fakeClock{t: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	rt := startGame(t, counterSceneGraph(1), "scene_counter", func(rt *Runtime) {
+		rt.now = fc.now
+		rt.SetResetCooldown(2 * time.Second)
+	})
+
+	press(rt, "button")
+	if rt.GetPuzzleResolution("puzzle_presses") != PuzzleSolved {
+		t.Fatal("expected puzzle solved before the reset")
+	}
+	if err := rt.ResetNode("puzzle_presses"); err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+
+	// The retained press arrives again right after the reset
+	fc.advance(500 * time.Millisecond)
+	press(rt, "button")
+	press(rt, "other_button")
+	if rt.GetPuzzleResolution("puzzle_presses") != PuzzleUnresolved {
+		t.Fatal("expected the press ignored during the cooldown")
+	}
+	var suppressed []events.Event
+	for _, e := range events.Snapshot() {
+		if e.Name == "puzzle.input_suppressed" {
+			suppressed = append(suppressed, e)
+		}
+	}
+	if len(suppressed) != 1 {
+		t.Fatalf("expected 1 puzzle.input_suppressed for the matching press, got %d", len(suppressed))
+	}
+	if f := suppressed[0].Fields; f["node_id"] != "puzzle_presses" || f["logical_id"] != "button" || f["remaining_ms"] != int64(1500) {
+		t.Errorf("unexpected suppression fields: %v", f)
+	}
+
+	fc.advance(2 * time.Second)
+	press(rt, "button")
+	if rt.GetPuzzleResolution("puzzle_presses") != PuzzleSolved {
+		t.Errorf("expected puzzle solved again after the cooldown, got %s", rt.GetPuzzleResolution("puzzle_presses"))
+	}
+}
+
+func TestResetCooldownSceneOverride(t *testing.T) {
+	sg := counterSceneGraph(1)
+	disabled := 0
+	sg.Scenes[0].ResetCooldownMs = &disabled
+	rt := startGame(t, sg, "scene_counter", func(rt *Runtime) {
+		rt.SetResetCooldown(2 * time.Second)
+	})
+
+	press(rt, "button")
+	if err := rt.ResetNode("puzzle_presses"); err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+	press(rt, "button")
+	if rt.GetPuzzleResolution("puzzle_presses") != PuzzleSolved {
+		t.Errorf("expected the scene to disable the cooldown, got %s", rt.GetPuzzleResolution("puzzle_presses"))
+	}
+}
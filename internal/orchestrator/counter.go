@@ -0,0 +1,68 @@
+package orchestrator
+
+import "github.com/AaronLay10/SentientEngine/internal/events"
+
+// counterVar holds counter values keyed by counter node id, readable in
+// conditions as vars.counter.<node_id>.
+const counterVar = "counter"
+
+// counterThreshold reads a counter's threshold, defaulting to 1.
+func counterThreshold(node *Node) int {
+	if n, ok := configInt(node.Config, "threshold"); ok && n > 0 {
+		return n
+	}
+	return 1
+}
+
+// counterValue returns a counter's current count.
+func (pr *PuzzleRuntime) counterValue(nodeID string) int {
+	counters, _ := pr.vars[counterVar].(map[string]interface{})
+	f, _ := toFloat(counters[nodeID])
+	return int(f)
+}
+
+// setCounter stores a counter value and emits puzzle.progress.
+func (pr *PuzzleRuntime) setCounter(node *Node, count int) {
+	if pr.vars != nil {
+		counters, ok := pr.vars[counterVar].(map[string]interface{})
+		if !ok {
+			counters = make(map[string]interface{})
+			pr.vars[counterVar] = counters
+		}
+		counters[node.ID] = float64(count)
+	} else {
+		// Standalone runtimes without session variables keep their own count
+		pr.vars = map[string]interface{}{
+			counterVar: map[string]interface{}{node.ID: float64(count)},
+		}
+	}
+
+	events.Emit("info", "puzzle.progress", "", map[string]interface{}{
+		"puzzle_id":   pr.parentNodeID,
+		"subgraph_id": pr.subgraph.ID,
+		"node_id":     node.ID,
+		"count":       count,
+		"threshold":   counterThreshold(node),
+	})
+}
+
+// handleCounter applies an event to an active counter node, which counts
+// matching events ("press the button 5 times"). Returns true once the
+// threshold is reached.
+func (pr *PuzzleRuntime) handleCounter(node *Node, ctx *EvalContext) bool {
+	if resetOn, ok := node.Config["reset_on"].(string); ok && resetOn != "" && EvalCondition(resetOn, ctx) {
+		if pr.counterValue(node.ID) != 0 {
+			pr.setCounter(node, 0)
+		}
+		return false
+	}
+
+	match, _ := node.Config["match"].(string)
+	if match == "" || !EvalCondition(match, ctx) {
+		return false
+	}
+
+	count := pr.counterValue(node.ID) + 1
+	pr.setCounter(node, count)
+	return count >= counterThreshold(node)
+}
@@ -0,0 +1,123 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// counterSceneGraph is a puzzle solved by pressing a button threshold times.
+func counterSceneGraph(threshold int) *SceneGraph {
+	scene := puzzleScene("scene_counter", "puzzle_presses", "presses", Node{
+		ID:   "count_presses",
+		Type: "counter",
+		Config: map[string]interface{}{
+			"match":     "event == 'device.input' && logical_id == 'button' && payload.pressed == true",
+			"reset_on":  "event == 'device.input' && logical_id == 'wrong_button'",
+			"threshold": float64(threshold),
+		},
+	})
+	scene.Nodes = append(scene.Nodes, Node{ID: "hint", Type: "gate", Config: map[string]interface{}{
+		"any_of": []interface{}{"vars.counter.count_presses >= 2"},
+	}})
+	return sceneGraph(scene)
+}
+
+func press(rt *Runtime, logicalID string) {
+	rt.InjectEvent("device.input", map[string]interface{}{
+		"logical_id": logicalID,
+		"payload":    map[string]interface{}{"pressed": true},
+	})
+}
+
+// progressCounts returns the counts reported by puzzle.progress events.
+func progressCounts() []interface{} {
+	var counts []interface{}
+	for _, e := range events.Snapshot() {
+		if e.Name == "puzzle.progress" {
+			counts = append(counts, e.Fields["count"])
+		}
+	}
+	return counts
+}
+
+func TestCounterReachesThreshold(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(counterSceneGraph(3))
+	if err := rt.StartGame("scene_counter"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+
+	press(rt, "button")
+	press(rt, "button")
+	if rt.GetPuzzleResolution("puzzle_presses") != PuzzleUnresolved {
+		t.Fatal("expected puzzle unresolved after 2 presses")
+	}
+	if got := rt.Variables()["counter"].(map[string]interface{})["count_presses"]; got != 2.0 {
+		t.Errorf("expected vars.counter.count_presses=2, got %v", got)
+	}
+
+	press(rt, "button")
+	if rt.GetPuzzleResolution("puzzle_presses") != PuzzleSolved {
+		t.Errorf("expected puzzle solved after 3 presses, got %s", rt.GetPuzzleResolution("puzzle_presses"))
+	}
+
+	counts := progressCounts()
+	want := []interface{}{0, 1, 2, 3}
+	if len(counts) != len(want) {
+		t.Fatalf("expected progress %v, got %v", want, counts)
+	}
+	for i := range want {
+		if counts[i] != want[i] {
+			t.Errorf("progress[%d] = %v, want %v", i, counts[i], want[i])
+		}
+	}
+
+	// Further presses after solving are ignored
+	press(rt, "button")
+	if n := len(progressCounts()); n != 4 {
+		t.Errorf("expected no progress after solve, got %d events", n)
+	}
+}
+
+func TestCounterResetOn(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(counterSceneGraph(3))
+	if err := rt.StartGame("scene_counter"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+
+	press(rt, "button")
+	press(rt, "button")
+	press(rt, "wrong_button")
+	press(rt, "button")
+	press(rt, "button")
+	if rt.GetPuzzleResolution("puzzle_presses") != PuzzleUnresolved {
+		t.Fatal("expected reset to require 3 fresh presses")
+	}
+
+	press(rt, "button")
+	if rt.GetPuzzleResolution("puzzle_presses") != PuzzleSolved {
+		t.Errorf("expected puzzle solved, got %s", rt.GetPuzzleResolution("puzzle_presses"))
+	}
+}
+
+func TestCounterVisibleToSceneConditions(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(counterSceneGraph(5))
+	if err := rt.StartGame("scene_counter"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	rt.mu.Lock()
+	rt.activateNode("hint")
+	rt.mu.Unlock()
+
+	press(rt, "button")
+	if rt.GetNodeState("hint") != NodeStateActive {
+		t.Fatalf("expected hint gate waiting, got %s", rt.GetNodeState("hint"))
+	}
+	press(rt, "button")
+	if rt.GetNodeState("hint") != NodeStateCompleted {
+		t.Errorf("expected hint gate open at 2 presses, got %s", rt.GetNodeState("hint"))
+	}
+}
@@ -0,0 +1,63 @@
+package orchestrator
+
+import "time"
+
+// WaitAction is the action name for a pure delay, handled by the runtime itself.
+// With delay_ms on action nodes it paces a sequence in wall-clock time
+// instead of in firmware.
+const WaitAction = "wait"
+
+// actionKey returns the scheduler key for a delayed action.
+func actionKey(key string) string {
+	return "action:" + key
+}
+
+// actionDelay returns how long an action node waits before completing.
+func actionDelay(config map[string]interface{}) time.Duration {
+	ms, _ := configInt(config, "delay_ms")
+	if action, _ := config["action"].(string); action == WaitAction {
+		params, _ := config["params"].(map[string]interface{})
+		duration, _ := configInt(params, "duration_ms")
+		ms += duration
+	}
+	if ms <= 0 {
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// cancelActions stops pending delayed actions of a node and of the subgraph it runs.
+func (r *Runtime) cancelActions(nodeID string) {
+	r.cancelScheduled(actionKey(nodeID))
+	r.cancelScheduledPrefix(actionKey(nodeID + "/"))
+}
+
+// subgraphScheduleFunc runs delayed subgraph actions on the runtime clock and
+// completes the parent node if the subgraph finished as a result.
+func (r *Runtime) subgraphScheduleFunc(parentID string) ScheduleFunc {
+	return func(nodeID string, d time.Duration, fn func()) {
+		r.schedule(actionKey(parentID+"/"+nodeID), d, func() {
+			fn()
+			r.finishSubgraphStep(parentID)
+			r.evaluateAllConditions()
+		})
+	}
+}
+
+// finishSubgraphStep completes a parent node whose subgraph finished outside
+// event handling.
+func (r *Runtime) finishSubgraphStep(parentID string) {
+	status := r.nodeStates[parentID]
+	if status == nil || status.State != NodeStateActive {
+		return
+	}
+	if pr, ok := r.puzzleRuntimes[parentID]; ok && pr.Done() {
+		r.stopPuzzleLimits(parentID)
+		r.puzzleStates[parentID].Resolution = pr.Resolution()
+		r.completeNode(parentID)
+	}
+	if sr, ok := r.subgraphs[parentID]; ok && sr.Done() {
+		delete(r.subgraphs, parentID)
+		r.completeNode(parentID)
+	}
+}
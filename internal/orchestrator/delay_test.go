@@ -0,0 +1,158 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// delaySceneGraph plays audio, waits, then unlocks a door after a further delay.
+// The reset routine subgraph does the same with a wait between two commands.
+func delaySceneGraph() *SceneGraph {
+	command := func(deviceID string) map[string]interface{} {
+		return map[string]interface{}{
+			"action": "device.command",
+			"params": map[string]interface{}{"device_id": deviceID, "signal": "on"},
+		}
+	}
+	unlock := command("door")
+	unlock["delay_ms"] = float64(2000)
+	wait := map[string]interface{}{
+		"action": WaitAction,
+		"params": map[string]interface{}{"duration_ms": float64(10000)},
+	}
+	return sceneGraph(Scene{
+		ID:    "scene_delay",
+		Entry: "intro",
+		Nodes: []Node{
+			{ID: "intro", Type: "action", Config: command("speaker")},
+			{ID: "pause", Type: "action", Config: wait},
+			{ID: "unlock", Type: "action", Config: unlock},
+			{ID: "routine", Type: "subgraph", Config: map[string]interface{}{"subgraph": "sub_routine"}},
+			{ID: "done", Type: "operator", Config: map[string]interface{}{}},
+		},
+		Edges: []Edge{
+			{From: "intro", To: "pause"},
+			{From: "pause", To: "unlock"},
+			{From: "unlock", To: "routine"},
+			{From: "routine", To: "done"},
+		},
+		Subgraphs: []Subgraph{
+			{
+				ID:    "sub_routine",
+				Entry: "fog",
+				Nodes: []Node{
+					{ID: "fog", Type: "action", Config: command("fog")},
+					{ID: "hold", Type: "action", Config: wait},
+					{ID: "lights", Type: "action", Config: command("lights")},
+					{ID: "end", Type: "terminal"},
+				},
+				Edges: []Edge{
+					{From: "fog", To: "hold"},
+					{From: "hold", To: "lights"},
+					{From: "lights", To: "end"},
+				},
+			},
+		},
+	})
+}
+
+// fire runs a pending scheduled task immediately.
+func fire(t *testing.T, rt *Runtime, key string) {
+	t.Helper()
+	rt.mu.Lock()
+	fired := rt.fireScheduled(key)
+	rt.mu.Unlock()
+	if !fired {
+		t.Fatalf("expected %s to be scheduled", key)
+	}
+}
+
+func TestDelayedActions(t *testing.T) {
+	events.Clear()
+	exec := &replyingExecutor{}
+	rt := NewRuntime(delaySceneGraph())
+	rt.SetActionExecutor(exec)
+
+	if err := rt.StartGame("scene_delay"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	if rt.GetNodeState("pause") != NodeStateActive {
+		t.Fatalf("expected pause active while waiting, got %s", rt.GetNodeState("pause"))
+	}
+
+	fire(t, rt, actionKey("pause"))
+	if rt.GetNodeState("unlock") != NodeStateActive {
+		t.Fatalf("expected unlock active during its delay, got %s", rt.GetNodeState("unlock"))
+	}
+	exec.mu.Lock()
+	sent := len(exec.commands)
+	exec.mu.Unlock()
+	if sent != 1 {
+		t.Fatalf("expected only the intro command before the delay, got %d", sent)
+	}
+
+	fire(t, rt, actionKey("unlock"))
+	if rt.GetNodeState("routine") != NodeStateActive {
+		t.Fatalf("expected routine running, got %s", rt.GetNodeState("routine"))
+	}
+
+	fire(t, rt, actionKey("routine/hold"))
+	if rt.GetNodeState("routine") != NodeStateCompleted {
+		t.Errorf("expected routine completed after its wait, got %s", rt.GetNodeState("routine"))
+	}
+	if rt.GetNodeState("done") != NodeStateActive {
+		t.Errorf("expected done active, got %s", rt.GetNodeState("done"))
+	}
+
+	exec.mu.Lock()
+	got := exec.commands
+	exec.mu.Unlock()
+	want := []string{"speaker:on", "door:on", "fog:on", "lights:on"}
+	if len(got) != len(want) {
+		t.Fatalf("expected commands %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("command[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDelayedActionsCancelledOnReset(t *testing.T) {
+	events.Clear()
+	exec := &replyingExecutor{}
+	rt := NewRuntime(delaySceneGraph())
+	rt.SetActionExecutor(exec)
+
+	if err := rt.StartGame("scene_delay"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	fire(t, rt, actionKey("pause"))
+	fire(t, rt, actionKey("unlock"))
+
+	// Resetting the subgraph node drops its pending wait
+	if err := rt.ResetToNode("intro"); err != nil {
+		t.Fatalf("ResetToNode failed: %v", err)
+	}
+	rt.mu.Lock()
+	_, pending := rt.tasks[actionKey("routine/hold")]
+	rt.mu.Unlock()
+	if pending {
+		t.Error("expected subgraph wait cancelled by reset")
+	}
+	if rt.GetNodeState("pause") != NodeStateActive {
+		t.Errorf("expected pause waiting again, got %s", rt.GetNodeState("pause"))
+	}
+
+	// Stopping the game drops the pending wait
+	if err := rt.StopGame(); err != nil {
+		t.Fatalf("failed to stop game: %v", err)
+	}
+	rt.mu.Lock()
+	_, pending = rt.tasks[actionKey("pause")]
+	rt.mu.Unlock()
+	if pending {
+		t.Error("expected wait cancelled when the game stops")
+	}
+}
@@ -0,0 +1,176 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DeviceRef is one place a scene graph names a device: in device command
+// params, hints and expect lists, or in a condition.
+type DeviceRef struct {
+	LogicalID string `json:"logical_id"`
+	Where     string `json:"where"` // e.g. "scene scene_intro node unlock params"
+}
+
+// GraphDeviceRefs returns every device reference in a scene graph, in graph
+// order.
+func GraphDeviceRefs(sg *SceneGraph) []DeviceRef {
+	var refs []DeviceRef
+	for _, t := range sg.Transitions {
+		refs = append(refs, conditionDeviceRefs(t.Condition, fmt.Sprintf("transition %s -> %s", t.From, t.To))...)
+	}
+	for _, scene := range sg.Scenes {
+		where := "scene " + scene.ID
+		refs = append(refs, edgeDeviceRefs(scene.Edges, where)...)
+		for _, inv := range scene.Invariants {
+			refs = append(refs, conditionDeviceRefs(inv.Condition, where+" invariant "+inv.Name)...)
+		}
+		refs = append(refs, nodeDeviceRefs(scene.Nodes, where)...)
+
+		for _, sub := range scene.Subgraphs {
+			subWhere := where + " subgraph " + sub.ID
+			refs = append(refs, edgeDeviceRefs(sub.Edges, subWhere)...)
+			refs = append(refs, nodeDeviceRefs(sub.Nodes, subWhere)...)
+		}
+	}
+	return refs
+}
+
+// ValidateDeviceRefs checks that every device the graph names is one of
+// devices, and returns every unknown reference joined into one error, so a
+// misspelt device is caught before the graph is deployed instead of when its
+// node runs.
+func ValidateDeviceRefs(sg *SceneGraph, devices map[string]bool) error {
+	var errs []error
+	for _, ref := range UnknownDeviceRefs(sg, devices) {
+		errs = append(errs, fmt.Errorf("%s: unknown device %q", ref.Where, ref.LogicalID))
+	}
+	return errors.Join(errs...)
+}
+
+// UnknownDeviceRefs returns the references to devices not in devices, in
+// graph order.
+func UnknownDeviceRefs(sg *SceneGraph, devices map[string]bool) []DeviceRef {
+	var unknown []DeviceRef
+	for _, ref := range GraphDeviceRefs(sg) {
+		if !devices[ref.LogicalID] {
+			unknown = append(unknown, ref)
+		}
+	}
+	return unknown
+}
+
+// Problem describes a reference to an unknown device for operators.
+func (r DeviceRef) Problem() string {
+	return fmt.Sprintf("graph references unknown device '%s' (%s)", r.LogicalID, r.Where)
+}
+
+// UnusedDevices returns the devices no reference in the graph names, sorted.
+func UnusedDevices(sg *SceneGraph, devices map[string]bool) []string {
+	used := make(map[string]bool)
+	for _, ref := range GraphDeviceRefs(sg) {
+		used[ref.LogicalID] = true
+	}
+	var unused []string
+	for id := range devices {
+		if !used[id] {
+			unused = append(unused, id)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+// edgeDeviceRefs returns the devices named by edge conditions.
+func edgeDeviceRefs(edges []Edge, where string) []DeviceRef {
+	var refs []DeviceRef
+	for _, edge := range edges {
+		refs = append(refs, conditionDeviceRefs(edge.Condition, fmt.Sprintf("%s edge %s -> %s", where, edge.From, edge.To))...)
+	}
+	return refs
+}
+
+// nodeDeviceRefs returns the devices named by node configs.
+func nodeDeviceRefs(nodes []Node, where string) []DeviceRef {
+	var refs []DeviceRef
+	for _, node := range nodes {
+		nodeWhere := where + " node " + node.ID
+		refs = append(refs, paramsDeviceRef(node.Config, nodeWhere+" params")...)
+		if list, ok := actionList(node.Config); ok {
+			for i, entry := range list {
+				if m, ok := entry.(map[string]interface{}); ok {
+					refs = append(refs, paramsDeviceRef(m, fmt.Sprintf("%s actions[%d]", nodeWhere, i))...)
+				}
+			}
+		}
+		for _, key := range []string{"hints", "expect"} {
+			list, _ := node.Config[key].([]interface{})
+			for i, entry := range list {
+				m, _ := entry.(map[string]interface{})
+				if id, _ := m["device_id"].(string); id != "" {
+					refs = append(refs, DeviceRef{LogicalID: id, Where: fmt.Sprintf("%s %s[%d]", nodeWhere, key, i)})
+				}
+			}
+		}
+
+		for _, key := range nodeConditionKeys {
+			if expr, ok := node.Config[key].(string); ok {
+				refs = append(refs, conditionDeviceRefs(expr, nodeWhere+" "+key)...)
+			}
+		}
+		for _, key := range []string{"all_of", "any_of"} {
+			for _, entry := range configStrings(node.Config, key) {
+				refs = append(refs, conditionDeviceRefs(entry, nodeWhere+" "+key)...)
+			}
+		}
+	}
+	return refs
+}
+
+// paramsDeviceRef returns the device an action's params name, if any.
+func paramsDeviceRef(config map[string]interface{}, where string) []DeviceRef {
+	params, _ := config["params"].(map[string]interface{})
+	if id, _ := params["device_id"].(string); id != "" {
+		return []DeviceRef{{LogicalID: id, Where: where}}
+	}
+	return nil
+}
+
+// conditionDeviceRefs returns the devices a condition names. Conditions that
+// do not compile name none (ValidateConditions reports them).
+func conditionDeviceRefs(expr, where string) []DeviceRef {
+	cond, err := CompileCondition(strings.TrimSpace(expr))
+	if err != nil || cond.root == nil {
+		return nil
+	}
+	var refs []DeviceRef
+	var walk func(n exprNode)
+	walk = func(n exprNode) {
+		switch n := n.(type) {
+		case *orNode:
+			for _, t := range n.terms {
+				walk(t)
+			}
+		case *andNode:
+			for _, t := range n.terms {
+				walk(t)
+			}
+		case *notNode:
+			walk(n.term)
+		case *compareNode:
+			if n.field == "logical_id" {
+				refs = append(refs, DeviceRef{LogicalID: n.value, Where: where})
+			} else if id, _, ok := payloadFieldRef(n.field); ok && id != "" {
+				refs = append(refs, DeviceRef{LogicalID: id, Where: where})
+			}
+		case *varNode:
+			if id, _, ok := payloadFieldRef(n.field); ok && id != "" {
+				refs = append(refs, DeviceRef{LogicalID: id, Where: where})
+			}
+		}
+	}
+	walk(cond.root)
+	return refs
+}
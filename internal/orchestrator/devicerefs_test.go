@@ -0,0 +1,48 @@
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGraphDeviceRefs(t *testing.T) {
+	sg := counterSceneGraph(3)
+	scene := &sg.Scenes[0]
+	scene.Nodes = append(scene.Nodes,
+		Node{ID: "unlock", Type: "action", Config: map[string]interface{}{
+			"action": "device.command",
+			"params": map[string]interface{}{"device_id": "crypt_door", "signal": "unlock"},
+		}},
+		Node{ID: "blackout", Type: "action", Config: map[string]interface{}{
+			"actions": []interface{}{
+				map[string]interface{}{"action": "device.command", "params": map[string]interface{}{"device_id": "lights", "signal": "off"}},
+				map[string]interface{}{"action": "audio.play", "params": map[string]interface{}{"file": "thunder.wav"}},
+			},
+			"expect": []interface{}{map[string]interface{}{"device_id": "lights", "field": "level", "value": float64(0)}},
+		}},
+	)
+	scene.Nodes[0].Config["hints"] = []interface{}{map[string]interface{}{"text": "Press it", "device_id": "hint_screen", "signal": "show"}}
+	scene.Edges = append(scene.Edges, Edge{From: "puzzle_presses", To: "unlock", Condition: "state.altar.lit == true"})
+
+	var got []string
+	for _, ref := range GraphDeviceRefs(sg) {
+		got = append(got, ref.LogicalID)
+	}
+	want := "altar,hint_screen,crypt_door,lights,lights,button,wrong_button"
+	if strings.Join(got, ",") != want {
+		t.Errorf("expected refs %s, got %v", want, got)
+	}
+
+	devices := map[string]bool{"button": true, "wrong_button": true, "crypt_door": true, "lights": true, "hint_screen": true, "fog": true}
+	err := ValidateDeviceRefs(sg, devices)
+	if err == nil || !strings.Contains(err.Error(), `scene scene_counter edge puzzle_presses -> unlock: unknown device "altar"`) {
+		t.Errorf("expected the unknown altar reported, got %v", err)
+	}
+	devices["altar"] = true
+	if err := ValidateDeviceRefs(sg, devices); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if unused := UnusedDevices(sg, devices); len(unused) != 1 || unused[0] != "fog" {
+		t.Errorf("expected fog unused, got %v", unused)
+	}
+}
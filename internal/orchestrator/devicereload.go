@@ -0,0 +1,168 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/mqtt"
+)
+
+// DevicesReload summarizes an applied devices.yaml reload.
+type DevicesReload struct {
+	Devices     int                           `json:"devices"`
+	Added       []string                      `json:"added"`
+	Removed     []string                      `json:"removed"`
+	Controllers []mqtt.ControllerRevalidation `json:"controllers"`
+
+	UnknownDevices []DeviceRef `json:"unknown_devices,omitempty"` // graph references the new file does not declare
+}
+
+// LatchedFieldSetter takes the latched payload fields of devices.yaml
+// (implemented by *mqtt.DeviceSubscriber).
+type LatchedFieldSetter interface {
+	SetLatchedFields(latched map[string][]string)
+}
+
+// DevicesReloader re-reads devices.yaml and applies it.
+type DevicesReloader struct {
+	mu       sync.Mutex
+	path     string
+	graph    *SceneGraph
+	current  *config.DevicesConfig
+	monitor  *mqtt.Monitor
+	executor *ActionExecutor
+	latched  LatchedFieldSetter
+	strict   bool        // reject files missing devices the graph names
+	unknown  []DeviceRef // graph references current does not declare
+}
+
+// NewDevicesReloader creates a reloader for the devices.yaml at path, loaded
+// at startup as current and checked against the scene graph on reload.
+func NewDevicesReloader(path string, current *config.DevicesConfig, sg *SceneGraph, monitor *mqtt.Monitor, executor *ActionExecutor) *DevicesReloader {
+	d := &DevicesReloader{
+		path:     path,
+		graph:    sg,
+		current:  current,
+		monitor:  monitor,
+		executor: executor,
+	}
+	if sg != nil {
+		d.unknown = UnknownDeviceRefs(sg, DeclaredDevices(current))
+	}
+	return d
+}
+
+// SetStrictDeviceRefs sets whether a reload is rejected when the scene graph
+// names devices the new file does not declare.
+func (d *DevicesReloader) SetStrictDeviceRefs(strict bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.strict = strict
+}
+
+// UnknownDeviceRefs returns the scene graph's references to devices the
+// current devices.yaml does not declare.
+func (d *DevicesReloader) UnknownDeviceRefs() []DeviceRef {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.unknown
+}
+
+// SetLatchedFieldSetter sets where latched fields are applied on reload.
+func (d *DevicesReloader) SetLatchedFieldSetter(s LatchedFieldSetter) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.latched = s
+}
+
+// Reload re-reads devices.yaml and applies it, so adding or changing a prop
+// does not need a restart. A file that fails to load, whose payload types no
+// longer fit the scene graph, or (with strict device refs) that drops a
+// device the graph names changes nothing. Otherwise command signals, payload
+// types, latched fields and controller specs are replaced and every
+// controller's last registration is re-validated.
+func (d *DevicesReloader) Reload() (*DevicesReload, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	cfg, err := config.LoadDevicesConfig(d.path)
+	if err != nil {
+		return nil, err
+	}
+	types := PayloadTypesFromConfig(cfg)
+	var unknown []DeviceRef
+	if d.graph != nil {
+		if err := ValidatePayloadTypes(d.graph, types); err != nil {
+			return nil, fmt.Errorf("scene graph does not fit the new devices.yaml: %w", err)
+		}
+		unknown = UnknownDeviceRefs(d.graph, DeclaredDevices(cfg))
+		if d.strict && len(unknown) > 0 {
+			return nil, fmt.Errorf("scene graph does not fit the new devices.yaml: %w", ValidateDeviceRefs(d.graph, DeclaredDevices(cfg)))
+		}
+	}
+
+	SetPayloadTypes(types)
+	if d.executor != nil {
+		d.executor.SetDevicesConfig(cfg)
+	}
+	if d.latched != nil {
+		d.latched.SetLatchedFields(cfg.LatchedFields())
+	}
+
+	result := &DevicesReload{
+		Devices:        len(cfg.Devices),
+		Added:          []string{},
+		Removed:        []string{},
+		UnknownDevices: unknown,
+	}
+	for id := range cfg.Devices {
+		if _, ok := d.current.Devices[id]; !ok {
+			result.Added = append(result.Added, id)
+		}
+	}
+	for id := range d.current.Devices {
+		if _, ok := cfg.Devices[id]; !ok {
+			result.Removed = append(result.Removed, id)
+		}
+	}
+	sort.Strings(result.Added)
+	sort.Strings(result.Removed)
+
+	result.Controllers = d.monitor.ReloadSpecs(DeviceSpecsFromConfig(cfg))
+	d.current = cfg
+	d.unknown = unknown
+	return result, nil
+}
+
+// DeclaredDevices returns the logical IDs devices.yaml declares.
+func DeclaredDevices(cfg *config.DevicesConfig) map[string]bool {
+	ids := make(map[string]bool, len(cfg.Devices))
+	for id := range cfg.Devices {
+		ids[id] = true
+	}
+	return ids
+}
+
+// DeviceSpecsFromConfig converts devices.yaml to the specs controllers'
+// registrations are validated against.
+func DeviceSpecsFromConfig(cfg *config.DevicesConfig) map[string]mqtt.DeviceSpec {
+	specs := make(map[string]mqtt.DeviceSpec, len(cfg.Devices))
+	for id, dev := range cfg.Devices {
+		specs[id] = mqtt.DeviceSpecFromConfig(dev.Type, dev.Required, dev.Capabilities)
+	}
+	return specs
+}
+
+// PowerSpecsFromConfig returns the smart plug mapping of each device with
+// power telemetry in devices.yaml.
+func PowerSpecsFromConfig(cfg *config.DevicesConfig) map[string]mqtt.PowerSpec {
+	specs := make(map[string]mqtt.PowerSpec)
+	for id, dev := range cfg.Devices {
+		if dev.Power != nil {
+			specs[id] = mqtt.PowerSpec{Topic: dev.Power.Topic, Field: dev.Power.Field, MinWatts: dev.Power.MinWatts}
+		}
+	}
+	return specs
+}
@@ -0,0 +1,96 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/mqtt"
+)
+
+const reloadDevicesYAML = `version: 1
+devices:
+  button:
+    type: button
+    signals:
+      outputs: [led]
+    payload:
+      pressed: %s
+  %s:
+    type: sensor
+`
+
+func writeDevicesYAML(t *testing.T, path, pressedType, extra string) {
+	t.Helper()
+	body := []byte(fmt.Sprintf(reloadDevicesYAML, pressedType, extra))
+	if err := os.WriteFile(path, body, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDevicesReloader(t *testing.T) {
+	defer SetPayloadTypes(nil)
+
+	path := filepath.Join(t.TempDir(), "devices.yaml")
+	writeDevicesYAML(t, path, "bool", "wrong_button")
+	cfg, err := config.LoadDevicesConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	monitor := mqtt.NewMonitor(DeviceSpecsFromConfig(cfg), 2.0)
+	executor := &ActionExecutor{devicesConfig: cfg}
+	reloader := NewDevicesReloader(path, cfg, counterSceneGraph(2), monitor, executor)
+	if unknown := reloader.UnknownDeviceRefs(); len(unknown) != 0 {
+		t.Errorf("expected every device the graph names declared, got %+v", unknown)
+	}
+
+	writeDevicesYAML(t, path, "bool", "altar")
+	reload, err := reloader.Reload()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reload.Devices != 2 || len(reload.Added) != 1 || reload.Added[0] != "altar" ||
+		len(reload.Removed) != 1 || reload.Removed[0] != "wrong_button" {
+		t.Errorf("unexpected reload summary: %+v", reload)
+	}
+	if _, ok := executor.devices().Devices["altar"]; !ok {
+		t.Error("expected the executor to use the reloaded devices.yaml")
+	}
+	if len(reload.UnknownDevices) == 0 || reload.UnknownDevices[0].LogicalID != "wrong_button" ||
+		len(reloader.UnknownDeviceRefs()) != len(reload.UnknownDevices) {
+		t.Errorf("expected the graph's wrong_button reported unknown, got %+v", reload.UnknownDevices)
+	}
+
+	// Strict: dropping a device the graph names is rejected
+	reloader.SetStrictDeviceRefs(true)
+	if _, err := reloader.Reload(); err == nil {
+		t.Error("expected the reload rejected while the graph names wrong_button")
+	}
+	writeDevicesYAML(t, path, "bool", "wrong_button")
+	if _, err := reloader.Reload(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if unknown := reloader.UnknownDeviceRefs(); len(unknown) != 0 {
+		t.Errorf("expected no unknown devices, got %+v", unknown)
+	}
+	reloader.SetStrictDeviceRefs(false)
+
+	// A payload type the graph cannot compare against leaves everything as it was
+	writeDevicesYAML(t, path, "int", "lights")
+	if _, err := reloader.Reload(); err == nil {
+		t.Fatal("expected the reload rejected")
+	}
+	if _, ok := executor.devices().Devices["wrong_button"]; !ok {
+		t.Error("expected a rejected reload to keep the previous devices.yaml")
+	}
+
+	if err := os.WriteFile(path, []byte("version: 2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reloader.Reload(); err == nil {
+		t.Error("expected an unsupported devices.yaml rejected")
+	}
+}
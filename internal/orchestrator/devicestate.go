@@ -0,0 +1,59 @@
+package orchestrator
+
+import (
+	"strings"
+	"sync"
+)
+
+// DeviceStateSource reports last known device values (implemented by
+// *mqtt.DeviceSubscriber), so conditions can ask "is the door closed" as
+// state.<logical_id>.<field> rather than only react to the moment it closed.
+// Device state outlives sessions but is unknown after a restart until the
+// device reports again.
+type DeviceStateSource interface {
+	LastValue(logicalID, field string) (interface{}, bool)
+}
+
+// statePrefix marks identifiers that read device state.
+const statePrefix = "state."
+
+// deviceStates holds the source read by condition evaluation.
+var deviceStates struct {
+	sync.RWMutex
+	source DeviceStateSource
+}
+
+// SetDeviceStates sets the source of device state for conditions.
+// nil clears it.
+func SetDeviceStates(source DeviceStateSource) {
+	deviceStates.Lock()
+	defer deviceStates.Unlock()
+	deviceStates.source = source
+}
+
+// stateFieldRef splits state.<logical_id>.<field> into its device and field.
+func stateFieldRef(field string) (logicalID, name string, ok bool) {
+	rest, ok := strings.CutPrefix(field, statePrefix)
+	if !ok {
+		return "", "", false
+	}
+	logicalID, name, ok = strings.Cut(rest, ".")
+	return logicalID, name, ok && logicalID != "" && name != ""
+}
+
+// deviceState returns the last known value of state.<logical_id>.<field>, or
+// nil if it is unknown.
+func deviceState(field string) interface{} {
+	logicalID, name, ok := stateFieldRef(field)
+	if !ok {
+		return nil
+	}
+	deviceStates.RLock()
+	source := deviceStates.source
+	deviceStates.RUnlock()
+	if source == nil {
+		return nil
+	}
+	v, _ := source.LastValue(logicalID, name)
+	return v
+}
@@ -0,0 +1,44 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+)
+
+// fakeDeviceStates is a fixed set of device values keyed by logical_id.field.
+type fakeDeviceStates map[string]interface{}
+
+func (f fakeDeviceStates) LastValue(logicalID, field string) (interface{}, bool) {
+	v, ok := f[logicalID+"."+field]
+	return v, ok
+}
+
+func TestLatchedStateConditions(t *testing.T) {
+	SetDeviceStates(fakeDeviceStates{"crypt_door.door_closed": "true", "lever.position": "down"})
+	defer SetDeviceStates(nil)
+	SetPayloadTypes(PayloadTypes{"crypt_door": {"door_closed": config.PayloadBool}})
+	defer SetPayloadTypes(nil)
+
+	// The event being handled comes from another device
+	ctx := &EvalContext{Event: &Event{Name: "device.input", Fields: map[string]interface{}{"logical_id": "button"}}}
+	for expr, want := range map[string]bool{
+		"state.crypt_door.door_closed == true":                           true,
+		"state.crypt_door.door_closed == 'true'":                         true,
+		"state.crypt_door.door_closed":                                   true,
+		"state.crypt_door.door_closed && state.lever.position == 'down'": true,
+		"state.crypt_door.door_closed == false":                          false,
+		"state.altar.lit":                                                false,
+		"logical_id == 'button' && state.lever.position == 'up'":         false,
+	} {
+		if got := EvalCondition(expr, ctx); got != want {
+			t.Errorf("%s: expected %v, got %v", expr, want, got)
+		}
+	}
+
+	sg := counterSceneGraph(1)
+	sg.Scenes[0].Nodes[1].Config["any_of"] = []interface{}{"state.crypt_door.door_closed == 'open'"}
+	if err := ValidatePayloadTypes(sg, PayloadTypes{"crypt_door": {"door_closed": config.PayloadBool}}); err == nil {
+		t.Error("expected a latched bool compared with text to be rejected")
+	}
+}
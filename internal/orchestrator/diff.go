@@ -0,0 +1,349 @@
+package orchestrator
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// ChangeKind describes how an element differs between two graph revisions.
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// GraphDiff is a semantic diff between two scene graph revisions.
+type GraphDiff struct {
+	Version *FieldChange `json:"version,omitempty"`
+	Scenes  []SceneDiff  `json:"scenes"`
+}
+
+// SceneDiff describes changes to a single scene.
+type SceneDiff struct {
+	SceneID   string         `json:"scene_id"`
+	Change    ChangeKind     `json:"change"`
+	Fields    []FieldChange  `json:"fields,omitempty"`
+	Nodes     []NodeDiff     `json:"nodes,omitempty"`
+	Edges     []EdgeDiff     `json:"edges,omitempty"`
+	Subgraphs []SubgraphDiff `json:"subgraphs,omitempty"`
+}
+
+// SubgraphDiff describes changes to a puzzle subgraph.
+type SubgraphDiff struct {
+	SubgraphID string        `json:"subgraph_id"`
+	Change     ChangeKind    `json:"change"`
+	Fields     []FieldChange `json:"fields,omitempty"`
+	Nodes      []NodeDiff    `json:"nodes,omitempty"`
+	Edges      []EdgeDiff    `json:"edges,omitempty"`
+}
+
+// NodeDiff describes changes to a node.
+type NodeDiff struct {
+	NodeID string        `json:"node_id"`
+	Type   string        `json:"type"`
+	Change ChangeKind    `json:"change"`
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// EdgeDiff describes changes to an edge. Edges are identified by from/to.
+type EdgeDiff struct {
+	From         string     `json:"from"`
+	To           string     `json:"to"`
+	Change       ChangeKind `json:"change"`
+	OldCondition string     `json:"old_condition,omitempty"`
+	NewCondition string     `json:"new_condition,omitempty"`
+}
+
+// FieldChange records the old and new value of a single field.
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old,omitempty"`
+	New   interface{} `json:"new,omitempty"`
+}
+
+// IsEmpty returns true if the two graphs are semantically identical.
+func (d *GraphDiff) IsEmpty() bool {
+	return d.Version == nil && len(d.Scenes) == 0
+}
+
+// DiffSceneGraphs computes a semantic diff from graph a (old) to graph b (new).
+// Results are sorted by ID so the output is stable between runs.
+func DiffSceneGraphs(a, b *SceneGraph) *GraphDiff {
+	diff := &GraphDiff{Scenes: []SceneDiff{}}
+
+	if a.Version != b.Version {
+		diff.Version = &FieldChange{Field: "version", Old: a.Version, New: b.Version}
+	}
+
+	oldScenes := make(map[string]*Scene)
+	for i := range a.Scenes {
+		oldScenes[a.Scenes[i].ID] = &a.Scenes[i]
+	}
+	newScenes := make(map[string]*Scene)
+	for i := range b.Scenes {
+		newScenes[b.Scenes[i].ID] = &b.Scenes[i]
+	}
+
+	for _, id := range unionKeys(oldScenes, newScenes) {
+		oldScene, inOld := oldScenes[id]
+		newScene, inNew := newScenes[id]
+
+		switch {
+		case !inOld:
+			diff.Scenes = append(diff.Scenes, SceneDiff{
+				SceneID: id,
+				Change:  ChangeAdded,
+				Nodes:   diffNodes(nil, newScene.Nodes),
+				Edges:   diffEdges(nil, newScene.Edges),
+			})
+		case !inNew:
+			diff.Scenes = append(diff.Scenes, SceneDiff{SceneID: id, Change: ChangeRemoved})
+		default:
+			sd := SceneDiff{SceneID: id, Change: ChangeChanged}
+			sd.Fields = appendFieldChange(sd.Fields, "name", oldScene.Name, newScene.Name)
+			sd.Fields = appendFieldChange(sd.Fields, "entry", oldScene.Entry, newScene.Entry)
+			sd.Fields = appendFieldChange(sd.Fields, "notes", oldScene.Notes, newScene.Notes)
+			sd.Nodes = diffNodes(oldScene.Nodes, newScene.Nodes)
+			sd.Edges = diffEdges(oldScene.Edges, newScene.Edges)
+			sd.Subgraphs = diffSubgraphs(oldScene.Subgraphs, newScene.Subgraphs)
+			if len(sd.Fields) > 0 || len(sd.Nodes) > 0 || len(sd.Edges) > 0 || len(sd.Subgraphs) > 0 {
+				diff.Scenes = append(diff.Scenes, sd)
+			}
+		}
+	}
+
+	return diff
+}
+
+func diffSubgraphs(a, b []Subgraph) []SubgraphDiff {
+	oldSubs := make(map[string]*Subgraph)
+	for i := range a {
+		oldSubs[a[i].ID] = &a[i]
+	}
+	newSubs := make(map[string]*Subgraph)
+	for i := range b {
+		newSubs[b[i].ID] = &b[i]
+	}
+
+	var result []SubgraphDiff
+	for _, id := range unionKeys(oldSubs, newSubs) {
+		oldSub, inOld := oldSubs[id]
+		newSub, inNew := newSubs[id]
+
+		switch {
+		case !inOld:
+			result = append(result, SubgraphDiff{
+				SubgraphID: id,
+				Change:     ChangeAdded,
+				Nodes:      diffNodes(nil, newSub.Nodes),
+				Edges:      diffEdges(nil, newSub.Edges),
+			})
+		case !inNew:
+			result = append(result, SubgraphDiff{SubgraphID: id, Change: ChangeRemoved})
+		default:
+			sd := SubgraphDiff{SubgraphID: id, Change: ChangeChanged}
+			sd.Fields = appendFieldChange(sd.Fields, "entry", oldSub.Entry, newSub.Entry)
+			if !reflect.DeepEqual(oldSub.Outputs, newSub.Outputs) {
+				sd.Fields = append(sd.Fields, FieldChange{Field: "outputs", Old: oldSub.Outputs, New: newSub.Outputs})
+			}
+			sd.Nodes = diffNodes(oldSub.Nodes, newSub.Nodes)
+			sd.Edges = diffEdges(oldSub.Edges, newSub.Edges)
+			if len(sd.Fields) > 0 || len(sd.Nodes) > 0 || len(sd.Edges) > 0 {
+				result = append(result, sd)
+			}
+		}
+	}
+	return result
+}
+
+func diffNodes(a, b []Node) []NodeDiff {
+	oldNodes := make(map[string]*Node)
+	for i := range a {
+		oldNodes[a[i].ID] = &a[i]
+	}
+	newNodes := make(map[string]*Node)
+	for i := range b {
+		newNodes[b[i].ID] = &b[i]
+	}
+
+	var result []NodeDiff
+	for _, id := range unionKeys(oldNodes, newNodes) {
+		oldNode, inOld := oldNodes[id]
+		newNode, inNew := newNodes[id]
+
+		switch {
+		case !inOld:
+			result = append(result, NodeDiff{NodeID: id, Type: newNode.Type, Change: ChangeAdded})
+		case !inNew:
+			result = append(result, NodeDiff{NodeID: id, Type: oldNode.Type, Change: ChangeRemoved})
+		default:
+			nd := NodeDiff{NodeID: id, Type: newNode.Type, Change: ChangeChanged}
+			nd.Fields = appendFieldChange(nd.Fields, "type", oldNode.Type, newNode.Type)
+			nd.Fields = appendFieldChange(nd.Fields, "notes", oldNode.Notes, newNode.Notes)
+			for _, key := range unionKeys(oldNode.Config, newNode.Config) {
+				oldVal, inOldCfg := oldNode.Config[key]
+				newVal, inNewCfg := newNode.Config[key]
+				if inOldCfg && inNewCfg && reflect.DeepEqual(oldVal, newVal) {
+					continue
+				}
+				nd.Fields = append(nd.Fields, FieldChange{Field: "config." + key, Old: oldVal, New: newVal})
+			}
+			if len(nd.Fields) > 0 {
+				result = append(result, nd)
+			}
+		}
+	}
+	return result
+}
+
+func diffEdges(a, b []Edge) []EdgeDiff {
+	type edgeKey struct{ from, to string }
+
+	oldEdges := make(map[edgeKey]Edge)
+	for _, e := range a {
+		oldEdges[edgeKey{e.From, e.To}] = e
+	}
+	newEdges := make(map[edgeKey]Edge)
+	for _, e := range b {
+		newEdges[edgeKey{e.From, e.To}] = e
+	}
+
+	keys := make([]edgeKey, 0, len(oldEdges)+len(newEdges))
+	for k := range oldEdges {
+		keys = append(keys, k)
+	}
+	for k := range newEdges {
+		if _, ok := oldEdges[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].from != keys[j].from {
+			return keys[i].from < keys[j].from
+		}
+		return keys[i].to < keys[j].to
+	})
+
+	var result []EdgeDiff
+	for _, k := range keys {
+		oldEdge, inOld := oldEdges[k]
+		newEdge, inNew := newEdges[k]
+
+		switch {
+		case !inOld:
+			result = append(result, EdgeDiff{From: k.from, To: k.to, Change: ChangeAdded, NewCondition: newEdge.Condition})
+		case !inNew:
+			result = append(result, EdgeDiff{From: k.from, To: k.to, Change: ChangeRemoved, OldCondition: oldEdge.Condition})
+		case oldEdge.Condition != newEdge.Condition:
+			result = append(result, EdgeDiff{
+				From:         k.from,
+				To:           k.to,
+				Change:       ChangeChanged,
+				OldCondition: oldEdge.Condition,
+				NewCondition: newEdge.Condition,
+			})
+		}
+	}
+	return result
+}
+
+func appendFieldChange(fields []FieldChange, name, oldVal, newVal string) []FieldChange {
+	if oldVal == newVal {
+		return fields
+	}
+	return append(fields, FieldChange{Field: name, Old: oldVal, New: newVal})
+}
+
+// unionKeys returns the sorted union of keys from two maps.
+func unionKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		seen[k] = true
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if !seen[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// WriteText writes a human-readable rendering of the diff, one change per line.
+// Lines are prefixed with "+" (added), "-" (removed), or "~" (changed).
+func (d *GraphDiff) WriteText(w io.Writer) {
+	if d.IsEmpty() {
+		fmt.Fprintln(w, "no changes")
+		return
+	}
+
+	if d.Version != nil {
+		fmt.Fprintf(w, "~ version: %v -> %v\n", d.Version.Old, d.Version.New)
+	}
+
+	for _, sd := range d.Scenes {
+		fmt.Fprintf(w, "%s scene %s\n", changeMarker(sd.Change), sd.SceneID)
+		writeFieldChanges(w, "    ", sd.Fields)
+		writeNodeDiffs(w, "  ", sd.Nodes)
+		writeEdgeDiffs(w, "  ", sd.Edges)
+		for _, sub := range sd.Subgraphs {
+			fmt.Fprintf(w, "  %s subgraph %s\n", changeMarker(sub.Change), sub.SubgraphID)
+			writeFieldChanges(w, "      ", sub.Fields)
+			writeNodeDiffs(w, "    ", sub.Nodes)
+			writeEdgeDiffs(w, "    ", sub.Edges)
+		}
+	}
+}
+
+func writeNodeDiffs(w io.Writer, indent string, nodes []NodeDiff) {
+	for _, nd := range nodes {
+		fmt.Fprintf(w, "%s%s node %s (%s)\n", indent, changeMarker(nd.Change), nd.NodeID, nd.Type)
+		writeFieldChanges(w, indent+"    ", nd.Fields)
+	}
+}
+
+func writeEdgeDiffs(w io.Writer, indent string, edges []EdgeDiff) {
+	for _, ed := range edges {
+		switch ed.Change {
+		case ChangeAdded:
+			fmt.Fprintf(w, "%s+ edge %s -> %s [%s]\n", indent, ed.From, ed.To, ed.NewCondition)
+		case ChangeRemoved:
+			fmt.Fprintf(w, "%s- edge %s -> %s [%s]\n", indent, ed.From, ed.To, ed.OldCondition)
+		default:
+			fmt.Fprintf(w, "%s~ edge %s -> %s: [%s] -> [%s]\n", indent, ed.From, ed.To, ed.OldCondition, ed.NewCondition)
+		}
+	}
+}
+
+func writeFieldChanges(w io.Writer, indent string, fields []FieldChange) {
+	for _, fc := range fields {
+		fmt.Fprintf(w, "%s%s: %v -> %v\n", indent, fc.Field, formatDiffValue(fc.Old), formatDiffValue(fc.New))
+	}
+}
+
+func formatDiffValue(v interface{}) string {
+	if v == nil {
+		return "(none)"
+	}
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func changeMarker(c ChangeKind) string {
+	switch c {
+	case ChangeAdded:
+		return "+"
+	case ChangeRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
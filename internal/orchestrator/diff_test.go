@@ -0,0 +1,149 @@
+package orchestrator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDiffSceneGraphsIdentical(t *testing.T) {
+	a, err := LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	b, err := LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+
+	diff := DiffSceneGraphs(a, b)
+	if !diff.IsEmpty() {
+		t.Errorf("expected empty diff for identical graphs, got %+v", diff)
+	}
+}
+
+func TestDiffSceneGraphsChanges(t *testing.T) {
+	a := &SceneGraph{
+		Version: 1,
+		Scenes: []Scene{
+			{
+				ID:    "scene_intro",
+				Name:  "Intro",
+				Entry: "start",
+				Nodes: []Node{
+					{ID: "start", Type: "action", Config: map[string]interface{}{"action": "lights.on"}},
+					{ID: "puzzle_a", Type: "puzzle", Config: map[string]interface{}{"subgraph": "sub_a"}},
+					{ID: "old_node", Type: "action", Config: map[string]interface{}{}},
+				},
+				Edges: []Edge{
+					{From: "start", To: "puzzle_a", Condition: ""},
+					{From: "puzzle_a", To: "old_node", Condition: "puzzle_a.resolved"},
+				},
+				Subgraphs: []Subgraph{
+					{ID: "sub_a", Entry: "a_wait", Nodes: []Node{{ID: "a_wait", Type: "decision"}}},
+				},
+			},
+			{ID: "scene_removed", Entry: "x"},
+		},
+	}
+	b := &SceneGraph{
+		Version: 1,
+		Scenes: []Scene{
+			{
+				ID:    "scene_intro",
+				Name:  "Intro",
+				Entry: "start",
+				Nodes: []Node{
+					{ID: "start", Type: "action", Config: map[string]interface{}{"action": "lights.off"}},
+					{ID: "puzzle_a", Type: "puzzle", Config: map[string]interface{}{"subgraph": "sub_a"}},
+					{ID: "new_node", Type: "terminal", Config: map[string]interface{}{}},
+				},
+				Edges: []Edge{
+					{From: "start", To: "puzzle_a", Condition: "event == 'node.completed'"},
+					{From: "puzzle_a", To: "new_node", Condition: "puzzle_a.resolved"},
+				},
+				Subgraphs: []Subgraph{
+					{ID: "sub_a", Entry: "a_wait", Nodes: []Node{{ID: "a_wait", Type: "decision"}}},
+				},
+			},
+			{ID: "scene_added", Entry: "y", Nodes: []Node{{ID: "y", Type: "terminal"}}},
+		},
+	}
+
+	diff := DiffSceneGraphs(a, b)
+	if diff.IsEmpty() {
+		t.Fatal("expected non-empty diff")
+	}
+
+	changes := make(map[string]ChangeKind)
+	for _, sd := range diff.Scenes {
+		changes[sd.SceneID] = sd.Change
+	}
+	if changes["scene_added"] != ChangeAdded {
+		t.Errorf("expected scene_added to be added, got %q", changes["scene_added"])
+	}
+	if changes["scene_removed"] != ChangeRemoved {
+		t.Errorf("expected scene_removed to be removed, got %q", changes["scene_removed"])
+	}
+
+	var intro *SceneDiff
+	for i := range diff.Scenes {
+		if diff.Scenes[i].SceneID == "scene_intro" {
+			intro = &diff.Scenes[i]
+		}
+	}
+	if intro == nil {
+		t.Fatal("expected scene_intro to be changed")
+	}
+	if len(intro.Subgraphs) != 0 {
+		t.Errorf("expected no subgraph changes, got %d", len(intro.Subgraphs))
+	}
+
+	nodeChanges := make(map[string]NodeDiff)
+	for _, nd := range intro.Nodes {
+		nodeChanges[nd.NodeID] = nd
+	}
+	if nodeChanges["new_node"].Change != ChangeAdded {
+		t.Errorf("expected new_node added, got %q", nodeChanges["new_node"].Change)
+	}
+	if nodeChanges["old_node"].Change != ChangeRemoved {
+		t.Errorf("expected old_node removed, got %q", nodeChanges["old_node"].Change)
+	}
+	start := nodeChanges["start"]
+	if start.Change != ChangeChanged || len(start.Fields) != 1 || start.Fields[0].Field != "config.action" {
+		t.Errorf("expected start config.action change, got %+v", start)
+	}
+	if _, ok := nodeChanges["puzzle_a"]; ok {
+		t.Error("expected unchanged puzzle_a to be omitted")
+	}
+
+	// start->puzzle_a condition changed, puzzle_a->old_node removed, puzzle_a->new_node added
+	if len(intro.Edges) != 3 {
+		t.Fatalf("expected 3 edge changes, got %d: %+v", len(intro.Edges), intro.Edges)
+	}
+	for _, ed := range intro.Edges {
+		switch {
+		case ed.From == "start" && ed.To == "puzzle_a":
+			if ed.Change != ChangeChanged || ed.NewCondition != "event == 'node.completed'" {
+				t.Errorf("unexpected edge diff: %+v", ed)
+			}
+		case ed.To == "old_node":
+			if ed.Change != ChangeRemoved {
+				t.Errorf("expected edge to old_node removed, got %q", ed.Change)
+			}
+		case ed.To == "new_node":
+			if ed.Change != ChangeAdded {
+				t.Errorf("expected edge to new_node added, got %q", ed.Change)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	diff.WriteText(&buf)
+	out := buf.String()
+	for _, want := range []string{"+ scene scene_added", "- scene scene_removed", "~ edge start -> puzzle_a", "config.action"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected text output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package orchestrator
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// DifficultyEventNames lists the persisted events the difficulty report reads.
+// puzzle.hint counts the hints players needed (see hints.go).
+var DifficultyEventNames = []string{
+	"scene.started",
+	"scene.advanced",
+	"scene.reset",
+	"puzzle.activated",
+	"puzzle.solved",
+	"puzzle.overridden",
+	"puzzle.reset",
+	"puzzle.hint",
+}
+
+// Outlier thresholds for the difficulty report.
+const (
+	// A puzzle is slow if its median solve time exceeds this multiple of the
+	// median across all puzzles with solve data.
+	slowMedianFactor = 2.0
+	// A puzzle is frequently skipped if this fraction of attempts were overridden.
+	highOverrideRate = 0.3
+	// A puzzle is hint-heavy if players need more than this many hints per attempt.
+	highHintsPerAttempt = 1.0
+)
+
+// DifficultyReport ranks puzzles by how hard players find them.
+type DifficultyReport struct {
+	GeneratedAt   string             `json:"generated_at"`
+	EventsScanned int                `json:"events_scanned"`
+	Puzzles       []PuzzleDifficulty `json:"puzzles"`
+}
+
+// PuzzleDifficulty aggregates historical statistics for a single puzzle node.
+type PuzzleDifficulty struct {
+	Rank            int          `json:"rank"`
+	NodeID          string       `json:"node_id"`
+	SceneID         string       `json:"scene_id"`
+	Attempts        int          `json:"attempts"`
+	Solved          int          `json:"solved"`
+	Overridden      int          `json:"overridden"`
+	OverrideRate    float64      `json:"override_rate"`
+	Hints           int          `json:"hints"`
+	HintsPerAttempt float64      `json:"hints_per_attempt"`
+	SolveTimeSec    *Percentiles `json:"solve_time_sec,omitempty"`
+	Outlier         bool         `json:"outlier"`
+	Flags           []string     `json:"flags,omitempty"`
+}
+
+// Percentiles summarizes a distribution of durations in seconds.
+type Percentiles struct {
+	Count  int     `json:"count"`
+	Min    float64 `json:"min"`
+	P25    float64 `json:"p25"`
+	Median float64 `json:"median"`
+	P75    float64 `json:"p75"`
+	P90    float64 `json:"p90"`
+	Max    float64 `json:"max"`
+}
+
+// puzzleSamples accumulates raw observations for one puzzle.
+type puzzleSamples struct {
+	attempts    int
+	solved      int
+	overridden  int
+	hints       int
+	solveTimes  []float64
+	activatedAt time.Time
+}
+
+// BuildDifficultyReport computes per-puzzle difficulty statistics from persisted
+// events (any order) and the scene graph. Only puzzle nodes in the graph are reported.
+func BuildDifficultyReport(rows []storage.EventRow, sg *SceneGraph) *DifficultyReport {
+	sorted := append([]storage.EventRow{}, rows...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].Timestamp.Equal(sorted[j].Timestamp) {
+			return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+		}
+		return sorted[i].EventID < sorted[j].EventID
+	})
+
+	// Collect puzzle nodes from the graph
+	puzzleScene := make(map[string]string)
+	for _, scene := range sg.Scenes {
+		for _, node := range scene.Nodes {
+			if node.Type == "puzzle" {
+				puzzleScene[node.ID] = scene.ID
+			}
+		}
+	}
+
+	samples := make(map[string]*puzzleSamples)
+	for nodeID := range puzzleScene {
+		samples[nodeID] = &puzzleSamples{}
+	}
+
+	for _, row := range sorted {
+		switch row.Event {
+		case "scene.started", "scene.advanced", "scene.reset":
+			// Session or scene boundary: in-flight activations no longer count
+			for _, s := range samples {
+				s.activatedAt = time.Time{}
+			}
+			continue
+		}
+
+		s, ok := samples[extractNodeID(row.Fields)]
+		if !ok {
+			continue
+		}
+
+		switch row.Event {
+		case "puzzle.activated":
+			s.attempts++
+			s.activatedAt = row.Timestamp
+		case "puzzle.solved":
+			s.solved++
+			if !s.activatedAt.IsZero() {
+				s.solveTimes = append(s.solveTimes, row.Timestamp.Sub(s.activatedAt).Seconds())
+				s.activatedAt = time.Time{}
+			}
+		case "puzzle.overridden":
+			s.overridden++
+			s.activatedAt = time.Time{}
+		case "puzzle.reset":
+			s.activatedAt = time.Time{}
+		case "puzzle.hint":
+			s.hints++
+		}
+	}
+
+	report := &DifficultyReport{
+		GeneratedAt:   time.Now().UTC().Format(time.RFC3339),
+		EventsScanned: len(rows),
+		Puzzles:       make([]PuzzleDifficulty, 0, len(samples)),
+	}
+
+	var medians []float64
+	for nodeID, s := range samples {
+		pd := PuzzleDifficulty{
+			NodeID:     nodeID,
+			SceneID:    puzzleScene[nodeID],
+			Attempts:   s.attempts,
+			Solved:     s.solved,
+			Overridden: s.overridden,
+			Hints:      s.hints,
+		}
+		if s.attempts > 0 {
+			pd.OverrideRate = float64(s.overridden) / float64(s.attempts)
+			pd.HintsPerAttempt = float64(s.hints) / float64(s.attempts)
+		}
+		if len(s.solveTimes) > 0 {
+			pd.SolveTimeSec = computePercentiles(s.solveTimes)
+			medians = append(medians, pd.SolveTimeSec.Median)
+		}
+		report.Puzzles = append(report.Puzzles, pd)
+	}
+
+	// Flag outliers
+	var medianOfMedians float64
+	if len(medians) >= 2 {
+		medianOfMedians = computePercentiles(medians).Median
+	}
+	for i := range report.Puzzles {
+		pd := &report.Puzzles[i]
+		if medianOfMedians > 0 && pd.SolveTimeSec != nil && pd.SolveTimeSec.Median > slowMedianFactor*medianOfMedians {
+			pd.Flags = append(pd.Flags, "slow_median")
+		}
+		if pd.Attempts > 0 && pd.OverrideRate >= highOverrideRate {
+			pd.Flags = append(pd.Flags, "high_override_rate")
+		}
+		if pd.Attempts > 0 && pd.HintsPerAttempt > highHintsPerAttempt {
+			pd.Flags = append(pd.Flags, "high_hint_usage")
+		}
+		pd.Outlier = len(pd.Flags) > 0
+	}
+
+	// Rank: slowest median first; puzzles without solve data rank last by override rate
+	sort.Slice(report.Puzzles, func(i, j int) bool {
+		a, b := report.Puzzles[i], report.Puzzles[j]
+		if (a.SolveTimeSec == nil) != (b.SolveTimeSec == nil) {
+			return a.SolveTimeSec != nil
+		}
+		if a.SolveTimeSec != nil && a.SolveTimeSec.Median != b.SolveTimeSec.Median {
+			return a.SolveTimeSec.Median > b.SolveTimeSec.Median
+		}
+		if a.OverrideRate != b.OverrideRate {
+			return a.OverrideRate > b.OverrideRate
+		}
+		return a.NodeID < b.NodeID
+	})
+	for i := range report.Puzzles {
+		report.Puzzles[i].Rank = i + 1
+	}
+
+	return report
+}
+
+// computePercentiles returns distribution statistics for a non-empty sample.
+func computePercentiles(values []float64) *Percentiles {
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	return &Percentiles{
+		Count:  len(sorted),
+		Min:    sorted[0],
+		P25:    percentile(sorted, 0.25),
+		Median: percentile(sorted, 0.5),
+		P75:    percentile(sorted, 0.75),
+		P90:    percentile(sorted, 0.9),
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// percentile returns the p-th percentile of sorted values using linear interpolation.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	pos := p * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	if lower == upper {
+		return sorted[lower]
+	}
+	frac := pos - float64(lower)
+	return sorted[lower] + (sorted[upper]-sorted[lower])*frac
+}
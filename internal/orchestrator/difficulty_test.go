@@ -0,0 +1,102 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+func TestBuildDifficultyReport(t *testing.T) {
+	sg, err := LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+
+	base := time.Date(2026, 1, 1, 18, 0, 0, 0, time.UTC)
+	at := func(min int) time.Time { return base.Add(time.Duration(min) * time.Minute) }
+	row := func(ts time.Time, event, key, nodeID string) storage.EventRow {
+		return storage.EventRow{Timestamp: ts, Event: event, Fields: map[string]interface{}{key: nodeID}}
+	}
+
+	rows := []storage.EventRow{
+		// Session 1: scarab 10 min, tiles 2 min
+		{Timestamp: at(0), Event: "scene.started", Fields: map[string]interface{}{"scene_id": "scene_intro"}},
+		row(at(0), "puzzle.activated", "node_id", "puzzle_scarab"),
+		row(at(0), "puzzle.activated", "node_id", "puzzle_tiles"),
+		row(at(2), "puzzle.solved", "puzzle_id", "puzzle_tiles"),
+		row(at(5), "puzzle.hint", "node_id", "puzzle_scarab"),
+		row(at(10), "puzzle.solved", "puzzle_id", "puzzle_scarab"),
+		// Session 2: scarab 20 min with two hints, tiles overridden
+		{Timestamp: at(100), Event: "scene.started", Fields: map[string]interface{}{"scene_id": "scene_intro"}},
+		row(at(100), "puzzle.activated", "node_id", "puzzle_scarab"),
+		row(at(100), "puzzle.activated", "node_id", "puzzle_tiles"),
+		row(at(103), "puzzle.overridden", "node_id", "puzzle_tiles"),
+		row(at(105), "puzzle.hint", "node_id", "puzzle_scarab"),
+		row(at(110), "puzzle.hint", "node_id", "puzzle_scarab"),
+		row(at(120), "puzzle.solved", "puzzle_id", "puzzle_scarab"),
+	}
+
+	// Pass rows newest-first as Postgres returns them
+	reversed := make([]storage.EventRow, len(rows))
+	for i := range rows {
+		rows[i].EventID = int64(i + 1)
+		reversed[len(rows)-1-i] = rows[i]
+	}
+
+	report := BuildDifficultyReport(reversed, sg)
+
+	if report.EventsScanned != len(rows) {
+		t.Errorf("expected %d events scanned, got %d", len(rows), report.EventsScanned)
+	}
+	if len(report.Puzzles) != 2 {
+		t.Fatalf("expected 2 puzzles, got %d", len(report.Puzzles))
+	}
+
+	scarab := report.Puzzles[0]
+	if scarab.NodeID != "puzzle_scarab" || scarab.Rank != 1 {
+		t.Fatalf("expected puzzle_scarab ranked first, got %s rank %d", scarab.NodeID, scarab.Rank)
+	}
+	if scarab.Attempts != 2 || scarab.Solved != 2 || scarab.Hints != 3 {
+		t.Errorf("unexpected scarab counts: %+v", scarab)
+	}
+	if scarab.SolveTimeSec == nil || scarab.SolveTimeSec.Median != 900 {
+		t.Errorf("expected scarab median 900s, got %+v", scarab.SolveTimeSec)
+	}
+	if !scarab.Outlier || !containsString(scarab.Flags, "high_hint_usage") {
+		t.Errorf("expected scarab flagged for hint usage, got %v", scarab.Flags)
+	}
+
+	tiles := report.Puzzles[1]
+	if tiles.Overridden != 1 || tiles.OverrideRate != 0.5 {
+		t.Errorf("expected tiles override rate 0.5, got %+v", tiles)
+	}
+	if tiles.SolveTimeSec == nil || tiles.SolveTimeSec.Count != 1 || tiles.SolveTimeSec.Median != 120 {
+		t.Errorf("expected single 120s tiles solve, got %+v", tiles.SolveTimeSec)
+	}
+	if !containsString(tiles.Flags, "high_override_rate") {
+		t.Errorf("expected tiles flagged for override rate, got %v", tiles.Flags)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{10, 20, 30, 40}
+	if got := percentile(values, 0.5); got != 25 {
+		t.Errorf("expected median 25, got %v", got)
+	}
+	if got := percentile(values, 0); got != 10 {
+		t.Errorf("expected p0 10, got %v", got)
+	}
+	if got := percentile(values, 1); got != 40 {
+		t.Errorf("expected p100 40, got %v", got)
+	}
+}
+
+func containsString(slice []string, val string) bool {
+	for _, s := range slice {
+		if s == val {
+			return true
+		}
+	}
+	return false
+}
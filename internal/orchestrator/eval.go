@@ -9,6 +9,7 @@ import (
 type EvalContext struct {
 	PuzzleStates map[string]*PuzzleStatus
 	Event        *Event
+	Vars         map[string]interface{}
 }
 
 // Event is an internal event representation for condition evaluation.
@@ -17,65 +18,27 @@ type Event struct {
 	Fields map[string]interface{}
 }
 
-// EvalCondition evaluates a condition expression.
-// MVP: supports ONLY these patterns from the example:
+// EvalCondition evaluates a condition expression (see CompileCondition for syntax).
+// Supported terms:
 //   - "" (empty = always true)
-//   - "<nodeID>.resolved" (single puzzle resolved check)
-//   - "<nodeID>.resolved && <nodeID>.resolved" (AND of two puzzle resolved checks)
+//   - "<nodeID>.resolved" (puzzle resolved check)
 //   - "event == '<eventName>'" (event name check)
-//   - "event == '<eventName>' && <field> == '<value>'" (event name + field check)
-//   - "logical_id == '<device_id>'" (device ID check for device.input)
+//   - "<field> == '<value>'" / "<field> != '<value>'" (event field check)
 //   - "payload.<field> == '<value>'" (nested payload field check for device.input)
+//   - "vars.<name> >= <number>" (session variable check; also ==, !=, >, <, <=)
+//   - "vars.<name>" (session variable is set and not false, zero or empty)
+//   - "state.<logical_id>.<field>" (last known device value; compares like vars)
+//
+// Terms combine with "!" (not), "&&" (and), "||" (or) and parentheses.
+// Precedence from highest: !, &&, ||. Operators inside quoted values are ignored.
+// Expressions that fail to parse evaluate to false; graphs are checked for
+// syntax errors at load time by ValidateConditions.
 func EvalCondition(expr string, ctx *EvalContext) bool {
-	expr = strings.TrimSpace(expr)
-
-	// Empty condition is always true
-	if expr == "" {
-		return true
-	}
-
-	// Handle AND conditions (split and evaluate both sides)
-	if strings.Contains(expr, "&&") {
-		parts := strings.SplitN(expr, "&&", 2)
-		left := strings.TrimSpace(parts[0])
-		right := strings.TrimSpace(parts[1])
-		return EvalCondition(left, ctx) && EvalCondition(right, ctx)
-	}
-
-	// Pattern: <nodeID>.resolved
-	if strings.HasSuffix(expr, ".resolved") {
-		nodeID := strings.TrimSuffix(expr, ".resolved")
-		if ctx.PuzzleStates == nil {
-			return false
-		}
-		if status, ok := ctx.PuzzleStates[nodeID]; ok {
-			return status.IsResolved()
-		}
+	cond, err := CompileCondition(strings.TrimSpace(expr))
+	if err != nil {
 		return false
 	}
-
-	// Pattern: event == '<eventName>'
-	if strings.HasPrefix(expr, "event ==") {
-		expected := extractSingleQuotedValue(expr, "event ==")
-		if ctx.Event == nil {
-			return false
-		}
-		return ctx.Event.Name == expected
-	}
-
-	// Pattern: <field> == '<value>' (for event field checks)
-	// Supports nested fields like "payload.signal" for device.input events
-	if strings.Contains(expr, "==") {
-		field, value := parseFieldEquality(expr)
-		if field == "" || ctx.Event == nil || ctx.Event.Fields == nil {
-			return false
-		}
-		fieldValue := getNestedField(ctx.Event.Fields, field)
-		return matchValue(fieldValue, value)
-	}
-
-	// Unknown pattern - return false
-	return false
+	return cond.Eval(ctx)
 }
 
 // getNestedField retrieves a value from nested maps using dot notation.
@@ -94,6 +57,38 @@ func getNestedField(fields map[string]interface{}, path string) interface{} {
 	return current
 }
 
+// toFloat converts numeric values (and numeric strings) to float64.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// truthy reports whether a value is set and not false, zero or empty.
+func truthy(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return val
+	case string:
+		return val != ""
+	}
+	if f, ok := toFloat(v); ok {
+		return f != 0
+	}
+	return true
+}
+
 // matchValue compares an interface value against a string target.
 // Handles string, bool, and numeric types.
 func matchValue(v interface{}, target string) bool {
@@ -126,36 +121,3 @@ func formatFloat(f float64) string {
 func formatInt(i int) string {
 	return strconv.Itoa(i)
 }
-
-// extractSingleQuotedValue extracts a single-quoted value after a prefix.
-// Example: "event == 'puzzle.solved'" with prefix "event ==" returns "puzzle.solved"
-func extractSingleQuotedValue(expr, prefix string) string {
-	idx := strings.Index(expr, prefix)
-	if idx == -1 {
-		return ""
-	}
-	rest := strings.TrimSpace(expr[idx+len(prefix):])
-	if len(rest) < 2 || rest[0] != '\'' {
-		return ""
-	}
-	end := strings.Index(rest[1:], "'")
-	if end == -1 {
-		return ""
-	}
-	return rest[1 : end+1]
-}
-
-// parseFieldEquality parses "<field> == '<value>'" and returns field, value.
-func parseFieldEquality(expr string) (string, string) {
-	parts := strings.SplitN(expr, "==", 2)
-	if len(parts) != 2 {
-		return "", ""
-	}
-	field := strings.TrimSpace(parts[0])
-	valueRaw := strings.TrimSpace(parts[1])
-	// Remove single quotes from value
-	if len(valueRaw) >= 2 && valueRaw[0] == '\'' && valueRaw[len(valueRaw)-1] == '\'' {
-		return field, valueRaw[1 : len(valueRaw)-1]
-	}
-	return field, valueRaw
-}
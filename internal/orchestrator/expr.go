@@ -0,0 +1,479 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ExprError is a condition syntax error with the column it was found at (1-based).
+type ExprError struct {
+	Expr string
+	Col  int
+	Msg  string
+}
+
+func (e *ExprError) Error() string {
+	return fmt.Sprintf("condition %q: col %d: %s", e.Expr, e.Col, e.Msg)
+}
+
+// Condition is a compiled condition expression: tokenized and parsed into a
+// small AST once, then evaluated against an EvalContext. Grammar (precedence
+// from lowest):
+//
+//	or      = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | primary
+//	primary = "(" or ")" | ident op literal | ident ".resolved" | ident ".failed" | "vars." ident
+//	op      = "==" | "!=" | ">" | ">=" | "<" | "<="
+//	literal = 'quoted text' | number | true | false | bare word
+//
+// The identifier "event" compares against the event name; identifiers starting
+// with "vars." read session variables; any other identifier is an event field
+// path (dots select nested payload fields). Ordering operators need a number.
+type Condition struct {
+	root exprNode
+}
+
+// Eval evaluates the condition. An empty condition is always true.
+func (c *Condition) Eval(ctx *EvalContext) bool {
+	if c.root == nil {
+		return true
+	}
+	return c.root.eval(ctx)
+}
+
+// NodeRefs returns the node IDs checked with ".resolved" or ".failed", in
+// order of first appearance.
+func (c *Condition) NodeRefs() []string {
+	var refs []string
+	seen := make(map[string]bool)
+	var walk func(n exprNode)
+	walk = func(n exprNode) {
+		switch n := n.(type) {
+		case *orNode:
+			for _, t := range n.terms {
+				walk(t)
+			}
+		case *andNode:
+			for _, t := range n.terms {
+				walk(t)
+			}
+		case *notNode:
+			walk(n.term)
+		case *resolvedNode:
+			if !seen[n.nodeID] {
+				seen[n.nodeID] = true
+				refs = append(refs, n.nodeID)
+			}
+		case *failedNode:
+			if !seen[n.nodeID] {
+				seen[n.nodeID] = true
+				refs = append(refs, n.nodeID)
+			}
+		}
+	}
+	if c.root != nil {
+		walk(c.root)
+	}
+	return refs
+}
+
+// compiledConditions caches compiled expressions by source text.
+var compiledConditions sync.Map // string -> *Condition
+
+// CompileCondition parses a condition expression, reusing earlier compilations.
+func CompileCondition(expr string) (*Condition, error) {
+	if cached, ok := compiledConditions.Load(expr); ok {
+		return cached.(*Condition), nil
+	}
+
+	p := &exprParser{src: expr}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+
+	cond := &Condition{}
+	if len(p.tokens) > 1 {
+		root, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.peek(); tok.kind != tokEOF {
+			return nil, p.errorAt(tok, "unexpected %s", tok)
+		}
+		cond.root = root
+	}
+
+	compiledConditions.Store(expr, cond)
+	return cond, nil
+}
+
+type exprNode interface {
+	eval(ctx *EvalContext) bool
+}
+
+type orNode struct{ terms []exprNode }
+
+func (n *orNode) eval(ctx *EvalContext) bool {
+	for _, t := range n.terms {
+		if t.eval(ctx) {
+			return true
+		}
+	}
+	return false
+}
+
+type andNode struct{ terms []exprNode }
+
+func (n *andNode) eval(ctx *EvalContext) bool {
+	for _, t := range n.terms {
+		if !t.eval(ctx) {
+			return false
+		}
+	}
+	return true
+}
+
+type notNode struct{ term exprNode }
+
+func (n *notNode) eval(ctx *EvalContext) bool {
+	return !n.term.eval(ctx)
+}
+
+// resolvedNode checks "<nodeID>.resolved".
+type resolvedNode struct{ nodeID string }
+
+func (n *resolvedNode) eval(ctx *EvalContext) bool {
+	if ctx == nil || ctx.PuzzleStates == nil {
+		return false
+	}
+	if status, ok := ctx.PuzzleStates[n.nodeID]; ok {
+		return status.IsResolved()
+	}
+	return false
+}
+
+// failedNode checks "<nodeID>.failed".
+type failedNode struct{ nodeID string }
+
+func (n *failedNode) eval(ctx *EvalContext) bool {
+	if ctx == nil || ctx.PuzzleStates == nil {
+		return false
+	}
+	if status, ok := ctx.PuzzleStates[n.nodeID]; ok {
+		return status.IsFailed()
+	}
+	return false
+}
+
+// varsPrefix marks identifiers that read session variables.
+const varsPrefix = "vars."
+
+// lookupField resolves an identifier to a session variable, device state or
+// event field.
+func lookupField(ctx *EvalContext, field string) (interface{}, bool) {
+	if strings.HasPrefix(field, statePrefix) {
+		return deviceState(field), true
+	}
+	if ctx == nil {
+		return nil, false
+	}
+	if name := strings.TrimPrefix(field, varsPrefix); name != field {
+		return getNestedField(ctx.Vars, name), true
+	}
+	if ctx.Event == nil || ctx.Event.Fields == nil {
+		return nil, false
+	}
+	return getNestedField(ctx.Event.Fields, field), true
+}
+
+// compareNode checks "<field> <op> <value>".
+type compareNode struct {
+	field  string
+	op     tokenKind
+	value  string
+	number float64 // parsed value for ordering operators
+}
+
+func (n *compareNode) eval(ctx *EvalContext) bool {
+	var v interface{}
+	if n.field == "event" {
+		if ctx == nil || ctx.Event == nil {
+			return false
+		}
+		v = ctx.Event.Name
+	} else {
+		var ok bool
+		if v, ok = lookupField(ctx, n.field); !ok {
+			return false
+		}
+	}
+
+	if typ := declaredPayloadType(ctx, n.field); typ != "" {
+		switch n.op {
+		case tokEq:
+			return matchTyped(v, n.value, typ)
+		case tokNeq:
+			return !matchTyped(v, n.value, typ)
+		}
+	}
+
+	switch n.op {
+	case tokEq:
+		return matchValue(v, n.value)
+	case tokNeq:
+		return !matchValue(v, n.value)
+	}
+
+	f, ok := toFloat(v)
+	if !ok {
+		return false
+	}
+	switch n.op {
+	case tokGt:
+		return f > n.number
+	case tokGte:
+		return f >= n.number
+	case tokLt:
+		return f < n.number
+	default:
+		return f <= n.number
+	}
+}
+
+// varNode checks that a session variable or device state is set and
+// truthy.
+type varNode struct{ field string }
+
+func (n *varNode) eval(ctx *EvalContext) bool {
+	v, _ := lookupField(ctx, n.field)
+	return truthy(v)
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokAnd
+	tokOr
+	tokNot
+	tokEq
+	tokNeq
+	tokGt
+	tokGte
+	tokLt
+	tokLte
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // byte offset in the source
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return fmt.Sprintf("'%s'", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+type exprParser struct {
+	src    string
+	tokens []token
+	next   int
+}
+
+func (p *exprParser) errorAt(tok token, format string, args ...interface{}) error {
+	return &ExprError{Expr: p.src, Col: tok.pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+// isIdentByte reports whether c may appear in an identifier or bare literal.
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '.' || c == '-' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func (p *exprParser) tokenize() error {
+	src := p.src
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "&&"):
+			p.tokens = append(p.tokens, token{tokAnd, "&&", i})
+			i += 2
+		case strings.HasPrefix(src[i:], "||"):
+			p.tokens = append(p.tokens, token{tokOr, "||", i})
+			i += 2
+		case strings.HasPrefix(src[i:], "=="):
+			p.tokens = append(p.tokens, token{tokEq, "==", i})
+			i += 2
+		case strings.HasPrefix(src[i:], "!="):
+			p.tokens = append(p.tokens, token{tokNeq, "!=", i})
+			i += 2
+		case strings.HasPrefix(src[i:], ">="):
+			p.tokens = append(p.tokens, token{tokGte, ">=", i})
+			i += 2
+		case strings.HasPrefix(src[i:], "<="):
+			p.tokens = append(p.tokens, token{tokLte, "<=", i})
+			i += 2
+		case c == '>':
+			p.tokens = append(p.tokens, token{tokGt, ">", i})
+			i++
+		case c == '<':
+			p.tokens = append(p.tokens, token{tokLt, "<", i})
+			i++
+		case c == '!':
+			p.tokens = append(p.tokens, token{tokNot, "!", i})
+			i++
+		case c == '(':
+			p.tokens = append(p.tokens, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			p.tokens = append(p.tokens, token{tokRParen, ")", i})
+			i++
+		case c == '\'':
+			end := strings.IndexByte(src[i+1:], '\'')
+			if end == -1 {
+				return &ExprError{Expr: src, Col: i + 1, Msg: "unterminated string"}
+			}
+			p.tokens = append(p.tokens, token{tokString, src[i+1 : i+1+end], i})
+			i += end + 2
+		case isIdentByte(c):
+			start := i
+			for i < len(src) && isIdentByte(src[i]) {
+				i++
+			}
+			p.tokens = append(p.tokens, token{tokIdent, src[start:i], start})
+		default:
+			return &ExprError{Expr: src, Col: i + 1, Msg: fmt.Sprintf("unexpected character %q", c)}
+		}
+	}
+	p.tokens = append(p.tokens, token{tokEOF, "", len(src)})
+	return nil
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *exprParser) advance() token {
+	tok := p.tokens[p.next]
+	if tok.kind != tokEOF {
+		p.next++
+	}
+	return tok
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	terms := []exprNode{first}
+	for p.peek().kind == tokOr {
+		p.advance()
+		term, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 1 {
+		return first, nil
+	}
+	return &orNode{terms: terms}, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	terms := []exprNode{first}
+	for p.peek().kind == tokAnd {
+		p.advance()
+		term, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 1 {
+		return first, nil
+	}
+	return &andNode{terms: terms}, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.peek().kind == tokNot {
+		p.advance()
+		term, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{term: term}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.advance()
+	switch tok.kind {
+	case tokLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.advance(); closing.kind != tokRParen {
+			return nil, p.errorAt(closing, "expected \")\", got %s", closing)
+		}
+		return inner, nil
+
+	case tokIdent:
+		switch op := p.peek(); op.kind {
+		case tokEq, tokNeq, tokGt, tokGte, tokLt, tokLte:
+			p.advance()
+			value := p.advance()
+			if value.kind != tokString && value.kind != tokIdent {
+				return nil, p.errorAt(value, "expected value after %s, got %s", op.text, value)
+			}
+			cmp := &compareNode{field: tok.text, op: op.kind, value: value.text}
+			if op.kind != tokEq && op.kind != tokNeq {
+				f, ok := toFloat(value.text)
+				if !ok {
+					return nil, p.errorAt(value, "expected number after %s, got %s", op.text, value)
+				}
+				cmp.number = f
+			}
+			return cmp, nil
+		}
+		if strings.HasPrefix(tok.text, varsPrefix) && len(tok.text) > len(varsPrefix) {
+			return &varNode{field: tok.text}, nil
+		}
+		if _, _, ok := stateFieldRef(tok.text); ok {
+			return &varNode{field: tok.text}, nil
+		}
+		if nodeID := strings.TrimSuffix(tok.text, ".resolved"); nodeID != tok.text && nodeID != "" {
+			return &resolvedNode{nodeID: nodeID}, nil
+		}
+		if nodeID := strings.TrimSuffix(tok.text, ".failed"); nodeID != tok.text && nodeID != "" {
+			return &failedNode{nodeID: nodeID}, nil
+		}
+		return nil, p.errorAt(tok, "expected comparison, .resolved or .failed after %s", tok)
+
+	default:
+		return nil, p.errorAt(tok, "expected condition, got %s", tok)
+	}
+}
@@ -0,0 +1,169 @@
+package orchestrator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompileConditionEval(t *testing.T) {
+	ctx := &EvalContext{
+		PuzzleStates: map[string]*PuzzleStatus{
+			"scarab": {NodeID: "scarab", Resolution: PuzzleSolved},
+			"vault":  {NodeID: "vault", Resolution: PuzzleFailed},
+		},
+		Event: &Event{
+			Name: "device.input",
+			Fields: map[string]interface{}{
+				"logical_id": "crypt_door",
+				"payload":    map[string]interface{}{"door_closed": true, "code": 1234.0},
+			},
+		},
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"", true},
+		{"event == 'device.input'", true},
+		{"event != 'device.input'", false},
+		{"logical_id == crypt_door", true},
+		{"logical_id != 'other'", true},
+		{"payload.door_closed == true", true},
+		{"payload.code == 1234", true},
+		{"scarab.resolved && !tiles.resolved", true},
+		{"vault.failed && !vault.resolved && !scarab.failed", true},
+		{"event == 'puzzle.solved' || scarab.resolved && payload.door_closed == false", false},
+		{"(event == 'puzzle.solved' || scarab.resolved) && payload.door_closed == true", true},
+		{"!(logical_id == 'crypt_door')", false},
+		{"logical_id == 'a && b' || event == 'device.input'", true},
+	}
+
+	for _, tt := range tests {
+		cond, err := CompileCondition(tt.expr)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.expr, err)
+		}
+		if got := cond.Eval(ctx); got != tt.want {
+			t.Errorf("%q = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCompileConditionSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		col  int
+	}{
+		{"event == 'node.completed", 10},
+		{"event ==", 9},
+		{"scarab", 1},
+		{"(scarab.resolved", 17},
+		{"scarab.resolved)", 16},
+		{"scarab.resolved && ", 20},
+		{"event = 'x'", 7},
+	}
+
+	for _, tt := range tests {
+		_, err := CompileCondition(tt.expr)
+		var exprErr *ExprError
+		if !errors.As(err, &exprErr) {
+			t.Fatalf("%q: expected ExprError, got %v", tt.expr, err)
+		}
+		if exprErr.Col != tt.col {
+			t.Errorf("%q: col = %d, want %d (%v)", tt.expr, exprErr.Col, tt.col, err)
+		}
+		if EvalCondition(tt.expr, &EvalContext{}) {
+			t.Errorf("%q: invalid condition evaluated true", tt.expr)
+		}
+	}
+}
+
+func TestCompileConditionCached(t *testing.T) {
+	a, err := CompileCondition("event == 'cache.test'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := CompileCondition("event == 'cache.test'")
+	if a != b {
+		t.Error("expected the compiled condition to be reused")
+	}
+}
+
+func TestLoadSceneGraphRejectsBadCondition(t *testing.T) {
+	graph := `{
+  "version": 1,
+  "scenes": [{
+    "id": "intro",
+    "entry": "start",
+    "nodes": [
+      { "id": "start", "type": "action", "config": {} },
+      { "id": "join", "type": "gate", "config": { "all_of": ["start", "lever.resolved"] } }
+    ],
+    "edges": [],
+    "subgraphs": [{
+      "id": "lever",
+      "entry": "wait",
+      "nodes": [{ "id": "wait", "type": "action", "config": {} }],
+      "edges": [{ "from": "wait", "to": "done", "condition": "event == 'puzzle.solved' &&" }]
+    }]
+  }]
+}`
+	path := filepath.Join(t.TempDir(), "scene-graph.json")
+	if err := os.WriteFile(path, []byte(graph), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadSceneGraph(path)
+	if err == nil {
+		t.Fatal("expected load error for invalid condition")
+	}
+	for _, want := range []string{"scene intro", "subgraph lever", "edge wait -> done", "col 28"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+}
+
+func TestCompileConditionVars(t *testing.T) {
+	ctx := &EvalContext{Vars: map[string]interface{}{
+		"attempts": 3.0,
+		"hinted":   true,
+		"answer":   "ra",
+		"input":    map[string]interface{}{"dial": map[string]interface{}{"value": 42.0}},
+	}}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"vars.attempts >= 3", true},
+		{"vars.attempts > 3", false},
+		{"vars.attempts < 3.5", true},
+		{"vars.attempts <= 2", false},
+		{"vars.attempts == 3", true},
+		{"vars.answer == 'ra'", true},
+		{"vars.answer > 1", false},
+		{"vars.hinted", true},
+		{"!vars.missing", true},
+		{"vars.missing != 'x'", true},
+		{"vars.input.dial.value >= 40 && vars.hinted", true},
+	}
+
+	for _, tt := range tests {
+		cond, err := CompileCondition(tt.expr)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.expr, err)
+		}
+		if got := cond.Eval(ctx); got != tt.want {
+			t.Errorf("%q = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	if _, err := CompileCondition("vars.attempts >= many"); err == nil {
+		t.Error("expected error for non-numeric ordering comparison")
+	}
+}
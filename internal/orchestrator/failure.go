@@ -0,0 +1,99 @@
+package orchestrator
+
+import (
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// puzzleTimeoutKey returns the scheduler key for a puzzle's timeout.
+func puzzleTimeoutKey(nodeID string) string {
+	return "puzzle-timeout:" + nodeID
+}
+
+// startPuzzleLimits arms a puzzle's timeout and auto hint and clears its
+// attempt count. timeout_ms and max_attempts let a puzzle fail instead of
+// waiting forever (see design/scene-graph/schema.md).
+func (r *Runtime) startPuzzleLimits(node *Node) {
+	delete(r.attempts, node.ID)
+	r.scheduleAutoHint(node)
+
+	timeoutMs, ok := configInt(node.Config, "timeout_ms")
+	if !ok || timeoutMs <= 0 {
+		return
+	}
+	nodeID := node.ID
+	r.schedule(puzzleTimeoutKey(nodeID), time.Duration(timeoutMs)*time.Millisecond, func() {
+		r.failPuzzle(nodeID, "timeout")
+		r.evaluateAllConditions()
+	})
+}
+
+// stopPuzzleLimits cancels a puzzle's timeout and auto hint and clears its
+// attempt count.
+func (r *Runtime) stopPuzzleLimits(nodeID string) {
+	r.cancelScheduled(puzzleTimeoutKey(nodeID))
+	r.cancelScheduled(hintKey(nodeID))
+	delete(r.attempts, nodeID)
+}
+
+// countAttempt records an event matching a puzzle's attempt_on condition and
+// fails the puzzle once max_attempts is reached.
+func (r *Runtime) countAttempt(nodeID string, evt Event) {
+	node := r.findNode(nodeID)
+	if node == nil {
+		return
+	}
+	maxAttempts, ok := configInt(node.Config, "max_attempts")
+	if !ok || maxAttempts <= 0 {
+		return
+	}
+	attemptOn, _ := node.Config["attempt_on"].(string)
+	if attemptOn == "" {
+		return
+	}
+	ctx := &EvalContext{Event: &evt, Vars: r.vars}
+	if !EvalCondition(attemptOn, ctx) {
+		return
+	}
+
+	r.attempts[nodeID]++
+	if r.attempts[nodeID] >= maxAttempts {
+		r.failPuzzle(nodeID, "max_attempts")
+	}
+}
+
+// failPuzzle marks an active puzzle as failed and follows its failure edges.
+func (r *Runtime) failPuzzle(nodeID, reason string) {
+	status := r.nodeStates[nodeID]
+	if status == nil || status.State != NodeStateActive {
+		return
+	}
+
+	attempts := r.attempts[nodeID]
+	r.stopPuzzleLimits(nodeID)
+	r.cancelActions(nodeID)
+	if pr, ok := r.puzzleRuntimes[nodeID]; ok {
+		pr.Fail()
+	}
+	if ps, ok := r.puzzleStates[nodeID]; ok {
+		ps.Resolution = PuzzleFailed
+	}
+
+	r.emitEvent("puzzle.failed", map[string]interface{}{
+		"node_id":  nodeID,
+		"reason":   reason,
+		"attempts": attempts,
+	})
+
+	status.State = NodeStateFailed
+	events.Emit("warning", "node.failed", "", map[string]interface{}{
+		"node_id": nodeID,
+		"reason":  reason,
+	})
+	r.checkInvariants()
+
+	r.checkParallelCompletion()
+	r.evaluateGates()
+	r.evaluateEdgesFrom(nodeID)
+}
@@ -0,0 +1,156 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// failureSceneGraph builds a scene where a puzzle branches on success or failure.
+func failureSceneGraph(config map[string]interface{}) *SceneGraph {
+	config["subgraph"] = "sub_vault"
+	return sceneGraph(Scene{
+		ID:    "scene_vault",
+		Entry: "vault",
+		Nodes: []Node{
+			{ID: "vault", Type: "puzzle", Config: config},
+			{ID: "escaped", Type: "operator", Config: map[string]interface{}{}},
+			{ID: "caught", Type: "operator", Config: map[string]interface{}{}},
+		},
+		Edges: []Edge{
+			{From: "vault", To: "escaped"},
+			{From: "vault", To: "caught", Condition: "vault.failed"},
+		},
+		Subgraphs: []Subgraph{
+			{
+				ID:    "sub_vault",
+				Entry: "wait_code",
+				Nodes: []Node{
+					{ID: "wait_code", Type: "decision"},
+					{ID: "opened", Type: "terminal"},
+				},
+				Edges: []Edge{
+					{From: "wait_code", To: "opened", Condition: "code == '1234'"},
+				},
+			},
+		},
+	})
+}
+
+func TestPuzzleTimeout(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(failureSceneGraph(map[string]interface{}{"timeout_ms": float64(60000)}))
+	if err := rt.StartScene("scene_vault"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+
+	rt.mu.Lock()
+	fired := rt.fireScheduled(puzzleTimeoutKey("vault"))
+	rt.mu.Unlock()
+	if !fired {
+		t.Fatal("expected puzzle timeout to be scheduled")
+	}
+
+	if rt.GetPuzzleResolution("vault") != PuzzleFailed {
+		t.Errorf("expected vault failed, got %s", rt.GetPuzzleResolution("vault"))
+	}
+	if rt.GetNodeState("vault") != NodeStateFailed {
+		t.Errorf("expected vault node failed, got %s", rt.GetNodeState("vault"))
+	}
+	if countEvents("puzzle.failed", "vault") != 1 {
+		t.Error("expected one puzzle.failed")
+	}
+	if rt.GetNodeState("caught") != NodeStateActive {
+		t.Errorf("expected failure branch active, got %s", rt.GetNodeState("caught"))
+	}
+	if rt.GetNodeState("escaped") != NodeStateIdle {
+		t.Errorf("expected unconditional edge not taken on failure, got %s", rt.GetNodeState("escaped"))
+	}
+
+	// Late input no longer solves the puzzle
+	rt.InjectEvent("device.input", map[string]interface{}{"code": "1234"})
+	if rt.GetPuzzleResolution("vault") != PuzzleFailed {
+		t.Errorf("expected vault to stay failed, got %s", rt.GetPuzzleResolution("vault"))
+	}
+}
+
+func TestPuzzleSolvedCancelsTimeout(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(failureSceneGraph(map[string]interface{}{"timeout_ms": float64(60000)}))
+	if err := rt.StartScene("scene_vault"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+
+	rt.InjectEvent("device.input", map[string]interface{}{"code": "1234"})
+	if rt.GetPuzzleResolution("vault") != PuzzleSolved {
+		t.Fatalf("expected vault solved, got %s", rt.GetPuzzleResolution("vault"))
+	}
+
+	rt.mu.Lock()
+	fired := rt.fireScheduled(puzzleTimeoutKey("vault"))
+	rt.mu.Unlock()
+	if fired {
+		t.Error("expected timeout cancelled once solved")
+	}
+	if rt.GetNodeState("escaped") != NodeStateActive {
+		t.Errorf("expected success branch active, got %s", rt.GetNodeState("escaped"))
+	}
+	if rt.GetNodeState("caught") != NodeStateIdle {
+		t.Errorf("expected failure branch idle, got %s", rt.GetNodeState("caught"))
+	}
+}
+
+func TestPuzzleMaxAttempts(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(failureSceneGraph(map[string]interface{}{
+		"max_attempts": float64(3),
+		"attempt_on":   "event == 'device.input' && logical_id == 'keypad'",
+	}))
+	if err := rt.StartScene("scene_vault"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+
+	wrong := map[string]interface{}{"logical_id": "keypad", "code": "0000"}
+	rt.InjectEvent("device.input", wrong)
+	rt.InjectEvent("device.input", map[string]interface{}{"logical_id": "lever"})
+	rt.InjectEvent("device.input", wrong)
+	if rt.GetPuzzleResolution("vault") != PuzzleUnresolved {
+		t.Fatalf("expected vault unresolved after two attempts, got %s", rt.GetPuzzleResolution("vault"))
+	}
+
+	rt.InjectEvent("device.input", wrong)
+	if rt.GetPuzzleResolution("vault") != PuzzleFailed {
+		t.Fatalf("expected vault failed after three attempts, got %s", rt.GetPuzzleResolution("vault"))
+	}
+	for _, e := range events.Snapshot() {
+		if e.Name == "puzzle.failed" {
+			if e.Fields["reason"] != "max_attempts" || e.Fields["attempts"] != 3 {
+				t.Errorf("unexpected puzzle.failed fields: %v", e.Fields)
+			}
+		}
+	}
+
+	// Resetting a failed puzzle starts it over
+	if err := rt.ResetNode("vault"); err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+	if rt.GetNodeState("vault") != NodeStateActive {
+		t.Fatalf("expected vault active after reset, got %s", rt.GetNodeState("vault"))
+	}
+	rt.InjectEvent("device.input", map[string]interface{}{"logical_id": "keypad", "code": "1234"})
+	if rt.GetPuzzleResolution("vault") != PuzzleSolved {
+		t.Errorf("expected vault solved after reset, got %s", rt.GetPuzzleResolution("vault"))
+	}
+}
+
+func TestPuzzleMaxAttemptsRequiresAttemptOn(t *testing.T) {
+	sg := failureSceneGraph(map[string]interface{}{"max_attempts": float64(3)})
+	if err := ValidateConditions(sg); err == nil {
+		t.Error("expected max_attempts without attempt_on to be rejected")
+	}
+
+	sg = failureSceneGraph(map[string]interface{}{"max_attempts": float64(3), "attempt_on": "event =="})
+	if err := ValidateConditions(sg); err == nil {
+		t.Error("expected invalid attempt_on to be rejected")
+	}
+}
@@ -0,0 +1,334 @@
+package orchestrator
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// FederationTokenHeader carries the shared token on federated event requests.
+const FederationTokenHeader = "X-Sentient-Federation-Token"
+
+// FederationPath is the API path sibling rooms POST events to.
+const FederationPath = "/federation/events"
+
+// FederatedEventName is emitted for each event received from a sibling room.
+const FederatedEventName = "room.federated"
+
+// defaultFederationTimeout bounds requests to sibling rooms.
+const defaultFederationTimeout = 5 * time.Second
+
+// federationSeenSize bounds the set of received events remembered for de-duplication.
+const federationSeenSize = 1024
+
+var (
+	// ErrFederationUnauthorized is returned for a bad token or an unknown origin room.
+	ErrFederationUnauthorized = errors.New("federation: unauthorized")
+	// ErrFederationLoop is returned for an event that originated in this room.
+	ErrFederationLoop = errors.New("federation: event originated in this room")
+)
+
+// FederationControl marks coordination messages between rooms (see
+// competition.go). They are handed to the control handler instead of being
+// emitted as room.federated.
+const FederationControl = "control"
+
+// FederatedEvent is an event sent from one room to its siblings.
+type FederatedEvent struct {
+	Type   string                 `json:"type,omitempty"` // "" for events, FederationControl for coordination
+	Origin string                 `json:"origin"`
+	Seq    uint64                 `json:"seq,omitempty"`
+	Event  string                 `json:"event"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	TS     string                 `json:"ts,omitempty"`
+}
+
+// EventInjector routes an event into the scene graph (implemented by *Runtime).
+type EventInjector interface {
+	InjectEvent(name string, fields map[string]interface{})
+}
+
+// Federation links this room with sibling rooms (ADR-012). Local events
+// matching federation.yaml publish rules are POSTed to every peer; events
+// received from peers are emitted as room.federated and injected into the
+// runtime. Received events are never forwarded again, so links cannot loop.
+type Federation struct {
+	roomID   string
+	token    string
+	peers    []config.FederationPeer
+	publish  []config.BridgeMatch
+	injector EventInjector
+	client   *http.Client
+
+	mu      sync.Mutex
+	seen    map[string]bool // origin/seq of received events
+	control func(FederatedEvent) error
+
+	queue   chan events.Event
+	remove  func()
+	dropped atomic.Bool
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewFederation creates a federation link for roomID. The shared token is read
+// from cfg.TokenEnv; received events are injected through injector.
+func NewFederation(roomID string, cfg *config.FederationConfig, injector EventInjector) (*Federation, error) {
+	token, err := config.ResolveSecret(cfg.TokenEnv)
+	if err != nil {
+		return nil, fmt.Errorf("federation: %w", err)
+	}
+	if token == "" {
+		return nil, fmt.Errorf("federation: %s not set", cfg.TokenEnv)
+	}
+
+	timeout := defaultFederationTimeout
+	if cfg.TimeoutMs > 0 {
+		timeout = time.Duration(cfg.TimeoutMs) * time.Millisecond
+	}
+
+	publish := make([]config.BridgeMatch, len(cfg.Publish))
+	for i, when := range cfg.Publish {
+		publish[i] = currentMatch(when, "federation.yaml")
+	}
+
+	return &Federation{
+		roomID:   roomID,
+		token:    token,
+		peers:    cfg.Peers,
+		publish:  publish,
+		injector: injector,
+		client:   &http.Client{Timeout: timeout},
+		seen:     make(map[string]bool),
+		queue:    make(chan events.Event, bridgeQueueSize),
+		stopCh:   make(chan struct{}),
+	}, nil
+}
+
+// Start subscribes to emitted events and publishes matches in the background.
+func (f *Federation) Start() {
+	f.remove = events.AddListener(func(e events.Event) {
+		select {
+		case f.queue <- e:
+		default:
+			// Never block the emitter; note the first overflow only
+			if f.dropped.CompareAndSwap(false, true) {
+				log.Printf("[federation] queue full, dropping events")
+			}
+		}
+	})
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		for {
+			select {
+			case <-f.stopCh:
+				return
+			case e := <-f.queue:
+				f.Handle(e)
+			}
+		}
+	}()
+}
+
+// Stop unsubscribes and waits for the worker to exit.
+func (f *Federation) Stop() {
+	if f.remove != nil {
+		f.remove()
+	}
+	close(f.stopCh)
+	f.wg.Wait()
+}
+
+// Handle sends the event to every peer if it matches a publish rule.
+func (f *Federation) Handle(e events.Event) {
+	// Received events are never forwarded, and delivery errors are never published
+	if e.Name == FederatedEventName {
+		return
+	}
+	if _, ok := e.Fields["federation"]; ok {
+		return
+	}
+
+	matched := false
+	for _, when := range f.publish {
+		if bridgeMatches(when, e) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return
+	}
+
+	fe := FederatedEvent{
+		Origin: f.roomID,
+		Seq:    e.Seq,
+		Event:  e.Name,
+		Fields: e.Fields,
+		TS:     e.Timestamp,
+	}
+	for _, peer := range f.peers {
+		if err := f.send(peer, fe); err != nil {
+			events.Emit("error", "system.error", "federated event not delivered", map[string]interface{}{
+				"federation": peer.Room,
+				"event_name": e.Name,
+				"error":      err.Error(),
+			})
+		}
+	}
+}
+
+// SetControlHandler sets the handler for control messages from peers.
+func (f *Federation) SetControlHandler(handler func(FederatedEvent) error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.control = handler
+}
+
+// SendControl sends a control message to one peer room.
+func (f *Federation) SendControl(room, name string, fields map[string]interface{}) error {
+	for _, peer := range f.peers {
+		if peer.Room == room {
+			return f.send(peer, FederatedEvent{
+				Type:   FederationControl,
+				Origin: f.roomID,
+				Event:  name,
+				Fields: fields,
+				TS:     time.Now().UTC().Format(time.RFC3339Nano),
+			})
+		}
+	}
+	return fmt.Errorf("federation: unknown peer %s", room)
+}
+
+// send POSTs an event to one peer.
+func (f *Federation) send(peer config.FederationPeer, fe FederatedEvent) error {
+	payload, err := json.Marshal(fe)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(peer.URL, "/")+FederationPath, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(FederationTokenHeader, f.token)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("peer returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Receive accepts an event from a sibling room, emitting it as room.federated
+// and injecting it into the runtime. Repeated deliveries are dropped silently.
+// Control messages go to the control handler instead.
+func (f *Federation) Receive(token string, fe FederatedEvent) error {
+	if subtle.ConstantTimeCompare([]byte(token), []byte(f.token)) != 1 {
+		return ErrFederationUnauthorized
+	}
+	if fe.Origin == f.roomID {
+		return ErrFederationLoop
+	}
+	if !f.isPeer(fe.Origin) {
+		return ErrFederationUnauthorized
+	}
+	if fe.Event == "" {
+		return fmt.Errorf("federation: event required")
+	}
+	// A sibling room on an older release may still use legacy names
+	fe.Event = events.Translate(fe.Event, "room "+fe.Origin)
+	if fe.Event == FederatedEventName {
+		return ErrFederationLoop
+	}
+
+	if fe.Type == FederationControl {
+		f.mu.Lock()
+		handler := f.control
+		f.mu.Unlock()
+		if handler == nil {
+			return fmt.Errorf("federation: control messages not accepted")
+		}
+		return handler(fe)
+	}
+	if fe.Type != "" {
+		return fmt.Errorf("federation: unknown message type %q", fe.Type)
+	}
+
+	if fe.Seq > 0 {
+		key := fmt.Sprintf("%s/%d", fe.Origin, fe.Seq)
+		f.mu.Lock()
+		dup := f.seen[key]
+		if !dup {
+			if len(f.seen) >= federationSeenSize {
+				f.seen = make(map[string]bool)
+			}
+			f.seen[key] = true
+		}
+		f.mu.Unlock()
+		if dup {
+			return nil
+		}
+	}
+
+	fields := map[string]interface{}{
+		"from_room":    fe.Origin,
+		"source_event": fe.Event,
+		"payload":      fe.Fields,
+	}
+	if fe.Seq > 0 {
+		fields["source_seq"] = fe.Seq
+	}
+	events.Emit("info", FederatedEventName, "", fields)
+	if f.injector != nil {
+		f.injector.InjectEvent(FederatedEventName, fields)
+	}
+	return nil
+}
+
+// IsPeer reports whether room is a configured sibling room.
+func (f *Federation) IsPeer(room string) bool {
+	return f.isPeer(room)
+}
+
+// RoomID returns this room's id.
+func (f *Federation) RoomID() string {
+	return f.roomID
+}
+
+func (f *Federation) isPeer(room string) bool {
+	for _, peer := range f.peers {
+		if peer.Room == room {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateFederationConditions compiles each publish rule's condition so syntax errors fail at startup.
+func ValidateFederationConditions(cfg *config.FederationConfig) error {
+	for i, when := range cfg.Publish {
+		if _, err := CompileCondition(when.Condition); err != nil {
+			return fmt.Errorf("federation publish %d: %w", i, err)
+		}
+	}
+	return nil
+}
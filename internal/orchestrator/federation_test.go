@@ -0,0 +1,156 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// federatedSceneGraph waits for the generator room to solve its puzzle.
+func federatedSceneGraph() *SceneGraph {
+	return sceneGraph(Scene{
+		ID:    "scene_elevator",
+		Entry: "wait_power",
+		Nodes: []Node{
+			{ID: "wait_power", Type: "subgraph", Config: map[string]interface{}{"subgraph": "power"}},
+			{ID: "done", Type: "terminal", Config: map[string]interface{}{}},
+		},
+		Edges: []Edge{{From: "wait_power", To: "done"}},
+		Subgraphs: []Subgraph{
+			{
+				ID:    "power",
+				Entry: "wait",
+				Nodes: []Node{
+					{ID: "wait", Type: "decision"},
+					{ID: "powered", Type: "terminal", Config: map[string]interface{}{}},
+				},
+				Edges: []Edge{{
+					From:      "wait",
+					To:        "powered",
+					Condition: "event == 'room.federated' && from_room == 'generator_room' && payload.node_id == 'puzzle_generator'",
+				}},
+			},
+		},
+	})
+}
+
+func newTestFederation(t *testing.T, roomID, peerRoom, peerURL string, injector EventInjector) *Federation {
+	t.Helper()
+	t.Setenv("TEST_FEDERATION_TOKEN", "s3cret")
+	f, err := NewFederation(roomID, &config.FederationConfig{
+		Version:  1,
+		TokenEnv: "TEST_FEDERATION_TOKEN",
+		Peers:    []config.FederationPeer{{Room: peerRoom, URL: peerURL}},
+		Publish:  []config.BridgeMatch{{Event: "puzzle.solved"}},
+	}, injector)
+	if err != nil {
+		t.Fatalf("failed to create federation: %v", err)
+	}
+	return f
+}
+
+func TestFederationLinksRooms(t *testing.T) {
+	events.Clear()
+	elevator := NewRuntime(federatedSceneGraph())
+	if err := elevator.StartScene("scene_elevator"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+	receiver := newTestFederation(t, "elevator_room", "generator_room", "http://unused", elevator)
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		var fe FederatedEvent
+		if r.URL.Path != FederationPath || json.NewDecoder(r.Body).Decode(&fe) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := receiver.Receive(r.Header.Get(FederationTokenHeader), fe); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer srv.Close()
+	sender := newTestFederation(t, "generator_room", "elevator_room", srv.URL, nil)
+
+	// Events not matching a publish rule stay local
+	sender.Handle(events.Event{Seq: 1, Name: "puzzle.progress", Fields: map[string]interface{}{"node_id": "puzzle_generator"}})
+	if requests.Load() != 0 {
+		t.Fatalf("expected no requests, got %d", requests.Load())
+	}
+
+	solved := events.Event{Seq: 2, Name: "puzzle.solved", Fields: map[string]interface{}{"node_id": "puzzle_generator"}}
+	sender.Handle(solved)
+	if elevator.GetNodeState("done") != NodeStateCompleted {
+		t.Errorf("expected linked event to complete the scene, got %s", elevator.GetNodeState("done"))
+	}
+	if n := countNamed("room.federated"); n != 1 {
+		t.Errorf("expected 1 room.federated, got %d", n)
+	}
+
+	// A repeated delivery is accepted but not processed again
+	sender.Handle(solved)
+	if n := countNamed("room.federated"); n != 1 {
+		t.Errorf("expected duplicate to be dropped, got %d room.federated", n)
+	}
+
+	// Received events are never forwarded
+	before := requests.Load()
+	for _, e := range events.Snapshot() {
+		if e.Name == "room.federated" {
+			receiver.Handle(e)
+			sender.Handle(e)
+		}
+	}
+	if requests.Load() != before {
+		t.Errorf("expected room.federated not to be forwarded")
+	}
+}
+
+func TestFederationReceiveRejects(t *testing.T) {
+	events.Clear()
+	f := newTestFederation(t, "elevator_room", "generator_room", "http://unused", nil)
+
+	tests := []struct {
+		name  string
+		token string
+		event FederatedEvent
+		want  error
+	}{
+		{"bad token", "wrong", FederatedEvent{Origin: "generator_room", Event: "puzzle.solved"}, ErrFederationUnauthorized},
+		{"unknown room", "s3cret", FederatedEvent{Origin: "other_room", Event: "puzzle.solved"}, ErrFederationUnauthorized},
+		{"own event", "s3cret", FederatedEvent{Origin: "elevator_room", Event: "puzzle.solved"}, ErrFederationLoop},
+		{"forwarded", "s3cret", FederatedEvent{Origin: "generator_room", Event: "room.federated"}, ErrFederationLoop},
+	}
+	for _, tt := range tests {
+		if err := f.Receive(tt.token, tt.event); !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+	if n := countNamed("room.federated"); n != 0 {
+		t.Errorf("expected rejected events not to be emitted, got %d", n)
+	}
+
+	// Delivery failures are reported without being published
+	down := newTestFederation(t, "generator_room", "elevator_room", "http://127.0.0.1:1", nil)
+	down.Handle(events.Event{Seq: 1, Name: "puzzle.solved", Fields: map[string]interface{}{}})
+	if n := countNamed("system.error"); n != 1 {
+		t.Errorf("expected 1 system.error for an unreachable peer, got %d", n)
+	}
+}
+
+// countNamed counts buffered events by name only.
+func countNamed(name string) int {
+	n := 0
+	for _, e := range events.Snapshot() {
+		if e.Name == name {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,73 @@
+package orchestrator
+
+// gateSatisfied reports whether a gate node's join condition currently holds:
+// every all_of entry and at least one any_of entry, each either a node ID in
+// the active scene (completed or overridden) or a condition expression.
+func (r *Runtime) gateSatisfied(node *Node) bool {
+	allOf := configStrings(node.Config, "all_of")
+	anyOf := configStrings(node.Config, "any_of")
+	if len(allOf) == 0 && len(anyOf) == 0 {
+		return true
+	}
+
+	for _, entry := range allOf {
+		if !r.gateEntrySatisfied(entry) {
+			return false
+		}
+	}
+
+	if len(anyOf) == 0 {
+		return true
+	}
+	for _, entry := range anyOf {
+		if r.gateEntrySatisfied(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// gateEntrySatisfied evaluates a single gate entry.
+func (r *Runtime) gateEntrySatisfied(entry string) bool {
+	if status, ok := r.nodeStates[entry]; ok && r.findNode(entry) != nil {
+		return status.State == NodeStateCompleted || status.State == NodeStateOverridden
+	}
+	return EvalCondition(entry, r.evalContext())
+}
+
+// evaluateGates completes any active gate whose join condition now holds.
+func (r *Runtime) evaluateGates() {
+	if r.activeScene == nil {
+		return
+	}
+	for i := range r.activeScene.Nodes {
+		node := &r.activeScene.Nodes[i]
+		if node.Type != "gate" {
+			continue
+		}
+		status := r.nodeStates[node.ID]
+		if status == nil || status.State != NodeStateActive {
+			continue
+		}
+		if r.gateSatisfied(node) {
+			r.completeNode(node.ID)
+		}
+	}
+}
+
+// configStrings reads a string list from node config. JSON arrays decode as []interface{}.
+func configStrings(config map[string]interface{}, key string) []string {
+	switch v := config[key].(type) {
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	case []string:
+		return v
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package orchestrator
+
+import (
+	"testing"
+)
+
+// gateSceneGraph builds a scene where two actions fan out and a gate joins them.
+func gateSceneGraph(gateConfig map[string]interface{}) *SceneGraph {
+	return sceneGraph(Scene{
+		ID:    "scene_gate",
+		Entry: "start",
+		Nodes: []Node{
+			{ID: "start", Type: "parallel", Config: map[string]interface{}{
+				"children": []interface{}{"puzzle_a", "puzzle_b"},
+			}},
+			{ID: "puzzle_a", Type: "puzzle", Config: map[string]interface{}{"subgraph": "sub_a"}},
+			{ID: "puzzle_b", Type: "puzzle", Config: map[string]interface{}{"subgraph": "sub_b"}},
+			{ID: "join", Type: "gate", Config: gateConfig},
+			{ID: "done", Type: "terminal", Config: map[string]interface{}{}},
+		},
+		Edges: []Edge{
+			{From: "start", To: "join"},
+			{From: "join", To: "done"},
+		},
+		Subgraphs: []Subgraph{
+			{ID: "sub_a", Entry: "a_wait", Nodes: []Node{{ID: "a_wait", Type: "decision"}}},
+			{ID: "sub_b", Entry: "b_wait", Nodes: []Node{{ID: "b_wait", Type: "decision"}}},
+		},
+	})
+}
+
+// startGate activates the gate directly so it waits on the puzzles.
+func startGate(t *testing.T, rt *Runtime) {
+	t.Helper()
+	if err := rt.StartScene("scene_gate"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+	rt.mu.Lock()
+	rt.activateNode("join")
+	rt.mu.Unlock()
+}
+
+func TestGateAllOf(t *testing.T) {
+	rt := NewRuntime(gateSceneGraph(map[string]interface{}{
+		"all_of": []interface{}{"puzzle_a", "puzzle_b.resolved"},
+	}))
+	startGate(t, rt)
+
+	if rt.GetNodeState("join") != NodeStateActive {
+		t.Fatalf("expected gate to wait, got %s", rt.GetNodeState("join"))
+	}
+
+	if err := rt.OverrideNode("puzzle_a"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+	if rt.GetNodeState("join") != NodeStateActive {
+		t.Errorf("expected gate to wait for puzzle_b, got %s", rt.GetNodeState("join"))
+	}
+
+	if err := rt.OverrideNode("puzzle_b"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+	if rt.GetNodeState("join") != NodeStateCompleted {
+		t.Errorf("expected gate to complete, got %s", rt.GetNodeState("join"))
+	}
+	if rt.GetNodeState("done") != NodeStateCompleted {
+		t.Errorf("expected terminal after gate, got %s", rt.GetNodeState("done"))
+	}
+}
+
+func TestGateAnyOf(t *testing.T) {
+	rt := NewRuntime(gateSceneGraph(map[string]interface{}{
+		"any_of": []interface{}{"puzzle_a", "puzzle_b"},
+	}))
+	startGate(t, rt)
+
+	if err := rt.OverrideNode("puzzle_b"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+	if rt.GetNodeState("join") != NodeStateCompleted {
+		t.Errorf("expected gate to complete on any_of, got %s", rt.GetNodeState("join"))
+	}
+}
+
+func TestGateAllOfAndAnyOf(t *testing.T) {
+	rt := NewRuntime(gateSceneGraph(map[string]interface{}{
+		"all_of": []interface{}{"puzzle_a"},
+		"any_of": []interface{}{"puzzle_b.resolved", "missing_node.resolved"},
+	}))
+	startGate(t, rt)
+
+	if err := rt.OverrideNode("puzzle_a"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+	if rt.GetNodeState("join") != NodeStateActive {
+		t.Errorf("expected gate to wait for any_of, got %s", rt.GetNodeState("join"))
+	}
+
+	if err := rt.OverrideNode("puzzle_b"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+	if rt.GetNodeState("join") != NodeStateCompleted {
+		t.Errorf("expected gate to complete, got %s", rt.GetNodeState("join"))
+	}
+}
+
+func TestGateEmptyCompletesImmediately(t *testing.T) {
+	rt := NewRuntime(gateSceneGraph(map[string]interface{}{}))
+	startGate(t, rt)
+
+	if rt.GetNodeState("join") != NodeStateCompleted {
+		t.Errorf("expected empty gate to complete immediately, got %s", rt.GetNodeState("join"))
+	}
+}
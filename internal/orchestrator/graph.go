@@ -4,6 +4,25 @@ package orchestrator
 type SceneGraph struct {
 	Version int     `json:"version"`
 	Scenes  []Scene `json:"scenes"`
+
+	Transitions []SceneTransition `json:"transitions,omitempty"` // scene chaining (see scenechain.go)
+
+	legacyEvents []string // legacy event names translated when the graph was loaded
+}
+
+// LegacyEvents returns the legacy event names the graph used, which were
+// translated to their current names when it was loaded.
+func (sg *SceneGraph) LegacyEvents() []string {
+	return sg.legacyEvents
+}
+
+// SceneTransition moves a game on to another scene when a scene completes.
+// The first transition from the completed scene whose condition holds is taken.
+type SceneTransition struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Condition string `json:"condition,omitempty"`
+	DelayMs   int    `json:"delay_ms,omitempty"`
 }
 
 // Scene is a container with nodes, edges, and subgraphs.
@@ -15,14 +34,28 @@ type Scene struct {
 	Nodes     []Node     `json:"nodes"`
 	Edges     []Edge     `json:"edges"`
 	Subgraphs []Subgraph `json:"subgraphs"`
+
+	Invariants []Invariant `json:"invariants,omitempty"`
+	Notes      string      `json:"notes,omitempty"` // operator runbook (markdown)
+
+	ResetCooldownMs *int `json:"reset_cooldown_ms,omitempty"` // overrides the room's re-solve cooldown
+}
+
+// Invariant is a condition the runtime asserts while a game runs.
+// With Node set it applies once that node has been activated.
+type Invariant struct {
+	Name      string `json:"name"`
+	Node      string `json:"node,omitempty"`
+	Condition string `json:"condition"`
 }
 
 // Node represents a node in the scene or subgraph.
-// Allowed types: action, puzzle, decision, timer, parallel, loop, gate, checkpoint, operator, random, subgraph, terminal
+// Allowed types: action, puzzle, decision, counter, timer, parallel, loop, gate, checkpoint, operator, random, subgraph, terminal
 type Node struct {
 	ID     string                 `json:"id"`
 	Type   string                 `json:"type"`
 	Config map[string]interface{} `json:"config"`
+	Notes  string                 `json:"notes,omitempty"` // operator runbook (markdown)
 }
 
 // Edge represents a transition between nodes.
@@ -0,0 +1,155 @@
+package orchestrator
+
+import (
+	"sort"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// HandoverEventNames lists the persisted events the shift handover digest reads.
+var HandoverEventNames = append(append([]string{}, SessionEventNames...),
+	"device.connected",
+	"device.disconnected",
+	"device.error",
+	"room.maintenance_started",
+)
+
+// HandoverDigest summarizes what happened during a shift.
+type HandoverDigest struct {
+	Since            string                `json:"since"`
+	Until            string                `json:"until"`
+	SessionsRun      int                   `json:"sessions_run"`
+	Sessions         []SessionSummary      `json:"sessions"`
+	Overrides        []HandoverOverride    `json:"overrides"`
+	DeviceIssues     []HandoverDeviceIssue `json:"device_issues"`
+	MaintenanceFlags []HandoverDeviceIssue `json:"maintenance_flags"`
+}
+
+// HandoverOverride records one operator override.
+type HandoverOverride struct {
+	NodeID string `json:"node_id"`
+	At     string `json:"at"`
+}
+
+// HandoverDeviceIssue is a device problem not yet cleared by a reconnect.
+type HandoverDeviceIssue struct {
+	DeviceID string `json:"device_id"`
+	Event    string `json:"event"`
+	Level    string `json:"level"`
+	Message  string `json:"message,omitempty"`
+	Anomaly  string `json:"anomaly,omitempty"`
+	Count    int    `json:"count"`
+	LastAt   string `json:"last_at"`
+}
+
+// BuildHandoverDigest summarizes persisted events (any order) between since and until.
+// Device issues are unresolved when no later device.connected was seen for that device;
+// maintenance flags are the failures of the most recent maintenance run.
+func BuildHandoverDigest(rows []storage.EventRow, since, until time.Time) *HandoverDigest {
+	sorted := append([]storage.EventRow(nil), rows...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Timestamp.Equal(sorted[j].Timestamp) {
+			return sorted[i].EventID < sorted[j].EventID
+		}
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	digest := &HandoverDigest{
+		Since:            since.UTC().Format(time.RFC3339),
+		Until:            until.UTC().Format(time.RFC3339),
+		Sessions:         []SessionSummary{},
+		Overrides:        []HandoverOverride{},
+		DeviceIssues:     []HandoverDeviceIssue{},
+		MaintenanceFlags: []HandoverDeviceIssue{},
+	}
+
+	issues := make(map[string]*HandoverDeviceIssue)
+	flags := make(map[string]*HandoverDeviceIssue)
+
+	for _, row := range sorted {
+		if row.Timestamp.After(until) {
+			break
+		}
+		inWindow := !row.Timestamp.Before(since)
+		at := row.Timestamp.UTC().Format(time.RFC3339)
+
+		switch row.Event {
+		case "operator.override":
+			if !inWindow {
+				continue
+			}
+			digest.Overrides = append(digest.Overrides, HandoverOverride{
+				NodeID: extractNodeID(row.Fields),
+				At:     at,
+			})
+
+		case "device.connected":
+			delete(issues, stringField(row.Fields, "logical_id"))
+
+		case "room.maintenance_started":
+			// A new run supersedes earlier results
+			flags = make(map[string]*HandoverDeviceIssue)
+
+		case "device.disconnected", "device.error":
+			deviceID := stringField(row.Fields, "logical_id")
+			if deviceID == "" {
+				deviceID = stringField(row.Fields, "device_id")
+			}
+			if deviceID == "" {
+				deviceID = stringField(row.Fields, "controller_id")
+			}
+			if deviceID == "" {
+				continue
+			}
+
+			target := issues
+			if maint, _ := row.Fields["maintenance"].(bool); maint {
+				target = flags
+			}
+			issue, ok := target[deviceID]
+			if !ok {
+				issue = &HandoverDeviceIssue{DeviceID: deviceID}
+				target[deviceID] = issue
+			}
+			issue.Event = row.Event
+			issue.Level = row.Level
+			issue.Message = ""
+			if row.Message != nil {
+				issue.Message = *row.Message
+			}
+			issue.Anomaly = stringField(row.Fields, "anomaly")
+			issue.Count++
+			issue.LastAt = at
+		}
+	}
+
+	// Keep sessions that overlap the window
+	for _, s := range BuildSessionSummaries(sorted) {
+		if s.StartedAt.After(until) || (s.EndedAt != nil && s.EndedAt.Before(since)) {
+			continue
+		}
+		digest.Sessions = append(digest.Sessions, s)
+	}
+	digest.SessionsRun = len(digest.Sessions)
+
+	digest.DeviceIssues = sortedIssues(issues)
+	digest.MaintenanceFlags = sortedIssues(flags)
+	return digest
+}
+
+// sortedIssues returns issues ordered by device ID.
+func sortedIssues(m map[string]*HandoverDeviceIssue) []HandoverDeviceIssue {
+	out := make([]HandoverDeviceIssue, 0, len(m))
+	for _, issue := range m {
+		out = append(out, *issue)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].DeviceID < out[j].DeviceID })
+	return out
+}
+
+// stringField returns a string field or "" if absent.
+func stringField(fields map[string]interface{}, key string) string {
+	s, _ := fields[key].(string)
+	return s
+}
@@ -0,0 +1,70 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+func TestBuildHandoverDigest(t *testing.T) {
+	now := time.Now()
+	at := func(minutesAgo int) time.Time { return now.Add(-time.Duration(minutesAgo) * time.Minute) }
+	msg := "heartbeat timeout"
+
+	rows := []storage.EventRow{
+		// Session that ended before the window
+		{EventID: 1, Timestamp: at(900), Event: "scene.started", Fields: map[string]interface{}{"scene_id": "scene_intro"}},
+		{EventID: 2, Timestamp: at(850), Event: "scene.reset", Fields: map[string]interface{}{"scene_id": "scene_intro"}},
+		// Completed session with an override
+		{EventID: 3, Timestamp: at(300), Event: "scene.started", Fields: map[string]interface{}{"scene_id": "scene_intro"}},
+		{EventID: 4, Timestamp: at(280), Event: "operator.override", Fields: map[string]interface{}{"node_id": "puzzle_scarab"}},
+		{EventID: 5, Timestamp: at(250), Event: "scene.completed", Fields: map[string]interface{}{"scene_id": "scene_intro"}},
+		{EventID: 6, Timestamp: at(249), Event: "scene.reset", Fields: map[string]interface{}{"scene_id": "scene_intro"}},
+		// Session still running
+		{EventID: 7, Timestamp: at(30), Event: "scene.started", Fields: map[string]interface{}{"scene_id": "scene_intro"}},
+		// Device errors: one cleared by reconnect, one outstanding
+		{EventID: 8, Timestamp: at(200), Level: "warning", Event: "device.disconnected", Message: &msg,
+			Fields: map[string]interface{}{"logical_id": "crypt_door"}},
+		{EventID: 9, Timestamp: at(190), Event: "device.connected", Fields: map[string]interface{}{"logical_id": "crypt_door"}},
+		{EventID: 10, Timestamp: at(20), Level: "warning", Event: "device.error",
+			Fields: map[string]interface{}{"logical_id": "fog_machine", "anomaly": "silent"}},
+		{EventID: 11, Timestamp: at(10), Level: "warning", Event: "device.error",
+			Fields: map[string]interface{}{"logical_id": "fog_machine", "anomaly": "silent"}},
+		// Maintenance: an older run's failure is superseded by the latest run
+		{EventID: 12, Timestamp: at(700), Event: "room.maintenance_started"},
+		{EventID: 13, Timestamp: at(699), Level: "warning", Event: "device.error",
+			Fields: map[string]interface{}{"device_id": "led_strip", "maintenance": true}},
+		{EventID: 14, Timestamp: at(400), Event: "room.maintenance_started"},
+		{EventID: 15, Timestamp: at(399), Level: "warning", Event: "device.error",
+			Fields: map[string]interface{}{"device_id": "dead_relay", "maintenance": true}},
+	}
+
+	digest := BuildHandoverDigest(rows, now.Add(-12*time.Hour+time.Minute), now)
+
+	if digest.SessionsRun != 2 {
+		t.Fatalf("expected 2 sessions in window, got %d: %+v", digest.SessionsRun, digest.Sessions)
+	}
+	if !digest.Sessions[0].Completed || digest.Sessions[0].Overrides != 1 {
+		t.Errorf("expected first session completed with one override, got %+v", digest.Sessions[0])
+	}
+	if digest.Sessions[1].EndedAt != nil {
+		t.Errorf("expected running session without end, got %+v", digest.Sessions[1])
+	}
+
+	if len(digest.Overrides) != 1 || digest.Overrides[0].NodeID != "puzzle_scarab" {
+		t.Errorf("expected one override of puzzle_scarab, got %+v", digest.Overrides)
+	}
+
+	if len(digest.DeviceIssues) != 1 {
+		t.Fatalf("expected one unresolved device issue, got %+v", digest.DeviceIssues)
+	}
+	issue := digest.DeviceIssues[0]
+	if issue.DeviceID != "fog_machine" || issue.Count != 2 || issue.Anomaly != "silent" {
+		t.Errorf("unexpected device issue: %+v", issue)
+	}
+
+	if len(digest.MaintenanceFlags) != 1 || digest.MaintenanceFlags[0].DeviceID != "dead_relay" {
+		t.Errorf("expected dead_relay maintenance flag, got %+v", digest.MaintenanceFlags)
+	}
+}
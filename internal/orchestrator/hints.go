@@ -0,0 +1,182 @@
+package orchestrator
+
+import (
+	"fmt"
+	"time"
+)
+
+// Sources reported by puzzle.hint.
+const (
+	HintOperator = "operator"
+	HintAuto     = "auto"
+)
+
+// hintNodePrefix marks device commands issued for hints.
+const hintNodePrefix = "hint:"
+
+// Hint is one hint of a puzzle node for players who are stuck, read from its
+// hints config (see design/scene-graph/schema.md). It is sent as a device
+// command to a screen or audio prop.
+type Hint struct {
+	Text     string
+	DeviceID string
+	Signal   string
+	Payload  interface{}
+}
+
+// hintKey returns the scheduler key for a puzzle's next auto hint.
+func hintKey(nodeID string) string {
+	return "hint:" + nodeID
+}
+
+// puzzleHints reads a puzzle node's hints.
+func puzzleHints(node *Node) []Hint {
+	raw, _ := node.Config["hints"].([]interface{})
+	hints := make([]Hint, 0, len(raw))
+	for _, item := range raw {
+		entry, _ := item.(map[string]interface{})
+		h := Hint{Payload: entry["payload"]}
+		h.Text, _ = entry["text"].(string)
+		h.DeviceID, _ = entry["device_id"].(string)
+		h.Signal, _ = entry["signal"].(string)
+		if h.Payload == nil {
+			h.Payload = map[string]interface{}{"text": h.Text}
+		}
+		hints = append(hints, h)
+	}
+	return hints
+}
+
+// validateHints checks a puzzle node's hints and auto hint delay.
+func validateHints(node Node) error {
+	raw, present := node.Config["hints"]
+	if present {
+		list, ok := raw.([]interface{})
+		if !ok {
+			return fmt.Errorf("node %s: hints must be a list", node.ID)
+		}
+		for i, item := range list {
+			entry, ok := item.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("node %s hints[%d]: must be an object", node.ID, i)
+			}
+			for _, key := range []string{"text", "device_id", "signal"} {
+				if s, _ := entry[key].(string); s == "" {
+					return fmt.Errorf("node %s hints[%d]: missing %s", node.ID, i, key)
+				}
+			}
+		}
+	}
+	if ms, ok := configInt(node.Config, "auto_hint_ms"); ok && ms < 0 {
+		return fmt.Errorf("node %s: auto_hint_ms must not be negative", node.ID)
+	}
+	return nil
+}
+
+// GiveHint sends a hint of an active puzzle to the players. A negative index
+// gives the next hint not given yet. It returns the index of the hint given.
+func (r *Runtime) GiveHint(nodeID string, index int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.activeScene == nil {
+		return 0, fmt.Errorf("no active game")
+	}
+	node := r.findNode(nodeID)
+	if node == nil || node.Type != "puzzle" {
+		return 0, fmt.Errorf("puzzle not found: %s", nodeID)
+	}
+	if r.nodeStates[nodeID].State != NodeStateActive {
+		return 0, fmt.Errorf("puzzle %s is not active", nodeID)
+	}
+
+	hints := puzzleHints(node)
+	if index < 0 {
+		index = r.hintsGiven[nodeID]
+	}
+	if index >= len(hints) {
+		return 0, fmt.Errorf("puzzle %s has no hint %d (%d hints)", nodeID, index, len(hints))
+	}
+	r.deliverHint(node, hints, index, HintOperator)
+	return index, nil
+}
+
+// deliverHint sends a hint as a device command, records it and restarts the
+// puzzle's idle time.
+// Caller must hold r.mu.
+func (r *Runtime) deliverHint(node *Node, hints []Hint, index int, source string) {
+	h := hints[index]
+	if r.actionExecutor != nil {
+		// The executor reports its own failures as device.error
+		_ = r.actionExecutor.ExecuteAction(hintNodePrefix+node.ID, map[string]interface{}{
+			"action": "device.command",
+			"params": map[string]interface{}{
+				"device_id": h.DeviceID,
+				"signal":    h.Signal,
+				"payload":   h.Payload,
+			},
+		})
+	}
+
+	r.emitEvent("puzzle.hint", map[string]interface{}{
+		"node_id": node.ID,
+		"hint":    index,
+		"text":    h.Text,
+		"source":  source,
+	})
+	if index >= r.hintsGiven[node.ID] {
+		r.hintsGiven[node.ID] = index + 1
+	}
+	r.scheduleAutoHint(node)
+}
+
+// scheduleAutoHint (re)starts a puzzle's idle time before its next auto hint.
+// Caller must hold r.mu.
+func (r *Runtime) scheduleAutoHint(node *Node) {
+	ms, ok := configInt(node.Config, "auto_hint_ms")
+	hints := puzzleHints(node)
+	if !ok || ms <= 0 || r.hintsGiven[node.ID] >= len(hints) {
+		r.cancelScheduled(hintKey(node.ID))
+		return
+	}
+	nodeID := node.ID
+	r.schedule(hintKey(nodeID), time.Duration(ms)*time.Millisecond, func() {
+		node := r.findNode(nodeID)
+		if node == nil || r.nodeStates[nodeID].State != NodeStateActive {
+			return
+		}
+		r.deliverHint(node, puzzleHints(node), r.hintsGiven[nodeID], HintAuto)
+	})
+}
+
+// noteHintActivity restarts a puzzle's idle time when evt matches one of its
+// waiting subgraph nodes.
+// Caller must hold r.mu.
+func (r *Runtime) noteHintActivity(nodeID string, pr *PuzzleRuntime, evt Event) {
+	if _, pending := r.tasks[hintKey(nodeID)]; !pending || !pr.Matches(evt) {
+		return
+	}
+	if node := r.findNode(nodeID); node != nil {
+		r.scheduleAutoHint(node)
+	}
+}
+
+// HintsGiven returns how many of a puzzle's hints were given this scene.
+func (r *Runtime) HintsGiven(nodeID string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.hintsGiven[nodeID]
+}
+
+// HasHints reports whether any puzzle in the graph has hints.
+func (sg *SceneGraph) HasHints() bool {
+	for _, scene := range sg.Scenes {
+		for i := range scene.Nodes {
+			if scene.Nodes[i].Type == "puzzle" && len(puzzleHints(&scene.Nodes[i])) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
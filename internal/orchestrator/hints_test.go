@@ -0,0 +1,142 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// hintSceneGraph is the button counter puzzle with a screen and an audio hint.
+func hintSceneGraph(autoHintMs int) *SceneGraph {
+	sg := counterSceneGraph(3)
+	puzzle := &sg.Scenes[0].Nodes[0]
+	puzzle.Config["hints"] = []interface{}{
+		map[string]interface{}{"text": "Press the button", "device_id": "hint_screen", "signal": "show_hint"},
+		map[string]interface{}{"text": "Three times", "device_id": "crypt_speaker", "signal": "play",
+			"payload": map[string]interface{}{"file": "hint_three.mp3"}},
+	}
+	puzzle.Config["auto_hint_ms"] = float64(autoHintMs)
+	return sg
+}
+
+// hintEvents returns the puzzle.hint events emitted so far.
+func hintEvents() []events.Event {
+	var hints []events.Event
+	for _, e := range events.Snapshot() {
+		if e.Name == "puzzle.hint" {
+			hints = append(hints, e)
+		}
+	}
+	return hints
+}
+
+func TestGiveHint(t *testing.T) {
+	exec := &replyingExecutor{}
+	rt := startGame(t, hintSceneGraph(0), "scene_counter", func(rt *Runtime) {
+		rt.SetActionExecutor(exec)
+	})
+
+	for want := 0; want < 2; want++ {
+		given, err := rt.GiveHint("puzzle_presses", -1)
+		if err != nil || given != want {
+			t.Fatalf("expected hint %d, got %d (%v)", want, given, err)
+		}
+	}
+	if _, err := rt.GiveHint("puzzle_presses", -1); err == nil {
+		t.Error("expected error once every hint was given")
+	}
+	if _, err := rt.GiveHint("puzzle_presses", 0); err != nil {
+		t.Errorf("expected an earlier hint to be repeatable: %v", err)
+	}
+	if _, err := rt.GiveHint("hint", -1); err == nil {
+		t.Error("expected error for a node that is not a puzzle")
+	}
+
+	if got := exec.commands; len(got) != 3 || got[0] != "hint_screen:show_hint" || got[1] != "crypt_speaker:play" {
+		t.Errorf("unexpected hint commands: %v", got)
+	}
+	hints := hintEvents()
+	if len(hints) != 3 || hints[1].Fields["text"] != "Three times" || hints[1].Fields["source"] != HintOperator {
+		t.Errorf("unexpected puzzle.hint events: %v", hints)
+	}
+	if n := rt.HintsGiven("puzzle_presses"); n != 2 {
+		t.Errorf("expected 2 hints given, got %d", n)
+	}
+}
+
+func TestAutoHint(t *testing.T) {
+	exec := &replyingExecutor{}
+	rt := startGame(t, hintSceneGraph(60000), "scene_counter", func(rt *Runtime) {
+		rt.SetActionExecutor(exec)
+	})
+	key := hintKey("puzzle_presses")
+
+	if !scheduled(rt, key) {
+		t.Fatal("expected an auto hint pending once the puzzle is active")
+	}
+	fire(t, rt, key)
+	fire(t, rt, key)
+	if scheduled(rt, key) {
+		t.Error("expected no auto hint after the last one")
+	}
+	if len(exec.commands) != 2 {
+		t.Errorf("expected both hints delivered, got %v", exec.commands)
+	}
+	for _, e := range hintEvents() {
+		if e.Fields["source"] != HintAuto {
+			t.Errorf("expected source %s, got %v", HintAuto, e.Fields["source"])
+		}
+	}
+}
+
+func TestAutoHintStopsWhenSolved(t *testing.T) {
+	rt := startGame(t, hintSceneGraph(60000), "scene_counter", func(rt *Runtime) {
+		rt.SetActionExecutor(&replyingExecutor{})
+	})
+
+	press(rt, "button")
+	if !scheduled(rt, hintKey("puzzle_presses")) {
+		t.Fatal("expected matching input to restart the idle time")
+	}
+	press(rt, "button")
+	press(rt, "button")
+	if scheduled(rt, hintKey("puzzle_presses")) {
+		t.Error("expected the auto hint cancelled once the puzzle is solved")
+	}
+	if _, err := rt.GiveHint("puzzle_presses", -1); err == nil {
+		t.Error("expected error for a solved puzzle")
+	}
+}
+
+func TestRestoreHints(t *testing.T) {
+	rt := startGame(t, hintSceneGraph(60000), "scene_counter", func(rt *Runtime) {
+		rt.SetActionExecutor(&replyingExecutor{})
+	})
+	if _, err := rt.GiveHint("puzzle_presses", -1); err != nil {
+		t.Fatalf("hint failed: %v", err)
+	}
+
+	restored := restartFromLog(t, hintSceneGraph(60000))
+	if n := restored.HintsGiven("puzzle_presses"); n != 1 {
+		t.Errorf("expected 1 hint given after restore, got %d", n)
+	}
+	if !scheduled(restored, hintKey("puzzle_presses")) {
+		t.Error("expected the resumed puzzle's auto hint pending")
+	}
+}
+
+func TestValidateHints(t *testing.T) {
+	for name, config := range map[string]map[string]interface{}{
+		"not a list":     {"hints": "Press the button"},
+		"missing text":   {"hints": []interface{}{map[string]interface{}{"device_id": "hint_screen", "signal": "show_hint"}}},
+		"missing signal": {"hints": []interface{}{map[string]interface{}{"text": "x", "device_id": "hint_screen"}}},
+		"negative delay": {"auto_hint_ms": float64(-1)},
+	} {
+		if err := validateHints(Node{ID: "puzzle_presses", Type: "puzzle", Config: config}); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if err := ValidateConditions(hintSceneGraph(60000)); err != nil {
+		t.Errorf("expected valid hints: %v", err)
+	}
+}
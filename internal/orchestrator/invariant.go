@@ -0,0 +1,70 @@
+package orchestrator
+
+import (
+	"fmt"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// checkInvariants evaluates the active scene's invariants and reports new violations.
+// A violation emits system.error once and again only after the invariant has
+// held in between; violations never change flow.
+func (r *Runtime) checkInvariants() {
+	if r.activeScene == nil || len(r.activeScene.Invariants) == 0 {
+		return
+	}
+
+	ctx := r.evalContext()
+	for _, inv := range r.activeScene.Invariants {
+		if inv.Node != "" {
+			status, ok := r.nodeStates[inv.Node]
+			if !ok || status.State == NodeStateIdle {
+				delete(r.violations, inv.Name)
+				continue
+			}
+		}
+
+		if EvalCondition(inv.Condition, ctx) {
+			delete(r.violations, inv.Name)
+			continue
+		}
+		if r.violations[inv.Name] {
+			continue
+		}
+		r.violations[inv.Name] = true
+		r.reportViolation(inv)
+	}
+}
+
+// reportViolation emits system.error with the state needed to diagnose a violation.
+func (r *Runtime) reportViolation(inv Invariant) {
+	puzzles := make(map[string]interface{}, len(r.puzzleStates))
+	for id, ps := range r.puzzleStates {
+		puzzles[id] = string(ps.Resolution)
+	}
+
+	fields := map[string]interface{}{
+		"invariant":     inv.Name,
+		"condition":     inv.Condition,
+		"scene_id":      r.activeScene.ID,
+		"puzzle_states": puzzles,
+		"active_nodes":  r.activeNodeIDs(),
+	}
+	if inv.Node != "" {
+		fields["node_id"] = inv.Node
+		fields["node_state"] = string(r.nodeStates[inv.Node].State)
+	}
+
+	events.Emit("error", "system.error", fmt.Sprintf("invariant violated: %s", inv.Name), fields)
+}
+
+// activeNodeIDs lists active nodes in scene order.
+func (r *Runtime) activeNodeIDs() []string {
+	ids := []string{}
+	for _, node := range r.activeScene.Nodes {
+		if status, ok := r.nodeStates[node.ID]; ok && status.State == NodeStateActive {
+			ids = append(ids, node.ID)
+		}
+	}
+	return ids
+}
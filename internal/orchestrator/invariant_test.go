@@ -0,0 +1,121 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// invariantSceneGraph has a buggy edge that reaches the finale before the puzzle resolves.
+func invariantSceneGraph(invariants ...Invariant) *SceneGraph {
+	return sceneGraph(Scene{
+		ID:    "scene_invariant",
+		Entry: "intro",
+		Nodes: []Node{
+			{ID: "intro", Type: "action", Config: map[string]interface{}{}},
+			{ID: "puzzle_a", Type: "puzzle", Config: map[string]interface{}{"subgraph": "sub_a"}},
+			{ID: "finale", Type: "operator", Config: map[string]interface{}{}},
+		},
+		Edges: []Edge{
+			{From: "intro", To: "puzzle_a"},
+			{From: "intro", To: "finale"},
+		},
+		Subgraphs: []Subgraph{
+			{ID: "sub_a", Entry: "a_wait", Nodes: []Node{{ID: "a_wait", Type: "decision"}}},
+		},
+		Invariants: invariants,
+	})
+}
+
+// violations returns system.error events reported for an invariant.
+func violations(name string) []events.Event {
+	var out []events.Event
+	for _, e := range events.Snapshot() {
+		if e.Name == "system.error" && e.Fields["invariant"] == name {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func TestInvariantViolationReported(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(invariantSceneGraph(Invariant{
+		Name:      "finale_after_puzzle",
+		Node:      "finale",
+		Condition: "puzzle_a.resolved",
+	}))
+
+	if err := rt.StartScene("scene_invariant"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+
+	got := violations("finale_after_puzzle")
+	if len(got) != 1 {
+		t.Fatalf("expected 1 violation, got %d", len(got))
+	}
+	fields := got[0].Fields
+	if fields["node_id"] != "finale" || fields["scene_id"] != "scene_invariant" {
+		t.Errorf("unexpected violation context: %v", fields)
+	}
+	if puzzles, _ := fields["puzzle_states"].(map[string]interface{}); puzzles["puzzle_a"] != "unresolved" {
+		t.Errorf("expected puzzle_states to show puzzle_a unresolved, got %v", fields["puzzle_states"])
+	}
+
+	// Further evaluation while still violated does not repeat the report
+	rt.InjectEvent("device.input", map[string]interface{}{"logical_id": "noise"})
+	if n := len(violations("finale_after_puzzle")); n != 1 {
+		t.Errorf("expected violation reported once, got %d", n)
+	}
+
+	// Invariants never change flow
+	if rt.GetNodeState("finale") != NodeStateActive {
+		t.Errorf("expected finale to stay active, got %s", rt.GetNodeState("finale"))
+	}
+}
+
+func TestInvariantHolds(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(invariantSceneGraph(Invariant{
+		Name:      "puzzle_waits",
+		Node:      "puzzle_a",
+		Condition: "!puzzle_a.resolved || intro.resolved",
+	}))
+
+	if err := rt.StartScene("scene_invariant"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+	if n := len(violations("puzzle_waits")); n != 0 {
+		t.Fatalf("expected no violation while puzzle unresolved, got %d", n)
+	}
+
+	if err := rt.OverrideNode("puzzle_a"); err != nil {
+		t.Fatalf("override failed: %v", err)
+	}
+	if n := len(violations("puzzle_waits")); n != 1 {
+		t.Fatalf("expected violation once puzzle resolved, got %d", n)
+	}
+
+	// Reset clears the violation so it can be reported again
+	if err := rt.ResetNode("puzzle_a"); err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+	if err := rt.OverrideNode("puzzle_a"); err != nil {
+		t.Fatalf("override failed: %v", err)
+	}
+	if n := len(violations("puzzle_waits")); n != 2 {
+		t.Errorf("expected second violation after reset, got %d", n)
+	}
+}
+
+func TestValidateInvariantUnknownNode(t *testing.T) {
+	sg := invariantSceneGraph(Invariant{Name: "bad", Node: "missing", Condition: "puzzle_a.resolved"})
+	if err := ValidateConditions(sg); err == nil {
+		t.Error("expected error for invariant on unknown node")
+	}
+
+	sg = invariantSceneGraph(Invariant{Name: "bad", Condition: "puzzle_a.resolved &&"})
+	if err := ValidateConditions(sg); err == nil {
+		t.Error("expected error for invalid invariant condition")
+	}
+}
@@ -0,0 +1,115 @@
+package orchestrator
+
+import "fmt"
+
+// JumpTo moves the running game to a node of the active scene, or of sceneID
+// when given. An empty nodeID starts sceneID at its entry node.
+func (r *Runtime) JumpTo(sceneID, nodeID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.activeScene == nil {
+		return fmt.Errorf("no active game")
+	}
+	if sceneID != "" && sceneID != r.activeScene.ID {
+		return r.switchScene(sceneID, nodeID, AdvanceOperator)
+	}
+	if r.findNode(nodeID) == nil {
+		return fmt.Errorf("node not found: %s", nodeID)
+	}
+
+	r.pushUndo("jump", nodeID)
+	r.jumpToNode(nodeID)
+	return nil
+}
+
+// jumpToNode overrides the unfinished nodes upstream of nodeID and activates
+// it, as if everything leading to it had run. Nodes that already ended keep
+// their state, and so do nodes on other branches and downstream of the
+// target. Caller must hold r.mu.
+func (r *Runtime) jumpToNode(nodeID string) {
+	upstream, enclosing := r.findUpstreamNodes(nodeID)
+
+	// A target that is running or already ran starts over
+	r.resetNodeState(nodeID, "")
+
+	for i := range r.activeScene.Nodes {
+		node := &r.activeScene.Nodes[i]
+		if !upstream[node.ID] {
+			continue
+		}
+		switch r.nodeStates[node.ID].State {
+		case NodeStateIdle, NodeStateActive:
+			r.overrideNode(node, map[string]interface{}{
+				"node_id": node.ID,
+				"jump_to": nodeID,
+			})
+		}
+	}
+
+	// Enclosing parallel blocks start with their skipped children overridden
+	for _, node := range r.activeScene.Nodes {
+		if enclosing[node.ID] && !upstream[node.ID] {
+			r.activateNode(node.ID)
+		}
+	}
+	r.activateNode(nodeID)
+
+	r.checkParallelCompletion()
+	r.evaluateAllConditions()
+}
+
+// findUpstreamNodes returns the nodes that lead to targetID along edges, and
+// the parallel nodes enclosing the target or one of those nodes.
+func (r *Runtime) findUpstreamNodes(targetID string) (upstream, enclosing map[string]bool) {
+	upstream = make(map[string]bool)
+	enclosing = make(map[string]bool)
+	visited := map[string]bool{targetID: true}
+	queue := []string{targetID}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, edge := range r.activeScene.Edges {
+			if edge.To == current && !visited[edge.From] {
+				visited[edge.From] = true
+				upstream[edge.From] = true
+				queue = append(queue, edge.From)
+			}
+		}
+
+		// A parallel child is entered through its parallel node
+		for _, node := range r.activeScene.Nodes {
+			if node.Type != "parallel" || !parallelHasChild(node, current) || visited[node.ID] {
+				continue
+			}
+			visited[node.ID] = true
+			enclosing[node.ID] = true
+			queue = append(queue, node.ID)
+		}
+	}
+
+	return upstream, enclosing
+}
+
+// parallelHasChild reports whether childID is one of a parallel node's children.
+func parallelHasChild(node Node, childID string) bool {
+	children, _ := node.Config["children"].([]interface{})
+	for _, child := range children {
+		if id, ok := child.(string); ok && id == childID {
+			return true
+		}
+	}
+	return false
+}
+
+// sceneHasNode reports whether a scene contains a node.
+func sceneHasNode(scene *Scene, nodeID string) bool {
+	for _, node := range scene.Nodes {
+		if node.ID == nodeID {
+			return true
+		}
+	}
+	return false
+}
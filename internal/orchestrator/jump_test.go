@@ -0,0 +1,127 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// jumpSceneGraph waits on an intro timer, then a button puzzle, then a
+// parallel block of two operator nodes before the terminal node.
+func jumpSceneGraph() *SceneGraph {
+	sg := counterSceneGraph(1)
+	scene := &sg.Scenes[0]
+	scene.Entry = "intro"
+	scene.Nodes = append(scene.Nodes,
+		Node{ID: "intro", Type: "timer", Config: map[string]interface{}{"duration_ms": float64(60000)}},
+		Node{ID: "finale", Type: "parallel", Config: map[string]interface{}{"children": []interface{}{"lights", "smoke"}}},
+		Node{ID: "lights", Type: "operator", Config: map[string]interface{}{}},
+		Node{ID: "smoke", Type: "operator", Config: map[string]interface{}{}},
+		Node{ID: "done", Type: "terminal"},
+	)
+	scene.Edges = append(scene.Edges,
+		Edge{From: "intro", To: "puzzle_presses"},
+		Edge{From: "puzzle_presses", To: "finale"},
+		Edge{From: "finale", To: "done"},
+	)
+	return sg
+}
+
+func TestJumpOverridesUpstream(t *testing.T) {
+	rt := startGame(t, jumpSceneGraph(), "scene_counter", nil)
+
+	if err := rt.JumpTo("", "lights"); err != nil {
+		t.Fatalf("jump failed: %v", err)
+	}
+
+	if rt.GetNodeState("intro") != NodeStateOverridden || countEvents("timer.cancelled", "intro") != 1 {
+		t.Errorf("expected the running intro timer cancelled and overridden, got %s", rt.GetNodeState("intro"))
+	}
+	if rt.GetPuzzleResolution("puzzle_presses") != PuzzleOverridden || countEvents("puzzle.overridden", "puzzle_presses") != 1 {
+		t.Errorf("expected the skipped puzzle overridden, got %s", rt.GetPuzzleResolution("puzzle_presses"))
+	}
+	for _, id := range []string{"finale", "lights", "smoke"} {
+		if rt.GetNodeState(id) != NodeStateActive {
+			t.Errorf("expected %s active in the entered parallel block, got %s", id, rt.GetNodeState(id))
+		}
+	}
+	if rt.GetNodeState("hint") != NodeStateIdle {
+		t.Errorf("expected the unrelated gate left alone, got %s", rt.GetNodeState("hint"))
+	}
+
+	for _, e := range events.Snapshot() {
+		if e.Name == "node.overridden" && e.Fields["jump_to"] != "lights" {
+			t.Errorf("expected node.overridden to carry jump_to, got %v", e.Fields)
+		}
+	}
+	if n := countNamed("node.overridden"); n != 2 {
+		t.Errorf("expected 2 skipped nodes, got %d", n)
+	}
+}
+
+func TestJumpCompletesScene(t *testing.T) {
+	rt := startGame(t, jumpSceneGraph(), "scene_counter", nil)
+
+	if err := rt.JumpTo("", "done"); err != nil {
+		t.Fatalf("jump failed: %v", err)
+	}
+	if rt.GetNodeState("finale") != NodeStateOverridden {
+		t.Errorf("expected the parallel block skipped, got %s", rt.GetNodeState("finale"))
+	}
+	if countNamed("scene.completed") != 1 {
+		t.Error("expected the scene to complete at the terminal node")
+	}
+}
+
+func TestJumpUndo(t *testing.T) {
+	rt := startGame(t, jumpSceneGraph(), "scene_counter", nil)
+
+	if err := rt.JumpTo("", "finale"); err != nil {
+		t.Fatalf("jump failed: %v", err)
+	}
+	action, err := rt.UndoLastAction()
+	if err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	if action.Action != "jump" || action.Target != "finale" {
+		t.Errorf("unexpected undone action: %+v", action)
+	}
+	if rt.GetNodeState("intro") != NodeStateActive || rt.GetNodeState("finale") != NodeStateIdle {
+		t.Errorf("expected the game back at the intro, got intro=%s finale=%s",
+			rt.GetNodeState("intro"), rt.GetNodeState("finale"))
+	}
+}
+
+func TestJumpToScene(t *testing.T) {
+	rt := startGame(t, chainedSceneGraph(), "scene_intro", nil)
+
+	if err := rt.JumpTo("scene_main", "missing"); err == nil {
+		t.Error("expected error for a node outside the scene")
+	}
+	if err := rt.JumpTo("scene_main", "main_done"); err != nil {
+		t.Fatalf("jump failed: %v", err)
+	}
+	if rt.ActiveSceneID() != "scene_main" {
+		t.Fatalf("expected scene_main, got %s", rt.ActiveSceneID())
+	}
+	if countEvents("puzzle.activated", "puzzle_presses") != 0 {
+		t.Error("expected the skipped entry puzzle not to start")
+	}
+	if rt.GetPuzzleResolution("puzzle_presses") != PuzzleOverridden {
+		t.Errorf("expected the skipped entry puzzle overridden, got %s", rt.GetPuzzleResolution("puzzle_presses"))
+	}
+	if !scheduled(rt, sceneTransitionKey) {
+		t.Error("expected the completed main scene to chain on")
+	}
+}
+
+func TestJumpUnknownNode(t *testing.T) {
+	rt := startGame(t, jumpSceneGraph(), "scene_counter", nil)
+
+	if err := rt.JumpTo("", "missing"); err == nil {
+		t.Error("expected error for unknown node")
+	}
+	if err := rt.JumpTo("scene_missing", ""); err == nil {
+		t.Error("expected error for unknown scene")
+	}
+}
@@ -0,0 +1,174 @@
+package orchestrator
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// DefaultKeyField is the payload field read by keypad nodes.
+const DefaultKeyField = "key"
+
+// keypadState is the entry and attempt count of a keypad node, which collects
+// keys from a code-entry device and compares the entry against a code (config
+// in design/scene-graph/schema.md).
+type keypadState struct {
+	entry    string
+	attempts int
+}
+
+// keypadCode returns the code a keypad expects, or "" if none is set.
+func (pr *PuzzleRuntime) keypadCode(node *Node) string {
+	if name, ok := node.Config["code_var"].(string); ok && name != "" {
+		return codeString(pr.vars[name])
+	}
+	return codeString(node.Config["code"])
+}
+
+// codeString formats a code or key value; numbers are written without a
+// fraction so 4721 and "4721" match.
+func codeString(v interface{}) string {
+	switch c := v.(type) {
+	case string:
+		return c
+	case float64:
+		return strconv.FormatFloat(c, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(c)
+	case int64:
+		return strconv.FormatInt(c, 10)
+	}
+	return ""
+}
+
+// randomCode draws a code of n digits.
+func randomCode(rng *rand.Rand, n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		var d int
+		if rng != nil {
+			d = rng.Intn(10)
+		} else {
+			d = rand.Intn(10)
+		}
+		b.WriteByte(byte('0' + d))
+	}
+	return b.String()
+}
+
+// startKeypad clears the entry and draws the session code if the keypad
+// generates one. The code is assigned with set_variable so it is recorded as
+// variable.set and survives restore.
+func (pr *PuzzleRuntime) startKeypad(node *Node) {
+	if pr.keypads == nil {
+		pr.keypads = make(map[string]*keypadState)
+	}
+	pr.keypads[node.ID] = &keypadState{}
+
+	name, _ := node.Config["code_var"].(string)
+	length, ok := configInt(node.Config, "code_length")
+	if name == "" || !ok || length <= 0 || pr.keypadCode(node) != "" {
+		return
+	}
+	code := randomCode(pr.rng, length)
+	if pr.actionFunc != nil {
+		pr.actionFunc(node.ID, map[string]interface{}{
+			"action": SetVariableAction,
+			"params": map[string]interface{}{"name": name, "value": code},
+		})
+	} else if pr.vars != nil {
+		pr.vars[name] = code
+	} else {
+		pr.vars = map[string]interface{}{name: code}
+	}
+}
+
+// keypadKeys extracts the pressed key(s) from an event payload.
+func keypadKeys(node *Node, evt *Event) string {
+	field, _ := node.Config["key_field"].(string)
+	if field == "" {
+		field = DefaultKeyField
+	}
+	if payload, ok := evt.Fields["payload"].(map[string]interface{}); ok {
+		return codeString(payload[field])
+	}
+	return codeString(evt.Fields["payload"])
+}
+
+// emitKeypadProgress reports a keypad's entry length and attempts.
+func (pr *PuzzleRuntime) emitKeypadProgress(node *Node, state *keypadState, code string, wrong bool) {
+	fields := map[string]interface{}{
+		"puzzle_id":   pr.parentNodeID,
+		"subgraph_id": pr.subgraph.ID,
+		"node_id":     node.ID,
+		"entered":     len(state.entry),
+		"length":      len(code),
+		"attempts":    state.attempts,
+	}
+	if maxAttempts, ok := configInt(node.Config, "max_attempts"); ok && maxAttempts > 0 {
+		fields["max_attempts"] = maxAttempts
+	}
+	if wrong {
+		fields["wrong"] = true
+	}
+	events.Emit("info", "puzzle.progress", "", fields)
+}
+
+// handleKeypad applies a key event to an active keypad node. Returns true once
+// the right code is entered; a puzzle that runs out of attempts is marked
+// failed instead.
+func (pr *PuzzleRuntime) handleKeypad(node *Node, ctx *EvalContext) bool {
+	match, _ := node.Config["match"].(string)
+	if match == "" || !EvalCondition(match, ctx) {
+		return false
+	}
+	state := pr.keypads[node.ID]
+	keys := keypadKeys(node, ctx.Event)
+	code := pr.keypadCode(node)
+	if state == nil || keys == "" || code == "" {
+		return false
+	}
+
+	submitKey, _ := node.Config["submit_key"].(string)
+	clearKey, _ := node.Config["clear_key"].(string)
+
+	switch keys {
+	case clearKey:
+		state.entry = ""
+		pr.emitKeypadProgress(node, state, code, false)
+		return false
+	case submitKey:
+		if state.entry == "" {
+			return false
+		}
+		return pr.submitKeypad(node, state, code)
+	}
+
+	state.entry += keys
+	if submitKey != "" || len(state.entry) < len(code) {
+		pr.emitKeypadProgress(node, state, code, false)
+		return false
+	}
+	return pr.submitKeypad(node, state, code)
+}
+
+// submitKeypad checks the entry against the code and counts a wrong attempt.
+func (pr *PuzzleRuntime) submitKeypad(node *Node, state *keypadState, code string) bool {
+	if state.entry == code {
+		state.entry = ""
+		return true
+	}
+
+	state.entry = ""
+	state.attempts++
+	pr.emitKeypadProgress(node, state, code, true)
+
+	maxAttempts, ok := configInt(node.Config, "max_attempts")
+	if ok && maxAttempts > 0 && state.attempts >= maxAttempts && !pr.generic {
+		pr.attempts = state.attempts
+		pr.Fail()
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package orchestrator
+
+import (
+	"maps"
+	"math/rand"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// keypadSceneGraph is a vault opened by a keypad; the puzzle fails over to a
+// lockout node.
+func keypadSceneGraph(keypad map[string]interface{}) *SceneGraph {
+	config := map[string]interface{}{
+		"match": "event == 'device.input' && logical_id == 'vault_keypad'",
+	}
+	maps.Copy(config, keypad)
+	scene := puzzleScene("scene_vault", "puzzle_vault", "vault", Node{ID: "vault_code", Type: "keypad", Config: config})
+	scene.Nodes = append(scene.Nodes, Node{ID: "lockout", Type: "gate"})
+	scene.Edges = []Edge{{From: "puzzle_vault", To: "lockout", Condition: "puzzle_vault.failed"}}
+	return sceneGraph(scene)
+}
+
+func enterKeys(rt *Runtime, keys ...interface{}) {
+	for _, k := range keys {
+		rt.InjectEvent("device.input", map[string]interface{}{
+			"logical_id": "vault_keypad",
+			"payload":    map[string]interface{}{"key": k},
+		})
+	}
+}
+
+func TestKeypadStaticCode(t *testing.T) {
+	sg := keypadSceneGraph(map[string]interface{}{"code": "4721"})
+	rt := startGame(t, sg, "scene_vault", nil)
+
+	enterKeys(rt, "4", "7", "2", "2")
+	if rt.GetPuzzleResolution("puzzle_vault") != PuzzleUnresolved {
+		t.Fatal("expected puzzle unresolved after a wrong code")
+	}
+	// Numeric keys and codes compare as digits
+	enterKeys(rt, 4.0, "7", "2", float64(1))
+	if rt.GetPuzzleResolution("puzzle_vault") != PuzzleSolved {
+		t.Errorf("expected puzzle solved, got %s", rt.GetPuzzleResolution("puzzle_vault"))
+	}
+	if n := countNamed("puzzle.solved"); n != 1 {
+		t.Errorf("expected one puzzle.solved, got %d", n)
+	}
+
+	var wrong int
+	for _, e := range events.Snapshot() {
+		if e.Name == "puzzle.progress" && e.Fields["wrong"] == true {
+			wrong++
+			if e.Fields["attempts"] != 1 || e.Fields["length"] != 4 {
+				t.Errorf("unexpected wrong-code progress %v", e.Fields)
+			}
+		}
+	}
+	if wrong != 1 {
+		t.Errorf("expected one wrong code reported, got %d", wrong)
+	}
+}
+
+func TestKeypadSubmitAndClearKeys(t *testing.T) {
+	rt := startGame(t, keypadSceneGraph(map[string]interface{}{
+		"code":       float64(123),
+		"submit_key": "#",
+		"clear_key":  "*",
+	}), "scene_vault", nil)
+
+	// The entry is not checked until submitted, and clear discards it
+	enterKeys(rt, "1", "2", "3", "4", "*", "#")
+	enterKeys(rt, "123")
+	if rt.GetPuzzleResolution("puzzle_vault") != PuzzleUnresolved {
+		t.Fatal("expected puzzle unresolved before submit")
+	}
+	enterKeys(rt, "#")
+	if rt.GetPuzzleResolution("puzzle_vault") != PuzzleSolved {
+		t.Errorf("expected puzzle solved, got %s", rt.GetPuzzleResolution("puzzle_vault"))
+	}
+	if n := countNamed("puzzle.failed"); n != 0 {
+		t.Errorf("expected no failure, got %d puzzle.failed", n)
+	}
+}
+
+func TestKeypadMaxAttempts(t *testing.T) {
+	sg := keypadSceneGraph(map[string]interface{}{"code": "99", "max_attempts": float64(2)})
+	rt := startGame(t, sg, "scene_vault", nil)
+
+	enterKeys(rt, "1", "2")
+	if rt.GetPuzzleResolution("puzzle_vault") != PuzzleUnresolved {
+		t.Fatal("expected puzzle unresolved after one wrong code")
+	}
+	enterKeys(rt, "3", "4")
+	if rt.GetPuzzleResolution("puzzle_vault") != PuzzleFailed {
+		t.Fatalf("expected puzzle failed, got %s", rt.GetPuzzleResolution("puzzle_vault"))
+	}
+
+	for _, e := range events.Snapshot() {
+		if e.Name == "puzzle.failed" {
+			if e.Fields["reason"] != "max_attempts" || e.Fields["attempts"] != 2 {
+				t.Errorf("unexpected puzzle.failed fields %v", e.Fields)
+			}
+		}
+	}
+	if n := countNamed("puzzle.failed"); n != 1 {
+		t.Errorf("expected one puzzle.failed, got %d", n)
+	}
+	if rt.nodeStates["lockout"].State == NodeStateIdle {
+		t.Error("expected the failure edge to be followed")
+	}
+
+	// The right code no longer solves a failed puzzle
+	enterKeys(rt, "9", "9")
+	if n := countNamed("puzzle.solved"); n != 0 {
+		t.Errorf("expected no puzzle.solved after failure, got %d", n)
+	}
+}
+
+func TestKeypadRandomCode(t *testing.T) {
+	sg := keypadSceneGraph(map[string]interface{}{"code_var": "vault_code", "code_length": float64(4)})
+	rt := startGame(t, sg, "scene_vault", func(rt *Runtime) {
+		rt.rng = rand.New(rand.NewSource(7))
+	})
+
+	code, _ := rt.Variables()["vault_code"].(string)
+	if len(code) != 4 {
+		t.Fatalf("expected a 4-digit code in vars.vault_code, got %q", code)
+	}
+	if n := countNamed("variable.set"); n != 1 {
+		t.Errorf("expected the code recorded as variable.set, got %d", n)
+	}
+
+	for _, digit := range code {
+		enterKeys(rt, string(digit))
+	}
+	if rt.GetPuzzleResolution("puzzle_vault") != PuzzleSolved {
+		t.Errorf("expected puzzle solved with the session code, got %s", rt.GetPuzzleResolution("puzzle_vault"))
+	}
+}
+
+func TestKeypadRandomCodeKeepsExistingVariable(t *testing.T) {
+	pr := NewPuzzleRuntime(&keypadSceneGraph(map[string]interface{}{
+		"code_var": "vault_code", "code_length": float64(4),
+	}).Scenes[0].Subgraphs[0], "puzzle_vault")
+	pr.SetVars(map[string]interface{}{"vault_code": "0042"})
+	pr.Start()
+
+	if got := pr.vars["vault_code"]; got != "0042" {
+		t.Errorf("expected the restored code kept, got %v", got)
+	}
+}
+
+func TestValidateKeypad(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		ok     bool
+	}{
+		{"static code", map[string]interface{}{"match": "event == 'device.input'", "code": "1234"}, true},
+		{"generated code", map[string]interface{}{"match": "event == 'device.input'", "code_var": "c", "code_length": float64(4)}, true},
+		{"missing match", map[string]interface{}{"code": "1234"}, false},
+		{"missing code", map[string]interface{}{"match": "event == 'device.input'"}, false},
+		{"both codes", map[string]interface{}{"match": "event == 'device.input'", "code": "1", "code_var": "c"}, false},
+		{"length without var", map[string]interface{}{"match": "event == 'device.input'", "code": "1", "code_length": float64(4)}, false},
+		{"reserved var", map[string]interface{}{"match": "event == 'device.input'", "code_var": "input"}, false},
+	}
+	for _, tt := range tests {
+		err := validateKeypad(Node{ID: "k", Type: "keypad", Config: tt.config})
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: got err %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
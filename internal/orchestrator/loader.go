@@ -2,8 +2,13 @@ package orchestrator
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
 )
 
 // LoadSceneGraph loads a scene graph from a JSON file.
@@ -13,14 +18,357 @@ func LoadSceneGraph(path string) (*SceneGraph, error) {
 		return nil, fmt.Errorf("failed to read scene graph file: %w", err)
 	}
 
+	// Graphs written against older registry revisions keep working
+	data, legacy, err := translateLegacyEvents(data, events.Aliases())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse scene graph JSON: %w", err)
+	}
+
 	var sg SceneGraph
 	if err := json.Unmarshal(data, &sg); err != nil {
 		return nil, fmt.Errorf("failed to parse scene graph JSON: %w", err)
 	}
+	sg.legacyEvents = legacy
+	for _, name := range legacy {
+		events.Translate(name, path)
+	}
 
 	if sg.Version != 1 {
 		return nil, fmt.Errorf("unsupported scene graph version: %d", sg.Version)
 	}
 
+	if err := ValidateSceneGraph(&sg); err != nil {
+		return nil, err
+	}
+
 	return &sg, nil
 }
+
+// translateLegacyEvents replaces legacy event names in a scene graph with
+// their current names: strings that are a legacy name (such as a subgraph's
+// resolved_true_event) and quoted legacy names within conditions. It returns
+// the graph unchanged when no legacy name is used, and the legacy names found.
+func translateLegacyEvents(data []byte, aliases map[string]string) ([]byte, []string, error) {
+	if len(aliases) == 0 {
+		return data, nil, nil
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	used := make(map[string]bool)
+	var walk func(v interface{}) interface{}
+	walk = func(v interface{}) interface{} {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for k, child := range v {
+				v[k] = walk(child)
+			}
+		case []interface{}:
+			for i, child := range v {
+				v[i] = walk(child)
+			}
+		case string:
+			if current, ok := aliases[v]; ok {
+				used[v] = true
+				return current
+			}
+			for legacy, current := range aliases {
+				for _, q := range []string{"'", `"`} {
+					if strings.Contains(v, q+legacy+q) {
+						used[legacy] = true
+						v = strings.ReplaceAll(v, q+legacy+q, q+current+q)
+					}
+				}
+			}
+			return v
+		}
+		return v
+	}
+	doc = walk(doc)
+	if len(used) == 0 {
+		return data, nil, nil
+	}
+
+	legacy := make([]string, 0, len(used))
+	for name := range used {
+		legacy = append(legacy, name)
+	}
+	sort.Strings(legacy)
+	out, err := json.Marshal(doc)
+	return out, legacy, err
+}
+
+// ValidateConditions compiles every condition in the graph (edge conditions,
+// loop stop conditions, gate entries, invariants and scene transitions) and checks action lists,
+// so mistakes surface at load time rather than as edges that silently never fire.
+// Every problem found is reported.
+func ValidateConditions(sg *SceneGraph) error {
+	return errors.Join(conditionErrors(sg)...)
+}
+
+// conditionErrors returns the problems ValidateConditions reports.
+func conditionErrors(sg *SceneGraph) []error {
+	errs := validateTransitions(sg)
+	for _, scene := range sg.Scenes {
+		sceneNodes := make(map[string]bool, len(scene.Nodes))
+		for _, node := range scene.Nodes {
+			sceneNodes[node.ID] = true
+		}
+
+		for _, err := range validateEdgeConditions(scene.Edges) {
+			errs = append(errs, fmt.Errorf("scene %s: %w", scene.ID, err))
+		}
+		for _, err := range validateNodeConditions(scene.Nodes, sceneNodes) {
+			errs = append(errs, fmt.Errorf("scene %s: %w", scene.ID, err))
+		}
+
+		for _, inv := range scene.Invariants {
+			if inv.Node != "" && !sceneNodes[inv.Node] {
+				errs = append(errs, fmt.Errorf("scene %s invariant %s: unknown node %s", scene.ID, inv.Name, inv.Node))
+			}
+			if _, err := CompileCondition(inv.Condition); err != nil {
+				errs = append(errs, fmt.Errorf("scene %s invariant %s: %w", scene.ID, inv.Name, err))
+			}
+		}
+
+		for _, sub := range scene.Subgraphs {
+			for _, err := range validateEdgeConditions(sub.Edges) {
+				errs = append(errs, fmt.Errorf("scene %s subgraph %s: %w", scene.ID, sub.ID, err))
+			}
+			for _, err := range validateNodeConditions(sub.Nodes, sceneNodes) {
+				errs = append(errs, fmt.Errorf("scene %s subgraph %s: %w", scene.ID, sub.ID, err))
+			}
+		}
+	}
+	return errs
+}
+
+// validateTransitions checks that scene transitions name known scenes and
+// compiles their conditions.
+func validateTransitions(sg *SceneGraph) []error {
+	scenes := make(map[string]bool, len(sg.Scenes))
+	for _, scene := range sg.Scenes {
+		scenes[scene.ID] = true
+	}
+	var errs []error
+	for _, t := range sg.Transitions {
+		if !scenes[t.From] || !scenes[t.To] {
+			errs = append(errs, fmt.Errorf("transition %s -> %s: unknown scene", t.From, t.To))
+		}
+		if t.DelayMs < 0 {
+			errs = append(errs, fmt.Errorf("transition %s -> %s: delay_ms must not be negative", t.From, t.To))
+		}
+		if _, err := CompileCondition(t.Condition); err != nil {
+			errs = append(errs, fmt.Errorf("transition %s -> %s: %w", t.From, t.To, err))
+		}
+	}
+	return errs
+}
+
+// validateEdgeConditions compiles the condition of each edge.
+func validateEdgeConditions(edges []Edge) []error {
+	var errs []error
+	for _, edge := range edges {
+		if _, err := CompileCondition(edge.Condition); err != nil {
+			errs = append(errs, fmt.Errorf("edge %s -> %s: %w", edge.From, edge.To, err))
+		}
+	}
+	return errs
+}
+
+// validateNodeConditions checks the config of each node (see validateNodeConfig).
+func validateNodeConditions(nodes []Node, sceneNodes map[string]bool) []error {
+	var errs []error
+	for _, node := range nodes {
+		if err := validateNodeConfig(node, sceneNodes); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// validateNodeConfig compiles loop stop conditions, gate entries and counter conditions,
+// and checks action lists, expected prop states, parallel quorums, keypad codes, threshold ranges, patterns and hints. Gate entries naming a scene node are node
+// references, not expressions.
+func validateNodeConfig(node Node, sceneNodes map[string]bool) error {
+	if err := validateActionList(node); err != nil {
+		return err
+	}
+	if err := validateRetry(node.Config); err != nil {
+		return fmt.Errorf("node %s: %w", node.ID, err)
+	}
+	if err := validateExpectations(node); err != nil {
+		return err
+	}
+	switch node.Type {
+	case "loop":
+		if expr, ok := node.Config["stop_condition"].(string); ok {
+			if _, err := CompileCondition(expr); err != nil {
+				return fmt.Errorf("node %s stop_condition: %w", node.ID, err)
+			}
+		}
+	case "counter":
+		for _, key := range []string{"match", "reset_on"} {
+			if expr, ok := node.Config[key].(string); ok {
+				if _, err := CompileCondition(expr); err != nil {
+					return fmt.Errorf("node %s %s: %w", node.ID, key, err)
+				}
+			}
+		}
+	case "keypad":
+		if err := validateKeypad(node); err != nil {
+			return err
+		}
+	case "threshold":
+		if err := validateThreshold(node); err != nil {
+			return err
+		}
+	case "pattern":
+		if err := validatePattern(node); err != nil {
+			return err
+		}
+	case "puzzle":
+		if err := validateHints(node); err != nil {
+			return err
+		}
+		if expr, ok := node.Config["attempt_on"].(string); ok {
+			if _, err := CompileCondition(expr); err != nil {
+				return fmt.Errorf("node %s attempt_on: %w", node.ID, err)
+			}
+		} else if _, ok := configInt(node.Config, "max_attempts"); ok {
+			return fmt.Errorf("node %s: max_attempts requires attempt_on", node.ID)
+		}
+	case "parallel":
+		if minComplete, ok := configInt(node.Config, "min_complete"); ok {
+			children, _ := node.Config["children"].([]interface{})
+			if minComplete < 1 || minComplete > len(children) {
+				return fmt.Errorf("node %s: min_complete must be between 1 and the number of children (%d)", node.ID, len(children))
+			}
+		}
+	case "gate":
+		for _, key := range []string{"all_of", "any_of"} {
+			for _, entry := range configStrings(node.Config, key) {
+				if sceneNodes[entry] {
+					continue
+				}
+				if _, err := CompileCondition(entry); err != nil {
+					return fmt.Errorf("node %s %s: %w", node.ID, key, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateKeypad checks a keypad node's match condition and code source.
+func validateKeypad(node Node) error {
+	match, _ := node.Config["match"].(string)
+	if match == "" {
+		return fmt.Errorf("node %s: keypad requires match", node.ID)
+	}
+	if _, err := CompileCondition(match); err != nil {
+		return fmt.Errorf("node %s match: %w", node.ID, err)
+	}
+
+	codeVar, _ := node.Config["code_var"].(string)
+	hasCode := codeString(node.Config["code"]) != ""
+	switch {
+	case hasCode && codeVar != "":
+		return fmt.Errorf("node %s: keypad takes code or code_var, not both", node.ID)
+	case !hasCode && codeVar == "":
+		return fmt.Errorf("node %s: keypad requires code or code_var", node.ID)
+	case codeVar == inputVar || codeVar == counterVar || strings.Contains(codeVar, "."):
+		return fmt.Errorf("node %s: invalid code_var %q", node.ID, codeVar)
+	}
+	if _, ok := node.Config["code_length"]; ok && codeVar == "" {
+		return fmt.Errorf("node %s: code_length requires code_var", node.ID)
+	}
+	return nil
+}
+
+// validateThreshold checks a threshold node's match condition and range.
+func validateThreshold(node Node) error {
+	match, _ := node.Config["match"].(string)
+	if match == "" {
+		return fmt.Errorf("node %s: threshold requires match", node.ID)
+	}
+	if _, err := CompileCondition(match); err != nil {
+		return fmt.Errorf("node %s match: %w", node.ID, err)
+	}
+
+	min, max, hasMin, hasMax, hysteresis := thresholdBounds(&node)
+	switch {
+	case !hasMin && !hasMax:
+		return fmt.Errorf("node %s: threshold requires min or max", node.ID)
+	case hasMin && hasMax && min > max:
+		return fmt.Errorf("node %s: min must not exceed max", node.ID)
+	case hysteresis < 0:
+		return fmt.Errorf("node %s: hysteresis must not be negative", node.ID)
+	}
+	for _, key := range []string{"hold_ms", "debounce_ms"} {
+		if ms, ok := configInt(node.Config, key); ok && ms < 0 {
+			return fmt.Errorf("node %s: %s must not be negative", node.ID, key)
+		}
+	}
+	return nil
+}
+
+// validatePattern checks a pattern node's match condition, outputs and pattern.
+func validatePattern(node Node) error {
+	match, _ := node.Config["match"].(string)
+	if match == "" {
+		return fmt.Errorf("node %s: pattern requires match", node.ID)
+	}
+	if _, err := CompileCondition(match); err != nil {
+		return fmt.Errorf("node %s match: %w", node.ID, err)
+	}
+
+	outputs, _ := node.Config["outputs"].(map[string]interface{})
+	if len(outputs) == 0 {
+		return fmt.Errorf("node %s: pattern requires outputs", node.ID)
+	}
+	for symbol, raw := range outputs {
+		config, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("node %s outputs.%s: must be an object", node.ID, symbol)
+		}
+		action, _ := config["action"].(string)
+		if _, ok := config[actionsKey]; !ok && action == "" {
+			return fmt.Errorf("node %s outputs.%s: missing action", node.ID, symbol)
+		}
+		if action == WaitAction {
+			return fmt.Errorf("node %s outputs.%s: wait is not allowed", node.ID, symbol)
+		}
+		if err := validateActionList(Node{ID: node.ID + " outputs." + symbol, Config: config}); err != nil {
+			return err
+		}
+		if err := validateRetry(config); err != nil {
+			return fmt.Errorf("node %s outputs.%s: %w", node.ID, symbol, err)
+		}
+	}
+
+	pattern := patternSymbols(&node)
+	length, hasLength := configInt(node.Config, "length")
+	switch {
+	case len(pattern) > 0 && hasLength:
+		return fmt.Errorf("node %s: pattern takes pattern or length, not both", node.ID)
+	case len(pattern) == 0 && (!hasLength || length <= 0):
+		return fmt.Errorf("node %s: pattern requires pattern or length", node.ID)
+	}
+	for _, symbol := range pattern {
+		if _, ok := outputs[symbol]; !ok {
+			return fmt.Errorf("node %s: pattern symbol %q has no output", node.ID, symbol)
+		}
+	}
+	for _, key := range []string{"step_ms", "speedup_ms", "min_step_ms"} {
+		if ms, ok := configInt(node.Config, key); ok && ms < 0 {
+			return fmt.Errorf("node %s: %s must not be negative", node.ID, key)
+		}
+	}
+	if onWrong, ok := node.Config["on_wrong"].(string); ok && onWrong != "repeat" && onWrong != "restart" {
+		return fmt.Errorf("node %s: on_wrong must be repeat or restart", node.ID)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTranslateLegacyEvents(t *testing.T) {
+	graph := []byte(`{
+  "version": 1,
+  "scenes": [{
+    "id": "s", "entry": "p",
+    "nodes": [{"id": "p", "type": "puzzle", "config": {"subgraph": "sub"}}],
+    "subgraphs": [{
+      "id": "sub", "entry": "w",
+      "nodes": [{"id": "w", "type": "decision", "config": {"expression": "event == 'tiles.done' && tile == 'tiles.done.not'"}}],
+      "outputs": {"resolved_true_event": "puzzle.completed"}
+    }]
+  }]
+}`)
+	aliases := map[string]string{"puzzle.completed": "puzzle.solved", "tiles.done": "puzzle.step"}
+
+	out, legacy, err := translateLegacyEvents(graph, aliases)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(legacy, ",") != "puzzle.completed,tiles.done" {
+		t.Errorf("unexpected legacy names: %v", legacy)
+	}
+	var sg SceneGraph
+	if err := json.Unmarshal(out, &sg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sub := sg.Scenes[0].Subgraphs[0]
+	if got := sub.Nodes[0].Config["expression"]; got != "event == 'puzzle.step' && tile == 'tiles.done.not'" {
+		t.Errorf("expected quoted legacy names translated in conditions, got %v", got)
+	}
+	if got := sub.Outputs["resolved_true_event"]; got != "puzzle.solved" {
+		t.Errorf("expected legacy event names translated, got %v", got)
+	}
+
+	// Graphs without legacy names are left as they are
+	same, legacy, err := translateLegacyEvents(out, aliases)
+	if err != nil || legacy != nil || string(same) != string(out) {
+		t.Errorf("expected current graph unchanged, got %v, %v", legacy, err)
+	}
+}
@@ -0,0 +1,76 @@
+package orchestrator
+
+import "time"
+
+// loopKey returns the scheduler key for a loop node. Loop nodes repeat an
+// action on the central clock until their stop_condition holds.
+func loopKey(nodeID string) string {
+	return "loop:" + nodeID
+}
+
+// loopInterval reads a loop's interval, drawing from [min, max] when bounds are given.
+// Returns false if the loop has no usable interval.
+func (r *Runtime) loopInterval(node *Node) (time.Duration, bool) {
+	if ms, ok := configInt(node.Config, "interval_ms"); ok {
+		if ms <= 0 {
+			return 0, false
+		}
+		return time.Duration(ms) * time.Millisecond, true
+	}
+
+	bounds, ok := node.Config["interval_ms"].(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	min, ok := configInt(bounds, "min")
+	if !ok || min <= 0 {
+		return 0, false
+	}
+	ms := min
+	if max, ok := configInt(bounds, "max"); ok && max > min {
+		ms = min + r.rng.Intn(max-min+1)
+	}
+	return time.Duration(ms) * time.Millisecond, true
+}
+
+// startLoop announces a loop and schedules its first tick.
+func (r *Runtime) startLoop(node *Node) {
+	r.emitEvent("loop.started", map[string]interface{}{"node_id": node.ID})
+	r.scheduleLoopTick(node, 1)
+}
+
+// scheduleLoopTick schedules tick number n of a loop.
+func (r *Runtime) scheduleLoopTick(node *Node, n int) {
+	interval, ok := r.loopInterval(node)
+	if !ok {
+		return
+	}
+	r.schedule(loopKey(node.ID), interval, func() {
+		r.tickLoop(node, n)
+	})
+}
+
+// tickLoop runs one loop iteration and schedules the next while the loop is active.
+func (r *Runtime) tickLoop(node *Node, n int) {
+	status := r.nodeStates[node.ID]
+	if status == nil || status.State != NodeStateActive {
+		return
+	}
+
+	r.emitEvent("loop.tick", map[string]interface{}{
+		"node_id": node.ID,
+		"tick":    n,
+	})
+	if hasAction(node.Config) {
+		// Failures are reported via device.error; the loop keeps its cadence
+		_ = r.runAction(node.ID, node.Config)
+	}
+
+	r.scheduleLoopTick(node, n+1)
+	r.evaluateAllConditions()
+}
+
+// stopLoop cancels a loop's pending tick.
+func (r *Runtime) stopLoop(nodeID string) {
+	r.cancelScheduled(loopKey(nodeID))
+}
@@ -0,0 +1,97 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// loopSceneGraph builds a scene where a strobe loop runs until a puzzle resolves.
+func loopSceneGraph(interval interface{}) *SceneGraph {
+	return sceneGraph(Scene{
+		ID:    "scene_loop",
+		Entry: "strobe",
+		Nodes: []Node{
+			{ID: "strobe", Type: "loop", Config: map[string]interface{}{
+				"interval_ms":    interval,
+				"action":         "device.command",
+				"params":         map[string]interface{}{"device_id": "strobe_light", "signal": "flash"},
+				"stop_condition": "puzzle_lever.resolved",
+			}},
+			{ID: "puzzle_lever", Type: "puzzle", Config: map[string]interface{}{"subgraph": "sub_lever"}},
+		},
+		Subgraphs: []Subgraph{
+			{ID: "sub_lever", Entry: "lever_wait", Nodes: []Node{{ID: "lever_wait", Type: "decision"}}},
+		},
+	})
+}
+
+func TestLoopTicksUntilStopCondition(t *testing.T) {
+	events.Clear()
+	executor := &replyingExecutor{}
+	rt := NewRuntime(loopSceneGraph(float64(500)))
+	rt.SetActionExecutor(executor)
+
+	if err := rt.StartScene("scene_loop"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		rt.mu.Lock()
+		fired := rt.fireScheduled(loopKey("strobe"))
+		rt.mu.Unlock()
+		if !fired {
+			t.Fatalf("expected tick %d to be pending", i+1)
+		}
+	}
+
+	if countEvents("loop.tick", "strobe") != 3 {
+		t.Errorf("expected 3 loop.tick events, got %d", countEvents("loop.tick", "strobe"))
+	}
+	if len(executor.commands) != 3 || executor.commands[0] != "strobe_light:flash" {
+		t.Errorf("expected 3 strobe commands, got %v", executor.commands)
+	}
+	var lastTick interface{}
+	for _, e := range events.Snapshot() {
+		if e.Name == "loop.tick" {
+			lastTick = e.Fields["tick"]
+		}
+	}
+	if lastTick != 3 {
+		t.Errorf("expected last tick count 3, got %v", lastTick)
+	}
+
+	if err := rt.OverrideNode("puzzle_lever"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+	if rt.GetNodeState("strobe") != NodeStateCompleted {
+		t.Errorf("expected loop to stop, got %s", rt.GetNodeState("strobe"))
+	}
+	if countEvents("loop.stopped", "strobe") != 1 {
+		t.Error("expected loop.stopped for strobe")
+	}
+
+	rt.mu.Lock()
+	_, pending := rt.tasks[loopKey("strobe")]
+	rt.mu.Unlock()
+	if pending {
+		t.Error("expected no pending tick after loop stopped")
+	}
+}
+
+func TestLoopRandomInterval(t *testing.T) {
+	rt := NewRuntime(loopSceneGraph(map[string]interface{}{"min": float64(1000), "max": float64(2000)}))
+	node := &rt.graph.Scenes[0].Nodes[0]
+
+	for i := 0; i < 20; i++ {
+		d, ok := rt.loopInterval(node)
+		if !ok || d.Milliseconds() < 1000 || d.Milliseconds() > 2000 {
+			t.Fatalf("interval out of bounds: %v", d)
+		}
+	}
+
+	node.Config["interval_ms"] = nil
+	if _, ok := rt.loopInterval(node); ok {
+		t.Error("expected loop without interval not to tick")
+	}
+}
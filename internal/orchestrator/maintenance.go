@@ -0,0 +1,312 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// defaultExpectTimeout bounds how long a step waits for an expected device input.
+const defaultExpectTimeout = 5 * time.Second
+
+// MaintenanceStepResult records the outcome of one routine step.
+type MaintenanceStepResult struct {
+	Step      int    `json:"step"`
+	Signal    string `json:"signal,omitempty"`
+	Expect    string `json:"expect,omitempty"`
+	OK        bool   `json:"ok"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latency_ms,omitempty"`
+}
+
+// DeviceTestResult records the outcome of one device's routine.
+type DeviceTestResult struct {
+	DeviceID    string                  `json:"device_id"`
+	Description string                  `json:"description,omitempty"`
+	Passed      bool                    `json:"passed"`
+	Steps       []MaintenanceStepResult `json:"steps"`
+}
+
+// MaintenanceReport summarizes a maintenance run.
+type MaintenanceReport struct {
+	StartedAt  string             `json:"started_at"`
+	FinishedAt string             `json:"finished_at,omitempty"`
+	Complete   bool               `json:"complete"`
+	Passed     int                `json:"passed"`
+	Failed     int                `json:"failed"`
+	Devices    []DeviceTestResult `json:"devices"`
+}
+
+// Maintenance runs device test routines while the room is closed to games.
+type Maintenance struct {
+	mu       sync.Mutex
+	rt       *Runtime
+	cfg      *config.MaintenanceConfig
+	executor ActionExecutorInterface
+	active   bool
+	cancel   chan struct{}
+	done     chan struct{}
+	report   *MaintenanceReport
+	waiters  map[string]chan string
+}
+
+// NewMaintenance creates a maintenance runner for the given runtime and routines.
+func NewMaintenance(rt *Runtime, cfg *config.MaintenanceConfig, executor ActionExecutorInterface) *Maintenance {
+	return &Maintenance{
+		rt:       rt,
+		cfg:      cfg,
+		executor: executor,
+		waiters:  make(map[string]chan string),
+	}
+}
+
+// Start enters maintenance mode and runs every configured routine once.
+// Fails if a game is in progress or maintenance is already active.
+func (m *Maintenance) Start() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.active {
+		return fmt.Errorf("maintenance already active")
+	}
+	if err := m.rt.setMaintenance(true); err != nil {
+		return err
+	}
+
+	m.active = true
+	m.cancel = make(chan struct{})
+	m.done = make(chan struct{})
+	m.report = &MaintenanceReport{
+		StartedAt: time.Now().UTC().Format(time.RFC3339),
+		Devices:   []DeviceTestResult{},
+	}
+
+	events.Emit("info", "room.maintenance_started", "", map[string]interface{}{
+		"routines": len(m.cfg.Routines),
+	})
+
+	go m.run(m.cancel, m.done)
+	return nil
+}
+
+// Stop aborts any running routine and leaves maintenance mode.
+func (m *Maintenance) Stop() error {
+	m.mu.Lock()
+	if !m.active {
+		m.mu.Unlock()
+		return fmt.Errorf("maintenance not active")
+	}
+	close(m.cancel)
+	done := m.done
+	m.mu.Unlock()
+
+	<-done
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.active = false
+	_ = m.rt.setMaintenance(false)
+
+	events.Emit("info", "room.maintenance_ended", "", map[string]interface{}{
+		"complete": m.report.Complete,
+		"passed":   m.report.Passed,
+		"failed":   m.report.Failed,
+	})
+	return nil
+}
+
+// Active returns true while the room is in maintenance mode.
+func (m *Maintenance) Active() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.active
+}
+
+// Report returns a copy of the most recent maintenance report, or nil if none has run.
+func (m *Maintenance) Report() *MaintenanceReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.report == nil {
+		return nil
+	}
+	cpy := *m.report
+	cpy.Devices = append([]DeviceTestResult{}, m.report.Devices...)
+	return &cpy
+}
+
+// Wait blocks until the current routine run finishes.
+func (m *Maintenance) Wait() {
+	m.mu.Lock()
+	done := m.done
+	m.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// HandleInput delivers a device.input event to a step waiting on that device.
+func (m *Maintenance) HandleInput(fields map[string]interface{}) {
+	logicalID, _ := fields["logical_id"].(string)
+	signal, _ := getNestedField(fields, "payload.signal").(string)
+	if logicalID == "" || signal == "" {
+		return
+	}
+
+	m.mu.Lock()
+	ch, ok := m.waiters[logicalID]
+	m.mu.Unlock()
+	if !ok {
+		return
+	}
+	select {
+	case ch <- signal:
+	default:
+	}
+}
+
+// run exercises each device in ID order and records results.
+func (m *Maintenance) run(cancel, done chan struct{}) {
+	defer close(done)
+
+	ids := make([]string, 0, len(m.cfg.Routines))
+	for id := range m.cfg.Routines {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, deviceID := range ids {
+		select {
+		case <-cancel:
+			return
+		default:
+		}
+
+		result := m.runRoutine(deviceID, m.cfg.Routines[deviceID], cancel)
+
+		m.mu.Lock()
+		m.report.Devices = append(m.report.Devices, result)
+		if result.Passed {
+			m.report.Passed++
+		} else {
+			m.report.Failed++
+		}
+		m.mu.Unlock()
+
+		if !result.Passed {
+			events.Emit("warning", "device.error", "maintenance routine failed", map[string]interface{}{
+				"device_id":   deviceID,
+				"maintenance": true,
+			})
+		}
+	}
+
+	m.mu.Lock()
+	m.report.Complete = true
+	m.report.FinishedAt = time.Now().UTC().Format(time.RFC3339)
+	m.mu.Unlock()
+}
+
+// runRoutine executes one device's steps, stopping at the first failure.
+func (m *Maintenance) runRoutine(deviceID string, routine config.MaintenanceRoutine, cancel chan struct{}) DeviceTestResult {
+	result := DeviceTestResult{
+		DeviceID:    deviceID,
+		Description: routine.Description,
+		Passed:      true,
+		Steps:       []MaintenanceStepResult{},
+	}
+
+	for i, step := range routine.Steps {
+		sr := m.runStep(deviceID, i, step, cancel)
+		result.Steps = append(result.Steps, sr)
+		if !sr.OK {
+			result.Passed = false
+			break
+		}
+	}
+	return result
+}
+
+// runStep sends a step's command, waits for its expected input, then pauses.
+func (m *Maintenance) runStep(deviceID string, index int, step config.MaintenanceStep, cancel chan struct{}) MaintenanceStepResult {
+	sr := MaintenanceStepResult{Step: index + 1, Signal: step.Signal, Expect: step.Expect, OK: true}
+
+	// Register for input before commanding so a fast reply is not missed
+	var inputs chan string
+	if step.Expect != "" {
+		inputs = make(chan string, 8)
+		m.mu.Lock()
+		m.waiters[deviceID] = inputs
+		m.mu.Unlock()
+		defer func() {
+			m.mu.Lock()
+			delete(m.waiters, deviceID)
+			m.mu.Unlock()
+		}()
+	}
+
+	start := time.Now()
+	if step.Signal != "" {
+		if m.executor == nil {
+			sr.OK = false
+			sr.Error = "action executor not available"
+			return sr
+		}
+		err := m.executor.ExecuteAction("maintenance:"+deviceID, map[string]interface{}{
+			"action": "device.command",
+			"params": map[string]interface{}{
+				"device_id": deviceID,
+				"signal":    step.Signal,
+				"payload":   step.Payload,
+			},
+		})
+		if err != nil {
+			sr.OK = false
+			sr.Error = err.Error()
+			return sr
+		}
+	}
+
+	if step.Expect != "" {
+		timeout := defaultExpectTimeout
+		if step.TimeoutMs > 0 {
+			timeout = time.Duration(step.TimeoutMs) * time.Millisecond
+		}
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+	wait:
+		for {
+			select {
+			case signal := <-inputs:
+				if signal == step.Expect {
+					sr.LatencyMs = time.Since(start).Milliseconds()
+					break wait
+				}
+			case <-timer.C:
+				sr.OK = false
+				sr.Error = fmt.Sprintf("no %s within %s", step.Expect, timeout)
+				return sr
+			case <-cancel:
+				sr.OK = false
+				sr.Error = "cancelled"
+				return sr
+			}
+		}
+	}
+
+	if step.WaitMs > 0 {
+		select {
+		case <-time.After(time.Duration(step.WaitMs) * time.Millisecond):
+		case <-cancel:
+			sr.OK = false
+			sr.Error = "cancelled"
+		}
+	}
+
+	return sr
+}
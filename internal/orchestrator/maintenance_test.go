@@ -0,0 +1,143 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// replyingExecutor records commands and simulates device replies.
+type replyingExecutor struct {
+	mu       sync.Mutex
+	commands []string
+	fail     map[string]bool
+	reply    func(deviceID, signal string)
+}
+
+func (e *replyingExecutor) ExecuteAction(nodeID string, cfg map[string]interface{}) error {
+	params := cfg["params"].(map[string]interface{})
+	deviceID := params["device_id"].(string)
+	signal := params["signal"].(string)
+
+	e.mu.Lock()
+	e.commands = append(e.commands, deviceID+":"+signal)
+	e.mu.Unlock()
+
+	if e.fail[deviceID] {
+		return fmt.Errorf("device %s not registered", deviceID)
+	}
+	if e.reply != nil {
+		e.reply(deviceID, signal)
+	}
+	return nil
+}
+
+func TestMaintenanceRun(t *testing.T) {
+	events.Clear()
+	sg, err := LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	rt := NewRuntime(sg)
+
+	cfg := &config.MaintenanceConfig{
+		Version: 1,
+		Routines: map[string]config.MaintenanceRoutine{
+			"crypt_door": {Steps: []config.MaintenanceStep{
+				{Signal: "lock"},
+				{Signal: "unlock", Expect: "door_open", TimeoutMs: 1000},
+			}},
+			"led_strip":     {Steps: []config.MaintenanceStep{{Signal: "sweep", WaitMs: 1}}},
+			"dead_relay":    {Steps: []config.MaintenanceStep{{Signal: "on"}}},
+			"silent_sensor": {Steps: []config.MaintenanceStep{{Expect: "pressed", TimeoutMs: 20}}},
+		},
+	}
+
+	var m *Maintenance
+	exec := &replyingExecutor{fail: map[string]bool{"dead_relay": true}}
+	exec.reply = func(deviceID, signal string) {
+		if deviceID == "crypt_door" && signal == "unlock" {
+			m.HandleInput(map[string]interface{}{
+				"logical_id": "crypt_door",
+				"payload":    map[string]interface{}{"signal": "door_open"},
+			})
+		}
+	}
+	m = NewMaintenance(rt, cfg, exec)
+
+	if err := m.Start(); err != nil {
+		t.Fatalf("failed to start maintenance: %v", err)
+	}
+	if err := rt.StartGame(""); err == nil {
+		t.Error("expected StartGame to fail during maintenance")
+	}
+
+	m.Wait()
+	report := m.Report()
+	if !report.Complete {
+		t.Fatal("expected report to be complete")
+	}
+	if report.Passed != 2 || report.Failed != 2 {
+		t.Errorf("expected 2 passed and 2 failed, got %d/%d", report.Passed, report.Failed)
+	}
+
+	results := make(map[string]DeviceTestResult)
+	for _, d := range report.Devices {
+		results[d.DeviceID] = d
+	}
+	if !results["crypt_door"].Passed || len(results["crypt_door"].Steps) != 2 {
+		t.Errorf("expected crypt_door to pass both steps, got %+v", results["crypt_door"])
+	}
+	if results["dead_relay"].Passed {
+		t.Error("expected dead_relay to fail")
+	}
+	if results["silent_sensor"].Passed || results["silent_sensor"].Steps[0].Error == "" {
+		t.Errorf("expected silent_sensor to time out, got %+v", results["silent_sensor"])
+	}
+
+	if err := m.Stop(); err != nil {
+		t.Fatalf("failed to stop maintenance: %v", err)
+	}
+	if rt.InMaintenance() {
+		t.Error("expected runtime to leave maintenance")
+	}
+	if err := rt.StartGame(""); err != nil {
+		t.Errorf("expected StartGame to succeed after maintenance, got %v", err)
+	}
+
+	var started, ended bool
+	for _, e := range events.Snapshot() {
+		switch e.Name {
+		case "room.maintenance_started":
+			started = true
+		case "room.maintenance_ended":
+			ended = true
+		}
+	}
+	if !started || !ended {
+		t.Error("expected maintenance start and end events")
+	}
+}
+
+func TestMaintenanceRejectedDuringGame(t *testing.T) {
+	sg, err := LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	rt := NewRuntime(sg)
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+
+	m := NewMaintenance(rt, &config.MaintenanceConfig{Version: 1}, nil)
+	if err := m.Start(); err == nil {
+		t.Error("expected maintenance to be rejected during a game")
+	}
+	if m.Active() {
+		t.Error("expected maintenance to remain inactive")
+	}
+}
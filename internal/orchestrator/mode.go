@@ -0,0 +1,120 @@
+package orchestrator
+
+import (
+	"fmt"
+	"log"
+)
+
+// RunMode selects the engine's operating profile (ops.mode in room.yaml):
+// live games with real props (show), games whose device commands are logged
+// instead of sent (rehearsal), or a room closed to games so props can be
+// exercised (maintenance).
+type RunMode string
+
+// Run modes.
+const (
+	ModeShow        RunMode = "show"
+	ModeRehearsal   RunMode = "rehearsal"
+	ModeMaintenance RunMode = "maintenance"
+)
+
+// ModeProfile is what a run mode allows.
+type ModeProfile struct {
+	Games          bool `json:"games"`           // games may start
+	DryRunActions  bool `json:"dry_run_actions"` // device commands are logged, not sent
+	SyntheticInput bool `json:"synthetic_input"` // operators may inject device inputs
+}
+
+var modeProfiles = map[RunMode]ModeProfile{
+	ModeShow:        {Games: true},
+	ModeRehearsal:   {Games: true, DryRunActions: true, SyntheticInput: true},
+	ModeMaintenance: {},
+}
+
+// ParseRunMode returns the run mode named s.
+func ParseRunMode(s string) (RunMode, error) {
+	mode := RunMode(s)
+	if _, ok := modeProfiles[mode]; !ok {
+		return "", fmt.Errorf("unknown mode %q (show, rehearsal or maintenance)", s)
+	}
+	return mode, nil
+}
+
+// Profile returns what the mode allows.
+func (m RunMode) Profile() ModeProfile {
+	return modeProfiles[m]
+}
+
+// Mode returns the current run mode.
+func (r *Runtime) Mode() RunMode {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.mode
+}
+
+// SetMode switches the run mode between games. by names who asked for the
+// switch and is recorded in room.mode_changed.
+func (r *Runtime) SetMode(mode RunMode, by string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := modeProfiles[mode]; !ok {
+		return fmt.Errorf("unknown mode %q", mode)
+	}
+	if r.activeScene != nil && mode != r.mode {
+		return fmt.Errorf("game in progress")
+	}
+	r.changeMode(mode, by)
+	return nil
+}
+
+// changeMode applies a run mode and records the switch.
+// Caller must hold r.mu.
+func (r *Runtime) changeMode(mode RunMode, by string) {
+	if mode == r.mode {
+		return
+	}
+	from := r.mode
+	r.mode = mode
+	r.dryRun.Store(mode.Profile().DryRunActions)
+
+	fields := map[string]interface{}{
+		"mode": string(mode),
+		"from": string(from),
+	}
+	if by != "" {
+		fields["by"] = by
+	}
+	r.emitEvent("room.mode_changed", fields)
+}
+
+// GatedExecutor wraps an executor so its commands are only logged while the
+// run mode dry-runs actions. SetActionExecutor applies it to the runtime's
+// own executor; other senders of scene-driven commands (announcements, the
+// event bridge) should use it too.
+func (r *Runtime) GatedExecutor(next ActionExecutorInterface) ActionExecutorInterface {
+	if next == nil {
+		return nil
+	}
+	if g, ok := next.(*gatedExecutor); ok && g.rt == r {
+		return g
+	}
+	return &gatedExecutor{rt: r, next: next}
+}
+
+// gatedExecutor drops actions while the runtime dry-runs them. It reads the
+// mode without the runtime lock, so it is safe to call while holding it.
+type gatedExecutor struct {
+	rt   *Runtime
+	next ActionExecutorInterface
+}
+
+func (g *gatedExecutor) ExecuteAction(nodeID string, config map[string]interface{}) error {
+	if g.rt.dryRun.Load() {
+		action, _ := config["action"].(string)
+		log.Printf("[mode] dry run: %s %s %v", nodeID, action, config["params"])
+		return nil
+	}
+	return g.next.ExecuteAction(nodeID, config)
+}
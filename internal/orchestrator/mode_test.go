@@ -0,0 +1,94 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+func TestRehearsalDryRunsActions(t *testing.T) {
+	events.Clear()
+	defer events.Clear()
+	exec := &replyingExecutor{}
+	rt := NewRuntime(counterSceneGraph(1))
+	gated := rt.GatedExecutor(exec)
+	command := map[string]interface{}{
+		"action": "device.command",
+		"params": map[string]interface{}{"device_id": "door", "signal": "unlock"},
+	}
+
+	if rt.Mode() != ModeShow {
+		t.Fatalf("expected show mode by default, got %s", rt.Mode())
+	}
+	if err := rt.SetMode(ModeRehearsal, "admin"); err != nil {
+		t.Fatalf("set mode failed: %v", err)
+	}
+	_ = gated.ExecuteAction("open_door", command)
+	if len(exec.commands) != 0 {
+		t.Errorf("expected no commands sent in rehearsal, got %v", exec.commands)
+	}
+
+	if err := rt.SetMode(ModeShow, "admin"); err != nil {
+		t.Fatalf("set mode failed: %v", err)
+	}
+	_ = gated.ExecuteAction("open_door", command)
+	if len(exec.commands) != 1 {
+		t.Errorf("expected the command sent in show mode, got %v", exec.commands)
+	}
+
+	var changes []events.Event
+	for _, e := range events.Snapshot() {
+		if e.Name == "room.mode_changed" {
+			changes = append(changes, e)
+		}
+	}
+	if len(changes) != 2 || changes[0].Fields["mode"] != "rehearsal" || changes[0].Fields["from"] != "show" || changes[0].Fields["by"] != "admin" {
+		t.Errorf("unexpected room.mode_changed events: %v", changes)
+	}
+}
+
+func TestModeGatesGames(t *testing.T) {
+	events.Clear()
+	defer events.Clear()
+	rt := NewRuntime(counterSceneGraph(1))
+
+	if _, err := ParseRunMode("party"); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+
+	if err := rt.SetMode(ModeMaintenance, "admin"); err != nil {
+		t.Fatalf("set mode failed: %v", err)
+	}
+	if err := rt.StartGame(""); err == nil {
+		t.Error("expected games rejected in maintenance mode")
+	}
+
+	if err := rt.SetMode(ModeRehearsal, "admin"); err != nil {
+		t.Fatalf("set mode failed: %v", err)
+	}
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("expected games in rehearsal mode: %v", err)
+	}
+	defer rt.StopGame()
+	if err := rt.SetMode(ModeShow, "admin"); err == nil {
+		t.Error("expected mode switch rejected during a game")
+	}
+}
+
+func TestMaintenanceReturnsToPreviousMode(t *testing.T) {
+	events.Clear()
+	defer events.Clear()
+	rt := NewRuntime(counterSceneGraph(1))
+	_ = rt.SetMode(ModeRehearsal, "room.yaml")
+
+	if err := rt.setMaintenance(true); err != nil {
+		t.Fatalf("enter maintenance failed: %v", err)
+	}
+	if !rt.InMaintenance() {
+		t.Fatal("expected maintenance mode")
+	}
+	_ = rt.setMaintenance(false)
+	if rt.Mode() != ModeRehearsal {
+		t.Errorf("expected rehearsal mode after maintenance, got %s", rt.Mode())
+	}
+}
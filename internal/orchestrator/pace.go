@@ -0,0 +1,123 @@
+package orchestrator
+
+import (
+	"time"
+)
+
+// PaceLight is a traffic-light summary of whether a team is on pace to finish.
+type PaceLight string
+
+const (
+	PaceGreen   PaceLight = "green"
+	PaceYellow  PaceLight = "yellow"
+	PaceRed     PaceLight = "red"
+	PaceUnknown PaceLight = "unknown"
+)
+
+// Pace thresholds as a ratio of expected remaining work to remaining clock time.
+const (
+	// At or below this ratio the team is comfortably on pace.
+	paceGreenRatio = 0.8
+	// Above this ratio the team is projected to run out of time.
+	paceRedRatio = 1.0
+)
+
+// PaceBaseline maps puzzle node IDs to their historical median solve time.
+type PaceBaseline map[string]time.Duration
+
+// NewPaceBaseline extracts median solve times from a difficulty report.
+func NewPaceBaseline(report *DifficultyReport) PaceBaseline {
+	baseline := make(PaceBaseline)
+	if report == nil {
+		return baseline
+	}
+	for _, pd := range report.Puzzles {
+		if pd.SolveTimeSec == nil {
+			continue
+		}
+		baseline[pd.NodeID] = time.Duration(pd.SolveTimeSec.Median * float64(time.Second))
+	}
+	return baseline
+}
+
+// PaceEstimate predicts whether the current team will finish before the clock runs out.
+type PaceEstimate struct {
+	Light               PaceLight `json:"light"`
+	ClockRemainingMs    int64     `json:"clock_remaining_ms"`
+	ExpectedRemainingMs int64     `json:"expected_remaining_ms"`
+	ProjectedFinishMs   int64     `json:"projected_finish_ms"`
+	PuzzlesRemaining    int       `json:"puzzles_remaining"`
+	PuzzlesNoHistory    int       `json:"puzzles_no_history"`
+}
+
+// EstimatePace projects the time needed to solve the remaining puzzles in the
+// active scene from historical medians. Puzzles already in progress are credited
+// with the time spent on them. Returns nil if no game is active.
+func (r *Runtime) EstimatePace(baseline PaceBaseline) *PaceEstimate {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.activeScene == nil {
+		return nil
+	}
+
+	// Puzzles without history are assumed to take the average known median
+	var fallback time.Duration
+	if len(baseline) > 0 {
+		var total time.Duration
+		for _, d := range baseline {
+			total += d
+		}
+		fallback = total / time.Duration(len(baseline))
+	}
+
+	now := r.now()
+	est := &PaceEstimate{}
+	var expected time.Duration
+	for _, node := range r.activeScene.Nodes {
+		if node.Type != "puzzle" {
+			continue
+		}
+		if ps, ok := r.puzzleStates[node.ID]; ok && ps.IsResolved() {
+			continue
+		}
+		est.PuzzlesRemaining++
+
+		median, ok := baseline[node.ID]
+		if !ok {
+			est.PuzzlesNoHistory++
+			median = fallback
+		}
+		if status := r.nodeStates[node.ID]; status != nil && status.State == NodeStateActive && !status.ActivatedAt.IsZero() {
+			median -= now.Sub(status.ActivatedAt)
+			if median < 0 {
+				median = 0
+			}
+		}
+		expected += median
+	}
+
+	clockRemaining := r.clockRemaining()
+	est.ClockRemainingMs = clockRemaining.Milliseconds()
+	est.ExpectedRemainingMs = expected.Milliseconds()
+	est.ProjectedFinishMs = (r.clockElapsed() + expected).Milliseconds()
+
+	switch {
+	case r.clock == nil:
+		est.Light = PaceUnknown
+	case r.clock.expired:
+		est.Light = PaceRed
+	case est.PuzzlesRemaining == 0:
+		est.Light = PaceGreen
+	case len(baseline) == 0:
+		est.Light = PaceUnknown
+	case clockRemaining == 0 || float64(expected)/float64(clockRemaining) > paceRedRatio:
+		est.Light = PaceRed
+	case float64(expected)/float64(clockRemaining) > paceGreenRatio:
+		est.Light = PaceYellow
+	default:
+		est.Light = PaceGreen
+	}
+
+	return est
+}
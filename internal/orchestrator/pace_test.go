@@ -0,0 +1,87 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEstimatePace(t *testing.T) {
+	rt, fc := newClockRuntime(t)
+
+	if est := rt.EstimatePace(PaceBaseline{}); est != nil {
+		t.Errorf("expected nil estimate without active game, got %+v", est)
+	}
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+
+	baseline := PaceBaseline{
+		"puzzle_scarab": 20 * time.Minute,
+		"puzzle_tiles":  30 * time.Minute,
+	}
+
+	// 50m of expected work against 60m on the clock
+	est := rt.EstimatePace(baseline)
+	if est.Light != PaceYellow {
+		t.Errorf("expected yellow at start, got %s (%+v)", est.Light, est)
+	}
+	if est.PuzzlesRemaining != 2 || est.ExpectedRemainingMs != (50*time.Minute).Milliseconds() {
+		t.Errorf("unexpected estimate at start: %+v", est)
+	}
+
+	// Scarab solved after 5m; tiles has been in progress for 5m
+	fc.advance(5 * time.Minute)
+	rt.InjectEvent("puzzle.solved", map[string]interface{}{"puzzle_id": "scarab"})
+
+	est = rt.EstimatePace(baseline)
+	if est.Light != PaceGreen {
+		t.Errorf("expected green after fast solve, got %s (%+v)", est.Light, est)
+	}
+	if est.PuzzlesRemaining != 1 || est.ExpectedRemainingMs != (25*time.Minute).Milliseconds() {
+		t.Errorf("unexpected estimate after solve: %+v", est)
+	}
+	if est.ProjectedFinishMs != (30 * time.Minute).Milliseconds() {
+		t.Errorf("expected projected finish at 30m, got %dms", est.ProjectedFinishMs)
+	}
+}
+
+func TestEstimatePaceBehind(t *testing.T) {
+	rt, _ := newClockRuntime(t)
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+
+	if est := rt.EstimatePace(PaceBaseline{}); est.Light != PaceUnknown {
+		t.Errorf("expected unknown without history, got %s", est.Light)
+	}
+
+	// Only scarab has history; tiles falls back to the average known median
+	est := rt.EstimatePace(PaceBaseline{"puzzle_scarab": 40 * time.Minute})
+	if est.Light != PaceRed {
+		t.Errorf("expected red when behind pace, got %s (%+v)", est.Light, est)
+	}
+	if est.PuzzlesNoHistory != 1 || est.ExpectedRemainingMs != (80*time.Minute).Milliseconds() {
+		t.Errorf("unexpected estimate with partial history: %+v", est)
+	}
+}
+
+func TestNewPaceBaseline(t *testing.T) {
+	report := &DifficultyReport{
+		Puzzles: []PuzzleDifficulty{
+			{NodeID: "puzzle_scarab", SolveTimeSec: &Percentiles{Median: 90}},
+			{NodeID: "puzzle_tiles"},
+		},
+	}
+
+	baseline := NewPaceBaseline(report)
+	if baseline["puzzle_scarab"] != 90*time.Second {
+		t.Errorf("expected 90s median for scarab, got %s", baseline["puzzle_scarab"])
+	}
+	if _, ok := baseline["puzzle_tiles"]; ok {
+		t.Error("expected puzzle without solve data to be omitted")
+	}
+}
@@ -0,0 +1,108 @@
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// quorumSceneGraph fans out into three puzzles joined by a parallel node with min_complete.
+func quorumSceneGraph(minComplete interface{}) *SceneGraph {
+	config := map[string]interface{}{
+		"children": []interface{}{"puzzle_a", "puzzle_b", "puzzle_c"},
+	}
+	if minComplete != nil {
+		config["min_complete"] = minComplete
+	}
+	return sceneGraph(Scene{
+		ID:    "scene_quorum",
+		Entry: "start",
+		Nodes: []Node{
+			{ID: "start", Type: "parallel", Config: config},
+			{ID: "puzzle_a", Type: "puzzle", Config: map[string]interface{}{"subgraph": "sub_a"}},
+			{ID: "puzzle_b", Type: "puzzle", Config: map[string]interface{}{"subgraph": "sub_b"}},
+			{ID: "puzzle_c", Type: "puzzle", Config: map[string]interface{}{"subgraph": "sub_c"}},
+			{ID: "next", Type: "operator", Config: map[string]interface{}{}},
+		},
+		Edges: []Edge{{From: "start", To: "next"}},
+		Subgraphs: []Subgraph{
+			{ID: "sub_a", Entry: "a_wait", Nodes: []Node{{ID: "a_wait", Type: "decision"}}},
+			{ID: "sub_b", Entry: "b_wait", Nodes: []Node{{ID: "b_wait", Type: "decision"}}},
+			{ID: "sub_c", Entry: "c_wait", Nodes: []Node{{ID: "c_wait", Type: "decision"}}},
+		},
+	})
+}
+
+func TestParallelQuorum(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(quorumSceneGraph(float64(2)))
+	if err := rt.StartScene("scene_quorum"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+
+	if err := rt.OverrideNode("puzzle_a"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+	if rt.GetNodeState("start") != NodeStateActive {
+		t.Fatalf("expected parallel to wait for a second puzzle, got %s", rt.GetNodeState("start"))
+	}
+
+	if err := rt.OverrideNode("puzzle_b"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+	if rt.GetNodeState("start") != NodeStateCompleted || rt.GetNodeState("next") != NodeStateActive {
+		t.Fatalf("expected quorum to complete the parallel, got %s/%s", rt.GetNodeState("start"), rt.GetNodeState("next"))
+	}
+
+	// The remaining puzzle stays available as an optional branch
+	if rt.GetNodeState("puzzle_c") != NodeStateActive {
+		t.Errorf("expected puzzle_c to keep running, got %s", rt.GetNodeState("puzzle_c"))
+	}
+
+	for _, e := range events.Snapshot() {
+		if e.Name == "node.completed" && e.Fields["node_id"] == "start" {
+			if e.Fields["min_complete"] != 2 || e.Fields["children_completed"] != 2 {
+				t.Errorf("unexpected quorum fields: %v", e.Fields)
+			}
+			return
+		}
+	}
+	t.Error("expected node.completed for the parallel")
+}
+
+func TestParallelQuorumIgnoresFailures(t *testing.T) {
+	rt := NewRuntime(quorumSceneGraph(float64(2)))
+	if err := rt.StartScene("scene_quorum"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+
+	rt.mu.Lock()
+	rt.failPuzzle("puzzle_a", "timeout")
+	rt.mu.Unlock()
+	if err := rt.OverrideNode("puzzle_b"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+	if rt.GetNodeState("start") != NodeStateActive {
+		t.Fatalf("expected a failed branch not to count, got %s", rt.GetNodeState("start"))
+	}
+
+	if err := rt.OverrideNode("puzzle_c"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+	if rt.GetNodeState("start") != NodeStateCompleted {
+		t.Errorf("expected parallel to complete, got %s", rt.GetNodeState("start"))
+	}
+}
+
+func TestParallelQuorumValidation(t *testing.T) {
+	for _, n := range []float64{0, 4} {
+		err := ValidateConditions(quorumSceneGraph(n))
+		if err == nil || !strings.Contains(err.Error(), "min_complete") {
+			t.Errorf("min_complete %v: expected error, got %v", n, err)
+		}
+	}
+	if err := ValidateConditions(quorumSceneGraph(float64(3))); err != nil {
+		t.Errorf("expected valid quorum, got %v", err)
+	}
+}
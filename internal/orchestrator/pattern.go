@@ -0,0 +1,251 @@
+package orchestrator
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// DefaultPatternField is the payload field read by pattern nodes.
+const DefaultPatternField = "symbol"
+
+// Pattern pacing defaults.
+const (
+	defaultPatternStepMs    = 1000
+	defaultPatternMinStepMs = 200
+)
+
+// patternState tracks one pattern node, a Simon-says puzzle: each round plays
+// a longer prefix of the pattern on output devices, faster, and the player
+// repeats it (config in design/scene-graph/schema.md). gen invalidates
+// pending playback when a round restarts.
+type patternState struct {
+	pattern  []string
+	round    int // 1-based
+	entered  int
+	attempts int
+	playing  bool
+	gen      int
+}
+
+// patternOutputs returns the action played for each symbol.
+func patternOutputs(node *Node) map[string]map[string]interface{} {
+	raw, _ := node.Config["outputs"].(map[string]interface{})
+	outputs := make(map[string]map[string]interface{}, len(raw))
+	for symbol, action := range raw {
+		if config, ok := action.(map[string]interface{}); ok {
+			outputs[symbol] = config
+		}
+	}
+	return outputs
+}
+
+// patternSymbols returns the configured pattern.
+func patternSymbols(node *Node) []string {
+	list, _ := node.Config["pattern"].([]interface{})
+	symbols := make([]string, 0, len(list))
+	for _, item := range list {
+		symbols = append(symbols, codeString(item))
+	}
+	return symbols
+}
+
+// randomPattern draws n symbols from the outputs.
+func randomPattern(rng *rand.Rand, outputs map[string]map[string]interface{}, n int) []string {
+	symbols := make([]string, 0, len(outputs))
+	for symbol := range outputs {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	pattern := make([]string, n)
+	for i := range pattern {
+		if rng != nil {
+			pattern[i] = symbols[rng.Intn(len(symbols))]
+		} else {
+			pattern[i] = symbols[rand.Intn(len(symbols))]
+		}
+	}
+	return pattern
+}
+
+// patternStartLength returns the symbols played in the first round.
+func patternStartLength(node *Node, total int) int {
+	n, ok := configInt(node.Config, "start_length")
+	if !ok || n < 1 {
+		n = 1
+	}
+	return min(n, total)
+}
+
+// patternStep returns the time between symbols in a round.
+func patternStep(node *Node, round int) time.Duration {
+	step, ok := configInt(node.Config, "step_ms")
+	if !ok || step <= 0 {
+		step = defaultPatternStepMs
+	}
+	floor, ok := configInt(node.Config, "min_step_ms")
+	if !ok || floor < 0 {
+		floor = defaultPatternMinStepMs
+	}
+	speedup, _ := configInt(node.Config, "speedup_ms")
+	step -= speedup * (round - 1)
+	if step < floor {
+		step = floor
+	}
+	return time.Duration(step) * time.Millisecond
+}
+
+// roundLength returns the symbols the player repeats in the current round.
+func (s *patternState) roundLength(node *Node) int {
+	return min(patternStartLength(node, len(s.pattern))+s.round-1, len(s.pattern))
+}
+
+// rounds returns the number of rounds in the pattern.
+func (s *patternState) rounds(node *Node) int {
+	return len(s.pattern) - patternStartLength(node, len(s.pattern)) + 1
+}
+
+// startPattern picks the pattern and plays the first round.
+func (pr *PuzzleRuntime) startPattern(node *Node) {
+	if pr.patterns == nil {
+		pr.patterns = make(map[string]*patternState)
+	}
+	pattern := patternSymbols(node)
+	if length, ok := configInt(node.Config, "length"); ok && length > 0 && len(pattern) == 0 {
+		pattern = randomPattern(pr.rng, patternOutputs(node), length)
+	}
+	state := &patternState{pattern: pattern, round: 1}
+	pr.patterns[node.ID] = state
+	if len(pattern) == 0 {
+		return
+	}
+	pr.playRound(node, state, patternStep(node, 1))
+}
+
+// emitPatternProgress reports a pattern node's round and input.
+func (pr *PuzzleRuntime) emitPatternProgress(node *Node, state *patternState, wrong bool) {
+	fields := map[string]interface{}{
+		"puzzle_id":   pr.parentNodeID,
+		"subgraph_id": pr.subgraph.ID,
+		"node_id":     node.ID,
+		"round":       state.round,
+		"rounds":      state.rounds(node),
+		"entered":     state.entered,
+		"length":      state.roundLength(node),
+		"attempts":    state.attempts,
+	}
+	if maxAttempts, ok := configInt(node.Config, "max_attempts"); ok && maxAttempts > 0 {
+		fields["max_attempts"] = maxAttempts
+	}
+	if wrong {
+		fields["wrong"] = true
+	}
+	events.Emit("info", "puzzle.progress", "", fields)
+}
+
+// playRound plays the current round after delay, one symbol per step.
+// Without a scheduler (standalone runtimes) the round plays at once.
+func (pr *PuzzleRuntime) playRound(node *Node, state *patternState, delay time.Duration) {
+	state.gen++
+	state.playing = true
+	state.entered = 0
+	pr.emitPatternProgress(node, state, false)
+
+	length := state.roundLength(node)
+	if pr.scheduleFunc == nil {
+		for _, symbol := range state.pattern[:length] {
+			pr.playSymbol(node, symbol)
+		}
+		state.playing = false
+		return
+	}
+	pr.playNext(node, state, state.gen, 0, delay)
+}
+
+// playNext schedules symbol i of the round; the round ends one step after the
+// last symbol so the player sees it before input counts.
+func (pr *PuzzleRuntime) playNext(node *Node, state *patternState, gen, i int, delay time.Duration) {
+	pr.scheduleFunc(node.ID+":play", delay, func() {
+		if state.gen != gen || !pr.nodeActive(node.ID) {
+			return
+		}
+		step := patternStep(node, state.round)
+		if i == state.roundLength(node) {
+			state.playing = false
+			return
+		}
+		pr.playSymbol(node, state.pattern[i])
+		pr.playNext(node, state, gen, i+1, step)
+	})
+}
+
+// playSymbol runs the output action for one symbol.
+func (pr *PuzzleRuntime) playSymbol(node *Node, symbol string) {
+	config, ok := patternOutputs(node)[symbol]
+	if !ok || pr.actionFunc == nil {
+		return
+	}
+	// Failures are reported as device.error by the executor
+	_ = pr.actionFunc(node.ID, config)
+}
+
+// patternInput extracts the pressed symbol from an event payload.
+func patternInput(node *Node, evt *Event) string {
+	field, _ := node.Config["field"].(string)
+	if field == "" {
+		field = DefaultPatternField
+	}
+	if payload, ok := evt.Fields["payload"].(map[string]interface{}); ok {
+		return codeString(getNestedField(payload, field))
+	}
+	return codeString(evt.Fields["payload"])
+}
+
+// handlePattern applies an input event to an active pattern node. Returns true
+// once the last round is repeated; a puzzle that runs out of attempts is
+// marked failed instead.
+func (pr *PuzzleRuntime) handlePattern(node *Node, ctx *EvalContext) bool {
+	match, _ := node.Config["match"].(string)
+	if match == "" || !EvalCondition(match, ctx) {
+		return false
+	}
+	state := pr.patterns[node.ID]
+	symbol := patternInput(node, ctx.Event)
+	if state == nil || state.playing || symbol == "" || len(state.pattern) == 0 {
+		return false
+	}
+
+	if symbol != state.pattern[state.entered] {
+		state.attempts++
+		pr.emitPatternProgress(node, state, true)
+		maxAttempts, ok := configInt(node.Config, "max_attempts")
+		if ok && maxAttempts > 0 && state.attempts >= maxAttempts && !pr.generic {
+			pr.attempts = state.attempts
+			pr.Fail()
+			return false
+		}
+		if onWrong, _ := node.Config["on_wrong"].(string); onWrong == "restart" {
+			state.round = 1
+		}
+		pr.playRound(node, state, patternStep(node, state.round))
+		return false
+	}
+
+	state.entered++
+	if state.entered < state.roundLength(node) {
+		pr.emitPatternProgress(node, state, false)
+		return false
+	}
+	if state.round == state.rounds(node) {
+		return true
+	}
+	state.round++
+	pr.playRound(node, state, patternStep(node, state.round))
+	return false
+}
@@ -0,0 +1,157 @@
+package orchestrator
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// patternSceneGraph is a crystal wall that lights a pattern the players repeat.
+// Each crystal "lights" by setting vars.crystal.
+func patternSceneGraph(pattern map[string]interface{}) *SceneGraph {
+	outputs := make(map[string]interface{})
+	for _, color := range []string{"red", "green", "blue"} {
+		outputs[color] = map[string]interface{}{
+			"action": SetVariableAction,
+			"params": map[string]interface{}{"name": "crystal", "value": color},
+		}
+	}
+	config := map[string]interface{}{
+		"match":   "event == 'device.input' && logical_id == 'crystal_pads'",
+		"outputs": outputs,
+		"pattern": []interface{}{"red", "green", "blue"},
+		"step_ms": float64(800),
+	}
+	maps.Copy(config, pattern)
+	return sceneGraph(puzzleScene("scene_crystals", "puzzle_crystals", "crystals", Node{ID: "crystal_wall", Type: "pattern", Config: config}))
+}
+
+const playKey = "action:puzzle_crystals/crystal_wall:play"
+
+// playRound fires the playback steps of a round and returns the crystals lit.
+func playRound(t *testing.T, rt *Runtime) []interface{} {
+	t.Helper()
+	before := len(events.Snapshot())
+	for scheduled(rt, playKey) {
+		fire(t, rt, playKey)
+	}
+	var lit []interface{}
+	for _, e := range events.Snapshot()[before:] {
+		if e.Name == "variable.set" {
+			lit = append(lit, e.Fields["value"])
+		}
+	}
+	return lit
+}
+
+func touch(rt *Runtime, colors ...string) {
+	for _, color := range colors {
+		rt.InjectEvent("device.input", map[string]interface{}{
+			"logical_id": "crystal_pads",
+			"payload":    map[string]interface{}{"symbol": color},
+		})
+	}
+}
+
+func TestPatternRounds(t *testing.T) {
+	rt := startGame(t, patternSceneGraph(nil), "scene_crystals", nil)
+	defer rt.StopGame()
+
+	if lit := playRound(t, rt); len(lit) != 1 || lit[0] != "red" {
+		t.Fatalf("expected round 1 to light red, got %v", lit)
+	}
+	touch(rt, "red")
+	if lit := playRound(t, rt); len(lit) != 2 || lit[1] != "green" {
+		t.Fatalf("expected round 2 to light red, green, got %v", lit)
+	}
+	touch(rt, "red", "green")
+	playRound(t, rt)
+	touch(rt, "red", "green")
+	if rt.GetPuzzleResolution("puzzle_crystals") != PuzzleUnresolved {
+		t.Fatal("expected puzzle unresolved before the last symbol")
+	}
+	touch(rt, "blue")
+	if rt.GetPuzzleResolution("puzzle_crystals") != PuzzleSolved {
+		t.Errorf("expected puzzle solved after the full pattern, got %s", rt.GetPuzzleResolution("puzzle_crystals"))
+	}
+}
+
+func TestPatternIgnoresInputWhilePlaying(t *testing.T) {
+	sg := patternSceneGraph(map[string]interface{}{"start_length": float64(3)})
+	rt := startGame(t, sg, "scene_crystals", nil)
+	defer rt.StopGame()
+
+	fire(t, rt, playKey) // red lit, green and blue to come
+	touch(rt, "green")
+	playRound(t, rt)
+	touch(rt, "red", "green", "blue")
+	if rt.GetPuzzleResolution("puzzle_crystals") != PuzzleSolved {
+		t.Errorf("expected input during playback ignored, got %s", rt.GetPuzzleResolution("puzzle_crystals"))
+	}
+}
+
+func TestPatternWrongInput(t *testing.T) {
+	sg := patternSceneGraph(map[string]interface{}{"on_wrong": "restart", "max_attempts": float64(2)})
+	rt := startGame(t, sg, "scene_crystals", nil)
+	defer rt.StopGame()
+
+	playRound(t, rt)
+	touch(rt, "red")
+	playRound(t, rt)
+	touch(rt, "red", "blue")
+	if lit := playRound(t, rt); len(lit) != 1 {
+		t.Fatalf("expected a wrong input to restart from round 1, got %v", lit)
+	}
+
+	touch(rt, "green")
+	if rt.GetPuzzleResolution("puzzle_crystals") != PuzzleFailed {
+		t.Errorf("expected puzzle failed after max_attempts, got %s", rt.GetPuzzleResolution("puzzle_crystals"))
+	}
+	if n := countEvents("puzzle.failed", "puzzle_crystals"); n != 1 {
+		t.Errorf("expected 1 puzzle.failed, got %d", n)
+	}
+}
+
+func TestPatternStep(t *testing.T) {
+	node := &Node{ID: "p", Type: "pattern", Config: map[string]interface{}{
+		"step_ms":     float64(1000),
+		"speedup_ms":  float64(300),
+		"min_step_ms": float64(250),
+	}}
+	want := []time.Duration{1000, 700, 400, 250}
+	for i, ms := range want {
+		if got := patternStep(node, i+1); got != ms*time.Millisecond {
+			t.Errorf("round %d: step %v, want %v", i+1, got, ms*time.Millisecond)
+		}
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	match := "event == 'device.input'"
+	outputs := map[string]interface{}{
+		"red":  map[string]interface{}{"action": "mqtt.publish"},
+		"blue": map[string]interface{}{"action": "mqtt.publish"},
+	}
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		ok     bool
+	}{
+		{"pattern", map[string]interface{}{"match": match, "outputs": outputs, "pattern": []interface{}{"red", "blue"}}, true},
+		{"length", map[string]interface{}{"match": match, "outputs": outputs, "length": float64(5)}, true},
+		{"missing match", map[string]interface{}{"outputs": outputs, "length": float64(5)}, false},
+		{"missing outputs", map[string]interface{}{"match": match, "pattern": []interface{}{"red"}}, false},
+		{"output without action", map[string]interface{}{"match": match, "outputs": map[string]interface{}{"red": map[string]interface{}{}}, "length": float64(3)}, false},
+		{"unknown symbol", map[string]interface{}{"match": match, "outputs": outputs, "pattern": []interface{}{"green"}}, false},
+		{"pattern and length", map[string]interface{}{"match": match, "outputs": outputs, "pattern": []interface{}{"red"}, "length": float64(3)}, false},
+		{"bad on_wrong", map[string]interface{}{"match": match, "outputs": outputs, "length": float64(3), "on_wrong": "ignore"}, false},
+	}
+	for _, tt := range tests {
+		err := validatePattern(Node{ID: "p", Type: "pattern", Config: tt.config})
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: got err %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
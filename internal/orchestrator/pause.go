@@ -0,0 +1,83 @@
+package orchestrator
+
+import (
+	"fmt"
+	"time"
+)
+
+// PauseScope marks operator.pause/operator.resume events that pause the game
+// rather than only the countdown.
+const PauseScope = "game"
+
+// SetPauseIgnoresInput sets whether device.input is ignored while paused.
+func (r *Runtime) SetPauseIgnoresInput(ignore bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pauseIgnoresInput = ignore
+}
+
+// IsPaused reports whether the game is paused.
+func (r *Runtime) IsPaused() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.paused
+}
+
+// PauseGame freezes the running session: the countdown, timer nodes, delayed
+// actions, loop ticks and puzzle timeouts keep the time they had left and
+// continue on resume. Pause and resume are recorded as operator.pause and
+// operator.resume with scope "game", which restore replays.
+func (r *Runtime) PauseGame() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.activeScene == nil {
+		return fmt.Errorf("no active game")
+	}
+	if r.paused {
+		return fmt.Errorf("game already paused")
+	}
+
+	r.emitEvent("operator.pause", map[string]interface{}{
+		"scope":         PauseScope,
+		"ignores_input": r.pauseIgnoresInput,
+	})
+	r.pauseClock()
+	r.pauseScheduled()
+	r.paused = true
+	r.pausedAt = r.now()
+	return nil
+}
+
+// ResumeGame continues a paused session.
+func (r *Runtime) ResumeGame() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.activeScene == nil {
+		return fmt.Errorf("no active game")
+	}
+	if !r.paused {
+		return fmt.Errorf("game not paused")
+	}
+
+	fields := map[string]interface{}{"scope": PauseScope}
+	if !r.pausedAt.IsZero() {
+		fields["paused_ms"] = r.now().Sub(r.pausedAt).Milliseconds()
+	}
+	r.emitEvent("operator.resume", fields)
+	r.paused = false
+	r.pausedAt = time.Time{}
+	r.resumeScheduled()
+	r.resumeClock()
+	return nil
+}
+
+// ignoresInput reports whether an injected event is dropped because the
+// game is paused.
+// Caller must hold r.mu.
+func (r *Runtime) ignoresInput(name string) bool {
+	return r.paused && r.pauseIgnoresInput && (name == "device.input" || name == "device.state_changed")
+}
@@ -0,0 +1,142 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+func TestPauseGameFreezesSession(t *testing.T) {
+	events.Clear()
+	sg := counterSceneGraph(2)
+	sg.Scenes[0].Nodes[0].Config["timeout_ms"] = float64(60000)
+	rt := NewRuntime(sg)
+	if err := rt.StartGame("scene_counter"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+
+	press(rt, "button")
+	if err := rt.PauseGame(); err != nil {
+		t.Fatalf("pause failed: %v", err)
+	}
+	if err := rt.PauseGame(); err == nil {
+		t.Error("expected pausing twice to fail")
+	}
+	if !rt.GameClock().Paused {
+		t.Error("expected the countdown paused with the game")
+	}
+	if err := rt.ResumeClock(); err == nil {
+		t.Error("expected the countdown to stay paused while the game is")
+	}
+
+	// Timers keep the time they had left and do not fire
+	timeoutKey := puzzleTimeoutKey("puzzle_presses")
+	rt.mu.Lock()
+	remaining, _ := rt.scheduledRemaining(timeoutKey)
+	rt.mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	rt.mu.Lock()
+	later, _ := rt.scheduledRemaining(timeoutKey)
+	rt.mu.Unlock()
+	if remaining == 0 || later != remaining {
+		t.Errorf("expected the puzzle timeout frozen, got %v then %v", remaining, later)
+	}
+
+	press(rt, "button")
+	if rt.GetPuzzleResolution("puzzle_presses") != PuzzleUnresolved {
+		t.Fatal("expected input ignored while paused")
+	}
+
+	if err := rt.ResumeGame(); err != nil {
+		t.Fatalf("resume failed: %v", err)
+	}
+	if rt.GameClock().Paused {
+		t.Error("expected the countdown running after resume")
+	}
+	press(rt, "button")
+	if rt.GetPuzzleResolution("puzzle_presses") != PuzzleSolved {
+		t.Errorf("expected puzzle solved after resume, got %s", rt.GetPuzzleResolution("puzzle_presses"))
+	}
+	if countNamed("operator.pause") != 1 || countNamed("operator.resume") != 1 {
+		t.Errorf("expected one operator.pause and one operator.resume, got %d and %d",
+			countNamed("operator.pause"), countNamed("operator.resume"))
+	}
+}
+
+func TestPauseHoldsFiredTask(t *testing.T) {
+	rt := NewRuntime(counterSceneGraph(1))
+	ran := make(chan struct{}, 1)
+
+	// The timer fires while the lock is held, then the game is paused
+	// before its callback gets the lock
+	rt.mu.Lock()
+	rt.schedule("test", time.Millisecond, func() { ran <- struct{}{} })
+	time.Sleep(20 * time.Millisecond)
+	rt.pauseScheduled()
+	rt.paused = true
+	rt.mu.Unlock()
+
+	select {
+	case <-ran:
+		t.Fatal("expected the task held while paused")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	rt.mu.Lock()
+	rt.paused = false
+	rt.resumeScheduled()
+	rt.mu.Unlock()
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("expected the task to run on resume")
+	}
+}
+
+func TestPauseGameKeepsInput(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(counterSceneGraph(1))
+	rt.SetPauseIgnoresInput(false)
+	if err := rt.StartGame("scene_counter"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+
+	if err := rt.PauseGame(); err != nil {
+		t.Fatalf("pause failed: %v", err)
+	}
+	press(rt, "button")
+	if rt.GetPuzzleResolution("puzzle_presses") != PuzzleSolved {
+		t.Errorf("expected input handled while paused, got %s", rt.GetPuzzleResolution("puzzle_presses"))
+	}
+}
+
+func TestRestorePausedGame(t *testing.T) {
+	pausedAt := time.Date(2026, 1, 1, 12, 30, 0, 0, time.UTC)
+	rows := []storage.EventRow{
+		{EventID: 1, Event: "scene.started", Fields: map[string]interface{}{"scene_id": "scene_counter"}},
+		{EventID: 2, Event: "operator.pause", Fields: map[string]interface{}{"timer_id": GameClockID}},
+		{EventID: 3, Event: "operator.resume", Fields: map[string]interface{}{"timer_id": GameClockID}},
+		{EventID: 4, Event: "operator.pause", Timestamp: pausedAt, Fields: map[string]interface{}{"scope": PauseScope}},
+	}
+	state := stateFromEvents(rows)
+	if !state.Paused || !state.PausedAt.Equal(pausedAt) {
+		t.Fatalf("expected a paused game restored, got paused=%v at %v", state.Paused, state.PausedAt)
+	}
+
+	events.Clear()
+	rt := NewRuntime(counterSceneGraph(1))
+	if err := rt.ApplyRestoredState(state); err != nil {
+		t.Fatalf("failed to apply restored state: %v", err)
+	}
+	defer rt.StopGame()
+	if !rt.IsPaused() {
+		t.Fatal("expected the runtime paused after restore")
+	}
+	if err := rt.ResumeGame(); err != nil {
+		t.Errorf("expected the restored pause to resume, got %v", err)
+	}
+}
@@ -0,0 +1,289 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+)
+
+// PayloadTypes maps logical device ID -> payload field -> declared type.
+// Conditions on a declared field compare by type instead of by text: a bool
+// field equals true whether the device sent true, "true" or 1, and a number
+// equals 5 whether it sent 5, 5.0 or "5".
+type PayloadTypes map[string]map[string]string
+
+// PayloadTypesFromConfig collects the payload declarations of devices.yaml.
+func PayloadTypesFromConfig(cfg *config.DevicesConfig) PayloadTypes {
+	types := make(PayloadTypes)
+	if cfg == nil {
+		return types
+	}
+	for id, dev := range cfg.Devices {
+		if len(dev.Payload) == 0 {
+			continue
+		}
+		fields := make(map[string]string, len(dev.Payload))
+		for name, f := range dev.Payload {
+			fields[name] = f.Type
+		}
+		types[id] = fields
+	}
+	return types
+}
+
+// payloadTypes holds the declarations used by condition evaluation.
+var payloadTypes struct {
+	sync.RWMutex
+	types PayloadTypes
+}
+
+// SetPayloadTypes sets the payload declarations used when evaluating
+// conditions. nil clears them.
+func SetPayloadTypes(types PayloadTypes) {
+	payloadTypes.Lock()
+	defer payloadTypes.Unlock()
+	payloadTypes.types = types
+}
+
+// lookup returns the declared type of a device's payload field, or "".
+func (t PayloadTypes) lookup(logicalID, field string) string {
+	if logicalID == "" {
+		return ""
+	}
+	return t[logicalID][field]
+}
+
+const (
+	payloadPrefix = "payload."
+	inputPrefix   = varsPrefix + inputVar + "."
+)
+
+// payloadFieldRef splits a condition field into the device and payload field
+// it reads: payload.<field>, vars.input.<logical_id>.<field> or
+// state.<logical_id>.<field>. logicalID is "" for payload.<field>, whose
+// device is the event's.
+func payloadFieldRef(field string) (logicalID, name string, ok bool) {
+	if name, ok := strings.CutPrefix(field, payloadPrefix); ok && name != "" {
+		return "", name, true
+	}
+	if rest, ok := strings.CutPrefix(field, inputPrefix); ok {
+		logicalID, name, ok := strings.Cut(rest, ".")
+		return logicalID, name, ok && logicalID != "" && name != ""
+	}
+	return stateFieldRef(field)
+}
+
+// declaredPayloadType returns the declared type of the field a comparison
+// reads, or "" if it reads an undeclared field.
+func declaredPayloadType(ctx *EvalContext, field string) string {
+	logicalID, name, ok := payloadFieldRef(field)
+	if !ok {
+		return ""
+	}
+	if logicalID == "" {
+		if ctx == nil || ctx.Event == nil {
+			return ""
+		}
+		logicalID, _ = ctx.Event.Fields["logical_id"].(string)
+	}
+
+	payloadTypes.RLock()
+	defer payloadTypes.RUnlock()
+	return payloadTypes.types.lookup(logicalID, name)
+}
+
+// parseBool reads a bool payload value or literal: true/false, or 1/0.
+func parseBool(v interface{}) (bool, bool) {
+	switch val := v.(type) {
+	case bool:
+		return val, true
+	case string:
+		switch val {
+		case "true", "1":
+			return true, true
+		case "false", "0":
+			return false, true
+		}
+		return false, false
+	}
+	if f, ok := toFloat(v); ok && (f == 0 || f == 1) {
+		return f == 1, true
+	}
+	return false, false
+}
+
+// matchTyped is matchValue for a field with a declared type.
+func matchTyped(v interface{}, target, typ string) bool {
+	switch typ {
+	case config.PayloadBool:
+		got, ok := parseBool(v)
+		want, wantOK := parseBool(target)
+		return ok && wantOK && got == want
+	case config.PayloadInt, config.PayloadNumber:
+		got, ok := toFloat(v)
+		want, wantOK := toFloat(target)
+		return ok && wantOK && got == want
+	case config.PayloadString:
+		if s, ok := v.(string); ok {
+			return s == target
+		}
+		return false
+	}
+	return matchValue(v, target)
+}
+
+// checkPayloadLiteral reports why a comparison can never match a field of
+// the given type, or "" if it can.
+func checkPayloadLiteral(typ string, op tokenKind, literal string) string {
+	ordering := op != tokEq && op != tokNeq
+	switch typ {
+	case config.PayloadBool:
+		if ordering {
+			return "bool fields only compare with == and !="
+		}
+		if _, ok := parseBool(literal); !ok {
+			return fmt.Sprintf("expected true or false, got %q", literal)
+		}
+	case config.PayloadInt:
+		f, ok := toFloat(literal)
+		if !ok || f != float64(int64(f)) {
+			return fmt.Sprintf("expected an integer, got %q", literal)
+		}
+	case config.PayloadNumber:
+		if _, ok := toFloat(literal); !ok {
+			return fmt.Sprintf("expected a number, got %q", literal)
+		}
+	case config.PayloadString:
+		if ordering {
+			return "string fields only compare with == and !="
+		}
+	}
+	return ""
+}
+
+// ValidatePayloadTypes checks every condition in the graph against the
+// declared payload types. A payload.<field> comparison is checked against
+// the devices named by logical_id == '<id>' in the same && chain; without one
+// the device is unknown and the comparison is not checked.
+func ValidatePayloadTypes(sg *SceneGraph, types PayloadTypes) error {
+	if len(types) == 0 {
+		return nil
+	}
+	for _, t := range sg.Transitions {
+		if err := checkConditionTypes(t.Condition, types); err != nil {
+			return fmt.Errorf("transition %s -> %s: %w", t.From, t.To, err)
+		}
+	}
+	for _, scene := range sg.Scenes {
+		for _, edge := range scene.Edges {
+			if err := checkConditionTypes(edge.Condition, types); err != nil {
+				return fmt.Errorf("scene %s edge %s -> %s: %w", scene.ID, edge.From, edge.To, err)
+			}
+		}
+		for _, inv := range scene.Invariants {
+			if err := checkConditionTypes(inv.Condition, types); err != nil {
+				return fmt.Errorf("scene %s invariant %s: %w", scene.ID, inv.Name, err)
+			}
+		}
+		if err := checkNodeConditionTypes(scene.Nodes, types); err != nil {
+			return fmt.Errorf("scene %s: %w", scene.ID, err)
+		}
+
+		for _, sub := range scene.Subgraphs {
+			for _, edge := range sub.Edges {
+				if err := checkConditionTypes(edge.Condition, types); err != nil {
+					return fmt.Errorf("scene %s subgraph %s edge %s -> %s: %w", scene.ID, sub.ID, edge.From, edge.To, err)
+				}
+			}
+			if err := checkNodeConditionTypes(sub.Nodes, types); err != nil {
+				return fmt.Errorf("scene %s subgraph %s: %w", scene.ID, sub.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// nodeConditionKeys are the node config fields holding a condition.
+var nodeConditionKeys = []string{"match", "reset_on", "stop_condition", "attempt_on"}
+
+// checkNodeConditionTypes checks the conditions in node configs, including
+// gate entries.
+func checkNodeConditionTypes(nodes []Node, types PayloadTypes) error {
+	for _, node := range nodes {
+		for _, key := range nodeConditionKeys {
+			if expr, ok := node.Config[key].(string); ok {
+				if err := checkConditionTypes(expr, types); err != nil {
+					return fmt.Errorf("node %s %s: %w", node.ID, key, err)
+				}
+			}
+		}
+		for _, key := range []string{"all_of", "any_of"} {
+			for _, entry := range configStrings(node.Config, key) {
+				if err := checkConditionTypes(entry, types); err != nil {
+					return fmt.Errorf("node %s %s: %w", node.ID, key, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkConditionTypes checks one condition. Conditions that do not compile
+// are left to ValidateConditions (gate entries may be node IDs).
+func checkConditionTypes(expr string, types PayloadTypes) error {
+	cond, err := CompileCondition(strings.TrimSpace(expr))
+	if err != nil || cond.root == nil {
+		return nil
+	}
+	if err := checkExprTypes(cond.root, nil, types); err != nil {
+		return fmt.Errorf("condition %q: %w", expr, err)
+	}
+	return nil
+}
+
+// checkExprTypes walks a condition. devices are the logical IDs the
+// enclosing && chains require.
+func checkExprTypes(n exprNode, devices []string, types PayloadTypes) error {
+	switch n := n.(type) {
+	case *orNode:
+		for _, t := range n.terms {
+			if err := checkExprTypes(t, devices, types); err != nil {
+				return err
+			}
+		}
+	case *andNode:
+		for _, t := range n.terms {
+			if cmp, ok := t.(*compareNode); ok && cmp.field == "logical_id" && cmp.op == tokEq {
+				devices = append(devices, cmp.value)
+			}
+		}
+		for _, t := range n.terms {
+			if err := checkExprTypes(t, devices, types); err != nil {
+				return err
+			}
+		}
+	case *notNode:
+		return checkExprTypes(n.term, devices, types)
+	case *compareNode:
+		logicalID, name, ok := payloadFieldRef(n.field)
+		if !ok {
+			return nil
+		}
+		targets := devices
+		if logicalID != "" {
+			targets = []string{logicalID}
+		}
+		for _, id := range targets {
+			typ := types.lookup(id, name)
+			if typ == "" {
+				continue
+			}
+			if msg := checkPayloadLiteral(typ, n.op, n.value); msg != "" {
+				return fmt.Errorf("%s of %s is %s: %s", n.field, id, typ, msg)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+)
+
+func withPayloadTypes(t *testing.T, types PayloadTypes) {
+	t.Helper()
+	SetPayloadTypes(types)
+	t.Cleanup(func() { SetPayloadTypes(nil) })
+}
+
+func TestTypedPayloadComparisons(t *testing.T) {
+	withPayloadTypes(t, PayloadTypes{
+		"lever":  {"pulled": "bool"},
+		"scale":  {"weight": "int"},
+		"beacon": {"temperature": "number"},
+	})
+
+	tests := []struct {
+		expr    string
+		id      string
+		payload map[string]interface{}
+		want    bool
+	}{
+		{"payload.pulled == true", "lever", map[string]interface{}{"pulled": "1"}, true},
+		{"payload.pulled == 'true'", "lever", map[string]interface{}{"pulled": float64(1)}, true},
+		{"payload.pulled == false", "lever", map[string]interface{}{"pulled": "0"}, true},
+		{"payload.pulled != true", "lever", map[string]interface{}{"pulled": true}, false},
+		{"payload.weight == 5", "scale", map[string]interface{}{"weight": "5.0"}, true},
+		{"payload.weight == 5", "scale", map[string]interface{}{"weight": "five"}, false},
+		{"payload.temperature == 21.5", "beacon", map[string]interface{}{"temperature": "21.50"}, true},
+		// Undeclared devices and fields keep text comparison
+		{"payload.pulled == true", "other", map[string]interface{}{"pulled": "1"}, false},
+		{"payload.state == 1", "lever", map[string]interface{}{"state": "1.0"}, false},
+	}
+	for _, tt := range tests {
+		ctx := &EvalContext{Event: &Event{Name: "device.input", Fields: map[string]interface{}{
+			"logical_id": tt.id,
+			"payload":    tt.payload,
+		}}}
+		if got := EvalCondition(tt.expr, ctx); got != tt.want {
+			t.Errorf("%s with %s %v: got %v, want %v", tt.expr, tt.id, tt.payload, got, tt.want)
+		}
+	}
+
+	ctx := &EvalContext{Vars: map[string]interface{}{
+		"input": map[string]interface{}{"lever": map[string]interface{}{"pulled": float64(1)}},
+	}}
+	if !EvalCondition("vars.input.lever.pulled == true", ctx) {
+		t.Error("expected vars.input.lever.pulled to compare as bool")
+	}
+}
+
+func TestValidatePayloadTypes(t *testing.T) {
+	types := PayloadTypes{
+		"lever":  {"pulled": "bool"},
+		"scale":  {"weight": "int"},
+		"beacon": {"temperature": "number", "zone": "string"},
+	}
+	graph := func(expr string) *SceneGraph {
+		return &SceneGraph{Version: 1, Scenes: []Scene{{
+			ID:    "scene_a",
+			Entry: "start",
+			Nodes: []Node{{ID: "start", Type: "action"}, {ID: "end", Type: "terminal"}},
+			Edges: []Edge{{From: "start", To: "end", Condition: expr}},
+		}}}
+	}
+
+	tests := []struct {
+		expr string
+		ok   bool
+	}{
+		{"logical_id == 'lever' && payload.pulled == true", true},
+		{"logical_id == 'lever' && payload.pulled == 'open'", false},
+		{"logical_id == 'lever' && payload.pulled > 0", false},
+		{"logical_id == 'scale' && payload.weight == 'true'", false},
+		{"logical_id == 'scale' && payload.weight >= 1.5", false},
+		{"logical_id == 'scale' && payload.weight >= 12", true},
+		{"logical_id == 'beacon' && (payload.temperature > 30 || payload.zone == 'north')", true},
+		{"logical_id == 'beacon' && !(payload.temperature == 'hot')", false},
+		{"logical_id == 'beacon' && payload.zone < 3", false},
+		{"vars.input.lever.pulled == 'yes'", false},
+		// Without a logical_id the device is unknown
+		{"payload.pulled == 'open'", true},
+		{"logical_id == 'door' && payload.pulled == 'open'", true},
+	}
+	for _, tt := range tests {
+		err := ValidatePayloadTypes(graph(tt.expr), types)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: got err %v, want ok=%v", tt.expr, err, tt.ok)
+		}
+	}
+
+	err := ValidatePayloadTypes(graph("logical_id == 'scale' && payload.weight == 'true'"), types)
+	if err == nil || !strings.Contains(err.Error(), "edge start -> end") || !strings.Contains(err.Error(), "payload.weight of scale is int") {
+		t.Errorf("expected error to name the edge and field, got %v", err)
+	}
+
+	types["button"] = map[string]string{"pressed": "int"}
+	if err := ValidatePayloadTypes(counterSceneGraph(3), types); err == nil || !strings.Contains(err.Error(), "node count_presses match") {
+		t.Errorf("expected subgraph node match to be checked, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package orchestrator
+
+import "github.com/AaronLay10/SentientEngine/internal/events"
+
+// SubgraphProgress is the position of an unresolved puzzle's subgraph,
+// rebuilt from puzzle.step and puzzle.progress so startup restore can resume
+// the puzzle part way through (ADR-015).
+type SubgraphProgress struct {
+	SubgraphID string
+	Completed  map[string]bool // subgraph node_id -> completed
+	Next       []string        // nodes activated by steps, in order
+	Attempts   map[string]int  // wrong keypad codes per node
+}
+
+// newSubgraphProgress creates empty progress for a puzzle's subgraph.
+func newSubgraphProgress(subgraphID string) *SubgraphProgress {
+	return &SubgraphProgress{
+		SubgraphID: subgraphID,
+		Completed:  make(map[string]bool),
+		Attempts:   make(map[string]int),
+	}
+}
+
+// activeNodes returns the nodes that were waiting: those a step activated that
+// never completed, or the entry node if no step was taken.
+func (p *SubgraphProgress) activeNodes(entry string) []string {
+	if len(p.Completed) == 0 && len(p.Next) == 0 {
+		return []string{entry}
+	}
+	var active []string
+	seen := make(map[string]bool)
+	for _, id := range p.Next {
+		if id == "" || p.Completed[id] || seen[id] {
+			continue
+		}
+		seen[id] = true
+		active = append(active, id)
+	}
+	return active
+}
+
+// recordStep emits puzzle.step for a completed subgraph node. next is the node
+// it activated, or "" if none.
+func (pr *PuzzleRuntime) recordStep(nodeID, next string) {
+	if pr.generic {
+		return
+	}
+	events.Emit("info", "puzzle.step", "", map[string]interface{}{
+		"puzzle_id":   pr.parentNodeID,
+		"subgraph_id": pr.subgraph.ID,
+		"node_id":     nodeID,
+		"next":        next,
+	})
+}
+
+// Resume puts the subgraph back where progress left it, without emitting
+// events for completed nodes. Waiting nodes keep their progress; an action
+// node that was waiting on a delay runs again, since its action had not run.
+func (pr *PuzzleRuntime) Resume(progress *SubgraphProgress) {
+	for nodeID := range progress.Completed {
+		if status := pr.nodeStates[nodeID]; status != nil {
+			status.State = NodeStateCompleted
+		}
+	}
+
+	for _, nodeID := range progress.activeNodes(pr.subgraph.Entry) {
+		node := pr.findNode(nodeID)
+		status := pr.nodeStates[nodeID]
+		if node == nil || status.State != NodeStateIdle {
+			continue
+		}
+		switch node.Type {
+		case "action", "terminal":
+			pr.activateNode(nodeID)
+		case "keypad":
+			status.State = NodeStateActive
+			pr.startKeypad(node)
+			pr.keypads[nodeID].attempts = progress.Attempts[nodeID]
+		case "threshold":
+			status.State = NodeStateActive
+			pr.startThreshold(node)
+		case "pattern":
+			// Patterns start over from the first round
+			status.State = NodeStateActive
+			pr.startPattern(node)
+			pr.patterns[nodeID].attempts = progress.Attempts[nodeID]
+		default:
+			// Decisions wait for events; counters keep vars.counter.<node_id>
+			status.State = NodeStateActive
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package orchestrator
 
 import (
+	"math/rand"
+	"time"
+
 	"github.com/AaronLay10/SentientEngine/internal/events"
 )
 
@@ -8,13 +11,26 @@ import (
 // Returns an error if execution fails.
 type ActionFunc func(nodeID string, config map[string]interface{}) error
 
+// ScheduleFunc runs fn after d on behalf of a subgraph node (see delay.go).
+type ScheduleFunc func(nodeID string, d time.Duration, fn func())
+
 // PuzzleRuntime manages execution of a single puzzle subgraph.
+// It also runs generic (non-puzzle) subgraphs, which finish without a resolution.
 type PuzzleRuntime struct {
 	subgraph     *Subgraph
 	parentNodeID string
 	nodeStates   map[string]*NodeStatus
 	resolution   PuzzleResolution
 	actionFunc   ActionFunc
+	scheduleFunc ScheduleFunc
+	vars         map[string]interface{}
+	rng          *rand.Rand
+	keypads      map[string]*keypadState    // see keypad.go
+	thresholds   map[string]*thresholdState // see threshold.go
+	patterns     map[string]*patternState   // see pattern.go
+	attempts     int                        // wrong inputs when a keypad or pattern failed the puzzle
+	generic      bool
+	finished     bool
 }
 
 // NewPuzzleRuntime creates a new runtime for a puzzle subgraph.
@@ -37,24 +53,50 @@ func NewPuzzleRuntime(subgraph *Subgraph, parentNodeID string) *PuzzleRuntime {
 	return pr
 }
 
+// NewSubgraphRuntime creates a runtime for a generic subgraph launched by a
+// scene-level subgraph node. It emits no puzzle events and has no resolution.
+func NewSubgraphRuntime(subgraph *Subgraph, parentNodeID string) *PuzzleRuntime {
+	pr := NewPuzzleRuntime(subgraph, parentNodeID)
+	pr.generic = true
+	return pr
+}
+
 // SetActionFunc sets the function used to execute action nodes.
 func (pr *PuzzleRuntime) SetActionFunc(fn ActionFunc) {
 	pr.actionFunc = fn
 }
 
+// SetScheduleFunc sets the function used to delay action nodes.
+// Without one, delay_ms and wait are ignored.
+func (pr *PuzzleRuntime) SetScheduleFunc(fn ScheduleFunc) {
+	pr.scheduleFunc = fn
+}
+
+// SetVars shares the session variables with subgraph conditions.
+func (pr *PuzzleRuntime) SetVars(vars map[string]interface{}) {
+	pr.vars = vars
+}
+
+// SetRand sets the source for generated keypad codes and patterns.
+func (pr *PuzzleRuntime) SetRand(rng *rand.Rand) {
+	pr.rng = rng
+}
+
 // Start begins subgraph execution at the entry node.
 func (pr *PuzzleRuntime) Start() {
 	pr.activateNode(pr.subgraph.Entry)
 }
 
-// HandleEvent processes an event and returns true if the puzzle resolved.
+// HandleEvent processes an event and returns true if the puzzle resolved
+// (or a generic subgraph reached its terminal).
 func (pr *PuzzleRuntime) HandleEvent(evt Event) bool {
-	if pr.resolution != PuzzleUnresolved {
+	if pr.Done() {
 		return false
 	}
 
 	ctx := &EvalContext{
 		Event: &evt,
+		Vars:  pr.vars,
 	}
 
 	// Find active decision nodes and evaluate their outgoing edges
@@ -64,11 +106,44 @@ func (pr *PuzzleRuntime) HandleEvent(evt Event) bool {
 			continue
 		}
 
+		if node.Type == "counter" {
+			if pr.handleCounter(&node, ctx) {
+				pr.completeNode(node.ID)
+				pr.advanceFromNode(node.ID)
+			}
+			continue
+		}
+
+		if node.Type == "threshold" {
+			if pr.handleThreshold(&node, ctx) {
+				pr.completeNode(node.ID)
+				pr.advanceFromNode(node.ID)
+			}
+			continue
+		}
+
+		if node.Type == "keypad" {
+			if pr.handleKeypad(&node, ctx) {
+				pr.completeNode(node.ID)
+				pr.advanceFromNode(node.ID)
+			}
+			continue
+		}
+
+		if node.Type == "pattern" {
+			if pr.handlePattern(&node, ctx) {
+				pr.completeNode(node.ID)
+				pr.advanceFromNode(node.ID)
+			}
+			continue
+		}
+
 		if node.Type == "decision" {
 			for _, edge := range pr.subgraph.Edges {
 				if edge.From == node.ID {
 					if EvalCondition(edge.Condition, ctx) {
 						pr.completeNode(node.ID)
+						pr.recordStep(node.ID, edge.To)
 						pr.activateNode(edge.To)
 						break
 					}
@@ -77,7 +152,12 @@ func (pr *PuzzleRuntime) HandleEvent(evt Event) bool {
 		}
 	}
 
-	return pr.resolution != PuzzleUnresolved
+	return pr.Done()
+}
+
+// Done returns true once the puzzle resolved or a generic subgraph finished.
+func (pr *PuzzleRuntime) Done() bool {
+	return pr.finished || pr.resolution != PuzzleUnresolved
 }
 
 // Override marks the puzzle as resolved via operator override.
@@ -93,6 +173,20 @@ func (pr *PuzzleRuntime) Override() {
 	})
 }
 
+// Fail stops the puzzle after a timeout or too many attempts.
+// The runtime emits puzzle.failed.
+func (pr *PuzzleRuntime) Fail() {
+	if pr.resolution != PuzzleUnresolved {
+		return
+	}
+	pr.resolution = PuzzleFailed
+}
+
+// Attempts returns the wrong inputs on the keypad or pattern that failed the puzzle.
+func (pr *PuzzleRuntime) Attempts() int {
+	return pr.attempts
+}
+
 // Resolution returns the current resolution state.
 func (pr *PuzzleRuntime) Resolution() PuzzleResolution {
 	return pr.resolution
@@ -122,22 +216,47 @@ func (pr *PuzzleRuntime) activateNode(nodeID string) {
 
 	switch node.Type {
 	case "action":
-		// Execute action if we have an executor
-		if pr.actionFunc != nil {
-			if err := pr.actionFunc(nodeID, node.Config); err != nil {
-				// Action failed, but we still complete the node for deterministic flow
-				// Error was already emitted via device.error event by the executor
-			}
+		if delay := actionDelay(node.Config); delay > 0 && pr.scheduleFunc != nil {
+			pr.scheduleFunc(nodeID, delay, func() {
+				if !pr.Done() {
+					pr.runAction(node)
+				}
+			})
+			return
 		}
-		pr.completeNode(nodeID)
-		pr.advanceFromNode(nodeID)
+		pr.runAction(node)
 	case "decision":
 		// Decision waits for events - handled in HandleEvent
+	case "counter":
+		// Counters start from zero and count events in HandleEvent
+		pr.setCounter(node, 0)
+	case "keypad":
+		// Keypads collect keys in HandleEvent
+		pr.startKeypad(node)
+	case "threshold":
+		// Thresholds watch sensor readings in HandleEvent
+		pr.startThreshold(node)
+	case "pattern":
+		// Patterns play their first round and check input in HandleEvent
+		pr.startPattern(node)
 	case "terminal":
 		pr.reachTerminal()
 	}
 }
 
+// runAction executes an action node and continues the sequence.
+func (pr *PuzzleRuntime) runAction(node *Node) {
+	// Execute action if we have an executor
+	if pr.actionFunc != nil {
+		if err := pr.actionFunc(node.ID, node.Config); err != nil {
+			// Action failed, but we still complete the node for deterministic flow
+			// Error was already emitted via device.error event by the executor
+		}
+	}
+	pr.completeNode(node.ID)
+	pr.advanceFromNode(node.ID)
+}
+
 func (pr *PuzzleRuntime) completeNode(nodeID string) {
 	status := pr.nodeStates[nodeID]
 	status.State = NodeStateCompleted
@@ -159,19 +278,26 @@ func (pr *PuzzleRuntime) advanceFromNode(nodeID string) {
 			Name:   "node.completed",
 			Fields: map[string]interface{}{"node_id": nodeID},
 		},
+		Vars: pr.vars,
 	}
 
 	for _, edge := range pr.subgraph.Edges {
 		if edge.From == nodeID {
 			if EvalCondition(edge.Condition, ctx) {
+				pr.recordStep(nodeID, edge.To)
 				pr.activateNode(edge.To)
 				return
 			}
 		}
 	}
+	pr.recordStep(nodeID, "")
 }
 
 func (pr *PuzzleRuntime) reachTerminal() {
+	if pr.generic {
+		pr.finished = true
+		return
+	}
 	pr.resolution = PuzzleSolved
 	events.Emit("info", "puzzle.solved", "", map[string]interface{}{
 		"puzzle_id":   pr.parentNodeID,
@@ -0,0 +1,90 @@
+package orchestrator
+
+// randomChoice is one weighted branch of a random node. The branch picked is
+// recorded on node.completed so restore takes the same one.
+type randomChoice struct {
+	to     string
+	weight float64
+}
+
+// randomChoices reads weighted choices from node config, dropping non-positive weights.
+func randomChoices(node *Node) []randomChoice {
+	raw, ok := node.Config["choices"].([]interface{})
+	if !ok {
+		return nil
+	}
+	var out []randomChoice
+	for _, item := range raw {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		to, _ := m["to"].(string)
+		if to == "" {
+			continue
+		}
+		weight := 1.0
+		if w, ok := m["weight"].(float64); ok {
+			weight = w
+		}
+		if weight <= 0 {
+			continue
+		}
+		out = append(out, randomChoice{to: to, weight: weight})
+	}
+	return out
+}
+
+// chooseRandom selects (or replays) a random node's branch.
+// Returns "" if the node has no valid choices.
+func (r *Runtime) chooseRandom(node *Node) string {
+	choices := randomChoices(node)
+
+	// Replay a recorded choice if it is still a valid branch
+	if recorded, ok := r.randomChoices[node.ID]; ok {
+		for _, c := range choices {
+			if c.to == recorded {
+				return recorded
+			}
+		}
+	}
+
+	if len(choices) == 0 {
+		return ""
+	}
+
+	var total float64
+	for _, c := range choices {
+		total += c.weight
+	}
+	pick := r.rng.Float64() * total
+	chosen := choices[len(choices)-1].to
+	for _, c := range choices {
+		if pick < c.weight {
+			chosen = c.to
+			break
+		}
+		pick -= c.weight
+	}
+
+	r.randomChoices[node.ID] = chosen
+	return chosen
+}
+
+// activateRandom picks a branch and completes the node, following only that branch.
+func (r *Runtime) activateRandom(node *Node) {
+	choice := r.chooseRandom(node)
+	r.randomChoices[node.ID] = choice
+	r.completeNodeWith(node.ID, map[string]interface{}{"choice": choice})
+}
+
+// edgeSelected reports whether an edge may be followed. Edges leaving a random
+// node are followed only toward the chosen branch.
+func (r *Runtime) edgeSelected(edge Edge) bool {
+	node := r.findNode(edge.From)
+	if node == nil || node.Type != "random" {
+		return true
+	}
+	choice, ok := r.randomChoices[edge.From]
+	return ok && choice == edge.To
+}
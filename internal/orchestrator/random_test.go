@@ -0,0 +1,178 @@
+package orchestrator
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// randomSceneGraph builds a scene where a puzzle leads into a random branch.
+func randomSceneGraph(choices []interface{}) *SceneGraph {
+	return sceneGraph(Scene{
+		ID:    "scene_random",
+		Entry: "intro",
+		Nodes: []Node{
+			{ID: "intro", Type: "puzzle", Config: map[string]interface{}{"subgraph": "sub_intro"}},
+			{ID: "pick", Type: "random", Config: map[string]interface{}{"choices": choices}},
+			{ID: "amb_a", Type: "decision"},
+			{ID: "amb_b", Type: "decision"},
+			{ID: "amb_c", Type: "decision"},
+		},
+		Edges: []Edge{
+			{From: "intro", To: "pick"},
+			{From: "pick", To: "amb_a"},
+			{From: "pick", To: "amb_b"},
+			{From: "pick", To: "amb_c"},
+		},
+		Subgraphs: []Subgraph{
+			{ID: "sub_intro", Entry: "wait", Nodes: []Node{{ID: "wait", Type: "decision"}}},
+		},
+	})
+}
+
+func threeChoices() []interface{} {
+	return []interface{}{
+		map[string]interface{}{"to": "amb_a", "weight": float64(1)},
+		map[string]interface{}{"to": "amb_b", "weight": float64(1)},
+		map[string]interface{}{"to": "amb_c", "weight": float64(1)},
+	}
+}
+
+// activeBranches returns which ambience branches were activated.
+func activeBranches(rt *Runtime) []string {
+	var out []string
+	for _, id := range []string{"amb_a", "amb_b", "amb_c"} {
+		if rt.GetNodeState(id) != NodeStateIdle {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func TestRandomFollowsOneBranch(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(randomSceneGraph([]interface{}{
+		map[string]interface{}{"to": "amb_a", "weight": float64(0)},
+		map[string]interface{}{"to": "amb_b", "weight": float64(2)},
+		map[string]interface{}{"to": "amb_c", "weight": float64(-1)},
+	}))
+	if err := rt.StartScene("scene_random"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+	if err := rt.OverrideNode("intro"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+
+	if rt.GetNodeState("pick") != NodeStateCompleted {
+		t.Fatalf("expected random node to complete, got %s", rt.GetNodeState("pick"))
+	}
+	branches := activeBranches(rt)
+	if len(branches) != 1 || branches[0] != "amb_b" {
+		t.Errorf("expected only amb_b to activate, got %v", branches)
+	}
+
+	var choice interface{}
+	for _, e := range events.Snapshot() {
+		if e.Name == "node.completed" && e.Fields["node_id"] == "pick" {
+			choice = e.Fields["choice"]
+		}
+	}
+	if choice != "amb_b" {
+		t.Errorf("expected choice amb_b in node.completed, got %v", choice)
+	}
+}
+
+func TestRandomWeights(t *testing.T) {
+	rt := NewRuntime(randomSceneGraph([]interface{}{
+		map[string]interface{}{"to": "amb_a", "weight": float64(3)},
+		map[string]interface{}{"to": "amb_b"},
+	}))
+	rt.rng = rand.New(rand.NewSource(1))
+	if err := rt.StartScene("scene_random"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+	node := rt.findNode("pick")
+
+	counts := make(map[string]int)
+	for i := 0; i < 4000; i++ {
+		delete(rt.randomChoices, "pick")
+		counts[rt.chooseRandom(node)]++
+	}
+
+	if counts["amb_a"] < 2800 || counts["amb_a"] > 3200 {
+		t.Errorf("expected amb_a roughly 3x amb_b, got %v", counts)
+	}
+	if counts["amb_a"]+counts["amb_b"] != 4000 {
+		t.Errorf("unexpected choices: %v", counts)
+	}
+}
+
+func TestRandomResetRedraws(t *testing.T) {
+	rt := NewRuntime(randomSceneGraph(threeChoices()))
+	if err := rt.StartScene("scene_random"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+	if err := rt.OverrideNode("intro"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+
+	rt.mu.Lock()
+	rt.resetNodeState("pick", "")
+	_, recorded := rt.randomChoices["pick"]
+	rt.mu.Unlock()
+
+	if recorded {
+		t.Error("expected reset to clear the random choice")
+	}
+}
+
+func TestRandomRestoreReplaysChoice(t *testing.T) {
+	now := time.Now()
+	rows := []storage.EventRow{
+		{EventID: 1, Timestamp: now.Add(-5 * time.Minute), Event: "scene.started",
+			Fields: map[string]interface{}{"scene_id": "scene_random"}},
+		{EventID: 2, Timestamp: now.Add(-4 * time.Minute), Event: "puzzle.overridden",
+			Fields: map[string]interface{}{"node_id": "intro"}},
+		{EventID: 3, Timestamp: now.Add(-4 * time.Minute), Event: "node.completed",
+			Fields: map[string]interface{}{"node_id": "pick", "choice": "amb_c"}},
+	}
+
+	state := stateFromEvents(rows)
+	if state.RandomChoices["pick"] != "amb_c" {
+		t.Fatalf("expected restored choice amb_c, got %v", state.RandomChoices)
+	}
+
+	// Whatever the seed, the restored runtime takes the recorded branch
+	for seed := int64(0); seed < 10; seed++ {
+		rt := NewRuntime(randomSceneGraph(threeChoices()))
+		rt.rng = rand.New(rand.NewSource(seed))
+		if err := rt.ApplyRestoredState(state); err != nil {
+			t.Fatalf("failed to apply restored state: %v", err)
+		}
+
+		rt.mu.Lock()
+		rt.evaluateAllConditions()
+		rt.mu.Unlock()
+
+		branches := activeBranches(rt)
+		if len(branches) != 1 || branches[0] != "amb_c" {
+			t.Fatalf("seed %d: expected amb_c after restore, got %v", seed, branches)
+		}
+	}
+}
+
+func TestRandomRestoreForgetsResetChoice(t *testing.T) {
+	rows := []storage.EventRow{
+		{EventID: 1, Event: "scene.started", Fields: map[string]interface{}{"scene_id": "scene_random"}},
+		{EventID: 2, Event: "node.completed", Fields: map[string]interface{}{"node_id": "pick", "choice": "amb_a"}},
+		{EventID: 3, Event: "node.reset", Fields: map[string]interface{}{"node_id": "pick"}},
+	}
+
+	state := stateFromEvents(rows)
+	if _, ok := state.RandomChoices["pick"]; ok {
+		t.Errorf("expected node.reset to clear the choice, got %v", state.RandomChoices)
+	}
+}
@@ -0,0 +1,254 @@
+package orchestrator
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// ReconcileMode selects what startup reconciliation does.
+type ReconcileMode string
+
+// Reconcile modes.
+const (
+	ReconcileOff     ReconcileMode = "off"
+	ReconcileReport  ReconcileMode = "report"
+	ReconcileCorrect ReconcileMode = "correct"
+)
+
+// Mismatch reasons.
+const (
+	MismatchValue      = "mismatch"
+	MismatchNoResponse = "no_response"
+)
+
+// ParseReconcileMode returns the reconcile mode named s.
+func ParseReconcileMode(s string) (ReconcileMode, error) {
+	switch mode := ReconcileMode(s); mode {
+	case ReconcileOff, ReconcileReport, ReconcileCorrect:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown reconcile mode %q (report, correct or off)", s)
+}
+
+// DeviceStateQuerier asks devices for their current state (implemented by
+// *mqtt.DeviceSubscriber).
+type DeviceStateQuerier interface {
+	QueryState(logicalIDs []string, timeout time.Duration) map[string]map[string]interface{}
+}
+
+// stateExpectation is the state a prop should be in once a node has ended.
+type stateExpectation struct {
+	nodeID   string
+	deviceID string
+	field    string
+	value    interface{}
+	correct  map[string]interface{} // signal and payload of the corrective command, or nil
+}
+
+// StateMismatch is a prop whose state differs from what the restored game expects.
+type StateMismatch struct {
+	NodeID    string      `json:"node_id"`
+	LogicalID string      `json:"logical_id"`
+	Field     string      `json:"field"`
+	Expected  interface{} `json:"expected"`
+	Actual    interface{} `json:"actual,omitempty"`
+	Reason    string      `json:"reason"`
+	Corrected bool        `json:"corrected"`
+}
+
+// Reconciliation is the outcome of one reconciliation.
+type Reconciliation struct {
+	Checked    int             `json:"checked"`
+	Mismatches []StateMismatch `json:"mismatches"`
+}
+
+// nodeExpectations reads a node's expect list.
+func nodeExpectations(node *Node) []stateExpectation {
+	raw, _ := node.Config["expect"].([]interface{})
+	var out []stateExpectation
+	for _, item := range raw {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		e := stateExpectation{nodeID: node.ID, value: m["value"]}
+		e.deviceID, _ = m["device_id"].(string)
+		e.field, _ = m["field"].(string)
+		e.correct, _ = m["correct"].(map[string]interface{})
+		out = append(out, e)
+	}
+	return out
+}
+
+// validateExpectations checks a node's expect list.
+func validateExpectations(node Node) error {
+	raw, ok := node.Config["expect"]
+	if !ok {
+		return nil
+	}
+	list, ok := raw.([]interface{})
+	if !ok {
+		return fmt.Errorf("node %s: expect must be a list", node.ID)
+	}
+	for i, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("node %s expect[%d]: must be an object", node.ID, i)
+		}
+		for _, key := range []string{"device_id", "field"} {
+			if s, _ := m[key].(string); s == "" {
+				return fmt.Errorf("node %s expect[%d]: missing %s", node.ID, i, key)
+			}
+		}
+		if _, ok := m["value"]; !ok {
+			return fmt.Errorf("node %s expect[%d]: missing value", node.ID, i)
+		}
+		if correct, ok := m["correct"]; ok {
+			c, _ := correct.(map[string]interface{})
+			if signal, _ := c["signal"].(string); signal == "" {
+				return fmt.Errorf("node %s expect[%d]: correct requires signal", node.ID, i)
+			}
+		}
+	}
+	return nil
+}
+
+// Reconcile compares the props named by ended nodes' expectations with what
+// the devices report, waiting up to timeout for their answers. A restored game
+// assumes props are where the logic left them, but a door may have been
+// closed by hand while the orchestrator was down. Each difference emits
+// device.state_mismatch; with correct set, mismatches that have a corrective
+// command send it (config.expect in design/scene-graph/schema.md). It returns
+// nil if no game is running or no ended node expects anything.
+func (r *Runtime) Reconcile(q DeviceStateQuerier, timeout time.Duration, correct bool) *Reconciliation {
+	r.mu.Lock()
+	expectations := r.endedExpectations()
+	r.mu.Unlock()
+	if len(expectations) == 0 {
+		return nil
+	}
+
+	var devices []string
+	seen := make(map[string]bool)
+	for _, e := range expectations {
+		if !seen[e.deviceID] {
+			seen[e.deviceID] = true
+			devices = append(devices, e.deviceID)
+		}
+	}
+
+	// Devices answer through the subscriber, which routes input to the
+	// runtime, so the lock is not held while waiting
+	reported := q.QueryState(devices, timeout)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := &Reconciliation{Checked: len(expectations), Mismatches: []StateMismatch{}}
+	for _, e := range expectations {
+		actual, answered := reported[e.deviceID][e.field]
+		if answered && expectationMet(e, actual) {
+			continue
+		}
+		m := StateMismatch{
+			NodeID:    e.nodeID,
+			LogicalID: e.deviceID,
+			Field:     e.field,
+			Expected:  e.value,
+			Actual:    actual,
+			Reason:    MismatchValue,
+		}
+		if !answered {
+			m.Reason = MismatchNoResponse
+		}
+		if correct && answered && e.correct != nil {
+			m.Corrected = r.sendCorrection(e)
+		}
+		report.Mismatches = append(report.Mismatches, m)
+
+		fields := map[string]interface{}{
+			"node_id":    m.NodeID,
+			"logical_id": m.LogicalID,
+			"field":      m.Field,
+			"expected":   m.Expected,
+			"reason":     m.Reason,
+			"corrected":  m.Corrected,
+		}
+		if answered {
+			fields["actual"] = m.Actual
+		}
+		r.emitEvent("device.state_mismatch", fields)
+	}
+
+	corrected := 0
+	for _, m := range report.Mismatches {
+		if m.Corrected {
+			corrected++
+		}
+	}
+	r.emitEvent("system.reconciled", map[string]interface{}{
+		"checked":    report.Checked,
+		"mismatches": len(report.Mismatches),
+		"corrected":  corrected,
+	})
+	return report
+}
+
+// endedExpectations returns the expectations of the active scene's ended
+// nodes, in node order.
+// Caller must hold r.mu.
+func (r *Runtime) endedExpectations() []stateExpectation {
+	if r.activeScene == nil {
+		return nil
+	}
+	var out []stateExpectation
+	for i := range r.activeScene.Nodes {
+		node := &r.activeScene.Nodes[i]
+		status, ok := r.nodeStates[node.ID]
+		if !ok || (status.State != NodeStateCompleted && status.State != NodeStateOverridden) {
+			continue
+		}
+		out = append(out, nodeExpectations(node)...)
+	}
+	return out
+}
+
+// expectationMet compares a reported value with an expectation, by declared
+// payload type if the field has one.
+func expectationMet(e stateExpectation, actual interface{}) bool {
+	payloadTypes.RLock()
+	typ := payloadTypes.types.lookup(e.deviceID, e.field)
+	payloadTypes.RUnlock()
+
+	target := fmt.Sprint(e.value)
+	if f, ok := e.value.(float64); ok {
+		target = formatFloat(f)
+	}
+	return matchTyped(actual, target, typ)
+}
+
+// sendCorrection sends an expectation's corrective command and reports
+// whether it was sent.
+// Caller must hold r.mu.
+func (r *Runtime) sendCorrection(e stateExpectation) bool {
+	if r.actionExecutor == nil {
+		return false
+	}
+	params := map[string]interface{}{
+		"device_id": e.deviceID,
+		"signal":    e.correct["signal"],
+	}
+	if payload, ok := e.correct["payload"]; ok {
+		params["payload"] = payload
+	}
+	err := r.actionExecutor.ExecuteAction(e.nodeID, map[string]interface{}{
+		"action": "device.command",
+		"params": params,
+	})
+	if err != nil {
+		log.Printf("[reconcile] correction for %s.%s failed: %v", e.deviceID, e.field, err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,162 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// fakeQuerier answers state queries from a fixed table.
+type fakeQuerier struct {
+	reported map[string]map[string]interface{}
+	asked    []string
+}
+
+func (q *fakeQuerier) QueryState(logicalIDs []string, timeout time.Duration) map[string]map[string]interface{} {
+	q.asked = append(q.asked, logicalIDs...)
+	out := make(map[string]map[string]interface{})
+	for _, id := range logicalIDs {
+		if fields, ok := q.reported[id]; ok {
+			out[id] = fields
+		}
+	}
+	return out
+}
+
+// reconcileSceneGraph is the button counter puzzle with expectations on the
+// props it leaves behind.
+func reconcileSceneGraph() *SceneGraph {
+	sg := counterSceneGraph(1)
+	sg.Scenes[0].Nodes[0].Config["expect"] = []interface{}{
+		map[string]interface{}{"device_id": "crypt_door", "field": "open", "value": true,
+			"correct": map[string]interface{}{"signal": "unlock"}},
+		map[string]interface{}{"device_id": "lights", "field": "level", "value": float64(80)},
+		map[string]interface{}{"device_id": "fog", "field": "on", "value": false},
+	}
+	sg.Scenes[0].Nodes[1].Config["expect"] = []interface{}{
+		map[string]interface{}{"device_id": "hint_screen", "field": "on", "value": true},
+	}
+	return sg
+}
+
+func TestReconcileReportsMismatches(t *testing.T) {
+	rt := startGame(t, reconcileSceneGraph(), "scene_counter", nil)
+	press(rt, "button")
+	q := &fakeQuerier{reported: map[string]map[string]interface{}{
+		"crypt_door": {"open": false},
+		"lights":     {"level": float64(80)},
+	}}
+
+	report := rt.Reconcile(q, time.Second, false)
+	if report == nil {
+		t.Fatal("expected a reconciliation")
+	}
+	if report.Checked != 3 {
+		t.Errorf("expected the completed puzzle's 3 expectations checked, got %d", report.Checked)
+	}
+	for _, id := range q.asked {
+		if id == "hint_screen" {
+			t.Error("expected nodes that have not ended to be skipped")
+		}
+	}
+	if len(report.Mismatches) != 2 {
+		t.Fatalf("expected 2 mismatches, got %+v", report.Mismatches)
+	}
+	door, fog := report.Mismatches[0], report.Mismatches[1]
+	if door.LogicalID != "crypt_door" || door.Reason != MismatchValue || door.Actual != false || door.Corrected {
+		t.Errorf("unexpected door mismatch: %+v", door)
+	}
+	if fog.LogicalID != "fog" || fog.Reason != MismatchNoResponse || fog.Actual != nil {
+		t.Errorf("unexpected fog mismatch: %+v", fog)
+	}
+
+	var mismatches, reconciled []events.Event
+	for _, e := range events.Snapshot() {
+		switch e.Name {
+		case "device.state_mismatch":
+			mismatches = append(mismatches, e)
+		case "system.reconciled":
+			reconciled = append(reconciled, e)
+		}
+	}
+	if len(mismatches) != 2 {
+		t.Fatalf("expected 2 device.state_mismatch, got %d", len(mismatches))
+	}
+	if f := mismatches[0].Fields; f["node_id"] != "puzzle_presses" || f["field"] != "open" || f["expected"] != true || f["actual"] != false {
+		t.Errorf("unexpected device.state_mismatch fields: %v", f)
+	}
+	if _, ok := mismatches[1].Fields["actual"]; ok {
+		t.Error("expected no actual value for a device that did not answer")
+	}
+	if len(reconciled) != 1 || reconciled[0].Fields["mismatches"] != 2 || reconciled[0].Fields["corrected"] != 0 {
+		t.Errorf("unexpected system.reconciled: %v", reconciled)
+	}
+}
+
+func TestReconcileCorrects(t *testing.T) {
+	exec := &replyingExecutor{}
+	rt := startGame(t, reconcileSceneGraph(), "scene_counter", func(rt *Runtime) {
+		rt.SetActionExecutor(exec)
+	})
+	press(rt, "button")
+	exec.commands = nil
+	q := &fakeQuerier{reported: map[string]map[string]interface{}{
+		"crypt_door": {"open": false},
+		"lights":     {"level": float64(50)},
+	}}
+
+	report := rt.Reconcile(q, time.Second, true)
+	if report == nil || len(report.Mismatches) != 3 {
+		t.Fatalf("expected 3 mismatches, got %+v", report)
+	}
+	if !report.Mismatches[0].Corrected {
+		t.Error("expected the door corrected")
+	}
+	if report.Mismatches[1].Corrected || report.Mismatches[2].Corrected {
+		t.Error("expected mismatches without a correct command left alone")
+	}
+	if len(exec.commands) != 1 || exec.commands[0] != "crypt_door:unlock" {
+		t.Errorf("expected one unlock command, got %v", exec.commands)
+	}
+}
+
+func TestReconcileWithoutExpectations(t *testing.T) {
+	events.Clear()
+	defer events.Clear()
+	rt := NewRuntime(counterSceneGraph(1))
+	if err := rt.StartGame("scene_counter"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer func() { _ = rt.StopGame() }()
+	press(rt, "button")
+
+	q := &fakeQuerier{}
+	if report := rt.Reconcile(q, time.Second, false); report != nil {
+		t.Errorf("expected nothing to reconcile, got %+v", report)
+	}
+	if len(q.asked) != 0 {
+		t.Errorf("expected no devices queried, got %v", q.asked)
+	}
+}
+
+func TestValidateExpectations(t *testing.T) {
+	tests := []struct {
+		name   string
+		expect interface{}
+		ok     bool
+	}{
+		{"valid", []interface{}{map[string]interface{}{"device_id": "door", "field": "open", "value": true}}, true},
+		{"not a list", "door.open", false},
+		{"missing field", []interface{}{map[string]interface{}{"device_id": "door", "value": true}}, false},
+		{"missing value", []interface{}{map[string]interface{}{"device_id": "door", "field": "open"}}, false},
+		{"correct without signal", []interface{}{map[string]interface{}{"device_id": "door", "field": "open", "value": true,
+			"correct": map[string]interface{}{"payload": 1}}}, false},
+	}
+	for _, tt := range tests {
+		node := Node{ID: "n", Type: "action", Config: map[string]interface{}{"expect": tt.expect}}
+		if err := validateExpectations(node); (err == nil) != tt.ok {
+			t.Errorf("%s: unexpected result %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package orchestrator
+
+import "log"
+
+// replayAllowed reports whether an action config may run again after a reset (default true).
+// Nodes with physical side effects (doors, drops) set replay_on_reset: false
+// so a reset path completes them without repeating the action.
+func replayAllowed(config map[string]interface{}) bool {
+	allowed, ok := config["replay_on_reset"].(bool)
+	return !ok || allowed
+}
+
+// runNodeAction executes an action node's action unless replay protection
+// applies. key identifies the node within the session. Returns true if skipped.
+func (r *Runtime) runNodeAction(key, nodeID string, config map[string]interface{}) (bool, error) {
+	if !replayAllowed(config) {
+		if r.executedActions[key] {
+			log.Printf("[reset] not replaying action %s (replay_on_reset: false)", key)
+			return true, nil
+		}
+		r.executedActions[key] = true
+	}
+	return false, r.runAction(nodeID, config)
+}
+
+// subgraphActionFunc runs subgraph actions for the node that launched the
+// subgraph, keyed so protection is tracked per parent node.
+func (r *Runtime) subgraphActionFunc(parentID string) ActionFunc {
+	return func(nodeID string, config map[string]interface{}) error {
+		_, err := r.runNodeAction(parentID+"/"+nodeID, nodeID, config)
+		return err
+	}
+}
@@ -0,0 +1,98 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// replaySceneGraph opens a door (protected) and plays a sound (replayable), then
+// waits on a puzzle whose subgraph also drives a protected device.
+func replaySceneGraph() *SceneGraph {
+	command := func(deviceID string, replay bool) map[string]interface{} {
+		return map[string]interface{}{
+			"action":          "device.command",
+			"replay_on_reset": replay,
+			"params":          map[string]interface{}{"device_id": deviceID, "signal": "on"},
+		}
+	}
+	return sceneGraph(Scene{
+		ID:    "scene_replay",
+		Entry: "open_door",
+		Nodes: []Node{
+			{ID: "open_door", Type: "action", Config: command("door", false)},
+			{ID: "play_sound", Type: "action", Config: command("speaker", true)},
+			{ID: "puzzle_a", Type: "puzzle", Config: map[string]interface{}{"subgraph": "sub_a"}},
+		},
+		Edges: []Edge{
+			{From: "open_door", To: "play_sound"},
+			{From: "play_sound", To: "puzzle_a"},
+		},
+		Subgraphs: []Subgraph{
+			{
+				ID:    "sub_a",
+				Entry: "drop_key",
+				Nodes: []Node{
+					{ID: "drop_key", Type: "action", Config: command("key_drop", false)},
+					{ID: "a_wait", Type: "decision"},
+				},
+				Edges: []Edge{{From: "drop_key", To: "a_wait"}},
+			},
+		},
+	})
+}
+
+func TestReplayOnResetFalse(t *testing.T) {
+	events.Clear()
+	exec := &replyingExecutor{}
+	rt := NewRuntime(replaySceneGraph())
+	rt.SetActionExecutor(exec)
+
+	if err := rt.StartGame("scene_replay"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	if err := rt.ResetToNode("open_door"); err != nil {
+		t.Fatalf("ResetToNode failed: %v", err)
+	}
+
+	exec.mu.Lock()
+	got := exec.commands
+	exec.mu.Unlock()
+	want := []string{"door:on", "speaker:on", "key_drop:on", "speaker:on"}
+	if len(got) != len(want) {
+		t.Fatalf("expected commands %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("command[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+
+	// Flow still continues through the skipped node
+	if rt.GetNodeState("puzzle_a") != NodeStateActive {
+		t.Errorf("expected puzzle_a active after reset, got %s", rt.GetNodeState("puzzle_a"))
+	}
+	skipped := 0
+	for _, e := range events.Snapshot() {
+		if e.Name == "node.completed" && e.Fields["node_id"] == "open_door" && e.Fields["replay_skipped"] == true {
+			skipped++
+		}
+	}
+	if skipped != 1 {
+		t.Errorf("expected one replay_skipped completion, got %d", skipped)
+	}
+
+	// A new game runs protected actions again
+	if err := rt.StopGame(); err != nil {
+		t.Fatalf("failed to stop game: %v", err)
+	}
+	if err := rt.StartGame("scene_replay"); err != nil {
+		t.Fatalf("failed to restart game: %v", err)
+	}
+	exec.mu.Lock()
+	n := len(exec.commands)
+	exec.mu.Unlock()
+	if n != 7 {
+		t.Errorf("expected door, speaker and key drop to run in a new game, got %d commands", n)
+	}
+}
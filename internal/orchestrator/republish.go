@@ -0,0 +1,149 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"log"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// RepublishPublisher publishes raw MQTT messages (implemented by *mqtt.Client).
+type RepublishPublisher interface {
+	IsConnected() bool
+	Publish(topic string, payload []byte) error
+	PublishRetained(topic string, payload []byte) error
+}
+
+// Republisher publishes engine events matching bridges.yaml republish onto
+// an MQTT topic tree for show-control systems. Events are published off the
+// emit path, in emission order. Events emitted while the broker is down are
+// not published later; failures are logged, not emitted, so they cannot feed
+// back into the stream.
+type Republisher struct {
+	root      string
+	patterns  []string
+	retain    bool
+	publisher RepublishPublisher
+
+	queue   chan events.Event
+	remove  func()
+	dropped atomic.Bool
+	failed  atomic.Bool
+	stopCh  chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewRepublisher creates a republisher publishing under root (the configured
+// topic, or the given default when none is set).
+func NewRepublisher(cfg *config.RepublishConfig, defaultRoot string, publisher RepublishPublisher) *Republisher {
+	root := cfg.Topic
+	if root == "" {
+		root = defaultRoot
+	}
+	patterns := make([]string, 0, len(cfg.EventPatterns()))
+	for _, p := range cfg.EventPatterns() {
+		// Exact names may be legacy names; globs are matched as written
+		if !strings.ContainsAny(p, "*?[") {
+			p = events.Translate(p, "bridges.yaml")
+		}
+		patterns = append(patterns, p)
+	}
+	return &Republisher{
+		root:      root,
+		patterns:  patterns,
+		retain:    cfg.Retain,
+		publisher: publisher,
+		queue:     make(chan events.Event, bridgeQueueSize),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start subscribes to emitted events and publishes them in the background.
+func (p *Republisher) Start() {
+	p.remove = events.AddListener(func(e events.Event) {
+		if !p.Matches(e.Name) {
+			return
+		}
+		select {
+		case p.queue <- e:
+		default:
+			// Never block the emitter; note the first overflow only
+			if p.dropped.CompareAndSwap(false, true) {
+				log.Printf("[republish] queue full, dropping events")
+			}
+		}
+	})
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		for {
+			select {
+			case <-p.stopCh:
+				return
+			case e := <-p.queue:
+				p.Publish(e)
+			}
+		}
+	}()
+}
+
+// Stop unsubscribes and waits for the worker to exit.
+func (p *Republisher) Stop() {
+	if p.remove != nil {
+		p.remove()
+	}
+	close(p.stopCh)
+	p.wg.Wait()
+}
+
+// Matches reports whether events with the given name are republished.
+func (p *Republisher) Matches(name string) bool {
+	for _, pattern := range p.patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Topic returns the topic an event is republished on.
+func (p *Republisher) Topic(name string) string {
+	return p.root + "/" + strings.ReplaceAll(name, ".", "/")
+}
+
+// Publish publishes an event as JSON on its topic. Failures are logged once
+// until a publish succeeds again.
+func (p *Republisher) Publish(e events.Event) {
+	if p.publisher == nil || !p.publisher.IsConnected() {
+		p.fail("MQTT client not connected")
+		return
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("[republish] failed to marshal %s: %v", e.Name, err)
+		return
+	}
+	topic := p.Topic(e.Name)
+	if p.retain {
+		err = p.publisher.PublishRetained(topic, payload)
+	} else {
+		err = p.publisher.Publish(topic, payload)
+	}
+	if err != nil {
+		p.fail("publish to " + topic + " failed: " + err.Error())
+		return
+	}
+	p.failed.Store(false)
+}
+
+func (p *Republisher) fail(msg string) {
+	if p.failed.CompareAndSwap(false, true) {
+		log.Printf("[republish] %s; events are not republished until the broker is back", msg)
+	}
+}
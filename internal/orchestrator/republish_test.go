@@ -0,0 +1,84 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// retainingMQTTClient records retained publishes apart from the others.
+type retainingMQTTClient struct {
+	*MockMQTTClient
+	retained []string
+}
+
+func (m *retainingMQTTClient) PublishRetained(topic string, payload []byte) error {
+	m.retained = append(m.retained, topic)
+	return nil
+}
+
+func TestRepublisher(t *testing.T) {
+	publisher := &retainingMQTTClient{MockMQTTClient: NewMockMQTTClient()}
+	p := NewRepublisher(&config.RepublishConfig{}, "sentient/events", publisher)
+
+	for name, want := range map[string]bool{
+		"scene.started": true, "puzzle.solved": true, "timer.sync": true,
+		"device.input": false, "system.startup": false,
+	} {
+		if p.Matches(name) != want {
+			t.Errorf("%s: expected republished %v", name, want)
+		}
+	}
+
+	p.Publish(events.Event{Seq: 7, Name: "puzzle.solved", Fields: map[string]interface{}{"node_id": "puzzle_scarab"}})
+	published := publisher.GetPublished()
+	if len(published) != 1 || published[0].Topic != "sentient/events/puzzle/solved" {
+		t.Fatalf("expected the event on sentient/events/puzzle/solved, got %+v", published)
+	}
+	var e events.Event
+	if err := json.Unmarshal(published[0].Payload, &e); err != nil || e.Seq != 7 || e.Fields["node_id"] != "puzzle_scarab" {
+		t.Errorf("expected the event as JSON, got %s", published[0].Payload)
+	}
+
+	// A broker that is down drops events rather than queueing them
+	publisher.SetConnected(false)
+	p.Publish(events.Event{Name: "puzzle.solved"})
+	if len(publisher.GetPublished()) != 1 {
+		t.Error("expected nothing published while disconnected")
+	}
+
+	retained := NewRepublisher(&config.RepublishConfig{Topic: "show/crypt", Events: []string{"scene.started"}, Retain: true},
+		"sentient/events", publisher)
+	publisher.SetConnected(true)
+	retained.Publish(events.Event{Name: "scene.started"})
+	if len(publisher.retained) != 1 || publisher.retained[0] != "show/crypt/scene/started" {
+		t.Errorf("expected a retained publish under the configured topic, got %v", publisher.retained)
+	}
+	if retained.Matches("scene.completed") {
+		t.Error("expected only the listed events republished")
+	}
+}
+
+func TestRepublisherListensToEmitter(t *testing.T) {
+	publisher := &retainingMQTTClient{MockMQTTClient: NewMockMQTTClient()}
+	p := NewRepublisher(&config.RepublishConfig{Events: []string{"scene.*"}}, "sentient/events", publisher)
+	p.Start()
+	defer p.Stop()
+
+	events.Emit("info", "puzzle.solved", "", nil)
+	events.Emit("info", "scene.completed", "", map[string]interface{}{"scene_id": "intro"})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(publisher.GetPublished()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the republished event")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if published := publisher.GetPublished(); len(published) != 1 || published[0].Topic != "sentient/events/scene/completed" {
+		t.Errorf("expected only scene.completed republished, got %+v", published)
+	}
+}
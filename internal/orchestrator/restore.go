@@ -2,49 +2,127 @@ package orchestrator
 
 import (
 	"log"
+	"time"
 
 	"github.com/AaronLay10/SentientEngine/internal/events"
-	"github.com/AaronLay10/SentientEngine/internal/storage/postgres"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
 )
 
-// DefaultRestoreLimit is the default number of events to load for restore.
+// DefaultRestoreLimit is the default number of events loaded per query during restore.
 const DefaultRestoreLimit = 1000
 
 // RestoredState represents the minimal state reconstructed from events.
 type RestoredState struct {
 	SessionActive bool
 	SceneID       string
-	PuzzleStates  map[string]PuzzleResolution // node_id -> resolution
+	SessionID     string // "" for events recorded before session IDs
+	StartedAt     time.Time
+	PuzzleStates  map[string]PuzzleResolution  // node_id -> resolution
+	RandomChoices map[string]string            // random node_id -> chosen node_id
+	Variables     map[string]interface{}       // session variables
+	Subgraphs     map[string]*SubgraphProgress // unresolved puzzle node_id -> subgraph progress
+	HintsGiven    map[string]int               // puzzle node_id -> hints given
+	Paused        bool                         // game paused with /game/pause
+	PausedAt      time.Time
+
+	SceneCompleted bool // the active scene reached its terminal
+
+	Report *SessionReport // the session's summary so far (see score.go)
+}
+
+// eventQuerier is the part of the event store restore reads from.
+type eventQuerier interface {
+	Query(filter storage.EventFilter) ([]storage.EventRow, error)
+	QueryByNames(names []string, limit int) ([]storage.EventRow, error)
 }
 
-// RestoreFromEvents loads events from Postgres and reconstructs minimal runtime state.
-// Returns nil if no relevant state was found or if client is nil.
+// RestoreFromEvents loads the latest open session from the event store and reconstructs
+// minimal runtime state. limit is the page size; every event since the session's
+// scene.started is replayed however long the session ran.
+// Returns nil if no session is open or if store is nil.
 // Session is considered active if there is a scene.started without a later scene.reset.
-func RestoreFromEvents(client *postgres.Client, roomID string, limit int) (*RestoredState, int, error) {
-	if client == nil {
+func RestoreFromEvents(store storage.Store, roomID string, limit int) (*RestoredState, int, error) {
+	if store == nil {
 		return nil, 0, nil
 	}
+	return restoreSession(store, limit)
+}
 
+// restoreSession anchors on the most recent scene.started or scene.reset and
+// folds every event from an open session's start.
+func restoreSession(q eventQuerier, limit int) (*RestoredState, int, error) {
 	if limit <= 0 {
 		limit = DefaultRestoreLimit
 	}
 
-	rows, err := client.Query(limit)
+	anchor, err := q.QueryByNames([]string{"scene.started", "scene.reset"}, 1)
 	if err != nil {
 		return nil, 0, err
 	}
-
-	if len(rows) == 0 {
+	if len(anchor) == 0 || anchor[0].Event != "scene.started" {
+		log.Printf("[restore] no open session")
 		return nil, 0, nil
 	}
 
+	rows, err := sessionEvents(q, anchor[0], limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	state := stateFromEvents(rows)
+	if state.SessionID != "" {
+		state.Report, _ = buildSessionReport(state.SessionID, rows)
+	}
+
+	log.Printf("[restore] processed %d events: session_active=%v scene_id=%q puzzles=%d",
+		len(rows), state.SessionActive, state.SceneID, len(state.PuzzleStates))
+
+	// Only return state if session is active with a valid scene
+	if !state.SessionActive || state.SceneID == "" {
+		return nil, len(rows), nil
+	}
+
+	return state, len(rows), nil
+}
+
+// sessionEvents pages through the events since start, oldest first.
+func sessionEvents(q eventQuerier, start storage.EventRow, limit int) ([]storage.EventRow, error) {
+	filter := storage.EventFilter{Limit: limit, Since: start.Timestamp}
+	var rows []storage.EventRow
+	for {
+		page, err := q.Query(filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(page) == 0 {
+			break
+		}
+		rows = append(rows, page...)
+		filter.Before = page[len(page)-1].EventID
+	}
+
 	// Reverse to chronological order (Query returns DESC by timestamp)
 	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
 		rows[i], rows[j] = rows[j], rows[i]
 	}
 
+	// Events sharing the anchor's timestamp may precede it
+	for i, row := range rows {
+		if row.EventID == start.EventID {
+			return rows[i:], nil
+		}
+	}
+	return rows, nil
+}
+
+// stateFromEvents folds chronologically ordered events into restored state.
+func stateFromEvents(rows []storage.EventRow) *RestoredState {
 	state := &RestoredState{
-		PuzzleStates: make(map[string]PuzzleResolution),
+		PuzzleStates:  make(map[string]PuzzleResolution),
+		RandomChoices: make(map[string]string),
+		Variables:     make(map[string]interface{}),
+		Subgraphs:     make(map[string]*SubgraphProgress),
+		HintsGiven:    make(map[string]int),
 	}
 
 	// Process events in chronological order to determine final state
@@ -56,20 +134,166 @@ func RestoreFromEvents(client *postgres.Client, roomID string, limit int) (*Rest
 			if sceneID, ok := row.Fields["scene_id"].(string); ok {
 				state.SceneID = sceneID
 			}
+			state.SessionID = ""
+			if row.SessionID != nil {
+				state.SessionID = *row.SessionID
+			}
+			state.StartedAt = row.Timestamp
 			// Clear puzzle states when a new scene starts
 			state.PuzzleStates = make(map[string]PuzzleResolution)
+			state.RandomChoices = make(map[string]string)
+			state.Variables = make(map[string]interface{})
+			state.Subgraphs = make(map[string]*SubgraphProgress)
+			state.HintsGiven = make(map[string]int)
+			state.Paused = false
+			state.SceneCompleted = false
+
+		case "scene.advanced":
+			// Chained to another scene: the session and its variables carry on
+			if sceneID, ok := row.Fields["scene_id"].(string); ok {
+				state.SceneID = sceneID
+			}
+			state.PuzzleStates = make(map[string]PuzzleResolution)
+			state.RandomChoices = make(map[string]string)
+			state.Subgraphs = make(map[string]*SubgraphProgress)
+			state.HintsGiven = make(map[string]int)
+			state.SceneCompleted = false
+
+		case "scene.completed":
+			state.SceneCompleted = true
 
 		case "scene.reset":
 			// Scene reset - session becomes inactive
 			state.SessionActive = false
 			state.SceneID = ""
+			state.SessionID = ""
 			state.PuzzleStates = make(map[string]PuzzleResolution)
+			state.RandomChoices = make(map[string]string)
+			state.Variables = make(map[string]interface{})
+			state.Subgraphs = make(map[string]*SubgraphProgress)
+			state.HintsGiven = make(map[string]int)
+			state.Paused = false
+			state.SceneCompleted = false
+
+		case "operator.pause":
+			// Game paused (clock-only pauses carry timer_id instead)
+			if scope, _ := row.Fields["scope"].(string); scope == PauseScope {
+				state.Paused = true
+				state.PausedAt = row.Timestamp
+			}
+
+		case "operator.resume":
+			if scope, _ := row.Fields["scope"].(string); scope == PauseScope {
+				state.Paused = false
+			}
+
+		case "variable.set":
+			// Session variable assignment
+			if name, ok := row.Fields["name"].(string); ok && name != "" {
+				state.Variables[name] = row.Fields["value"]
+			}
+
+		case "puzzle.progress":
+			// Counter value, as seen by vars.counter.<node_id>
+			nodeID, _ := row.Fields["node_id"].(string)
+			if count, ok := toFloat(row.Fields["count"]); ok && nodeID != "" {
+				counters, ok := state.Variables[counterVar].(map[string]interface{})
+				if !ok {
+					counters = make(map[string]interface{})
+					state.Variables[counterVar] = counters
+				}
+				counters[nodeID] = count
+			}
+			// Wrong keypad codes so far
+			if progress := state.Subgraphs[extractPuzzleID(row.Fields)]; progress != nil && nodeID != "" {
+				if attempts, ok := toFloat(row.Fields["attempts"]); ok {
+					progress.Attempts[nodeID] = int(attempts)
+				}
+			}
+
+		case "puzzle.activated":
+			// Puzzle subgraph starts from its entry node
+			nodeID, _ := row.Fields["node_id"].(string)
+			if subgraphID, ok := row.Fields["subgraph_id"].(string); ok && nodeID != "" {
+				state.Subgraphs[nodeID] = newSubgraphProgress(subgraphID)
+			}
+
+		case "puzzle.hint":
+			// Hints are given in order; an earlier one may be repeated
+			nodeID := extractNodeID(row.Fields)
+			if hint, ok := toFloat(row.Fields["hint"]); ok && nodeID != "" && int(hint)+1 > state.HintsGiven[nodeID] {
+				state.HintsGiven[nodeID] = int(hint) + 1
+			}
+
+		case "puzzle.step":
+			// Subgraph node completed, activating next
+			if progress := state.Subgraphs[extractPuzzleID(row.Fields)]; progress != nil {
+				if nodeID, ok := row.Fields["node_id"].(string); ok && nodeID != "" {
+					progress.Completed[nodeID] = true
+				}
+				next, _ := row.Fields["next"].(string)
+				progress.Next = append(progress.Next, next)
+			}
+
+		case "operator.undo":
+			// Undo carries the complete state it restored
+			if puzzles, ok := row.Fields["puzzles"].(map[string]interface{}); ok {
+				state.PuzzleStates = make(map[string]PuzzleResolution)
+				for id, res := range puzzles {
+					if s, ok := res.(string); ok {
+						state.PuzzleStates[id] = PuzzleResolution(s)
+					}
+				}
+			}
+			if choices, ok := row.Fields["random_choices"].(map[string]interface{}); ok {
+				state.RandomChoices = make(map[string]string)
+				for id, choice := range choices {
+					if s, ok := choice.(string); ok {
+						state.RandomChoices[id] = s
+					}
+				}
+			}
+			if vars, ok := row.Fields["variables"].(map[string]interface{}); ok {
+				state.Variables = copyVars(vars)
+			}
+
+		case "device.input":
+			// Latest input per device, as seen by vars.input.<logical_id>
+			if logicalID, ok := row.Fields["logical_id"].(string); ok && logicalID != "" {
+				inputs, ok := state.Variables[inputVar].(map[string]interface{})
+				if !ok {
+					inputs = make(map[string]interface{})
+					state.Variables[inputVar] = inputs
+				}
+				inputs[logicalID] = row.Fields["payload"]
+			}
+
+		case "node.completed":
+			// Random node branch selection
+			nodeID := extractNodeID(row.Fields)
+			if choice, ok := row.Fields["choice"].(string); ok && nodeID != "" {
+				state.RandomChoices[nodeID] = choice
+			}
+
+		case "node.reset":
+			// Reset random nodes draw again
+			nodeID := extractNodeID(row.Fields)
+			delete(state.RandomChoices, nodeID)
 
 		case "puzzle.solved":
 			// Puzzle was solved
 			nodeID := extractNodeID(row.Fields)
 			if nodeID != "" {
 				state.PuzzleStates[nodeID] = PuzzleSolved
+				delete(state.Subgraphs, nodeID)
+			}
+
+		case "puzzle.failed":
+			// Puzzle timed out or ran out of attempts
+			nodeID := extractNodeID(row.Fields)
+			if nodeID != "" {
+				state.PuzzleStates[nodeID] = PuzzleFailed
+				delete(state.Subgraphs, nodeID)
 			}
 
 		case "puzzle.overridden":
@@ -77,6 +301,7 @@ func RestoreFromEvents(client *postgres.Client, roomID string, limit int) (*Rest
 			nodeID := extractNodeID(row.Fields)
 			if nodeID != "" {
 				state.PuzzleStates[nodeID] = PuzzleOverridden
+				delete(state.Subgraphs, nodeID)
 			}
 
 		case "operator.override":
@@ -84,6 +309,7 @@ func RestoreFromEvents(client *postgres.Client, roomID string, limit int) (*Rest
 			nodeID := extractNodeID(row.Fields)
 			if nodeID != "" {
 				state.PuzzleStates[nodeID] = PuzzleOverridden
+				delete(state.Subgraphs, nodeID)
 			}
 
 		case "puzzle.reset":
@@ -91,6 +317,7 @@ func RestoreFromEvents(client *postgres.Client, roomID string, limit int) (*Rest
 			nodeID := extractNodeID(row.Fields)
 			if nodeID != "" {
 				state.PuzzleStates[nodeID] = PuzzleUnresolved
+				delete(state.Subgraphs, nodeID)
 			}
 
 		case "operator.reset":
@@ -98,19 +325,12 @@ func RestoreFromEvents(client *postgres.Client, roomID string, limit int) (*Rest
 			nodeID := extractNodeID(row.Fields)
 			if nodeID != "" {
 				state.PuzzleStates[nodeID] = PuzzleUnresolved
+				delete(state.Subgraphs, nodeID)
 			}
 		}
 	}
 
-	log.Printf("[restore] processed %d events: session_active=%v scene_id=%q puzzles=%d",
-		len(rows), state.SessionActive, state.SceneID, len(state.PuzzleStates))
-
-	// Only return state if session is active with a valid scene
-	if !state.SessionActive || state.SceneID == "" {
-		return nil, len(rows), nil
-	}
-
-	return state, len(rows), nil
+	return state
 }
 
 // extractNodeID extracts node_id from event fields, trying multiple field names.
@@ -125,9 +345,18 @@ func extractNodeID(fields map[string]interface{}) string {
 	return ""
 }
 
+// extractPuzzleID extracts the owning puzzle's node ID from subgraph events.
+func extractPuzzleID(fields map[string]interface{}) string {
+	puzzleID, _ := fields["puzzle_id"].(string)
+	return puzzleID
+}
+
 // ApplyRestoredState applies restored state to the runtime.
 // This does NOT re-emit events or trigger actions.
 func (r *Runtime) ApplyRestoredState(state *RestoredState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if state == nil || !state.SessionActive || state.SceneID == "" {
 		return nil
 	}
@@ -143,6 +372,12 @@ func (r *Runtime) ApplyRestoredState(state *RestoredState) error {
 		log.Printf("[restore] scene not found: %s", state.SceneID)
 		return nil
 	}
+	r.sceneStartedAt = state.StartedAt
+
+	// Events after the restart belong to the interrupted session
+	if state.SessionID != "" {
+		events.ResumeSession(state.SessionID)
+	}
 
 	// Initialize node states for the active scene
 	for _, node := range r.activeScene.Nodes {
@@ -171,16 +406,74 @@ func (r *Runtime) ApplyRestoredState(state *RestoredState) error {
 					ns.State = NodeStateCompleted
 				case PuzzleOverridden:
 					ns.State = NodeStateOverridden
+				case PuzzleFailed:
+					ns.State = NodeStateFailed
 				}
 			}
 			log.Printf("[restore] applied puzzle state: %s -> %s", nodeID, resolution)
 		}
 	}
 
-	log.Printf("[restore] restored scene %s with %d puzzle states", state.SceneID, len(state.PuzzleStates))
+	for nodeID, n := range state.HintsGiven {
+		r.hintsGiven[nodeID] = n
+	}
+	r.report = state.Report
+
+	// Recorded random choices are replayed when those nodes activate again
+	for nodeID, choice := range state.RandomChoices {
+		r.randomChoices[nodeID] = choice
+	}
+	r.replaceVars(state.Variables)
+
+	// A paused game stays paused; its timers wait for /game/resume
+	if state.Paused {
+		r.paused = true
+		r.pausedAt = state.PausedAt
+	}
+
+	// In-progress puzzles resume their subgraphs where they were
+	for nodeID, progress := range state.Subgraphs {
+		if ps, ok := r.puzzleStates[nodeID]; ok && ps.Resolution == PuzzleUnresolved {
+			r.resumePuzzle(nodeID, progress)
+		}
+	}
+
+	// A completed scene whose transition had not run yet moves on again;
+	// otherwise the stall watchdog starts over
+	if state.SceneCompleted {
+		r.chainScene()
+	} else {
+		r.noteProgress()
+	}
+
+	log.Printf("[restore] restored scene %s with %d puzzle states (paused=%v)", state.SceneID, len(state.PuzzleStates), state.Paused)
 	return nil
 }
 
+// resumePuzzle recreates an unresolved puzzle's subgraph from restored progress.
+// The puzzle's timeout starts over.
+func (r *Runtime) resumePuzzle(nodeID string, progress *SubgraphProgress) {
+	node := r.findNode(nodeID)
+	if node == nil {
+		return
+	}
+	subgraph := r.findSubgraph(progress.SubgraphID)
+	if subgraph == nil {
+		log.Printf("[restore] subgraph not found: %s", progress.SubgraphID)
+		return
+	}
+
+	pr := r.newPuzzleRuntime(node, subgraph)
+	r.puzzleRuntimes[nodeID] = pr
+	if ns, ok := r.nodeStates[nodeID]; ok {
+		ns.State = NodeStateActive
+		ns.ActivatedAt = r.now()
+	}
+	r.startPuzzleLimits(node)
+	pr.Resume(progress)
+	log.Printf("[restore] resumed puzzle %s after %d steps", nodeID, len(progress.Next))
+}
+
 // EmitStartupRestore emits the system.startup_restore event.
 func EmitStartupRestore(restored int, roomID string) {
 	events.Emit("info", "system.startup_restore", "", map[string]interface{}{
@@ -5,7 +5,7 @@ import (
 	"time"
 
 	"github.com/AaronLay10/SentientEngine/internal/events"
-	"github.com/AaronLay10/SentientEngine/internal/storage/postgres"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
 )
 
 func TestRestoreFromEventsNilClient(t *testing.T) {
@@ -22,6 +22,99 @@ func TestRestoreFromEventsNilClient(t *testing.T) {
 	}
 }
 
+// fakeEventStore serves stored events the way the Postgres client does:
+// newest first, paged by limit and cursor.
+type fakeEventStore struct {
+	rows    []storage.EventRow // chronological
+	queries int
+}
+
+func (f *fakeEventStore) Query(filter storage.EventFilter) ([]storage.EventRow, error) {
+	f.queries++
+	var out []storage.EventRow
+	for i := len(f.rows) - 1; i >= 0 && len(out) < filter.Limit; i-- {
+		row := f.rows[i]
+		if row.Timestamp.Before(filter.Since) || (filter.Before > 0 && row.EventID >= filter.Before) {
+			continue
+		}
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+func (f *fakeEventStore) QueryByNames(names []string, limit int) ([]storage.EventRow, error) {
+	var out []storage.EventRow
+	for i := len(f.rows) - 1; i >= 0 && len(out) < limit; i-- {
+		for _, name := range names {
+			if f.rows[i].Event == name {
+				out = append(out, f.rows[i])
+			}
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeEventStore) add(ts time.Time, name string, fields map[string]interface{}) {
+	f.rows = append(f.rows, storage.EventRow{EventID: int64(len(f.rows) + 1), Timestamp: ts, Event: name, Fields: fields})
+}
+
+func TestRestoreSessionLongerThanLimit(t *testing.T) {
+	base := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	store := &fakeEventStore{}
+
+	// An earlier, finished session
+	store.add(base, "scene.started", map[string]interface{}{"scene_id": "scene_intro"})
+	store.add(base.Add(time.Minute), "puzzle.solved", map[string]interface{}{"puzzle_id": "puzzle_tiles"})
+	store.add(base.Add(2*time.Minute), "scene.reset", map[string]interface{}{"scene_id": "scene_intro"})
+
+	// The open session: solved early, then far more events than one page
+	start := base.Add(time.Hour)
+	store.add(start, "scene.started", map[string]interface{}{"scene_id": "scene_intro"})
+	store.add(start, "puzzle.solved", map[string]interface{}{"puzzle_id": "puzzle_scarab"})
+	for i := 0; i < 250; i++ {
+		store.add(start.Add(time.Duration(i+1)*time.Second), "device.input", map[string]interface{}{"logical_id": "crypt_door"})
+	}
+
+	state, count, err := restoreSession(store, 100)
+	if err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if state == nil || state.SceneID != "scene_intro" {
+		t.Fatalf("expected the open session restored, got %+v", state)
+	}
+	if count != 252 {
+		t.Errorf("expected the 252 events of the open session, got %d", count)
+	}
+	if state.PuzzleStates["puzzle_scarab"] != PuzzleSolved {
+		t.Error("expected puzzle_scarab solved from the start of the session")
+	}
+	if _, ok := state.PuzzleStates["puzzle_tiles"]; ok {
+		t.Error("expected the finished session ignored")
+	}
+	if store.queries != 4 {
+		t.Errorf("expected 3 pages and an empty one, got %d queries", store.queries)
+	}
+}
+
+func TestRestoreSessionClosed(t *testing.T) {
+	base := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	store := &fakeEventStore{}
+	store.add(base, "scene.started", map[string]interface{}{"scene_id": "scene_intro"})
+	store.add(base.Add(time.Minute), "scene.reset", map[string]interface{}{"scene_id": "scene_intro"})
+	store.add(base.Add(2*time.Minute), "device.input", map[string]interface{}{"logical_id": "crypt_door"})
+
+	state, _, err := restoreSession(store, 100)
+	if err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if state != nil {
+		t.Errorf("expected no state after scene.reset, got %+v", state)
+	}
+	if store.queries != 0 {
+		t.Errorf("expected no event pages loaded for a closed session, got %d", store.queries)
+	}
+}
+
 func TestRestoredStateBasic(t *testing.T) {
 	// Test RestoredState initialization
 	state := &RestoredState{
@@ -231,7 +324,7 @@ func TestRestoreOverrideRestart(t *testing.T) {
 // TestProcessEventsToState simulates processing DB events to build state.
 func TestProcessEventsToState(t *testing.T) {
 	// Simulate events as they would appear in the database
-	mockEvents := []postgres.EventRow{
+	mockEvents := []storage.EventRow{
 		{
 			EventID:   1,
 			Timestamp: time.Now().Add(-10 * time.Minute),
@@ -315,7 +408,7 @@ func TestProcessEventsToState(t *testing.T) {
 
 func TestRestoreSceneResetClearsState(t *testing.T) {
 	// Test that scene.reset clears puzzle states and session
-	mockEvents := []postgres.EventRow{
+	mockEvents := []storage.EventRow{
 		{
 			EventID:   1,
 			Timestamp: time.Now().Add(-10 * time.Minute),
@@ -373,7 +466,7 @@ func TestRestoreSceneResetClearsState(t *testing.T) {
 
 func TestRestorePuzzleResetClearsPuzzle(t *testing.T) {
 	// Test that puzzle.reset clears individual puzzle state
-	mockEvents := []postgres.EventRow{
+	mockEvents := []storage.EventRow{
 		{
 			EventID:   1,
 			Timestamp: time.Now().Add(-10 * time.Minute),
@@ -429,7 +522,7 @@ func TestRestorePuzzleResetClearsPuzzle(t *testing.T) {
 
 func TestRestoreNewSceneStartClearsPuzzles(t *testing.T) {
 	// Test that a new scene.started clears puzzle states from previous scene
-	mockEvents := []postgres.EventRow{
+	mockEvents := []storage.EventRow{
 		{
 			EventID:   1,
 			Timestamp: time.Now().Add(-10 * time.Minute),
@@ -697,3 +790,187 @@ func TestSessionLifecycle(t *testing.T) {
 		t.Error("expected scene.reset after StopGame")
 	}
 }
+
+// TestSessionIDStamping verifies that every event of a game carries the
+// session ID and that each game gets a new one.
+func TestSessionIDStamping(t *testing.T) {
+	sg, err := LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+
+	events.Clear()
+	rt := NewRuntime(sg)
+	if err := rt.StartGame("scene_intro"); err != nil {
+		t.Fatalf("StartGame failed: %v", err)
+	}
+	first := events.CurrentSession()
+	if first == "" {
+		t.Fatal("expected a session ID after StartGame")
+	}
+	if err := rt.StopGame(); err != nil {
+		t.Fatalf("StopGame failed: %v", err)
+	}
+	if id := events.CurrentSession(); id != "" {
+		t.Errorf("expected no session after StopGame, got %s", id)
+	}
+
+	for _, e := range events.Snapshot() {
+		if e.SessionID != first {
+			t.Errorf("%s: expected session %s, got %q", e.Name, first, e.SessionID)
+		}
+	}
+
+	// Events between games carry no session
+	events.Emit("info", "system.startup", "", nil)
+	snapshot := events.Snapshot()
+	if last := snapshot[len(snapshot)-1]; last.SessionID != "" {
+		t.Errorf("expected no session between games, got %s", last.SessionID)
+	}
+
+	if err := rt.StartGame("scene_intro"); err != nil {
+		t.Fatalf("StartGame failed: %v", err)
+	}
+	defer rt.StopGame()
+	if second := events.CurrentSession(); second == "" || second == first {
+		t.Errorf("expected a new session ID, got %q after %q", second, first)
+	}
+}
+
+func TestRestoreResumesSession(t *testing.T) {
+	sid := "5f0c6f0e-8f7a-4c43-9d43-2b8f0e6d8a11"
+	rows := []storage.EventRow{
+		{EventID: 1, Event: "scene.started", Fields: map[string]interface{}{"scene_id": "scene_intro"}, SessionID: &sid},
+	}
+	state := stateFromEvents(rows)
+	if state.SessionID != sid {
+		t.Fatalf("expected restored session %s, got %q", sid, state.SessionID)
+	}
+
+	sg, err := LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	rt := NewRuntime(sg)
+	if err := rt.ApplyRestoredState(state); err != nil {
+		t.Fatalf("failed to apply restored state: %v", err)
+	}
+	if id := events.CurrentSession(); id != sid {
+		t.Errorf("expected events stamped with the restored session, got %q", id)
+	}
+	if err := rt.StopGame(); err != nil {
+		t.Fatalf("StopGame failed: %v", err)
+	}
+	if id := events.CurrentSession(); id != "" {
+		t.Errorf("expected the restored session to end, got %q", id)
+	}
+}
+
+// brazierSceneGraph is a puzzle solved by lighting two braziers, then pulling a lever.
+func brazierSceneGraph() *SceneGraph {
+	return sceneGraph(Scene{
+		ID:    "scene_braziers",
+		Entry: "puzzle_braziers",
+		Nodes: []Node{
+			{ID: "puzzle_braziers", Type: "puzzle", Config: map[string]interface{}{"subgraph": "braziers"}},
+		},
+		Subgraphs: []Subgraph{
+			{
+				ID:    "braziers",
+				Entry: "light_braziers",
+				Nodes: []Node{
+					{ID: "light_braziers", Type: "counter", Config: map[string]interface{}{
+						"match":     "event == 'device.input' && logical_id == 'brazier'",
+						"threshold": float64(2),
+					}},
+					{ID: "wait_lever", Type: "decision"},
+					{ID: "done", Type: "terminal"},
+				},
+				Edges: []Edge{
+					{From: "light_braziers", To: "wait_lever"},
+					{From: "wait_lever", To: "done", Condition: "event == 'device.input' && logical_id == 'lever'"},
+				},
+			},
+		},
+	})
+}
+
+// restartFromLog restores a fresh runtime from the events emitted so far.
+func restartFromLog(t *testing.T, sg *SceneGraph) *Runtime {
+	t.Helper()
+	var rows []storage.EventRow
+	for i, e := range events.Snapshot() {
+		rows = append(rows, storage.EventRow{EventID: int64(i + 1), Event: e.Name, Fields: e.Fields})
+	}
+	events.Clear()
+
+	rt := NewRuntime(sg)
+	if err := rt.ApplyRestoredState(stateFromEvents(rows)); err != nil {
+		t.Fatalf("failed to apply restored state: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = rt.StopGame()
+		events.Clear()
+	})
+	return rt
+}
+
+func TestRestoreResumesSubgraph(t *testing.T) {
+	events.Clear()
+	sg := brazierSceneGraph()
+	rt := NewRuntime(sg)
+	if err := rt.StartGame("scene_braziers"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	press(rt, "brazier")
+	press(rt, "brazier")
+
+	// Both braziers lit: the restored puzzle waits for the lever
+	rt = restartFromLog(t, sg)
+	if len(events.Snapshot()) != 0 {
+		t.Errorf("expected no events re-emitted on resume, got %v", events.Snapshot())
+	}
+	press(rt, "brazier")
+	if rt.GetPuzzleResolution("puzzle_braziers") != PuzzleUnresolved {
+		t.Fatal("expected the completed counter not to run again")
+	}
+	press(rt, "lever")
+	if rt.GetPuzzleResolution("puzzle_braziers") != PuzzleSolved {
+		t.Errorf("expected puzzle solved by the lever after restore, got %s", rt.GetPuzzleResolution("puzzle_braziers"))
+	}
+}
+
+func TestRestoreResumesCounter(t *testing.T) {
+	events.Clear()
+	sg := brazierSceneGraph()
+	rt := NewRuntime(sg)
+	if err := rt.StartGame("scene_braziers"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	press(rt, "brazier")
+
+	// One brazier lit: one more completes the counter
+	rt = restartFromLog(t, sg)
+	press(rt, "lever")
+	if rt.GetPuzzleResolution("puzzle_braziers") != PuzzleUnresolved {
+		t.Fatal("expected the lever ignored before the braziers are lit")
+	}
+	press(rt, "brazier")
+	press(rt, "lever")
+	if rt.GetPuzzleResolution("puzzle_braziers") != PuzzleSolved {
+		t.Errorf("expected puzzle solved after the second brazier, got %s", rt.GetPuzzleResolution("puzzle_braziers"))
+	}
+}
+
+func TestRestoreResolvedPuzzleNotResumed(t *testing.T) {
+	rows := []storage.EventRow{
+		{EventID: 1, Event: "scene.started", Fields: map[string]interface{}{"scene_id": "scene_braziers"}},
+		{EventID: 2, Event: "puzzle.activated", Fields: map[string]interface{}{"node_id": "puzzle_braziers", "subgraph_id": "braziers"}},
+		{EventID: 3, Event: "puzzle.step", Fields: map[string]interface{}{"puzzle_id": "puzzle_braziers", "subgraph_id": "braziers", "node_id": "light_braziers", "next": "wait_lever"}},
+		{EventID: 4, Event: "operator.override", Fields: map[string]interface{}{"node_id": "puzzle_braziers"}},
+	}
+	state := stateFromEvents(rows)
+	if _, ok := state.Subgraphs["puzzle_braziers"]; ok {
+		t.Error("expected no subgraph progress for an overridden puzzle")
+	}
+}
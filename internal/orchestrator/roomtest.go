@@ -0,0 +1,333 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// roomTestStart is the simulated wall clock time at which room tests start.
+var roomTestStart = time.Date(2026, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+// DefaultRoomGraph is the scene graph of a room directory, as deployed.
+const DefaultRoomGraph = "graphs/scene-graph.v1.json"
+
+// RoomTestResult is the outcome of one room test.
+type RoomTestResult struct {
+	Name     string   `json:"name"`
+	Path     string   `json:"path,omitempty"`
+	Failures []string `json:"failures,omitempty"`
+}
+
+// Passed reports whether every check of the test held.
+func (res *RoomTestResult) Passed() bool {
+	return len(res.Failures) == 0
+}
+
+func (res *RoomTestResult) failf(format string, args ...interface{}) {
+	res.Failures = append(res.Failures, fmt.Sprintf(format, args...))
+}
+
+// RunRoomTests plays every room test of a room directory against its scene
+// graph (graphPath, default DefaultRoomGraph in the room directory), with
+// game settings from its room.yaml. A test file that does not load is
+// reported as a failed test. An error is returned when the room itself does
+// not load.
+func RunRoomTests(roomDir, graphPath string) ([]*RoomTestResult, error) {
+	if graphPath == "" {
+		graphPath = filepath.Join(roomDir, DefaultRoomGraph)
+	}
+	sg, err := LoadSceneGraph(graphPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", graphPath, err)
+	}
+	roomPath := filepath.Join(roomDir, "room.yaml")
+	roomCfg, err := config.LoadRoomConfig(roomPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", roomPath, err)
+	}
+	paths, err := config.FindRoomTests(roomDir)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*RoomTestResult, 0, len(paths))
+	for _, path := range paths {
+		test, err := config.LoadRoomTest(path)
+		if err != nil {
+			res := &RoomTestResult{Name: filepath.Base(path), Path: path}
+			res.failf("load: %v", err)
+			results = append(results, res)
+			continue
+		}
+		res := RunRoomTest(sg, roomCfg, test)
+		res.Path = path
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// recordedCommand is a device command a room test's game sent.
+type recordedCommand struct {
+	device  string
+	signal  string
+	payload interface{}
+}
+
+// recordingExecutor records device commands instead of publishing them.
+type recordingExecutor struct {
+	commands []recordedCommand
+}
+
+func (e *recordingExecutor) ExecuteAction(nodeID string, config map[string]interface{}) error {
+	if config["action"] != "device.command" {
+		return nil
+	}
+	params, _ := config["params"].(map[string]interface{})
+	device, _ := params["device_id"].(string)
+	signal, _ := params["signal"].(string)
+	e.commands = append(e.commands, recordedCommand{device: device, signal: signal, payload: params["payload"]})
+	return nil
+}
+
+// roomTestRun is the state of a room test being played.
+type roomTestRun struct {
+	rt       *Runtime
+	executor *recordingExecutor
+	res      *RoomTestResult
+
+	mu     sync.Mutex
+	events []events.Event
+
+	// Positions in events and commands of the previous check
+	checkedEvents   int
+	checkedCommands int
+}
+
+// RunRoomTest plays a room test against a scene graph. Game settings
+// (duration, cooldowns, stall timeout) come from roomCfg, which may be nil.
+// The game runs in memory on simulated time with seeded random choices, and
+// device commands are recorded instead of published, so no broker or
+// database is needed and runs repeat.
+func RunRoomTest(sg *SceneGraph, roomCfg *config.RoomConfig, test *config.RoomTest) *RoomTestResult {
+	res := &RoomTestResult{Name: test.Name}
+
+	rt := NewRuntime(sg)
+	if roomCfg != nil {
+		rt.SetGameDuration(roomCfg.GameDuration(), roomCfg.MaxGameDuration())
+		rt.SetCascadeReset(roomCfg.Ops.CascadeReset)
+		rt.SetResetCooldown(roomCfg.ResetCooldown())
+		rt.SetStallTimeout(roomCfg.StallTimeout())
+		rt.SetPauseIgnoresInput(roomCfg.PauseIgnoresInput())
+	}
+	seed := test.Seed
+	if seed == 0 {
+		seed = 1
+	}
+	rt.rng = rand.New(rand.NewSource(seed))
+	rt.useManualClock(roomTestStart)
+
+	run := &roomTestRun{rt: rt, executor: &recordingExecutor{}, res: res}
+	rt.SetActionExecutor(run.executor)
+
+	remove := events.AddListener(func(e events.Event) {
+		run.mu.Lock()
+		run.events = append(run.events, e)
+		run.mu.Unlock()
+	})
+	defer remove()
+
+	if err := rt.StartGame(test.Scene); err != nil {
+		res.failf("start game: %v", err)
+		return res
+	}
+	defer func() { _ = rt.StopGame() }()
+
+	for i, step := range test.Steps {
+		if err := run.step(step); err != nil {
+			res.failf("steps[%d]: %v", i, err)
+			return res
+		}
+		if step.Expect != nil {
+			run.check(fmt.Sprintf("steps[%d]", i), step.Expect)
+		}
+	}
+	if test.Expect != nil {
+		run.check("expect", test.Expect)
+	}
+	return res
+}
+
+// step plays one step of the test. Checks are made by the caller.
+func (run *roomTestRun) step(step config.RoomTestStep) error {
+	rt := run.rt
+	switch {
+	case step.Input != nil:
+		rt.InjectEvent("device.input", map[string]interface{}{
+			"logical_id": step.Input.Device,
+			"payload":    normalizeTestValue(step.Input.Payload),
+		})
+	case step.Event != nil:
+		fields, _ := normalizeTestValue(step.Event.Fields).(map[string]interface{})
+		if fields == nil {
+			fields = make(map[string]interface{})
+		}
+		rt.InjectEvent(events.Translate(step.Event.Name, "room test"), fields)
+	case step.WaitSec > 0:
+		rt.mu.Lock()
+		defer rt.mu.Unlock()
+		return rt.advanceClock(time.Duration(step.WaitSec * float64(time.Second)))
+	case step.Override != "":
+		return rt.OverrideNode(step.Override)
+	case step.Reset != "":
+		return rt.ResetNode(step.Reset)
+	}
+	return nil
+}
+
+// check records every expectation that does not hold, then moves the
+// window of events and commands checked next past the current ones.
+func (run *roomTestRun) check(where string, want *config.RoomTestCheck) {
+	run.mu.Lock()
+	emitted := run.events[run.checkedEvents:]
+	run.checkedEvents = len(run.events)
+	run.mu.Unlock()
+	sent := run.executor.commands[run.checkedCommands:]
+	run.checkedCommands = len(run.executor.commands)
+	res := run.res
+
+	next := 0
+	for _, w := range want.Events {
+		found := false
+		for next < len(emitted) {
+			e := emitted[next]
+			next++
+			if matchTestEvent(w, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			res.failf("%s: expected event %s", where, describeTestEvent(w))
+			break
+		}
+	}
+	for _, w := range want.NoEvents {
+		for _, e := range emitted {
+			if matchTestEvent(w, e) {
+				res.failf("%s: unexpected event %s", where, describeTestEvent(w))
+				break
+			}
+		}
+	}
+
+	next = 0
+	for _, w := range want.Commands {
+		found := false
+		for next < len(sent) {
+			c := sent[next]
+			next++
+			if c.device == w.Device && c.signal == w.Signal &&
+				(w.Payload == nil || matchTestValue(normalizeTestValue(w.Payload), normalizeTestValue(c.payload))) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			res.failf("%s: expected command %s.%s", where, w.Device, w.Signal)
+			break
+		}
+	}
+
+	for _, nodeID := range sortedKeys(want.Nodes) {
+		if !run.rt.HasNode(nodeID) {
+			res.failf("%s: unknown node %s", where, nodeID)
+		} else if got := run.rt.GetNodeState(nodeID); string(got) != want.Nodes[nodeID] {
+			res.failf("%s: node %s is %s, expected %s", where, nodeID, got, want.Nodes[nodeID])
+		}
+	}
+	for _, nodeID := range sortedKeys(want.Puzzles) {
+		if got := run.rt.GetPuzzleResolution(nodeID); string(got) != want.Puzzles[nodeID] {
+			res.failf("%s: puzzle %s is %s, expected %s", where, nodeID, got, want.Puzzles[nodeID])
+		}
+	}
+	if len(want.Variables) > 0 {
+		vars := normalizeTestValue(run.rt.Variables()).(map[string]interface{})
+		for _, name := range sortedKeys(want.Variables) {
+			w := normalizeTestValue(want.Variables[name])
+			if got, ok := vars[name]; !ok || !matchTestValue(w, got) {
+				res.failf("%s: variable %s is %v, expected %v", where, name, got, w)
+			}
+		}
+	}
+}
+
+// matchTestEvent reports whether an event has the expected name and fields.
+func matchTestEvent(want config.RoomTestEvent, e events.Event) bool {
+	if name, _ := events.Canonical(want.Name); e.Name != name {
+		return false
+	}
+	if len(want.Fields) == 0 {
+		return true
+	}
+	return matchTestValue(normalizeTestValue(want.Fields), normalizeTestValue(e.Fields))
+}
+
+func describeTestEvent(want config.RoomTestEvent) string {
+	if len(want.Fields) == 0 {
+		return want.Name
+	}
+	b, _ := json.Marshal(want.Fields)
+	return want.Name + " " + string(b)
+}
+
+// matchTestValue reports whether got matches want. Objects match when every
+// field of want matches; other values must be equal.
+func matchTestValue(want, got interface{}) bool {
+	wantObj, ok := want.(map[string]interface{})
+	if !ok {
+		return reflect.DeepEqual(want, got)
+	}
+	gotObj, ok := got.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for k, w := range wantObj {
+		g, ok := gotObj[k]
+		if !ok || !matchTestValue(w, g) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeTestValue converts a value to its JSON form, so numbers compare
+// equal whether they came from YAML or the runtime.
+func normalizeTestValue(v interface{}) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,91 @@
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+)
+
+func TestRunRoomTest(t *testing.T) {
+	sg, err := LoadSceneGraph("../../rooms/_template/graphs/scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+
+	test := &config.RoomTest{
+		Name: "wrong",
+		Steps: []config.RoomTestStep{
+			{Input: &config.RoomTestInput{Device: "crypt_door", Payload: map[string]interface{}{"door_closed": true}}},
+			{Expect: &config.RoomTestCheck{
+				Commands: []config.RoomTestCommand{{Device: "crypt_door", Signal: "unlock", Payload: map[string]interface{}{"source": "operator"}}},
+				Puzzles:  map[string]string{"puzzle_scarab": "solved"},
+			}},
+			{WaitSec: 7200},
+		},
+		Expect: &config.RoomTestCheck{
+			// puzzle.solved was checked by the step before
+			Events:   []config.RoomTestEvent{{Name: "puzzle.solved"}},
+			NoEvents: []config.RoomTestEvent{{Name: "timer.expired", Fields: map[string]interface{}{"timer_id": GameClockID}}},
+			Nodes:    map[string]string{"ghost": "active"},
+		},
+	}
+	res := RunRoomTest(sg, nil, test)
+	want := []string{
+		"steps[1]: expected command crypt_door.unlock",
+		"expect: expected event puzzle.solved",
+		`expect: unexpected event timer.expired {"timer_id":"game_clock"}`,
+		"expect: unknown node ghost",
+	}
+	if res.Passed() || strings.Join(res.Failures, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected failures:\n%s", strings.Join(res.Failures, "\n"))
+	}
+
+	// Every room test in the template passes
+	results, err := RunRoomTests("../../rooms/_template", "")
+	if err != nil || len(results) == 0 {
+		t.Fatalf("expected template room tests, got %v, %v", results, err)
+	}
+	for _, res := range results {
+		if !res.Passed() {
+			t.Errorf("%s: %v", res.Name, res.Failures)
+		}
+	}
+}
+
+func TestAdvanceClock(t *testing.T) {
+	sg, err := LoadSceneGraph("../../rooms/_template/graphs/scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	rt := NewRuntime(sg)
+	rt.useManualClock(roomTestStart)
+
+	var fired []string
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.schedule("b", 2*time.Second, func() { fired = append(fired, "b") })
+	rt.schedule("a", time.Second, func() {
+		fired = append(fired, "a")
+		rt.schedule("c", time.Second, func() { fired = append(fired, "c") })
+	})
+	rt.schedule("d", time.Minute, func() { fired = append(fired, "d") })
+
+	if err := rt.advanceClock(2 * time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(fired, ","); got != "a,b,c" {
+		t.Errorf("expected tasks in due order, ties by key, got %s", got)
+	}
+	if remaining, _ := rt.scheduledRemaining("d"); remaining != 58*time.Second {
+		t.Errorf("expected 58s left on d, got %v", remaining)
+	}
+
+	var spin func()
+	spin = func() { rt.schedule("spin", 0, spin) }
+	rt.schedule("spin", 0, spin)
+	if err := rt.advanceClock(time.Second); err == nil {
+		t.Error("expected a task rescheduling itself without delay to be stopped")
+	}
+}
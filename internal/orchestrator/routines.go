@@ -0,0 +1,336 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// routineNodePrefix marks device commands issued by routines.
+const routineNodePrefix = "routine:"
+
+var routineDays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Routine is a validated daily routine.
+type Routine struct {
+	Name     string
+	Hour     int
+	Minute   int
+	Days     map[time.Weekday]bool // nil for every day
+	Commands []config.RoutineCommand
+	Precheck bool
+	Mode     RunMode // empty to leave the mode as it is
+}
+
+// runsOn reports whether the routine runs on the given day.
+func (r Routine) runsOn(day time.Weekday) bool {
+	return r.Days == nil || r.Days[day]
+}
+
+// routineRun summarizes one run of a routine.
+type routineRun struct {
+	Routine        string
+	OK             bool
+	Skipped        bool
+	Error          string
+	Commands       int
+	FailedCommands int
+	PrecheckPassed int
+	PrecheckFailed int
+	Mode           string
+	DurationMs     int64
+}
+
+// ParseRoutines validates the routines in room.yaml. Commands must name a
+// device declared in devices.yaml and one of its outputs.
+func ParseRoutines(cfgs []config.RoutineConfig, devCfg *config.DevicesConfig) ([]Routine, error) {
+	var errs []error
+	seen := make(map[string]bool)
+	routines := make([]Routine, 0, len(cfgs))
+	for i, c := range cfgs {
+		label := fmt.Sprintf("routines[%d]", i)
+		if c.Name == "" {
+			errs = append(errs, fmt.Errorf("%s: name is required", label))
+		} else {
+			label = "routine " + c.Name
+			if seen[c.Name] {
+				errs = append(errs, fmt.Errorf("%s: duplicate name", label))
+			}
+			seen[c.Name] = true
+		}
+
+		r := Routine{Name: c.Name, Commands: c.Commands, Precheck: c.Precheck}
+		at, err := time.Parse("15:04", c.At)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid at %q (want HH:MM)", label, c.At))
+		}
+		r.Hour, r.Minute = at.Hour(), at.Minute()
+
+		for _, d := range c.Days {
+			day, ok := routineDays[strings.ToLower(d)]
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: unknown day %q (mon-sun)", label, d))
+				continue
+			}
+			if r.Days == nil {
+				r.Days = make(map[time.Weekday]bool)
+			}
+			r.Days[day] = true
+		}
+
+		if c.Mode != "" {
+			if r.Mode, err = ParseRunMode(c.Mode); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", label, err))
+			}
+		}
+
+		for j, cmd := range c.Commands {
+			if err := validateRoutineCommand(cmd, devCfg); err != nil {
+				errs = append(errs, fmt.Errorf("%s: commands[%d]: %w", label, j, err))
+			}
+		}
+		routines = append(routines, r)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return routines, nil
+}
+
+func validateRoutineCommand(cmd config.RoutineCommand, devCfg *config.DevicesConfig) error {
+	if cmd.Device == "" || cmd.Signal == "" {
+		return fmt.Errorf("device and signal are required")
+	}
+	if devCfg == nil {
+		return nil
+	}
+	dev, ok := devCfg.Devices[cmd.Device]
+	if !ok {
+		return fmt.Errorf("device %s is not declared in devices.yaml", cmd.Device)
+	}
+	for _, output := range dev.Signals.Outputs {
+		if output == cmd.Signal {
+			return nil
+		}
+	}
+	return fmt.Errorf("signal %s is not an output of %s", cmd.Signal, cmd.Device)
+}
+
+// Routines runs daily routines on schedule, one at a time, in the room's time
+// zone. A routine due while a game is in progress is skipped and reported.
+type Routines struct {
+	routines    []Routine
+	loc         *time.Location
+	rt          *Runtime
+	executor    ActionExecutorInterface
+	maintenance *Maintenance
+	now         func() time.Time
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewRoutines creates a scheduler for routines in the given time zone. Device
+// commands go through executor; prechecks run on maintenance (nil disables
+// them).
+func NewRoutines(routines []Routine, loc *time.Location, rt *Runtime, executor ActionExecutorInterface, maintenance *Maintenance) *Routines {
+	return &Routines{
+		routines:    routines,
+		loc:         loc,
+		rt:          rt,
+		executor:    executor,
+		maintenance: maintenance,
+		now:         time.Now,
+		stopCh:      make(chan struct{}),
+	}
+}
+
+// Start runs the schedule loop in the background.
+func (s *Routines) Start() {
+	if len(s.routines) == 0 {
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			now := s.now()
+			due, at := s.next(now)
+			timer := time.NewTimer(at.Sub(now))
+			select {
+			case <-s.stopCh:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			for _, r := range due {
+				s.run(r)
+			}
+		}
+	}()
+}
+
+// Stop stops the schedule loop, cutting short a routine that is running.
+func (s *Routines) Stop() {
+	close(s.stopCh)
+	s.wg.Wait()
+}
+
+// next returns the routines due at the earliest run time after now.
+func (s *Routines) next(now time.Time) ([]Routine, time.Time) {
+	var due []Routine
+	var earliest time.Time
+	for _, r := range s.routines {
+		at := nextRoutineRun(now, r, s.loc)
+		switch {
+		case earliest.IsZero() || at.Before(earliest):
+			due, earliest = []Routine{r}, at
+		case at.Equal(earliest):
+			due = append(due, r)
+		}
+	}
+	return due, earliest
+}
+
+// nextRoutineRun returns the first time after now the routine is due.
+func nextRoutineRun(now time.Time, r Routine, loc *time.Location) time.Time {
+	local := now.In(loc)
+	at := time.Date(local.Year(), local.Month(), local.Day(), r.Hour, r.Minute, 0, 0, loc)
+	for !at.After(now) || !r.runsOn(at.Weekday()) {
+		at = time.Date(at.Year(), at.Month(), at.Day()+1, r.Hour, r.Minute, 0, 0, loc)
+	}
+	return at
+}
+
+// run sends the routine's commands, runs its precheck and switches its mode,
+// carrying on past failures so a closing routine powers down what it can.
+func (s *Routines) run(r Routine) routineRun {
+	start := time.Now()
+	res := routineRun{Routine: r.Name}
+	events.Emit("info", "room.routine_started", "", map[string]interface{}{
+		"routine": r.Name,
+	})
+
+	var errs []string
+	if s.rt.IsGameActive() {
+		res.Skipped = true
+		errs = append(errs, "game in progress")
+	} else {
+		s.sendCommands(r, &res)
+		if r.Precheck {
+			if err := s.precheck(&res); err != nil {
+				errs = append(errs, "precheck: "+err.Error())
+			}
+		}
+		if r.Mode != "" {
+			if err := s.rt.SetMode(r.Mode, routineNodePrefix+r.Name); err != nil {
+				errs = append(errs, "mode: "+err.Error())
+			} else {
+				res.Mode = string(r.Mode)
+			}
+		}
+	}
+
+	res.Error = strings.Join(errs, "; ")
+	res.OK = len(errs) == 0 && res.FailedCommands == 0 && res.PrecheckFailed == 0
+	res.DurationMs = time.Since(start).Milliseconds()
+
+	level := "info"
+	if !res.OK {
+		level = "warning"
+	}
+	fields := map[string]interface{}{
+		"routine":         r.Name,
+		"ok":              res.OK,
+		"commands":        res.Commands,
+		"failed_commands": res.FailedCommands,
+		"duration_ms":     res.DurationMs,
+	}
+	if res.Skipped {
+		fields["skipped"] = true
+	}
+	if res.Error != "" {
+		fields["error"] = res.Error
+	}
+	if r.Precheck {
+		fields["precheck_passed"] = res.PrecheckPassed
+		fields["precheck_failed"] = res.PrecheckFailed
+	}
+	if res.Mode != "" {
+		fields["mode"] = res.Mode
+	}
+	events.Emit(level, "room.routine_finished", "", fields)
+	return res
+}
+
+// sendCommands sends each command in order, pausing after those with a wait.
+func (s *Routines) sendCommands(r Routine, res *routineRun) {
+	for _, cmd := range r.Commands {
+		res.Commands++
+		if s.executor == nil {
+			res.FailedCommands++
+			continue
+		}
+		// The executor reports its own failures as device.error
+		err := s.executor.ExecuteAction(routineNodePrefix+r.Name, map[string]interface{}{
+			"action": "device.command",
+			"params": map[string]interface{}{
+				"device_id": cmd.Device,
+				"signal":    cmd.Signal,
+				"payload":   cmd.Payload,
+			},
+		})
+		if err != nil {
+			res.FailedCommands++
+		}
+		if cmd.WaitMs > 0 {
+			select {
+			case <-time.After(time.Duration(cmd.WaitMs) * time.Millisecond):
+			case <-s.stopCh:
+				return
+			}
+		}
+	}
+}
+
+// precheck runs the maintenance.yaml device tests and records the results.
+func (s *Routines) precheck(res *routineRun) error {
+	if s.maintenance == nil {
+		return fmt.Errorf("maintenance not available")
+	}
+	if err := s.maintenance.Start(); err != nil {
+		return err
+	}
+	done := make(chan struct{})
+	go func() {
+		s.maintenance.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-s.stopCh:
+	}
+	_ = s.maintenance.Stop()
+
+	if report := s.maintenance.Report(); report != nil {
+		res.PrecheckPassed = report.Passed
+		res.PrecheckFailed = report.Failed
+		if !report.Complete {
+			return fmt.Errorf("interrupted")
+		}
+	}
+	return nil
+}
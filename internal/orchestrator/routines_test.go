@@ -0,0 +1,136 @@
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+func routineDevices() *config.DevicesConfig {
+	var fog config.DeviceDefinition
+	fog.Signals.Outputs = []string{"power"}
+	return &config.DevicesConfig{Version: 1, Devices: map[string]config.DeviceDefinition{"fog_machine": fog}}
+}
+
+func TestParseRoutines(t *testing.T) {
+	routines, err := ParseRoutines([]config.RoutineConfig{
+		{Name: "opening", At: "09:30", Days: []string{"Sat", "sun"}, Precheck: true, Mode: "show",
+			Commands: []config.RoutineCommand{{Device: "fog_machine", Signal: "power", Payload: true}}},
+		{Name: "closing", At: "23:00", Mode: "maintenance"},
+	}, routineDevices())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	opening := routines[0]
+	if opening.Hour != 9 || opening.Minute != 30 || opening.Mode != ModeShow ||
+		!opening.runsOn(time.Saturday) || opening.runsOn(time.Monday) {
+		t.Errorf("unexpected opening routine: %+v", opening)
+	}
+	if !routines[1].runsOn(time.Monday) {
+		t.Error("expected a routine without days to run every day")
+	}
+
+	_, err = ParseRoutines([]config.RoutineConfig{
+		{Name: "opening", At: "9.30", Days: []string{"someday"}, Mode: "party",
+			Commands: []config.RoutineCommand{
+				{Device: "fog_machine", Signal: "explode"},
+				{Device: "ghost", Signal: "power"},
+			}},
+		{Name: "opening", At: "10:00"},
+		{At: "11:00"},
+	}, routineDevices())
+	if err == nil {
+		t.Fatal("expected invalid routines rejected")
+	}
+	for _, want := range []string{"invalid at", "unknown day", "unknown mode", "not an output", "not declared", "duplicate name", "name is required"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestNextRoutineRun(t *testing.T) {
+	loc, err := time.LoadLocation("America/Phoenix")
+	if err != nil {
+		t.Skip("time zone data not available")
+	}
+	weekend := Routine{Hour: 9, Minute: 30, Days: map[time.Weekday]bool{time.Saturday: true, time.Sunday: true}}
+	daily := Routine{Hour: 23}
+
+	// Wednesday 2026-01-07 10:00 local
+	now := time.Date(2026, 1, 7, 10, 0, 0, 0, loc)
+	if got := nextRoutineRun(now, weekend, loc); !got.Equal(time.Date(2026, 1, 10, 9, 30, 0, 0, loc)) {
+		t.Errorf("expected Saturday 09:30, got %v", got)
+	}
+	if got := nextRoutineRun(now, daily, loc); !got.Equal(time.Date(2026, 1, 7, 23, 0, 0, 0, loc)) {
+		t.Errorf("expected today 23:00, got %v", got)
+	}
+	if got := nextRoutineRun(now.Add(13*time.Hour), daily, loc); !got.Equal(time.Date(2026, 1, 8, 23, 0, 0, 0, loc)) {
+		t.Errorf("expected tomorrow 23:00, got %v", got)
+	}
+
+	s := NewRoutines([]Routine{{Name: "a", Hour: 23}, {Name: "b", Hour: 23}, weekend}, loc, nil, nil, nil)
+	due, at := s.next(now)
+	if len(due) != 2 || !at.Equal(time.Date(2026, 1, 7, 23, 0, 0, 0, loc)) {
+		t.Errorf("expected both 23:00 routines due together, got %d at %v", len(due), at)
+	}
+}
+
+func TestRoutineRun(t *testing.T) {
+	events.Clear()
+	sg, err := LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+	rt := NewRuntime(sg)
+	exec := &replyingExecutor{fail: map[string]bool{"dead_relay": true}}
+	maint := NewMaintenance(rt, &config.MaintenanceConfig{
+		Version:  1,
+		Routines: map[string]config.MaintenanceRoutine{"fog_machine": {Steps: []config.MaintenanceStep{{Signal: "puff"}}}},
+	}, exec)
+
+	s := NewRoutines(nil, time.UTC, rt, exec, maint)
+	res := s.run(Routine{
+		Name: "closing",
+		Commands: []config.RoutineCommand{
+			{Device: "dead_relay", Signal: "power", Payload: false},
+			{Device: "fog_machine", Signal: "power", Payload: false, WaitMs: 1},
+		},
+		Precheck: true,
+		Mode:     ModeMaintenance,
+	})
+
+	if res.OK || res.Commands != 2 || res.FailedCommands != 1 || res.PrecheckPassed != 1 || res.Mode != "maintenance" {
+		t.Errorf("unexpected run: %+v", res)
+	}
+	if got := strings.Join(exec.commands, ","); got != "dead_relay:power,fog_machine:power,fog_machine:puff" {
+		t.Errorf("expected every command sent before the precheck, got %s", got)
+	}
+	if !rt.InMaintenance() || maint.Active() {
+		t.Error("expected the room left in maintenance mode with no test run active")
+	}
+
+	var finished *events.Event
+	for _, e := range events.Snapshot() {
+		if e.Name == "room.routine_finished" {
+			finished = &e
+		}
+	}
+	if finished == nil || finished.Level != "warning" || finished.Fields["failed_commands"] != 1 {
+		t.Errorf("expected room.routine_finished warning, got %+v", finished)
+	}
+
+	// A routine never interrupts a game
+	_ = rt.SetMode(ModeShow, "")
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	exec.commands = nil
+	res = s.run(Routine{Name: "closing", Commands: []config.RoutineCommand{{Device: "fog_machine", Signal: "power"}}, Mode: ModeMaintenance})
+	if !res.Skipped || res.OK || len(exec.commands) != 0 || rt.Mode() != ModeShow {
+		t.Errorf("expected the routine skipped during a game, got %+v", res)
+	}
+}
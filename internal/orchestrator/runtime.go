@@ -2,18 +2,69 @@ package orchestrator
 
 import (
 	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/AaronLay10/SentientEngine/internal/events"
 )
 
 // Runtime manages scene graph execution.
 type Runtime struct {
+	mu             sync.Mutex
 	graph          *SceneGraph
 	activeScene    *Scene
 	nodeStates     map[string]*NodeStatus
 	puzzleStates   map[string]*PuzzleStatus
 	puzzleRuntimes map[string]*PuzzleRuntime
+	subgraphs      map[string]*PuzzleRuntime
 	actionExecutor ActionExecutorInterface
+	tasks          map[string]*scheduledTask
+	clock          *gameClock
+	gameDuration   time.Duration
+	maxDuration    time.Duration
+	now            func() time.Time
+	mode           RunMode     // run mode (see mode.go)
+	modeBefore     RunMode     // mode to return to when maintenance routines stop
+	dryRun         atomic.Bool // the run mode dry-runs actions
+	randomChoices  map[string]string
+	rng            *rand.Rand
+	sceneStartedAt time.Time
+
+	checkpointStore CheckpointStore
+	checkpoints     map[string]*Checkpoint
+	lastCheckpoint  string
+
+	violations map[string]bool // invariants currently reported as violated
+
+	cascadeReset bool // ResetNode also resets downstream nodes by default
+
+	vars map[string]interface{} // session variables (see variables.go)
+
+	executedActions map[string]bool // actions with replay_on_reset: false that already ran
+
+	undoStack []undoEntry // operator actions that can be undone (see undo.go)
+
+	attempts map[string]int // failed attempts per puzzle (see failure.go)
+
+	hintsGiven map[string]int // hints given per puzzle (see hints.go)
+
+	report *SessionReport // running or last session's summary (see score.go)
+
+	paused            bool      // game paused (see pause.go)
+	pausedAt          time.Time // when the game was paused
+	pauseIgnoresInput bool      // device.input is ignored while paused
+
+	resetCooldown time.Duration        // default re-solve cooldown after a puzzle reset
+	cooldowns     map[string]time.Time // puzzle node_id -> end of its cooldown (see cooldown.go)
+
+	stallTimeout time.Duration // game time without progress before scene.stalled (see stall.go)
+
+	video videoSync // timer.sync settings and state (see video.go)
+
+	manualClock bool      // scheduled tasks wait for advanceClock (see scheduler.go)
+	clockTime   time.Time // current time of the manual clock
 }
 
 // NewRuntime creates a new scene runtime.
@@ -23,11 +74,35 @@ func NewRuntime(sg *SceneGraph) *Runtime {
 		nodeStates:     make(map[string]*NodeStatus),
 		puzzleStates:   make(map[string]*PuzzleStatus),
 		puzzleRuntimes: make(map[string]*PuzzleRuntime),
+		subgraphs:      make(map[string]*PuzzleRuntime),
+		tasks:          make(map[string]*scheduledTask),
+		gameDuration:   DefaultGameDuration,
+		now:            time.Now,
+		mode:           ModeShow,
+		randomChoices:  make(map[string]string),
+		rng:            rand.New(rand.NewSource(time.Now().UnixNano())),
+		checkpoints:    make(map[string]*Checkpoint),
+		violations:     make(map[string]bool),
+		vars:           make(map[string]interface{}),
+
+		executedActions: make(map[string]bool),
+		attempts:        make(map[string]int),
+		hintsGiven:      make(map[string]int),
+		cooldowns:       make(map[string]time.Time),
+		video:           videoSync{interval: DefaultVideoSyncInterval},
+
+		pauseIgnoresInput: true,
 	}
 }
 
 // StartScene initializes and starts a scene by ID.
 func (r *Runtime) StartScene(sceneID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.startScene(sceneID)
+}
+
+func (r *Runtime) startScene(sceneID string) error {
 	// Find scene
 	for i := range r.graph.Scenes {
 		if r.graph.Scenes[i].ID == sceneID {
@@ -39,7 +114,20 @@ func (r *Runtime) StartScene(sceneID string) error {
 		return fmt.Errorf("scene not found: %s", sceneID)
 	}
 
-	// Initialize all nodes to idle
+	r.initSceneNodes()
+	r.sceneStartedAt = r.now()
+
+	// Emit scene.started
+	r.emitEvent("scene.started", map[string]interface{}{"scene_id": sceneID})
+
+	// Activate entry node
+	r.activateNode(r.activeScene.Entry)
+
+	return nil
+}
+
+// initSceneNodes sets every node of the active scene to idle.
+func (r *Runtime) initSceneNodes() {
 	for _, node := range r.activeScene.Nodes {
 		r.nodeStates[node.ID] = &NodeStatus{
 			NodeID: node.ID,
@@ -53,26 +141,60 @@ func (r *Runtime) StartScene(sceneID string) error {
 			}
 		}
 	}
-
-	// Emit scene.started
-	r.emitEvent("scene.started", map[string]interface{}{"scene_id": sceneID})
-
-	// Activate entry node
-	r.activateNode(r.activeScene.Entry)
-
-	return nil
 }
 
 // InjectEvent processes an external event (for testing).
 func (r *Runtime) InjectEvent(name string, fields map[string]interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Device input outside a game (e.g. maintenance) has nothing to route to
+	if r.activeScene == nil {
+		return
+	}
+
+	// Props handled during a pause do not advance the game
+	if r.ignoresInput(name) {
+		return
+	}
+
 	evt := Event{Name: name, Fields: fields}
 
+	// Device inputs are visible to conditions as vars.input.<logical_id>
+	if name == "device.input" {
+		r.recordInput(fields)
+	}
+
 	// Route to active puzzle runtimes
 	for nodeID, pr := range r.puzzleRuntimes {
+		if r.coolingDown(nodeID, pr, evt) {
+			continue
+		}
+		r.noteHintActivity(nodeID, pr, evt)
+		if pr.Matches(evt) {
+			r.noteProgress()
+		}
 		if pr.HandleEvent(evt) {
+			if pr.Resolution() == PuzzleFailed {
+				// A keypad ran out of attempts
+				r.attempts[nodeID] = pr.Attempts()
+				r.failPuzzle(nodeID, "max_attempts")
+				continue
+			}
 			// Puzzle resolved
+			r.stopPuzzleLimits(nodeID)
 			r.puzzleStates[nodeID].Resolution = pr.Resolution()
 			r.completeNode(nodeID)
+		} else if !pr.Done() {
+			r.countAttempt(nodeID, evt)
+		}
+	}
+
+	// Route to running generic subgraphs
+	for nodeID, sr := range r.subgraphs {
+		if sr.HandleEvent(evt) {
+			delete(r.subgraphs, nodeID)
+			r.completeNode(nodeID)
 		}
 	}
 
@@ -92,7 +214,10 @@ func (r *Runtime) activateNode(nodeID string) {
 	}
 
 	status.State = NodeStateActive
+	status.ActivatedAt = r.now()
 	r.emitEvent("node.started", map[string]interface{}{"node_id": nodeID})
+	r.checkInvariants()
+	r.noteProgress()
 
 	switch node.Type {
 	case "parallel":
@@ -101,14 +226,32 @@ func (r *Runtime) activateNode(nodeID string) {
 		r.activatePuzzle(node)
 	case "action":
 		r.executeAction(node)
+	case "timer":
+		r.startTimer(node)
+	case "random":
+		r.activateRandom(node)
+	case "checkpoint":
+		r.activateCheckpoint(node)
+	case "subgraph":
+		r.activateSubgraph(node)
+	case "operator":
+		// Operator nodes stay active until explicitly approved
+		r.requestApproval(node)
+	case "gate":
+		// Gates complete once their all_of/any_of conditions hold
+		if r.gateSatisfied(node) {
+			r.completeNode(nodeID)
+		}
 	case "loop":
-		// MVP: loops stay active until stop_condition is true
-		// Stop condition is evaluated when puzzle states change
-		r.emitEvent("loop.started", map[string]interface{}{"node_id": nodeID})
+		// Loops tick on interval_ms until stop_condition is true
+		// Stop condition is evaluated when puzzle states change and on each tick
+		r.startLoop(node)
 	case "terminal":
 		// Terminal nodes complete immediately
 		r.completeNode(nodeID)
 		r.emitEvent("scene.completed", map[string]interface{}{"scene_id": r.activeScene.ID})
+		r.cancelScheduled(stallKey)
+		r.chainScene()
 	}
 }
 
@@ -134,14 +277,9 @@ func (r *Runtime) activatePuzzle(node *Node) {
 		return
 	}
 
-	pr := NewPuzzleRuntime(subgraph, node.ID)
-
-	// Pass action executor to puzzle runtime so subgraph actions are executed
-	if r.actionExecutor != nil {
-		pr.SetActionFunc(r.actionExecutor.ExecuteAction)
-	}
-
+	pr := r.newPuzzleRuntime(node, subgraph)
 	r.puzzleRuntimes[node.ID] = pr
+	r.startPuzzleLimits(node)
 
 	r.emitEvent("puzzle.activated", map[string]interface{}{
 		"node_id":     node.ID,
@@ -152,30 +290,164 @@ func (r *Runtime) activatePuzzle(node *Node) {
 	pr.Start()
 }
 
+// newPuzzleRuntime creates the runtime for a puzzle node's subgraph.
+func (r *Runtime) newPuzzleRuntime(node *Node, subgraph *Subgraph) *PuzzleRuntime {
+	pr := NewPuzzleRuntime(subgraph, node.ID)
+
+	// Pass action executor to puzzle runtime so subgraph actions are executed
+	pr.SetActionFunc(r.subgraphActionFunc(node.ID))
+	pr.SetScheduleFunc(r.subgraphScheduleFunc(node.ID))
+	pr.SetVars(r.vars)
+	pr.SetRand(r.rng)
+	return pr
+}
+
+// activateSubgraph runs a reusable, non-puzzle subgraph; the node completes
+// when the subgraph reaches its terminal.
+func (r *Runtime) activateSubgraph(node *Node) {
+	subgraphID, ok := node.Config["subgraph"].(string)
+	if !ok {
+		return
+	}
+	subgraph := r.findSubgraph(subgraphID)
+	if subgraph == nil {
+		return
+	}
+
+	sr := NewSubgraphRuntime(subgraph, node.ID)
+	sr.SetActionFunc(r.subgraphActionFunc(node.ID))
+	sr.SetScheduleFunc(r.subgraphScheduleFunc(node.ID))
+	sr.SetVars(r.vars)
+	sr.SetRand(r.rng)
+
+	// Sequences made only of actions finish immediately
+	sr.Start()
+	if sr.Done() {
+		r.completeNode(node.ID)
+		return
+	}
+	r.subgraphs[node.ID] = sr
+}
+
 func (r *Runtime) executeAction(node *Node) {
-	// If we have an action executor, try to execute the action
-	if r.actionExecutor != nil {
-		if err := r.actionExecutor.ExecuteAction(node.ID, node.Config); err != nil {
-			// Action failed, but we still complete the node for deterministic flow
-			// The error was already logged via device.error event
-		}
+	// Delayed actions keep the node active until the delay elapses
+	if delay := actionDelay(node.Config); delay > 0 {
+		nodeID := node.ID
+		r.schedule(actionKey(nodeID), delay, func() {
+			if status := r.nodeStates[nodeID]; status == nil || status.State != NodeStateActive {
+				return
+			}
+			r.completeAction(r.findNode(nodeID))
+			r.evaluateAllConditions()
+		})
+		return
+	}
+	r.completeAction(node)
+}
+
+// completeAction runs an action node's action and completes the node.
+func (r *Runtime) completeAction(node *Node) {
+	skipped, err := r.runNodeAction(node.ID, node.ID, node.Config)
+	if err != nil {
+		// Action failed, but we still complete the node for deterministic flow
+		// The error was already logged via device.error event
 	}
 	// MVP: actions complete immediately (synchronous)
+	if skipped {
+		r.completeNodeWith(node.ID, map[string]interface{}{"replay_skipped": true})
+		return
+	}
 	r.completeNode(node.ID)
 }
 
+// timerKey returns the scheduler key for a timer node.
+func timerKey(nodeID string) string {
+	return "timer:" + nodeID
+}
+
+// startTimer begins a timer node's countdown. The node completes when it expires.
+func (r *Runtime) startTimer(node *Node) {
+	durationMs, _ := configInt(node.Config, "duration_ms")
+	if durationMs < 0 {
+		durationMs = 0
+	}
+
+	r.emitEvent("timer.started", map[string]interface{}{
+		"node_id":     node.ID,
+		"duration_ms": durationMs,
+	})
+
+	nodeID := node.ID
+	r.schedule(timerKey(nodeID), time.Duration(durationMs)*time.Millisecond, func() {
+		r.expireTimer(nodeID)
+	})
+}
+
+// expireTimer completes a timer node whose countdown has elapsed.
+func (r *Runtime) expireTimer(nodeID string) {
+	status := r.nodeStates[nodeID]
+	if status == nil || status.State != NodeStateActive {
+		return
+	}
+	r.emitEvent("timer.expired", map[string]interface{}{"node_id": nodeID})
+	r.completeNode(nodeID)
+	r.evaluateAllConditions()
+}
+
+// cancelTimer stops a running timer and emits timer.cancelled if one was pending.
+func (r *Runtime) cancelTimer(nodeID, reason string) {
+	if !r.cancelScheduled(timerKey(nodeID)) {
+		return
+	}
+	r.emitEvent("timer.cancelled", map[string]interface{}{
+		"node_id": nodeID,
+		"reason":  reason,
+	})
+}
+
+// configInt reads an integer config value. JSON numbers decode as float64.
+func configInt(config map[string]interface{}, key string) (int, bool) {
+	switch v := config[key].(type) {
+	case float64:
+		return int(v), true
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	}
+	return 0, false
+}
+
 func (r *Runtime) completeNode(nodeID string) {
+	r.completeNodeWith(nodeID, nil)
+}
+
+// completeNodeWith completes a node, adding extra fields to its node.completed event.
+func (r *Runtime) completeNodeWith(nodeID string, extra map[string]interface{}) {
 	status := r.nodeStates[nodeID]
 	if status.State == NodeStateCompleted {
 		return
 	}
 	status.State = NodeStateCompleted
 
-	r.emitEvent("node.completed", map[string]interface{}{"node_id": nodeID})
+	fields := map[string]interface{}{"node_id": nodeID}
+	for k, v := range extra {
+		fields[k] = v
+	}
+	r.emitEvent("node.completed", fields)
+	// The puzzle runtime emitted puzzle.solved itself
+	if ps, ok := r.puzzleStates[nodeID]; ok && ps.Resolution == PuzzleSolved {
+		r.tally("puzzle.solved", map[string]interface{}{"node_id": nodeID})
+	}
+	r.checkInvariants()
+	r.noteProgress()
 
 	// Check if this completes a parallel node
 	r.checkParallelCompletion()
 
+	// Check if this opens a gate
+	r.evaluateGates()
+
 	// Evaluate outgoing edges
 	r.evaluateEdgesFrom(nodeID)
 }
@@ -190,21 +462,35 @@ func (r *Runtime) checkParallelCompletion() {
 			continue
 		}
 
-		// Check if all children are completed (or overridden)
+		// Check if all children are completed (or overridden or failed)
 		childrenRaw, ok := node.Config["children"].([]interface{})
 		if !ok {
 			continue
 		}
 		allComplete := true
+		succeeded := 0
 		for _, child := range childrenRaw {
 			if childID, ok := child.(string); ok {
-				childStatus := r.nodeStates[childID]
-				if childStatus.State != NodeStateCompleted && childStatus.State != NodeStateOverridden {
+				switch r.nodeStates[childID].State {
+				case NodeStateCompleted, NodeStateOverridden:
+					succeeded++
+				case NodeStateFailed:
+					// Ended, but does not count toward a quorum
+				default:
 					allComplete = false
-					break
 				}
 			}
 		}
+
+		// Quorum join: complete once min_complete children succeeded; the
+		// remaining children keep running as optional branches
+		if minComplete, ok := configInt(node.Config, "min_complete"); ok && minComplete > 0 && succeeded >= minComplete {
+			r.completeNodeWith(node.ID, map[string]interface{}{
+				"min_complete":       minComplete,
+				"children_completed": succeeded,
+			})
+			continue
+		}
 		if allComplete {
 			r.completeNode(node.ID)
 		}
@@ -212,18 +498,24 @@ func (r *Runtime) checkParallelCompletion() {
 }
 
 func (r *Runtime) evaluateEdgesFrom(fromNodeID string) {
-	ctx := &EvalContext{
-		PuzzleStates: r.puzzleStates,
-	}
+	ctx := r.evalContext()
+	failed := r.nodeStates[fromNodeID].State == NodeStateFailed
 
 	for _, edge := range r.activeScene.Edges {
 		if edge.From != fromNodeID {
 			continue
 		}
+		// Failed nodes only follow edges that branch on a condition
+		if failed && edge.Condition == "" {
+			continue
+		}
 		toStatus := r.nodeStates[edge.To]
 		if toStatus.State != NodeStateIdle {
 			continue
 		}
+		if !r.edgeSelected(edge) {
+			continue
+		}
 		if EvalCondition(edge.Condition, ctx) {
 			r.activateNode(edge.To)
 		}
@@ -231,9 +523,7 @@ func (r *Runtime) evaluateEdgesFrom(fromNodeID string) {
 }
 
 func (r *Runtime) evaluateAllConditions() {
-	ctx := &EvalContext{
-		PuzzleStates: r.puzzleStates,
-	}
+	ctx := r.evalContext()
 
 	// Evaluate loop stop conditions (loops complete when stop_condition is true)
 	for _, node := range r.activeScene.Nodes {
@@ -249,28 +539,42 @@ func (r *Runtime) evaluateAllConditions() {
 			continue
 		}
 		if EvalCondition(stopCondition, ctx) {
+			r.stopLoop(node.ID)
 			r.emitEvent("loop.stopped", map[string]interface{}{"node_id": node.ID})
 			r.completeNode(node.ID)
 		}
 	}
 
+	// Evaluate gate joins that may depend on puzzle resolution
+	r.evaluateGates()
+
 	// Evaluate edge conditions
 	for _, edge := range r.activeScene.Edges {
 		fromStatus := r.nodeStates[edge.From]
 		toStatus := r.nodeStates[edge.To]
 
-		// Only evaluate if source is completed/overridden and target is idle
-		fromDone := fromStatus.State == NodeStateCompleted || fromStatus.State == NodeStateOverridden
-		if fromDone && toStatus.State == NodeStateIdle {
+		// Only evaluate if source is completed/overridden and target is idle;
+		// failed sources only follow conditional edges
+		fromDone := fromStatus.State == NodeStateCompleted || fromStatus.State == NodeStateOverridden ||
+			(fromStatus.State == NodeStateFailed && edge.Condition != "")
+		if fromDone && toStatus.State == NodeStateIdle && r.edgeSelected(edge) {
 			if EvalCondition(edge.Condition, ctx) {
 				r.activateNode(edge.To)
 			}
 		}
 	}
+
+	r.checkInvariants()
+}
+
+// evalContext returns the condition context for the active scene.
+func (r *Runtime) evalContext() *EvalContext {
+	return &EvalContext{PuzzleStates: r.puzzleStates, Vars: r.vars}
 }
 
 func (r *Runtime) emitEvent(name string, fields map[string]interface{}) {
 	events.Emit("info", name, "", fields)
+	r.tally(name, fields)
 }
 
 func (r *Runtime) findNode(nodeID string) *Node {
@@ -293,6 +597,9 @@ func (r *Runtime) findSubgraph(subgraphID string) *Subgraph {
 
 // GetNodeState returns the state of a node (for testing).
 func (r *Runtime) GetNodeState(nodeID string) NodeState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if status, ok := r.nodeStates[nodeID]; ok {
 		return status.State
 	}
@@ -301,6 +608,9 @@ func (r *Runtime) GetNodeState(nodeID string) NodeState {
 
 // GetPuzzleResolution returns the resolution of a puzzle node (for testing).
 func (r *Runtime) GetPuzzleResolution(nodeID string) PuzzleResolution {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if status, ok := r.puzzleStates[nodeID]; ok {
 		return status.Resolution
 	}
@@ -309,6 +619,9 @@ func (r *Runtime) GetPuzzleResolution(nodeID string) PuzzleResolution {
 
 // HasNode returns true if the node exists in the active scene.
 func (r *Runtime) HasNode(nodeID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if r.activeScene == nil {
 		return false
 	}
@@ -319,6 +632,9 @@ func (r *Runtime) HasNode(nodeID string) bool {
 // For puzzle nodes, marks the puzzle as overridden and emits puzzle.overridden.
 // Triggers evaluation logic (loop stop, parallel join, edges).
 func (r *Runtime) OverrideNode(nodeID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if r.activeScene == nil {
 		return fmt.Errorf("no active scene")
 	}
@@ -332,32 +648,90 @@ func (r *Runtime) OverrideNode(nodeID string) error {
 	if status.State == NodeStateCompleted || status.State == NodeStateOverridden {
 		return nil // already completed
 	}
+	r.pushUndo("override", nodeID)
+	r.overrideNode(node, map[string]interface{}{"node_id": nodeID})
+
+	// Trigger evaluation logic
+	r.checkParallelCompletion()
+	r.evaluateAllConditions()
+
+	return nil
+}
+
+// overrideNode stops whatever the node is running, marks it overridden and
+// emits node.overridden with the given fields followed by node.completed. It
+// does not evaluate edges.
+// Caller must hold r.mu.
+func (r *Runtime) overrideNode(node *Node, fields map[string]interface{}) {
+	nodeID := node.ID
+	status := r.nodeStates[nodeID]
 
 	// For puzzle nodes, mark puzzle as overridden
 	if node.Type == "puzzle" {
+		r.stopPuzzleLimits(nodeID)
 		if ps, ok := r.puzzleStates[nodeID]; ok {
 			ps.Resolution = PuzzleOverridden
 		}
 		r.emitEvent("puzzle.overridden", map[string]interface{}{"node_id": nodeID})
 	}
 
+	// For timer nodes, stop the countdown
+	if node.Type == "timer" {
+		r.cancelTimer(nodeID, "overridden")
+	}
+
+	// For loop nodes, stop ticking
+	if node.Type == "loop" {
+		r.stopLoop(nodeID)
+	}
+
+	// For subgraph nodes, abandon the running sequence
+	delete(r.subgraphs, nodeID)
+
+	// Drop pending delayed actions of the node or its subgraph
+	r.cancelActions(nodeID)
+
 	// Mark node as overridden
 	status.State = NodeStateOverridden
-	r.emitEvent("node.overridden", map[string]interface{}{"node_id": nodeID})
+	r.emitEvent("node.overridden", fields)
 
 	// Emit node.completed (overridden counts as completed for flow)
-	r.emitEvent("node.completed", map[string]interface{}{"node_id": nodeID})
+	completed := map[string]interface{}{"node_id": nodeID}
+	if node.Type == "random" {
+		// Overriding a random node still picks a branch so flow can continue
+		completed["choice"] = r.chooseRandom(node)
+	}
+	r.emitEvent("node.completed", completed)
+}
 
-	// Trigger evaluation logic
-	r.checkParallelCompletion()
-	r.evaluateAllConditions()
+// SetCascadeReset sets whether ResetNode also resets downstream nodes by default.
+func (r *Runtime) SetCascadeReset(enabled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	return nil
+	r.cascadeReset = enabled
 }
 
 // ResetNode returns a node to active/waiting state.
 // For puzzle nodes, marks the puzzle as unresolved and emits puzzle.reset.
+// Downstream nodes are also reset when cascading reset is enabled.
 func (r *Runtime) ResetNode(nodeID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.resetNode(nodeID, r.cascadeReset)
+}
+
+// ResetNodeCascade resets a node, choosing explicitly whether dependent downstream
+// nodes that already ran are returned to idle as well.
+func (r *Runtime) ResetNodeCascade(nodeID string, cascade bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.resetNode(nodeID, cascade)
+}
+
+func (r *Runtime) resetNode(nodeID string, cascade bool) error {
 	if r.activeScene == nil {
 		return fmt.Errorf("no active scene")
 	}
@@ -368,24 +742,85 @@ func (r *Runtime) ResetNode(nodeID string) error {
 	}
 
 	status := r.nodeStates[nodeID]
+	r.pushUndo("reset", nodeID)
 
 	// For puzzle nodes, mark puzzle as unresolved
 	if node.Type == "puzzle" {
 		if ps, ok := r.puzzleStates[nodeID]; ok {
 			ps.Resolution = PuzzleUnresolved
 		}
+		r.stopPuzzleLimits(nodeID)
+		r.startCooldown(nodeID)
 		r.emitEvent("puzzle.reset", map[string]interface{}{"node_id": nodeID})
 	}
 
+	// For timer nodes, cancel any running countdown and start it over
+	if node.Type == "timer" {
+		r.cancelTimer(nodeID, "operator_reset")
+	}
+
+	// For loop nodes, stop ticking until the loop starts over
+	if node.Type == "loop" {
+		r.stopLoop(nodeID)
+	}
+
+	// For subgraph nodes, drop the running sequence and start it over
+	delete(r.subgraphs, nodeID)
+
+	// Drop pending delayed actions of the node or its subgraph
+	r.cancelActions(nodeID)
+
+	// Return downstream nodes to idle so they run again after this node
+	resetFields := map[string]interface{}{"node_id": nodeID}
+	if cascade {
+		if cascaded := r.resetDownstream(nodeID); len(cascaded) > 0 {
+			resetFields["cascade"] = cascaded
+		}
+	}
+
 	// Return node to active state
 	status.State = NodeStateActive
-	r.emitEvent("node.reset", map[string]interface{}{"node_id": nodeID})
+	status.ActivatedAt = r.now()
+	r.emitEvent("node.reset", resetFields)
+
+	switch node.Type {
+	case "parallel":
+		r.activateParallel(node)
+	case "timer":
+		r.startTimer(node)
+	case "action":
+		// Delayed actions start their delay over
+		if actionDelay(node.Config) > 0 {
+			r.executeAction(node)
+		}
+	case "subgraph":
+		r.activateSubgraph(node)
+	case "operator":
+		r.requestApproval(node)
+	case "loop":
+		r.startLoop(node)
+	case "puzzle":
+		// A resolved puzzle gets a fresh subgraph; the timeout starts over
+		if pr, ok := r.puzzleRuntimes[nodeID]; !ok || pr.Done() {
+			r.activatePuzzle(node)
+		} else {
+			r.startPuzzleLimits(node)
+		}
+	}
 
+	r.checkInvariants()
 	return nil
 }
 
 // StartGame starts a game session with the specified scene (or first scene if empty).
 func (r *Runtime) StartGame(sceneID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.mode.Profile().Games {
+		return fmt.Errorf("room is in %s mode", r.mode)
+	}
+
 	// If no scene specified, use first scene
 	if sceneID == "" {
 		if len(r.graph.Scenes) == 0 {
@@ -394,23 +829,64 @@ func (r *Runtime) StartGame(sceneID string) error {
 		sceneID = r.graph.Scenes[0].ID
 	}
 
+	if r.findScene(sceneID) == nil {
+		return fmt.Errorf("scene not found: %s", sceneID)
+	}
+
+	// Starting over without a stop ends the previous session
+	if r.activeScene != nil {
+		result := r.sessionResult()
+		r.endReport(result)
+		events.EndSession(result)
+	}
+
 	// Reset state before starting
 	r.resetState()
 
+	// Every event from here until StopGame carries the session ID
+	sessionID := events.StartSession(sceneID)
+	r.report = newSessionReport(sessionID, r.now())
+
 	// Start the scene
-	return r.StartScene(sceneID)
+	if err := r.startScene(sceneID); err != nil {
+		return err
+	}
+
+	// Start the session countdown
+	r.startClock()
+
+	// Start timeline sync for CCTV review
+	r.startVideoSync()
+	return nil
 }
 
 // StopGame stops the active game and resets runtime state.
 func (r *Runtime) StopGame() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if r.activeScene == nil {
 		return fmt.Errorf("no active game")
 	}
 
 	sceneID := r.activeScene.ID
+	result := r.sessionResult()
+
+	// Cancel running timers so they do not fire into the next session
+	for _, node := range r.activeScene.Nodes {
+		if node.Type == "timer" {
+			r.cancelTimer(node.ID, "scene_stopped")
+		}
+	}
+
+	// Final timeline sync, then cancel the session countdown
+	r.stopVideoSync()
+	r.stopClock("scene_stopped")
 
 	// Emit scene.reset before clearing state
 	r.emitEvent("scene.reset", map[string]interface{}{"scene_id": sceneID})
+	r.endReport(result)
+	events.EndSession(result)
 
 	// Reset all state
 	r.resetState()
@@ -418,28 +894,115 @@ func (r *Runtime) StopGame() error {
 	return nil
 }
 
+// sessionResult reports how the running game ended: completed if the scene
+// reached a terminal, expired if the clock ran out, otherwise stopped.
+// Caller must hold r.mu.
+func (r *Runtime) sessionResult() string {
+	for _, node := range r.activeScene.Nodes {
+		if st := r.nodeStates[node.ID]; node.Type == "terminal" && st != nil && st.State == NodeStateCompleted {
+			return events.SessionCompleted
+		}
+	}
+	if r.clock != nil && r.clock.expired {
+		return events.SessionExpired
+	}
+	return events.SessionStopped
+}
+
+// findScene returns the scene with the given ID, or nil.
+func (r *Runtime) findScene(sceneID string) *Scene {
+	for i := range r.graph.Scenes {
+		if r.graph.Scenes[i].ID == sceneID {
+			return &r.graph.Scenes[i]
+		}
+	}
+	return nil
+}
+
+// ActiveSceneID returns the ID of the running scene, or "" if no game is active.
+func (r *Runtime) ActiveSceneID() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.activeScene == nil {
+		return ""
+	}
+	return r.activeScene.ID
+}
+
 // IsGameActive returns true if a game is currently running.
 func (r *Runtime) IsGameActive() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	return r.activeScene != nil
 }
 
+// InMaintenance returns true while the room is closed to games for maintenance.
+func (r *Runtime) InMaintenance() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.mode == ModeMaintenance
+}
+
+// setMaintenance enters maintenance mode for a routine run, or returns to the
+// mode the room was in before. Entering fails while a game is in progress.
+func (r *Runtime) setMaintenance(on bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !on {
+		r.changeMode(r.modeBefore, "maintenance")
+		return nil
+	}
+	if r.activeScene != nil {
+		return fmt.Errorf("game in progress")
+	}
+	r.modeBefore = r.mode
+	r.changeMode(ModeMaintenance, "maintenance")
+	return nil
+}
+
 // resetState clears all runtime state.
 func (r *Runtime) resetState() {
+	r.cancelAllScheduled()
+	r.clock = nil
+	r.randomChoices = make(map[string]string)
+	r.checkpoints = make(map[string]*Checkpoint)
+	r.lastCheckpoint = ""
+	r.violations = make(map[string]bool)
+	r.vars = make(map[string]interface{})
+	r.executedActions = make(map[string]bool)
+	r.undoStack = nil
+	r.attempts = make(map[string]int)
+	r.hintsGiven = make(map[string]int)
+	r.cooldowns = make(map[string]time.Time)
+	r.paused = false
+	r.pausedAt = time.Time{}
+	r.video.startedAt = time.Time{}
 	r.activeScene = nil
 	r.nodeStates = make(map[string]*NodeStatus)
 	r.puzzleStates = make(map[string]*PuzzleStatus)
 	r.puzzleRuntimes = make(map[string]*PuzzleRuntime)
+	r.subgraphs = make(map[string]*PuzzleRuntime)
 }
 
-// SetActionExecutor sets the action executor for device commands.
+// SetActionExecutor sets the action executor for device commands. Commands
+// are only logged while the run mode dry-runs actions.
 func (r *Runtime) SetActionExecutor(executor ActionExecutorInterface) {
-	r.actionExecutor = executor
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.actionExecutor = r.GatedExecutor(executor)
 }
 
 // ResetToNode resets the runtime to resume execution from the specified node.
 // This is a runtime checkpoint reset, NOT a startup restore.
 // It clears all downstream state and re-activates the target node.
 func (r *Runtime) ResetToNode(nodeID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	if r.activeScene == nil {
 		return fmt.Errorf("no active session")
 	}
@@ -449,6 +1012,8 @@ func (r *Runtime) ResetToNode(nodeID string) error {
 		return fmt.Errorf("node not found: %s", nodeID)
 	}
 
+	r.pushUndo("reset_to_node", nodeID)
+
 	// Find all nodes reachable from the target node (downstream)
 	downstream := r.findDownstreamNodes(nodeID)
 
@@ -457,7 +1022,7 @@ func (r *Runtime) ResetToNode(nodeID string) error {
 
 	// Reset all downstream nodes
 	for nid := range downstream {
-		r.resetNodeState(nid)
+		r.resetNodeState(nid, "")
 	}
 
 	// Re-activate the target node to resume execution
@@ -466,6 +1031,27 @@ func (r *Runtime) ResetToNode(nodeID string) error {
 	return nil
 }
 
+// resetDownstream returns every non-idle node downstream of nodeID to idle,
+// in scene order, and returns their IDs.
+func (r *Runtime) resetDownstream(nodeID string) []string {
+	downstream := r.findDownstreamNodes(nodeID)
+
+	reset := []string{}
+	for _, node := range r.activeScene.Nodes {
+		if node.ID == nodeID || !downstream[node.ID] {
+			continue
+		}
+		if status := r.nodeStates[node.ID]; status == nil || status.State == NodeStateIdle {
+			continue
+		}
+		reset = append(reset, node.ID)
+	}
+	for _, id := range reset {
+		r.resetNodeState(id, nodeID)
+	}
+	return reset
+}
+
 // findDownstreamNodes returns all nodes reachable via edges from the given node.
 func (r *Runtime) findDownstreamNodes(startID string) map[string]bool {
 	downstream := make(map[string]bool)
@@ -508,8 +1094,9 @@ func (r *Runtime) findDownstreamNodes(startID string) map[string]bool {
 	return downstream
 }
 
-// resetNodeState resets a single node's state and emits appropriate events.
-func (r *Runtime) resetNodeState(nodeID string) {
+// resetNodeState returns a node to idle. cascadeFrom names the node whose reset
+// caused this one, if any.
+func (r *Runtime) resetNodeState(nodeID, cascadeFrom string) {
 	node := r.findNode(nodeID)
 	if node == nil {
 		return
@@ -525,11 +1112,28 @@ func (r *Runtime) resetNodeState(nodeID string) {
 		return
 	}
 
-	// For active loops, emit loop.stopped
+	// For active loops, stop ticking and emit loop.stopped
 	if node.Type == "loop" && status.State == NodeStateActive {
+		r.stopLoop(nodeID)
 		r.emitEvent("loop.stopped", map[string]interface{}{"node_id": nodeID})
 	}
 
+	// For active timers, stop the countdown
+	if node.Type == "timer" {
+		r.cancelTimer(nodeID, "operator_reset")
+	}
+
+	// For subgraph nodes, drop the running sequence so it restarts from its entry
+	delete(r.subgraphs, nodeID)
+
+	// Drop pending delayed actions of the node or its subgraph
+	r.cancelActions(nodeID)
+
+	// For random nodes, forget the choice so the branch is drawn again
+	if node.Type == "random" {
+		delete(r.randomChoices, nodeID)
+	}
+
 	// For puzzle nodes, clear puzzle state and runtime
 	if node.Type == "puzzle" {
 		if ps, ok := r.puzzleStates[nodeID]; ok {
@@ -537,10 +1141,16 @@ func (r *Runtime) resetNodeState(nodeID string) {
 		}
 		// Remove puzzle runtime to allow fresh re-execution
 		delete(r.puzzleRuntimes, nodeID)
+		r.stopPuzzleLimits(nodeID)
+		r.startCooldown(nodeID)
 		r.emitEvent("puzzle.reset", map[string]interface{}{"node_id": nodeID})
 	}
 
 	// Reset node to idle
 	status.State = NodeStateIdle
-	r.emitEvent("node.reset", map[string]interface{}{"node_id": nodeID})
+	fields := map[string]interface{}{"node_id": nodeID}
+	if cascadeFrom != "" {
+		fields["cascade_from"] = cascadeFrom
+	}
+	r.emitEvent("node.reset", fields)
 }
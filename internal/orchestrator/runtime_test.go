@@ -6,6 +6,54 @@ import (
 	"github.com/AaronLay10/SentientEngine/internal/events"
 )
 
+// sceneGraph returns a graph holding a single scene.
+func sceneGraph(scene Scene) *SceneGraph {
+	return &SceneGraph{Version: 1, Scenes: []Scene{scene}}
+}
+
+// puzzleScene returns a scene whose entry puzzle runs subgraph subgraphID:
+// node, then a terminal named done.
+func puzzleScene(sceneID, puzzleID, subgraphID string, node Node) Scene {
+	return Scene{
+		ID:    sceneID,
+		Entry: puzzleID,
+		Nodes: []Node{
+			{ID: puzzleID, Type: "puzzle", Config: map[string]interface{}{"subgraph": subgraphID}},
+		},
+		Subgraphs: []Subgraph{
+			{
+				ID:    subgraphID,
+				Entry: node.ID,
+				Nodes: []Node{node, {ID: "done", Type: "terminal"}},
+				Edges: []Edge{{From: node.ID, To: "done"}},
+			},
+		},
+	}
+}
+
+// startGame checks sg and starts a game in sceneID with no buffered events,
+// stopping it when the test ends. setup, if set, configures the runtime
+// before the game starts.
+func startGame(t *testing.T, sg *SceneGraph, sceneID string, setup func(rt *Runtime)) *Runtime {
+	t.Helper()
+	events.Clear()
+	if err := ValidateConditions(sg); err != nil {
+		t.Fatalf("invalid scene graph: %v", err)
+	}
+	rt := NewRuntime(sg)
+	if setup != nil {
+		setup(rt)
+	}
+	if err := rt.StartGame(sceneID); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = rt.StopGame()
+		events.Clear()
+	})
+	return rt
+}
+
 func TestLoadSceneGraph(t *testing.T) {
 	sg, err := LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
 	if err != nil {
@@ -296,6 +344,48 @@ func TestConditionEvaluator(t *testing.T) {
 	}
 }
 
+func TestConditionOrNotGrouping(t *testing.T) {
+	ctx := &EvalContext{PuzzleStates: map[string]*PuzzleStatus{
+		"puzzle_a": {NodeID: "puzzle_a", Resolution: PuzzleUnresolved},
+		"puzzle_b": {NodeID: "puzzle_b", Resolution: PuzzleSolved},
+		"puzzle_c": {NodeID: "puzzle_c", Resolution: PuzzleUnresolved},
+	}}
+
+	cases := []struct {
+		expr string
+		want bool
+	}{
+		{"puzzle_a.resolved || puzzle_b.resolved", true},
+		{"puzzle_a.resolved || puzzle_c.resolved", false},
+		{"!puzzle_a.resolved", true},
+		{"! puzzle_b.resolved", false},
+		{"(puzzle_a.resolved || puzzle_b.resolved) && !puzzle_c.resolved", true},
+		{"(puzzle_a.resolved || puzzle_b.resolved) && puzzle_c.resolved", false},
+		{"puzzle_a.resolved || puzzle_b.resolved && puzzle_c.resolved", false},
+		{"puzzle_b.resolved || puzzle_a.resolved && puzzle_c.resolved", true},
+		{"!(puzzle_a.resolved || puzzle_c.resolved)", true},
+		{"((puzzle_b.resolved))", true},
+		{"(puzzle_a.resolved) || (puzzle_b.resolved)", true},
+	}
+	for _, tc := range cases {
+		if got := EvalCondition(tc.expr, ctx); got != tc.want {
+			t.Errorf("EvalCondition(%q) = %v, want %v", tc.expr, got, tc.want)
+		}
+	}
+
+	// Subgraph edges evaluate event conditions the same way
+	eventCtx := &EvalContext{Event: &Event{
+		Name:   "device.input",
+		Fields: map[string]interface{}{"logical_id": "lever", "payload": map[string]interface{}{"signal": "up || down"}},
+	}}
+	if !EvalCondition("event == 'device.input' && (logical_id == 'dial' || logical_id == 'lever')", eventCtx) {
+		t.Error("expected grouped OR on event fields to match")
+	}
+	if !EvalCondition("payload.signal == 'up || down'", eventCtx) {
+		t.Error("expected operators inside quotes to be treated as text")
+	}
+}
+
 // TestNestedFieldEvaluation tests nested payload field matching for device.input
 func TestNestedFieldEvaluation(t *testing.T) {
 	// Test device.input with nested payload
@@ -987,3 +1077,106 @@ func TestResetToNodeCanReExecute(t *testing.T) {
 		t.Errorf("expected puzzle_scarab node to be completed after re-execution, got %v", rt.GetNodeState("puzzle_scarab"))
 	}
 }
+
+// cascadeSceneGraph chains puzzle_a -> after -> finale so resets can cascade.
+func cascadeSceneGraph() *SceneGraph {
+	return sceneGraph(Scene{
+		ID:    "scene_cascade",
+		Entry: "puzzle_a",
+		Nodes: []Node{
+			{ID: "puzzle_a", Type: "puzzle", Config: map[string]interface{}{"subgraph": "sub_a"}},
+			{ID: "after", Type: "action", Config: map[string]interface{}{}},
+			{ID: "finale", Type: "operator", Config: map[string]interface{}{}},
+		},
+		Edges: []Edge{
+			{From: "puzzle_a", To: "after", Condition: "puzzle_a.resolved"},
+			{From: "after", To: "finale"},
+		},
+		Subgraphs: []Subgraph{
+			{ID: "sub_a", Entry: "a_wait", Nodes: []Node{{ID: "a_wait", Type: "decision"}}},
+		},
+	})
+}
+
+// TestResetNodeCascade verifies downstream nodes return to idle and the cascade is reported.
+func TestResetNodeCascade(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(cascadeSceneGraph())
+	if err := rt.StartScene("scene_cascade"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+	if err := rt.OverrideNode("puzzle_a"); err != nil {
+		t.Fatalf("override failed: %v", err)
+	}
+	if rt.GetNodeState("finale") != NodeStateActive {
+		t.Fatalf("expected finale active, got %s", rt.GetNodeState("finale"))
+	}
+
+	if err := rt.ResetNodeCascade("puzzle_a", true); err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+
+	if rt.GetNodeState("puzzle_a") != NodeStateActive {
+		t.Errorf("expected puzzle_a active, got %s", rt.GetNodeState("puzzle_a"))
+	}
+	for _, id := range []string{"after", "finale"} {
+		if rt.GetNodeState(id) != NodeStateIdle {
+			t.Errorf("expected %s idle after cascade, got %s", id, rt.GetNodeState(id))
+		}
+	}
+
+	var cascade []string
+	cascadeFrom := 0
+	for _, e := range events.Snapshot() {
+		if e.Name != "node.reset" {
+			continue
+		}
+		if e.Fields["node_id"] == "puzzle_a" {
+			cascade, _ = e.Fields["cascade"].([]string)
+		}
+		if e.Fields["cascade_from"] == "puzzle_a" {
+			cascadeFrom++
+		}
+	}
+	if len(cascade) != 2 || cascade[0] != "after" || cascade[1] != "finale" {
+		t.Errorf("expected cascade [after finale], got %v", cascade)
+	}
+	if cascadeFrom != 2 {
+		t.Errorf("expected 2 node.reset events with cascade_from, got %d", cascadeFrom)
+	}
+
+	// Solving again runs the downstream nodes again
+	if err := rt.OverrideNode("puzzle_a"); err != nil {
+		t.Fatalf("override failed: %v", err)
+	}
+	if rt.GetNodeState("finale") != NodeStateActive {
+		t.Errorf("expected finale active again, got %s", rt.GetNodeState("finale"))
+	}
+}
+
+// TestResetNodeCascadeDefault verifies ResetNode follows the configured default.
+func TestResetNodeCascadeDefault(t *testing.T) {
+	for _, cascade := range []bool{false, true} {
+		events.Clear()
+		rt := NewRuntime(cascadeSceneGraph())
+		rt.SetCascadeReset(cascade)
+		if err := rt.StartScene("scene_cascade"); err != nil {
+			t.Fatalf("failed to start scene: %v", err)
+		}
+		if err := rt.OverrideNode("puzzle_a"); err != nil {
+			t.Fatalf("override failed: %v", err)
+		}
+
+		if err := rt.ResetNode("puzzle_a"); err != nil {
+			t.Fatalf("reset failed: %v", err)
+		}
+
+		want := NodeStateActive
+		if cascade {
+			want = NodeStateIdle
+		}
+		if got := rt.GetNodeState("finale"); got != want {
+			t.Errorf("cascade=%v: expected finale %s, got %s", cascade, want, got)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package orchestrator
+
+import (
+	"fmt"
+	"time"
+)
+
+// sceneTransitionKey is the scheduler key for a pending scene transition.
+const sceneTransitionKey = "scene:transition"
+
+// Reasons reported by scene.advanced.
+const (
+	AdvanceTransition = "transition"
+	AdvanceOperator   = "operator"
+)
+
+// nextTransition returns the transition taken from a completed scene, or nil.
+// Caller must hold r.mu.
+func (r *Runtime) nextTransition(sceneID string) *SceneTransition {
+	ctx := r.evalContext()
+	for i := range r.graph.Transitions {
+		t := &r.graph.Transitions[i]
+		if t.From == sceneID && EvalCondition(t.Condition, ctx) {
+			return t
+		}
+	}
+	return nil
+}
+
+// chainScene schedules the transition out of the active scene once it
+// completes. The switch always runs from the scheduler, never from inside the
+// node activation that completed the scene.
+// Caller must hold r.mu.
+func (r *Runtime) chainScene() {
+	t := r.nextTransition(r.activeScene.ID)
+	if t == nil {
+		return
+	}
+	from := r.activeScene.ID
+	r.schedule(sceneTransitionKey, time.Duration(t.DelayMs)*time.Millisecond, func() {
+		if r.activeScene == nil || r.activeScene.ID != from {
+			return
+		}
+		_ = r.switchScene(t.To, "", AdvanceTransition)
+	})
+}
+
+// AdvanceScene moves the running game to another scene (operator override).
+// Like a transition (see Scene Transitions in design/scene-graph/schema.md) it
+// keeps the session, countdown and variables, emits scene.advanced instead of
+// scene.started and drops everything tied to the previous scene's nodes.
+func (r *Runtime) AdvanceScene(sceneID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.activeScene == nil {
+		return fmt.Errorf("no active game")
+	}
+	return r.switchScene(sceneID, "", AdvanceOperator)
+}
+
+// switchScene replaces the active scene within the running session and starts
+// it at startID, or at its entry node when startID is empty.
+// Caller must hold r.mu.
+func (r *Runtime) switchScene(sceneID, startID, reason string) error {
+	scene := r.findScene(sceneID)
+	if scene == nil {
+		return fmt.Errorf("scene not found: %s", sceneID)
+	}
+	if startID != "" && !sceneHasNode(scene, startID) {
+		return fmt.Errorf("node not found in scene %s: %s", sceneID, startID)
+	}
+	from := r.activeScene.ID
+
+	// Timers report their cancellation; other scene tasks stop silently
+	for _, node := range r.activeScene.Nodes {
+		if node.Type == "timer" {
+			r.cancelTimer(node.ID, "scene_advanced")
+		}
+	}
+	r.cancelSceneScheduled()
+
+	r.nodeStates = make(map[string]*NodeStatus)
+	r.puzzleStates = make(map[string]*PuzzleStatus)
+	r.puzzleRuntimes = make(map[string]*PuzzleRuntime)
+	r.subgraphs = make(map[string]*PuzzleRuntime)
+	r.randomChoices = make(map[string]string)
+	r.checkpoints = make(map[string]*Checkpoint)
+	r.lastCheckpoint = ""
+	r.violations = make(map[string]bool)
+	r.executedActions = make(map[string]bool)
+	r.undoStack = nil
+	r.attempts = make(map[string]int)
+	r.hintsGiven = make(map[string]int)
+	r.cooldowns = make(map[string]time.Time)
+
+	r.activeScene = scene
+	r.initSceneNodes()
+
+	r.emitEvent("scene.advanced", map[string]interface{}{
+		"scene_id": sceneID,
+		"from":     from,
+		"reason":   reason,
+	})
+	if startID == "" || startID == scene.Entry {
+		r.activateNode(scene.Entry)
+	} else {
+		r.jumpToNode(startID)
+	}
+	return nil
+}
+
+// cancelSceneScheduled stops every pending task except the session
+// countdown and timeline sync.
+// Caller must hold r.mu.
+func (r *Runtime) cancelSceneScheduled() {
+	for key := range r.tasks {
+		if key != gameClockKey && key != videoSyncKey {
+			r.cancelScheduled(key)
+		}
+	}
+}
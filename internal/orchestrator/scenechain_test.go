@@ -0,0 +1,170 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// chainedSceneGraph is an intro that marks the team ready, a main scene with a button
+// puzzle and a finale, chained with transitions.
+func chainedSceneGraph() *SceneGraph {
+	main := counterSceneGraph(1).Scenes[0]
+	main.ID = "scene_main"
+	main.Nodes = append(main.Nodes, Node{ID: "main_done", Type: "terminal"})
+	main.Edges = append(main.Edges, Edge{From: "puzzle_presses", To: "main_done"})
+
+	return &SceneGraph{
+		Version: 1,
+		Scenes: []Scene{
+			{
+				ID:    "scene_intro",
+				Entry: "welcome",
+				Nodes: []Node{
+					{ID: "welcome", Type: "action", Config: map[string]interface{}{
+						"action": SetVariableAction,
+						"params": map[string]interface{}{"name": "team_ready", "value": true},
+					}},
+					{ID: "intro_done", Type: "terminal"},
+				},
+				Edges: []Edge{{From: "welcome", To: "intro_done"}},
+			},
+			main,
+			{
+				ID:    "scene_finale",
+				Entry: "the_end",
+				Nodes: []Node{{ID: "the_end", Type: "terminal"}},
+			},
+		},
+		Transitions: []SceneTransition{
+			{From: "scene_intro", To: "scene_main", DelayMs: 3000},
+			{From: "scene_main", To: "scene_finale", Condition: "puzzle_presses.resolved && vars.team_ready"},
+		},
+	}
+}
+
+func TestSceneTransitions(t *testing.T) {
+	rt := startGame(t, chainedSceneGraph(), "scene_intro", nil)
+
+	if rt.ActiveSceneID() != "scene_intro" || !scheduled(rt, sceneTransitionKey) {
+		t.Fatalf("expected a pending transition out of the completed intro, scene %s", rt.ActiveSceneID())
+	}
+	fire(t, rt, sceneTransitionKey)
+	if rt.ActiveSceneID() != "scene_main" {
+		t.Fatalf("expected scene_main after the transition, got %s", rt.ActiveSceneID())
+	}
+	if rt.GetPuzzleResolution("puzzle_presses") != PuzzleUnresolved {
+		t.Fatal("expected main scene puzzle active")
+	}
+
+	press(rt, "button")
+	fire(t, rt, sceneTransitionKey)
+	if rt.ActiveSceneID() != "scene_finale" {
+		t.Fatalf("expected scene_finale once main completed, got %s", rt.ActiveSceneID())
+	}
+	if scheduled(rt, sceneTransitionKey) {
+		t.Error("expected no transition out of the finale")
+	}
+
+	if n := countNamed("scene.started"); n != 1 {
+		t.Errorf("expected 1 scene.started for the session, got %d", n)
+	}
+	if n := countNamed("scene.advanced"); n != 2 {
+		t.Errorf("expected 2 scene.advanced, got %d", n)
+	}
+	if n := countNamed("scene.completed"); n != 3 {
+		t.Errorf("expected 3 scene.completed, got %d", n)
+	}
+}
+
+func TestSceneTransitionCondition(t *testing.T) {
+	rt := startGame(t, chainedSceneGraph(), "scene_intro", nil)
+	fire(t, rt, sceneTransitionKey)
+
+	// Overriding resolves the puzzle, but without vars.team_ready no transition matches
+	rt.mu.Lock()
+	delete(rt.vars, "team_ready")
+	rt.mu.Unlock()
+	if err := rt.OverrideNode("puzzle_presses"); err != nil {
+		t.Fatalf("override failed: %v", err)
+	}
+	if scheduled(rt, sceneTransitionKey) || rt.ActiveSceneID() != "scene_main" {
+		t.Errorf("expected the game to end in scene_main, got %s", rt.ActiveSceneID())
+	}
+}
+
+func TestAdvanceScene(t *testing.T) {
+	rt := startGame(t, chainedSceneGraph(), "scene_intro", nil)
+
+	if err := rt.AdvanceScene("scene_missing"); err == nil {
+		t.Error("expected error for unknown scene")
+	}
+	if err := rt.AdvanceScene("scene_main"); err != nil {
+		t.Fatalf("advance failed: %v", err)
+	}
+	if rt.ActiveSceneID() != "scene_main" {
+		t.Fatalf("expected scene_main, got %s", rt.ActiveSceneID())
+	}
+	if scheduled(rt, sceneTransitionKey) {
+		t.Error("expected the intro's pending transition cancelled")
+	}
+	if !scheduled(rt, gameClockKey) {
+		t.Error("expected the session countdown to keep running")
+	}
+	if !rt.vars["team_ready"].(bool) {
+		t.Error("expected session variables to carry over")
+	}
+
+	var reason interface{}
+	for _, e := range events.Snapshot() {
+		if e.Name == "scene.advanced" {
+			reason = e.Fields["reason"]
+		}
+	}
+	if reason != AdvanceOperator {
+		t.Errorf("expected reason %s, got %v", AdvanceOperator, reason)
+	}
+}
+
+func TestRestoreChainedScene(t *testing.T) {
+	rt := startGame(t, chainedSceneGraph(), "scene_intro", nil)
+	fire(t, rt, sceneTransitionKey)
+
+	restored := restartFromLog(t, chainedSceneGraph())
+	if restored.ActiveSceneID() != "scene_main" {
+		t.Fatalf("expected restore into scene_main, got %s", restored.ActiveSceneID())
+	}
+	if restored.vars["team_ready"] != true {
+		t.Error("expected variables set in the intro to be restored")
+	}
+}
+
+func TestRestorePendingTransition(t *testing.T) {
+	rt := startGame(t, chainedSceneGraph(), "scene_intro", nil)
+	fire(t, rt, sceneTransitionKey)
+	press(rt, "button")
+
+	// Main completed but its transition had not run when the orchestrator stopped
+	restored := restartFromLog(t, chainedSceneGraph())
+	if !scheduled(restored, sceneTransitionKey) {
+		t.Fatal("expected the pending transition scheduled again")
+	}
+	fire(t, restored, sceneTransitionKey)
+	if restored.ActiveSceneID() != "scene_finale" {
+		t.Errorf("expected scene_finale, got %s", restored.ActiveSceneID())
+	}
+}
+
+func TestValidateTransitions(t *testing.T) {
+	for name, transition := range map[string]SceneTransition{
+		"unknown scene":  {From: "scene_intro", To: "scene_missing"},
+		"bad condition":  {From: "scene_intro", To: "scene_main", Condition: "vars.x =="},
+		"negative delay": {From: "scene_intro", To: "scene_main", DelayMs: -1},
+	} {
+		sg := chainedSceneGraph()
+		sg.Transitions = append(sg.Transitions, transition)
+		if err := ValidateConditions(sg); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// scheduledTask is a pending callback on the runtime's central clock.
+type scheduledTask struct {
+	timer     *time.Timer
+	fireAt    time.Time
+	remaining time.Duration // time left when the game was paused
+	fn        func()
+}
+
+// stop stops the task's timer, if it has one (not on a manual clock).
+func (t *scheduledTask) stop() {
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+}
+
+// schedule runs fn after d while holding the runtime lock.
+// Scheduling a key that is already pending replaces the earlier task.
+// While the game is paused the task waits for resume (see pause.go).
+// Caller must hold r.mu.
+func (r *Runtime) schedule(key string, d time.Duration, fn func()) {
+	r.cancelScheduled(key)
+
+	task := &scheduledTask{fn: fn}
+	r.armScheduled(key, task, d)
+	if r.paused {
+		task.stop()
+		task.remaining = d
+	}
+	r.tasks[key] = task
+}
+
+// armScheduled starts the timer of a task due after d. On a manual clock
+// the task waits for advanceClock instead.
+// Caller must hold r.mu.
+func (r *Runtime) armScheduled(key string, task *scheduledTask, d time.Duration) {
+	task.fireAt = r.now().Add(d)
+	if r.manualClock {
+		return
+	}
+	task.timer = time.AfterFunc(d, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.paused {
+			// Paused after the timer fired but before the lock was free;
+			// the task was kept with no time left and runs on resume
+			return
+		}
+		r.runScheduled(key, task)
+	})
+}
+
+// pauseScheduled stops every pending task, keeping the time each had left.
+// Caller must hold r.mu.
+func (r *Runtime) pauseScheduled() {
+	for _, task := range r.tasks {
+		task.stop()
+		task.remaining = max(task.fireAt.Sub(r.now()), 0)
+	}
+}
+
+// resumeScheduled restarts tasks stopped by pauseScheduled with the time
+// they had left.
+// Caller must hold r.mu.
+func (r *Runtime) resumeScheduled() {
+	for key, task := range r.tasks {
+		r.armScheduled(key, task, task.remaining)
+		task.remaining = 0
+	}
+}
+
+// runScheduled executes a task if it is still the pending task for key.
+// Caller must hold r.mu.
+func (r *Runtime) runScheduled(key string, task *scheduledTask) bool {
+	if r.tasks[key] != task {
+		// Cancelled or replaced after the clock fired
+		return false
+	}
+	delete(r.tasks, key)
+	task.stop()
+	task.fn()
+	return true
+}
+
+// fireScheduled runs a pending task immediately instead of waiting for its deadline.
+// Caller must hold r.mu.
+func (r *Runtime) fireScheduled(key string) bool {
+	task, ok := r.tasks[key]
+	if !ok {
+		return false
+	}
+	return r.runScheduled(key, task)
+}
+
+// cancelScheduled stops a pending task. Returns true if a task was pending.
+// Caller must hold r.mu.
+func (r *Runtime) cancelScheduled(key string) bool {
+	task, ok := r.tasks[key]
+	if !ok {
+		return false
+	}
+	task.stop()
+	delete(r.tasks, key)
+	return true
+}
+
+// cancelAllScheduled stops every pending task.
+// Caller must hold r.mu.
+func (r *Runtime) cancelAllScheduled() {
+	for key, task := range r.tasks {
+		task.stop()
+		delete(r.tasks, key)
+	}
+}
+
+// scheduledRemaining returns the time left before a pending task fires.
+// Caller must hold r.mu.
+func (r *Runtime) scheduledRemaining(key string) (time.Duration, bool) {
+	task, ok := r.tasks[key]
+	if !ok {
+		return 0, false
+	}
+	if r.paused {
+		return task.remaining, true
+	}
+	remaining := task.fireAt.Sub(r.now())
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, true
+}
+
+// cancelScheduledPrefix stops every pending task whose key starts with prefix.
+// Caller must hold r.mu.
+func (r *Runtime) cancelScheduledPrefix(prefix string) {
+	for key, task := range r.tasks {
+		if strings.HasPrefix(key, prefix) {
+			task.stop()
+			delete(r.tasks, key)
+		}
+	}
+}
+
+// useManualClock stops the runtime following real time: from start, time
+// moves only when advanceClock is called. Room tests use it to play hours of
+// timers in an instant. Must be called before the runtime is used.
+func (r *Runtime) useManualClock(start time.Time) {
+	r.manualClock = true
+	r.clockTime = start
+	r.now = func() time.Time { return r.clockTime }
+}
+
+// maxClockSteps bounds the tasks one advanceClock may run, so a task that
+// keeps rescheduling itself without delay cannot hang it.
+const maxClockSteps = 100000
+
+// advanceClock moves a manual clock forward by d, running pending tasks in
+// the order they fall due. Tasks wait while the game is paused.
+// Caller must hold r.mu.
+func (r *Runtime) advanceClock(d time.Duration) error {
+	end := r.clockTime.Add(d)
+	for steps := 0; !r.paused; steps++ {
+		if steps == maxClockSteps {
+			return fmt.Errorf("more than %d scheduled tasks due within %v", maxClockSteps, d)
+		}
+		var nextKey string
+		var next *scheduledTask
+		for key, task := range r.tasks {
+			if task.fireAt.After(end) {
+				continue
+			}
+			// Ties go by key so runs are repeatable
+			if next == nil || task.fireAt.Before(next.fireAt) ||
+				(task.fireAt.Equal(next.fireAt) && key < nextKey) {
+				nextKey, next = key, task
+			}
+		}
+		if next == nil {
+			break
+		}
+		if next.fireAt.After(r.clockTime) {
+			r.clockTime = next.fireAt
+		}
+		r.runScheduled(nextKey, next)
+	}
+	r.clockTime = end
+	return nil
+}
@@ -0,0 +1,248 @@
+package orchestrator
+
+import (
+	"sort"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// Every game session is scored so venues can print results and compare teams.
+// The runtime tallies the running session as it plays; finished sessions are
+// rebuilt from their persisted events the same way.
+//
+// A session scores 100 points per puzzle the players solved and 500 for
+// completing the game, minus 25 per hint and 50 per failed puzzle, and never
+// less than zero. Overridden puzzles score nothing. Teams with the same score
+// are ranked by completion time.
+const (
+	scorePerPuzzle      = 100
+	scoreCompletion     = 500
+	scoreHintPenalty    = 25
+	scoreFailurePenalty = 50
+)
+
+// SessionReport is the post-game summary of one session.
+type SessionReport struct {
+	SessionID         string         `json:"session_id"`
+	SceneID           string         `json:"scene_id"` // first scene
+	Scenes            []string       `json:"scenes"`   // every scene played, in order
+	StartedAt         time.Time      `json:"started_at"`
+	EndedAt           *time.Time     `json:"ended_at,omitempty"`
+	Result            string         `json:"result,omitempty"` // completed, expired or stopped once ended
+	Completed         bool           `json:"completed"`
+	CompletionTimeSec *float64       `json:"completion_time_sec,omitempty"`
+	DurationSec       float64        `json:"duration_sec"`
+	PuzzlesSolved     int            `json:"puzzles_solved"`
+	PuzzlesOverridden int            `json:"puzzles_overridden"`
+	Hints             int            `json:"hints"`
+	Failures          int            `json:"failures"` // puzzles failed
+	Score             int            `json:"score"`
+	Puzzles           []PuzzleResult `json:"puzzles"`
+
+	scene       string                   // scene being played
+	expired     bool                     // the game clock ran out
+	puzzles     map[string]*PuzzleResult // node_id -> result
+	activatedAt map[string]time.Time     // unresolved puzzle node_id -> activation
+}
+
+// PuzzleResult is how one puzzle went in a session.
+type PuzzleResult struct {
+	NodeID       string           `json:"node_id"`
+	SceneID      string           `json:"scene_id"`
+	Resolution   PuzzleResolution `json:"resolution"`
+	SolveTimeSec *float64         `json:"solve_time_sec,omitempty"`
+	Hints        int              `json:"hints"`
+}
+
+// newSessionReport starts an empty report for a session.
+func newSessionReport(sessionID string, startedAt time.Time) *SessionReport {
+	return &SessionReport{
+		SessionID:   sessionID,
+		StartedAt:   startedAt,
+		Scenes:      []string{},
+		puzzles:     make(map[string]*PuzzleResult),
+		activatedAt: make(map[string]time.Time),
+	}
+}
+
+// puzzle returns the result of a puzzle, adding it if it is new.
+func (s *SessionReport) puzzle(nodeID string) *PuzzleResult {
+	p, ok := s.puzzles[nodeID]
+	if !ok {
+		p = &PuzzleResult{NodeID: nodeID, SceneID: s.scene, Resolution: PuzzleUnresolved}
+		s.puzzles[nodeID] = p
+	}
+	return p
+}
+
+// record folds one event of the session into the report.
+func (s *SessionReport) record(name string, fields map[string]interface{}, at time.Time) {
+	nodeID := extractNodeID(fields)
+	switch name {
+	case "scene.started", "scene.advanced":
+		s.scene = stringField(fields, "scene_id")
+		if s.SceneID == "" {
+			s.SceneID = s.scene
+		}
+		s.Scenes = append(s.Scenes, s.scene)
+		s.Completed = false
+		s.CompletionTimeSec = nil
+	case "scene.completed":
+		s.Completed = true
+		completion := at.Sub(s.StartedAt).Seconds()
+		s.CompletionTimeSec = &completion
+	case "puzzle.activated":
+		s.puzzle(nodeID)
+		s.activatedAt[nodeID] = at
+	case "puzzle.solved":
+		p := s.puzzle(nodeID)
+		p.Resolution = PuzzleSolved
+		if activated, ok := s.activatedAt[nodeID]; ok {
+			solve := at.Sub(activated).Seconds()
+			p.SolveTimeSec = &solve
+		}
+		delete(s.activatedAt, nodeID)
+	case "puzzle.overridden":
+		s.puzzle(nodeID).Resolution = PuzzleOverridden
+		delete(s.activatedAt, nodeID)
+	case "puzzle.failed":
+		s.puzzle(nodeID).Resolution = PuzzleFailed
+		delete(s.activatedAt, nodeID)
+	case "puzzle.reset":
+		s.puzzle(nodeID).Resolution = PuzzleUnresolved
+		delete(s.activatedAt, nodeID)
+	case "puzzle.hint":
+		s.puzzle(nodeID).Hints++
+	case "timer.expired":
+		if stringField(fields, "timer_id") == GameClockID {
+			s.expired = true
+		}
+	}
+}
+
+// end closes the report with the session's result.
+func (s *SessionReport) end(at time.Time, result string) {
+	ended := at
+	s.EndedAt = &ended
+	s.Result = result
+}
+
+// summary returns a copy of the report with totals and score computed as of now.
+func (s *SessionReport) summary(now time.Time) *SessionReport {
+	out := *s
+	out.Scenes = append([]string{}, s.Scenes...)
+	out.Puzzles = make([]PuzzleResult, 0, len(s.puzzles))
+	out.PuzzlesSolved, out.PuzzlesOverridden, out.Failures, out.Hints = 0, 0, 0, 0
+	for _, p := range s.puzzles {
+		out.Puzzles = append(out.Puzzles, *p)
+		switch p.Resolution {
+		case PuzzleSolved:
+			out.PuzzlesSolved++
+		case PuzzleOverridden:
+			out.PuzzlesOverridden++
+		case PuzzleFailed:
+			out.Failures++
+		}
+		out.Hints += p.Hints
+	}
+	sort.Slice(out.Puzzles, func(i, j int) bool {
+		return out.Puzzles[i].NodeID < out.Puzzles[j].NodeID
+	})
+
+	end := now
+	if s.EndedAt != nil {
+		end = *s.EndedAt
+	}
+	out.DurationSec = end.Sub(s.StartedAt).Seconds()
+
+	score := scorePerPuzzle*out.PuzzlesSolved - scoreHintPenalty*out.Hints - scoreFailurePenalty*out.Failures
+	if out.Completed {
+		score += scoreCompletion
+	}
+	out.Score = max(score, 0)
+	return &out
+}
+
+// BuildSessionReport rebuilds the summary of one session from its persisted
+// events (any order). It returns nil if the events do not include the
+// session's scene.started.
+func BuildSessionReport(sessionID string, rows []storage.EventRow) *SessionReport {
+	sorted := append([]storage.EventRow(nil), rows...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Timestamp.Equal(sorted[j].Timestamp) {
+			return sorted[i].EventID < sorted[j].EventID
+		}
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	report, last := buildSessionReport(sessionID, sorted)
+	if report == nil {
+		return nil
+	}
+	return report.summary(last)
+}
+
+// buildSessionReport folds a session's chronologically ordered events into a
+// report and returns it with the time of its last event.
+func buildSessionReport(sessionID string, rows []storage.EventRow) (*SessionReport, time.Time) {
+	var report *SessionReport
+	var last time.Time
+	for _, row := range rows {
+		if row.SessionID == nil || *row.SessionID != sessionID {
+			continue
+		}
+		if report == nil {
+			if row.Event != "scene.started" {
+				continue
+			}
+			report = newSessionReport(sessionID, row.Timestamp)
+		}
+		report.record(row.Event, row.Fields, row.Timestamp)
+		last = row.Timestamp
+		if row.Event == "scene.reset" {
+			report.end(row.Timestamp, report.result())
+		}
+	}
+	return report, last
+}
+
+// result reports how the session ended, like Runtime.sessionResult.
+func (s *SessionReport) result() string {
+	switch {
+	case s.Completed:
+		return events.SessionCompleted
+	case s.expired:
+		return events.SessionExpired
+	}
+	return events.SessionStopped
+}
+
+// SessionReport returns the summary of the running or most recently finished
+// session with the given ID, or nil if the runtime did not play it.
+func (r *Runtime) SessionReport(sessionID string) *SessionReport {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.report == nil || r.report.SessionID != sessionID {
+		return nil
+	}
+	return r.report.summary(r.now())
+}
+
+// tally records an event in the running session's report.
+// Caller must hold r.mu.
+func (r *Runtime) tally(name string, fields map[string]interface{}) {
+	if r.report != nil && r.report.EndedAt == nil {
+		r.report.record(name, fields, r.now())
+	}
+}
+
+// endReport closes the running session's report.
+// Caller must hold r.mu.
+func (r *Runtime) endReport(result string) {
+	if r.report != nil && r.report.EndedAt == nil {
+		r.report.end(r.now(), result)
+	}
+}
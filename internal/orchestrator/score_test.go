@@ -0,0 +1,100 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+func TestRuntimeSessionReport(t *testing.T) {
+	rt := startGame(t, hintSceneGraph(0), "scene_counter", func(rt *Runtime) {
+		rt.SetActionExecutor(&replyingExecutor{})
+	})
+	sessionID := events.CurrentSession()
+
+	if _, err := rt.GiveHint("puzzle_presses", -1); err != nil {
+		t.Fatalf("hint failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		press(rt, "button")
+	}
+
+	report := rt.SessionReport(sessionID)
+	if report == nil {
+		t.Fatal("expected a report for the running session")
+	}
+	if report.SceneID != "scene_counter" || report.EndedAt != nil || report.Result != "" {
+		t.Errorf("unexpected running report: %+v", report)
+	}
+	if report.PuzzlesSolved != 1 || report.Hints != 1 || report.Score != scorePerPuzzle-scoreHintPenalty {
+		t.Errorf("expected one solve and one hint, got %+v", report)
+	}
+	if len(report.Puzzles) != 1 || report.Puzzles[0].Resolution != PuzzleSolved || report.Puzzles[0].SolveTimeSec == nil {
+		t.Errorf("unexpected puzzle results: %+v", report.Puzzles)
+	}
+
+	if err := rt.StopGame(); err != nil {
+		t.Fatalf("stop failed: %v", err)
+	}
+	report = rt.SessionReport(sessionID)
+	if report == nil || report.EndedAt == nil || report.Result != events.SessionStopped {
+		t.Errorf("expected the finished session's report, got %+v", report)
+	}
+	if rt.SessionReport("other") != nil {
+		t.Error("expected no report for a session the runtime did not play")
+	}
+}
+
+func TestBuildSessionReport(t *testing.T) {
+	base := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	at := func(sec int) time.Time { return base.Add(time.Duration(sec) * time.Second) }
+	session, other := "s1", "s2"
+	row := func(id int64, sec int, name string, sid *string, fields map[string]interface{}) storage.EventRow {
+		return storage.EventRow{EventID: id, Timestamp: at(sec), Event: name, SessionID: sid, Fields: fields}
+	}
+	node := func(id string) map[string]interface{} { return map[string]interface{}{"node_id": id} }
+
+	// Rows out of order to exercise sorting
+	rows := []storage.EventRow{
+		row(9, 900, "scene.reset", &session, nil),
+		row(1, 0, "scene.started", &session, map[string]interface{}{"scene_id": "scene_intro"}),
+		row(2, 10, "puzzle.activated", &session, node("vault")),
+		row(3, 70, "puzzle.hint", &session, node("vault")),
+		row(4, 130, "puzzle.solved", &session, node("vault")),
+		row(5, 140, "puzzle.activated", &session, node("keypad")),
+		row(6, 300, "puzzle.failed", &session, node("keypad")),
+		row(7, 310, "puzzle.overridden", &session, node("altar")),
+		row(8, 600, "scene.completed", &session, nil),
+		// Another session's events are ignored
+		row(10, 1000, "scene.started", &other, map[string]interface{}{"scene_id": "scene_intro"}),
+		row(11, 1010, "puzzle.solved", &other, node("vault")),
+	}
+
+	report := BuildSessionReport(session, rows)
+	if report == nil {
+		t.Fatal("expected a report")
+	}
+	if !report.Completed || report.Result != events.SessionCompleted || report.DurationSec != 900 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if report.CompletionTimeSec == nil || *report.CompletionTimeSec != 600 {
+		t.Errorf("expected completion after 600s, got %v", report.CompletionTimeSec)
+	}
+	if report.PuzzlesSolved != 1 || report.PuzzlesOverridden != 1 || report.Failures != 1 || report.Hints != 1 {
+		t.Errorf("unexpected totals: %+v", report)
+	}
+	if want := scorePerPuzzle + scoreCompletion - scoreHintPenalty - scoreFailurePenalty; report.Score != want {
+		t.Errorf("expected score %d, got %d", want, report.Score)
+	}
+	for _, p := range report.Puzzles {
+		if p.NodeID == "vault" && (p.SolveTimeSec == nil || *p.SolveTimeSec != 120) {
+			t.Errorf("expected vault solved in 120s, got %v", p.SolveTimeSec)
+		}
+	}
+
+	if BuildSessionReport("missing", rows) != nil {
+		t.Error("expected nil for a session without events")
+	}
+}
@@ -0,0 +1,98 @@
+package orchestrator
+
+import (
+	"sort"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// SessionEventNames lists the persisted events session summaries read.
+var SessionEventNames = []string{
+	"scene.started",
+	"scene.advanced",
+	"scene.completed",
+	"scene.reset",
+	"puzzle.solved",
+	"puzzle.overridden",
+	"puzzle.hint",
+	"operator.override",
+}
+
+// SessionSummary describes one game session reconstructed from events.
+type SessionSummary struct {
+	SceneID           string     `json:"scene_id"`
+	StartedAt         time.Time  `json:"started_at"`
+	EndedAt           *time.Time `json:"ended_at,omitempty"`
+	DurationSec       float64    `json:"duration_sec"`
+	Completed         bool       `json:"completed"`
+	PuzzlesSolved     int        `json:"puzzles_solved"`
+	PuzzlesOverridden int        `json:"puzzles_overridden"`
+	Overrides         int        `json:"overrides"`
+	Hints             int        `json:"hints"`
+}
+
+// BuildSessionSummaries reconstructs sessions from persisted events (any order),
+// oldest first. A session runs from scene.started to scene.reset; a session
+// that was never reset ends at the next scene.started or is still running.
+// A chained session is completed only if its last scene completed.
+func BuildSessionSummaries(rows []storage.EventRow) []SessionSummary {
+	sorted := append([]storage.EventRow(nil), rows...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Timestamp.Equal(sorted[j].Timestamp) {
+			return sorted[i].EventID < sorted[j].EventID
+		}
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	sessions := []SessionSummary{}
+	current := -1
+
+	end := func(at time.Time) {
+		if current < 0 {
+			return
+		}
+		s := &sessions[current]
+		ended := at
+		s.EndedAt = &ended
+		s.DurationSec = at.Sub(s.StartedAt).Seconds()
+		current = -1
+	}
+
+	for _, row := range sorted {
+		switch row.Event {
+		case "scene.started":
+			end(row.Timestamp)
+			sessions = append(sessions, SessionSummary{
+				SceneID:   stringField(row.Fields, "scene_id"),
+				StartedAt: row.Timestamp,
+			})
+			current = len(sessions) - 1
+			continue
+		case "scene.reset":
+			end(row.Timestamp)
+			continue
+		}
+
+		if current < 0 {
+			continue
+		}
+		s := &sessions[current]
+		switch row.Event {
+		case "scene.completed":
+			s.Completed = true
+		case "scene.advanced":
+			s.Completed = false
+		case "puzzle.solved":
+			s.PuzzlesSolved++
+		case "puzzle.overridden":
+			s.PuzzlesOverridden++
+		case "puzzle.hint":
+			s.Hints++
+		case "operator.override":
+			s.Overrides++
+		}
+	}
+
+	return sessions
+}
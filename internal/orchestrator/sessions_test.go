@@ -0,0 +1,47 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+func TestBuildSessionSummaries(t *testing.T) {
+	base := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	at := func(min int) time.Time { return base.Add(time.Duration(min) * time.Minute) }
+
+	// Rows out of order to exercise sorting
+	rows := []storage.EventRow{
+		{EventID: 5, Timestamp: at(50), Event: "scene.reset"},
+		{EventID: 1, Timestamp: at(0), Event: "scene.started", Fields: map[string]interface{}{"scene_id": "scene_intro"}},
+		{EventID: 2, Timestamp: at(10), Event: "puzzle.hint"},
+		{EventID: 3, Timestamp: at(20), Event: "puzzle.solved"},
+		{EventID: 4, Timestamp: at(45), Event: "scene.completed"},
+		// Events outside any session are ignored
+		{EventID: 6, Timestamp: at(55), Event: "puzzle.solved"},
+		{EventID: 7, Timestamp: at(60), Event: "scene.started", Fields: map[string]interface{}{"scene_id": "scene_intro"}},
+		{EventID: 8, Timestamp: at(70), Event: "operator.override"},
+		// A chained scene continues the session; completing the intro does not complete it
+		{EventID: 9, Timestamp: at(75), Event: "scene.completed"},
+		{EventID: 10, Timestamp: at(76), Event: "scene.advanced", Fields: map[string]interface{}{"scene_id": "scene_main"}},
+	}
+
+	sessions := BuildSessionSummaries(rows)
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %+v", sessions)
+	}
+
+	first := sessions[0]
+	if first.SceneID != "scene_intro" || !first.Completed || first.DurationSec != 3000 {
+		t.Errorf("unexpected first session: %+v", first)
+	}
+	if first.PuzzlesSolved != 1 || first.Hints != 1 {
+		t.Errorf("expected one solve and one hint, got %+v", first)
+	}
+
+	second := sessions[1]
+	if second.EndedAt != nil || second.Completed || second.Overrides != 1 {
+		t.Errorf("expected running incomplete session with one override, got %+v", second)
+	}
+}
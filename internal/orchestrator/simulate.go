@@ -0,0 +1,242 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultSimulationRuns is the number of simulated games when none is given.
+	DefaultSimulationRuns = 1000
+	// MaxSimulationRuns bounds the cost of one simulation.
+	MaxSimulationRuns = 20000
+	// defaultSolveSpread is the p90/median ratio assumed when only a median is known.
+	defaultSolveSpread = 1.5
+	// z90 is the standard normal quantile at p90.
+	z90 = 1.2815515655446004
+)
+
+// Solve time sources reported per puzzle.
+const (
+	SolveTimeAnnotation = "annotation"
+	SolveTimeHistory    = "history"
+	SolveTimeDefault    = "default"
+)
+
+// ErrNoSolveTimes is returned when no puzzle has an annotation or solve history.
+var ErrNoSolveTimes = errors.New("no puzzle has expected_solve_sec or solve history")
+
+// SimulationOptions configures SimulateDuration.
+type SimulationOptions struct {
+	SceneID string            // "" for the first scene
+	Runs    int               // 0 for DefaultSimulationRuns
+	Seed    int64             // same seed, same result
+	Target  time.Duration     // game length to hit; 0 to skip
+	History *DifficultyReport // may be nil
+}
+
+// DurationSimulation summarizes simulated game lengths for one scene.
+type DurationSimulation struct {
+	SceneID     string           `json:"scene_id"`
+	Runs        int              `json:"runs"`
+	MeanMs      int64            `json:"mean_ms"`
+	StdDevMs    int64            `json:"stddev_ms"`
+	P10Ms       int64            `json:"p10_ms"`
+	MedianMs    int64            `json:"median_ms"`
+	P90Ms       int64            `json:"p90_ms"`
+	TargetMs    int64            `json:"target_ms,omitempty"`
+	OnTargetPct *float64         `json:"on_target_pct,omitempty"` // share of games finished within the target
+	Puzzles     []PuzzleEstimate `json:"puzzles"`
+}
+
+// PuzzleEstimate is the solve time distribution used for one puzzle.
+type PuzzleEstimate struct {
+	NodeID      string  `json:"node_id"`
+	Source      string  `json:"source"`
+	MedianMs    int64   `json:"median_ms"`
+	P90Ms       int64   `json:"p90_ms"`
+	CriticalPct float64 `json:"critical_pct"` // share of games in which the puzzle was on the critical path
+}
+
+// SimulateDuration estimates how long a scene takes to play by playing it
+// many times, using the dependency model of AnalyzeSceneGraph. Puzzle solve
+// times are drawn log-normally from each puzzle's expected_solve_sec, else
+// its historical solve times, else the average known median.
+func SimulateDuration(sg *SceneGraph, opts SimulationOptions) (*DurationSimulation, error) {
+	var scene *Scene
+	for i := range sg.Scenes {
+		if opts.SceneID == "" || sg.Scenes[i].ID == opts.SceneID {
+			scene = &sg.Scenes[i]
+			break
+		}
+	}
+	if scene == nil {
+		if opts.SceneID == "" {
+			return nil, fmt.Errorf("no scenes available")
+		}
+		return nil, fmt.Errorf("scene not found: %s", opts.SceneID)
+	}
+
+	runs := opts.Runs
+	if runs <= 0 {
+		runs = DefaultSimulationRuns
+	}
+	if runs > MaxSimulationRuns {
+		runs = MaxSimulationRuns
+	}
+
+	estimates, err := puzzleEstimates(scene, opts.History)
+	if err != nil {
+		return nil, err
+	}
+
+	// Log-normal parameters per puzzle
+	type dist struct{ mu, sigma float64 }
+	dists := make(map[string]dist, len(estimates))
+	for _, est := range estimates {
+		d := dist{mu: math.Log(float64(est.MedianMs))}
+		if est.P90Ms > est.MedianMs {
+			d.sigma = math.Log(float64(est.P90Ms)/float64(est.MedianMs)) / z90
+		}
+		dists[est.NodeID] = d
+	}
+
+	deps := buildSceneDeps(scene)
+	rng := rand.New(rand.NewSource(opts.Seed))
+	durations := make([]float64, 0, runs)
+	critical := make(map[string]int)
+	for i := 0; i < runs; i++ {
+		plan := deps.schedule(func(node *Node) int64 {
+			switch node.Type {
+			case "puzzle":
+				d := dists[node.ID]
+				return int64(math.Round(math.Exp(d.mu + d.sigma*rng.NormFloat64())))
+			case "timer":
+				if ms, ok := configInt(node.Config, "duration_ms"); ok && ms > 0 {
+					return int64(ms)
+				}
+			}
+			return 0
+		})
+		durations = append(durations, float64(plan.endFinish))
+		for _, id := range plan.criticalPath() {
+			if deps.nodes[id].Type == "puzzle" {
+				critical[id]++
+			}
+		}
+	}
+
+	sim := &DurationSimulation{SceneID: scene.ID, Runs: runs, Puzzles: estimates}
+	var sum float64
+	for _, d := range durations {
+		sum += d
+	}
+	mean := sum / float64(runs)
+	var sq float64
+	for _, d := range durations {
+		sq += (d - mean) * (d - mean)
+	}
+	sort.Float64s(durations)
+	sim.MeanMs = int64(mean)
+	sim.StdDevMs = int64(math.Sqrt(sq / float64(runs)))
+	sim.P10Ms = int64(percentile(durations, 0.1))
+	sim.MedianMs = int64(percentile(durations, 0.5))
+	sim.P90Ms = int64(percentile(durations, 0.9))
+
+	if opts.Target > 0 {
+		target := float64(opts.Target.Milliseconds())
+		within := sort.Search(len(durations), func(i int) bool { return durations[i] > target })
+		pct := percentOf(within, runs)
+		sim.TargetMs = opts.Target.Milliseconds()
+		sim.OnTargetPct = &pct
+	}
+	for i := range sim.Puzzles {
+		sim.Puzzles[i].CriticalPct = percentOf(critical[sim.Puzzles[i].NodeID], runs)
+	}
+	return sim, nil
+}
+
+// puzzleEstimates picks the solve time distribution of every puzzle in scene order.
+func puzzleEstimates(scene *Scene, history *DifficultyReport) ([]PuzzleEstimate, error) {
+	historical := make(map[string]*Percentiles)
+	if history != nil {
+		for _, pd := range history.Puzzles {
+			if pd.SolveTimeSec != nil && pd.SolveTimeSec.Median > 0 {
+				historical[pd.NodeID] = pd.SolveTimeSec
+			}
+		}
+	}
+
+	var estimates []PuzzleEstimate
+	var known []int
+	for _, node := range scene.Nodes {
+		if node.Type != "puzzle" {
+			continue
+		}
+		est := PuzzleEstimate{NodeID: node.ID, Source: SolveTimeDefault}
+		if median, p90, ok := solveAnnotation(node.Config); ok {
+			est.Source = SolveTimeAnnotation
+			est.MedianMs, est.P90Ms = secToMs(median), secToMs(p90)
+		} else if pct, ok := historical[node.ID]; ok {
+			est.Source = SolveTimeHistory
+			est.MedianMs, est.P90Ms = secToMs(pct.Median), secToMs(pct.P90)
+		}
+		if est.Source != SolveTimeDefault {
+			if est.P90Ms < est.MedianMs {
+				est.P90Ms = est.MedianMs
+			}
+			known = append(known, len(estimates))
+		}
+		estimates = append(estimates, est)
+	}
+	if len(estimates) == 0 {
+		return []PuzzleEstimate{}, nil
+	}
+	if len(known) == 0 {
+		return nil, ErrNoSolveTimes
+	}
+
+	var total int64
+	for _, i := range known {
+		total += estimates[i].MedianMs
+	}
+	fallback := total / int64(len(known))
+	for i := range estimates {
+		if estimates[i].Source == SolveTimeDefault {
+			estimates[i].MedianMs = fallback
+			estimates[i].P90Ms = int64(float64(fallback) * defaultSolveSpread)
+		}
+	}
+	return estimates, nil
+}
+
+// solveAnnotation reads expected_solve_sec as a median or {median, p90}.
+func solveAnnotation(config map[string]interface{}) (median, p90 float64, ok bool) {
+	switch v := config["expected_solve_sec"].(type) {
+	case float64:
+		median = v
+	case map[string]interface{}:
+		median, _ = v["median"].(float64)
+		p90, _ = v["p90"].(float64)
+	}
+	if median <= 0 {
+		return 0, 0, false
+	}
+	if p90 <= 0 {
+		p90 = median * defaultSolveSpread
+	}
+	return median, p90, true
+}
+
+func secToMs(sec float64) int64 {
+	return int64(sec * 1000)
+}
+
+// percentOf returns n/total as a percentage with one decimal.
+func percentOf(n, total int) float64 {
+	return math.Round(float64(n)/float64(total)*1000) / 10
+}
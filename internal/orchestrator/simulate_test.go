@@ -0,0 +1,71 @@
+package orchestrator
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSimulateDuration(t *testing.T) {
+	sg, err := LoadSceneGraph("../../design/scene-graph/examples/mvp-scene-graph.v1.json")
+	if err != nil {
+		t.Fatalf("failed to load scene graph: %v", err)
+	}
+
+	if _, err := SimulateDuration(sg, SimulationOptions{}); !errors.Is(err, ErrNoSolveTimes) {
+		t.Errorf("expected ErrNoSolveTimes without estimates, got %v", err)
+	}
+	if _, err := SimulateDuration(sg, SimulationOptions{SceneID: "scene_missing"}); err == nil {
+		t.Error("expected error for unknown scene")
+	}
+
+	// Fixed solve times: the scene takes as long as the slower parallel puzzle
+	sg.Scenes[0].Nodes[1].Config["expected_solve_sec"] = map[string]interface{}{"median": 300.0, "p90": 300.0}
+	history := &DifficultyReport{Puzzles: []PuzzleDifficulty{
+		{NodeID: "puzzle_tiles", SolveTimeSec: &Percentiles{Count: 20, Median: 600, P90: 600}},
+	}}
+	sim, err := SimulateDuration(sg, SimulationOptions{Runs: 50, Target: 60 * time.Minute, History: history})
+	if err != nil {
+		t.Fatalf("simulation failed: %v", err)
+	}
+	if sim.MedianMs != 600000 || sim.P10Ms != 600000 || sim.P90Ms != 600000 || sim.StdDevMs != 0 {
+		t.Errorf("expected a fixed 10 minutes, got %+v", sim)
+	}
+	if sim.OnTargetPct == nil || *sim.OnTargetPct != 100 {
+		t.Errorf("expected every game on target, got %v", sim.OnTargetPct)
+	}
+	scarab, tiles := sim.Puzzles[0], sim.Puzzles[1]
+	if scarab.Source != SolveTimeAnnotation || tiles.Source != SolveTimeHistory {
+		t.Errorf("unexpected sources: %s / %s", scarab.Source, tiles.Source)
+	}
+	if tiles.CriticalPct != 100 || scarab.CriticalPct != 0 {
+		t.Errorf("expected puzzle_tiles always critical, got %v / %v", tiles.CriticalPct, scarab.CriticalPct)
+	}
+
+	// A bare median gets the default spread; a puzzle without data gets the average median
+	sg.Scenes[0].Nodes[1].Config["expected_solve_sec"] = 600.0
+	opts := SimulationOptions{Runs: 500, Seed: 7, Target: 12 * time.Minute}
+	sim, err = SimulateDuration(sg, opts)
+	if err != nil {
+		t.Fatalf("simulation failed: %v", err)
+	}
+	tiles = sim.Puzzles[1]
+	if tiles.Source != SolveTimeDefault || tiles.MedianMs != 600000 || tiles.P90Ms != 900000 {
+		t.Errorf("unexpected default estimate: %+v", tiles)
+	}
+	if !(sim.P10Ms < sim.MedianMs && sim.MedianMs < sim.P90Ms) || sim.StdDevMs == 0 {
+		t.Errorf("expected a spread of durations, got %+v", sim)
+	}
+	// The later of two draws is longer than either median
+	if sim.MedianMs <= 600000 {
+		t.Errorf("expected median above 10 minutes, got %dms", sim.MedianMs)
+	}
+	if sim.OnTargetPct == nil || *sim.OnTargetPct <= 0 || *sim.OnTargetPct >= 100 {
+		t.Errorf("expected some games over a 12 minute target, got %v", sim.OnTargetPct)
+	}
+
+	again, _ := SimulateDuration(sg, opts)
+	if again.MeanMs != sim.MeanMs || again.P90Ms != sim.P90Ms {
+		t.Error("expected the same seed to give the same result")
+	}
+}
@@ -0,0 +1,161 @@
+package orchestrator
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// RuntimeSnapshot is the complete live state of the runtime, so dashboards
+// can show the current session without replaying events.
+type RuntimeSnapshot struct {
+	GameActive       bool                   `json:"game_active"`
+	Paused           bool                   `json:"paused"`
+	Mode             RunMode                `json:"mode"`
+	Maintenance      bool                   `json:"maintenance"`
+	SceneID          string                 `json:"scene_id,omitempty"`
+	SceneStartedAt   *time.Time             `json:"scene_started_at,omitempty"`
+	SceneCompletedAt *time.Time             `json:"scene_completed_at,omitempty"`
+	Clock            ClockState             `json:"clock"`
+	Nodes            []NodeSnapshot         `json:"nodes"`
+	Puzzles          []PuzzleSnapshot       `json:"puzzles"`
+	Timers           []TimerSnapshot        `json:"timers"`
+	PendingApprovals []string               `json:"pending_approvals"`
+	LastCheckpoint   string                 `json:"last_checkpoint,omitempty"`
+	RandomChoices    map[string]string      `json:"random_choices"`
+	Variables        map[string]interface{} `json:"variables"`
+}
+
+// NodeSnapshot is one node of the active scene.
+type NodeSnapshot struct {
+	ID          string     `json:"id"`
+	Type        string     `json:"type"`
+	State       NodeState  `json:"state"`
+	ActivatedAt *time.Time `json:"activated_at,omitempty"`
+}
+
+// PuzzleSnapshot is one puzzle node's resolution.
+type PuzzleSnapshot struct {
+	NodeID     string           `json:"node_id"`
+	Resolution PuzzleResolution `json:"resolution"`
+	Attempts   int              `json:"attempts,omitempty"`
+	Hints      int              `json:"hints,omitempty"` // hints given
+}
+
+// TimerSnapshot is a pending scheduled task: a timer node, delayed action,
+// loop tick, puzzle timeout, the session countdown or the next timeline sync.
+type TimerSnapshot struct {
+	Kind        string `json:"kind"`
+	ID          string `json:"id"`
+	RemainingMs int64  `json:"remaining_ms"`
+}
+
+// Snapshot returns the runtime's current state. Lists are sorted so repeated
+// calls are stable.
+func (r *Runtime) Snapshot() RuntimeSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snap := RuntimeSnapshot{
+		GameActive:       r.activeScene != nil,
+		Paused:           r.paused,
+		Mode:             r.mode,
+		Maintenance:      r.mode == ModeMaintenance,
+		Clock:            r.clockState(),
+		Nodes:            []NodeSnapshot{},
+		Puzzles:          []PuzzleSnapshot{},
+		Timers:           []TimerSnapshot{},
+		PendingApprovals: []string{},
+		LastCheckpoint:   r.lastCheckpoint,
+		RandomChoices:    make(map[string]string, len(r.randomChoices)),
+		Variables:        copyVars(r.vars),
+	}
+	for id, choice := range r.randomChoices {
+		snap.RandomChoices[id] = choice
+	}
+
+	if r.activeScene == nil {
+		return snap
+	}
+
+	snap.SceneID = r.activeScene.ID
+	if !r.sceneStartedAt.IsZero() {
+		startedAt := r.sceneStartedAt
+		snap.SceneStartedAt = &startedAt
+	}
+
+	// Nodes in scene order
+	for _, node := range r.activeScene.Nodes {
+		ns := NodeSnapshot{ID: node.ID, Type: node.Type, State: NodeStateIdle}
+		if status, ok := r.nodeStates[node.ID]; ok {
+			ns.State = status.State
+			if !status.ActivatedAt.IsZero() {
+				activatedAt := status.ActivatedAt
+				ns.ActivatedAt = &activatedAt
+			}
+		}
+		snap.Nodes = append(snap.Nodes, ns)
+		if node.Type == "terminal" && ns.State == NodeStateCompleted && ns.ActivatedAt != nil && snap.SceneCompletedAt == nil {
+			snap.SceneCompletedAt = ns.ActivatedAt
+		}
+		if node.Type == "operator" && ns.State == NodeStateActive {
+			snap.PendingApprovals = append(snap.PendingApprovals, node.ID)
+		}
+	}
+
+	for id, ps := range r.puzzleStates {
+		snap.Puzzles = append(snap.Puzzles, PuzzleSnapshot{
+			NodeID:     id,
+			Resolution: ps.Resolution,
+			Attempts:   r.attempts[id],
+			Hints:      r.hintsGiven[id],
+		})
+	}
+	sort.Slice(snap.Puzzles, func(i, j int) bool { return snap.Puzzles[i].NodeID < snap.Puzzles[j].NodeID })
+
+	for key := range r.tasks {
+		remaining, _ := r.scheduledRemaining(key)
+		kind, id, _ := strings.Cut(key, ":")
+		snap.Timers = append(snap.Timers, TimerSnapshot{
+			Kind:        kind,
+			ID:          id,
+			RemainingMs: remaining.Milliseconds(),
+		})
+	}
+	sort.Slice(snap.Timers, func(i, j int) bool {
+		if snap.Timers[i].Kind != snap.Timers[j].Kind {
+			return snap.Timers[i].Kind < snap.Timers[j].Kind
+		}
+		return snap.Timers[i].ID < snap.Timers[j].ID
+	})
+
+	return snap
+}
+
+// ProgressPct is the share of the scene's puzzles that have ended (solved,
+// overridden or failed), from 0 to 100. Scenes without puzzles count ended nodes.
+func (s RuntimeSnapshot) ProgressPct() int {
+	if !s.GameActive {
+		return 0
+	}
+
+	done, total := 0, len(s.Puzzles)
+	if total > 0 {
+		for _, p := range s.Puzzles {
+			if p.Resolution != PuzzleUnresolved {
+				done++
+			}
+		}
+	} else {
+		total = len(s.Nodes)
+		for _, n := range s.Nodes {
+			if n.State == NodeStateCompleted || n.State == NodeStateOverridden || n.State == NodeStateFailed {
+				done++
+			}
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return done * 100 / total
+}
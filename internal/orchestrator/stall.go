@@ -0,0 +1,57 @@
+package orchestrator
+
+import (
+	"sort"
+	"time"
+)
+
+// stallKey is the scheduler key of the stall watchdog.
+const stallKey = "stall"
+
+// SetStallTimeout sets how long a game may go without progress before it is
+// reported as stalled. 0 disables stall detection. Progress is a node starting
+// or completing, or device input matching a condition an active puzzle waits
+// on; fiddling with the wrong prop does not count, and paused time is not
+// counted.
+func (r *Runtime) SetStallTimeout(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stallTimeout = d
+	if d <= 0 {
+		r.cancelScheduled(stallKey)
+	}
+}
+
+// noteProgress restarts the stall watchdog.
+// Caller must hold r.mu.
+func (r *Runtime) noteProgress() {
+	if r.stallTimeout <= 0 || r.activeScene == nil {
+		return
+	}
+	sceneID := r.activeScene.ID
+	idle := r.stallTimeout
+	r.schedule(stallKey, idle, func() {
+		if r.activeScene == nil || r.activeScene.ID != sceneID {
+			return
+		}
+		r.emitEvent("scene.stalled", map[string]interface{}{
+			"scene_id": sceneID,
+			"idle_ms":  idle.Milliseconds(),
+			"puzzles":  r.waitingPuzzles(),
+		})
+	})
+}
+
+// waitingPuzzles returns the IDs of the puzzles still waiting for input.
+// Caller must hold r.mu.
+func (r *Runtime) waitingPuzzles() []string {
+	ids := []string{}
+	for nodeID, pr := range r.puzzleRuntimes {
+		if !pr.Done() {
+			ids = append(ids, nodeID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,102 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// stallTask returns the pending stall watchdog, or nil.
+func stallTask(rt *Runtime) *scheduledTask {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.tasks[stallKey]
+}
+
+func TestStallWatchdog(t *testing.T) {
+	rt := startGame(t, counterSceneGraph(3), "scene_counter", func(rt *Runtime) {
+		rt.SetStallTimeout(10 * time.Minute)
+	})
+
+	armed := stallTask(rt)
+	if armed == nil {
+		t.Fatal("expected the watchdog armed once the game starts")
+	}
+	press(rt, "lever")
+	if stallTask(rt) != armed {
+		t.Error("expected input no puzzle waits on not to count as progress")
+	}
+	press(rt, "button")
+	if stallTask(rt) == armed {
+		t.Error("expected matching input to restart the watchdog")
+	}
+
+	fire(t, rt, stallKey)
+	var stalled []events.Event
+	for _, e := range events.Snapshot() {
+		if e.Name == "scene.stalled" {
+			stalled = append(stalled, e)
+		}
+	}
+	if len(stalled) != 1 {
+		t.Fatalf("expected one scene.stalled, got %d", len(stalled))
+	}
+	fields := stalled[0].Fields
+	if fields["scene_id"] != "scene_counter" || fields["idle_ms"] != int64(600000) {
+		t.Errorf("unexpected scene.stalled fields: %v", fields)
+	}
+	if puzzles, _ := fields["puzzles"].([]string); len(puzzles) != 1 || puzzles[0] != "puzzle_presses" {
+		t.Errorf("expected the waiting puzzle reported, got %v", fields["puzzles"])
+	}
+	if scheduled(rt, stallKey) {
+		t.Error("expected one report per stall")
+	}
+
+	press(rt, "button")
+	if !scheduled(rt, stallKey) {
+		t.Error("expected progress to re-arm the watchdog")
+	}
+}
+
+func TestStallWatchdogStopsWhenSceneCompletes(t *testing.T) {
+	events.Clear()
+	defer events.Clear()
+	sg := counterSceneGraph(1)
+	scene := &sg.Scenes[0]
+	scene.Nodes = append(scene.Nodes, Node{ID: "end", Type: "terminal"})
+	scene.Edges = append(scene.Edges, Edge{From: "puzzle_presses", To: "end"})
+	rt := NewRuntime(sg)
+	rt.SetStallTimeout(10 * time.Minute)
+	if err := rt.StartGame("scene_counter"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	defer rt.StopGame()
+
+	press(rt, "button")
+	if countEvents("node.completed", "end") != 1 {
+		t.Fatal("expected the scene to complete")
+	}
+	if scheduled(rt, stallKey) {
+		t.Error("expected no watchdog once the scene completed")
+	}
+}
+
+func TestStallWatchdogDisabled(t *testing.T) {
+	rt := startGame(t, counterSceneGraph(3), "scene_counter", func(rt *Runtime) {
+		rt.SetStallTimeout(0)
+	})
+	if scheduled(rt, stallKey) {
+		t.Error("expected no watchdog without a stall timeout")
+	}
+
+	rt.SetStallTimeout(time.Minute)
+	press(rt, "button")
+	if !scheduled(rt, stallKey) {
+		t.Fatal("expected the watchdog armed after enabling it")
+	}
+	rt.SetStallTimeout(0)
+	if scheduled(rt, stallKey) {
+		t.Error("expected disabling to cancel the watchdog")
+	}
+}
@@ -1,5 +1,7 @@
 package orchestrator
 
+import "time"
+
 // NodeState represents the lifecycle state of a node.
 type NodeState string
 
@@ -13,17 +15,19 @@ const (
 
 // NodeStatus tracks the runtime state of a node.
 type NodeStatus struct {
-	NodeID string
-	State  NodeState
+	NodeID      string
+	State       NodeState
+	ActivatedAt time.Time
 }
 
 // PuzzleResolution indicates how a puzzle was resolved.
 type PuzzleResolution string
 
 const (
-	PuzzleUnresolved  PuzzleResolution = "unresolved"
-	PuzzleSolved      PuzzleResolution = "solved"
-	PuzzleOverridden  PuzzleResolution = "overridden"
+	PuzzleUnresolved PuzzleResolution = "unresolved"
+	PuzzleSolved     PuzzleResolution = "solved"
+	PuzzleOverridden PuzzleResolution = "overridden"
+	PuzzleFailed     PuzzleResolution = "failed"
 )
 
 // PuzzleStatus tracks the resolution state of a puzzle node.
@@ -36,3 +40,8 @@ type PuzzleStatus struct {
 func (ps *PuzzleStatus) IsResolved() bool {
 	return ps.Resolution == PuzzleSolved || ps.Resolution == PuzzleOverridden
 }
+
+// IsFailed returns true if the puzzle timed out or ran out of attempts.
+func (ps *PuzzleStatus) IsFailed() bool {
+	return ps.Resolution == PuzzleFailed
+}
@@ -0,0 +1,122 @@
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// subgraphSceneGraph builds a scene that runs a lighting show, then a reset
+// routine that waits for a device before finishing.
+func subgraphSceneGraph() *SceneGraph {
+	return sceneGraph(Scene{
+		ID:    "scene_sub",
+		Entry: "show",
+		Nodes: []Node{
+			{ID: "show", Type: "subgraph", Config: map[string]interface{}{"subgraph": "lighting_show"}},
+			{ID: "reset_props", Type: "subgraph", Config: map[string]interface{}{"subgraph": "reset_routine"}},
+			{ID: "done", Type: "terminal", Config: map[string]interface{}{}},
+		},
+		Edges: []Edge{
+			{From: "show", To: "reset_props"},
+			{From: "reset_props", To: "done"},
+		},
+		Subgraphs: []Subgraph{
+			{
+				ID:    "lighting_show",
+				Entry: "lights_on",
+				Nodes: []Node{
+					{ID: "lights_on", Type: "action", Config: map[string]interface{}{"action": "device.command"}},
+					{ID: "show_done", Type: "terminal", Config: map[string]interface{}{}},
+				},
+				Edges: []Edge{{From: "lights_on", To: "show_done"}},
+			},
+			{
+				ID:    "reset_routine",
+				Entry: "wait_door",
+				Nodes: []Node{
+					{ID: "wait_door", Type: "decision"},
+					{ID: "reset_done", Type: "terminal", Config: map[string]interface{}{}},
+				},
+				Edges: []Edge{{
+					From:      "wait_door",
+					To:        "reset_done",
+					Condition: "event == 'device.input' && logical_id == 'crypt_door' && payload.signal == 'door_closed'",
+				}},
+			},
+		},
+	})
+}
+
+func TestSubgraphNode(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(subgraphSceneGraph())
+	if err := rt.StartScene("scene_sub"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+
+	// The lighting show is all actions, so it completes immediately
+	if rt.GetNodeState("show") != NodeStateCompleted {
+		t.Fatalf("expected show to complete, got %s", rt.GetNodeState("show"))
+	}
+	if rt.GetNodeState("reset_props") != NodeStateActive {
+		t.Fatalf("expected reset_props to wait, got %s", rt.GetNodeState("reset_props"))
+	}
+
+	rt.InjectEvent("device.input", map[string]interface{}{
+		"logical_id": "crypt_door",
+		"payload":    map[string]interface{}{"signal": "door_open"},
+	})
+	if rt.GetNodeState("reset_props") != NodeStateActive {
+		t.Errorf("expected reset_props to keep waiting, got %s", rt.GetNodeState("reset_props"))
+	}
+
+	rt.InjectEvent("device.input", map[string]interface{}{
+		"logical_id": "crypt_door",
+		"payload":    map[string]interface{}{"signal": "door_closed"},
+	})
+	if rt.GetNodeState("reset_props") != NodeStateCompleted {
+		t.Errorf("expected reset_props to complete, got %s", rt.GetNodeState("reset_props"))
+	}
+	if rt.GetNodeState("done") != NodeStateCompleted {
+		t.Errorf("expected scene to reach terminal, got %s", rt.GetNodeState("done"))
+	}
+
+	// Generic subgraphs never produce puzzle state
+	for _, e := range events.Snapshot() {
+		if strings.HasPrefix(e.Name, "puzzle.") {
+			t.Errorf("unexpected puzzle event from subgraph node: %s", e.Name)
+		}
+	}
+	if len(rt.puzzleStates) != 0 {
+		t.Errorf("expected no puzzle states, got %d", len(rt.puzzleStates))
+	}
+}
+
+func TestSubgraphNodeResetRestarts(t *testing.T) {
+	rt := NewRuntime(subgraphSceneGraph())
+	if err := rt.StartScene("scene_sub"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+
+	if err := rt.ResetNode("reset_props"); err != nil {
+		t.Fatalf("failed to reset: %v", err)
+	}
+	if rt.GetNodeState("reset_props") != NodeStateActive {
+		t.Fatalf("expected reset_props active after reset, got %s", rt.GetNodeState("reset_props"))
+	}
+	if _, ok := rt.subgraphs["reset_props"]; !ok {
+		t.Fatal("expected reset to restart the subgraph")
+	}
+
+	if err := rt.OverrideNode("reset_props"); err != nil {
+		t.Fatalf("failed to override: %v", err)
+	}
+	if len(rt.subgraphs) != 0 {
+		t.Error("expected override to stop the running subgraph")
+	}
+	if rt.GetNodeState("done") != NodeStateCompleted {
+		t.Errorf("expected flow to continue after override, got %s", rt.GetNodeState("done"))
+	}
+}
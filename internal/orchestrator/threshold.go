@@ -0,0 +1,172 @@
+package orchestrator
+
+import (
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// DefaultThresholdField is the payload field read by threshold nodes.
+const DefaultThresholdField = "value"
+
+// thresholdState tracks one threshold node, which completes once an analog
+// sensor (scale, light level, potentiometer) stays in range long enough
+// (config in design/scene-graph/schema.md). Generations invalidate pending
+// hold and leave timers when the value moves.
+type thresholdState struct {
+	inRange  bool
+	held     bool // hold elapsed while a leave was pending
+	leaving  bool // out of range, waiting for debounce_ms
+	holdGen  int
+	leaveGen int
+}
+
+// thresholdBounds reads a threshold node's range and hysteresis.
+func thresholdBounds(node *Node) (min, max float64, hasMin, hasMax bool, hysteresis float64) {
+	min, hasMin = toFloat(node.Config["min"])
+	max, hasMax = toFloat(node.Config["max"])
+	hysteresis, _ = toFloat(node.Config["hysteresis"])
+	return min, max, hasMin, hasMax, hysteresis
+}
+
+// thresholdDuration reads a millisecond config value.
+func thresholdDuration(node *Node, key string) time.Duration {
+	ms, ok := configInt(node.Config, key)
+	if !ok || ms <= 0 {
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// inThreshold reports whether v counts as in range. A value already in range
+// stays in until it passes the bounds by the hysteresis margin.
+func inThreshold(node *Node, v float64, inRange bool) bool {
+	min, max, hasMin, hasMax, hysteresis := thresholdBounds(node)
+	if !inRange {
+		hysteresis = 0
+	}
+	if hasMin && v < min-hysteresis {
+		return false
+	}
+	if hasMax && v > max+hysteresis {
+		return false
+	}
+	return true
+}
+
+// thresholdValue extracts the sensor value from an event payload.
+func thresholdValue(node *Node, evt *Event) (float64, bool) {
+	field, _ := node.Config["field"].(string)
+	if field == "" {
+		field = DefaultThresholdField
+	}
+	if payload, ok := evt.Fields["payload"].(map[string]interface{}); ok {
+		return toFloat(getNestedField(payload, field))
+	}
+	return toFloat(evt.Fields["payload"])
+}
+
+// startThreshold resets a threshold node when it activates.
+func (pr *PuzzleRuntime) startThreshold(node *Node) {
+	if pr.thresholds == nil {
+		pr.thresholds = make(map[string]*thresholdState)
+	}
+	pr.thresholds[node.ID] = &thresholdState{}
+}
+
+// emitThresholdProgress reports a threshold node entering or leaving its range.
+func (pr *PuzzleRuntime) emitThresholdProgress(node *Node, inRange bool, value float64) {
+	events.Emit("info", "puzzle.progress", "", map[string]interface{}{
+		"puzzle_id":   pr.parentNodeID,
+		"subgraph_id": pr.subgraph.ID,
+		"node_id":     node.ID,
+		"in_range":    inRange,
+		"value":       value,
+	})
+}
+
+// handleThreshold applies a sensor reading to an active threshold node.
+// Returns true if the node completes on this reading.
+func (pr *PuzzleRuntime) handleThreshold(node *Node, ctx *EvalContext) bool {
+	match, _ := node.Config["match"].(string)
+	if match == "" || !EvalCondition(match, ctx) {
+		return false
+	}
+	state := pr.thresholds[node.ID]
+	value, ok := thresholdValue(node, ctx.Event)
+	if state == nil || !ok {
+		return false
+	}
+
+	if inThreshold(node, value, state.inRange) {
+		if state.leaving {
+			// Back in range before the debounce ran out
+			state.leaving = false
+			state.leaveGen++
+			return state.held
+		}
+		if state.inRange {
+			return false
+		}
+		state.inRange = true
+		pr.emitThresholdProgress(node, true, value)
+		return pr.startHold(node, state)
+	}
+
+	if !state.inRange || state.leaving {
+		return false
+	}
+	debounce := thresholdDuration(node, "debounce_ms")
+	if debounce == 0 || pr.scheduleFunc == nil {
+		pr.leaveThreshold(node, state, value)
+		return false
+	}
+	state.leaving = true
+	state.leaveGen++
+	gen := state.leaveGen
+	pr.scheduleFunc(node.ID+":leave", debounce, func() {
+		if state.leaving && state.leaveGen == gen && pr.nodeActive(node.ID) {
+			pr.leaveThreshold(node, state, value)
+		}
+	})
+	return false
+}
+
+// startHold arms the hold timer. Returns true if no hold is needed, which is
+// also the case without a scheduler (standalone runtimes).
+func (pr *PuzzleRuntime) startHold(node *Node, state *thresholdState) bool {
+	hold := thresholdDuration(node, "hold_ms")
+	if hold == 0 || pr.scheduleFunc == nil {
+		return true
+	}
+	state.holdGen++
+	gen := state.holdGen
+	pr.scheduleFunc(node.ID, hold, func() {
+		if state.holdGen != gen || !state.inRange || !pr.nodeActive(node.ID) {
+			return
+		}
+		if state.leaving {
+			// Decide when the value returns or the debounce runs out
+			state.held = true
+			return
+		}
+		pr.completeNode(node.ID)
+		pr.advanceFromNode(node.ID)
+	})
+	return false
+}
+
+// leaveThreshold marks the value out of range and abandons the hold.
+func (pr *PuzzleRuntime) leaveThreshold(node *Node, state *thresholdState, value float64) {
+	state.inRange = false
+	state.leaving = false
+	state.held = false
+	state.holdGen++
+	pr.emitThresholdProgress(node, false, value)
+}
+
+// nodeActive reports whether a subgraph node is still waiting.
+func (pr *PuzzleRuntime) nodeActive(nodeID string) bool {
+	status := pr.nodeStates[nodeID]
+	return !pr.Done() && status != nil && status.State == NodeStateActive
+}
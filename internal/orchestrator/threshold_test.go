@@ -0,0 +1,189 @@
+package orchestrator
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// thresholdSceneGraph is an altar solved by holding the right weight on a scale.
+func thresholdSceneGraph(threshold map[string]interface{}) *SceneGraph {
+	config := map[string]interface{}{
+		"match": "event == 'device.input' && logical_id == 'altar_scale'",
+		"field": "grams",
+		"min":   float64(950),
+		"max":   float64(1050),
+	}
+	maps.Copy(config, threshold)
+	return sceneGraph(puzzleScene("scene_altar", "puzzle_altar", "altar", Node{ID: "altar_weight", Type: "threshold", Config: config}))
+}
+
+const (
+	holdKey  = "action:puzzle_altar/altar_weight"
+	leaveKey = "action:puzzle_altar/altar_weight:leave"
+)
+
+func weigh(rt *Runtime, grams float64) {
+	rt.InjectEvent("device.input", map[string]interface{}{
+		"logical_id": "altar_scale",
+		"payload":    map[string]interface{}{"grams": grams},
+	})
+}
+
+func scheduled(rt *Runtime, key string) bool {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	_, ok := rt.tasks[key]
+	return ok
+}
+
+func TestThresholdHold(t *testing.T) {
+	sg := thresholdSceneGraph(map[string]interface{}{"hold_ms": float64(3000)})
+	rt := startGame(t, sg, "scene_altar", nil)
+	defer rt.StopGame()
+
+	weigh(rt, 400)
+	if scheduled(rt, holdKey) {
+		t.Fatal("expected no hold below the range")
+	}
+	weigh(rt, 1000)
+	if !scheduled(rt, holdKey) {
+		t.Fatal("expected the hold armed in range")
+	}
+
+	// Leaving the range abandons the hold
+	weigh(rt, 1200)
+	fire(t, rt, holdKey)
+	if rt.GetPuzzleResolution("puzzle_altar") != PuzzleUnresolved {
+		t.Fatal("expected a stale hold not to solve the puzzle")
+	}
+
+	weigh(rt, 1000)
+	fire(t, rt, holdKey)
+	if rt.GetPuzzleResolution("puzzle_altar") != PuzzleSolved {
+		t.Errorf("expected puzzle solved after the hold, got %s", rt.GetPuzzleResolution("puzzle_altar"))
+	}
+
+	var transitions []interface{}
+	for _, e := range events.Snapshot() {
+		if e.Name == "puzzle.progress" {
+			transitions = append(transitions, e.Fields["in_range"])
+		}
+	}
+	want := []interface{}{true, false, true}
+	if len(transitions) != len(want) {
+		t.Fatalf("expected in_range transitions %v, got %v", want, transitions)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Errorf("transition %d = %v, want %v", i, transitions[i], want[i])
+		}
+	}
+}
+
+func TestThresholdHysteresis(t *testing.T) {
+	sg := thresholdSceneGraph(map[string]interface{}{"hold_ms": float64(3000), "hysteresis": float64(20)})
+	rt := startGame(t, sg, "scene_altar", nil)
+	defer rt.StopGame()
+
+	// Entering needs the strict range
+	weigh(rt, 940)
+	if scheduled(rt, holdKey) {
+		t.Fatal("expected 940 g outside the range")
+	}
+	weigh(rt, 960)
+
+	// Jitter just past the bound does not count as leaving
+	weigh(rt, 935)
+	weigh(rt, 1065)
+	fire(t, rt, holdKey)
+	if rt.GetPuzzleResolution("puzzle_altar") != PuzzleSolved {
+		t.Errorf("expected jitter within the hysteresis to keep the hold, got %s", rt.GetPuzzleResolution("puzzle_altar"))
+	}
+}
+
+func TestThresholdDebounce(t *testing.T) {
+	sg := thresholdSceneGraph(map[string]interface{}{"hold_ms": float64(3000), "debounce_ms": float64(500)})
+	rt := startGame(t, sg, "scene_altar", nil)
+	defer rt.StopGame()
+
+	// A short excursion is forgiven
+	weigh(rt, 1000)
+	weigh(rt, 0)
+	if !scheduled(rt, leaveKey) {
+		t.Fatal("expected the leave debounced")
+	}
+	weigh(rt, 1000)
+	fire(t, rt, leaveKey) // stale: the value came back
+
+	// The hold ends during another excursion: decided by what follows
+	weigh(rt, 0)
+	fire(t, rt, holdKey)
+	if rt.GetPuzzleResolution("puzzle_altar") != PuzzleUnresolved {
+		t.Fatal("expected no solve while the value is out of range")
+	}
+	weigh(rt, 1010)
+	if rt.GetPuzzleResolution("puzzle_altar") != PuzzleSolved {
+		t.Errorf("expected puzzle solved on return within the debounce, got %s", rt.GetPuzzleResolution("puzzle_altar"))
+	}
+}
+
+func TestThresholdDebounceExpires(t *testing.T) {
+	sg := thresholdSceneGraph(map[string]interface{}{"hold_ms": float64(3000), "debounce_ms": float64(500)})
+	rt := startGame(t, sg, "scene_altar", nil)
+	defer rt.StopGame()
+
+	weigh(rt, 1000)
+	weigh(rt, 0)
+	fire(t, rt, leaveKey)
+	fire(t, rt, holdKey)
+	if rt.GetPuzzleResolution("puzzle_altar") != PuzzleUnresolved {
+		t.Fatal("expected a confirmed leave to abandon the hold")
+	}
+	weigh(rt, 1000)
+	if rt.GetPuzzleResolution("puzzle_altar") != PuzzleUnresolved {
+		t.Fatal("expected re-entering to start a fresh hold")
+	}
+	fire(t, rt, holdKey)
+	if rt.GetPuzzleResolution("puzzle_altar") != PuzzleSolved {
+		t.Errorf("expected puzzle solved after a fresh hold, got %s", rt.GetPuzzleResolution("puzzle_altar"))
+	}
+}
+
+func TestThresholdWithoutHold(t *testing.T) {
+	sg := thresholdSceneGraph(map[string]interface{}{"min": nil, "max": float64(10), "field": ""})
+	rt := startGame(t, sg, "scene_altar", nil)
+	defer rt.StopGame()
+
+	rt.InjectEvent("device.input", map[string]interface{}{
+		"logical_id": "altar_scale",
+		"payload":    map[string]interface{}{"value": float64(3)},
+	})
+	if rt.GetPuzzleResolution("puzzle_altar") != PuzzleSolved {
+		t.Errorf("expected an immediate solve without hold_ms, got %s", rt.GetPuzzleResolution("puzzle_altar"))
+	}
+}
+
+func TestValidateThreshold(t *testing.T) {
+	match := "event == 'device.input'"
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		ok     bool
+	}{
+		{"range", map[string]interface{}{"match": match, "min": float64(1), "max": float64(2)}, true},
+		{"min only", map[string]interface{}{"match": match, "min": float64(1), "hold_ms": float64(500)}, true},
+		{"missing match", map[string]interface{}{"min": float64(1)}, false},
+		{"missing range", map[string]interface{}{"match": match}, false},
+		{"inverted range", map[string]interface{}{"match": match, "min": float64(2), "max": float64(1)}, false},
+		{"negative hysteresis", map[string]interface{}{"match": match, "min": float64(1), "hysteresis": float64(-1)}, false},
+		{"negative hold", map[string]interface{}{"match": match, "min": float64(1), "hold_ms": float64(-1)}, false},
+	}
+	for _, tt := range tests {
+		err := validateThreshold(Node{ID: "t", Type: "threshold", Config: tt.config})
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: got err %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// timerSceneGraph builds a scene where a timer gates the terminal node.
+func timerSceneGraph(durationMs int) *SceneGraph {
+	return sceneGraph(Scene{
+		ID:    "scene_timer",
+		Entry: "countdown",
+		Nodes: []Node{
+			{ID: "countdown", Type: "timer", Config: map[string]interface{}{"duration_ms": float64(durationMs)}},
+			{ID: "done", Type: "terminal", Config: map[string]interface{}{}},
+		},
+		Edges: []Edge{{From: "countdown", To: "done"}},
+	})
+}
+
+// countEvents returns how many buffered events match name and node_id.
+func countEvents(name, nodeID string) int {
+	n := 0
+	for _, e := range events.Snapshot() {
+		if e.Name == name && e.Fields["node_id"] == nodeID {
+			n++
+		}
+	}
+	return n
+}
+
+func TestTimerNodeExpires(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(timerSceneGraph(60000))
+
+	if err := rt.StartScene("scene_timer"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+
+	if rt.GetNodeState("countdown") != NodeStateActive {
+		t.Fatalf("expected countdown to be active, got %s", rt.GetNodeState("countdown"))
+	}
+	if countEvents("timer.started", "countdown") != 1 {
+		t.Error("expected timer.started for countdown")
+	}
+
+	// Fire the timer without waiting for the full duration
+	rt.mu.Lock()
+	fired := rt.fireScheduled(timerKey("countdown"))
+	rt.mu.Unlock()
+	if !fired {
+		t.Fatal("expected countdown timer to be pending")
+	}
+
+	if countEvents("timer.expired", "countdown") != 1 {
+		t.Error("expected timer.expired for countdown")
+	}
+	if rt.GetNodeState("countdown") != NodeStateCompleted {
+		t.Errorf("expected countdown to be completed, got %s", rt.GetNodeState("countdown"))
+	}
+	if rt.GetNodeState("done") != NodeStateCompleted {
+		t.Errorf("expected done to be completed after timer expiry, got %s", rt.GetNodeState("done"))
+	}
+}
+
+func TestTimerNodeExpiresOnClock(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(timerSceneGraph(10))
+
+	if err := rt.StartScene("scene_timer"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for rt.GetNodeState("done") != NodeStateCompleted {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for timer to expire")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestTimerNodeCancelledOnStop(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(timerSceneGraph(60000))
+
+	if err := rt.StartGame("scene_timer"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	if err := rt.StopGame(); err != nil {
+		t.Fatalf("failed to stop game: %v", err)
+	}
+
+	if countEvents("timer.cancelled", "countdown") != 1 {
+		t.Error("expected timer.cancelled when the scene stops")
+	}
+
+	rt.mu.Lock()
+	pending := len(rt.tasks)
+	rt.mu.Unlock()
+	if pending != 0 {
+		t.Errorf("expected no pending tasks after stop, got %d", pending)
+	}
+}
+
+func TestTimerNodeOperatorReset(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(timerSceneGraph(60000))
+
+	if err := rt.StartScene("scene_timer"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+
+	if err := rt.ResetNode("countdown"); err != nil {
+		t.Fatalf("failed to reset node: %v", err)
+	}
+
+	if countEvents("timer.cancelled", "countdown") != 1 {
+		t.Error("expected timer.cancelled on operator reset")
+	}
+	// Reset returns the node to waiting, so the countdown starts again
+	if countEvents("timer.started", "countdown") != 2 {
+		t.Errorf("expected timer to restart after reset, got %d timer.started", countEvents("timer.started", "countdown"))
+	}
+	if rt.GetNodeState("countdown") != NodeStateActive {
+		t.Errorf("expected countdown to be active, got %s", rt.GetNodeState("countdown"))
+	}
+}
+
+func TestTimerNodeOverride(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(timerSceneGraph(60000))
+
+	if err := rt.StartScene("scene_timer"); err != nil {
+		t.Fatalf("failed to start scene: %v", err)
+	}
+	if err := rt.OverrideNode("countdown"); err != nil {
+		t.Fatalf("failed to override node: %v", err)
+	}
+
+	if countEvents("timer.cancelled", "countdown") != 1 {
+		t.Error("expected timer.cancelled on override")
+	}
+	if countEvents("timer.expired", "countdown") != 0 {
+		t.Error("expected no timer.expired after override")
+	}
+	if rt.GetNodeState("done") != NodeStateCompleted {
+		t.Errorf("expected done to be completed after override, got %s", rt.GetNodeState("done"))
+	}
+}
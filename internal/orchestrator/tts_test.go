@@ -0,0 +1,71 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/tts"
+)
+
+// fakeSpeaker records queued speech.
+type fakeSpeaker struct {
+	said []tts.Request
+}
+
+func (s *fakeSpeaker) Say(req tts.Request) error {
+	s.said = append(s.said, req)
+	return nil
+}
+
+func TestTTSSpeakExpandsVariables(t *testing.T) {
+	speaker := &fakeSpeaker{}
+	executor := NewActionExecutor(nil, nil, nil)
+	executor.SetSpeaker(speaker)
+
+	rt := NewRuntime(&SceneGraph{Version: 1})
+	rt.SetActionExecutor(executor)
+
+	config := map[string]interface{}{
+		"action": TTSAction,
+		"params": map[string]interface{}{
+			"text":    "The vault code is {{vars.vault_code}}{{ vars.missing }}.",
+			"speaker": "vault",
+		},
+	}
+	rt.mu.Lock()
+	rt.vars["vault_code"] = 4417.0
+	err := rt.runAction("hint_code", config)
+	rt.mu.Unlock()
+	if err != nil {
+		t.Fatalf("runAction failed: %v", err)
+	}
+
+	if len(speaker.said) != 1 {
+		t.Fatalf("expected one request, got %d", len(speaker.said))
+	}
+	if got := speaker.said[0]; got.Text != "The vault code is 4417." || got.Speaker != "vault" {
+		t.Errorf("unexpected request: %+v", got)
+	}
+
+	// The graph's config is left untouched
+	if text := config["params"].(map[string]interface{})["text"]; text != "The vault code is {{vars.vault_code}}{{ vars.missing }}." {
+		t.Errorf("expected node config unchanged, got %v", text)
+	}
+}
+
+func TestTTSSpeakNotConfigured(t *testing.T) {
+	events.Clear()
+	executor := NewActionExecutor(nil, nil, nil)
+
+	err := executor.ExecuteAction("hint", map[string]interface{}{
+		"action": TTSAction,
+		"params": map[string]interface{}{"text": "hello"},
+	})
+	if err == nil {
+		t.Fatal("expected error without a TTS client")
+	}
+	snapshot := events.Snapshot()
+	if len(snapshot) != 1 || snapshot[0].Name != "device.error" || snapshot[0].Fields["error"] != "tts not configured" {
+		t.Errorf("expected device.error, got %+v", snapshot)
+	}
+}
@@ -0,0 +1,89 @@
+package orchestrator
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxUndoDepth bounds the operator action stack.
+const maxUndoDepth = 20
+
+// OperatorAction describes an undoable operator action.
+type OperatorAction struct {
+	Action string    `json:"action"` // override, reset, reset_to_node, reset_to_checkpoint, jump
+	Target string    `json:"target"` // node id or checkpoint name
+	At     time.Time `json:"at"`
+}
+
+// undoEntry pairs an operator action with the state it replaced.
+type undoEntry struct {
+	action OperatorAction
+	before *Checkpoint
+}
+
+// pushUndo records the current state before an operator action changes it.
+// The stack is per session and cleared when a game starts or stops.
+func (r *Runtime) pushUndo(action, target string) {
+	entry := undoEntry{
+		action: OperatorAction{Action: action, Target: target, At: r.now()},
+		before: r.snapshot("undo:"+action, target),
+	}
+	r.undoStack = append(r.undoStack, entry)
+	if len(r.undoStack) > maxUndoDepth {
+		r.undoStack = r.undoStack[len(r.undoStack)-maxUndoDepth:]
+	}
+}
+
+// UndoLastAction reverts the most recent operator action in this session, so
+// a misclick during a live game can be taken back (ADR-010). Only runtime
+// state is restored: physical side effects are not reversed, and nodes
+// returned to active restart from scratch.
+func (r *Runtime) UndoLastAction() (OperatorAction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.activeScene == nil {
+		return OperatorAction{}, fmt.Errorf("no active session")
+	}
+	if len(r.undoStack) == 0 {
+		return OperatorAction{}, fmt.Errorf("nothing to undo")
+	}
+
+	entry := r.undoStack[len(r.undoStack)-1]
+	r.undoStack = r.undoStack[:len(r.undoStack)-1]
+	if entry.before.SceneID != r.activeScene.ID {
+		return OperatorAction{}, fmt.Errorf("%s of %s belongs to scene %s", entry.action.Action, entry.action.Target, entry.before.SceneID)
+	}
+
+	r.restoreSnapshot(entry.before, false)
+
+	puzzles := make(map[string]interface{}, len(r.puzzleStates))
+	for id, ps := range r.puzzleStates {
+		puzzles[id] = string(ps.Resolution)
+	}
+	choices := make(map[string]interface{}, len(r.randomChoices))
+	for id, choice := range r.randomChoices {
+		choices[id] = choice
+	}
+	r.emitEvent("operator.undo", map[string]interface{}{
+		"action":         entry.action.Action,
+		"node_id":        entry.action.Target,
+		"puzzles":        puzzles,
+		"random_choices": choices,
+		"variables":      copyVars(r.vars),
+	})
+
+	return entry.action, nil
+}
+
+// UndoStack returns the undoable operator actions, most recent last.
+func (r *Runtime) UndoStack() []OperatorAction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	actions := make([]OperatorAction, 0, len(r.undoStack))
+	for _, entry := range r.undoStack {
+		actions = append(actions, entry.action)
+	}
+	return actions
+}
@@ -0,0 +1,119 @@
+package orchestrator
+
+import (
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+func TestUndoOverride(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(cascadeSceneGraph())
+	if err := rt.StartGame("scene_cascade"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+
+	if err := rt.OverrideNode("puzzle_a"); err != nil {
+		t.Fatalf("override failed: %v", err)
+	}
+	if rt.GetNodeState("finale") != NodeStateActive {
+		t.Fatalf("expected finale active after override, got %s", rt.GetNodeState("finale"))
+	}
+
+	action, err := rt.UndoLastAction()
+	if err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	if action.Action != "override" || action.Target != "puzzle_a" {
+		t.Errorf("unexpected undone action: %+v", action)
+	}
+
+	if rt.GetNodeState("puzzle_a") != NodeStateActive {
+		t.Errorf("expected puzzle_a active again, got %s", rt.GetNodeState("puzzle_a"))
+	}
+	if rt.GetPuzzleResolution("puzzle_a") != PuzzleUnresolved {
+		t.Errorf("expected puzzle_a unresolved, got %s", rt.GetPuzzleResolution("puzzle_a"))
+	}
+	for _, id := range []string{"after", "finale"} {
+		if rt.GetNodeState(id) != NodeStateIdle {
+			t.Errorf("expected %s idle after undo, got %s", id, rt.GetNodeState(id))
+		}
+	}
+
+	var undo *events.Event
+	for _, e := range events.Snapshot() {
+		if e.Name == "operator.undo" {
+			e := e
+			undo = &e
+		}
+	}
+	if undo == nil {
+		t.Fatal("expected operator.undo event")
+	}
+	if puzzles, _ := undo.Fields["puzzles"].(map[string]interface{}); puzzles["puzzle_a"] != "unresolved" {
+		t.Errorf("expected operator.undo to carry puzzle states, got %v", undo.Fields["puzzles"])
+	}
+
+	// The puzzle can still be solved after undo
+	if err := rt.OverrideNode("puzzle_a"); err != nil {
+		t.Fatalf("override failed: %v", err)
+	}
+	if rt.GetNodeState("finale") != NodeStateActive {
+		t.Errorf("expected finale active after second override, got %s", rt.GetNodeState("finale"))
+	}
+}
+
+func TestUndoResetRestoresCompletedPuzzle(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(cascadeSceneGraph())
+	if err := rt.StartGame("scene_cascade"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	if err := rt.OverrideNode("puzzle_a"); err != nil {
+		t.Fatalf("override failed: %v", err)
+	}
+	if err := rt.ResetNodeCascade("puzzle_a", true); err != nil {
+		t.Fatalf("reset failed: %v", err)
+	}
+
+	if _, err := rt.UndoLastAction(); err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	if rt.GetPuzzleResolution("puzzle_a") != PuzzleOverridden {
+		t.Errorf("expected puzzle_a overridden again, got %s", rt.GetPuzzleResolution("puzzle_a"))
+	}
+	if rt.GetNodeState("after") != NodeStateCompleted {
+		t.Errorf("expected after completed again, got %s", rt.GetNodeState("after"))
+	}
+	if rt.GetNodeState("finale") != NodeStateActive {
+		t.Errorf("expected finale active again, got %s", rt.GetNodeState("finale"))
+	}
+	if n := len(rt.UndoStack()); n != 1 {
+		t.Errorf("expected override still undoable, got %d entries", n)
+	}
+}
+
+func TestUndoNothingToUndo(t *testing.T) {
+	rt := NewRuntime(cascadeSceneGraph())
+	if _, err := rt.UndoLastAction(); err == nil {
+		t.Error("expected error without active session")
+	}
+
+	if err := rt.StartGame("scene_cascade"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	if err := rt.OverrideNode("puzzle_a"); err != nil {
+		t.Fatalf("override failed: %v", err)
+	}
+
+	// A new game starts with an empty stack
+	if err := rt.StopGame(); err != nil {
+		t.Fatalf("failed to stop game: %v", err)
+	}
+	if err := rt.StartGame("scene_cascade"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+	if _, err := rt.UndoLastAction(); err == nil {
+		t.Error("expected nothing to undo in a new game")
+	}
+}
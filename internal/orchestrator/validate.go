@@ -0,0 +1,206 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// sceneNodeTypes are the node types a scene can run.
+var sceneNodeTypes = map[string]bool{
+	"action": true, "puzzle": true, "timer": true, "parallel": true, "loop": true, "gate": true,
+	"checkpoint": true, "operator": true, "random": true, "subgraph": true, "terminal": true,
+}
+
+// subgraphNodeTypes are the node types a puzzle or generic subgraph can run.
+var subgraphNodeTypes = map[string]bool{
+	"action": true, "decision": true, "counter": true, "keypad": true, "threshold": true,
+	"pattern": true, "terminal": true,
+}
+
+// ValidateSceneGraph checks that a graph is well formed and returns every
+// problem found, joined into one error, so a broken graph stops startup
+// instead of failing mid-game. After the structure it runs
+// ValidateConditions for condition syntax and node config.
+func ValidateSceneGraph(sg *SceneGraph) error {
+	var errs []error
+	if len(sg.Scenes) == 0 {
+		errs = append(errs, fmt.Errorf("scene graph has no scenes"))
+	}
+
+	scenes := make(map[string]bool, len(sg.Scenes))
+	for i := range sg.Scenes {
+		scene := &sg.Scenes[i]
+		switch {
+		case scene.ID == "":
+			errs = append(errs, fmt.Errorf("scene %d: missing id", i))
+		case scenes[scene.ID]:
+			errs = append(errs, fmt.Errorf("scene %s: duplicate id", scene.ID))
+		}
+		scenes[scene.ID] = true
+
+		for _, err := range validateScene(scene) {
+			errs = append(errs, fmt.Errorf("scene %s: %w", scene.ID, err))
+		}
+	}
+
+	errs = append(errs, conditionErrors(sg)...)
+	return errors.Join(errs...)
+}
+
+// validateScene checks the nodes, edges and subgraphs of one scene.
+func validateScene(scene *Scene) []error {
+	errs, nodes := validateNodes(scene.Entry, scene.Nodes, sceneNodeTypes)
+	errs = append(errs, validateEdges(scene.Edges, nodes)...)
+
+	subgraphs := make(map[string]bool, len(scene.Subgraphs))
+	for i, sub := range scene.Subgraphs {
+		switch {
+		case sub.ID == "":
+			errs = append(errs, fmt.Errorf("subgraph %d: missing id", i))
+			continue
+		case subgraphs[sub.ID]:
+			errs = append(errs, fmt.Errorf("subgraph %s: duplicate id", sub.ID))
+		}
+		subgraphs[sub.ID] = true
+
+		subErrs, subNodes := validateNodes(sub.Entry, sub.Nodes, subgraphNodeTypes)
+		subErrs = append(subErrs, validateEdges(sub.Edges, subNodes)...)
+		for _, err := range subErrs {
+			errs = append(errs, fmt.Errorf("subgraph %s: %w", sub.ID, err))
+		}
+	}
+
+	for _, node := range scene.Nodes {
+		errs = append(errs, validateNodeRefs(node, nodes, subgraphs)...)
+	}
+	return errs
+}
+
+// validateNodes checks node IDs and types and the entry node, and returns the
+// set of node IDs.
+func validateNodes(entry string, nodes []Node, types map[string]bool) ([]error, map[string]bool) {
+	var errs []error
+	ids := make(map[string]bool, len(nodes))
+	for i, node := range nodes {
+		switch {
+		case node.ID == "":
+			errs = append(errs, fmt.Errorf("node %d: missing id", i))
+			continue
+		case ids[node.ID]:
+			errs = append(errs, fmt.Errorf("node %s: duplicate id", node.ID))
+		}
+		ids[node.ID] = true
+
+		if !types[node.Type] {
+			errs = append(errs, fmt.Errorf("node %s: unknown type %q", node.ID, node.Type))
+		}
+	}
+
+	switch {
+	case entry == "":
+		errs = append(errs, fmt.Errorf("missing entry"))
+	case !ids[entry]:
+		errs = append(errs, fmt.Errorf("entry %s: unknown node", entry))
+	}
+	return errs, ids
+}
+
+// validateEdges checks that edges join known nodes and form no cycle.
+func validateEdges(edges []Edge, nodes map[string]bool) []error {
+	var errs []error
+	for _, edge := range edges {
+		for _, id := range []string{edge.From, edge.To} {
+			if !nodes[id] {
+				errs = append(errs, fmt.Errorf("edge %s -> %s: unknown node %q", edge.From, edge.To, id))
+			}
+		}
+	}
+	if cycle := findCycle(edges); cycle != nil {
+		errs = append(errs, fmt.Errorf("cycle %s: a node cannot run again once it has ended", strings.Join(cycle, " -> ")))
+	}
+	return errs
+}
+
+// validateNodeRefs checks the nodes and subgraphs a scene node's config names.
+func validateNodeRefs(node Node, nodes, subgraphs map[string]bool) []error {
+	var errs []error
+	switch node.Type {
+	case "puzzle", "subgraph":
+		subgraphID, _ := node.Config["subgraph"].(string)
+		switch {
+		case subgraphID == "":
+			errs = append(errs, fmt.Errorf("node %s: %s requires subgraph", node.ID, node.Type))
+		case !subgraphs[subgraphID]:
+			errs = append(errs, fmt.Errorf("node %s: unknown subgraph %q", node.ID, subgraphID))
+		}
+	case "parallel":
+		for _, child := range configStrings(node.Config, "children") {
+			if !nodes[child] {
+				errs = append(errs, fmt.Errorf("node %s children: unknown node %q", node.ID, child))
+			}
+		}
+	case "random":
+		choices := randomChoices(&node)
+		if len(choices) == 0 {
+			errs = append(errs, fmt.Errorf("node %s: random requires choices", node.ID))
+		}
+		for _, c := range choices {
+			if !nodes[c.to] {
+				errs = append(errs, fmt.Errorf("node %s choices: unknown node %q", node.ID, c.to))
+			}
+		}
+	}
+	return errs
+}
+
+// findCycle returns the nodes of a cycle in edges, first node repeated at the
+// end, or nil if the edges form none.
+func findCycle(edges []Edge) []string {
+	next := make(map[string][]string)
+	var order []string
+	for _, edge := range edges {
+		if _, ok := next[edge.From]; !ok {
+			order = append(order, edge.From)
+		}
+		next[edge.From] = append(next[edge.From], edge.To)
+	}
+
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	state := make(map[string]int)
+	var path []string
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		state[id] = onPath
+		path = append(path, id)
+		for _, to := range next[id] {
+			switch state[to] {
+			case onPath:
+				for i, p := range path {
+					if p == to {
+						return append(append([]string{}, path[i:]...), to)
+					}
+				}
+			case unvisited:
+				if cycle := visit(to); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+		return nil
+	}
+	for _, id := range order {
+		if state[id] == unvisited {
+			if cycle := visit(id); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
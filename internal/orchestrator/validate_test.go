@@ -0,0 +1,84 @@
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSceneGraph(t *testing.T) {
+	sg := counterSceneGraph(3)
+	if err := ValidateSceneGraph(sg); err != nil {
+		t.Fatalf("expected a valid graph: %v", err)
+	}
+
+	scene := &sg.Scenes[0]
+	scene.Entry = "intro"
+	scene.Nodes = append(scene.Nodes,
+		Node{ID: "hint", Type: "gate", Config: map[string]interface{}{}},
+		Node{ID: "fog", Type: "smoke", Config: map[string]interface{}{}},
+		Node{ID: "puzzle_tiles", Type: "puzzle", Config: map[string]interface{}{"subgraph": "tiles"}},
+		Node{ID: "both", Type: "parallel", Config: map[string]interface{}{"children": []interface{}{"puzzle_presses", "puzzle_scarab"}}},
+		Node{ID: "end", Type: "terminal", Config: map[string]interface{}{}},
+	)
+	scene.Edges = append(scene.Edges,
+		Edge{From: "puzzle_presses", To: "both"},
+		Edge{From: "both", To: "puzzle_presses"},
+		Edge{From: "both", To: "ending"},
+	)
+	scene.Subgraphs[0].Nodes[0].Config["match"] = "event == "
+
+	err := ValidateSceneGraph(sg)
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	for _, want := range []string{
+		"scene scene_counter: entry intro: unknown node",
+		"scene scene_counter: node hint: duplicate id",
+		`scene scene_counter: node fog: unknown type "smoke"`,
+		`scene scene_counter: node puzzle_tiles: unknown subgraph "tiles"`,
+		`scene scene_counter: node both children: unknown node "puzzle_scarab"`,
+		`scene scene_counter: edge both -> ending: unknown node "ending"`,
+		"scene scene_counter: cycle puzzle_presses -> both -> puzzle_presses",
+		"scene scene_counter subgraph presses: node count_presses match:",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in:\n%v", want, err)
+		}
+	}
+}
+
+func TestValidateSceneGraphSubgraphs(t *testing.T) {
+	sg := counterSceneGraph(3)
+	sub := &sg.Scenes[0].Subgraphs[0]
+	sub.Entry = ""
+	sub.Nodes = append(sub.Nodes, Node{ID: "wait", Type: "timer", Config: map[string]interface{}{}})
+	sg.Scenes = append(sg.Scenes, Scene{ID: "scene_counter", Entry: "end", Nodes: []Node{{ID: "end", Type: "terminal"}}})
+
+	err := ValidateSceneGraph(sg)
+	if err == nil {
+		t.Fatal("expected errors")
+	}
+	for _, want := range []string{
+		"scene scene_counter: subgraph presses: missing entry",
+		`scene scene_counter: subgraph presses: node wait: unknown type "timer"`,
+		"scene scene_counter: duplicate id",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in:\n%v", want, err)
+		}
+	}
+}
+
+func TestFindCycle(t *testing.T) {
+	edges := []Edge{{From: "a", To: "b"}, {From: "b", To: "c"}, {From: "c", To: "d"}}
+	if cycle := findCycle(edges); cycle != nil {
+		t.Errorf("expected no cycle, got %v", cycle)
+	}
+	edges = append(edges, Edge{From: "d", To: "b"})
+	if cycle := strings.Join(findCycle(edges), " -> "); cycle != "b -> c -> d -> b" {
+		t.Errorf("expected cycle b -> c -> d -> b, got %s", cycle)
+	}
+	if cycle := findCycle([]Edge{{From: "a", To: "a"}}); len(cycle) != 2 {
+		t.Errorf("expected a self edge to be a cycle, got %v", cycle)
+	}
+}
@@ -0,0 +1,167 @@
+package orchestrator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// SetVariableAction is the action name handled by the runtime itself. It
+// assigns a session variable, which conditions read as vars.<name>.
+const SetVariableAction = "set_variable"
+
+// inputVar holds the latest device.input payloads keyed by logical_id.
+const inputVar = "input"
+
+// runAction executes an action config, handling action lists, set_variable and
+// wait locally and passing everything else to the action executor.
+func (r *Runtime) runAction(nodeID string, config map[string]interface{}) error {
+	if list, ok := actionList(config); ok {
+		return r.runActionList(nodeID, list)
+	}
+	switch action, _ := config["action"].(string); action {
+	case SetVariableAction:
+		return r.setVariable(nodeID, config)
+	case WaitAction:
+		// The delay itself is applied when the node activates (see delay.go)
+		return nil
+	case TTSAction:
+		// Spoken text may include session values, e.g. "The code is {{vars.code}}"
+		config = r.expandTextParam(config)
+	}
+	if r.actionExecutor == nil {
+		return nil
+	}
+	return r.actionExecutor.ExecuteAction(nodeID, config)
+}
+
+// setVariable applies a set_variable action and emits variable.set.
+func (r *Runtime) setVariable(nodeID string, config map[string]interface{}) error {
+	params, _ := config["params"].(map[string]interface{})
+	name, _ := params["name"].(string)
+	if name == "" || name == inputVar || name == counterVar || strings.Contains(name, ".") {
+		return r.variableError(nodeID, name, "invalid or missing 'name' in params")
+	}
+
+	var value interface{}
+	if inc, ok := params["increment"]; ok {
+		delta, ok := toFloat(inc)
+		if !ok {
+			return r.variableError(nodeID, name, "'increment' must be a number")
+		}
+		current, _ := toFloat(r.vars[name])
+		value = current + delta
+	} else if v, ok := params["value"]; ok {
+		value = v
+	} else {
+		return r.variableError(nodeID, name, "missing 'value' or 'increment' in params")
+	}
+
+	r.vars[name] = value
+	r.emitEvent("variable.set", map[string]interface{}{
+		"node_id": nodeID,
+		"name":    name,
+		"value":   value,
+	})
+	return nil
+}
+
+// variableError reports a misconfigured set_variable action.
+func (r *Runtime) variableError(nodeID, name, msg string) error {
+	fields := map[string]interface{}{
+		"node_id": nodeID,
+		"action":  SetVariableAction,
+		"error":   msg,
+	}
+	if name != "" {
+		fields["name"] = name
+	}
+	events.Emit("error", "system.error", msg, fields)
+	return fmt.Errorf("node %s: %s", nodeID, msg)
+}
+
+// varPlaceholder matches {{vars.<name>}} in action text.
+var varPlaceholder = regexp.MustCompile(`\{\{\s*vars\.([A-Za-z0-9_.\-]+)\s*\}\}`)
+
+// expandTextParam returns a copy of config whose params.text has
+// {{vars.<name>}} placeholders replaced by session variables. Unset
+// variables expand to an empty string.
+func (r *Runtime) expandTextParam(config map[string]interface{}) map[string]interface{} {
+	params, _ := config["params"].(map[string]interface{})
+	text, ok := params["text"].(string)
+	if !ok || !strings.Contains(text, "{{") {
+		return config
+	}
+
+	expanded := varPlaceholder.ReplaceAllStringFunc(text, func(m string) string {
+		name := varPlaceholder.FindStringSubmatch(m)[1]
+		v := getNestedField(r.vars, name)
+		if v == nil {
+			return ""
+		}
+		return fmt.Sprint(v)
+	})
+
+	cp := make(map[string]interface{}, len(config))
+	for k, v := range config {
+		cp[k] = v
+	}
+	cpParams := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		cpParams[k] = v
+	}
+	cpParams["text"] = expanded
+	cp["params"] = cpParams
+	return cp
+}
+
+// recordInput stores a device.input payload under vars.input.<logical_id>.
+func (r *Runtime) recordInput(fields map[string]interface{}) {
+	logicalID, _ := fields["logical_id"].(string)
+	if logicalID == "" {
+		return
+	}
+	inputs, ok := r.vars[inputVar].(map[string]interface{})
+	if !ok {
+		inputs = make(map[string]interface{})
+		r.vars[inputVar] = inputs
+	}
+	inputs[logicalID] = fields["payload"]
+}
+
+// copyVars copies variables, including the per-device input and counter maps.
+func copyVars(src map[string]interface{}) map[string]interface{} {
+	dst := make(map[string]interface{}, len(src))
+	for k, v := range src {
+		if inputs, ok := v.(map[string]interface{}); ok && (k == inputVar || k == counterVar) {
+			cp := make(map[string]interface{}, len(inputs))
+			for id, payload := range inputs {
+				cp[id] = payload
+			}
+			v = cp
+		}
+		dst[k] = v
+	}
+	return dst
+}
+
+// replaceVars swaps in a new set of variables in place, so running subgraphs
+// keep seeing the session's map.
+func (r *Runtime) replaceVars(src map[string]interface{}) {
+	for k := range r.vars {
+		delete(r.vars, k)
+	}
+	for k, v := range copyVars(src) {
+		r.vars[k] = v
+	}
+}
+
+// Variables returns a copy of the session variables.
+func (r *Runtime) Variables() map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return copyVars(r.vars)
+}
@@ -0,0 +1,164 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/events"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// variableSceneGraph counts attempts and opens a gate on a variable or device input.
+func variableSceneGraph(gateEntry string) *SceneGraph {
+	return sceneGraph(Scene{
+		ID:    "scene_vars",
+		Entry: "init",
+		Nodes: []Node{
+			{ID: "init", Type: "action", Config: map[string]interface{}{
+				"action": SetVariableAction,
+				"params": map[string]interface{}{"name": "attempts", "value": 0.0},
+			}},
+			{ID: "join", Type: "gate", Config: map[string]interface{}{
+				"any_of": []interface{}{gateEntry},
+			}},
+			{ID: "done", Type: "terminal", Config: map[string]interface{}{}},
+		},
+		Edges: []Edge{
+			{From: "init", To: "join"},
+			{From: "join", To: "done"},
+		},
+	})
+}
+
+// bumpAttempts runs a set_variable increment as an action node would.
+func bumpAttempts(t *testing.T, rt *Runtime) {
+	t.Helper()
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	err := rt.runAction("bump", map[string]interface{}{
+		"action": SetVariableAction,
+		"params": map[string]interface{}{"name": "attempts", "increment": 1.0},
+	})
+	if err != nil {
+		t.Fatalf("set_variable failed: %v", err)
+	}
+	rt.evaluateAllConditions()
+}
+
+func TestSetVariableCounter(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(variableSceneGraph("vars.attempts >= 3"))
+	if err := rt.StartGame("scene_vars"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		bumpAttempts(t, rt)
+	}
+	if rt.GetNodeState("join") != NodeStateActive {
+		t.Fatalf("expected gate to wait at 2 attempts, got %s", rt.GetNodeState("join"))
+	}
+
+	bumpAttempts(t, rt)
+	if rt.GetNodeState("join") != NodeStateCompleted {
+		t.Errorf("expected gate to open at 3 attempts, got %s", rt.GetNodeState("join"))
+	}
+	if got := rt.Variables()["attempts"]; got != 3.0 {
+		t.Errorf("expected attempts=3, got %v", got)
+	}
+
+	n := 0
+	for _, e := range events.Snapshot() {
+		if e.Name == "variable.set" && e.Fields["name"] == "attempts" {
+			n++
+		}
+	}
+	if n != 4 {
+		t.Errorf("expected 4 variable.set events, got %d", n)
+	}
+
+	// Stopping the game clears variables
+	if err := rt.StopGame(); err != nil {
+		t.Fatalf("failed to stop game: %v", err)
+	}
+	if len(rt.Variables()) != 0 {
+		t.Errorf("expected variables cleared, got %v", rt.Variables())
+	}
+}
+
+func TestDeviceInputVariable(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(variableSceneGraph("vars.input.lever.pulled == true"))
+	if err := rt.StartGame("scene_vars"); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+
+	rt.InjectEvent("device.input", map[string]interface{}{
+		"logical_id": "lever",
+		"payload":    map[string]interface{}{"pulled": false},
+	})
+	if rt.GetNodeState("join") != NodeStateActive {
+		t.Fatalf("expected gate to wait, got %s", rt.GetNodeState("join"))
+	}
+
+	rt.InjectEvent("device.input", map[string]interface{}{
+		"logical_id": "lever",
+		"payload":    map[string]interface{}{"pulled": true},
+	})
+	if rt.GetNodeState("join") != NodeStateCompleted {
+		t.Errorf("expected gate to open on lever input, got %s", rt.GetNodeState("join"))
+	}
+}
+
+func TestSetVariableInvalid(t *testing.T) {
+	events.Clear()
+	rt := NewRuntime(variableSceneGraph("vars.attempts >= 3"))
+
+	for _, params := range []map[string]interface{}{
+		{"value": 1.0},
+		{"name": "input", "value": 1.0},
+		{"name": "a.b", "value": 1.0},
+		{"name": "attempts"},
+		{"name": "attempts", "increment": "lots"},
+	} {
+		rt.mu.Lock()
+		err := rt.runAction("bad", map[string]interface{}{"action": SetVariableAction, "params": params})
+		rt.mu.Unlock()
+		if err == nil {
+			t.Errorf("expected error for params %v", params)
+		}
+	}
+	if n := countEvents("system.error", "bad"); n != 5 {
+		t.Errorf("expected 5 system.error events, got %d", n)
+	}
+}
+
+func TestVariablesCheckpointAndRestore(t *testing.T) {
+	now := time.Now()
+	rows := []storage.EventRow{
+		{Timestamp: now.Add(-3 * time.Minute), Event: "scene.started", Fields: map[string]interface{}{"scene_id": "scene_vars"}},
+		{Timestamp: now.Add(-2 * time.Minute), Event: "variable.set", Fields: map[string]interface{}{"name": "attempts", "value": 2.0}},
+		{Timestamp: now.Add(-1 * time.Minute), Event: "device.input", Fields: map[string]interface{}{
+			"logical_id": "lever",
+			"payload":    map[string]interface{}{"pulled": true},
+		}},
+	}
+
+	state := stateFromEvents(rows)
+	if state.Variables["attempts"] != 2.0 {
+		t.Fatalf("expected attempts=2 restored, got %v", state.Variables["attempts"])
+	}
+
+	rt := NewRuntime(variableSceneGraph("vars.attempts >= 3"))
+	if err := rt.ApplyRestoredState(state); err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+	vars := rt.Variables()
+	if vars["attempts"] != 2.0 {
+		t.Errorf("expected attempts=2 applied, got %v", vars["attempts"])
+	}
+	inputs, _ := vars["input"].(map[string]interface{})
+	if lever, _ := inputs["lever"].(map[string]interface{}); lever["pulled"] != true {
+		t.Errorf("expected lever input restored, got %v", vars["input"])
+	}
+}
@@ -0,0 +1,91 @@
+package orchestrator
+
+import (
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+)
+
+// DefaultVideoSyncInterval is how often timer.sync is emitted when none is configured.
+const DefaultVideoSyncInterval = 10 * time.Second
+
+// videoSyncKey is the scheduler key for the next timer.sync.
+const videoSyncKey = "video:sync"
+
+// videoSync holds the sync settings and the current session's sync state.
+// timer.sync lets review tools line the event timeline up with CCTV footage.
+type videoSync struct {
+	interval  time.Duration
+	cameras   []config.VideoCamera
+	startedAt time.Time
+	index     int
+}
+
+// SetVideoSync sets the timer.sync interval and the cameras reported with it.
+func (r *Runtime) SetVideoSync(interval time.Duration, cameras []config.VideoCamera) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if interval <= 0 {
+		interval = DefaultVideoSyncInterval
+	}
+	r.video.interval = interval
+	r.video.cameras = append([]config.VideoCamera(nil), cameras...)
+}
+
+// startVideoSync emits the first timer.sync of a session and schedules the rest.
+// Caller must hold r.mu.
+func (r *Runtime) startVideoSync() {
+	r.video.startedAt = r.now()
+	r.video.index = 0
+	r.emitVideoSync(false)
+	r.scheduleVideoSync()
+}
+
+// scheduleVideoSync arms the next periodic timer.sync.
+// Caller must hold r.mu.
+func (r *Runtime) scheduleVideoSync() {
+	r.schedule(videoSyncKey, r.video.interval, func() {
+		if r.activeScene == nil {
+			return
+		}
+		r.emitVideoSync(false)
+		r.scheduleVideoSync()
+	})
+}
+
+// stopVideoSync emits the final timer.sync of a session and stops the interval.
+// Caller must hold r.mu.
+func (r *Runtime) stopVideoSync() {
+	if r.video.startedAt.IsZero() {
+		return
+	}
+	r.cancelScheduled(videoSyncKey)
+	r.emitVideoSync(true)
+	r.video.startedAt = time.Time{}
+}
+
+// emitVideoSync emits one timer.sync.
+// Caller must hold r.mu.
+func (r *Runtime) emitVideoSync(final bool) {
+	now := r.now().UTC()
+	fields := map[string]interface{}{
+		"timer_id":           GameClockID,
+		"sync_index":         r.video.index,
+		"wall_clock":         now.Format(time.RFC3339Nano),
+		"wall_clock_ms":      now.UnixMilli(),
+		"session_elapsed_ms": now.Sub(r.video.startedAt).Milliseconds(),
+		"clock_elapsed_ms":   r.clockElapsed().Milliseconds(),
+	}
+	if r.activeScene != nil {
+		fields["scene_id"] = r.activeScene.ID
+	}
+	if len(r.video.cameras) > 0 {
+		fields["cameras"] = r.video.cameras
+	}
+	if final {
+		fields["final"] = true
+	}
+	r.video.index++
+	r.emitEvent("timer.sync", fields)
+}
@@ -0,0 +1,83 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+	"github.com/AaronLay10/SentientEngine/internal/events"
+)
+
+// syncEvents returns the timer.sync events in the buffer.
+func syncEvents() []events.Event {
+	var out []events.Event
+	for _, e := range events.Snapshot() {
+		if e.Name == "timer.sync" {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func TestVideoSync(t *testing.T) {
+	events.Clear()
+	rt, fc := newClockRuntime(t)
+	cameras := []config.VideoCamera{{ID: "cam_main", NVR: "nvr-01", Channel: 3, OffsetMs: -250}}
+	rt.SetVideoSync(30*time.Second, cameras)
+
+	if err := rt.StartGame(""); err != nil {
+		t.Fatalf("failed to start game: %v", err)
+	}
+
+	syncs := syncEvents()
+	if len(syncs) != 1 {
+		t.Fatalf("expected 1 timer.sync at start, got %d", len(syncs))
+	}
+	first := syncs[0].Fields
+	if first["sync_index"] != 0 || first["session_elapsed_ms"] != int64(0) {
+		t.Errorf("unexpected first sync: %v", first)
+	}
+	if cams, ok := first["cameras"].([]config.VideoCamera); !ok || len(cams) != 1 || cams[0].ID != "cam_main" {
+		t.Errorf("expected camera metadata, got %v", first["cameras"])
+	}
+
+	// Pauses count toward session time but not the countdown
+	fc.advance(10 * time.Second)
+	if err := rt.PauseClock(); err != nil {
+		t.Fatalf("failed to pause: %v", err)
+	}
+	fc.advance(20 * time.Second)
+	rt.mu.Lock()
+	fired := rt.fireScheduled(videoSyncKey)
+	rt.mu.Unlock()
+	if !fired {
+		t.Fatal("expected a pending timer.sync")
+	}
+
+	syncs = syncEvents()
+	if len(syncs) != 2 {
+		t.Fatalf("expected 2 timer.sync events, got %d", len(syncs))
+	}
+	second := syncs[1].Fields
+	if second["sync_index"] != 1 || second["session_elapsed_ms"] != int64(30000) || second["clock_elapsed_ms"] != int64(10000) {
+		t.Errorf("unexpected periodic sync: %v", second)
+	}
+	if second["wall_clock_ms"] != fc.t.UnixMilli() {
+		t.Errorf("expected wall_clock_ms %d, got %v", fc.t.UnixMilli(), second["wall_clock_ms"])
+	}
+
+	if err := rt.StopGame(); err != nil {
+		t.Fatalf("failed to stop game: %v", err)
+	}
+	syncs = syncEvents()
+	if len(syncs) != 3 || syncs[2].Fields["final"] != true {
+		t.Fatalf("expected a final timer.sync on stop, got %d syncs", len(syncs))
+	}
+
+	rt.mu.Lock()
+	_, pending := rt.tasks[videoSyncKey]
+	rt.mu.Unlock()
+	if pending {
+		t.Error("expected no timer.sync scheduled after stop")
+	}
+}
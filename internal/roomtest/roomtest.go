@@ -0,0 +1,38 @@
+// Package roomtest runs a room's room tests (rooms/<room-id>/tests/*.yaml)
+// from Go tests, so room logic regressions fail go test like any other.
+//
+//	func TestRoom(t *testing.T) {
+//		roomtest.Run(t, "../../rooms/clockwork")
+//	}
+package roomtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+)
+
+// Run plays every room test of a room directory against its deployed scene
+// graph, each as a subtest named after the test.
+func Run(t *testing.T, roomDir string) {
+	t.Helper()
+	RunGraph(t, roomDir, "")
+}
+
+// RunGraph is Run with another scene graph than the deployed one, for
+// example a revision not yet deployed.
+func RunGraph(t *testing.T, roomDir, graphPath string) {
+	t.Helper()
+	results, err := orchestrator.RunRoomTests(roomDir, graphPath)
+	if err != nil {
+		t.Fatalf("room %s: %v", roomDir, err)
+	}
+	for _, res := range results {
+		t.Run(res.Name, func(t *testing.T) {
+			if !res.Passed() {
+				t.Errorf("%s:\n  %s", res.Path, strings.Join(res.Failures, "\n  "))
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package roomtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+)
+
+// TestRooms runs the room tests of every room in the repository.
+func TestRooms(t *testing.T) {
+	dirs, err := filepath.Glob("../../rooms/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range dirs {
+		if _, err := os.Stat(filepath.Join(dir, config.RoomTestDir)); err != nil {
+			continue
+		}
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			Run(t, dir)
+		})
+	}
+}
@@ -0,0 +1,622 @@
+// Package bolt stores a room's events and state in an embedded bbolt file,
+// for single-board deployments that do not run Postgres. It implements
+// storage.Store with the same ordering and limits as the Postgres client.
+package bolt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	bbolt "go.etcd.io/bbolt"
+
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+// Buckets. Events and controller log lines are keyed by an increasing ID
+// and indexed by time, so the newest-first queries walk an index backwards
+// instead of sorting.
+var (
+	bucketEvents      = []byte("events")          // event_id -> EventRow
+	bucketEventsByTS  = []byte("events_by_ts")    // ts + event_id -> nil
+	bucketEventsBySeq = []byte("events_by_seq")   // seq -> event_id (latest)
+	bucketSessions    = []byte("sessions")        // session_id -> SessionRow
+	bucketCheckpoints = []byte("checkpoints")     // id -> CheckpointRow
+	bucketAssets      = []byte("device_assets")   // logical_id -> asset
+	bucketTriggers    = []byte("trigger_tokens")  // token_hash -> trigger
+	bucketPrefs       = []byte("operator_prefs")  // username -> key -> value
+	bucketLogs        = []byte("controller_logs") // ts + id -> ControllerLogRow
+)
+
+// openTimeout bounds waiting for another process holding the file.
+const openTimeout = 2 * time.Second
+
+// Store is a room's storage in a bbolt file.
+type Store struct {
+	db     *bbolt.DB
+	roomID string
+
+	aliases map[string]string // legacy event name -> current name
+}
+
+// Open opens (creating if needed) the bbolt file at path. Only one process
+// can have it open at a time.
+func Open(path, roomID string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{
+			bucketEvents, bucketEventsByTS, bucketEventsBySeq, bucketSessions, bucketCheckpoints,
+			bucketAssets, bucketTriggers, bucketPrefs, bucketLogs,
+		} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create buckets: %w", err)
+	}
+	return &Store{db: db, roomID: roomID}, nil
+}
+
+// Ping checks that the file is open.
+func (s *Store) Ping() error {
+	return s.db.View(func(tx *bbolt.Tx) error { return nil })
+}
+
+// Close closes the file.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// itob encodes n so keys sort numerically.
+func itob(n uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, n)
+	return b
+}
+
+// timeKey encodes ts and id so keys sort by time, then ID. Times before
+// 1970 sort first.
+func timeKey(ts time.Time, id uint64) []byte {
+	nanos := ts.UnixNano()
+	if nanos < 0 {
+		nanos = 0
+	}
+	return append(itob(uint64(nanos)), itob(id)...)
+}
+
+// AppendBatch stores events in one transaction: either all are stored or,
+// on error, none. Events whose seq is already stored are skipped, as is (with
+// a log line) an event whose fields cannot be encoded.
+func (s *Store) AppendBatch(batch []storage.EventInsert) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		events := tx.Bucket(bucketEvents)
+		byTS := tx.Bucket(bucketEventsByTS)
+		bySeq := tx.Bucket(bucketEventsBySeq)
+		for _, e := range batch {
+			// A batch replayed after an ambiguous failure may already be stored
+			if bySeq.Get(itob(e.Seq)) != nil {
+				continue
+			}
+			id, err := events.NextSequence()
+			if err != nil {
+				return err
+			}
+			row := storage.EventRow{
+				EventID:   int64(id),
+				Seq:       int64(e.Seq),
+				Timestamp: e.Timestamp,
+				Level:     e.Level,
+				Event:     e.Event,
+				Fields:    e.Fields,
+				RoomID:    s.roomID,
+			}
+			if e.Message != "" {
+				msg := e.Message
+				row.Message = &msg
+			}
+			if e.SessionID != "" {
+				sessionID := e.SessionID
+				row.SessionID = &sessionID
+			}
+			value, err := json.Marshal(row)
+			if err != nil {
+				log.Printf("[storage] skipping event %d: cannot encode fields: %v", e.Seq, err)
+				continue
+			}
+			if err := events.Put(itob(id), value); err != nil {
+				return err
+			}
+			if err := byTS.Put(timeKey(e.Timestamp, id), nil); err != nil {
+				return err
+			}
+			if err := bySeq.Put(itob(e.Seq), itob(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MaxSeq returns the highest event sequence number stored, or 0 if there
+// are no events.
+func (s *Store) MaxSeq() (uint64, error) {
+	var last uint64
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		if k, _ := tx.Bucket(bucketEventsBySeq).Cursor().Last(); k != nil {
+			last = binary.BigEndian.Uint64(k)
+		}
+		return nil
+	})
+	return last, err
+}
+
+// SetEventAliases sets the legacy event names to translate when reading
+// events back. Call before querying.
+func (s *Store) SetEventAliases(aliases map[string]string) {
+	s.aliases = aliases
+}
+
+// current returns the current name of an event name.
+func (s *Store) current(name string) string {
+	if current, ok := s.aliases[name]; ok {
+		return current
+	}
+	return name
+}
+
+// event reads one event, translating a legacy name.
+func (s *Store) event(events *bbolt.Bucket, id []byte) (*storage.EventRow, error) {
+	value := events.Get(id)
+	if value == nil {
+		return nil, nil
+	}
+	var row storage.EventRow
+	if err := json.Unmarshal(value, &row); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event %d: %w", binary.BigEndian.Uint64(id), err)
+	}
+	row.Event = s.current(row.Event)
+	return &row, nil
+}
+
+// EventBySeq returns the event with the given sequence number,
+// or nil if none exists.
+func (s *Store) EventBySeq(seq uint64) (*storage.EventRow, error) {
+	var found *storage.EventRow
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		id := tx.Bucket(bucketEventsBySeq).Get(itob(seq))
+		if id == nil {
+			return nil
+		}
+		var err error
+		found, err = s.event(tx.Bucket(bucketEvents), id)
+		return err
+	})
+	return found, err
+}
+
+// newestFirst calls fn with each event from before (a time key, nil for the
+// end) back to since, newest first, until fn returns false.
+func (s *Store) newestFirst(tx *bbolt.Tx, since time.Time, before []byte, fn func(row *storage.EventRow) bool) error {
+	events := tx.Bucket(bucketEvents)
+	c := tx.Bucket(bucketEventsByTS).Cursor()
+
+	var k []byte
+	if before == nil {
+		k, _ = c.Last()
+	} else if k, _ = c.Seek(before); k == nil {
+		k, _ = c.Last()
+	}
+	if k != nil && before != nil && bytes.Compare(k, before) >= 0 {
+		k, _ = c.Prev()
+	}
+	var floor []byte
+	if !since.IsZero() {
+		floor = timeKey(since, 0)
+	}
+	for ; k != nil; k, _ = c.Prev() {
+		if floor != nil && bytes.Compare(k, floor) < 0 {
+			return nil
+		}
+		row, err := s.event(events, k[8:])
+		if err != nil {
+			return err
+		}
+		if row != nil && !fn(row) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// Query returns events matching filter in descending order by timestamp.
+// Pass the event_id of the last row as Before to fetch the next page.
+func (s *Store) Query(filter storage.EventFilter) ([]storage.EventRow, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = storage.DefaultQueryLimit
+	}
+	if limit > storage.MaxQueryLimit {
+		limit = storage.MaxQueryLimit
+	}
+
+	var found []storage.EventRow
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		var before []byte
+		if !filter.Until.IsZero() {
+			before = timeKey(filter.Until, 0)
+		}
+		if filter.Before > 0 {
+			cursor, err := s.event(tx.Bucket(bucketEvents), itob(uint64(filter.Before)))
+			if err != nil {
+				return err
+			}
+			if cursor == nil {
+				return nil
+			}
+			if key := timeKey(cursor.Timestamp, uint64(cursor.EventID)); before == nil || bytes.Compare(key, before) < 0 {
+				before = key
+			}
+		}
+
+		skip := filter.Offset
+		return s.newestFirst(tx, filter.Since, before, func(row *storage.EventRow) bool {
+			if !matches(row, filter) {
+				return true
+			}
+			if skip > 0 {
+				skip--
+				return true
+			}
+			found = append(found, *row)
+			return len(found) < limit
+		})
+	})
+	return found, err
+}
+
+// matches reports whether an event passes the filter's field conditions.
+func matches(row *storage.EventRow, filter storage.EventFilter) bool {
+	if filter.EventPrefix != "" && !strings.HasPrefix(row.Event, filter.EventPrefix) {
+		return false
+	}
+	if filter.Level != "" && row.Level != filter.Level {
+		return false
+	}
+	if filter.SessionID != "" && (row.SessionID == nil || *row.SessionID != filter.SessionID) {
+		return false
+	}
+	if filter.NodeID != "" {
+		nodeID, ok := row.Fields["node_id"]
+		if !ok || fmt.Sprint(nodeID) != filter.NodeID {
+			return false
+		}
+	}
+	return true
+}
+
+// QueryByNames returns the last N events whose name is in names,
+// in descending order by timestamp.
+func (s *Store) QueryByNames(names []string, limit int) ([]storage.EventRow, error) {
+	if limit <= 0 {
+		limit = storage.DefaultQueryLimit
+	}
+	if limit > storage.MaxNamesQueryLimit {
+		limit = storage.MaxNamesQueryLimit
+	}
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	var found []storage.EventRow
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return s.newestFirst(tx, time.Time{}, nil, func(row *storage.EventRow) bool {
+			if wanted[row.Event] {
+				found = append(found, *row)
+			}
+			return len(found) < limit
+		})
+	})
+	return found, err
+}
+
+// putJSON stores v as JSON under key.
+func putJSON(b *bbolt.Bucket, key []byte, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return b.Put(key, value)
+}
+
+// StartSession records the start of a game session.
+func (s *Store) StartSession(sessionID, sceneID string, ts time.Time) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		sessions := tx.Bucket(bucketSessions)
+		if sessions.Get([]byte(sessionID)) != nil {
+			return fmt.Errorf("session %s already exists", sessionID)
+		}
+		return putJSON(sessions, []byte(sessionID), storage.SessionRow{
+			SessionID: sessionID,
+			SceneID:   sceneID,
+			StartedAt: ts,
+			RoomID:    s.roomID,
+		})
+	})
+}
+
+// EndSession records the end time and result of a game session.
+func (s *Store) EndSession(sessionID string, ts time.Time, result string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		sessions := tx.Bucket(bucketSessions)
+		value := sessions.Get([]byte(sessionID))
+		if value == nil {
+			return nil
+		}
+		var row storage.SessionRow
+		if err := json.Unmarshal(value, &row); err != nil {
+			return err
+		}
+		row.EndedAt = &ts
+		row.Result = &result
+		return putJSON(sessions, []byte(sessionID), row)
+	})
+}
+
+// Sessions returns game sessions, newest first.
+func (s *Store) Sessions(limit, offset int) ([]storage.SessionRow, error) {
+	if limit <= 0 {
+		limit = storage.DefaultSessions
+	}
+	var sessions []storage.SessionRow
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketSessions).ForEach(func(_, value []byte) error {
+			var row storage.SessionRow
+			if err := json.Unmarshal(value, &row); err != nil {
+				return err
+			}
+			sessions = append(sessions, row)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].StartedAt.After(sessions[j].StartedAt)
+	})
+	if offset >= len(sessions) {
+		return nil, nil
+	}
+	sessions = sessions[max(offset, 0):]
+	if len(sessions) > limit {
+		sessions = sessions[:limit]
+	}
+	return sessions, nil
+}
+
+// Session returns one game session, or nil if it does not exist.
+func (s *Store) Session(sessionID string) (*storage.SessionRow, error) {
+	var found *storage.SessionRow
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		value := tx.Bucket(bucketSessions).Get([]byte(sessionID))
+		if value == nil {
+			return nil
+		}
+		found = &storage.SessionRow{}
+		return json.Unmarshal(value, found)
+	})
+	return found, err
+}
+
+// SaveCheckpoint stores a named runtime snapshot.
+func (s *Store) SaveCheckpoint(ts time.Time, name, sceneID string, snapshot []byte) error {
+	if !json.Valid(snapshot) {
+		return fmt.Errorf("checkpoint %s: snapshot is not JSON", name)
+	}
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		checkpoints := tx.Bucket(bucketCheckpoints)
+		id, err := checkpoints.NextSequence()
+		if err != nil {
+			return err
+		}
+		return putJSON(checkpoints, itob(id), storage.CheckpointRow{
+			ID:        int64(id),
+			Timestamp: ts,
+			Name:      name,
+			SceneID:   sceneID,
+			Snapshot:  snapshot,
+			RoomID:    s.roomID,
+		})
+	})
+}
+
+// LatestCheckpoint returns the most recent snapshot with the given name,
+// or the most recent snapshot of any name if name is empty.
+// Returns nil if none exists.
+func (s *Store) LatestCheckpoint(name string) (*storage.CheckpointRow, error) {
+	var latest *storage.CheckpointRow
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketCheckpoints).ForEach(func(_, value []byte) error {
+			var cp storage.CheckpointRow
+			if err := json.Unmarshal(value, &cp); err != nil {
+				return err
+			}
+			if name != "" && cp.Name != name {
+				return nil
+			}
+			if latest == nil || cp.Timestamp.After(latest.Timestamp) {
+				latest = &cp
+			}
+			return nil
+		})
+	})
+	return latest, err
+}
+
+// put stores a copy of value under key in a bucket.
+func (s *Store) put(bucket []byte, key string, value []byte) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucket).Put([]byte(key), value)
+	})
+}
+
+// remove deletes key from a bucket.
+func (s *Store) remove(bucket []byte, key string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucket).Delete([]byte(key))
+	})
+}
+
+// load returns copies of every value in a bucket by key.
+func (s *Store) load(bucket []byte) (map[string][]byte, error) {
+	values := make(map[string][]byte)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucket).ForEach(func(k, v []byte) error {
+			values[string(k)] = append([]byte(nil), v...)
+			return nil
+		})
+	})
+	return values, err
+}
+
+// SaveDeviceAsset stores asset metadata for a logical device, replacing any previous record.
+func (s *Store) SaveDeviceAsset(logicalID string, asset []byte, ts time.Time) error {
+	return s.put(bucketAssets, logicalID, asset)
+}
+
+// LoadDeviceAssets returns stored asset metadata keyed by logical device ID.
+func (s *Store) LoadDeviceAssets() (map[string][]byte, error) {
+	return s.load(bucketAssets)
+}
+
+// SaveTriggerToken stores a trigger token by the hash of its secret, replacing any previous record.
+func (s *Store) SaveTriggerToken(tokenHash string, trigger []byte, ts time.Time) error {
+	return s.put(bucketTriggers, tokenHash, trigger)
+}
+
+// DeleteTriggerToken removes a stored trigger token.
+func (s *Store) DeleteTriggerToken(tokenHash string) error {
+	return s.remove(bucketTriggers, tokenHash)
+}
+
+// LoadTriggerTokens returns stored trigger tokens keyed by secret hash.
+func (s *Store) LoadTriggerTokens() (map[string][]byte, error) {
+	return s.load(bucketTriggers)
+}
+
+// SavePref stores one preference of an operator, replacing any previous value.
+func (s *Store) SavePref(username, key string, value []byte, ts time.Time) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		user, err := tx.Bucket(bucketPrefs).CreateBucketIfNotExists([]byte(username))
+		if err != nil {
+			return err
+		}
+		return user.Put([]byte(key), value)
+	})
+}
+
+// DeletePref removes one preference of an operator.
+func (s *Store) DeletePref(username, key string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		user := tx.Bucket(bucketPrefs).Bucket([]byte(username))
+		if user == nil {
+			return nil
+		}
+		return user.Delete([]byte(key))
+	})
+}
+
+// LoadPrefs returns stored preferences keyed by username, then key.
+func (s *Store) LoadPrefs() (map[string]map[string][]byte, error) {
+	prefs := make(map[string]map[string][]byte)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucketPrefs).ForEachBucket(func(username []byte) error {
+			return tx.Bucket(bucketPrefs).Bucket(username).ForEach(func(k, v []byte) error {
+				if prefs[string(username)] == nil {
+					prefs[string(username)] = make(map[string][]byte)
+				}
+				prefs[string(username)][string(k)] = append([]byte(nil), v...)
+				return nil
+			})
+		})
+	})
+	return prefs, err
+}
+
+// SaveControllerLog stores one line of controller debug output.
+func (s *Store) SaveControllerLog(row storage.ControllerLogRow) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		logs := tx.Bucket(bucketLogs)
+		id, err := logs.NextSequence()
+		if err != nil {
+			return err
+		}
+		return putJSON(logs, timeKey(row.Timestamp, id), row)
+	})
+}
+
+// ControllerLogs returns a controller's most recent log lines, oldest first.
+func (s *Store) ControllerLogs(controllerID string, limit int) ([]storage.ControllerLogRow, error) {
+	var logs []storage.ControllerLogRow
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		c := tx.Bucket(bucketLogs).Cursor()
+		for k, v := c.Last(); k != nil && len(logs) < limit; k, v = c.Prev() {
+			var row storage.ControllerLogRow
+			if err := json.Unmarshal(v, &row); err != nil {
+				return err
+			}
+			if row.ControllerID == controllerID {
+				logs = append(logs, row)
+			}
+		}
+		return nil
+	})
+	for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
+		logs[i], logs[j] = logs[j], logs[i]
+	}
+	return logs, err
+}
+
+// PruneControllerLogs deletes controller log lines older than before and
+// returns how many were deleted.
+func (s *Store) PruneControllerLogs(before time.Time) (int64, error) {
+	var deleted int64
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		logs := tx.Bucket(bucketLogs)
+		end := timeKey(before, 0)
+		var old [][]byte
+		c := logs.Cursor()
+		for k, _ := c.First(); k != nil && bytes.Compare(k, end) < 0; k, _ = c.Next() {
+			old = append(old, append([]byte(nil), k...))
+		}
+		for _, k := range old {
+			if err := logs.Delete(k); err != nil {
+				return err
+			}
+		}
+		deleted = int64(len(old))
+		return nil
+	})
+	return deleted, err
+}
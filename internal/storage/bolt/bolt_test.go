@@ -0,0 +1,306 @@
+package bolt
+
+import (
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/orchestrator"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+)
+
+func openTemp(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "room", "sentient.db"), "test_room")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// seqsOf returns the seq of each row.
+func seqsOf(rows []storage.EventRow) []int64 {
+	seqs := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		seqs = append(seqs, row.Seq)
+	}
+	return seqs
+}
+
+func TestEventsQuery(t *testing.T) {
+	s := openTemp(t)
+	base := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	err := s.AppendBatch([]storage.EventInsert{
+		{Seq: 1, Timestamp: base, Level: "info", Event: "scene.started", SessionID: "s1", Fields: map[string]interface{}{"scene_id": "scene_intro"}},
+		{Seq: 2, Timestamp: base.Add(time.Minute), Level: "info", Event: "puzzle.activated", SessionID: "s1", Fields: map[string]interface{}{"node_id": "puzzle_scarab"}},
+		{Seq: 3, Timestamp: base.Add(2 * time.Minute), Level: "warning", Event: "system.warning", Message: "unknown device"},
+		{Seq: 4, Timestamp: base.Add(3 * time.Minute), Level: "info", Event: "puzzle.solved", SessionID: "s1", Fields: map[string]interface{}{"node_id": "puzzle_scarab"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Spilled events are replayed after newer ones were stored; events
+	// already stored by an earlier attempt are skipped
+	if err := s.AppendBatch([]storage.EventInsert{
+		{Seq: 4, Timestamp: base.Add(3 * time.Minute), Level: "info", Event: "puzzle.solved", SessionID: "s1"},
+		{Seq: 5, Timestamp: base.Add(90 * time.Second), Level: "info", Event: "puzzle.progress", SessionID: "s1", Fields: map[string]interface{}{"node_id": "puzzle_tiles"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := s.Query(storage.EventFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := seqsOf(all); !reflect.DeepEqual(got, []int64{4, 3, 5, 2, 1}) {
+		t.Errorf("expected events newest first by timestamp, got seqs %v", got)
+	}
+	if all[1].Message == nil || *all[1].Message != "unknown device" || all[1].SessionID != nil || all[1].RoomID != "test_room" {
+		t.Errorf("unexpected row %+v", all[1])
+	}
+
+	cases := []struct {
+		name   string
+		filter storage.EventFilter
+		want   []int64
+	}{
+		{"prefix", storage.EventFilter{EventPrefix: "puzzle."}, []int64{4, 5, 2}},
+		{"level", storage.EventFilter{Level: "warning"}, []int64{3}},
+		{"session", storage.EventFilter{SessionID: "s1"}, []int64{4, 5, 2, 1}},
+		{"node", storage.EventFilter{NodeID: "puzzle_scarab"}, []int64{4, 2}},
+		{"window", storage.EventFilter{Since: base.Add(time.Minute), Until: base.Add(3 * time.Minute)}, []int64{3, 5, 2}},
+		{"limit", storage.EventFilter{Limit: 2}, []int64{4, 3}},
+		{"offset", storage.EventFilter{Limit: 2, Offset: 2}, []int64{5, 2}},
+		{"cursor", storage.EventFilter{Limit: 2, Before: all[1].EventID}, []int64{5, 2}},
+		{"unknown cursor", storage.EventFilter{Before: 999}, []int64{}},
+	}
+	for _, tc := range cases {
+		rows, err := s.Query(tc.filter)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got := seqsOf(rows); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: expected seqs %v, got %v", tc.name, tc.want, got)
+		}
+	}
+
+	rows, err := s.QueryByNames([]string{"puzzle.solved", "scene.started"}, 1)
+	if err != nil || !reflect.DeepEqual(seqsOf(rows), []int64{4}) {
+		t.Errorf("expected the latest named event, got %v (%v)", seqsOf(rows), err)
+	}
+
+	row, err := s.EventBySeq(2)
+	if err != nil || row == nil || row.Event != "puzzle.activated" || row.Fields["node_id"] != "puzzle_scarab" {
+		t.Errorf("expected seq 2 looked up, got %+v (%v)", row, err)
+	}
+	if row, err := s.EventBySeq(42); row != nil || err != nil {
+		t.Errorf("expected no event for an unknown seq, got %+v (%v)", row, err)
+	}
+	if seq, err := s.MaxSeq(); seq != 5 || err != nil {
+		t.Errorf("expected max seq 5, got %d (%v)", seq, err)
+	}
+}
+
+func TestAppendBatchSkipsUnencodableEvent(t *testing.T) {
+	s := openTemp(t)
+	now := time.Now().UTC()
+	err := s.AppendBatch([]storage.EventInsert{
+		{Seq: 1, Timestamp: now, Level: "info", Event: "device.input", Fields: map[string]interface{}{"value": 1.0}},
+		{Seq: 2, Timestamp: now, Level: "info", Event: "device.input", Fields: map[string]interface{}{"value": math.NaN()}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row, err := s.EventBySeq(1); row == nil || err != nil {
+		t.Errorf("expected the valid event stored, got %+v (%v)", row, err)
+	}
+	if row, err := s.EventBySeq(2); row != nil || err != nil {
+		t.Errorf("expected the NaN event skipped, got %+v (%v)", row, err)
+	}
+}
+
+func TestEventAliases(t *testing.T) {
+	s := openTemp(t)
+	if err := s.AppendBatch([]storage.EventInsert{
+		{Seq: 1, Timestamp: time.Now(), Level: "info", Event: "puzzle.done"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	s.SetEventAliases(map[string]string{"puzzle.done": "puzzle.solved"})
+
+	rows, err := s.QueryByNames([]string{"puzzle.solved"}, 0)
+	if err != nil || len(rows) != 1 || rows[0].Event != "puzzle.solved" {
+		t.Errorf("expected the legacy event read back under its current name, got %+v (%v)", rows, err)
+	}
+	rows, err = s.Query(storage.EventFilter{EventPrefix: "puzzle.solved"})
+	if err != nil || len(rows) != 1 {
+		t.Errorf("expected the legacy event to match the current name, got %+v (%v)", rows, err)
+	}
+}
+
+func TestSessionsAndCheckpoints(t *testing.T) {
+	s := openTemp(t)
+	base := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	for i, id := range []string{"s1", "s2", "s3"} {
+		if err := s.StartSession(id, "scene_intro", base.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.StartSession("s1", "scene_intro", base); err == nil {
+		t.Error("expected a duplicate session rejected")
+	}
+	if err := s.EndSession("s1", base.Add(30*time.Minute), "completed"); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := s.Sessions(2, 1)
+	if err != nil || len(sessions) != 2 || sessions[0].SessionID != "s2" || sessions[1].SessionID != "s1" {
+		t.Fatalf("expected s2, s1 newest first, got %+v (%v)", sessions, err)
+	}
+	if sessions[1].Result == nil || *sessions[1].Result != "completed" || sessions[1].EndedAt == nil {
+		t.Errorf("expected s1 ended, got %+v", sessions[1])
+	}
+	if session, err := s.Session("s9"); session != nil || err != nil {
+		t.Errorf("expected no unknown session, got %+v (%v)", session, err)
+	}
+
+	if cp, err := s.LatestCheckpoint(""); cp != nil || err != nil {
+		t.Errorf("expected no checkpoint yet, got %+v (%v)", cp, err)
+	}
+	for i, name := range []string{"auto", "before_finale", "auto"} {
+		if err := s.SaveCheckpoint(base.Add(time.Duration(i)*time.Minute), name, "scene_intro", []byte(`{"n":`+string(rune('0'+i))+`}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cp, err := s.LatestCheckpoint("before_finale")
+	if err != nil || cp == nil || string(cp.Snapshot) != `{"n":1}` {
+		t.Errorf("expected the named checkpoint, got %+v (%v)", cp, err)
+	}
+	cp, err = s.LatestCheckpoint("")
+	if err != nil || cp == nil || string(cp.Snapshot) != `{"n":2}` {
+		t.Errorf("expected the latest checkpoint, got %+v (%v)", cp, err)
+	}
+}
+
+func TestRoomState(t *testing.T) {
+	s := openTemp(t)
+	now := time.Now()
+
+	if err := s.SaveDeviceAsset("crypt_door", []byte(`{"serial":"A1"}`), now); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveDeviceAsset("crypt_door", []byte(`{"serial":"A2"}`), now); err != nil {
+		t.Fatal(err)
+	}
+	assets, err := s.LoadDeviceAssets()
+	if err != nil || len(assets) != 1 || string(assets["crypt_door"]) != `{"serial":"A2"}` {
+		t.Errorf("expected the asset replaced, got %q (%v)", assets, err)
+	}
+
+	_ = s.SaveTriggerToken("h1", []byte(`{"event":"a"}`), now)
+	_ = s.SaveTriggerToken("h2", []byte(`{"event":"b"}`), now)
+	if err := s.DeleteTriggerToken("h1"); err != nil {
+		t.Fatal(err)
+	}
+	triggers, err := s.LoadTriggerTokens()
+	if err != nil || len(triggers) != 1 || triggers["h2"] == nil {
+		t.Errorf("expected only h2 left, got %q (%v)", triggers, err)
+	}
+
+	_ = s.SavePref("ana", "theme", []byte(`"dark"`), now)
+	_ = s.SavePref("ana", "layout", []byte(`"grid"`), now)
+	_ = s.SavePref("ben", "theme", []byte(`"light"`), now)
+	if err := s.DeletePref("ana", "layout"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.DeletePref("cy", "theme"); err != nil {
+		t.Errorf("expected deleting an unknown pref to succeed, got %v", err)
+	}
+	prefs, err := s.LoadPrefs()
+	want := map[string]map[string][]byte{
+		"ana": {"theme": []byte(`"dark"`)},
+		"ben": {"theme": []byte(`"light"`)},
+	}
+	if err != nil || !reflect.DeepEqual(prefs, want) {
+		t.Errorf("expected %q, got %q (%v)", want, prefs, err)
+	}
+}
+
+func TestControllerLogs(t *testing.T) {
+	s := openTemp(t)
+	base := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		for _, id := range []string{"ctrl-001", "ctrl-002"} {
+			if err := s.SaveControllerLog(storage.ControllerLogRow{
+				ControllerID: id, Timestamp: base.Add(time.Duration(i) * time.Minute), Level: "info", Message: string(rune('a' + i)),
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	logs, err := s.ControllerLogs("ctrl-001", 3)
+	if err != nil || len(logs) != 3 || logs[0].Message != "c" || logs[2].Message != "e" {
+		t.Errorf("expected the last 3 lines oldest first, got %+v (%v)", logs, err)
+	}
+
+	deleted, err := s.PruneControllerLogs(base.Add(2 * time.Minute))
+	if err != nil || deleted != 4 {
+		t.Errorf("expected 4 lines pruned, got %d (%v)", deleted, err)
+	}
+	logs, _ = s.ControllerLogs("ctrl-002", 10)
+	if len(logs) != 3 || logs[0].Message != "c" {
+		t.Errorf("expected lines from c on kept, got %+v", logs)
+	}
+}
+
+func TestReopenAndRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sentient.db")
+	s, err := Open(path, "test_room")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An open session with more events than one restore page, several of
+	// them sharing a timestamp
+	start := time.Date(2024, 3, 4, 11, 0, 0, 0, time.UTC)
+	batch := []storage.EventInsert{
+		{Seq: 1, Timestamp: start, Level: "info", Event: "scene.started", SessionID: "s1", Fields: map[string]interface{}{"scene_id": "scene_intro"}},
+		{Seq: 2, Timestamp: start, Level: "info", Event: "puzzle.solved", SessionID: "s1", Fields: map[string]interface{}{"puzzle_id": "puzzle_scarab"}},
+	}
+	for i := 0; i < 250; i++ {
+		batch = append(batch, storage.EventInsert{
+			Seq: uint64(i + 3), Timestamp: start.Add(time.Duration(i/10+1) * time.Second), Level: "info", Event: "device.input",
+			Fields: map[string]interface{}{"logical_id": "crypt_door"},
+		})
+	}
+	if err := s.AppendBatch(batch); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Ping(); err == nil {
+		t.Error("expected a closed store to fail its ping")
+	}
+
+	s, err = Open(path, "test_room")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	state, count, err := orchestrator.RestoreFromEvents(s, "test_room", 100)
+	if err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if state == nil || state.SceneID != "scene_intro" || state.PuzzleStates["puzzle_scarab"] != orchestrator.PuzzleSolved {
+		t.Fatalf("expected the open session restored, got %+v", state)
+	}
+	if count != 252 {
+		t.Errorf("expected all 252 events of the session, got %d", count)
+	}
+}
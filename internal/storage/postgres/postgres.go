@@ -1,35 +1,29 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"log"
 	"os"
-	"sync"
+	"strings"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/AaronLay10/SentientEngine/internal/storage"
+	"github.com/lib/pq"
 )
 
-// EventRow represents an event stored in Postgres.
-type EventRow struct {
-	EventID   int64                  `json:"event_id"`
-	Timestamp time.Time              `json:"ts"`
-	Level     string                 `json:"level"`
-	Event     string                 `json:"event"`
-	Message   *string                `json:"msg,omitempty"`
-	Fields    map[string]interface{} `json:"fields,omitempty"`
-	RoomID    string                 `json:"room_id"`
-	SessionID *string                `json:"session_id,omitempty"`
-}
+// pingTimeout bounds a health probe of the database.
+const pingTimeout = 5 * time.Second
 
-// Client manages the Postgres connection for event storage.
+// Client manages the Postgres connection for event storage. It implements
+// storage.Store.
 type Client struct {
 	db     *sql.DB
 	roomID string
 
-	mu          sync.Mutex
-	errorLogged bool
+	aliases map[string]string // legacy event name -> current name
 }
 
 // New creates a new Postgres client using environment variables.
@@ -96,70 +90,594 @@ func (c *Client) createTable() error {
 		);
 		CREATE INDEX IF NOT EXISTS idx_events_ts ON events(ts DESC);
 		CREATE INDEX IF NOT EXISTS idx_events_room_id ON events(room_id);
+		ALTER TABLE events ADD COLUMN IF NOT EXISTS seq BIGINT;
+		DO $$
+		BEGIN
+			IF to_regclass('idx_events_room_seq_unique') IS NULL THEN
+				-- Keep the first copy of events stored twice before seq was unique
+				DELETE FROM events a USING events b
+				WHERE a.room_id = b.room_id AND a.seq = b.seq AND a.event_id > b.event_id;
+				CREATE UNIQUE INDEX idx_events_room_seq_unique ON events(room_id, seq);
+			END IF;
+		END $$;
+		DROP INDEX IF EXISTS idx_events_room_seq;
+		CREATE INDEX IF NOT EXISTS idx_events_session ON events(session_id);
+
+		CREATE TABLE IF NOT EXISTS sessions (
+			session_id TEXT PRIMARY KEY,
+			room_id    TEXT NOT NULL,
+			scene_id   TEXT NOT NULL,
+			started_at TIMESTAMPTZ NOT NULL,
+			ended_at   TIMESTAMPTZ,
+			result     TEXT
+		);
+		CREATE INDEX IF NOT EXISTS idx_sessions_room_started ON sessions(room_id, started_at DESC);
+
+		CREATE TABLE IF NOT EXISTS checkpoints (
+			id       BIGSERIAL PRIMARY KEY,
+			ts       TIMESTAMPTZ NOT NULL,
+			name     TEXT NOT NULL,
+			scene_id TEXT NOT NULL,
+			snapshot JSONB NOT NULL,
+			room_id  TEXT NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_checkpoints_room_name ON checkpoints(room_id, name, ts DESC);
+
+		CREATE TABLE IF NOT EXISTS device_assets (
+			room_id    TEXT NOT NULL,
+			logical_id TEXT NOT NULL,
+			asset      JSONB NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL,
+			PRIMARY KEY (room_id, logical_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS trigger_tokens (
+			room_id    TEXT NOT NULL,
+			token_hash TEXT NOT NULL,
+			trigger    JSONB NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL,
+			PRIMARY KEY (room_id, token_hash)
+		);
+
+		CREATE TABLE IF NOT EXISTS operator_prefs (
+			room_id    TEXT NOT NULL,
+			username   TEXT NOT NULL,
+			key        TEXT NOT NULL,
+			value      JSONB NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL,
+			PRIMARY KEY (room_id, username, key)
+		);
+
+		CREATE TABLE IF NOT EXISTS controller_logs (
+			id            BIGSERIAL PRIMARY KEY,
+			ts            TIMESTAMPTZ NOT NULL,
+			room_id       TEXT NOT NULL,
+			controller_id TEXT NOT NULL,
+			level         TEXT NOT NULL,
+			msg           TEXT NOT NULL,
+			dropped       INT NOT NULL DEFAULT 0
+		);
+		CREATE INDEX IF NOT EXISTS idx_controller_logs_room_ctrl ON controller_logs(room_id, controller_id, ts DESC);
+		CREATE INDEX IF NOT EXISTS idx_controller_logs_ts ON controller_logs(ts);
 	`
 	_, err := c.db.Exec(query)
 	return err
 }
 
-// Append inserts an event into the database.
-// Returns error if insert fails.
-func (c *Client) Append(ts time.Time, level, event, msg string, fields map[string]interface{}, sessionID string) error {
-	var fieldsJSON []byte
-	var err error
-	if fields != nil {
-		fieldsJSON, err = json.Marshal(fields)
-		if err != nil {
-			return fmt.Errorf("failed to marshal fields: %w", err)
+// AppendBatch inserts events in one statement: either all are stored or,
+// on error, none. Events whose seq is already stored are skipped, so a batch
+// retried after an ambiguous failure is not stored twice. An event whose
+// fields cannot be encoded is logged and skipped rather than failing the
+// rest of the batch.
+func (c *Client) AppendBatch(batch []storage.EventInsert) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	const columns = 8
+	var values strings.Builder
+	args := make([]interface{}, 0, len(batch)*columns)
+	for _, e := range batch {
+		var fieldsJSON []byte
+		if e.Fields != nil {
+			var err error
+			fieldsJSON, err = json.Marshal(e.Fields)
+			if err != nil {
+				log.Printf("[storage] skipping event %d: cannot encode fields: %v", e.Seq, err)
+				continue
+			}
+		}
+
+		var msgPtr *string
+		if e.Message != "" {
+			msg := e.Message
+			msgPtr = &msg
+		}
+
+		var sessionPtr *string
+		if e.SessionID != "" {
+			sessionID := e.SessionID
+			sessionPtr = &sessionID
 		}
+
+		n := len(args)
+		if n > 0 {
+			values.WriteString(", ")
+		}
+		fmt.Fprintf(&values, "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)", n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8)
+		args = append(args, e.Timestamp, e.Level, e.Event, msgPtr, fieldsJSON, c.roomID, sessionPtr, int64(e.Seq))
+	}
+	if len(args) == 0 {
+		return nil
 	}
 
-	var msgPtr *string
-	if msg != "" {
-		msgPtr = &msg
+	query := `INSERT INTO events (ts, level, event, msg, fields, room_id, session_id, seq) VALUES ` + values.String() +
+		` ON CONFLICT (room_id, seq) DO NOTHING`
+	_, err := c.db.Exec(query, args...)
+	return err
+}
+
+// MaxSeq returns the highest event sequence number stored for this room,
+// or 0 if none has been stored yet.
+func (c *Client) MaxSeq() (uint64, error) {
+	var last sql.NullInt64
+	err := c.db.QueryRow(`SELECT MAX(seq) FROM events WHERE room_id = $1`, c.roomID).Scan(&last)
+	if err != nil || !last.Valid || last.Int64 < 0 {
+		return 0, err
 	}
+	return uint64(last.Int64), nil
+}
 
-	var sessionPtr *string
-	if sessionID != "" {
-		sessionPtr = &sessionID
+// SetEventAliases sets the legacy event names to translate when reading
+// events back: events stored under a legacy name are returned under its
+// current name and match queries for it. Call before querying.
+func (c *Client) SetEventAliases(aliases map[string]string) {
+	c.aliases = aliases
+}
+
+// legacyNames returns the legacy names of the given event names.
+func (c *Client) legacyNames(match func(name string) bool) []string {
+	var names []string
+	for legacy, current := range c.aliases {
+		if match(current) {
+			names = append(names, legacy)
+		}
 	}
+	return names
+}
 
+// scanEvents reads event rows, translating legacy event names.
+func (c *Client) scanEvents(rows *sql.Rows) ([]storage.EventRow, error) {
+	found, err := scanEventRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	for i := range found {
+		if current, ok := c.aliases[found[i].Event]; ok {
+			found[i].Event = current
+		}
+	}
+	return found, nil
+}
+
+// EventBySeq returns the event with the given sequence number,
+// or nil if none exists.
+func (c *Client) EventBySeq(seq uint64) (*storage.EventRow, error) {
 	query := `
-		INSERT INTO events (ts, level, event, msg, fields, room_id, session_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		SELECT event_id, ts, level, event, msg, fields, room_id, session_id, seq
+		FROM events
+		WHERE room_id = $1 AND seq = $2
+		ORDER BY event_id DESC
+		LIMIT 1
 	`
-	_, err = c.db.Exec(query, ts, level, event, msgPtr, fieldsJSON, c.roomID, sessionPtr)
-	return err
+	rows, err := c.db.Query(query, c.roomID, int64(seq))
+	if err != nil {
+		return nil, err
+	}
+	found, err := c.scanEvents(rows)
+	if err != nil || len(found) == 0 {
+		return nil, err
+	}
+	return &found[0], nil
+}
+
+// Query returns events matching filter in descending order by timestamp.
+// Pass the event_id of the last row as Before to fetch the next page.
+func (c *Client) Query(filter storage.EventFilter) ([]storage.EventRow, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = storage.DefaultQueryLimit
+	}
+	if limit > storage.MaxQueryLimit {
+		limit = storage.MaxQueryLimit
+	}
+
+	args := []interface{}{c.roomID}
+	where := "room_id = $1"
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		where += fmt.Sprintf(" AND "+cond, len(args))
+	}
+	if filter.EventPrefix != "" {
+		legacy := c.legacyNames(func(name string) bool { return strings.HasPrefix(name, filter.EventPrefix) })
+		if len(legacy) > 0 {
+			args = append(args, escapeLike(filter.EventPrefix)+"%", pq.Array(legacy))
+			where += fmt.Sprintf(` AND (event LIKE $%d ESCAPE '\' OR event = ANY($%d))`, len(args)-1, len(args))
+		} else {
+			add(`event LIKE $%d ESCAPE '\'`, escapeLike(filter.EventPrefix)+"%")
+		}
+	}
+	if filter.Level != "" {
+		add("level = $%d", filter.Level)
+	}
+	if !filter.Since.IsZero() {
+		add("ts >= $%d", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		add("ts < $%d", filter.Until)
+	}
+	if filter.SessionID != "" {
+		add("session_id = $%d", filter.SessionID)
+	}
+	if filter.NodeID != "" {
+		add("fields->>'node_id' = $%d", filter.NodeID)
+	}
+	if filter.Before > 0 {
+		add("(ts, event_id) < (SELECT ts, event_id FROM events WHERE event_id = $%d)", filter.Before)
+	}
+
+	args = append(args, limit, filter.Offset)
+	query := fmt.Sprintf(`
+		SELECT event_id, ts, level, event, msg, fields, room_id, session_id, seq
+		FROM events
+		WHERE %s
+		ORDER BY ts DESC, event_id DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+	rows, err := c.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return c.scanEvents(rows)
+}
+
+// escapeLike escapes LIKE wildcards so s matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
-// Query returns the last N events from the database in descending order by timestamp.
-func (c *Client) Query(limit int) ([]EventRow, error) {
+// QueryByNames returns the last N events whose name is in names,
+// in descending order by timestamp.
+func (c *Client) QueryByNames(names []string, limit int) ([]storage.EventRow, error) {
 	if limit <= 0 {
-		limit = 200
+		limit = storage.DefaultQueryLimit
 	}
-	if limit > 10000 {
-		limit = 10000
+	if limit > storage.MaxNamesQueryLimit {
+		limit = storage.MaxNamesQueryLimit
 	}
 
 	query := `
-		SELECT event_id, ts, level, event, msg, fields, room_id, session_id
+		SELECT event_id, ts, level, event, msg, fields, room_id, session_id, seq
 		FROM events
+		WHERE room_id = $1 AND event = ANY($2)
+		ORDER BY ts DESC
+		LIMIT $3
+	`
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	names = append(names[:len(names):len(names)], c.legacyNames(func(name string) bool { return wanted[name] })...)
+
+	rows, err := c.db.Query(query, c.roomID, pq.Array(names), limit)
+	if err != nil {
+		return nil, err
+	}
+	return c.scanEvents(rows)
+}
+
+// StartSession records the start of a game session.
+func (c *Client) StartSession(sessionID, sceneID string, ts time.Time) error {
+	query := `
+		INSERT INTO sessions (session_id, room_id, scene_id, started_at)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := c.db.Exec(query, sessionID, c.roomID, sceneID, ts)
+	return err
+}
+
+// EndSession records the end time and result of a game session.
+func (c *Client) EndSession(sessionID string, ts time.Time, result string) error {
+	query := `
+		UPDATE sessions SET ended_at = $3, result = $4
+		WHERE room_id = $1 AND session_id = $2
+	`
+	_, err := c.db.Exec(query, c.roomID, sessionID, ts, result)
+	return err
+}
+
+// Sessions returns game sessions, newest first.
+func (c *Client) Sessions(limit, offset int) ([]storage.SessionRow, error) {
+	if limit <= 0 {
+		limit = storage.DefaultSessions
+	}
+	query := `
+		SELECT session_id, scene_id, started_at, ended_at, result, room_id
+		FROM sessions
 		WHERE room_id = $1
+		ORDER BY started_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := c.db.Query(query, c.roomID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return scanSessionRows(rows)
+}
+
+// Session returns one game session, or nil if it does not exist.
+func (c *Client) Session(sessionID string) (*storage.SessionRow, error) {
+	query := `
+		SELECT session_id, scene_id, started_at, ended_at, result, room_id
+		FROM sessions
+		WHERE room_id = $1 AND session_id = $2
+	`
+	rows, err := c.db.Query(query, c.roomID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	found, err := scanSessionRows(rows)
+	if err != nil || len(found) == 0 {
+		return nil, err
+	}
+	return &found[0], nil
+}
+
+// scanSessionRows scans session rows and closes the result set.
+func scanSessionRows(rows *sql.Rows) ([]storage.SessionRow, error) {
+	defer rows.Close()
+
+	var sessions []storage.SessionRow
+	for rows.Next() {
+		var s storage.SessionRow
+		var endedAt sql.NullTime
+		var result sql.NullString
+		if err := rows.Scan(&s.SessionID, &s.SceneID, &s.StartedAt, &endedAt, &result, &s.RoomID); err != nil {
+			return nil, err
+		}
+		if endedAt.Valid {
+			s.EndedAt = &endedAt.Time
+		}
+		if result.Valid {
+			s.Result = &result.String
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, rows.Err()
+}
+
+// SaveCheckpoint stores a named runtime snapshot.
+func (c *Client) SaveCheckpoint(ts time.Time, name, sceneID string, snapshot []byte) error {
+	query := `
+		INSERT INTO checkpoints (ts, name, scene_id, snapshot, room_id)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := c.db.Exec(query, ts, name, sceneID, snapshot, c.roomID)
+	return err
+}
+
+// LatestCheckpoint returns the most recent snapshot with the given name,
+// or the most recent snapshot of any name if name is empty.
+// Returns nil if none exists.
+func (c *Client) LatestCheckpoint(name string) (*storage.CheckpointRow, error) {
+	query := `
+		SELECT id, ts, name, scene_id, snapshot, room_id
+		FROM checkpoints
+		WHERE room_id = $1 AND ($2 = '' OR name = $2)
 		ORDER BY ts DESC
-		LIMIT $2
+		LIMIT 1
+	`
+	var cp storage.CheckpointRow
+	var snapshot []byte
+	err := c.db.QueryRow(query, c.roomID, name).Scan(&cp.ID, &cp.Timestamp, &cp.Name, &cp.SceneID, &snapshot, &cp.RoomID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cp.Snapshot = snapshot
+	return &cp, nil
+}
+
+// SaveDeviceAsset stores asset metadata for a logical device, replacing any previous record.
+func (c *Client) SaveDeviceAsset(logicalID string, asset []byte, ts time.Time) error {
+	query := `
+		INSERT INTO device_assets (room_id, logical_id, asset, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (room_id, logical_id)
+		DO UPDATE SET asset = EXCLUDED.asset, updated_at = EXCLUDED.updated_at
+	`
+	_, err := c.db.Exec(query, c.roomID, logicalID, asset, ts)
+	return err
+}
+
+// LoadDeviceAssets returns stored asset metadata keyed by logical device ID.
+func (c *Client) LoadDeviceAssets() (map[string][]byte, error) {
+	query := `
+		SELECT logical_id, asset
+		FROM device_assets
+		WHERE room_id = $1
+	`
+	rows, err := c.db.Query(query, c.roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	assets := make(map[string][]byte)
+	for rows.Next() {
+		var logicalID string
+		var asset []byte
+		if err := rows.Scan(&logicalID, &asset); err != nil {
+			return nil, err
+		}
+		assets[logicalID] = asset
+	}
+	return assets, rows.Err()
+}
+
+// SaveTriggerToken stores a trigger token by the hash of its secret, replacing any previous record.
+func (c *Client) SaveTriggerToken(tokenHash string, trigger []byte, ts time.Time) error {
+	query := `
+		INSERT INTO trigger_tokens (room_id, token_hash, trigger, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (room_id, token_hash)
+		DO UPDATE SET trigger = EXCLUDED.trigger, updated_at = EXCLUDED.updated_at
+	`
+	_, err := c.db.Exec(query, c.roomID, tokenHash, trigger, ts)
+	return err
+}
+
+// DeleteTriggerToken removes a stored trigger token.
+func (c *Client) DeleteTriggerToken(tokenHash string) error {
+	_, err := c.db.Exec(`DELETE FROM trigger_tokens WHERE room_id = $1 AND token_hash = $2`, c.roomID, tokenHash)
+	return err
+}
+
+// LoadTriggerTokens returns stored trigger tokens keyed by secret hash.
+func (c *Client) LoadTriggerTokens() (map[string][]byte, error) {
+	query := `
+		SELECT token_hash, trigger
+		FROM trigger_tokens
+		WHERE room_id = $1
+	`
+	rows, err := c.db.Query(query, c.roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	triggers := make(map[string][]byte)
+	for rows.Next() {
+		var tokenHash string
+		var trigger []byte
+		if err := rows.Scan(&tokenHash, &trigger); err != nil {
+			return nil, err
+		}
+		triggers[tokenHash] = trigger
+	}
+	return triggers, rows.Err()
+}
+
+// SavePref stores one preference of an operator, replacing any previous value.
+func (c *Client) SavePref(username, key string, value []byte, ts time.Time) error {
+	query := `
+		INSERT INTO operator_prefs (room_id, username, key, value, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (room_id, username, key)
+		DO UPDATE SET value = EXCLUDED.value, updated_at = EXCLUDED.updated_at
+	`
+	_, err := c.db.Exec(query, c.roomID, username, key, value, ts)
+	return err
+}
+
+// DeletePref removes one preference of an operator.
+func (c *Client) DeletePref(username, key string) error {
+	_, err := c.db.Exec(`DELETE FROM operator_prefs WHERE room_id = $1 AND username = $2 AND key = $3`, c.roomID, username, key)
+	return err
+}
+
+// LoadPrefs returns stored preferences keyed by username, then key.
+func (c *Client) LoadPrefs() (map[string]map[string][]byte, error) {
+	query := `
+		SELECT username, key, value
+		FROM operator_prefs
+		WHERE room_id = $1
 	`
-	rows, err := c.db.Query(query, c.roomID, limit)
+	rows, err := c.db.Query(query, c.roomID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var events []EventRow
+	prefs := make(map[string]map[string][]byte)
 	for rows.Next() {
-		var e EventRow
+		var username, key string
+		var value []byte
+		if err := rows.Scan(&username, &key, &value); err != nil {
+			return nil, err
+		}
+		if prefs[username] == nil {
+			prefs[username] = make(map[string][]byte)
+		}
+		prefs[username][key] = value
+	}
+	return prefs, rows.Err()
+}
+
+// SaveControllerLog stores one line of controller debug output.
+func (c *Client) SaveControllerLog(row storage.ControllerLogRow) error {
+	query := `
+		INSERT INTO controller_logs (ts, room_id, controller_id, level, msg, dropped)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := c.db.Exec(query, row.Timestamp, c.roomID, row.ControllerID, row.Level, row.Message, row.Dropped)
+	return err
+}
+
+// ControllerLogs returns a controller's most recent log lines, oldest first.
+func (c *Client) ControllerLogs(controllerID string, limit int) ([]storage.ControllerLogRow, error) {
+	query := `
+		SELECT controller_id, ts, level, msg, dropped
+		FROM (
+			SELECT id, controller_id, ts, level, msg, dropped
+			FROM controller_logs
+			WHERE room_id = $1 AND controller_id = $2
+			ORDER BY ts DESC, id DESC
+			LIMIT $3
+		) recent
+		ORDER BY ts ASC, id ASC
+	`
+	rows, err := c.db.Query(query, c.roomID, controllerID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []storage.ControllerLogRow
+	for rows.Next() {
+		var row storage.ControllerLogRow
+		if err := rows.Scan(&row.ControllerID, &row.Timestamp, &row.Level, &row.Message, &row.Dropped); err != nil {
+			return nil, err
+		}
+		logs = append(logs, row)
+	}
+	return logs, rows.Err()
+}
+
+// PruneControllerLogs deletes controller log lines older than before and
+// returns how many were deleted.
+func (c *Client) PruneControllerLogs(before time.Time) (int64, error) {
+	res, err := c.db.Exec(`DELETE FROM controller_logs WHERE room_id = $1 AND ts < $2`, c.roomID, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// scanEventRows scans event rows and closes the result set.
+func scanEventRows(rows *sql.Rows) ([]storage.EventRow, error) {
+	defer rows.Close()
+
+	var events []storage.EventRow
+	for rows.Next() {
+		var e storage.EventRow
 		var fieldsJSON []byte
 		var msg, sessionID sql.NullString
+		var seq sql.NullInt64
 
-		if err := rows.Scan(&e.EventID, &e.Timestamp, &e.Level, &e.Event, &msg, &fieldsJSON, &e.RoomID, &sessionID); err != nil {
+		if err := rows.Scan(&e.EventID, &e.Timestamp, &e.Level, &e.Event, &msg, &fieldsJSON, &e.RoomID, &sessionID, &seq); err != nil {
 			return nil, err
 		}
 
@@ -169,6 +687,7 @@ func (c *Client) Query(limit int) ([]EventRow, error) {
 		if sessionID.Valid {
 			e.SessionID = &sessionID.String
 		}
+		e.Seq = seq.Int64
 		if len(fieldsJSON) > 0 {
 			if err := json.Unmarshal(fieldsJSON, &e.Fields); err != nil {
 				return nil, fmt.Errorf("failed to unmarshal fields: %w", err)
@@ -181,6 +700,13 @@ func (c *Client) Query(limit int) ([]EventRow, error) {
 	return events, rows.Err()
 }
 
+// Ping checks that the database answers, reconnecting if needed.
+func (c *Client) Ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+	defer cancel()
+	return c.db.PingContext(ctx)
+}
+
 // Close closes the database connection.
 func (c *Client) Close() error {
 	if c.db != nil {
@@ -188,17 +714,3 @@ func (c *Client) Close() error {
 	}
 	return nil
 }
-
-// MarkErrorLogged marks that an error has been logged (to avoid spam).
-func (c *Client) MarkErrorLogged() {
-	c.mu.Lock()
-	c.errorLogged = true
-	c.mu.Unlock()
-}
-
-// HasLoggedError returns true if an error has been logged.
-func (c *Client) HasLoggedError() bool {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	return c.errorLogged
-}
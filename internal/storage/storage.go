@@ -0,0 +1,157 @@
+// Package storage defines the persistence the engine needs: the event log,
+// game sessions, checkpoints and the small stores of room state. Rooms pick a
+// backend with SENTIENT_STORAGE: Postgres (storage/postgres, the default) or
+// an embedded bbolt file (storage/bolt) for single-board deployments that do
+// not run a database server. Each backend stores one room's data.
+package storage
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Store is implemented by every storage backend.
+type Store interface {
+	// Ping checks that the backend answers, reconnecting if needed.
+	Ping() error
+	// Close releases the backend.
+	Close() error
+
+	// AppendBatch stores events: either all of them or, on error, none.
+	// Events whose seq is already stored, or whose fields cannot be encoded,
+	// are skipped.
+	AppendBatch(batch []EventInsert) error
+	// MaxSeq returns the highest event seq stored, or 0 if there is none.
+	MaxSeq() (uint64, error)
+	// SetEventAliases sets the legacy event names to translate when reading
+	// events back: events stored under a legacy name are returned under its
+	// current name and match queries for it. Call before querying.
+	SetEventAliases(aliases map[string]string)
+	// EventBySeq returns the event with the given seq, or nil if none exists.
+	EventBySeq(seq uint64) (*EventRow, error)
+	// Query returns events matching filter in descending order by timestamp.
+	Query(filter EventFilter) ([]EventRow, error)
+	// QueryByNames returns the last limit events whose name is in names,
+	// in descending order by timestamp.
+	QueryByNames(names []string, limit int) ([]EventRow, error)
+
+	// StartSession records the start of a game session.
+	StartSession(sessionID, sceneID string, ts time.Time) error
+	// EndSession records the end time and result of a game session.
+	EndSession(sessionID string, ts time.Time, result string) error
+	// Sessions returns game sessions, newest first.
+	Sessions(limit, offset int) ([]SessionRow, error)
+	// Session returns one game session, or nil if it does not exist.
+	Session(sessionID string) (*SessionRow, error)
+
+	// SaveCheckpoint stores a named runtime snapshot.
+	SaveCheckpoint(ts time.Time, name, sceneID string, snapshot []byte) error
+	// LatestCheckpoint returns the most recent snapshot with the given name,
+	// or of any name if name is empty, or nil if none exists.
+	LatestCheckpoint(name string) (*CheckpointRow, error)
+
+	// SaveDeviceAsset stores asset metadata for a logical device, replacing
+	// any previous record.
+	SaveDeviceAsset(logicalID string, asset []byte, ts time.Time) error
+	// LoadDeviceAssets returns stored asset metadata keyed by logical ID.
+	LoadDeviceAssets() (map[string][]byte, error)
+
+	// SaveTriggerToken stores a trigger token by the hash of its secret,
+	// replacing any previous record.
+	SaveTriggerToken(tokenHash string, trigger []byte, ts time.Time) error
+	// DeleteTriggerToken removes a stored trigger token.
+	DeleteTriggerToken(tokenHash string) error
+	// LoadTriggerTokens returns stored trigger tokens keyed by secret hash.
+	LoadTriggerTokens() (map[string][]byte, error)
+
+	// SavePref stores one preference of an operator, replacing any previous
+	// value.
+	SavePref(username, key string, value []byte, ts time.Time) error
+	// DeletePref removes one preference of an operator.
+	DeletePref(username, key string) error
+	// LoadPrefs returns stored preferences keyed by username, then key.
+	LoadPrefs() (map[string]map[string][]byte, error)
+
+	// SaveControllerLog stores one line of controller debug output.
+	SaveControllerLog(row ControllerLogRow) error
+	// ControllerLogs returns a controller's most recent log lines, oldest
+	// first.
+	ControllerLogs(controllerID string, limit int) ([]ControllerLogRow, error)
+	// PruneControllerLogs deletes controller log lines older than before and
+	// returns how many were deleted.
+	PruneControllerLogs(before time.Time) (int64, error)
+}
+
+// Query limits shared by the backends.
+const (
+	DefaultQueryLimit  = 200
+	MaxQueryLimit      = 10000  // Query
+	MaxNamesQueryLimit = 100000 // QueryByNames
+	DefaultSessions    = 50
+)
+
+// EventRow represents a stored event.
+type EventRow struct {
+	EventID   int64                  `json:"event_id"`
+	Seq       int64                  `json:"seq,omitempty"`
+	Timestamp time.Time              `json:"ts"`
+	Level     string                 `json:"level"`
+	Event     string                 `json:"event"`
+	Message   *string                `json:"msg,omitempty"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+	RoomID    string                 `json:"room_id"`
+	SessionID *string                `json:"session_id,omitempty"`
+}
+
+// EventInsert is an event to be stored.
+type EventInsert struct {
+	Seq       uint64                 `json:"seq"` // stable sequence number (see MaxSeq)
+	Timestamp time.Time              `json:"ts"`
+	Level     string                 `json:"level"`
+	Event     string                 `json:"event"`
+	Message   string                 `json:"msg,omitempty"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+	SessionID string                 `json:"session_id,omitempty"`
+}
+
+// EventFilter selects events for Query. Zero-valued fields match everything.
+type EventFilter struct {
+	Limit       int    // default 200, max 10000
+	Offset      int    // rows to skip
+	Before      int64  // cursor: only events after this event_id in result order
+	EventPrefix string // event name prefix, e.g. "puzzle."
+	Level       string
+	Since       time.Time // inclusive
+	Until       time.Time // exclusive
+	SessionID   string
+	NodeID      string // fields.node_id
+}
+
+// SessionRow represents a stored game session.
+type SessionRow struct {
+	SessionID string     `json:"session_id"`
+	SceneID   string     `json:"scene_id"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Result    *string    `json:"result,omitempty"` // completed, expired or stopped; nil while running
+	RoomID    string     `json:"room_id"`
+}
+
+// CheckpointRow represents a stored named runtime snapshot.
+type CheckpointRow struct {
+	ID        int64           `json:"id"`
+	Timestamp time.Time       `json:"ts"`
+	Name      string          `json:"name"`
+	SceneID   string          `json:"scene_id"`
+	Snapshot  json.RawMessage `json:"snapshot"`
+	RoomID    string          `json:"room_id"`
+}
+
+// ControllerLogRow is a stored line of controller debug output.
+type ControllerLogRow struct {
+	ControllerID string    `json:"controller_id"`
+	Timestamp    time.Time `json:"ts"`
+	Level        string    `json:"level"`
+	Message      string    `json:"msg"`
+	Dropped      int       `json:"dropped,omitempty"`
+}
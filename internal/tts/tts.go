@@ -0,0 +1,286 @@
+package tts
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+	defaultFormat  = "wav"
+	queueSize      = 32
+	maxMemoryClips = 64
+	maxAudioBytes  = 32 << 20
+	engineHTTP     = "http"
+	enginePiper    = "piper"
+)
+
+// Request is one piece of text to speak on a room speaker.
+type Request struct {
+	Text    string
+	Voice   string // empty for the configured voice
+	Speaker string // empty for the default speaker
+
+	// OnError is called from the worker if synthesis or playback fails.
+	OnError func(err error)
+}
+
+// Client synthesizes speech with the configured TTS service and plays it on
+// room speakers. Generated clips are cached by engine, voice and text, on disk
+// when cache_dir is set and in memory otherwise. Requests queued with Say are
+// spoken one at a time, in order.
+type Client struct {
+	cfg    config.TTSConfig
+	apiKey string
+	http   *http.Client
+
+	mu     sync.Mutex
+	memory map[string][]byte
+
+	queue  chan Request
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// New creates a TTS client. The API key, if any, is read from cfg.APIKeyEnv.
+func New(cfg config.TTSConfig) (*Client, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("tts: url required")
+	}
+	if cfg.Engine == "" {
+		cfg.Engine = enginePiper
+	}
+	if cfg.Engine != enginePiper && cfg.Engine != engineHTTP {
+		return nil, fmt.Errorf("tts: unknown engine %q (want piper or http)", cfg.Engine)
+	}
+	if cfg.Format == "" {
+		cfg.Format = defaultFormat
+	}
+	if cfg.DefaultSpeaker != "" {
+		if _, ok := cfg.Speakers[cfg.DefaultSpeaker]; !ok {
+			return nil, fmt.Errorf("tts: default_speaker %q not in speakers", cfg.DefaultSpeaker)
+		}
+	}
+	if cfg.CacheDir != "" {
+		if err := os.MkdirAll(cfg.CacheDir, 0755); err != nil {
+			return nil, fmt.Errorf("tts: cache_dir: %w", err)
+		}
+	}
+
+	var apiKey string
+	if cfg.APIKeyEnv != "" {
+		key, err := config.ResolveSecret(cfg.APIKeyEnv)
+		if err != nil {
+			return nil, fmt.Errorf("tts: %w", err)
+		}
+		apiKey = key
+	}
+
+	timeout := defaultTimeout
+	if cfg.TimeoutMs > 0 {
+		timeout = time.Duration(cfg.TimeoutMs) * time.Millisecond
+	}
+
+	return &Client{
+		cfg:    cfg,
+		apiKey: apiKey,
+		http:   &http.Client{Timeout: timeout},
+		memory: make(map[string][]byte),
+		queue:  make(chan Request, queueSize),
+		stopCh: make(chan struct{}),
+	}, nil
+}
+
+// Start runs the worker that speaks queued requests.
+func (c *Client) Start() {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		for {
+			select {
+			case <-c.stopCh:
+				return
+			case req := <-c.queue:
+				if err := c.Speak(req); err != nil && req.OnError != nil {
+					req.OnError(err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop waits for the worker to exit. Requests still queued are dropped.
+func (c *Client) Stop() {
+	close(c.stopCh)
+	c.wg.Wait()
+}
+
+// Say queues a request without blocking. Returns an error if the speaker is
+// unknown or the queue is full.
+func (c *Client) Say(req Request) error {
+	if _, err := c.speakerURL(req.Speaker); err != nil {
+		return err
+	}
+	select {
+	case c.queue <- req:
+		return nil
+	default:
+		return fmt.Errorf("tts: queue full")
+	}
+}
+
+// Speak synthesizes (or loads from cache) and plays a request, blocking until done.
+func (c *Client) Speak(req Request) error {
+	target, err := c.speakerURL(req.Speaker)
+	if err != nil {
+		return err
+	}
+	audio, err := c.Synthesize(req.Text, req.Voice)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.http.Post(target, "audio/"+c.cfg.Format, bytes.NewReader(audio))
+	if err != nil {
+		return fmt.Errorf("tts: speaker: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("tts: speaker: %s", resp.Status)
+	}
+	return nil
+}
+
+// Synthesize returns the audio for text, generating it only on a cache miss.
+func (c *Client) Synthesize(text, voice string) ([]byte, error) {
+	if text == "" {
+		return nil, fmt.Errorf("tts: empty text")
+	}
+	if voice == "" {
+		voice = c.cfg.Voice
+	}
+
+	key := c.cacheKey(text, voice)
+	if audio, ok := c.cached(key); ok {
+		return audio, nil
+	}
+
+	audio, err := c.generate(text, voice)
+	if err != nil {
+		return nil, err
+	}
+	c.store(key, audio)
+	return audio, nil
+}
+
+// generate calls the TTS service.
+func (c *Client) generate(text, voice string) ([]byte, error) {
+	var req *http.Request
+	var err error
+	switch c.cfg.Engine {
+	case enginePiper:
+		// Piper's HTTP server takes the raw text as the request body
+		target := c.cfg.URL
+		if voice != "" {
+			target += "?" + url.Values{"voice": {voice}}.Encode()
+		}
+		req, err = http.NewRequest(http.MethodPost, target, bytes.NewBufferString(text))
+		if err == nil {
+			req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+		}
+	default:
+		body, _ := json.Marshal(map[string]string{
+			"text":   text,
+			"voice":  voice,
+			"format": c.cfg.Format,
+		})
+		req, err = http.NewRequest(http.MethodPost, c.cfg.URL, bytes.NewReader(body))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tts: %w", err)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("tts: synthesize: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("tts: synthesize: %s", resp.Status)
+	}
+	audio, err := io.ReadAll(io.LimitReader(resp.Body, maxAudioBytes))
+	if err != nil {
+		return nil, fmt.Errorf("tts: synthesize: %w", err)
+	}
+	if len(audio) == 0 {
+		return nil, fmt.Errorf("tts: synthesize: empty response")
+	}
+	return audio, nil
+}
+
+// speakerURL resolves a speaker name, falling back to the default speaker.
+func (c *Client) speakerURL(name string) (string, error) {
+	if name == "" {
+		name = c.cfg.DefaultSpeaker
+	}
+	if name == "" {
+		return "", fmt.Errorf("tts: no speaker given and no default_speaker configured")
+	}
+	target, ok := c.cfg.Speakers[name]
+	if !ok {
+		return "", fmt.Errorf("tts: unknown speaker %q", name)
+	}
+	return target, nil
+}
+
+// cacheKey identifies a clip by engine, voice, format and text.
+func (c *Client) cacheKey(text, voice string) string {
+	sum := sha256.Sum256([]byte(c.cfg.Engine + "\x00" + voice + "\x00" + c.cfg.Format + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Client) cached(key string) ([]byte, bool) {
+	if c.cfg.CacheDir != "" {
+		audio, err := os.ReadFile(filepath.Join(c.cfg.CacheDir, key+"."+c.cfg.Format))
+		return audio, err == nil && len(audio) > 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	audio, ok := c.memory[key]
+	return audio, ok
+}
+
+func (c *Client) store(key string, audio []byte) {
+	if c.cfg.CacheDir != "" {
+		path := filepath.Join(c.cfg.CacheDir, key+"."+c.cfg.Format)
+		if err := os.WriteFile(path, audio, 0644); err != nil {
+			log.Printf("[tts] failed to cache clip: %v", err)
+		}
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.memory) >= maxMemoryClips {
+		c.memory = make(map[string][]byte)
+	}
+	c.memory[key] = audio
+}
@@ -0,0 +1,171 @@
+package tts
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AaronLay10/SentientEngine/internal/config"
+)
+
+// fakeServices runs a TTS service that echoes the text as audio and a speaker
+// that records what it plays.
+type fakeServices struct {
+	tts     *httptest.Server
+	speaker *httptest.Server
+
+	mu       sync.Mutex
+	requests int
+	played   []string
+}
+
+func newFakeServices(t *testing.T) *fakeServices {
+	f := &fakeServices{}
+	f.tts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		f.mu.Lock()
+		f.requests++
+		f.mu.Unlock()
+		w.Header().Set("Content-Type", "audio/wav")
+		_, _ = w.Write([]byte("RIFF:" + r.URL.Query().Get("voice") + ":" + string(body)))
+	}))
+	f.speaker = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		f.mu.Lock()
+		f.played = append(f.played, string(body))
+		f.mu.Unlock()
+	}))
+	t.Cleanup(func() {
+		f.tts.Close()
+		f.speaker.Close()
+	})
+	return f
+}
+
+func (f *fakeServices) config() config.TTSConfig {
+	return config.TTSConfig{
+		URL:            f.tts.URL,
+		Voice:          "lessac",
+		Speakers:       map[string]string{"main": f.speaker.URL},
+		DefaultSpeaker: "main",
+	}
+}
+
+func TestSpeakCachesClips(t *testing.T) {
+	f := newFakeServices(t)
+	c, err := New(f.config())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := c.Speak(Request{Text: "The code is 4417"}); err != nil {
+			t.Fatalf("Speak failed: %v", err)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.requests != 1 {
+		t.Errorf("expected one synthesis for a repeated line, got %d", f.requests)
+	}
+	if len(f.played) != 2 || f.played[0] != "RIFF:lessac:The code is 4417" {
+		t.Errorf("unexpected playback: %v", f.played)
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	f := newFakeServices(t)
+	cfg := f.config()
+	cfg.CacheDir = t.TempDir()
+
+	for i := 0; i < 2; i++ {
+		// A fresh client reuses clips generated by an earlier one
+		c, err := New(cfg)
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		if _, err := c.Synthesize("Welcome", ""); err != nil {
+			t.Fatalf("Synthesize failed: %v", err)
+		}
+	}
+
+	entries, _ := os.ReadDir(cfg.CacheDir)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.requests != 1 || len(entries) != 1 {
+		t.Errorf("expected 1 synthesis and 1 cached file, got %d and %d", f.requests, len(entries))
+	}
+}
+
+func TestSayQueue(t *testing.T) {
+	f := newFakeServices(t)
+	c, err := New(f.config())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	c.Start()
+	defer c.Stop()
+
+	if err := c.Say(Request{Text: "hint", Speaker: "lobby"}); err == nil {
+		t.Error("expected error for unknown speaker")
+	}
+
+	if err := c.Say(Request{Text: "queued"}); err != nil {
+		t.Fatalf("Say failed: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		f.mu.Lock()
+		n := len(f.played)
+		f.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected queued request to be played")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Failures are reported through OnError
+	down := httptest.NewServer(http.NotFoundHandler())
+	defer down.Close()
+	cfg := f.config()
+	cfg.URL = down.URL
+	failing, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	failing.Start()
+	defer failing.Stop()
+
+	errs := make(chan error, 1)
+	if err := failing.Say(Request{Text: "hint", OnError: func(err error) { errs <- err }}); err != nil {
+		t.Fatalf("Say failed: %v", err)
+	}
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("expected synthesis error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected OnError to be called")
+	}
+}
+
+func TestNewValidation(t *testing.T) {
+	if _, err := New(config.TTSConfig{}); err == nil {
+		t.Error("expected error without url")
+	}
+	if _, err := New(config.TTSConfig{URL: "http://tts", Engine: "espeak"}); err == nil {
+		t.Error("expected error for unknown engine")
+	}
+	if _, err := New(config.TTSConfig{URL: "http://tts", DefaultSpeaker: "main"}); err == nil {
+		t.Error("expected error for unknown default speaker")
+	}
+}
@@ -9,6 +9,7 @@ set -euo pipefail
 #   SENTIENT_ADMIN_USER_FILE, SENTIENT_ADMIN_PASS_FILE     - Auth files
 #   SENTIENT_OPERATOR_USER_FILE, SENTIENT_OPERATOR_PASS_FILE
 #   SENTIENT_TLS_CERT_FILE, SENTIENT_TLS_KEY_FILE          - TLS files
+#   SENTIENT_EXPORT_*, SENTIENT_SMTP_*                     - Session reports
 
 ROOM_NAME="${1:-_template}"
 API_PORT="${2:-8080}"
@@ -42,6 +43,21 @@ if [ -n "${SENTIENT_ADMIN_USER_FILE:-}" ] && [ -f "$SENTIENT_ADMIN_USER_FILE" ];
   ENV_ARGS="$ENV_ARGS -e SENTIENT_OPERATOR_PASS_FILE=/run/secrets/auth/$(basename "${SENTIENT_OPERATOR_PASS_FILE:-}")"
 fi
 
+# Session report export settings are passed through as-is
+for var in SENTIENT_EXPORT_SCHEDULE SENTIENT_EXPORT_HOUR SENTIENT_EXPORT_DIR \
+           SENTIENT_EXPORT_EMAIL_FROM SENTIENT_EXPORT_EMAIL_TO SENTIENT_SMTP_HOST SENTIENT_SMTP_PORT; do
+  if [ -n "${!var:-}" ]; then
+    ENV_ARGS="$ENV_ARGS -e $var=${!var}"
+  fi
+done
+
+# SMTP credentials via *_FILE pattern
+if [ -n "${SENTIENT_SMTP_USER_FILE:-}" ] && [ -f "$SENTIENT_SMTP_USER_FILE" ]; then
+  VOLUME_ARGS="$VOLUME_ARGS -v $(dirname "$SENTIENT_SMTP_USER_FILE"):/run/secrets/smtp:ro"
+  ENV_ARGS="$ENV_ARGS -e SENTIENT_SMTP_USER_FILE=/run/secrets/smtp/$(basename "$SENTIENT_SMTP_USER_FILE")"
+  ENV_ARGS="$ENV_ARGS -e SENTIENT_SMTP_PASS_FILE=/run/secrets/smtp/$(basename "${SENTIENT_SMTP_PASS_FILE:-}")"
+fi
+
 # TLS configuration (file paths, not *_FILE pattern)
 # SENTIENT_TLS_CERT_FILE and SENTIENT_TLS_KEY_FILE are host paths to mount
 TLS_ARGS=""